@@ -83,6 +83,12 @@ type Topology struct {
 	// +optional
 	RolloutAfter *metav1.Time `json:"rolloutAfter,omitempty"`
 
+	// Profile is the profile, or tier, of the Cluster, e.g. dev, stage or prod.
+	// The profile is set on the Cluster with the cluster.x-k8s.io/profile label, so ClusterResourceSets,
+	// webhooks and other policies can target sets of Clusters.
+	// +optional
+	Profile string `json:"profile,omitempty"`
+
 	// ControlPlane describes the cluster control plane.
 	// +optional
 	ControlPlane ControlPlaneTopology `json:"controlPlane,omitempty"`
@@ -137,6 +143,12 @@ type ControlPlaneTopology struct {
 	// Defaults to 10 seconds.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// AdditionalTags is an optional set of tags to be added to the cloud resources created by the
+	// infrastructure provider for control plane Machines.
+	// NOTE: These tags are merged with the ones defined in the ClusterClass, taking precedence in case of conflicts.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
 }
 
 // WorkersTopology represents the different sets of worker nodes in the cluster.
@@ -144,8 +156,35 @@ type WorkersTopology struct {
 	// MachineDeployments is a list of machine deployments in the cluster.
 	// +optional
 	MachineDeployments []MachineDeploymentTopology `json:"machineDeployments,omitempty"`
+
+	// MachinePools is a list of machine pools in the cluster.
+	// NOTE: MachinePools can be used only if the MachinePool feature flag is enabled.
+	// +optional
+	MachinePools []MachinePoolTopology `json:"machinePools,omitempty"`
+
+	// MachineDeploymentsUpgradePolicy defines how MachineDeployments pick up a new version of the topology
+	// once the control plane has been upgraded.
+	// Valid values are "Sequential" and "Parallel"; defaults to "Sequential", meaning that MachineDeployments
+	// are upgraded one at a time, and the next MachineDeployment is upgraded only after all the Machines of the
+	// previous one are available, i.e. ready for at least the MachineDeployment's MinReadySeconds.
+	// "Parallel" upgrades all the MachineDeployments at the same time.
+	// +kubebuilder:validation:Enum=Sequential;Parallel
+	// +optional
+	MachineDeploymentsUpgradePolicy MachineDeploymentsUpgradePolicy `json:"machineDeploymentsUpgradePolicy,omitempty"`
 }
 
+// MachineDeploymentsUpgradePolicy defines how MachineDeployments in a Cluster topology are upgraded.
+type MachineDeploymentsUpgradePolicy string
+
+const (
+	// SequentialMachineDeploymentsUpgradePolicy upgrades one MachineDeployment at a time, waiting for all the Machines
+	// of the MachineDeployment being upgraded to be available before moving to the next one.
+	SequentialMachineDeploymentsUpgradePolicy MachineDeploymentsUpgradePolicy = "Sequential"
+
+	// ParallelMachineDeploymentsUpgradePolicy upgrades all the MachineDeployments at the same time.
+	ParallelMachineDeploymentsUpgradePolicy MachineDeploymentsUpgradePolicy = "Parallel"
+)
+
 // MachineDeploymentTopology specifies the different parameters for a set of worker nodes in the topology.
 // This set of nodes is managed by a MachineDeployment object whose lifecycle is managed by the Cluster controller.
 type MachineDeploymentTopology struct {
@@ -199,6 +238,12 @@ type MachineDeploymentTopology struct {
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
 
+	// AdditionalTags is an optional set of tags to be added to the cloud resources created by the
+	// infrastructure provider for Machines of this MachineDeployment.
+	// NOTE: These tags are merged with the ones defined in the MachineDeploymentClass, taking precedence in case of conflicts.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+
 	// Minimum number of seconds for which a newly created machine should
 	// be ready.
 	// Defaults to 0 (machine will be considered available as soon as it
@@ -211,9 +256,118 @@ type MachineDeploymentTopology struct {
 	// +optional
 	Strategy *MachineDeploymentStrategy `json:"strategy,omitempty"`
 
+	// DeletePolicy defines the policy used to identify the machines to delete when the MachineDeployment
+	// is scaled down, e.g. when Replicas is decreased.
+	// Valid values are "Random", "Newest", "Oldest".
+	// Machines annotated with "cluster.x-k8s.io/delete-machine" are always deleted first, no matter of the policy.
+	// NOTE: If set, DeletePolicy takes precedence over the delete policy defined in Strategy or in the MachineDeploymentClass.
+	// +kubebuilder:validation:Enum=Random;Newest;Oldest
+	// +optional
+	DeletePolicy *string `json:"deletePolicy,omitempty"`
+
+	// RolloutAfter triggers a one-time rollout of the Machines of this MachineDeployment only, e.g. to pick up
+	// a new machine image, as soon as the given time is reached and without changing the ClusterClass or the version.
+	// Each new value triggers a new rollout; removing the value does not trigger any rollout.
+	// +optional
+	RolloutAfter *metav1.Time `json:"rolloutAfter,omitempty"`
+
+	// MaxVersionSkew is the maximum number of minor versions the Kubernetes version of this MachineDeployment can
+	// lag behind the version of the control plane while the Cluster is upgraded through the intermediate versions
+	// defined in the topology.cluster.x-k8s.io/upgrade-path annotation. The control plane moves to the next version
+	// as long as the skew is respected, and the MachineDeployment is upgraded only when required to respect the skew
+	// or when the control plane reached the final version, thus reducing the number of rollouts of its Machines.
+	// If not set, the MachineDeployment is upgraded to each intermediate version.
+	// NOTE: The skew must be supported by the Kubernetes version skew policy between kubelets and the API server.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=3
+	// +optional
+	MaxVersionSkew *int32 `json:"maxVersionSkew,omitempty"`
+
 	// Variables can be used to customize the MachineDeployment through patches.
 	// +optional
 	Variables *MachineDeploymentVariables `json:"variables,omitempty"`
+
+	// BootstrapOverrides can be used to customize the node registration settings of the bootstrap
+	// configuration template defined in the MachineDeploymentClass for this MachineDeployment only.
+	// NOTE: Overrides are exposed to patches through the builtin.machineDeployment.bootstrap.overrides variable
+	// and applied to the KubeadmConfigTemplate of the MachineDeployment before the patches defined in the ClusterClass.
+	// +optional
+	BootstrapOverrides *MachineDeploymentBootstrapOverrides `json:"bootstrapOverrides,omitempty"`
+}
+
+// MachinePoolTopology specifies the different parameters for a pool of worker nodes in the topology.
+// This pool of nodes is managed by a MachinePool object whose lifecycle is managed by the Cluster controller.
+type MachinePoolTopology struct {
+	// Metadata is the metadata applied to the MachinePool.
+	// At runtime this metadata is merged with the corresponding metadata from the ClusterClass.
+	// +optional
+	Metadata ObjectMeta `json:"metadata,omitempty"`
+
+	// Class is the name of the MachinePoolClass used to create the pool of worker nodes.
+	// This should match one of the machine pool classes defined in the ClusterClass object
+	// mentioned in the `Cluster.Spec.Class` field.
+	Class string `json:"class"`
+
+	// Name is the unique identifier for this MachinePoolTopology.
+	// The value is used with other unique identifiers to create a MachinePool's Name
+	// (e.g. cluster's name, etc). In case the name is greater than the allowed maximum length,
+	// the values are hashed together.
+	Name string `json:"name"`
+
+	// FailureDomains is the list of failure domains the machine pool will be created in.
+	// Must match a key in the FailureDomains map stored on the cluster object.
+	// +optional
+	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// NodeDrainTimeout is the total amount of time that the controller will spend on draining a node.
+	// The default value is 0, meaning that the node can be drained without any time limitations.
+	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
+	// +optional
+	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// NodeVolumeDetachTimeout is the total amount of time that the controller will spend on waiting for all volumes
+	// to be detached. The default value is 0, meaning that the volumes can be detached without any time limitations.
+	// +optional
+	NodeVolumeDetachTimeout *metav1.Duration `json:"nodeVolumeDetachTimeout,omitempty"`
+
+	// NodeDeletionTimeout defines how long the controller will attempt to delete the Node that the MachinePool
+	// hosts after the MachinePool is marked for deletion. A duration of 0 will retry deletion indefinitely.
+	// Defaults to 10 seconds.
+	// +optional
+	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// Minimum number of seconds for which a newly created machine pool should
+	// be ready.
+	// Defaults to 0 (machine will be considered available as soon as it
+	// is ready)
+	// +optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// Replicas is the number of nodes belonging to this pool.
+	// If the value is nil, the MachinePool is created without the number of Replicas (defaulting to 1)
+	// and it's assumed that an external entity (like cluster autoscaler) is responsible for the management
+	// of this value.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+}
+
+// MachineDeploymentBootstrapOverrides defines the node registration settings overridden for the
+// bootstrap configuration template of a MachineDeployment.
+type MachineDeploymentBootstrapOverrides struct {
+	// KubeletExtraArgs are extra arguments passed to the kubelet, merged with the ones defined in the
+	// bootstrap configuration template and taking precedence in case of conflicts.
+	// +optional
+	KubeletExtraArgs map[string]string `json:"kubeletExtraArgs,omitempty"`
+
+	// NodeLabels are labels set on the Nodes by the kubelet when registering, passed to the kubelet through
+	// the node-labels argument and merged with the ones already defined in the bootstrap configuration template.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// Taints are taints set on the Nodes when registering, added to the ones defined in the bootstrap
+	// configuration template; taints with the same key and effect are replaced.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
 }
 
 // MachineHealthCheckTopology defines a MachineHealthCheck for a group of machines.
@@ -249,7 +403,30 @@ type ClusterVariable struct {
 	// hard-coded schema for apiextensionsv1.JSON which cannot be produced by another type via controller-tools,
 	// i.e. it is not possible to have no type field.
 	// Ref: https://github.com/kubernetes-sigs/controller-tools/blob/d0e03a142d0ecdd5491593e941ee1d6b5d91dba6/pkg/crd/known_types.go#L106-L111
-	Value apiextensionsv1.JSON `json:"value"`
+	// Note: value and valueFrom are mutually exclusive.
+	// +optional
+	Value apiextensionsv1.JSON `json:"value,omitempty"`
+
+	// ValueFrom is a source for the value of the variable.
+	// The value is read from a Secret or a ConfigMap in the same namespace as the Cluster when computing
+	// the desired state of the Cluster topology, and it is never written into the Cluster object.
+	// Note: value and valueFrom are mutually exclusive.
+	// +optional
+	ValueFrom *ClusterVariableValueSource `json:"valueFrom,omitempty"`
+}
+
+// ClusterVariableValueSource represents a source for the value of a ClusterVariable.
+// Only one of its fields may be set.
+// If the corresponding ClusterClassVariable is of type string, the content of the key is used as is,
+// otherwise the content of the key must be a valid JSON value.
+type ClusterVariableValueSource struct {
+	// SecretKeyRef selects a key of a Secret in the same namespace as the Cluster.
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// ConfigMapKeyRef selects a key of a ConfigMap in the same namespace as the Cluster.
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
 }
 
 // MachineDeploymentVariables can be used to provide variables for a specific MachineDeployment.
@@ -334,6 +511,16 @@ type ClusterStatus struct {
 	// +optional
 	ControlPlaneReady bool `json:"controlPlaneReady"`
 
+	// ControlPlaneAddresses are the addresses of the control plane Machines of the cluster, without duplicates and
+	// ordered as the addresses of Machines, i.e. by address type and with the addresses of the primary IP family first.
+	// +optional
+	ControlPlaneAddresses MachineAddresses `json:"controlPlaneAddresses,omitempty"`
+
+	// IPFamilies are the IP families of the addresses of the control plane Machines of the cluster, with the primary
+	// IP family first, e.g. [IPv6, IPv4] for a dual-stack cluster where IPv6 is the primary IP family.
+	// +optional
+	IPFamilies []AddressIPFamily `json:"ipFamilies,omitempty"`
+
 	// Conditions defines current service state of the cluster.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
@@ -341,6 +528,11 @@ type ClusterStatus struct {
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// TopologyObservedGeneration is the latest generation observed by the topology controller, i.e. the generation
+	// the TopologyReconciled and TopologyInSync conditions refer to; it is set only for Clusters with a managed topology.
+	// +optional
+	TopologyObservedGeneration int64 `json:"topologyObservedGeneration,omitempty"`
 }
 
 // ANCHOR_END: ClusterStatus
@@ -61,6 +61,11 @@ type ClusterClassSpec struct {
 	// +optional
 	Workers WorkersClass `json:"workers,omitempty"`
 
+	// ClusterNetwork defines the defaults for the cluster network of the Clusters using this ClusterClass,
+	// and the ranges Clusters are permitted to use when overriding them.
+	// +optional
+	ClusterNetwork *ClusterClassNetwork `json:"clusterNetwork,omitempty"`
+
 	// Variables defines the variables which can be configured
 	// in the Cluster topology and are then used in patches.
 	// +optional
@@ -95,6 +100,18 @@ type ControlPlaneClass struct {
 	// +optional
 	MachineInfrastructure *LocalObjectTemplate `json:"machineInfrastructure,omitempty"`
 
+	// FailureDomainMachineInfrastructure defines the infrastructure information for control plane
+	// machines in specific failure domains, e.g. to use different instance types in each zone;
+	// control plane machines in failure domains not included in this list use MachineInfrastructure.
+	//
+	// This field is supported if and only if MachineInfrastructure is defined and the control plane
+	// provider supports spec.machineTemplate.failureDomainInfrastructureRefs.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=failureDomain
+	FailureDomainMachineInfrastructure []FailureDomainMachineInfrastructure `json:"failureDomainMachineInfrastructure,omitempty"`
+
 	// MachineHealthCheck defines a MachineHealthCheck for this ControlPlaneClass.
 	// This field is supported if and only if the ControlPlane provider template
 	// referenced above is Machine based and supports setting replicas.
@@ -120,6 +137,53 @@ type ControlPlaneClass struct {
 	// NOTE: This value can be overridden while defining a Cluster.Topology.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// AdditionalTags is an optional set of tags to be added to the cloud resources created by the
+	// infrastructure provider for control plane Machines.
+	// NOTE: Tags defined while defining a Cluster.Topology are merged with these tags,
+	// taking precedence in case of conflicts.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+}
+
+// FailureDomainMachineInfrastructure defines the infrastructure information for control plane machines in a failure domain.
+type FailureDomainMachineInfrastructure struct {
+	// FailureDomain is the name of the failure domain.
+	// Must match a key in the FailureDomains map stored on the Cluster object.
+	FailureDomain string `json:"failureDomain"`
+
+	// LocalObjectTemplate contains the reference to the infrastructure machine template.
+	LocalObjectTemplate `json:",inline"`
+}
+
+// ClusterClassNetwork defines the defaults for the cluster network of the Clusters using a ClusterClass.
+// Defaults are applied by the topology controller to the clusterNetwork of a Cluster when the corresponding
+// field is not set.
+type ClusterClassNetwork struct {
+	// Services defines the default and the permitted network ranges from which service VIPs are allocated.
+	// +optional
+	Services *ClusterClassNetworkRanges `json:"services,omitempty"`
+
+	// Pods defines the default and the permitted network ranges from which Pod networks are allocated.
+	// +optional
+	Pods *ClusterClassNetworkRanges `json:"pods,omitempty"`
+
+	// ServiceDomain is the default domain name for services.
+	// +optional
+	ServiceDomain string `json:"serviceDomain,omitempty"`
+}
+
+// ClusterClassNetworkRanges defines the default and the permitted ranges of network addresses.
+type ClusterClassNetworkRanges struct {
+	// CIDRBlocks is the default list of CIDR blocks, used when the Cluster does not define any.
+	// Each CIDR block must be contained in one of the PermittedCIDRBlocks, if defined.
+	// +optional
+	CIDRBlocks []string `json:"cidrBlocks,omitempty"`
+
+	// PermittedCIDRBlocks is the list of CIDR blocks which contain the CIDR blocks a Cluster is permitted to use.
+	// If not set, Clusters can use any CIDR block.
+	// +optional
+	PermittedCIDRBlocks []string `json:"permittedCIDRBlocks,omitempty"`
 }
 
 // WorkersClass is a collection of deployment classes.
@@ -128,6 +192,12 @@ type WorkersClass struct {
 	// a set of worker nodes.
 	// +optional
 	MachineDeployments []MachineDeploymentClass `json:"machineDeployments,omitempty"`
+
+	// MachinePools is a list of machine pool classes that can be used to create
+	// a set of worker nodes.
+	// NOTE: MachinePools can be used only if the MachinePool feature flag is enabled.
+	// +optional
+	MachinePools []MachinePoolClass `json:"machinePools,omitempty"`
 }
 
 // MachineDeploymentClass serves as a template to define a set of worker nodes of the cluster
@@ -172,6 +242,13 @@ type MachineDeploymentClass struct {
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
 
+	// AdditionalTags is an optional set of tags to be added to the cloud resources created by the
+	// infrastructure provider for Machines of this MachineDeploymentClass.
+	// NOTE: Tags defined while defining a Cluster.Topology using this MachineDeploymentClass are merged
+	// with these tags, taking precedence in case of conflicts.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+
 	// Minimum number of seconds for which a newly created machine should
 	// be ready.
 	// Defaults to 0 (machine will be considered available as soon as it
@@ -202,6 +279,70 @@ type MachineDeploymentClassTemplate struct {
 	Infrastructure LocalObjectTemplate `json:"infrastructure"`
 }
 
+// MachinePoolClass serves as a template to define a pool of worker nodes of the cluster
+// provisioned using `ClusterClass`.
+type MachinePoolClass struct {
+	// Class denotes a type of machine pool present in the cluster,
+	// this name MUST be unique within a ClusterClass and can be referenced
+	// in the Cluster to create a managed MachinePool.
+	Class string `json:"class"`
+
+	// Template is a local struct containing a collection of templates for creation of
+	// MachinePool objects representing a pool of worker nodes.
+	Template MachinePoolClassTemplate `json:"template"`
+
+	// FailureDomains is the list of failure domains the MachinePool should be attached to.
+	// Must match a key in the FailureDomains map stored on the cluster object.
+	// NOTE: This value can be overridden while defining a Cluster.Topology using this MachinePoolClass.
+	// +optional
+	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// NodeDrainTimeout is the total amount of time that the controller will spend on draining a node.
+	// The default value is 0, meaning that the node can be drained without any time limitations.
+	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
+	// NOTE: This value can be overridden while defining a Cluster.Topology using this MachinePoolClass.
+	// +optional
+	NodeDrainTimeout *metav1.Duration `json:"nodeDrainTimeout,omitempty"`
+
+	// NodeVolumeDetachTimeout is the total amount of time that the controller will spend on waiting for all volumes
+	// to be detached. The default value is 0, meaning that the volumes can be detached without any time limitations.
+	// NOTE: This value can be overridden while defining a Cluster.Topology using this MachinePoolClass.
+	// +optional
+	NodeVolumeDetachTimeout *metav1.Duration `json:"nodeVolumeDetachTimeout,omitempty"`
+
+	// NodeDeletionTimeout defines how long the controller will attempt to delete the Node that the Machine
+	// hosts after the Machine Pool is marked for deletion. A duration of 0 will retry deletion indefinitely.
+	// Defaults to 10 seconds.
+	// NOTE: This value can be overridden while defining a Cluster.Topology using this MachinePoolClass.
+	// +optional
+	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// Minimum number of seconds for which a newly created machine pool should
+	// be ready.
+	// Defaults to 0 (machine will be considered available as soon as it
+	// is ready)
+	// NOTE: This value can be overridden while defining a Cluster.Topology using this MachinePoolClass.
+	// +optional
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+}
+
+// MachinePoolClassTemplate defines how a MachinePool generated from a MachinePoolClass
+// should look like.
+type MachinePoolClassTemplate struct {
+	// Metadata is the metadata applied to the MachinePool.
+	// At runtime this metadata is merged with the corresponding metadata from the topology.
+	// +optional
+	Metadata ObjectMeta `json:"metadata,omitempty"`
+
+	// Bootstrap contains the bootstrap template reference to be used
+	// for the creation of the Machines in the MachinePool.
+	Bootstrap LocalObjectTemplate `json:"bootstrap"`
+
+	// Infrastructure contains the infrastructure template reference to be used
+	// for the creation of the MachinePool.
+	Infrastructure LocalObjectTemplate `json:"infrastructure"`
+}
+
 // MachineHealthCheckClass defines a MachineHealthCheck for a group of Machines.
 type MachineHealthCheckClass struct {
 	// UnhealthyConditions contains a list of the conditions that determine
@@ -467,6 +608,11 @@ type PatchSelectorMatch struct {
 	// .spec.workers.machineDeployments.
 	// +optional
 	MachineDeploymentClass *PatchSelectorMatchMachineDeploymentClass `json:"machineDeploymentClass,omitempty"`
+
+	// MachinePoolClass selects templates referenced in specific MachinePoolClasses in
+	// .spec.workers.machinePools.
+	// +optional
+	MachinePoolClass *PatchSelectorMatchMachinePoolClass `json:"machinePoolClass,omitempty"`
 }
 
 // PatchSelectorMatchMachineDeploymentClass selects templates referenced
@@ -477,6 +623,14 @@ type PatchSelectorMatchMachineDeploymentClass struct {
 	Names []string `json:"names,omitempty"`
 }
 
+// PatchSelectorMatchMachinePoolClass selects templates referenced
+// in specific MachinePoolClasses in .spec.workers.machinePools.
+type PatchSelectorMatchMachinePoolClass struct {
+	// Names selects templates by class names.
+	// +optional
+	Names []string `json:"names,omitempty"`
+}
+
 // JSONPatch defines a JSON patch.
 type JSONPatch struct {
 	// Op defines the operation of the patch.
@@ -548,6 +702,10 @@ type ClusterClassStatus struct {
 	// Conditions defines current observed state of the ClusterClass.
 	// +optional
 	Conditions Conditions `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // GetConditions returns the set of conditions for this object.
@@ -26,9 +26,20 @@ const (
 	// external objects(bootstrap and infrastructure providers).
 	ClusterLabelName = "cluster.x-k8s.io/cluster-name"
 
+	// ClusterProfileLabelName is the label set on Clusters to track their profile, or tier, e.g. dev, stage or prod;
+	// it is set by the topology controller from Cluster.spec.topology.profile, and it can be used by ClusterResourceSets,
+	// webhooks and other policies to target sets of Clusters.
+	ClusterProfileLabelName = "cluster.x-k8s.io/profile"
+
 	// ClusterTopologyOwnedLabel is the label set on all the object which are managed as part of a ClusterTopology.
 	ClusterTopologyOwnedLabel = "topology.cluster.x-k8s.io/owned"
 
+	// ClusterTopologyClassNameLabel is the label set on the objects managed as part of a ClusterTopology
+	// to track the name of the ClusterClass they have been generated from.
+	// NOTE: This label is not set on MachineDeployment selectors and on Machines, because changing its value
+	// during a ClusterClass rebase should not trigger rollouts.
+	ClusterTopologyClassNameLabel = "topology.cluster.x-k8s.io/class-name"
+
 	// ClusterTopologyManagedFieldsAnnotation is the annotation used to store the list of paths managed
 	// by the topology controller; changes to those paths will be considered authoritative.
 	// NOTE: Managed field depends on the last reconciliation of a managed object; this list can
@@ -49,15 +60,169 @@ const (
 	// to track the name of the MachineDeployment topology it represents.
 	ClusterTopologyMachineDeploymentLabelName = "topology.cluster.x-k8s.io/deployment-name"
 
+	// ClusterTopologyMachinePoolNameLabel is the label set on the generated MachinePool objects
+	// to track the name of the MachinePool topology it represents.
+	ClusterTopologyMachinePoolNameLabel = "topology.cluster.x-k8s.io/pool-name"
+
+	// ClusterTopologyMachineDeploymentRolloutAfterAnnotation is set by the topology controller on the Machine template
+	// of a MachineDeployment to the rolloutAfter value of the MachineDeployment topology once it is reached; each new
+	// value changes the Machine template, thus triggering a rollout of the MachineDeployment.
+	ClusterTopologyMachineDeploymentRolloutAfterAnnotation = "topology.cluster.x-k8s.io/rollout-after"
+
 	// ClusterTopologyUnsafeUpdateClassNameAnnotation can be used to disable the webhook check on
 	// update that disallows a pre-existing Cluster to be populated with Topology information and Class.
 	ClusterTopologyUnsafeUpdateClassNameAnnotation = "unsafe.topology.cluster.x-k8s.io/disable-update-class-name-check"
 
+	// ClusterTopologyAllowedClassesAnnotation can be set on a Namespace to restrict the ClusterClasses that
+	// Clusters in that namespace can use. The value is a comma separated list of ClusterClass names (e.g. "gold,silver")
+	// and Cluster.spec.topology.class must be one of them.
+	// NOTE: This annotation should be settable only by platform administrators, e.g. using RBAC on Namespaces.
+	ClusterTopologyAllowedClassesAnnotation = "topology.cluster.x-k8s.io/allowed-cluster-classes"
+
+	// ClusterTopologyMetadataPrecedenceAnnotation can be used to choose if labels and annotations defined in the
+	// Cluster topology or in the ClusterClass take precedence when the same key is defined in both with different values.
+	// Allowed values are Cluster (the default) and ClusterClass; when the annotation is set, the topology controller
+	// stops reporting the conflicting keys with warning events on the Cluster.
+	ClusterTopologyMetadataPrecedenceAnnotation = "topology.cluster.x-k8s.io/metadata-precedence"
+
+	// ClusterTopologyMetadataPrecedenceCluster is the ClusterTopologyMetadataPrecedenceAnnotation value to be used when
+	// labels and annotations defined in the Cluster topology should take precedence.
+	ClusterTopologyMetadataPrecedenceCluster = "Cluster"
+
+	// ClusterTopologyMetadataPrecedenceClusterClass is the ClusterTopologyMetadataPrecedenceAnnotation value to be used when
+	// labels and annotations defined in the ClusterClass should take precedence.
+	ClusterTopologyMetadataPrecedenceClusterClass = "ClusterClass"
+
+	// ClusterTopologyIgnorePathsAnnotation can be set on a ClusterClass to define a comma separated list of paths, e.g.
+	// spec.template.spec.foo, that the topology controller should ignore when reconciling the InfrastructureCluster,
+	// the ControlPlane and the templates generated from the ClusterClass.
+	// This allows to stop perpetual updates or template rotations when providers populate fields with values that
+	// differ from the ones in the ClusterClass templates; ignored paths are considered only when updating existing objects,
+	// so objects are created with all the fields and then fields under ignored paths are left to providers and users.
+	// NOTE: Paths must be under spec; paths pointing to an array item are not supported.
+	ClusterTopologyIgnorePathsAnnotation = "topology.cluster.x-k8s.io/ignore-paths"
+
+	// ClusterTopologyBuiltinPatchesAnnotation can be set on a ClusterClass to enable a comma separated list of builtin
+	// patches, e.g. proxy,registryMirrors. Each builtin patch injects the value of the Cluster topology variable with the
+	// same name, which must be defined in the ClusterClass, into the KubeadmControlPlaneTemplate and into all the
+	// KubeadmConfigTemplates of the ClusterClass, or into the InfrastructureClusterTemplate for the controlPlaneLoadBalancer
+	// builtin patch; builtin patches are applied before the patches of the ClusterClass.
+	ClusterTopologyBuiltinPatchesAnnotation = "topology.cluster.x-k8s.io/builtin-patches"
+
+	// ClusterTopologyBuiltinPatchProxy is the builtin patch configuring the HTTP proxy for containerd on all the machines;
+	// the value of the proxy variable is an object with the httpProxy, httpsProxy and noProxy fields.
+	ClusterTopologyBuiltinPatchProxy = "proxy"
+
+	// ClusterTopologyBuiltinPatchRegistryMirrors is the builtin patch configuring registry mirrors for containerd on all
+	// the machines; the value of the registryMirrors variable is a list of objects with the registry and endpoints fields.
+	ClusterTopologyBuiltinPatchRegistryMirrors = "registryMirrors"
+
+	// ClusterTopologyBuiltinPatchControlPlaneLoadBalancer is the builtin patch configuring the control plane load balancer
+	// in the InfrastructureClusterTemplate; the value of the controlPlaneLoadBalancer variable is an object with the scheme,
+	// internal or external, and allowedCIDRs fields.
+	ClusterTopologyBuiltinPatchControlPlaneLoadBalancer = "controlPlaneLoadBalancer"
+
+	// ControlPlaneLoadBalancerSchemePathAnnotation can be set on the CustomResourceDefinition of an InfrastructureClusterTemplate
+	// to declare the path of the field storing the scheme of the control plane load balancer, e.g.
+	// spec.template.spec.controlPlaneLoadBalancer.scheme; the field is set by the controlPlaneLoadBalancer builtin patch.
+	ControlPlaneLoadBalancerSchemePathAnnotation = "topology.cluster.x-k8s.io/control-plane-load-balancer-scheme-path"
+
+	// ControlPlaneLoadBalancerSchemesAnnotation can be set on the CustomResourceDefinition of an InfrastructureClusterTemplate
+	// to declare the values of the scheme field for the internal and external schemes of the control plane load balancer,
+	// e.g. internal=internal,external=internet-facing; internal and external are used for schemes not declared.
+	ControlPlaneLoadBalancerSchemesAnnotation = "topology.cluster.x-k8s.io/control-plane-load-balancer-schemes"
+
+	// ControlPlaneLoadBalancerAllowedCIDRsPathAnnotation can be set on the CustomResourceDefinition of an InfrastructureClusterTemplate
+	// to declare the path of the field storing the CIDRs allowed to reach the control plane load balancer, e.g.
+	// spec.template.spec.controlPlaneLoadBalancer.allowedCIDRs; the field is set by the controlPlaneLoadBalancer builtin patch.
+	ControlPlaneLoadBalancerAllowedCIDRsPathAnnotation = "topology.cluster.x-k8s.io/control-plane-load-balancer-allowed-cidrs-path"
+
+	// ClusterTopologyDriftPolicyAnnotation can be set on a Cluster with a managed topology to enable the detection of
+	// out-of-band changes to the InfrastructureCluster, the ControlPlane and the MachineDeployments generated from the
+	// Cluster topology, e.g. changes applied with kubectl. Allowed values are Report and Enforce; drift is reported in
+	// the TopologyInSync condition of the Cluster, in events and in metrics.
+	ClusterTopologyDriftPolicyAnnotation = "topology.cluster.x-k8s.io/drift-policy"
+
+	// ClusterTopologyDriftPolicyReport is the ClusterTopologyDriftPolicyAnnotation value to be used when out-of-band
+	// changes should be reported but not reverted; the changes are overwritten only when the desired state of the
+	// object changes, e.g. when the Cluster topology or the ClusterClass is changed.
+	ClusterTopologyDriftPolicyReport = "Report"
+
+	// ClusterTopologyDriftPolicyEnforce is the ClusterTopologyDriftPolicyAnnotation value to be used when out-of-band
+	// changes should be reported and then reverted, which is what the topology controller does by default.
+	ClusterTopologyDriftPolicyEnforce = "Enforce"
+
+	// ClusterTopologyDesiredStateHashAnnotation is set by the topology controller on objects generated from the topology
+	// of Clusters with the ClusterTopologyDriftPolicyAnnotation; it contains the hash of the desired state last applied
+	// to the object, and it is used to tell out-of-band changes from changes to the desired state.
+	ClusterTopologyDesiredStateHashAnnotation = "topology.cluster.x-k8s.io/desired-state-hash"
+
+	// ClusterTopologyInputsHashAnnotation is set by the topology controller on objects generated from a Cluster topology;
+	// it contains the hash of the inputs used to compute the desired state of the object, i.e. the generation of the
+	// ClusterClass, the resourceVersion of the templates the object is generated from and the Cluster topology.
+	// NOTE: A change of this annotation tells that the object has been changed because of a change of its inputs.
+	ClusterTopologyInputsHashAnnotation = "topology.cluster.x-k8s.io/inputs-hash"
+
+	// ClusterTopologyPausedAnnotation can be set on a Cluster with a managed topology to pause the reconciliation of
+	// the topology only, e.g. to hold changes to the ClusterClass or to the Cluster topology while other controllers,
+	// like the Cluster and the Machine controllers, keep operating as usual; see Cluster.spec.paused to pause all of them.
+	ClusterTopologyPausedAnnotation = "topology.cluster.x-k8s.io/paused"
+
+	// ClusterTopologyPlanAnnotation can be set on a Cluster with a managed topology to compute the desired state of the
+	// topology without applying it, e.g. to preview the impact of a change to the ClusterClass or to the Cluster topology
+	// before it rolls out; pending changes are reported in the TopologyReconciled condition of the Cluster and in events.
+	ClusterTopologyPlanAnnotation = "topology.cluster.x-k8s.io/plan"
+
+	// ClusterTopologyUpgradePathAnnotation can be set on a Cluster with a managed topology to define the comma separated
+	// list of intermediate versions, e.g. v1.24.7,v1.25.3, to upgrade through when the version defined in the topology is
+	// increased by more than one minor version; the control plane and the MachineDeployments are upgraded to each of the
+	// intermediate versions in order, given that kubeadm does not support skipping minor versions.
+	ClusterTopologyUpgradePathAnnotation = "topology.cluster.x-k8s.io/upgrade-path"
+
 	// ProviderLabelName is the label set on components in the provider manifest.
 	// This label allows to easily identify all the components belonging to a provider; the clusterctl
 	// tool uses this label for implementing provider's lifecycle operations.
 	ProviderLabelName = "cluster.x-k8s.io/provider"
 
+	// ClusterClassCNISupportedOSAnnotation can be set on a ClusterClass to define the comma separated list of
+	// operating systems, e.g. linux,windows, supported by the CNI of the clusters created from the class;
+	// the Cluster webhook rejects topologies with MachineDeployments using other operating systems, as defined
+	// by the kubernetes.io/os label in the ClusterClass or in the Cluster topology metadata.
+	ClusterClassCNISupportedOSAnnotation = "topology.cluster.x-k8s.io/cni-supported-os"
+
+	// MinimumKubernetesVersionAnnotation can be set by providers on their CRDs to define the minimum Kubernetes version,
+	// e.g. v1.21.0, supported for workload clusters; Clusters with older versions are reported by the
+	// KubernetesVersionSupported condition. See also MaximumKubernetesVersionAnnotation.
+	MinimumKubernetesVersionAnnotation = "cluster.x-k8s.io/minimum-kubernetes-version"
+
+	// MaximumKubernetesVersionAnnotation can be set by providers on their CRDs to define the maximum Kubernetes minor
+	// version, e.g. v1.25, supported for workload clusters; Clusters with newer versions are reported by the
+	// KubernetesVersionSupported condition. See also MinimumKubernetesVersionAnnotation.
+	MaximumKubernetesVersionAnnotation = "cluster.x-k8s.io/maximum-kubernetes-version"
+
+	// BootstrapTokenTTLAnnotation can be set on a Cluster to define the TTL, e.g. 30m, of the bootstrap tokens created
+	// by bootstrap providers for the machines of the Cluster, overriding the provider default, e.g. the value of the
+	// --bootstrap-token-ttl flag of the kubeadm bootstrap provider. Tokens are refreshed or rotated accordingly.
+	BootstrapTokenTTLAnnotation = "cluster.x-k8s.io/bootstrap-token-ttl"
+
+	// KubeletRotateServerCertificatesAnnotation can be set on a Cluster to define if the kubelet of all the machines of
+	// the Cluster should request its serving certificate from the API server and rotate it. Allowed values are true and
+	// false; bootstrap providers apply it to the kubelet configuration unless it is already explicitly set there.
+	// NOTE: When set to true, the kubelet serving certificate signing requests must be approved, e.g. by deploying a
+	// kubelet-serving CSR approver in the workload cluster; otherwise kubelets have no serving certificate, and
+	// operations like kubectl logs and kubectl exec fail.
+	KubeletRotateServerCertificatesAnnotation = "cluster.x-k8s.io/kubelet-rotate-server-certificates"
+
+	// ClusterDeletionProtectionAnnotation can be set on a Cluster to protect it from accidental deletion. The value is the
+	// maximum number of Machines the Cluster can have to be deleted, e.g. 0 to protect any Cluster with Machines; Clusters
+	// with more Machines can be deleted only after being unlocked using the ClusterDeletionUnlockAnnotation.
+	ClusterDeletionProtectionAnnotation = "cluster.x-k8s.io/deletion-protection"
+
+	// ClusterDeletionUnlockAnnotation can be set on a Cluster protected by the ClusterDeletionProtectionAnnotation to allow
+	// its deletion until the given expiry time, in RFC3339 format, e.g. 2022-10-17T15:04:05Z. The expiry time can be at
+	// most one hour in the future.
+	ClusterDeletionUnlockAnnotation = "cluster.x-k8s.io/deletion-unlock"
+
 	// ClusterNameAnnotation is the annotation set on nodes identifying the name of the cluster the node belongs to.
 	ClusterNameAnnotation = "cluster.x-k8s.io/cluster-name"
 
@@ -73,6 +238,9 @@ const (
 	// OwnerNameAnnotation is the annotation set on nodes identifying the owner name.
 	OwnerNameAnnotation = "cluster.x-k8s.io/owner-name"
 
+	// MachineDeploymentAnnotation is the annotation set on nodes identifying the MachineDeployment the node belongs to, if any.
+	MachineDeploymentAnnotation = "cluster.x-k8s.io/machine-deployment"
+
 	// PausedAnnotation is an annotation that can be applied to any Cluster API
 	// object to prevent a controller from processing a resource.
 	//
@@ -85,6 +253,13 @@ const (
 	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
 	DisableMachineCreate = "cluster.x-k8s.io/disable-machine-create"
 
+	// ProviderIDPoolAnnotation is an annotation that can be set on a MachineSet or on a MachineDeployment to define a
+	// comma separated list of provider IDs, e.g. of known bare-metal hosts, to be pre-allocated to the machines created by
+	// the MachineSet; each new Machine gets a provider ID from the pool which is not yet used by other Machines in the
+	// same Cluster, and new Machines are not created when the pool is exhausted.
+	// NOTE: Infrastructure providers are expected to honor Machine.Spec.ProviderID when provisioning a machine.
+	ProviderIDPoolAnnotation = "cluster.x-k8s.io/provider-id-pool"
+
 	// WatchLabel is a label othat can be applied to any Cluster API object.
 	//
 	// Controllers which allow for selective reconciliation may check this label and proceed
@@ -103,15 +278,34 @@ const (
 	// that was cloned for the machine. This annotation is set only during cloning a template. Older/adopted machines will not have this annotation.
 	TemplateClonedFromGroupKindAnnotation = "cluster.x-k8s.io/cloned-from-groupkind"
 
+	// OrphanMachinesOnDeleteAnnotation can be set on a MachineDeployment or on a MachineSet to delete it while
+	// orphaning its Machines, i.e. Machines are not deleted and they can be adopted later by another MachineSet
+	// with a matching selector.
+	OrphanMachinesOnDeleteAnnotation = "cluster.x-k8s.io/orphan-machines-on-delete"
+
 	// MachineSkipRemediationAnnotation is the annotation used to mark the machines that should not be considered for remediation by MachineHealthCheck reconciler.
 	MachineSkipRemediationAnnotation = "cluster.x-k8s.io/skip-remediation"
 
+	// InstanceRefreshedAnnotation can be set by infrastructure providers on an InfraMachine to signal that the
+	// underlying instance has been replaced or restarted outside of Cluster API. The value must change every time
+	// this happens, e.g. by using a timestamp; the Machine controller reacts to a new value by revalidating
+	// the Machine's NodeRef, ProviderID, addresses and conditions instead of keeping stale status.
+	InstanceRefreshedAnnotation = "cluster.x-k8s.io/instance-refreshed"
+
+	// ObservedInstanceRefreshAnnotation is set on a Machine by the Machine controller with the last value of the
+	// InstanceRefreshedAnnotation it has processed.
+	ObservedInstanceRefreshAnnotation = "cluster.x-k8s.io/observed-instance-refresh"
+
 	// ClusterSecretType defines the type of secret created by core components.
 	ClusterSecretType corev1.SecretType = "cluster.x-k8s.io/secret" //nolint:gosec
 
 	// InterruptibleLabel is the label used to mark the nodes that run on interruptible instances.
 	InterruptibleLabel = "cluster.x-k8s.io/interruptible"
 
+	// ManagedNodeLabelDomain is the domain of the labels that are kept in sync from a Machine to its Node,
+	// e.g. node.cluster.x-k8s.io/pool or pool.node.cluster.x-k8s.io/name.
+	ManagedNodeLabelDomain = "node.cluster.x-k8s.io"
+
 	// ManagedByAnnotation is an annotation that can be applied to InfraCluster resources to signify that
 	// some external system is managing the cluster infrastructure.
 	//
@@ -170,6 +364,19 @@ type MachineAddress struct {
 // MachineAddresses is a slice of MachineAddress items to be used by infrastructure providers.
 type MachineAddresses []MachineAddress
 
+// AddressIPFamily is the IP family of an address.
+// +kubebuilder:validation:Enum=IPv4;IPv6
+type AddressIPFamily string
+
+// Define the AddressIPFamily constants.
+const (
+	// IPv4AddressFamily is the IP family of IPv4 addresses.
+	IPv4AddressFamily AddressIPFamily = "IPv4"
+
+	// IPv6AddressFamily is the IP family of IPv6 addresses.
+	IPv6AddressFamily AddressIPFamily = "IPv6"
+)
+
 // ObjectMeta is metadata that all persisted resources must have, which includes all objects
 // users must create. This is a copy of customizable fields from metav1.ObjectMeta.
 //
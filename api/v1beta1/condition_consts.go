@@ -45,12 +45,26 @@ const (
 	// This condition is mirrored from the Ready condition in the infrastructure ref object, and
 	// the absence of this condition might signal problems in the reconcile external loops or the fact that
 	// the infrastructure provider does not implement the Ready condition yet.
+	// For Clusters, when the infrastructure is not ready, messages from the other conditions of the infrastructure
+	// object are appended to the condition message.
 	InfrastructureReadyCondition ConditionType = "InfrastructureReady"
 
 	// WaitingForInfrastructureFallbackReason (Severity=Info) documents a cluster/machine/machinepool waiting for the underlying infrastructure
 	// to be available.
 	// NOTE: This reason is used only as a fallback when the infrastructure object is not reporting its own ready condition.
 	WaitingForInfrastructureFallbackReason = "WaitingForInfrastructure"
+
+	// InfrastructureQuotaExceededReason (Severity=Warning) documents an infrastructure object which cannot be provisioned
+	// because a quota of the underlying infrastructure, e.g. the cloud account quota for instances or cores, is exceeded.
+	// NOTE: This reason is part of the infrastructure provider contract; providers should set it on the Ready condition of
+	// an InfrastructureMachine, which is mirrored into the InfrastructureReady condition of the Machine. The MachineSet
+	// controller then stops creating new Machines, given that they would be blocked by the same quota.
+	InfrastructureQuotaExceededReason = "InfrastructureQuotaExceeded"
+
+	// WaitingForInfrastructureReadinessChecksReason (Severity=Info) documents a machine whose infrastructure provider
+	// reports status.ready, but which is waiting for the infrastructure readiness checks configured in the Machine controller
+	// to pass, e.g. for the cloud instance to pass the health checks of the cloud provider.
+	WaitingForInfrastructureReadinessChecksReason = "WaitingForInfrastructureReadinessChecks"
 )
 
 // ANCHOR_END: CommonConditions
@@ -76,6 +90,8 @@ const (
 	// This condition is mirrored from the Ready condition in the control plane ref object, and
 	// the absence of this condition might signal problems in the reconcile external loops or the fact that
 	// the control plane provider does not implement the Ready condition yet.
+	// When the control plane is not ready, messages from the other conditions of the control plane object are
+	// appended to the condition message.
 	ControlPlaneReadyCondition ConditionType = "ControlPlaneReady"
 
 	// WaitingForControlPlaneFallbackReason (Severity=Info) documents a cluster waiting for the control plane
@@ -89,6 +105,40 @@ const (
 	// NOTE: Having the control plane machine available is a pre-condition for joining additional control planes
 	// or workers nodes.
 	WaitingForControlPlaneAvailableReason = "WaitingForControlPlaneAvailable"
+
+	// ReconcileCircuitClosedCondition documents that a Cluster is reconciled normally; when the reconciliation of
+	// a Cluster fails too many consecutive times the circuit is opened, and the Cluster is reconciled only at a long
+	// interval so it does not consume a disproportionate share of the controller workers.
+	// NOTE: This condition is set only after the circuit has been opened at least once for the Cluster.
+	ReconcileCircuitClosedCondition ConditionType = "ReconcileCircuitClosed"
+
+	// ReconcileCircuitOpenReason (Severity=Warning) documents a Cluster whose reconciliation failed too many
+	// consecutive times.
+	ReconcileCircuitOpenReason = "ReconcileCircuitOpen"
+
+	// RemoteConnectionProbeCondition reports whether the API server of the workload cluster can be reached, as observed
+	// by the periodic probes of the connection to the workload cluster; it makes unreachable or slow workload clusters
+	// visible before the controllers accessing them start failing.
+	// NOTE: This condition is set only after the connection to the workload cluster has been probed at least once.
+	RemoteConnectionProbeCondition ConditionType = "RemoteConnectionProbe"
+
+	// RemoteConnectionFailedReason (Severity=Warning) documents a Cluster whose API server could not be reached
+	// by the last probes.
+	RemoteConnectionFailedReason = "RemoteConnectionFailed"
+
+	// KubernetesVersionSupportedCondition reports whether the Kubernetes version of a Cluster is supported by the
+	// installed Cluster API release and by the providers of the InfrastructureCluster and of the ControlPlane.
+	// NOTE: This condition is set only when the Kubernetes version of the Cluster is known, i.e. it is defined
+	// in the Cluster topology or in the ControlPlane object.
+	KubernetesVersionSupportedCondition ConditionType = "KubernetesVersionSupported"
+
+	// KubernetesVersionTooOldReason (Severity=Warning) documents a Cluster with a Kubernetes version older than
+	// the minimum version supported by Cluster API or by one of its providers.
+	KubernetesVersionTooOldReason = "KubernetesVersionTooOld"
+
+	// KubernetesVersionTooNewReason (Severity=Warning) documents a Cluster with a Kubernetes version newer than
+	// the maximum version supported by Cluster API or by one of its providers.
+	KubernetesVersionTooNewReason = "KubernetesVersionTooNew"
 )
 
 // Conditions and condition Reasons for the Machine object.
@@ -105,6 +155,10 @@ const (
 	// NOTE: This reason is used only as a fallback when the bootstrap object is not reporting its own ready condition.
 	WaitingForDataSecretFallbackReason = "WaitingForDataSecret"
 
+	// BootstrapDataFormatMismatchReason (Severity=Error) documents a machine whose bootstrap data has an invalid format, or
+	// a format different from the one expected by the infrastructure provider.
+	BootstrapDataFormatMismatchReason = "BootstrapDataFormatMismatch"
+
 	// DrainingSucceededCondition provide evidence of the status of the node drain operation which happens during the machine
 	// deletion process.
 	DrainingSucceededCondition ConditionType = "DrainingSucceeded"
@@ -115,6 +169,10 @@ const (
 	// DrainingFailedReason (Severity=Warning) documents a machine node drain operation failed.
 	DrainingFailedReason = "DrainingFailed"
 
+	// DrainingBlockedByPodDisruptionBudgetReason (Severity=Info) documents a machine node drain operation
+	// blocked by PodDisruptionBudgets not allowing the eviction of some of the pods.
+	DrainingBlockedByPodDisruptionBudgetReason = "DrainingBlockedByPodDisruptionBudget"
+
 	// PreDrainDeleteHookSucceededCondition reports a machine waiting for a PreDrainDeleteHook before being delete.
 	PreDrainDeleteHookSucceededCondition ConditionType = "PreDrainDeleteHookSucceeded"
 
@@ -198,10 +256,25 @@ const (
 	// NB. provisioned --> NodeRef != "".
 	NodeNotFoundReason = "NodeNotFound"
 
+	// InstanceRefreshedReason documents a machine's node being revalidated because the infrastructure provider
+	// reported the underlying instance has been replaced or restarted outside of Cluster API.
+	InstanceRefreshedReason = "InstanceRefreshed"
+
 	// NodeConditionsFailedReason (Severity=Warning) documents a node is not in a healthy state due to the failed state of at least 1 Kubelet condition.
 	NodeConditionsFailedReason = "NodeConditionsFailed"
 )
 
+// Conditions and condition Reasons for the reboot of the Machine's instance.
+const (
+	// MachineRebootCompletedCondition is set to False on machines with a pending reboot request, and removed once the
+	// infrastructure provider acknowledges the reboot of the underlying instance.
+	MachineRebootCompletedCondition ConditionType = "RebootCompleted"
+
+	// WaitingForRebootReason (Severity=Info) documents a machine waiting for the infrastructure provider to reboot
+	// the underlying instance.
+	WaitingForRebootReason = "WaitingForReboot"
+)
+
 // Conditions and condition Reasons for the MachineHealthCheck object.
 
 const (
@@ -223,12 +296,26 @@ const (
 
 	// WaitingForAvailableMachinesReason (Severity=Warning) reflects the fact that the required minimum number of machines for a machinedeployment are not available.
 	WaitingForAvailableMachinesReason = "WaitingForAvailableMachines"
+
+	// MachineDeploymentProgressingCondition documents that the rollout of a MachineDeployment is making progress,
+	// that is, new machines are created or become ready within the progress deadline.
+	MachineDeploymentProgressingCondition ConditionType = "Progressing"
+
+	// ProgressDeadlineExceededReason (Severity=Warning) reflects the fact that the rollout of a MachineDeployment
+	// did not make progress within the progress deadline.
+	ProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+	// RolledBackReason (Severity=Warning) reflects the fact that a MachineDeployment has been rolled back to
+	// the previous MachineSet revision because the rollout did not make progress within the progress deadline.
+	RolledBackReason = "RolledBack"
 )
 
 // Conditions and condition Reasons for  MachineSets.
 
 const (
 	// MachinesCreatedCondition documents that the machines controlled by the MachineSet are created.
+	// NOTE: This condition is also set on MachineDeployments, where it is false when any of its MachineSets
+	// is not creating machines because of an infrastructure quota.
 	// When this condition is false, it indicates that there was an error when cloning the infrastructure/bootstrap template or
 	// when generating the machine object.
 	MachinesCreatedCondition ConditionType = "MachinesCreated"
@@ -248,6 +335,10 @@ const (
 	// generate a machine object.
 	MachineCreationFailedReason = "MachineCreationFailed"
 
+	// ProviderIDPoolExhaustedReason (Severity=Warning) documents a MachineSet failing to
+	// generate a machine object because there are no free provider IDs left in the ProviderIDPoolAnnotation.
+	ProviderIDPoolExhaustedReason = "ProviderIDPoolExhausted"
+
 	// ResizedCondition documents a MachineSet is resizing the set of controlled machines.
 	ResizedCondition ConditionType = "Resized"
 
@@ -279,9 +370,50 @@ const (
 	// not yet completed because at least one of the MachineDeployments is not yet updated to match the desired topology spec.
 	TopologyReconciledMachineDeploymentsUpgradePendingReason = "MachineDeploymentsUpgradePending"
 
+	// TopologyReconciledMachinePoolsUpgradePendingReason (Severity=Info) documents reconciliation of a Cluster topology
+	// not yet completed because at least one of the MachinePools is not yet updated to match the desired topology spec.
+	TopologyReconciledMachinePoolsUpgradePendingReason = "MachinePoolsUpgradePending"
+
 	// TopologyReconciledHookBlockingReason (Severity=Info) documents reconciliation of a Cluster topology
 	// not yet completed because at least one of the lifecycle hooks is blocking.
 	TopologyReconciledHookBlockingReason = "LifecycleHookBlocking"
+
+	// TopologyReconciledPausedReason (Severity=Info) documents reconciliation of a Cluster topology
+	// not yet completed because the Cluster has the ClusterTopologyPausedAnnotation.
+	TopologyReconciledPausedReason = "TopologyPaused"
+
+	// TopologyReconciledPlanReason (Severity=Info) documents reconciliation of a Cluster topology not applied
+	// because the Cluster has the ClusterTopologyPlanAnnotation; the message reports the pending changes.
+	TopologyReconciledPlanReason = "TopologyPlan"
+
+	// TopologyBlockedByWebhookReason (Severity=Warning) documents reconciliation of a Cluster topology
+	// not yet completed because a webhook required to create or update the objects of the Cluster is not available,
+	// e.g. while a provider is being upgraded; reconciliation is retried with a backoff.
+	TopologyBlockedByWebhookReason = "TopologyBlockedByWebhook"
+
+	// TopologyReconciledObjectNotOwnedReason (Severity=Warning) documents reconciliation of a Cluster topology
+	// not yet completed because an object referenced from the Cluster is not owned by the topology of the Cluster,
+	// e.g. it does not have the ClusterTopologyOwnedLabel or it belongs to another Cluster; the object is not overwritten.
+	TopologyReconciledObjectNotOwnedReason = "TopologyObjectNotOwned"
+
+	// TopologyInSyncCondition documents whether the objects generated from a Cluster topology have been changed
+	// out-of-band, i.e. by someone else than the topology controller.
+	// NOTE: This condition is set only on Clusters with the topology.cluster.x-k8s.io/drift-policy annotation.
+	TopologyInSyncCondition ConditionType = "TopologyInSync"
+
+	// TopologyDriftDetectedReason (Severity=Warning) documents out-of-band changes detected in at least one
+	// of the objects generated from a Cluster topology.
+	TopologyDriftDetectedReason = "DriftDetected"
+
+	// TopologyMetadataConsistentCondition documents whether labels and annotations defined both in the Cluster topology
+	// and in the ClusterClass have the same values.
+	// NOTE: This condition is not set on Clusters with the topology.cluster.x-k8s.io/metadata-precedence annotation,
+	// given that the precedence between conflicting values is chosen explicitly.
+	TopologyMetadataConsistentCondition ConditionType = "TopologyMetadataConsistent"
+
+	// TopologyMetadataConflictReason (Severity=Warning) documents labels or annotations defined both in the Cluster
+	// topology and in the ClusterClass with different values; values from the Cluster topology are used.
+	TopologyMetadataConflictReason = "MetadataConflict"
 )
 
 // Conditions and condition reasons for ClusterClass.
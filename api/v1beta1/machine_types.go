@@ -59,6 +59,45 @@ const (
 	// This annotation can be set on BootstrapConfig or Machine objects. The value set on the Machine object takes precedence.
 	// This annotation can only be used on Control Plane Machines.
 	MachineCertificatesExpiryDateAnnotation = "machine.cluster.x-k8s.io/certificates-expiry"
+
+	// MachineDeletionCauseAnnotation is set on a Machine by the controller deleting it to record why the Machine
+	// is being deleted, e.g. ScaleDown or Remediation. Machines deleted without this annotation are considered
+	// as deleted by the user.
+	MachineDeletionCauseAnnotation = "machine.cluster.x-k8s.io/deletion-cause"
+
+	// MachineDeletedByAnnotation is set on a Machine by the controller deleting it to record the name of the controller.
+	MachineDeletedByAnnotation = "machine.cluster.x-k8s.io/deleted-by"
+
+	// MachineRebootRequestedAnnotation can be set on a Machine to request the reboot of the underlying instance without
+	// deleting the Machine. The value must change for every request, e.g. by using a timestamp; the Machine controller
+	// copies the annotation to the InfraMachine, and removes it from both once the reboot has been acknowledged.
+	MachineRebootRequestedAnnotation = "machine.cluster.x-k8s.io/reboot-requested"
+
+	// MachineRebootAcknowledgedAnnotation is set on an InfraMachine by infrastructure providers supporting reboots,
+	// with the value of the MachineRebootRequestedAnnotation, once the underlying instance has been rebooted.
+	MachineRebootAcknowledgedAnnotation = "machine.cluster.x-k8s.io/reboot-acknowledged"
+)
+
+const (
+	// MachineDeletionCauseScaleDown is the MachineDeletionCauseAnnotation value used when a Machine is deleted
+	// because its owner has been scaled down.
+	MachineDeletionCauseScaleDown = "ScaleDown"
+
+	// MachineDeletionCauseRollout is the MachineDeletionCauseAnnotation value used when a Machine is deleted
+	// because it is being replaced by a Machine with the new spec of its owner.
+	MachineDeletionCauseRollout = "Rollout"
+
+	// MachineDeletionCauseRemediation is the MachineDeletionCauseAnnotation value used when a Machine is deleted
+	// because it has been marked as unhealthy by a MachineHealthCheck.
+	MachineDeletionCauseRemediation = "Remediation"
+
+	// MachineDeletionCauseOwnerDeletion is the MachineDeletionCauseAnnotation value used when a Machine is deleted
+	// because its owner is being deleted.
+	MachineDeletionCauseOwnerDeletion = "OwnerDeletion"
+
+	// MachineDeletionCauseUser is the deletion cause reported for Machines deleted without the
+	// MachineDeletionCauseAnnotation, e.g. by a user.
+	MachineDeletionCauseUser = "User"
 )
 
 // ANCHOR: MachineSpec
@@ -100,6 +139,13 @@ type MachineSpec struct {
 	// +optional
 	FailureDomain *string `json:"failureDomain,omitempty"`
 
+	// AdditionalTags is an optional set of tags to be added to the cloud resources created by the
+	// infrastructure provider for this Machine, e.g. instances, disks or network interfaces.
+	// Infrastructure providers are expected to propagate these tags in addition to the ones they manage;
+	// how tags are applied to cloud resources, e.g. restrictions on keys and values, is provider specific.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+
 	// NodeDrainTimeout is the total amount of time that the controller will spend on draining a node.
 	// The default value is 0, meaning that the node can be drained without any time limitations.
 	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
@@ -251,6 +297,33 @@ type Bootstrap struct {
 
 // ANCHOR_END: Bootstrap
 
+// BootstrapDataSecretFormatKey is the key of the bootstrap data secret reporting the format of the bootstrap data.
+const BootstrapDataSecretFormatKey = "format"
+
+// BootstrapDataFormat defines the format of the bootstrap data.
+type BootstrapDataFormat string
+
+const (
+	// BootstrapDataFormatCloudConfig is the format for bootstrap data consumed by cloud-init.
+	BootstrapDataFormatCloudConfig BootstrapDataFormat = "cloud-config"
+
+	// BootstrapDataFormatIgnition is the format for bootstrap data consumed by Ignition.
+	BootstrapDataFormatIgnition BootstrapDataFormat = "ignition"
+
+	// BootstrapDataFormatRaw is the format for bootstrap data which is passed to the machine as is,
+	// e.g. a shell script.
+	BootstrapDataFormatRaw BootstrapDataFormat = "raw"
+)
+
+// IsValid returns true if the bootstrap data format is one of the formats defined by the bootstrap contract.
+func (f BootstrapDataFormat) IsValid() bool {
+	switch f {
+	case BootstrapDataFormatCloudConfig, BootstrapDataFormatIgnition, BootstrapDataFormatRaw:
+		return true
+	}
+	return false
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=machines,shortName=ma,scope=Namespaced,categories=cluster-api
 // +kubebuilder:subresource:status
@@ -261,6 +334,9 @@ type Bootstrap struct {
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Machine status such as Terminating/Pending/Running/Failed etc"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of Machine"
 // +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="Kubernetes version associated with this Machine"
+// +kubebuilder:printcolumn:name="OS-Image",type="string",JSONPath=".status.nodeInfo.osImage",description="OS image reported by the Node associated with this Machine",priority=1
+// +kubebuilder:printcolumn:name="Kernel-Version",type="string",JSONPath=".status.nodeInfo.kernelVersion",description="Kernel version reported by the Node associated with this Machine",priority=1
+// +kubebuilder:printcolumn:name="Container-Runtime",type="string",JSONPath=".status.nodeInfo.containerRuntimeVersion",description="Container runtime version reported by the Node associated with this Machine",priority=1
 
 // Machine is the Schema for the machines API.
 type Machine struct {
@@ -28,6 +28,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util/version"
 )
 
@@ -102,24 +103,29 @@ func (m *Machine) validate(old *Machine) error {
 		)
 	}
 
-	if m.Spec.Bootstrap.ConfigRef != nil && m.Spec.Bootstrap.ConfigRef.Namespace != m.Namespace {
+	// NOTE: Referencing objects in a different namespace than the Machine is behind the MachineCrossNamespaceRefs
+	// feature gate flag; the web hook must prevent cross-namespace references in case the feature flag is disabled.
+	crossNamespaceRefsMessage := "must match metadata.namespace, unless the MachineCrossNamespaceRefs feature flag is enabled"
+	allowCrossNamespaceRefs := feature.Gates.Enabled(feature.MachineCrossNamespaceRefs)
+
+	if m.Spec.Bootstrap.ConfigRef != nil && m.Spec.Bootstrap.ConfigRef.Namespace != m.Namespace && !allowCrossNamespaceRefs {
 		allErrs = append(
 			allErrs,
 			field.Invalid(
 				specPath.Child("bootstrap", "configRef", "namespace"),
 				m.Spec.Bootstrap.ConfigRef.Namespace,
-				"must match metadata.namespace",
+				crossNamespaceRefsMessage,
 			),
 		)
 	}
 
-	if m.Spec.InfrastructureRef.Namespace != m.Namespace {
+	if m.Spec.InfrastructureRef.Namespace != m.Namespace && !allowCrossNamespaceRefs {
 		allErrs = append(
 			allErrs,
 			field.Invalid(
 				specPath.Child("infrastructureRef", "namespace"),
 				m.Spec.InfrastructureRef.Namespace,
-				"must match metadata.namespace",
+				crossNamespaceRefsMessage,
 			),
 		)
 	}
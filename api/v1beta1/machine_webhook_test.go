@@ -22,8 +22,10 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 
+	"sigs.k8s.io/cluster-api/feature"
 	utildefaulting "sigs.k8s.io/cluster-api/util/defaulting"
 )
 
@@ -91,11 +93,12 @@ func TestMachineBootstrapValidation(t *testing.T) {
 
 func TestMachineNamespaceValidation(t *testing.T) {
 	tests := []struct {
-		name      string
-		expectErr bool
-		bootstrap Bootstrap
-		infraRef  corev1.ObjectReference
-		namespace string
+		name                    string
+		expectErr               bool
+		bootstrap               Bootstrap
+		infraRef                corev1.ObjectReference
+		namespace               string
+		enableCrossNamespaceRef bool
 	}{
 		{
 			name:      "should succeed if all namespaces match",
@@ -125,10 +128,20 @@ func TestMachineNamespaceValidation(t *testing.T) {
 			bootstrap: Bootstrap{ConfigRef: &corev1.ObjectReference{Namespace: "foobar2"}},
 			infraRef:  corev1.ObjectReference{Namespace: "foobar3"},
 		},
+		{
+			name:                    "should succeed if no namespaces match and the MachineCrossNamespaceRefs feature flag is enabled",
+			expectErr:               false,
+			namespace:               "foobar1",
+			bootstrap:               Bootstrap{ConfigRef: &corev1.ObjectReference{Namespace: "foobar2"}},
+			infraRef:                corev1.ObjectReference{Namespace: "foobar3"},
+			enableCrossNamespaceRef: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineCrossNamespaceRefs, tt.enableCrossNamespaceRef)()
+
 			g := NewWithT(t)
 
 			m := &Machine{
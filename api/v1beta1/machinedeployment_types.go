@@ -25,6 +25,10 @@ const (
 	// MachineDeploymentTopologyFinalizer is the finalizer used by the topology MachineDeployment controller to
 	// clean up referenced template resources if necessary when a MachineDeployment is being deleted.
 	MachineDeploymentTopologyFinalizer = "machinedeployment.topology.cluster.x-k8s.io"
+
+	// MachineDeploymentOrphanMachinesFinalizer is the finalizer used by the MachineDeployment controller to
+	// orphan Machines when a MachineDeployment with the OrphanMachinesOnDeleteAnnotation is being deleted.
+	MachineDeploymentOrphanMachinesFinalizer = "machinedeployment.cluster.x-k8s.io/orphan-machines"
 )
 
 // MachineDeploymentStrategyType defines the type of MachineDeployment rollout strategies.
@@ -54,6 +58,10 @@ const (
 	// proportions in case the deployment has surge replicas.
 	MaxReplicasAnnotation = "machinedeployment.clusters.x-k8s.io/max-replicas"
 
+	// RolledBackAnnotation is set on a MachineSet when the machine deployment has been rolled back to a previous
+	// revision because the rollout of the MachineSet did not make progress within the progress deadline.
+	RolledBackAnnotation = "machinedeployment.clusters.x-k8s.io/rolled-back"
+
 	// MachineDeploymentUniqueLabel is the label applied to Machines
 	// in a MachineDeployment containing the hash of the template.
 	MachineDeploymentUniqueLabel = "machine-template-hash"
@@ -108,8 +116,16 @@ type MachineDeploymentSpec struct {
 	// process failed deployments and a condition with a ProgressDeadlineExceeded
 	// reason will be surfaced in the deployment status. Note that progress will
 	// not be estimated during the time a deployment is paused. Defaults to 600s.
+	// NOTE: The progress deadline is considered only when using the RollingUpdate strategy.
 	// +optional
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// AutoRollback, if true, instructs the deployment controller to roll back the deployment to the previous
+	// MachineSet revision when a rollout does not make progress within ProgressDeadlineSeconds.
+	// A MachineSet revision is never rolled back more than once; if the rollout does not make progress after
+	// the rollback, a condition with a ProgressDeadlineExceeded reason is surfaced in the deployment status.
+	// +optional
+	AutoRollback bool `json:"autoRollback,omitempty"`
 }
 
 // ANCHOR_END: MachineDeploymentSpec
@@ -69,6 +69,14 @@ type MachineHealthCheckSpec struct {
 	// a controller that lives outside of Cluster API.
 	// +optional
 	RemediationTemplate *corev1.ObjectReference `json:"remediationTemplate,omitempty"`
+
+	// PauseRemediationDuringRollout, if true, defers the remediation of unhealthy machines which are going
+	// to be replaced by a rollout of their control plane or MachineDeployment, or by the upgrade of their
+	// Cluster, so remediation does not race with the intentional replacement of machines.
+	// Machines created by the rollout, and machines still unhealthy when the rollout is completed, are
+	// remediated as usual.
+	// +optional
+	PauseRemediationDuringRollout *bool `json:"pauseRemediationDuringRollout,omitempty"`
 }
 
 // ANCHOR_END: MachineHealthCHeckSpec
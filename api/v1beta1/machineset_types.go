@@ -29,6 +29,10 @@ const (
 	// MachineSetTopologyFinalizer is the finalizer used by the topology MachineDeployment controller to
 	// clean up referenced template resources if necessary when a MachineSet is being deleted.
 	MachineSetTopologyFinalizer = "machineset.topology.cluster.x-k8s.io"
+
+	// MachineSetOrphanMachinesFinalizer is the finalizer used by the MachineSet controller to
+	// orphan Machines when a MachineSet with the OrphanMachinesOnDeleteAnnotation is being deleted.
+	MachineSetOrphanMachinesFinalizer = "machineset.cluster.x-k8s.io/orphan-machines"
 )
 
 // ANCHOR: MachineSetSpec
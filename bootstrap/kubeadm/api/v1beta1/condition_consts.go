@@ -56,6 +56,7 @@ const (
 	CertificatesGenerationFailedReason = "CertificatesGenerationFailed"
 
 	// CertificatesCorruptedReason (Severity=Error) documents a KubeadmConfig controller detecting
-	// an error while retrieving certificates for a joining node.
+	// an error while retrieving certificates for a joining node, or detecting invalid certificates, e.g.
+	// expired or not matching the corresponding key.
 	CertificatesCorruptedReason = "CertificatesCorrupted"
 )
@@ -38,6 +38,9 @@ const (
 	cloudConfigHeader              = `## template: jinja
 #cloud-config
 `
+	// kubeadmJoinErrorFile is the file where the retriable join script saves the output of the last failed
+	// kubeadm command, so it can be collected when troubleshooting join failures.
+	kubeadmJoinErrorFile = "/var/log/cluster-api/kubeadm-join-error.log"
 )
 
 // BaseUserData is shared across all the various types of files written to disk.
@@ -55,6 +58,7 @@ type BaseUserData struct {
 	UseExperimentalRetry bool
 	KubeadmCommand       string
 	KubeadmVerbosity     string
+	KubeadmJoinErrorFile string
 	SentinelFileCommand  string
 }
 
@@ -64,6 +68,7 @@ func (input *BaseUserData) prepare() error {
 	input.KubeadmCommand = fmt.Sprintf(standardJoinCommand, input.KubeadmVerbosity)
 	if input.UseExperimentalRetry {
 		input.KubeadmCommand = retriableJoinScriptName
+		input.KubeadmJoinErrorFile = kubeadmJoinErrorFile
 		joinScriptFile, err := generateBootstrapScript(input)
 		if err != nil {
 			return errors.Wrap(err, "failed to generate user data for machine joining control plane")
@@ -273,6 +273,7 @@ func TestNewJoinControlPlaneExperimentalRetry(t *testing.T) {
     owner: ` + retriableJoinScriptOwner + `
     permissions: '` + retriableJoinScriptPermissions + `'
     `,
+		`KUBEADM_JOIN_ERROR_FILE="` + kubeadmJoinErrorFile + `"`,
 	}
 	for _, f := range expectedFiles {
 		g.Expect(out).To(ContainSubstring(f))
@@ -13,6 +13,15 @@
 # See the License for the specific language governing permissions and
 # limitations under the License.
 
+# The number of attempts for each kubeadm join phase, and the exponential backoff between attempts in seconds.
+RETRY_ATTEMPTS=5
+RETRY_INITIAL_BACKOFF=10
+RETRY_MAX_BACKOFF=120
+
+# The file where the output of the last failed kubeadm command is saved.
+# shellcheck disable=SC1083
+KUBEADM_JOIN_ERROR_FILE="{{.KubeadmJoinErrorFile}}"
+
 # Log an error and exit.
 # Args:
 #   $1 Message to log with the error
@@ -30,7 +39,7 @@ log::error_exit() {
   # {{ end }}
   log::info "Resetting kubeadm"
   kubeadm reset -f || true
-  log::error "cluster.x-k8s.io kubeadm bootstrap script $0 exiting with status ${code}"
+  log::error "cluster.x-k8s.io kubeadm bootstrap script $0 exiting with status ${code}, the output of the last failed kubeadm command is saved in ${KUBEADM_JOIN_ERROR_FILE}"
   exit "${code}"
 }
 
@@ -82,13 +91,43 @@ check_kubeadm_command() {
   esac
 }
 
+# Save the output of a failed kubeadm command to a well-known file, so the last join error
+# can be collected when troubleshooting a machine which failed to join the cluster.
+# Args:
+#   $1 The kubeadm command
+#   $2 The file with the output of the kubeadm command
+save_kubeadm_error() {
+  local command="${1}"
+  local output="${2}"
+  mkdir -p "$(dirname "${KUBEADM_JOIN_ERROR_FILE}")"
+  {
+    echo "# [$(date --iso-8601=seconds)] ${command}"
+    cat "${output}"
+  } >"${KUBEADM_JOIN_ERROR_FILE}"
+}
+
+# Run a kubeadm command, saving its output if it fails.
+run-kubeadm-command() {
+  local output
+  local kubeadm_return
+  output=$(mktemp)
+  # shellcheck disable=SC1083
+  "$@" --config=/run/kubeadm/kubeadm-join-config.yaml {{.KubeadmVerbosity}} 2>&1 | tee "${output}"
+  kubeadm_return=${PIPESTATUS[0]}
+  if [ "${kubeadm_return}" -ne 0 ]; then
+    save_kubeadm_error "$*" "${output}"
+  fi
+  rm -f "${output}"
+  return "${kubeadm_return}"
+}
+
 function retry-command() {
   n=0
   local kubeadm_return
-  until [ $n -ge 5 ]; do
+  local backoff=${RETRY_INITIAL_BACKOFF}
+  until [ $n -ge ${RETRY_ATTEMPTS} ]; do
     log::info "running '$*'"
-    # shellcheck disable=SC1083
-    "$@" --config=/run/kubeadm/kubeadm-join-config.yaml {{.KubeadmVerbosity}}
+    run-kubeadm-command "$@"
     kubeadm_return=$?
     check_kubeadm_command "'$*'" "${kubeadm_return}"
     if [ ${kubeadm_return} -eq 0 ]; then
@@ -99,7 +138,16 @@ function retry-command() {
       break
     fi
     n=$((n + 1))
-    sleep 15
+    if [ $n -ge ${RETRY_ATTEMPTS} ]; then
+      break
+    fi
+    # Back off exponentially, so slow networks or control planes have time to settle before the next attempt.
+    log::info "retrying '$*' in ${backoff} seconds"
+    sleep "${backoff}"
+    backoff=$((backoff * 2))
+    if [ ${backoff} -gt ${RETRY_MAX_BACKOFF} ]; then
+      backoff=${RETRY_MAX_BACKOFF}
+    fi
   done
   if [ ${kubeadm_return} -ne 0 ]; then
     log::error_exit "too many errors, exiting" "${kubeadm_return}"
@@ -110,8 +158,7 @@ function retry-command() {
 function try-or-die-command() {
   local kubeadm_return
   log::info "running '$*'"
-  # shellcheck disable=SC1083
-  "$@" --config=/run/kubeadm/kubeadm-join-config.yaml {{.KubeadmVerbosity}}
+  run-kubeadm-command "$@"
   kubeadm_return=$?
   check_kubeadm_command "'$*'" "${kubeadm_return}"
   if [ ${kubeadm_return} -ne 0 ]; then
@@ -66,6 +66,9 @@ const (
 const (
 	// DefaultTokenTTL is the default TTL used for tokens.
 	DefaultTokenTTL = 15 * time.Minute
+
+	// kubeletRotateServerCertificatesArg is the kubelet arg used to enable the rotation of the kubelet serving certificate.
+	kubeletRotateServerCertificatesArg = "rotate-server-certificates"
 )
 
 // InitLocker is a lock that is used around kubeadm init.
@@ -311,11 +314,12 @@ func (r *KubeadmConfigReconciler) refreshBootstrapToken(ctx context.Context, con
 	}
 
 	log.Info("Refreshing token until the infrastructure has a chance to consume it")
-	if err := refreshToken(ctx, remoteClient, token, r.TokenTTL); err != nil {
+	tokenTTL := r.tokenTTL(cluster)
+	if err := refreshToken(ctx, remoteClient, token, tokenTTL); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to refresh bootstrap token")
 	}
 	return ctrl.Result{
-		RequeueAfter: r.TokenTTL / 2,
+		RequeueAfter: tokenTTL / 2,
 	}, nil
 }
 
@@ -328,13 +332,14 @@ func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Co
 	}
 
 	token := config.Spec.JoinConfiguration.Discovery.BootstrapToken.Token
-	shouldRotate, err := shouldRotate(ctx, remoteClient, token, r.TokenTTL)
+	tokenTTL := r.tokenTTL(cluster)
+	shouldRotate, err := shouldRotate(ctx, remoteClient, token, tokenTTL)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if shouldRotate {
 		log.Info("Creating new bootstrap token, the existing one should be rotated")
-		token, err := createToken(ctx, remoteClient, r.TokenTTL)
+		token, err := createToken(ctx, remoteClient, tokenTTL)
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to create new bootstrap token")
 		}
@@ -346,7 +351,7 @@ func (r *KubeadmConfigReconciler) rotateMachinePoolBootstrapToken(ctx context.Co
 		return r.joinWorker(ctx, scope)
 	}
 	return ctrl.Result{
-		RequeueAfter: r.TokenTTL / 3,
+		RequeueAfter: tokenTTL / 3,
 	}, nil
 }
 
@@ -412,7 +417,12 @@ func (r *KubeadmConfigReconciler) handleClusterNotInitialized(ctx context.Contex
 			},
 		}
 	}
-	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(scope.Config.Spec.InitConfiguration, parsedVersion)
+	// injects into the kubelet configuration the settings defined at cluster level; the settings are applied only
+	// to the generated bootstrap data, so the KubeadmConfig spec is not altered.
+	initConfiguration := scope.Config.Spec.InitConfiguration.DeepCopy()
+	reconcileKubeletSettings(ctx, scope.Cluster, &initConfiguration.NodeRegistration)
+
+	initdata, err := kubeadmtypes.MarshalInitConfigurationForVersion(initConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal init configuration")
 		return ctrl.Result{}, err
@@ -524,6 +534,10 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
 		return ctrl.Result{}, err
 	}
+	if err := certificates.Validate(); err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, err
+	}
 	conditions.MarkTrue(scope.Config, bootstrapv1.CertificatesAvailableCondition)
 
 	// Ensure that joinConfiguration.Discovery is properly set for joining node on the current cluster.
@@ -533,13 +547,18 @@ func (r *KubeadmConfigReconciler) joinWorker(ctx context.Context, scope *Scope)
 		return res, nil
 	}
 
+	// Ensure that the kubelet configuration includes the settings defined at cluster level; the settings are applied
+	// only to the generated bootstrap data, so the KubeadmConfig spec is not altered.
+	joinConfiguration := scope.Config.Spec.JoinConfiguration.DeepCopy()
+	reconcileKubeletSettings(ctx, scope.Cluster, &joinConfiguration.NodeRegistration)
+
 	kubernetesVersion := scope.ConfigOwner.KubernetesVersion()
 	parsedVersion, err := semver.ParseTolerant(kubernetesVersion)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", kubernetesVersion)
 	}
 
-	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(scope.Config.Spec.JoinConfiguration, parsedVersion)
+	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
 		return ctrl.Result{}, err
@@ -629,6 +648,10 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
 		return ctrl.Result{}, err
 	}
+	if err := certificates.Validate(); err != nil {
+		conditions.MarkFalse(scope.Config, bootstrapv1.CertificatesAvailableCondition, bootstrapv1.CertificatesCorruptedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, err
+	}
 
 	conditions.MarkTrue(scope.Config, bootstrapv1.CertificatesAvailableCondition)
 
@@ -639,13 +662,18 @@ func (r *KubeadmConfigReconciler) joinControlplane(ctx context.Context, scope *S
 		return res, nil
 	}
 
+	// Ensure that the kubelet configuration includes the settings defined at cluster level; the settings are applied
+	// only to the generated bootstrap data, so the KubeadmConfig spec is not altered.
+	joinConfiguration := scope.Config.Spec.JoinConfiguration.DeepCopy()
+	reconcileKubeletSettings(ctx, scope.Cluster, &joinConfiguration.NodeRegistration)
+
 	kubernetesVersion := scope.ConfigOwner.KubernetesVersion()
 	parsedVersion, err := semver.ParseTolerant(kubernetesVersion)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", kubernetesVersion)
 	}
 
-	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(scope.Config.Spec.JoinConfiguration, parsedVersion)
+	joinData, err := kubeadmtypes.MarshalJoinConfigurationForVersion(joinConfiguration, parsedVersion)
 	if err != nil {
 		scope.Error(err, "Failed to marshal join configuration")
 		return ctrl.Result{}, err
@@ -913,7 +941,7 @@ func (r *KubeadmConfigReconciler) reconcileDiscovery(ctx context.Context, cluste
 			return ctrl.Result{}, err
 		}
 
-		token, err := createToken(ctx, remoteClient, r.TokenTTL)
+		token, err := createToken(ctx, remoteClient, r.tokenTTL(cluster))
 		if err != nil {
 			return ctrl.Result{}, errors.Wrapf(err, "failed to create new bootstrap token")
 		}
@@ -977,6 +1005,37 @@ func (r *KubeadmConfigReconciler) reconcileTopLevelObjectSettings(ctx context.Co
 	}
 }
 
+// tokenTTL returns the TTL of the bootstrap tokens for the machines of a cluster, which can be defined using the
+// BootstrapTokenTTLAnnotation on the cluster; otherwise the TTL defined for the controller is used.
+func (r *KubeadmConfigReconciler) tokenTTL(cluster *clusterv1.Cluster) time.Duration {
+	if value, ok := cluster.Annotations[clusterv1.BootstrapTokenTTLAnnotation]; ok {
+		// NOTE: Invalid values are rejected by the Cluster webhook; ignore them in case the webhook is bypassed.
+		if ttl, err := time.ParseDuration(value); err == nil && ttl > 0 {
+			return ttl
+		}
+	}
+	return r.TokenTTL
+}
+
+// reconcileKubeletSettings injects into the kubelet extra args of a node the settings defined at cluster level
+// using annotations, e.g. KubeletRotateServerCertificatesAnnotation.
+// User provided kubelet extra args are respected, which take precedence over cluster level settings.
+// NOTE: This func must be called on a copy of the KubeadmConfig spec used to generate the bootstrap data, because
+// altering the spec would make the KubeadmConfig differ from the one in the KubeadmControlPlane, thus triggering rollouts.
+func reconcileKubeletSettings(ctx context.Context, cluster *clusterv1.Cluster, nodeRegistration *bootstrapv1.NodeRegistrationOptions) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if value, ok := cluster.Annotations[clusterv1.KubeletRotateServerCertificatesAnnotation]; ok {
+		if _, ok := nodeRegistration.KubeletExtraArgs[kubeletRotateServerCertificatesArg]; !ok {
+			if nodeRegistration.KubeletExtraArgs == nil {
+				nodeRegistration.KubeletExtraArgs = map[string]string{}
+			}
+			nodeRegistration.KubeletExtraArgs[kubeletRotateServerCertificatesArg] = value
+			log.V(3).Info("Altering NodeRegistration.KubeletExtraArgs", kubeletRotateServerCertificatesArg, value)
+		}
+	}
+}
+
 // storeBootstrapData creates a new secret with the data passed in as input,
 // sets the reference in the configuration status and ready to true.
 func (r *KubeadmConfigReconciler) storeBootstrapData(ctx context.Context, scope *Scope, data []byte) error {
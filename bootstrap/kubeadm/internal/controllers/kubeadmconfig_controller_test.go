@@ -26,6 +26,7 @@ import (
 
 	ignition "github.com/flatcar/ignition/config/v2_3"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -595,6 +596,51 @@ func TestReconcileIfJoinNodesAndControlPlaneIsReady(t *testing.T) {
 	}
 }
 
+func TestReconcileKubeletSettingsAreAppliedToBootstrapDataOnly(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster").Build()
+	cluster.Status.InfrastructureReady = true
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	cluster.Spec.ControlPlaneEndpoint = clusterv1.APIEndpoint{Host: "100.105.150.1", Port: 6443}
+	cluster.Annotations = map[string]string{clusterv1.KubeletRotateServerCertificatesAnnotation: "true"}
+
+	machine := newWorkerMachineForCluster(cluster)
+	config := newWorkerJoinKubeadmConfig(machine.Namespace, "worker-join-cfg")
+	addKubeadmConfigToMachine(config, machine)
+
+	objects := []client.Object{
+		cluster,
+		machine,
+		config,
+	}
+	objects = append(objects, createSecrets(t, cluster, config)...)
+	myclient := fake.NewClientBuilder().WithObjects(objects...).Build()
+	k := &KubeadmConfigReconciler{
+		Client:             myclient,
+		KubeadmInitLock:    &myInitLocker{},
+		remoteClientGetter: fakeremote.NewClusterClient,
+	}
+
+	request := ctrl.Request{
+		NamespacedName: client.ObjectKey{
+			Namespace: config.GetNamespace(),
+			Name:      config.GetName(),
+		},
+	}
+	_, err := k.Reconcile(ctx, request)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := getKubeadmConfig(myclient, config.GetName(), metav1.NamespaceDefault)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Status.DataSecretName).NotTo(BeNil())
+	g.Expect(cfg.Spec.JoinConfiguration.NodeRegistration.KubeletExtraArgs).ToNot(HaveKey("rotate-server-certificates"))
+
+	s := &corev1.Secret{}
+	g.Expect(myclient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: *cfg.Status.DataSecretName}, s)).To(Succeed())
+	g.Expect(string(s.Data["value"])).To(ContainSubstring("rotate-server-certificates: \"true\""))
+}
+
 func TestReconcileIfJoinNodePoolsAndControlPlaneIsReady(t *testing.T) {
 	_ = feature.MutableGates.Set("MachinePool=true")
 
@@ -1530,6 +1576,44 @@ func TestKubeadmConfigReconciler_Reconcile_DynamicDefaultsForClusterConfiguratio
 	}
 }
 
+// Set bootstrap token and kubelet settings based on the annotations of the cluster object.
+func TestKubeadmConfigReconciler_ClusterLevelBootstrapSettings(t *testing.T) {
+	t.Run("Bootstrap token TTL", func(t *testing.T) {
+		g := NewWithT(t)
+
+		k := &KubeadmConfigReconciler{TokenTTL: DefaultTokenTTL}
+		cluster := builder.Cluster(metav1.NamespaceDefault, "mycluster").Build()
+		g.Expect(k.tokenTTL(cluster)).To(Equal(DefaultTokenTTL))
+
+		cluster.Annotations = map[string]string{clusterv1.BootstrapTokenTTLAnnotation: "1h"}
+		g.Expect(k.tokenTTL(cluster)).To(Equal(time.Hour))
+
+		// Invalid values are ignored.
+		cluster.Annotations = map[string]string{clusterv1.BootstrapTokenTTLAnnotation: "foo"}
+		g.Expect(k.tokenTTL(cluster)).To(Equal(DefaultTokenTTL))
+	})
+
+	t.Run("Kubelet rotate server certificates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := builder.Cluster(metav1.NamespaceDefault, "mycluster").Build()
+		nodeRegistration := &bootstrapv1.NodeRegistrationOptions{}
+		reconcileKubeletSettings(ctx, cluster, nodeRegistration)
+		g.Expect(nodeRegistration.KubeletExtraArgs).To(BeEmpty())
+
+		cluster.Annotations = map[string]string{clusterv1.KubeletRotateServerCertificatesAnnotation: "true"}
+		reconcileKubeletSettings(ctx, cluster, nodeRegistration)
+		g.Expect(nodeRegistration.KubeletExtraArgs).To(HaveKeyWithValue("rotate-server-certificates", "true"))
+
+		// Config settings have precedence.
+		nodeRegistration = &bootstrapv1.NodeRegistrationOptions{
+			KubeletExtraArgs: map[string]string{"rotate-server-certificates": "false"},
+		}
+		reconcileKubeletSettings(ctx, cluster, nodeRegistration)
+		g.Expect(nodeRegistration.KubeletExtraArgs).To(HaveKeyWithValue("rotate-server-certificates", "false"))
+	})
+}
+
 // Allow users to skip CA Verification if they *really* want to.
 func TestKubeadmConfigReconciler_Reconcile_AlwaysCheckCAVerificationUnlessRequestedToSkip(t *testing.T) {
 	// Setup work for an initialized cluster
@@ -1678,6 +1762,41 @@ func TestKubeadmConfigReconciler_Reconcile_DoesNotFailIfCASecretsAlreadyExist(t
 	m := newControlPlaneMachine(cluster, "control-plane-machine")
 	configName := "my-config"
 	c := newControlPlaneInitKubeadmConfig(m.Namespace, configName)
+	addKubeadmConfigToMachine(c, m)
+	etcdCA := &secret.Certificate{Purpose: secret.EtcdCA}
+	g.Expect(etcdCA.Generate()).To(Succeed())
+	scrt := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", cluster.Name, secret.EtcdCA),
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"tls.crt": etcdCA.KeyPair.Cert,
+			"tls.key": etcdCA.KeyPair.Key,
+		},
+	}
+	fakec := fake.NewClientBuilder().WithObjects(cluster, m, c, scrt).Build()
+	reconciler := &KubeadmConfigReconciler{
+		Client:          fakec,
+		KubeadmInitLock: &myInitLocker{},
+	}
+	req := ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: configName},
+	}
+	_, err := reconciler.Reconcile(ctx, req)
+	g.Expect(err).NotTo(HaveOccurred())
+}
+
+// Reconcile should fail if a CA Secret provided by the user is not valid.
+func TestKubeadmConfigReconciler_Reconcile_FailsIfCASecretIsInvalid(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "my-cluster").Build()
+	cluster.Status.InfrastructureReady = true
+	m := newControlPlaneMachine(cluster, "control-plane-machine")
+	configName := "my-config"
+	c := newControlPlaneInitKubeadmConfig(m.Namespace, configName)
+	addKubeadmConfigToMachine(c, m)
 	scrt := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-%s", cluster.Name, secret.EtcdCA),
@@ -1697,7 +1816,12 @@ func TestKubeadmConfigReconciler_Reconcile_DoesNotFailIfCASecretsAlreadyExist(t
 		NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: configName},
 	}
 	_, err := reconciler.Reconcile(ctx, req)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(errors.Is(err, secret.ErrInvalidCertificate)).To(BeTrue())
+
+	cfg, err := getKubeadmConfig(fakec, configName, metav1.NamespaceDefault)
 	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(conditions.IsFalse(cfg, bootstrapv1.CertificatesAvailableCondition)).To(BeTrue())
 }
 
 // Exactly one control plane machine initializes if there are multiple control plane machines defined.
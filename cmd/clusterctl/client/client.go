@@ -98,6 +98,16 @@ type AlphaClient interface {
 	RolloutUndo(options RolloutOptions) error
 	// TopologyPlan dry runs the topology reconciler
 	TopologyPlan(options TopologyPlanOptions) (*TopologyPlanOutput, error)
+	// TopologyOwnership reports the field ownership of the objects owned by managed topologies
+	TopologyOwnership(options TopologyOwnershipOptions) (*TopologyOwnershipOutput, error)
+	// TopologyAdopt adopts an existing Cluster under the management of a ClusterClass
+	TopologyAdopt(options TopologyAdoptOptions) (*TopologyAdoptOutput, error)
+	// TopologyExport exports a ClusterClass and all the templates it references as a self-contained bundle
+	TopologyExport(options TopologyExportOptions) (*TopologyExportOutput, error)
+	// TopologyImport imports a bundle generated by TopologyExport
+	TopologyImport(options TopologyImportOptions) (*TopologyImportOutput, error)
+	// SupportBundle collects the information required to troubleshoot a Cluster into a redacted archive
+	SupportBundle(options SupportBundleOptions) error
 }
 
 // YamlPrinter exposes methods that prints the processed template and
@@ -156,6 +156,26 @@ func (f fakeClient) TopologyPlan(options TopologyPlanOptions) (*cluster.Topology
 	return f.internalClient.TopologyPlan(options)
 }
 
+func (f fakeClient) TopologyOwnership(options TopologyOwnershipOptions) (*cluster.TopologyOwnershipOutput, error) {
+	return f.internalClient.TopologyOwnership(options)
+}
+
+func (f fakeClient) TopologyAdopt(options TopologyAdoptOptions) (*cluster.TopologyAdoptOutput, error) {
+	return f.internalClient.TopologyAdopt(options)
+}
+
+func (f fakeClient) TopologyExport(options TopologyExportOptions) (*cluster.TopologyExportOutput, error) {
+	return f.internalClient.TopologyExport(options)
+}
+
+func (f fakeClient) TopologyImport(options TopologyImportOptions) (*cluster.TopologyImportOutput, error) {
+	return f.internalClient.TopologyImport(options)
+}
+
+func (f fakeClient) SupportBundle(options SupportBundleOptions) error {
+	return f.internalClient.SupportBundle(options)
+}
+
 // newFakeClient returns a clusterctl client that allows to execute tests on a set of fake config, fake repositories and fake clusters.
 // you can use WithCluster and WithRepository to prepare for the test case.
 func newFakeClient(configClient config.Client) *fakeClient {
@@ -330,6 +350,10 @@ func (f *fakeClusterClient) Topology() cluster.TopologyClient {
 	return f.internalclient.Topology()
 }
 
+func (f *fakeClusterClient) SupportBundle() cluster.SupportBundleClient {
+	return f.internalclient.SupportBundle()
+}
+
 func (f *fakeClusterClient) WithObjs(objs ...client.Object) *fakeClusterClient {
 	f.fakeProxy.WithObjs(objs...)
 	return f
@@ -392,6 +416,10 @@ func (f fakeConfigClient) ImageMeta() config.ImageMetaClient {
 	return f.internalclient.ImageMeta()
 }
 
+func (f fakeConfigClient) DeploymentMeta() config.DeploymentMetaClient {
+	return f.internalclient.DeploymentMeta()
+}
+
 func (f *fakeConfigClient) WithVar(key, value string) *fakeConfigClient {
 	f.fakeReader.WithVar(key, value)
 	return f
@@ -86,6 +86,9 @@ type Client interface {
 
 	// Topology returns a TopologyClient that can be used for performing dry run executions of the topology reconciler.
 	Topology() TopologyClient
+
+	// SupportBundle returns a SupportBundleClient that can be used for collecting the information required to troubleshoot a Cluster.
+	SupportBundle() SupportBundleClient
 }
 
 // PollImmediateWaiter tries a condition func until it returns true, an error, or the timeout is reached.
@@ -151,6 +154,10 @@ func (c *clusterClient) Topology() TopologyClient {
 	return newTopologyClient(c.proxy, c.ProviderInventory())
 }
 
+func (c *clusterClient) SupportBundle() SupportBundleClient {
+	return newSupportBundleClient(c.proxy, c.ProviderInventory(), c.WorkloadCluster())
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*clusterClient)
 
@@ -27,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -36,16 +37,21 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/yaml"
 )
 
+// ResourceMutatorFunc holds the type for mutators to be applied on resources during a move operation.
+type ResourceMutatorFunc func(u *unstructured.Unstructured) error
+
 // ObjectMover defines methods for moving Cluster API objects to another management cluster.
 type ObjectMover interface {
 	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-	Move(namespace string, toCluster Client, dryRun bool) error
+	// Mutators, if any, are applied to all the objects before creating them in the target management cluster.
+	Move(namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error
 
 	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target directory.
 	ToDirectory(namespace string, directory string) error
@@ -69,15 +75,17 @@ type objectMover struct {
 	fromProxy             Proxy
 	fromProviderInventory InventoryClient
 	dryRun                bool
+	mutators              []ResourceMutatorFunc
 }
 
 // ensure objectMover implements the ObjectMover interface.
 var _ ObjectMover = &objectMover{}
 
-func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool) error {
+func (o *objectMover) Move(namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 	log.Info("Performing move...")
 	o.dryRun = dryRun
+	o.mutators = mutators
 	if o.dryRun {
 		log.Info("********************************************************")
 		log.Info("This is a dry-run move, will not perform any real action")
@@ -382,13 +390,13 @@ func (o *objectMover) move(graph *objectGraph, toProxy Proxy) error {
 
 	// Resume the ClusterClasses in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target ClusterClasses")
-	if err := setClusterClassPause(toProxy, clusterClasses, false, o.dryRun); err != nil {
+	if err := setClusterClassPause(toProxy, clusterClasses, false, o.dryRun, o.mutators...); err != nil {
 		return errors.Wrap(err, "error resuming ClusterClasses")
 	}
 
 	// Reset the pause field on the Cluster object in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target cluster")
-	return setClusterPause(toProxy, clusters, false, o.dryRun)
+	return setClusterPause(toProxy, clusters, false, o.dryRun, o.mutators...)
 }
 
 func (o *objectMover) toDirectory(graph *objectGraph, directory string) error {
@@ -555,7 +563,8 @@ func getMoveSequence(graph *objectGraph) *moveSequence {
 }
 
 // setClusterPause sets the paused field on nodes referring to Cluster objects.
-func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool) error {
+// Mutators, if any, are applied to the identity of the nodes to get the Cluster objects to patch.
+func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool, mutators ...ResourceMutatorFunc) error {
 	if dryRun {
 		return nil
 	}
@@ -576,7 +585,7 @@ func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool) err
 
 		// Nb. The operation is wrapped in a retry loop to make setClusterPause more resilient to unexpected conditions.
 		if err := retryWithExponentialBackoff(setClusterPauseBackoff, func() error {
-			return patchCluster(proxy, cluster, patch)
+			return patchCluster(proxy, cluster, patch, mutators...)
 		}); err != nil {
 			return errors.Wrapf(err, "error setting Cluster.Spec.Paused=%t", value)
 		}
@@ -585,7 +594,8 @@ func setClusterPause(proxy Proxy, clusters []*node, value bool, dryRun bool) err
 }
 
 // setClusterClassPause sets the paused annotation on nodes referring to ClusterClass objects.
-func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRun bool) error {
+// Mutators, if any, are applied to the identity of the nodes to get the ClusterClass objects to patch.
+func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRun bool, mutators ...ResourceMutatorFunc) error {
 	if dryRun {
 		return nil
 	}
@@ -603,7 +613,7 @@ func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRu
 
 		// Nb. The operation is wrapped in a retry loop to make setClusterClassPause more resilient to unexpected conditions.
 		if err := retryWithExponentialBackoff(setClusterClassPauseBackoff, func() error {
-			return pauseClusterClass(proxy, clusterclass, pause)
+			return pauseClusterClass(proxy, clusterclass, pause, mutators...)
 		}); err != nil {
 			return errors.Wrapf(err, "error updating ClusterClass %s/%s", clusterclass.identity.Namespace, clusterclass.identity.Name)
 		}
@@ -612,16 +622,16 @@ func setClusterClassPause(proxy Proxy, clusterclasses []*node, pause bool, dryRu
 }
 
 // patchCluster applies a patch to a node referring to a Cluster object.
-func patchCluster(proxy Proxy, cluster *node, patch client.Patch) error {
+func patchCluster(proxy Proxy, cluster *node, patch client.Patch, mutators ...ResourceMutatorFunc) error {
 	cFrom, err := proxy.NewClient()
 	if err != nil {
 		return err
 	}
 
 	clusterObj := &clusterv1.Cluster{}
-	clusterObjKey := client.ObjectKey{
-		Namespace: cluster.identity.Namespace,
-		Name:      cluster.identity.Name,
+	clusterObjKey, err := getTargetObjectKey(cluster, mutators...)
+	if err != nil {
+		return err
 	}
 
 	if err := cFrom.Get(ctx, clusterObjKey, clusterObj); err != nil {
@@ -637,7 +647,7 @@ func patchCluster(proxy Proxy, cluster *node, patch client.Patch) error {
 	return nil
 }
 
-func pauseClusterClass(proxy Proxy, n *node, pause bool) error {
+func pauseClusterClass(proxy Proxy, n *node, pause bool, mutators ...ResourceMutatorFunc) error {
 	cFrom, err := proxy.NewClient()
 	if err != nil {
 		return errors.Wrap(err, "error creating client")
@@ -645,17 +655,17 @@ func pauseClusterClass(proxy Proxy, n *node, pause bool) error {
 
 	// Get the ClusterClass from the server
 	clusterClass := &clusterv1.ClusterClass{}
-	clusterClassObjKey := client.ObjectKey{
-		Name:      n.identity.Name,
-		Namespace: n.identity.Namespace,
+	clusterClassObjKey, err := getTargetObjectKey(n, mutators...)
+	if err != nil {
+		return err
 	}
 	if err := cFrom.Get(ctx, clusterClassObjKey, clusterClass); err != nil {
-		return errors.Wrapf(err, "error reading ClusterClass %s/%s", n.identity.Namespace, n.identity.Name)
+		return errors.Wrapf(err, "error reading ClusterClass %s/%s", clusterClassObjKey.Namespace, clusterClassObjKey.Name)
 	}
 
 	patchHelper, err := patch.NewHelper(clusterClass, cFrom)
 	if err != nil {
-		return errors.Wrapf(err, "error creating patcher for ClusterClass %s/%s", n.identity.Namespace, n.identity.Name)
+		return errors.Wrapf(err, "error creating patcher for ClusterClass %s/%s", clusterClassObjKey.Namespace, clusterClassObjKey.Name)
 	}
 
 	// Update the annotation to the desired state
@@ -678,9 +688,34 @@ func pauseClusterClass(proxy Proxy, n *node, pause bool) error {
 
 	// Update the cluster class with the new annotations.
 	if err := patchHelper.Patch(ctx, clusterClass); err != nil {
-		return errors.Wrapf(err, "error patching ClusterClass %s/%s", n.identity.Namespace, n.identity.Name)
+		return errors.Wrapf(err, "error patching ClusterClass %s/%s", clusterClassObjKey.Namespace, clusterClassObjKey.Name)
+	}
+
+	return nil
+}
+
+// getTargetObjectKey returns the key of the object corresponding to a node once the mutators, if any, are applied.
+func getTargetObjectKey(n *node, mutators ...ResourceMutatorFunc) (client.ObjectKey, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(n.identity.APIVersion)
+	obj.SetKind(n.identity.Kind)
+	obj.SetNamespace(n.identity.Namespace)
+	obj.SetName(n.identity.Name)
+
+	if err := applyMutators(obj, mutators...); err != nil {
+		return client.ObjectKey{}, err
 	}
+	return client.ObjectKeyFromObject(obj), nil
+}
 
+// applyMutators applies mutators to an object.
+func applyMutators(obj *unstructured.Unstructured, mutators ...ResourceMutatorFunc) error {
+	for _, mutator := range mutators {
+		if err := mutator(obj); err != nil {
+			return errors.Wrapf(err, "error applying resource mutator to %q %s/%s",
+				obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+		}
+	}
 	return nil
 }
 
@@ -698,7 +733,11 @@ func (o *objectMover) ensureNamespaces(graph *objectGraph, toProxy Proxy) error
 			continue
 		}
 
-		namespace := node.identity.Namespace
+		key, err := getTargetObjectKey(node, o.mutators...)
+		if err != nil {
+			return err
+		}
+		namespace := key.Namespace
 
 		// If the namespace was already processed, skip it.
 		if namespaces.Has(namespace) {
@@ -883,6 +922,13 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) erro
 	// Rebuild the owne reference chain
 	o.buildOwnerChain(obj, nodeToCreate)
 
+	// Applies the mutators, if any, to all the objects but the global ones.
+	if !nodeToCreate.isGlobal {
+		if err := applyMutators(obj, o.mutators...); err != nil {
+			return err
+		}
+	}
+
 	// FIXME Workaround for https://github.com/kubernetes/kubernetes/issues/32220. Remove when the issue is fixed.
 	// If the resource already exists, the API server ordinarily returns an AlreadyExists error. Due to the above issue, if the resource has a non-empty metadata.generateName field, the API server returns a ServerTimeoutError. To ensure that the API server returns an AlreadyExists error, we set the metadata.generateName field to an empty string.
 	if len(obj.GetName()) > 0 && len(obj.GetGenerateName()) > 0 {
@@ -913,7 +959,7 @@ func (o *objectMover) createTargetObject(nodeToCreate *node, toProxy Proxy) erro
 			existingTargetObj := &unstructured.Unstructured{}
 			existingTargetObj.SetAPIVersion(obj.GetAPIVersion())
 			existingTargetObj.SetKind(obj.GetKind())
-			if err := cTo.Get(ctx, objKey, existingTargetObj); err != nil {
+			if err := cTo.Get(ctx, client.ObjectKeyFromObject(obj), existingTargetObj); err != nil {
 				return errors.Wrapf(err, "error reading resource for %q %s/%s",
 					existingTargetObj.GroupVersionKind(), existingTargetObj.GetNamespace(), existingTargetObj.GetName())
 			}
@@ -1215,3 +1261,83 @@ func patchTopologyManagedFields(ctx context.Context, oldManagedFields []metav1.M
 	}
 	return nil
 }
+
+// NewNamespaceMutator returns a ResourceMutatorFunc moving objects from a namespace to another one.
+// Besides the namespace of the object, the mutator rewrites the namespace of the object references defined by the
+// Cluster API types and by the ControlPlane contract, e.g. spec.infrastructureRef or spec.template.spec.bootstrap.configRef,
+// pointing to the source namespace.
+// NOTE: Object references defined by providers outside of the Cluster API contracts, e.g. identityRef, are not rewritten.
+func NewNamespaceMutator(fromNamespace, toNamespace string) ResourceMutatorFunc {
+	return func(u *unstructured.Unstructured) error {
+		if u.GetNamespace() != fromNamespace {
+			return nil
+		}
+		u.SetNamespace(toNamespace)
+
+		for _, p := range namespacedRefPaths(u.GroupVersionKind().GroupKind()) {
+			visitPath(u.Object, p, func(fields map[string]interface{}, field string) {
+				ref, ok := fields[field].(map[string]interface{})
+				if !ok {
+					return
+				}
+				if namespace, ok := ref["namespace"].(string); ok && namespace == fromNamespace {
+					ref["namespace"] = toNamespace
+				}
+			})
+		}
+		return nil
+	}
+}
+
+// namespacedRefPaths returns the paths of the object references of the given kind which must be moved together
+// with the object; paths traverse lists, e.g. spec.workers.machineDeployments.template.bootstrap.ref identifies
+// the bootstrap template references of all the MachineDeployment classes.
+func namespacedRefPaths(gk schema.GroupKind) []contract.Path {
+	if gk.Group == controlPlaneGroup {
+		return []contract.Path{
+			contract.ControlPlane().MachineTemplate().InfrastructureRef().Path(),
+			contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().RefPath(),
+		}
+	}
+	return clusterAPIRefPaths[gk]
+}
+
+// controlPlaneGroup is the API group of the objects implementing the ControlPlane contract.
+const controlPlaneGroup = "controlplane.cluster.x-k8s.io"
+
+var (
+	// clusterAPIRefPaths are the paths of the object references of the Cluster API types, by kind.
+	clusterAPIRefPaths = map[schema.GroupKind][]contract.Path{
+		clusterv1.GroupVersion.WithKind("Cluster").GroupKind(): {
+			{"spec", "infrastructureRef"},
+			{"spec", "controlPlaneRef"},
+		},
+		clusterv1.GroupVersion.WithKind("Machine").GroupKind(): {
+			{"spec", "infrastructureRef"},
+			{"spec", "bootstrap", "configRef"},
+		},
+		clusterv1.GroupVersion.WithKind("MachineSet").GroupKind():         machineTemplateRefPaths,
+		clusterv1.GroupVersion.WithKind("MachineDeployment").GroupKind():  machineTemplateRefPaths,
+		clusterv1.GroupVersion.WithKind("MachinePool").GroupKind():        machineTemplateRefPaths,
+		clusterv1.GroupVersion.WithKind("MachineHealthCheck").GroupKind(): {{"spec", "remediationTemplate"}},
+		clusterv1.GroupVersion.WithKind("ClusterClass").GroupKind(): {
+			{"spec", "infrastructure", "ref"},
+			{"spec", "controlPlane", "ref"},
+			{"spec", "controlPlane", "machineInfrastructure", "ref"},
+			{"spec", "controlPlane", "failureDomainMachineInfrastructure", "ref"},
+			{"spec", "controlPlane", "machineHealthCheck", "remediationTemplate"},
+			{"spec", "workers", "machineDeployments", "template", "bootstrap", "ref"},
+			{"spec", "workers", "machineDeployments", "template", "infrastructure", "ref"},
+			{"spec", "workers", "machineDeployments", "machineHealthCheck", "remediationTemplate"},
+			{"spec", "workers", "machinePools", "template", "bootstrap", "ref"},
+			{"spec", "workers", "machinePools", "template", "infrastructure", "ref"},
+		},
+	}
+
+	// machineTemplateRefPaths are the paths of the object references of the Machine template of MachineSets,
+	// MachineDeployments and MachinePools.
+	machineTemplateRefPaths = []contract.Path{
+		{"spec", "template", "spec", "infrastructureRef"},
+		{"spec", "template", "spec", "bootstrap", "configRef"},
+	}
+)
@@ -1235,6 +1235,275 @@ func Test_objectMover_move(t *testing.T) {
 	}
 }
 
+func Test_objectMover_move_withNamespaceMutator(t *testing.T) {
+	// NB. we are testing the move using the same set of moveTests, but moving all the objects in ns1 to ns3.
+	for _, tt := range moveTests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+
+			// Get all the types to be considered for discovery
+			g.Expect(getFakeDiscoveryTypes(graph)).To(Succeed())
+
+			// trigger discovery the content of the source cluster
+			g.Expect(graph.Discovery("")).To(Succeed())
+
+			// gets a fakeProxy to an empty cluster with all the required CRDs
+			toProxy := getFakeProxyWithCRDs()
+
+			// Run move with a mutator moving objects from ns1 to ns3
+			mover := objectMover{
+				fromProxy: graph.proxy,
+				mutators:  []ResourceMutatorFunc{NewNamespaceMutator("ns1", "ns3")},
+			}
+
+			err := mover.move(graph, toProxy)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+
+			csTo, err := toProxy.NewClient()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			for _, node := range graph.uidToNode {
+				key := client.ObjectKey{
+					Namespace: node.identity.Namespace,
+					Name:      node.identity.Name,
+				}
+				if key.Namespace == "ns1" {
+					key.Namespace = "ns3"
+				}
+
+				// objects are created in the target namespace of the target cluster
+				oTo := &unstructured.Unstructured{}
+				oTo.SetAPIVersion(node.identity.APIVersion)
+				oTo.SetKind(node.identity.Kind)
+
+				if err := csTo.Get(ctx, key, oTo); err != nil {
+					t.Errorf("error = %v when checking for %v created in target cluster", err, key)
+					continue
+				}
+
+				// clusters are resumed in the target namespace of the target cluster
+				if node.identity.GroupVersionKind().GroupKind() == clusterv1.GroupVersion.WithKind("Cluster").GroupKind() {
+					paused, _, err := unstructured.NestedBool(oTo.Object, "spec", "paused")
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(paused).To(BeFalse())
+				}
+			}
+		})
+	}
+}
+
+func Test_NewNamespaceMutator(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "moves the object and the references pointing to the source namespace",
+			obj: map[string]interface{}{
+				"apiVersion": clusterv1.GroupVersion.String(),
+				"kind":       "Cluster",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns1",
+				},
+				"spec": map[string]interface{}{
+					"infrastructureRef": map[string]interface{}{
+						"kind":      "InfrastructureCluster",
+						"name":      "foo",
+						"namespace": "ns1",
+					},
+					"controlPlaneRef": map[string]interface{}{
+						"kind":      "ControlPlane",
+						"name":      "foo",
+						"namespace": "ns2",
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": clusterv1.GroupVersion.String(),
+				"kind":       "Cluster",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns3",
+				},
+				"spec": map[string]interface{}{
+					"infrastructureRef": map[string]interface{}{
+						"kind":      "InfrastructureCluster",
+						"name":      "foo",
+						"namespace": "ns3",
+					},
+					// References to other namespaces are left untouched.
+					"controlPlaneRef": map[string]interface{}{
+						"kind":      "ControlPlane",
+						"name":      "foo",
+						"namespace": "ns2",
+					},
+				},
+			},
+		},
+		{
+			name: "moves the references in lists",
+			obj: map[string]interface{}{
+				"apiVersion": clusterv1.GroupVersion.String(),
+				"kind":       "ClusterClass",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns1",
+				},
+				"spec": map[string]interface{}{
+					"workers": map[string]interface{}{
+						"machineDeployments": []interface{}{
+							map[string]interface{}{
+								"template": map[string]interface{}{
+									"bootstrap": map[string]interface{}{
+										"ref": map[string]interface{}{"name": "bar", "namespace": "ns1"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": clusterv1.GroupVersion.String(),
+				"kind":       "ClusterClass",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns3",
+				},
+				"spec": map[string]interface{}{
+					"workers": map[string]interface{}{
+						"machineDeployments": []interface{}{
+							map[string]interface{}{
+								"template": map[string]interface{}{
+									"bootstrap": map[string]interface{}{
+										"ref": map[string]interface{}{"name": "bar", "namespace": "ns3"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "moves the references of the ControlPlane contract",
+			obj: map[string]interface{}{
+				"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+				"kind":       "ControlPlane",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns1",
+				},
+				"spec": map[string]interface{}{
+					"machineTemplate": map[string]interface{}{
+						"infrastructureRef": map[string]interface{}{"name": "bar", "namespace": "ns1"},
+						"failureDomainInfrastructureRefs": []interface{}{
+							map[string]interface{}{
+								"failureDomain":     "fd1",
+								"infrastructureRef": map[string]interface{}{"name": "baz", "namespace": "ns1"},
+							},
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+				"kind":       "ControlPlane",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns3",
+				},
+				"spec": map[string]interface{}{
+					"machineTemplate": map[string]interface{}{
+						"infrastructureRef": map[string]interface{}{"name": "bar", "namespace": "ns3"},
+						"failureDomainInfrastructureRefs": []interface{}{
+							map[string]interface{}{
+								"failureDomain":     "fd1",
+								"infrastructureRef": map[string]interface{}{"name": "baz", "namespace": "ns3"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "does not move references defined by providers outside of the Cluster API contracts",
+			obj: map[string]interface{}{
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"kind":       "InfrastructureCluster",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns1",
+				},
+				"spec": map[string]interface{}{
+					"identityRef": map[string]interface{}{"name": "bar", "namespace": "ns1"},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"kind":       "InfrastructureCluster",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns3",
+				},
+				"spec": map[string]interface{}{
+					"identityRef": map[string]interface{}{"name": "bar", "namespace": "ns1"},
+				},
+			},
+		},
+		{
+			name: "ignores objects in other namespaces",
+			obj: map[string]interface{}{
+				"apiVersion": clusterv1.GroupVersion.String(),
+				"kind":       "Cluster",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns2",
+				},
+				"spec": map[string]interface{}{
+					"infrastructureRef": map[string]interface{}{
+						"name":      "foo",
+						"namespace": "ns1",
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"apiVersion": clusterv1.GroupVersion.String(),
+				"kind":       "Cluster",
+				"metadata": map[string]interface{}{
+					"name":      "foo",
+					"namespace": "ns2",
+				},
+				"spec": map[string]interface{}{
+					"infrastructureRef": map[string]interface{}{
+						"name":      "foo",
+						"namespace": "ns1",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{Object: tt.obj}
+			g.Expect(NewNamespaceMutator("ns1", "ns3")(obj)).To(Succeed())
+			g.Expect(obj.Object).To(Equal(tt.want))
+		})
+	}
+}
+
 func Test_objectMover_checkProvisioningCompleted(t *testing.T) {
 	type fields struct {
 		objs []client.Object
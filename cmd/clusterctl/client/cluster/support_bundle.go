@@ -0,0 +1,570 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/internal/contract"
+)
+
+const (
+	// redactedValue is the value replacing sensitive information in a support bundle.
+	redactedValue = "REDACTED"
+
+	// minRedactedValueLength is the minimum length of the values of Secrets which are redacted from
+	// the support bundle files other than the Secrets themselves, e.g. logs; shorter values would
+	// redact too much unrelated content.
+	minRedactedValueLength = 8
+
+	// lastAppliedConfigAnnotation is the annotation used by kubectl to store the last applied configuration,
+	// which could include sensitive information.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+var (
+	// kubeadmConfigSpecSensitivePaths are the paths of the sensitive fields of a KubeadmConfigSpec; paths
+	// traverse lists, e.g. files.content identifies the content of all the files.
+	kubeadmConfigSpecSensitivePaths = []contract.Path{
+		{"joinConfiguration", "discovery", "bootstrapToken", "token"},
+		{"files", "content"},
+		{"users", "passwd"},
+	}
+
+	// sensitivePaths are the paths of the fields which are redacted from the objects in a support bundle, by kind.
+	sensitivePaths = map[schema.GroupKind][]contract.Path{
+		{Group: "bootstrap.cluster.x-k8s.io", Kind: "KubeadmConfig"}:                  withPrefix(contract.Path{"spec"}, kubeadmConfigSpecSensitivePaths),
+		{Group: "bootstrap.cluster.x-k8s.io", Kind: "KubeadmConfigTemplate"}:          withPrefix(contract.Path{"spec", "template", "spec"}, kubeadmConfigSpecSensitivePaths),
+		{Group: "controlplane.cluster.x-k8s.io", Kind: "KubeadmControlPlane"}:         withPrefix(contract.Path{"spec", "kubeadmConfigSpec"}, kubeadmConfigSpecSensitivePaths),
+		{Group: "controlplane.cluster.x-k8s.io", Kind: "KubeadmControlPlaneTemplate"}: withPrefix(contract.Path{"spec", "template", "spec", "kubeadmConfigSpec"}, kubeadmConfigSpecSensitivePaths),
+	}
+
+	// bootstrapTokenRegex matches bootstrap tokens, which are redacted from all the files in a support bundle,
+	// e.g. logs, because they could belong to objects not collected in the bundle.
+	bootstrapTokenRegex = regexp.MustCompile(`\b[a-z0-9]{6}\.[a-z0-9]{16}\b`)
+)
+
+func withPrefix(prefix contract.Path, paths []contract.Path) []contract.Path {
+	ret := make([]contract.Path, 0, len(paths))
+	for _, p := range paths {
+		ret = append(ret, append(append(contract.Path{}, prefix...), p...))
+	}
+	return ret
+}
+
+// SupportBundleClient has methods to collect the information required to troubleshoot a Cluster.
+type SupportBundleClient interface {
+	// Collect collects Cluster API objects, provider objects, controller logs, events and optionally
+	// a summary of the workload cluster, and writes them as a gzipped tar archive.
+	Collect(in *SupportBundleInput) error
+}
+
+// SupportBundleInput defines the input for the Collect function.
+type SupportBundleInput struct {
+	// Namespace is the namespace of the Cluster.
+	Namespace string
+
+	// ClusterName is the name of the Cluster.
+	ClusterName string
+
+	// LogsTailLines is the number of lines of the controller logs to collect; all the lines are collected if nil.
+	LogsTailLines *int64
+
+	// IncludeWorkloadCluster, if true, adds a summary of the Nodes and Pods of the workload cluster.
+	IncludeWorkloadCluster bool
+
+	// Output is where the archive is written.
+	Output io.Writer
+}
+
+// podLogsGetter returns the logs of a container.
+type podLogsGetter func(ctx context.Context, namespace, name, container string, tailLines *int64) ([]byte, error)
+
+// workloadClientGetter returns a client for the workload cluster accessible with the given kubeconfig.
+type workloadClientGetter func(kubeconfig string) (client.Client, error)
+
+// discoveryTypesGetter sets the types of the objects to be discovered in an object graph.
+type discoveryTypesGetter func(graph *objectGraph) error
+
+// supportBundleClient implements SupportBundleClient.
+type supportBundleClient struct {
+	proxy             Proxy
+	inventoryClient   InventoryClient
+	workloadCluster   WorkloadCluster
+	getDiscoveryTypes discoveryTypesGetter
+	getPodLogs        podLogsGetter
+	getWorkloadClient workloadClientGetter
+}
+
+// ensure supportBundleClient implements SupportBundleClient.
+var _ SupportBundleClient = &supportBundleClient{}
+
+// newSupportBundleClient returns a SupportBundleClient.
+func newSupportBundleClient(proxy Proxy, inventoryClient InventoryClient, workloadCluster WorkloadCluster) *supportBundleClient {
+	c := &supportBundleClient{
+		proxy:           proxy,
+		inventoryClient: inventoryClient,
+		workloadCluster: workloadCluster,
+	}
+	c.getDiscoveryTypes = (*objectGraph).getDiscoveryTypes
+	c.getPodLogs = c.podLogs
+	c.getWorkloadClient = newWorkloadClient
+	return c
+}
+
+func (s *supportBundleClient) Collect(in *SupportBundleInput) error {
+	log := logf.Log
+
+	if in.ClusterName == "" {
+		return errors.New("name of the Cluster is required")
+	}
+
+	c, err := s.proxy.NewClient()
+	if err != nil {
+		return err
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: in.Namespace, Name: in.ClusterName}, cluster); err != nil {
+		return errors.Wrapf(err, "failed to get Cluster %s/%s", in.Namespace, in.ClusterName)
+	}
+
+	b := newSupportBundle()
+
+	log.Info("Collecting Cluster API objects", "Cluster", in.ClusterName, "Namespace", in.Namespace)
+	objs, err := s.collectObjects(cluster)
+	if err != nil {
+		return err
+	}
+	// Record all the sensitive values before adding any file to the bundle, so they are redacted everywhere,
+	// e.g. in controller logs.
+	uids := map[types.UID]bool{}
+	for _, obj := range objs {
+		uids[obj.GetUID()] = true
+		b.redactor.addSecret(obj)
+		b.redactor.addSensitiveFields(obj)
+	}
+	for _, obj := range objs {
+		if err := b.addObject(path.Join("objects", obj.GetKind(), obj.GetName()+".yaml"), obj); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Collecting events")
+	if err := s.collectEvents(c, cluster.Namespace, uids, b); err != nil {
+		return err
+	}
+
+	log.Info("Collecting controller logs")
+	if err := s.collectLogs(c, in.LogsTailLines, b); err != nil {
+		return err
+	}
+
+	if in.IncludeWorkloadCluster {
+		log.Info("Collecting workload cluster summary")
+		if err := s.collectWorkloadCluster(cluster, b); err != nil {
+			// The workload cluster could be unreachable, which is often the reason for collecting a support bundle;
+			// record the error in the bundle instead of failing.
+			log.Info("Failed to collect the workload cluster summary", "Error", err.Error())
+			b.addFile("workload-cluster/error.txt", []byte(err.Error()+"\n"))
+		}
+	}
+
+	return b.write(in.Output)
+}
+
+// collectObjects returns the Cluster and all the objects belonging to the Cluster, as discovered by
+// the object graph used by move, including the ClusterClass used by the Cluster and its templates.
+func (s *supportBundleClient) collectObjects(cluster *clusterv1.Cluster) ([]*unstructured.Unstructured, error) {
+	graph := newObjectGraph(s.proxy, s.inventoryClient)
+	if err := s.getDiscoveryTypes(graph); err != nil {
+		return nil, err
+	}
+	if err := graph.Discovery(cluster.Namespace); err != nil {
+		return nil, err
+	}
+
+	tenants := []*node{}
+	for _, n := range graph.getClusters() {
+		if n.identity.UID == cluster.UID {
+			tenants = append(tenants, n)
+		}
+	}
+	if cluster.Spec.Topology != nil {
+		for _, n := range graph.getClusterClasses() {
+			if n.identity.Name == cluster.Spec.Topology.Class {
+				tenants = append(tenants, n)
+			}
+		}
+	}
+
+	c, err := s.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []*unstructured.Unstructured{}
+	for _, n := range graph.getNodes() {
+		if n.virtual || !belongsTo(n, tenants) {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(n.identity.APIVersion)
+		obj.SetKind(n.identity.Kind)
+		if err := c.Get(ctx, client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get %s %s/%s", n.identity.Kind, n.identity.Namespace, n.identity.Name)
+		}
+		objs = append(objs, obj)
+	}
+
+	sort.Slice(objs, func(i, j int) bool {
+		if objs[i].GetKind() != objs[j].GetKind() {
+			return objs[i].GetKind() < objs[j].GetKind()
+		}
+		return objs[i].GetName() < objs[j].GetName()
+	})
+	return objs, nil
+}
+
+// belongsTo returns true if the node is one of the given tenants or belongs to one of them.
+func belongsTo(n *node, tenants []*node) bool {
+	for _, t := range tenants {
+		if n == t {
+			return true
+		}
+		if _, ok := n.tenant[t]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// collectEvents adds the events related to the collected objects.
+func (s *supportBundleClient) collectEvents(c client.Client, namespace string, uids map[types.UID]bool, b *supportBundle) error {
+	eventList := &corev1.EventList{}
+	if err := c.List(ctx, eventList, client.InNamespace(namespace)); err != nil {
+		return errors.Wrapf(err, "failed to list events in namespace %s", namespace)
+	}
+
+	events := []corev1.Event{}
+	for _, e := range eventList.Items {
+		if uids[e.InvolvedObject.UID] {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(&events[j].LastTimestamp)
+	})
+
+	out := &bytes.Buffer{}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tCOUNT\tMESSAGE")
+	for _, e := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%d\t%s\n",
+			e.LastTimestamp.UTC().Format(time.RFC3339), e.Type, e.Reason, e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Count, strings.TrimSpace(e.Message))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	b.addFile("events.txt", out.Bytes())
+	return nil
+}
+
+// collectLogs adds the logs of the controllers of the providers installed in the management cluster.
+func (s *supportBundleClient) collectLogs(c client.Client, tailLines *int64, b *supportBundle) error {
+	log := logf.Log
+
+	providers, err := s.inventoryClient.List()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range providers.Items {
+		podList := &corev1.PodList{}
+		if err := c.List(ctx, podList, client.InNamespace(p.Namespace), client.MatchingLabels{clusterv1.ProviderLabelName: p.ManifestLabel()}); err != nil {
+			return errors.Wrapf(err, "failed to list Pods for provider %s", p.InstanceName())
+		}
+		for _, pod := range podList.Items {
+			for _, container := range pod.Spec.Containers {
+				logs, err := s.getPodLogs(ctx, pod.Namespace, pod.Name, container.Name, tailLines)
+				if err != nil {
+					// Logs could be unavailable, e.g. if the Pod is not yet started; collect the other logs anyway.
+					log.V(1).Info("Failed to get logs", "Pod", pod.Name, "Container", container.Name, "Error", err.Error())
+					continue
+				}
+				b.addFile(path.Join("logs", p.InstanceName(), fmt.Sprintf("%s-%s.log", pod.Name, container.Name)), logs)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *supportBundleClient) podLogs(ctx context.Context, namespace, name, container string, tailLines *int64) ([]byte, error) {
+	config, err := s.proxy.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	cs, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return cs.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{Container: container, TailLines: tailLines}).DoRaw(ctx)
+}
+
+// collectWorkloadCluster adds a summary of the Nodes and the Pods of the workload cluster.
+func (s *supportBundleClient) collectWorkloadCluster(cluster *clusterv1.Cluster, b *supportBundle) error {
+	kubeconfig, err := s.workloadCluster.GetKubeconfig(cluster.Name, cluster.Namespace)
+	if err != nil {
+		return err
+	}
+	c, err := s.getWorkloadClient(kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := c.List(ctx, nodeList); err != nil {
+		return errors.Wrap(err, "failed to list Nodes in the workload cluster")
+	}
+	out := &bytes.Buffer{}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tREADY\tVERSION\tPROVIDER ID")
+	for _, n := range nodeList.Items {
+		ready := corev1.ConditionUnknown
+		for _, c := range n.Status.Conditions {
+			if c.Type == corev1.NodeReady {
+				ready = c.Status
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", n.Name, ready, n.Status.NodeInfo.KubeletVersion, n.Spec.ProviderID)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	b.addFile("workload-cluster/nodes.txt", out.Bytes())
+
+	podList := &corev1.PodList{}
+	if err := c.List(ctx, podList); err != nil {
+		return errors.Wrap(err, "failed to list Pods in the workload cluster")
+	}
+	out = &bytes.Buffer{}
+	w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tPHASE\tRESTARTS\tNODE")
+	for _, p := range podList.Items {
+		var restarts int32
+		for _, cs := range p.Status.ContainerStatuses {
+			restarts += cs.RestartCount
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", p.Namespace, p.Name, p.Status.Phase, restarts, p.Spec.NodeName)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	b.addFile("workload-cluster/pods.txt", out.Bytes())
+	return nil
+}
+
+func newWorkloadClient(kubeconfig string) (client.Client, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeconfig))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create REST config for the workload cluster")
+	}
+	config.Timeout = 30 * time.Second
+	return client.New(config, client.Options{Scheme: scheme.Scheme})
+}
+
+// supportBundle collects the files of a support bundle.
+type supportBundle struct {
+	redactor *redactor
+	names    []string
+	files    map[string][]byte
+}
+
+func newSupportBundle() *supportBundle {
+	return &supportBundle{
+		redactor: &redactor{},
+		files:    map[string][]byte{},
+	}
+}
+
+// addObject adds an object to the bundle, redacting the data of Secrets, the sensitive fields and the annotations
+// which could contain sensitive information.
+func (b *supportBundle) addObject(name string, obj *unstructured.Unstructured) error {
+	obj = obj.DeepCopy()
+	obj.SetManagedFields(nil)
+	if annotations := obj.GetAnnotations(); annotations != nil {
+		delete(annotations, lastAppliedConfigAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+	for _, p := range sensitivePaths[obj.GroupVersionKind().GroupKind()] {
+		visitPath(obj.Object, p, func(fields map[string]interface{}, field string) {
+			fields[field] = redactedValue
+		})
+	}
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			data, ok, err := unstructured.NestedMap(obj.Object, field)
+			if err != nil || !ok {
+				continue
+			}
+			for k := range data {
+				data[k] = redactedValue
+			}
+			if err := unstructured.SetNestedMap(obj.Object, data, field); err != nil {
+				return errors.Wrapf(err, "failed to redact Secret %s", obj.GetName())
+			}
+		}
+	}
+
+	content, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s %s", obj.GetKind(), obj.GetName())
+	}
+	b.addFile(name, content)
+	return nil
+}
+
+// addFile adds a file to the bundle, redacting the values of the collected Secrets.
+func (b *supportBundle) addFile(name string, content []byte) {
+	if _, ok := b.files[name]; !ok {
+		b.names = append(b.names, name)
+	}
+	b.files[name] = b.redactor.redact(content)
+}
+
+// write writes the bundle as a gzipped tar archive.
+func (b *supportBundle) write(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+	for _, name := range b.names {
+		content := b.files[name]
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(content)),
+			ModTime: now,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to write %s to the support bundle", name)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return errors.Wrapf(err, "failed to write %s to the support bundle", name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the support bundle")
+	}
+	return errors.Wrap(gw.Close(), "failed to write the support bundle")
+}
+
+// redactor replaces the values of Secrets in the files of a support bundle, e.g. credentials printed in logs.
+type redactor struct {
+	values [][]byte
+}
+
+// addSecret records the values of a Secret to be redacted.
+func (r *redactor) addSecret(obj *unstructured.Unstructured) {
+	if obj.GetAPIVersion() != "v1" || obj.GetKind() != "Secret" {
+		return
+	}
+	secret := &corev1.Secret{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, secret); err != nil {
+		return
+	}
+	for _, v := range secret.Data {
+		if len(v) >= minRedactedValueLength {
+			r.values = append(r.values, v)
+		}
+	}
+	for _, v := range secret.StringData {
+		if len(v) >= minRedactedValueLength {
+			r.values = append(r.values, []byte(v))
+		}
+	}
+}
+
+// addSensitiveFields records the values of the sensitive fields of an object to be redacted.
+func (r *redactor) addSensitiveFields(obj *unstructured.Unstructured) {
+	for _, p := range sensitivePaths[obj.GroupVersionKind().GroupKind()] {
+		visitPath(obj.Object, p, func(fields map[string]interface{}, field string) {
+			if v, ok := fields[field].(string); ok && len(v) >= minRedactedValueLength {
+				r.values = append(r.values, []byte(v))
+			}
+		})
+	}
+}
+
+func (r *redactor) redact(content []byte) []byte {
+	for _, v := range r.values {
+		content = bytes.ReplaceAll(content, v, []byte(redactedValue))
+	}
+	return bootstrapTokenRegex.ReplaceAll(content, []byte(redactedValue))
+}
+
+// visitPath calls visit for each field identified by the path, traversing all the items of the lists along the path.
+func visitPath(fields map[string]interface{}, p contract.Path, visit func(fields map[string]interface{}, field string)) {
+	if len(p) == 0 {
+		return
+	}
+	value, ok := fields[p[0]]
+	if !ok {
+		return
+	}
+	if len(p) == 1 {
+		visit(fields, p[0])
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		visitPath(v, p[1:], visit)
+	case []interface{}:
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				visitPath(m, p[1:], visit)
+			}
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+type fakeWorkloadCluster struct{}
+
+func (fakeWorkloadCluster) GetKubeconfig(_, _ string) (string, error) {
+	return "kubeconfig", nil
+}
+
+func Test_supportBundleClient_Collect(t *testing.T) {
+	g := NewWithT(t)
+
+	password := "my-secret-password"
+
+	objs := test.NewFakeCluster("ns1", "cluster1").Objs()
+	objs = append(objs, test.NewFakeCluster("ns1", "cluster2").Objs()...)
+
+	var cluster1 *clusterv1.Cluster
+	for _, o := range objs {
+		if c, ok := o.(*clusterv1.Cluster); ok && c.Name == "cluster1" {
+			cluster1 = c
+		}
+	}
+	g.Expect(cluster1).ToNot(BeNil())
+
+	credentials := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "cluster1-credentials",
+			UID:       "cluster1-credentials",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: cluster1.Name, UID: cluster1.UID},
+			},
+		},
+		Data: map[string][]byte{"password": []byte(password)},
+	}
+	events := []client.Object{
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "ns1", Name: "event1"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Cluster", Name: "cluster1", UID: cluster1.UID},
+			Reason:         "Cluster1Reason",
+			Message:        "failed to log in with " + password,
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "ns1", Name: "event2"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Cluster", Name: "cluster2", UID: "cluster2"},
+			Reason:         "Cluster2Reason",
+		},
+	}
+	controllerPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "infra1-system",
+			Name:      "infra1-controller-manager",
+			Labels:    map[string]string{clusterv1.ProviderLabelName: "infrastructure-infra1"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "manager"}}},
+	}
+
+	proxy := getFakeProxyWithCRDs().
+		WithProviderInventory("infra1", clusterctlv1.InfrastructureProviderType, "v1.0.0", "infra1-system").
+		WithObjs(objs...).
+		WithObjs(credentials, controllerPod).
+		WithObjs(events...)
+
+	s := newSupportBundleClient(proxy, newInventoryClient(proxy, nil), fakeWorkloadCluster{})
+	s.getDiscoveryTypes = getFakeDiscoveryTypes
+	s.getPodLogs = func(_ context.Context, namespace, name, container string, _ *int64) ([]byte, error) {
+		return []byte(namespace + "/" + name + "/" + container + ": using password " + password + "\n"), nil
+	}
+	s.getWorkloadClient = func(_ string) (client.Client, error) {
+		return fake.NewClientBuilder().WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}).Build(), nil
+	}
+
+	out := &bytes.Buffer{}
+	g.Expect(s.Collect(&SupportBundleInput{
+		Namespace:              "ns1",
+		ClusterName:            "cluster1",
+		IncludeWorkloadCluster: true,
+		Output:                 out,
+	})).To(Succeed())
+
+	files := readSupportBundle(g, out)
+
+	g.Expect(files).To(HaveKey("objects/Cluster/cluster1.yaml"))
+	g.Expect(files).To(HaveKey("objects/GenericInfrastructureCluster/cluster1.yaml"))
+	g.Expect(files).ToNot(HaveKey("objects/Cluster/cluster2.yaml"))
+
+	g.Expect(files).To(HaveKey("objects/Secret/cluster1-credentials.yaml"))
+	g.Expect(files["objects/Secret/cluster1-credentials.yaml"]).To(ContainSubstring("password: " + redactedValue))
+
+	g.Expect(files).To(HaveKey("events.txt"))
+	g.Expect(files["events.txt"]).To(ContainSubstring("Cluster1Reason"))
+	g.Expect(files["events.txt"]).ToNot(ContainSubstring("Cluster2Reason"))
+
+	g.Expect(files).To(HaveKey("logs/infra1-system/infrastructure-infra1/infra1-controller-manager-manager.log"))
+	g.Expect(files["logs/infra1-system/infrastructure-infra1/infra1-controller-manager-manager.log"]).To(ContainSubstring("using password " + redactedValue))
+
+	g.Expect(files).To(HaveKey("workload-cluster/nodes.txt"))
+	g.Expect(files["workload-cluster/nodes.txt"]).To(ContainSubstring("node1"))
+
+	for name, content := range files {
+		g.Expect(content).ToNot(ContainSubstring(password), "file %s is not redacted", name)
+	}
+}
+
+func readSupportBundle(g *WithT, r io.Reader) map[string]string {
+	gr, err := gzip.NewReader(r)
+	g.Expect(err).ToNot(HaveOccurred())
+	tr := tar.NewReader(gr)
+
+	files := map[string]string{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		content, err := io.ReadAll(tr)
+		g.Expect(err).ToNot(HaveOccurred())
+		files[h.Name] = string(content)
+	}
+	return files
+}
+
+func Test_supportBundle_addObjectRedactsSensitiveFields(t *testing.T) {
+	token := "abcdef.0123456789abcdef"
+	fileContent := "my-secret-file-content"
+
+	tests := []struct {
+		name  string
+		obj   *unstructured.Unstructured
+		value string
+	}{
+		{
+			name: "KubeadmConfig bootstrap token",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+				"kind":       "KubeadmConfig",
+				"metadata":   map[string]interface{}{"name": "config1"},
+				"spec": map[string]interface{}{
+					"joinConfiguration": map[string]interface{}{
+						"discovery": map[string]interface{}{
+							"bootstrapToken": map[string]interface{}{"token": token},
+						},
+					},
+				},
+			}},
+			value: token,
+		},
+		{
+			name: "KubeadmConfig files content",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+				"kind":       "KubeadmConfig",
+				"metadata":   map[string]interface{}{"name": "config1"},
+				"spec": map[string]interface{}{
+					"files": []interface{}{
+						map[string]interface{}{"path": "/etc/foo", "content": fileContent},
+					},
+				},
+			}},
+			value: fileContent,
+		},
+		{
+			name: "KubeadmControlPlane files content",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+				"kind":       "KubeadmControlPlane",
+				"metadata":   map[string]interface{}{"name": "kcp1"},
+				"spec": map[string]interface{}{
+					"kubeadmConfigSpec": map[string]interface{}{
+						"files": []interface{}{
+							map[string]interface{}{"path": "/etc/foo", "content": fileContent},
+						},
+					},
+				},
+			}},
+			value: fileContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			b := newSupportBundle()
+			b.redactor.addSensitiveFields(tt.obj)
+			g.Expect(b.addObject("object.yaml", tt.obj)).To(Succeed())
+			b.addFile("controller.log", []byte("using "+tt.value+"\n"))
+
+			g.Expect(string(b.files["object.yaml"])).To(ContainSubstring(redactedValue))
+			g.Expect(string(b.files["object.yaml"])).ToNot(ContainSubstring(tt.value))
+			g.Expect(string(b.files["controller.log"])).To(Equal("using " + redactedValue + "\n"))
+
+			// The collected object is not altered.
+			content, err := yaml.Marshal(tt.obj.Object)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(content)).To(ContainSubstring(tt.value))
+		})
+	}
+}
+
+func Test_redactor_redactsBootstrapTokens(t *testing.T) {
+	g := NewWithT(t)
+
+	r := &redactor{}
+	g.Expect(string(r.redact([]byte("joining with token abcdef.0123456789abcdef")))).To(Equal("joining with token " + redactedValue))
+}
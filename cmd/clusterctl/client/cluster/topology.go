@@ -54,6 +54,10 @@ const (
 // TopologyClient has methods to work with ClusterClass and ManagedTopologies.
 type TopologyClient interface {
 	Plan(in *TopologyPlanInput) (*TopologyPlanOutput, error)
+	Ownership(in *TopologyOwnershipInput) (*TopologyOwnershipOutput, error)
+	Adopt(in *TopologyAdoptInput) (*TopologyAdoptOutput, error)
+	Export(in *TopologyExportInput) (*TopologyExportOutput, error)
+	Import(in *TopologyImportInput) (*TopologyImportOutput, error)
 }
 
 // topologyClient implements TopologyClient.
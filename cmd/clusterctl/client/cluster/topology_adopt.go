@@ -0,0 +1,296 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+)
+
+// TopologyAdoptInput defines the input for the Adopt function.
+type TopologyAdoptInput struct {
+	// Namespace is the namespace of the Cluster to adopt.
+	Namespace string
+
+	// ClusterName is the name of the Cluster to adopt.
+	ClusterName string
+
+	// ClassName is the name of the ClusterClass the Cluster should use after adoption.
+	// The ClusterClass must exist in the same namespace of the Cluster.
+	ClassName string
+
+	// DryRun, if true, only checks that the Cluster is compatible with the ClusterClass
+	// and computes the resulting topology without changing any object.
+	DryRun bool
+}
+
+// TopologyAdoptOutput defines the output of the Adopt function.
+type TopologyAdoptOutput struct {
+	// Issues is the list of incompatibilities between the Cluster and the ClusterClass.
+	// If there are issues the Cluster cannot be adopted and no object is changed.
+	Issues []string
+
+	// Topology is the topology computed for the Cluster from its current state.
+	Topology *clusterv1.Topology
+
+	// Objects is the list of objects that are labeled as owned by the topology.
+	// NOTE: The objects keep their current names, so the topology controller picks them up
+	// as the current state of the Cluster instead of creating new ones.
+	Objects []*corev1.ObjectReference
+}
+
+// Adopt moves an existing Cluster, which is not using a managed topology, under the management of
+// the topology controller using the given ClusterClass.
+// Adoption requires the Cluster to be compatible with the ClusterClass, i.e. the InfrastructureCluster, the ControlPlane
+// and all the MachineDeployments of the Cluster must be of the same kinds defined in the ClusterClass.
+// If the Cluster is compatible, all its objects are labeled as owned by the topology and the Cluster topology
+// is set with a version and replicas matching the current state of the Cluster.
+func (t *topologyClient) Adopt(in *TopologyAdoptInput) (*TopologyAdoptOutput, error) {
+	ctx := context.TODO()
+	log := logf.Log
+
+	c, err := t.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: in.Namespace, Name: in.ClusterName}, cluster); err != nil {
+		return nil, errors.Wrapf(err, "failed to get Cluster %s/%s", in.Namespace, in.ClusterName)
+	}
+	if cluster.Spec.Topology != nil {
+		return nil, errors.Errorf("Cluster %s/%s already has a managed topology", in.Namespace, in.ClusterName)
+	}
+
+	clusterClass := &clusterv1.ClusterClass{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: in.Namespace, Name: in.ClassName}, clusterClass); err != nil {
+		return nil, errors.Wrapf(err, "failed to get ClusterClass %s/%s", in.Namespace, in.ClassName)
+	}
+
+	res, err := computeAdoption(ctx, c, cluster, clusterClass)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Issues) > 0 || in.DryRun {
+		return res, nil
+	}
+
+	// Label all the objects as owned by the topology before setting the topology on the Cluster,
+	// so the topology controller finds a consistent current state on its first reconcile.
+	for _, ref := range res.Objects {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(ref.APIVersion)
+		obj.SetKind(ref.Kind)
+		if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s", tlog.KRef{Ref: ref})
+		}
+		patchHelper := client.MergeFrom(obj.DeepCopy())
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[clusterv1.ClusterLabelName] = cluster.Name
+		labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+		labels[clusterv1.ClusterTopologyClassNameLabel] = in.ClassName
+		if ref.Kind == "MachineDeployment" && ref.APIVersion == clusterv1.GroupVersion.String() {
+			labels[clusterv1.ClusterTopologyMachineDeploymentLabelName] = ref.Name
+		}
+		obj.SetLabels(labels)
+		if err := c.Patch(ctx, obj, patchHelper); err != nil {
+			return nil, errors.Wrapf(err, "failed to label %s as owned by the topology", tlog.KRef{Ref: ref})
+		}
+		log.V(1).Info("Labeled object as owned by the topology", "kind", ref.Kind, "namespace", ref.Namespace, "name", ref.Name)
+	}
+
+	// Set the topology on the Cluster. The unsafe annotation is required because the Cluster webhook
+	// does not allow to set a class on an existing Cluster.
+	patchHelper := client.MergeFrom(cluster.DeepCopy())
+	annotations := cluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.ClusterTopologyUnsafeUpdateClassNameAnnotation] = ""
+	cluster.SetAnnotations(annotations)
+	cluster.Spec.Topology = res.Topology
+	if err := c.Patch(ctx, cluster, patchHelper); err != nil {
+		return nil, errors.Wrapf(err, "failed to set topology on Cluster %s/%s", cluster.Namespace, cluster.Name)
+	}
+
+	return res, nil
+}
+
+// computeAdoption checks that the Cluster is compatible with the ClusterClass and computes the topology
+// and the list of objects to be adopted.
+func computeAdoption(ctx context.Context, c client.Reader, cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) (*TopologyAdoptOutput, error) {
+	res := &TopologyAdoptOutput{
+		Topology: &clusterv1.Topology{
+			Class: clusterClass.Name,
+		},
+	}
+
+	// Check the InfrastructureCluster.
+	if cluster.Spec.InfrastructureRef == nil {
+		res.Issues = append(res.Issues, "Cluster does not have an infrastructureRef")
+	} else {
+		if issue := checkRefCompatibility(cluster.Spec.InfrastructureRef, clusterClass.Spec.Infrastructure.Ref, true); issue != "" {
+			res.Issues = append(res.Issues, fmt.Sprintf("InfrastructureCluster %s", issue))
+		}
+		res.Objects = append(res.Objects, refWithNamespace(cluster.Spec.InfrastructureRef, cluster.Namespace))
+	}
+
+	// Check the ControlPlane.
+	if cluster.Spec.ControlPlaneRef == nil {
+		res.Issues = append(res.Issues, "Cluster does not have a controlPlaneRef")
+	} else {
+		controlPlaneRef := refWithNamespace(cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if issue := checkRefCompatibility(controlPlaneRef, clusterClass.Spec.ControlPlane.Ref, true); issue != "" {
+			res.Issues = append(res.Issues, fmt.Sprintf("ControlPlane %s", issue))
+		}
+		res.Objects = append(res.Objects, controlPlaneRef)
+
+		controlPlane := &unstructured.Unstructured{}
+		controlPlane.SetAPIVersion(controlPlaneRef.APIVersion)
+		controlPlane.SetKind(controlPlaneRef.Kind)
+		if err := c.Get(ctx, client.ObjectKey{Namespace: controlPlaneRef.Namespace, Name: controlPlaneRef.Name}, controlPlane); err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s", tlog.KRef{Ref: controlPlaneRef})
+		}
+
+		version, err := contract.ControlPlane().Version().Get(controlPlane)
+		if err != nil {
+			res.Issues = append(res.Issues, fmt.Sprintf("failed to get the version from %s: %v", tlog.KRef{Ref: controlPlaneRef}, err))
+		} else {
+			res.Topology.Version = *version
+		}
+
+		if replicas, err := contract.ControlPlane().Replicas().Get(controlPlane); err == nil {
+			r := int32(*replicas)
+			res.Topology.ControlPlane.Replicas = &r
+		}
+
+		if clusterClass.Spec.ControlPlane.MachineInfrastructure != nil {
+			infrastructureRef, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(controlPlane)
+			if err != nil {
+				res.Issues = append(res.Issues, fmt.Sprintf("failed to get the InfrastructureMachineTemplate from %s: %v", tlog.KRef{Ref: controlPlaneRef}, err))
+			} else {
+				infrastructureRef = refWithNamespace(infrastructureRef, cluster.Namespace)
+				if issue := checkRefCompatibility(infrastructureRef, clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref, false); issue != "" {
+					res.Issues = append(res.Issues, fmt.Sprintf("ControlPlane InfrastructureMachineTemplate %s", issue))
+				}
+				res.Objects = append(res.Objects, infrastructureRef)
+			}
+		}
+	}
+
+	// Check the MachineDeployments.
+	mdList := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, mdList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	sort.Slice(mdList.Items, func(i, j int) bool { return mdList.Items[i].Name < mdList.Items[j].Name })
+	for i := range mdList.Items {
+		md := &mdList.Items[i]
+		if md.Spec.Template.Spec.Bootstrap.ConfigRef == nil {
+			res.Issues = append(res.Issues, fmt.Sprintf("MachineDeployment %s does not have a reference to a bootstrap config template", md.Name))
+			continue
+		}
+		bootstrapRef := refWithNamespace(md.Spec.Template.Spec.Bootstrap.ConfigRef, md.Namespace)
+		infrastructureRef := refWithNamespace(&md.Spec.Template.Spec.InfrastructureRef, md.Namespace)
+
+		// Use the first MachineDeploymentClass using the same template kinds of the MachineDeployment.
+		class := ""
+		for _, mdClass := range clusterClass.Spec.Workers.MachineDeployments {
+			if checkRefCompatibility(bootstrapRef, mdClass.Template.Bootstrap.Ref, false) == "" &&
+				checkRefCompatibility(infrastructureRef, mdClass.Template.Infrastructure.Ref, false) == "" {
+				class = mdClass.Class
+				break
+			}
+		}
+		if class == "" {
+			res.Issues = append(res.Issues, fmt.Sprintf("MachineDeployment %s does not match any MachineDeploymentClass: no class uses %s and %s", md.Name, bootstrapRef.Kind, infrastructureRef.Kind))
+			continue
+		}
+
+		if res.Topology.Workers == nil {
+			res.Topology.Workers = &clusterv1.WorkersTopology{}
+		}
+		res.Topology.Workers.MachineDeployments = append(res.Topology.Workers.MachineDeployments, clusterv1.MachineDeploymentTopology{
+			Class:    class,
+			Name:     md.Name,
+			Replicas: md.Spec.Replicas,
+		})
+		res.Objects = append(res.Objects,
+			&corev1.ObjectReference{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "MachineDeployment",
+				Namespace:  md.Namespace,
+				Name:       md.Name,
+			},
+			bootstrapRef,
+			infrastructureRef,
+		)
+	}
+
+	return res, nil
+}
+
+// checkRefCompatibility checks that the object referenced by ref belongs to the same API group and kind of the
+// template referenced in the ClusterClass. If isTemplate is true the object is expected to be created from the template,
+// and thus its kind is expected to match the template kind without the Template suffix.
+func checkRefCompatibility(ref, classRef *corev1.ObjectReference, isTemplate bool) string {
+	if classRef == nil {
+		return "is not defined in the ClusterClass"
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return fmt.Sprintf("has an invalid apiVersion %q", ref.APIVersion)
+	}
+	classGV, err := schema.ParseGroupVersion(classRef.APIVersion)
+	if err != nil {
+		return fmt.Sprintf("is referenced in the ClusterClass with an invalid apiVersion %q", classRef.APIVersion)
+	}
+
+	classKind := classRef.Kind
+	if isTemplate {
+		classKind = strings.TrimSuffix(classKind, clusterv1.TemplateSuffix)
+	}
+	if gv.Group != classGV.Group || ref.Kind != classKind {
+		return fmt.Sprintf("%s.%s does not match %s.%s defined in the ClusterClass", ref.Kind, gv.Group, classKind, classGV.Group)
+	}
+	return ""
+}
+
+// refWithNamespace returns a copy of the reference with the namespace set to the given value if missing.
+func refWithNamespace(ref *corev1.ObjectReference, namespace string) *corev1.ObjectReference {
+	res := ref.DeepCopy()
+	if res.Namespace == "" {
+		res.Namespace = namespace
+	}
+	return res
+}
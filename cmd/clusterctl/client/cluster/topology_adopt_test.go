@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	fakebootstrap "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/bootstrap"
+	fakecontrolplane "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/controlplane"
+	fakeinfrastructure "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/infrastructure"
+)
+
+func Test_topologyClient_Adopt(t *testing.T) {
+	newObjs := func() []client.Object {
+		return []client.Object{
+			&clusterv1.Cluster{
+				TypeMeta:   metav1.TypeMeta{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1"},
+				Spec: clusterv1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureCluster", Name: "cluster1"},
+					ControlPlaneRef:   &corev1.ObjectReference{APIVersion: fakecontrolplane.GroupVersion.String(), Kind: "GenericControlPlane", Name: "cluster1"},
+				},
+			},
+			&fakeinfrastructure.GenericInfrastructureCluster{
+				TypeMeta:   metav1.TypeMeta{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureCluster"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1"},
+			},
+			&fakecontrolplane.GenericControlPlane{
+				TypeMeta:   metav1.TypeMeta{APIVersion: fakecontrolplane.GroupVersion.String(), Kind: "GenericControlPlane"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "cluster1"},
+				Spec:       fakecontrolplane.GenericControlPlaneSpec{Version: "v1.25.0"},
+			},
+			&clusterv1.MachineDeployment{
+				TypeMeta: metav1.TypeMeta{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineDeployment"},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns1",
+					Name:      "md1",
+					Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster1"},
+				},
+				Spec: clusterv1.MachineDeploymentSpec{
+					ClusterName: "cluster1",
+					Replicas:    pointer.Int32(3),
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							ClusterName: "cluster1",
+							Bootstrap: clusterv1.Bootstrap{
+								ConfigRef: &corev1.ObjectReference{APIVersion: fakebootstrap.GroupVersion.String(), Kind: "GenericBootstrapConfigTemplate", Name: "md1"},
+							},
+							InfrastructureRef: corev1.ObjectReference{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureMachineTemplate", Name: "md1"},
+						},
+					},
+				},
+			},
+			&fakebootstrap.GenericBootstrapConfigTemplate{
+				TypeMeta:   metav1.TypeMeta{APIVersion: fakebootstrap.GroupVersion.String(), Kind: "GenericBootstrapConfigTemplate"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "md1"},
+			},
+			&fakeinfrastructure.GenericInfrastructureMachineTemplate{
+				TypeMeta:   metav1.TypeMeta{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureMachineTemplate"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "md1"},
+			},
+		}
+	}
+	newClusterClass := func(infrastructureMachineTemplateKind string) *clusterv1.ClusterClass {
+		return &clusterv1.ClusterClass{
+			TypeMeta:   metav1.TypeMeta{APIVersion: clusterv1.GroupVersion.String(), Kind: "ClusterClass"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "class1"},
+			Spec: clusterv1.ClusterClassSpec{
+				Infrastructure: clusterv1.LocalObjectTemplate{
+					Ref: &corev1.ObjectReference{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureClusterTemplate", Name: "class1"},
+				},
+				ControlPlane: clusterv1.ControlPlaneClass{
+					LocalObjectTemplate: clusterv1.LocalObjectTemplate{
+						Ref: &corev1.ObjectReference{APIVersion: fakecontrolplane.GroupVersion.String(), Kind: "GenericControlPlaneTemplate", Name: "class1"},
+					},
+				},
+				Workers: clusterv1.WorkersClass{
+					MachineDeployments: []clusterv1.MachineDeploymentClass{
+						{
+							Class: "default-worker",
+							Template: clusterv1.MachineDeploymentClassTemplate{
+								Bootstrap: clusterv1.LocalObjectTemplate{
+									Ref: &corev1.ObjectReference{APIVersion: fakebootstrap.GroupVersion.String(), Kind: "GenericBootstrapConfigTemplate", Name: "class1"},
+								},
+								Infrastructure: clusterv1.LocalObjectTemplate{
+									Ref: &corev1.ObjectReference{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: infrastructureMachineTemplateKind, Name: "class1"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		clusterClass *clusterv1.ClusterClass
+		dryRun       bool
+		wantIssues   bool
+	}{
+		{
+			name:         "adopts a compatible cluster",
+			clusterClass: newClusterClass("GenericInfrastructureMachineTemplate"),
+		},
+		{
+			name:         "does not change objects in dry run",
+			clusterClass: newClusterClass("GenericInfrastructureMachineTemplate"),
+			dryRun:       true,
+		},
+		{
+			name:         "reports issues for an incompatible cluster",
+			clusterClass: newClusterClass("AnotherInfrastructureMachineTemplate"),
+			wantIssues:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			proxy := test.NewFakeProxy().WithObjs(append(newObjs(), tt.clusterClass)...)
+			tc := newTopologyClient(proxy, newInventoryClient(proxy, nil))
+
+			out, err := tc.Adopt(&TopologyAdoptInput{
+				Namespace:   "ns1",
+				ClusterName: "cluster1",
+				ClassName:   "class1",
+				DryRun:      tt.dryRun,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			c, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			cluster := &clusterv1.Cluster{}
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "cluster1"}, cluster)).To(Succeed())
+			md := &clusterv1.MachineDeployment{}
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "md1"}, md)).To(Succeed())
+
+			if tt.wantIssues {
+				g.Expect(out.Issues).To(HaveLen(1))
+				g.Expect(cluster.Spec.Topology).To(BeNil())
+				return
+			}
+			g.Expect(out.Issues).To(BeEmpty())
+			g.Expect(out.Objects).To(HaveLen(5))
+			g.Expect(out.Topology).To(Equal(&clusterv1.Topology{
+				Class:   "class1",
+				Version: "v1.25.0",
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{Class: "default-worker", Name: "md1", Replicas: pointer.Int32(3)},
+					},
+				},
+			}))
+
+			if tt.dryRun {
+				g.Expect(cluster.Spec.Topology).To(BeNil())
+				g.Expect(md.Labels).ToNot(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+				return
+			}
+			g.Expect(cluster.Spec.Topology).To(Equal(out.Topology))
+			g.Expect(cluster.Annotations).To(HaveKey(clusterv1.ClusterTopologyUnsafeUpdateClassNameAnnotation))
+			g.Expect(md.Labels).To(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+			g.Expect(md.Labels).To(HaveKeyWithValue(clusterv1.ClusterTopologyClassNameLabel, "class1"))
+			g.Expect(md.Labels).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachineDeploymentLabelName, "md1"))
+		})
+	}
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/topology/clusterclass"
+)
+
+// TopologyExportInput defines the input for the Export function.
+type TopologyExportInput struct {
+	// Namespace is the namespace of the ClusterClass to export.
+	Namespace string
+
+	// ClassName is the name of the ClusterClass to export.
+	ClassName string
+}
+
+// TopologyExportOutput defines the output of the Export function.
+type TopologyExportOutput struct {
+	// Objs is the bundle, the list of templates referenced by the ClusterClass followed by the ClusterClass.
+	// Objects in the bundle do not have a namespace and do not have metadata set by the API server.
+	Objs []unstructured.Unstructured
+}
+
+// TopologyImportInput defines the input for the Import function.
+type TopologyImportInput struct {
+	// Objs is the bundle to import, as generated by Export.
+	Objs []unstructured.Unstructured
+
+	// TargetNamespace is the namespace where the objects of the bundle should be created.
+	TargetNamespace string
+
+	// ClassName is the name of the imported ClusterClass. If empty, the name in the bundle is used.
+	ClassName string
+
+	// NamePrefix is a prefix added to the name of all the templates in the bundle.
+	NamePrefix string
+
+	// DryRun, if true, only computes the objects to be created, without creating them.
+	DryRun bool
+}
+
+// TopologyImportOutput defines the output of the Import function.
+type TopologyImportOutput struct {
+	// Objs is the list of objects created in the target namespace, with names and references remapped.
+	Objs []unstructured.Unstructured
+}
+
+// Export returns a ClusterClass and all the templates it references as a self-contained bundle,
+// which can be imported into another namespace or another management cluster using Import.
+func (t *topologyClient) Export(in *TopologyExportInput) (*TopologyExportOutput, error) {
+	ctx := context.TODO()
+
+	c, err := t.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterClass := &clusterv1.ClusterClass{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: in.Namespace, Name: in.ClassName}, clusterClass); err != nil {
+		return nil, errors.Wrapf(err, "failed to get ClusterClass %s/%s", in.Namespace, in.ClassName)
+	}
+
+	objs := []unstructured.Unstructured{}
+	exported := map[string]bool{}
+	for _, templateRef := range clusterclass.TemplateRefs(clusterClass) {
+		ref := templateRef.Ref
+		// NOTE: The same template can be referenced multiple times in a ClusterClass, but it is exported only once.
+		key := bundleObjectKey(ref.GroupVersionKind().GroupKind(), ref.Name)
+		if !exported[key] {
+			exported[key] = true
+
+			template := &unstructured.Unstructured{}
+			template.SetAPIVersion(ref.APIVersion)
+			template.SetKind(ref.Kind)
+			if err := c.Get(ctx, client.ObjectKey{Namespace: clusterClass.Namespace, Name: ref.Name}, template); err != nil {
+				return nil, errors.Wrapf(err, "failed to get %s %s/%s referenced by ClusterClass %s/%s", ref.Kind, clusterClass.Namespace, ref.Name, clusterClass.Namespace, clusterClass.Name)
+			}
+			cleanupBundleObject(template)
+			objs = append(objs, *template)
+		}
+
+		// References in the bundle do not have a namespace, so they follow the namespace the ClusterClass is imported to.
+		ref.Namespace = ""
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		return bundleObjectKey(objs[i].GroupVersionKind().GroupKind(), objs[i].GetName()) < bundleObjectKey(objs[j].GroupVersionKind().GroupKind(), objs[j].GetName())
+	})
+
+	clusterClassObj, err := toUnstructuredClusterClass(clusterClass)
+	if err != nil {
+		return nil, err
+	}
+	cleanupBundleObject(clusterClassObj)
+	objs = append(objs, *clusterClassObj)
+
+	return &TopologyExportOutput{Objs: objs}, nil
+}
+
+// Import creates the ClusterClass and the templates of a bundle generated by Export in the target namespace,
+// optionally renaming the ClusterClass and adding a prefix to the name of the templates; all the references
+// in the ClusterClass are remapped accordingly.
+func (t *topologyClient) Import(in *TopologyImportInput) (*TopologyImportOutput, error) {
+	ctx := context.TODO()
+
+	// Split the bundle into the ClusterClass and the templates.
+	var clusterClass *clusterv1.ClusterClass
+	templates := map[string]*unstructured.Unstructured{}
+	for i := range in.Objs {
+		obj := in.Objs[i].DeepCopy()
+		if obj.GroupVersionKind().GroupKind() == clusterv1.GroupVersion.WithKind("ClusterClass").GroupKind() {
+			if clusterClass != nil {
+				return nil, errors.New("invalid bundle: the bundle must contain exactly one ClusterClass")
+			}
+			clusterClass = &clusterv1.ClusterClass{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, clusterClass); err != nil {
+				return nil, errors.Wrapf(err, "failed to convert %s to ClusterClass", obj.GetName())
+			}
+			continue
+		}
+		key := bundleObjectKey(obj.GroupVersionKind().GroupKind(), obj.GetName())
+		if _, ok := templates[key]; ok {
+			return nil, errors.Errorf("invalid bundle: %s %s is included more than once", obj.GetKind(), obj.GetName())
+		}
+		templates[key] = obj
+	}
+	if clusterClass == nil {
+		return nil, errors.New("invalid bundle: the bundle must contain exactly one ClusterClass")
+	}
+
+	// Remap the references of the ClusterClass to the templates in the target namespace.
+	referenced := map[string]bool{}
+	for _, templateRef := range clusterclass.TemplateRefs(clusterClass) {
+		ref := templateRef.Ref
+		key := bundleObjectKey(ref.GroupVersionKind().GroupKind(), ref.Name)
+		if _, ok := templates[key]; !ok {
+			return nil, errors.Errorf("invalid bundle: %s %s referenced by the ClusterClass is not included in the bundle", ref.Kind, ref.Name)
+		}
+		referenced[key] = true
+		ref.Namespace = in.TargetNamespace
+		ref.Name = in.NamePrefix + ref.Name
+	}
+
+	objs := []unstructured.Unstructured{}
+	keys := make([]string, 0, len(templates))
+	for key := range templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !referenced[key] {
+			return nil, errors.Errorf("invalid bundle: %s %s is not referenced by the ClusterClass", templates[key].GetKind(), templates[key].GetName())
+		}
+		template := templates[key]
+		cleanupBundleObject(template)
+		template.SetNamespace(in.TargetNamespace)
+		template.SetName(in.NamePrefix + template.GetName())
+		objs = append(objs, *template)
+	}
+
+	if in.ClassName != "" {
+		clusterClass.Name = in.ClassName
+	}
+	clusterClassObj, err := toUnstructuredClusterClass(clusterClass)
+	if err != nil {
+		return nil, err
+	}
+	cleanupBundleObject(clusterClassObj)
+	clusterClassObj.SetNamespace(in.TargetNamespace)
+	// NOTE: The ClusterClass is created last, because it is valid only if all the templates it references exist.
+	objs = append(objs, *clusterClassObj)
+
+	if in.DryRun {
+		return &TopologyImportOutput{Objs: objs}, nil
+	}
+
+	c, err := t.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that none of the objects exists before creating them, so the import does not stop halfway.
+	var errList []error
+	for i := range objs {
+		existing := &unstructured.Unstructured{}
+		existing.SetGroupVersionKind(objs[i].GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(&objs[i]), existing); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errList = append(errList, errors.Wrapf(err, "failed to check if %s %s/%s exists", objs[i].GetKind(), objs[i].GetNamespace(), objs[i].GetName()))
+			}
+			continue
+		}
+		errList = append(errList, errors.Errorf("%s %s/%s already exists", objs[i].GetKind(), objs[i].GetNamespace(), objs[i].GetName()))
+	}
+	if len(errList) > 0 {
+		return nil, kerrors.NewAggregate(errList)
+	}
+
+	for i := range objs {
+		if err := c.Create(ctx, &objs[i]); err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s %s/%s", objs[i].GetKind(), objs[i].GetNamespace(), objs[i].GetName())
+		}
+	}
+
+	return &TopologyImportOutput{Objs: objs}, nil
+}
+
+// bundleObjectKey returns the key identifying an object in a bundle.
+func bundleObjectKey(gk schema.GroupKind, name string) string {
+	return strings.Join([]string{gk.Group, gk.Kind, name}, "/")
+}
+
+// toUnstructuredClusterClass converts a ClusterClass to Unstructured.
+func toUnstructuredClusterClass(clusterClass *clusterv1.ClusterClass) (*unstructured.Unstructured, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(clusterClass)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to convert ClusterClass %s to Unstructured", clusterClass.Name)
+	}
+	obj := &unstructured.Unstructured{Object: u}
+	obj.SetGroupVersionKind(clusterv1.GroupVersion.WithKind("ClusterClass"))
+	return obj, nil
+}
+
+// cleanupBundleObject removes from an object the namespace, the status and all the metadata
+// which are specific to the management cluster the object has been read from.
+func cleanupBundleObject(obj *unstructured.Unstructured) {
+	obj.SetNamespace("")
+	obj.SetUID("")
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+	obj.SetSelfLink("")
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetDeletionTimestamp(nil)
+	obj.SetDeletionGracePeriodSeconds(nil)
+	obj.SetManagedFields(nil)
+	obj.SetOwnerReferences(nil)
+	obj.SetFinalizers(nil)
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, corev1.LastAppliedConfigAnnotation)
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+
+	unstructured.RemoveNestedField(obj.Object, "status")
+}
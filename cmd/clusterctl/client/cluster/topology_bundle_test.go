@@ -0,0 +1,201 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/internal/topology/clusterclass"
+)
+
+func newBundleTestObjs(namespace string) []client.Object {
+	return test.NewFakeClusterClass(namespace, "class1").
+		WithWorkerMachineDeploymentClasses([]*test.FakeMachineDeploymentClass{
+			test.NewFakeMachineDeploymentClass(namespace, "worker1"),
+			test.NewFakeMachineDeploymentClass(namespace, "worker2"),
+		}).Objs()
+}
+
+func Test_topologyClient_Export(t *testing.T) {
+	t.Run("exports a ClusterClass with all the templates it references", func(t *testing.T) {
+		g := NewWithT(t)
+
+		proxy := test.NewFakeProxy().WithObjs(newBundleTestObjs("ns1")...)
+		tc := newTopologyClient(proxy, newInventoryClient(proxy, nil))
+
+		out, err := tc.Export(&TopologyExportInput{Namespace: "ns1", ClassName: "class1"})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// The bundle contains the InfrastructureClusterTemplate, the ControlPlaneTemplate, two bootstrap templates and
+		// two InfrastructureMachineTemplates, followed by the ClusterClass.
+		g.Expect(out.Objs).To(HaveLen(7))
+		g.Expect(out.Objs[6].GetKind()).To(Equal("ClusterClass"))
+		for _, obj := range out.Objs {
+			g.Expect(obj.GetNamespace()).To(BeEmpty())
+			g.Expect(obj.GetUID()).To(BeEmpty())
+			g.Expect(obj.GetResourceVersion()).To(BeEmpty())
+			g.Expect(obj.GetOwnerReferences()).To(BeEmpty())
+			g.Expect(obj.Object).ToNot(HaveKey("status"))
+		}
+
+		clusterClass := &clusterv1.ClusterClass{}
+		g.Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(out.Objs[6].Object, clusterClass)).To(Succeed())
+		for _, templateRef := range clusterclass.TemplateRefs(clusterClass) {
+			ref := templateRef.Ref
+			g.Expect(ref.Namespace).To(BeEmpty())
+		}
+	})
+	t.Run("fails if the ClusterClass does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		proxy := test.NewFakeProxy().WithObjs(newBundleTestObjs("ns1")...)
+		tc := newTopologyClient(proxy, newInventoryClient(proxy, nil))
+
+		_, err := tc.Export(&TopologyExportInput{Namespace: "ns1", ClassName: "does-not-exist"})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func Test_topologyClient_Import(t *testing.T) {
+	tests := []struct {
+		name          string
+		className     string
+		namePrefix    string
+		dryRun        bool
+		existingObjs  []client.Object
+		modifyBundle  func(objs []unstructured.Unstructured) []unstructured.Unstructured
+		wantClassName string
+		wantPrefix    string
+		wantErr       bool
+	}{
+		{
+			name:          "imports a bundle into the target namespace",
+			wantClassName: "class1",
+		},
+		{
+			name:          "imports a bundle renaming the ClusterClass and the templates",
+			className:     "class2",
+			namePrefix:    "team-a-",
+			wantClassName: "class2",
+			wantPrefix:    "team-a-",
+		},
+		{
+			name:          "does not create objects in dry run",
+			dryRun:        true,
+			wantClassName: "class1",
+		},
+		{
+			name:         "fails if an object already exists",
+			existingObjs: []client.Object{builder.InfrastructureMachineTemplate("ns2", "worker1").Build()},
+			wantErr:      true,
+		},
+		{
+			name: "fails if the bundle does not contain a ClusterClass",
+			modifyBundle: func(objs []unstructured.Unstructured) []unstructured.Unstructured {
+				return objs[:len(objs)-1]
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if a template referenced by the ClusterClass is not included in the bundle",
+			modifyBundle: func(objs []unstructured.Unstructured) []unstructured.Unstructured {
+				return objs[1:]
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the bundle contains objects not referenced by the ClusterClass",
+			modifyBundle: func(objs []unstructured.Unstructured) []unstructured.Unstructured {
+				return append(objs, *builder.InfrastructureMachineTemplate("", "not-referenced").Build())
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			sourceProxy := test.NewFakeProxy().WithObjs(newBundleTestObjs("ns1")...)
+			bundle, err := newTopologyClient(sourceProxy, newInventoryClient(sourceProxy, nil)).
+				Export(&TopologyExportInput{Namespace: "ns1", ClassName: "class1"})
+			g.Expect(err).ToNot(HaveOccurred())
+			objs := bundle.Objs
+			if tt.modifyBundle != nil {
+				objs = tt.modifyBundle(objs)
+			}
+
+			proxy := test.NewFakeProxy().WithObjs(tt.existingObjs...)
+			tc := newTopologyClient(proxy, newInventoryClient(proxy, nil))
+
+			out, err := tc.Import(&TopologyImportInput{
+				Objs:            objs,
+				TargetNamespace: "ns2",
+				ClassName:       tt.className,
+				NamePrefix:      tt.namePrefix,
+				DryRun:          tt.dryRun,
+			})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(out.Objs).To(HaveLen(7))
+
+			clusterClassObj := out.Objs[6]
+			g.Expect(clusterClassObj.GetNamespace()).To(Equal("ns2"))
+			g.Expect(clusterClassObj.GetName()).To(Equal(tt.wantClassName))
+			clusterClass := &clusterv1.ClusterClass{}
+			g.Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(clusterClassObj.Object, clusterClass)).To(Succeed())
+
+			// All the references of the ClusterClass point to the imported templates.
+			importedNames := map[string]bool{}
+			for _, obj := range out.Objs[:6] {
+				g.Expect(obj.GetNamespace()).To(Equal("ns2"))
+				g.Expect(obj.GetName()).To(HavePrefix(tt.wantPrefix))
+				importedNames[bundleObjectKey(obj.GroupVersionKind().GroupKind(), obj.GetName())] = true
+			}
+			for _, templateRef := range clusterclass.TemplateRefs(clusterClass) {
+				ref := templateRef.Ref
+				g.Expect(ref.Namespace).To(Equal("ns2"))
+				g.Expect(importedNames).To(HaveKey(bundleObjectKey(ref.GroupVersionKind().GroupKind(), ref.Name)))
+			}
+
+			c, err := proxy.NewClient()
+			g.Expect(err).ToNot(HaveOccurred())
+			for i := range out.Objs {
+				obj := &unstructured.Unstructured{}
+				obj.SetGroupVersionKind(out.Objs[i].GroupVersionKind())
+				err := c.Get(ctx, client.ObjectKeyFromObject(&out.Objs[i]), obj)
+				if tt.dryRun {
+					g.Expect(err).To(HaveOccurred())
+					continue
+				}
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
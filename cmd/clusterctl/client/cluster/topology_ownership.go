@@ -0,0 +1,271 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
+)
+
+// TopologyOwnershipInput defines the input for the Ownership function.
+type TopologyOwnershipInput struct {
+	// Namespace is the namespace of the Clusters to inspect.
+	// If empty, Clusters in all the namespaces are inspected.
+	Namespace string
+
+	// ClusterName is the name of the Cluster to inspect.
+	// If empty, all the Clusters with a managed topology are inspected.
+	ClusterName string
+}
+
+// TopologyOwnershipOutput defines the output of the Ownership function.
+type TopologyOwnershipOutput struct {
+	// Objects is the list of topology owned objects along with the ownership
+	// of their fields.
+	Objects []ObjectOwnership
+}
+
+// ObjectOwnership defines the field ownership of a topology owned object.
+type ObjectOwnership struct {
+	// Cluster is the Cluster the object belongs to.
+	Cluster client.ObjectKey
+
+	// Object is a reference to the object.
+	Object corev1.ObjectReference
+
+	// Fields is the list of fields managed on the object, sorted by path.
+	Fields []FieldOwnership
+}
+
+// FieldOwnership defines the managers of a single field.
+type FieldOwnership struct {
+	// Path is the path of the field, e.g. `.spec.replicas`.
+	Path string
+
+	// Managers is the list of managers of the field.
+	// NOTE: A field can be owned by more than one manager when using server side apply.
+	Managers []FieldManager
+}
+
+// FieldManager defines a manager of a field as recorded in managedFields.
+type FieldManager struct {
+	// Manager is the name of the field manager.
+	Manager string
+
+	// Operation is the type of operation which lead to the ownership, either Apply or Update.
+	Operation metav1.ManagedFieldsOperationType
+
+	// Subresource is the subresource used to update the field, if any.
+	Subresource string
+}
+
+// IsTopology returns true if the manager is the topology controller.
+func (m FieldManager) IsTopology() bool {
+	return m.Manager == structuredmerge.TopologyManagerName
+}
+
+// String returns a human readable representation of the FieldManager.
+func (m FieldManager) String() string {
+	if m.Subresource != "" {
+		return fmt.Sprintf("%s (%s, %s)", m.Manager, m.Operation, m.Subresource)
+	}
+	return fmt.Sprintf("%s (%s)", m.Manager, m.Operation)
+}
+
+// Ownership reads managedFields of the objects owned by the topology of one or more Clusters and
+// reports, for each field, which managers own it.
+// This helps to understand which fields are co-owned by the topology controller and other managers
+// e.g. users or autoscalers, thus giving insights on why server side apply conflicts happen.
+func (t *topologyClient) Ownership(in *TopologyOwnershipInput) (*TopologyOwnershipOutput, error) {
+	ctx := context.TODO()
+
+	c, err := t.proxy.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := []clusterv1.Cluster{}
+	if in.ClusterName != "" {
+		cluster := &clusterv1.Cluster{}
+		if err := c.Get(ctx, client.ObjectKey{Namespace: in.Namespace, Name: in.ClusterName}, cluster); err != nil {
+			return nil, errors.Wrapf(err, "failed to get Cluster %s/%s", in.Namespace, in.ClusterName)
+		}
+		if cluster.Spec.Topology == nil {
+			return nil, errors.Errorf("Cluster %s/%s does not have a managed topology", in.Namespace, in.ClusterName)
+		}
+		clusters = append(clusters, *cluster)
+	} else {
+		clusterList := &clusterv1.ClusterList{}
+		if err := c.List(ctx, clusterList, client.InNamespace(in.Namespace)); err != nil {
+			return nil, errors.Wrap(err, "failed to list Clusters")
+		}
+		for _, cluster := range clusterList.Items {
+			if cluster.Spec.Topology == nil {
+				continue
+			}
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	res := &TopologyOwnershipOutput{}
+	for i := range clusters {
+		cluster := &clusters[i]
+		refs, err := topologyOwnedRefs(ctx, c, cluster)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get objects owned by the topology of Cluster %s", client.ObjectKeyFromObject(cluster))
+		}
+
+		for _, ref := range refs {
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(ref.APIVersion)
+			obj.SetKind(ref.Kind)
+			if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+				if apierrors.IsNotFound(err) {
+					// The object might not be created yet or it might have been deleted in the meantime.
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+			}
+
+			fields, err := fieldOwnership(obj)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to compute field ownership for %s %s/%s", ref.Kind, ref.Namespace, ref.Name)
+			}
+
+			res.Objects = append(res.Objects, ObjectOwnership{
+				Cluster: client.ObjectKeyFromObject(cluster),
+				Object:  *ref,
+				Fields:  fields,
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// topologyOwnedRefs returns references to the Cluster and to all the objects managed by its topology.
+func topologyOwnedRefs(ctx context.Context, c client.Reader, cluster *clusterv1.Cluster) ([]*corev1.ObjectReference, error) {
+	refs := []*corev1.ObjectReference{
+		{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+			Namespace:  cluster.Namespace,
+			Name:       cluster.Name,
+		},
+	}
+
+	if cluster.Spec.InfrastructureRef != nil {
+		refs = append(refs, cluster.Spec.InfrastructureRef)
+	}
+
+	if cluster.Spec.ControlPlaneRef != nil {
+		refs = append(refs, cluster.Spec.ControlPlaneRef)
+
+		controlPlane := &unstructured.Unstructured{}
+		controlPlane.SetAPIVersion(cluster.Spec.ControlPlaneRef.APIVersion)
+		controlPlane.SetKind(cluster.Spec.ControlPlaneRef.Kind)
+		if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Spec.ControlPlaneRef.Namespace, Name: cluster.Spec.ControlPlaneRef.Name}, controlPlane); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, errors.Wrapf(err, "failed to get %s %s/%s", cluster.Spec.ControlPlaneRef.Kind, cluster.Spec.ControlPlaneRef.Namespace, cluster.Spec.ControlPlaneRef.Name)
+			}
+		} else {
+			// NOTE: The control plane machine template is optional in the contract.
+			if infrastructureRef, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(controlPlane); err == nil {
+				refs = append(refs, infrastructureRef)
+			}
+		}
+	}
+
+	mdList := &clusterv1.MachineDeploymentList{}
+	if err := c.List(ctx, mdList,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+		client.HasLabels{clusterv1.ClusterTopologyOwnedLabel},
+	); err != nil {
+		return nil, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	sort.Slice(mdList.Items, func(i, j int) bool { return mdList.Items[i].Name < mdList.Items[j].Name })
+	for i := range mdList.Items {
+		md := &mdList.Items[i]
+		refs = append(refs, &corev1.ObjectReference{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "MachineDeployment",
+			Namespace:  md.Namespace,
+			Name:       md.Name,
+		})
+		if md.Spec.Template.Spec.Bootstrap.ConfigRef != nil {
+			refs = append(refs, md.Spec.Template.Spec.Bootstrap.ConfigRef)
+		}
+		refs = append(refs, &md.Spec.Template.Spec.InfrastructureRef)
+	}
+
+	// Make sure that all the references have a namespace.
+	for _, ref := range refs {
+		if ref.Namespace == "" {
+			ref.Namespace = cluster.Namespace
+		}
+	}
+	return refs, nil
+}
+
+// fieldOwnership computes the ownership of each field of an object using its managedFields.
+func fieldOwnership(obj *unstructured.Unstructured) ([]FieldOwnership, error) {
+	managersByPath := map[string][]FieldManager{}
+	for _, managedField := range obj.GetManagedFields() {
+		if managedField.FieldsV1 == nil {
+			continue
+		}
+
+		fieldSet := &fieldpath.Set{}
+		if err := fieldSet.FromJSON(bytes.NewReader(managedField.FieldsV1.Raw)); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse managed fields for manager %q", managedField.Manager)
+		}
+
+		manager := FieldManager{
+			Manager:     managedField.Manager,
+			Operation:   managedField.Operation,
+			Subresource: managedField.Subresource,
+		}
+		fieldSet.Leaves().Iterate(func(path fieldpath.Path) {
+			managersByPath[path.String()] = append(managersByPath[path.String()], manager)
+		})
+	}
+
+	fields := make([]FieldOwnership, 0, len(managersByPath))
+	for path, managers := range managersByPath {
+		fields = append(fields, FieldOwnership{
+			Path:     path,
+			Managers: managers,
+		})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields, nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	fakeinfrastructure "sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test/providers/infrastructure"
+)
+
+func Test_fieldOwnership(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   "capi-topology",
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:version":{}}}}}`)},
+		},
+		{
+			Manager:   "cluster-autoscaler",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		},
+		{
+			Manager:     "manager",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:replicas":{}}}`)},
+		},
+	})
+
+	got, err := fieldOwnership(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal([]FieldOwnership{
+		{
+			Path: ".spec.replicas",
+			Managers: []FieldManager{
+				{Manager: "capi-topology", Operation: metav1.ManagedFieldsOperationApply},
+				{Manager: "cluster-autoscaler", Operation: metav1.ManagedFieldsOperationUpdate},
+			},
+		},
+		{
+			Path: ".spec.template.spec.version",
+			Managers: []FieldManager{
+				{Manager: "capi-topology", Operation: metav1.ManagedFieldsOperationApply},
+			},
+		},
+		{
+			Path: ".status.replicas",
+			Managers: []FieldManager{
+				{Manager: "manager", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status"},
+			},
+		},
+	}))
+	g.Expect(got[0].Managers[0].IsTopology()).To(BeTrue())
+	g.Expect(got[0].Managers[1].IsTopology()).To(BeFalse())
+}
+
+func Test_topologyClient_Ownership(t *testing.T) {
+	infraCluster := &fakeinfrastructure.GenericInfrastructureCluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: fakeinfrastructure.GroupVersion.String(),
+			Kind:       "GenericInfrastructureCluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "infra-cluster1",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "capi-topology",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{"f:foo":{}}}}`)},
+				},
+			},
+		},
+	}
+	topologyCluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "cluster1",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "capi-topology",
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:infrastructureRef":{}}}`)},
+				},
+			},
+		},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{Class: "class1", Version: "v1.25.0"},
+			InfrastructureRef: &corev1.ObjectReference{
+				APIVersion: fakeinfrastructure.GroupVersion.String(),
+				Kind:       "GenericInfrastructureCluster",
+				Namespace:  "ns1",
+				Name:       "infra-cluster1",
+			},
+		},
+	}
+	md := &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "MachineDeployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "md1",
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName:          "cluster1",
+				clusterv1.ClusterTopologyOwnedLabel: "",
+			},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: "cluster1",
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "cluster1",
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: fakeinfrastructure.GroupVersion.String(),
+						Kind:       "GenericInfrastructureMachineTemplate",
+						Name:       "not-existing",
+					},
+				},
+			},
+		},
+	}
+	nonTopologyCluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "cluster2",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		in          *TopologyOwnershipInput
+		wantObjects []corev1.ObjectReference
+		wantErr     bool
+	}{
+		{
+			name: "report all the clusters with a managed topology",
+			in:   &TopologyOwnershipInput{},
+			wantObjects: []corev1.ObjectReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Namespace: "ns1", Name: "cluster1"},
+				{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureCluster", Namespace: "ns1", Name: "infra-cluster1"},
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineDeployment", Namespace: "ns1", Name: "md1"},
+			},
+		},
+		{
+			name: "report a single cluster",
+			in:   &TopologyOwnershipInput{Namespace: "ns1", ClusterName: "cluster1"},
+			wantObjects: []corev1.ObjectReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Namespace: "ns1", Name: "cluster1"},
+				{APIVersion: fakeinfrastructure.GroupVersion.String(), Kind: "GenericInfrastructureCluster", Namespace: "ns1", Name: "infra-cluster1"},
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineDeployment", Namespace: "ns1", Name: "md1"},
+			},
+		},
+		{
+			name:    "fails for a cluster without a managed topology",
+			in:      &TopologyOwnershipInput{Namespace: "ns1", ClusterName: "cluster2"},
+			wantErr: true,
+		},
+		{
+			name:    "fails for a cluster that does not exist",
+			in:      &TopologyOwnershipInput{Namespace: "ns1", ClusterName: "cluster3"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			proxy := test.NewFakeProxy().WithObjs(infraCluster, topologyCluster, md, nonTopologyCluster)
+			tc := newTopologyClient(proxy, newInventoryClient(proxy, nil))
+
+			out, err := tc.Ownership(tt.in)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			gotObjects := []corev1.ObjectReference{}
+			for _, o := range out.Objects {
+				g.Expect(o.Cluster).To(Equal(client.ObjectKeyFromObject(topologyCluster)))
+				gotObjects = append(gotObjects, o.Object)
+			}
+			g.Expect(gotObjects).To(Equal(tt.wantObjects))
+
+			g.Expect(out.Objects[0].Fields).To(ConsistOf(FieldOwnership{
+				Path:     ".spec.infrastructureRef",
+				Managers: []FieldManager{{Manager: "capi-topology", Operation: metav1.ManagedFieldsOperationApply}},
+			}))
+		})
+	}
+}
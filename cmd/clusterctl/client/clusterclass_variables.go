@@ -0,0 +1,173 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+	"sigs.k8s.io/cluster-api/internal/topology/variables"
+)
+
+// TopologyVariableResolver returns the value for a required variable defined in a ClusterClass which is
+// not set in the topology of a Cluster in the workload cluster template, e.g. by prompting the user.
+// The returned value is validated against the variable schema before being added to the Cluster topology.
+type TopologyVariableResolver func(clusterName string, variable clusterv1.ClusterClassVariable) (*apiextensionsv1.JSON, error)
+
+// resolveTopologyVariables validates the topology variables of the Clusters in a workload cluster template against
+// the variable schemas defined in the corresponding ClusterClass, in order to surface invalid or missing variables
+// before the template is applied. If a resolver is provided, it is used to get values for missing required variables.
+// NOTE: ClusterClasses are read from the template or, if not included in the template, from the management cluster;
+// the validation is skipped for Clusters using a ClusterClass which is not available, e.g. when generating a template
+// without a management cluster.
+func resolveTopologyVariables(template Template, clusterClient cluster.Client, resolver TopologyVariableResolver) error {
+	var c client.Client
+	if err := clusterClient.Proxy().CheckClusterAvailable(); err == nil {
+		c, err = clusterClient.Proxy().NewClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	objs := template.Objs()
+	for i := range objs {
+		obj := &objs[i]
+		if obj.GroupVersionKind().GroupKind() != clusterv1.GroupVersion.WithKind("Cluster").GroupKind() {
+			continue
+		}
+		cluster := &clusterv1.Cluster{}
+		if err := scheme.Scheme.Convert(obj, cluster, nil); err != nil {
+			return errors.Wrap(err, "failed to convert object to Cluster")
+		}
+		if cluster.Spec.Topology == nil {
+			continue
+		}
+
+		clusterClass, err := getClusterClass(template, c, cluster.Spec.Topology.Class, cluster.Namespace)
+		if err != nil {
+			return err
+		}
+		if clusterClass == nil {
+			continue
+		}
+
+		// Get values for the required variables which are not set in the Cluster and which are not going to be defaulted.
+		resolved := false
+		if resolver != nil {
+			for _, variable := range clusterClass.Spec.Variables {
+				if !variable.Required || variable.Schema.OpenAPIV3Schema.Default != nil || hasTopologyVariable(cluster.Spec.Topology.Variables, variable.Name) {
+					continue
+				}
+				value, err := resolver(cluster.Name, variable)
+				if err != nil {
+					return errors.Wrapf(err, "failed to get value for variable %q of Cluster %q", variable.Name, cluster.Name)
+				}
+				cluster.Spec.Topology.Variables = append(cluster.Spec.Topology.Variables, clusterv1.ClusterVariable{
+					Name:  variable.Name,
+					Value: *value,
+				})
+				resolved = true
+			}
+		}
+
+		// Validate the variables the same way the Cluster webhook does, i.e. after defaulting.
+		fldPath := field.NewPath("spec", "topology", "variables")
+		defaulted, errs := variables.DefaultClusterVariables(cluster.Spec.Topology.Variables, clusterClass.Spec.Variables, fldPath)
+		if len(errs) == 0 {
+			errs = variables.ValidateClusterVariables(defaulted, clusterClass.Spec.Variables, fldPath)
+		}
+		if len(errs) > 0 {
+			return errors.Wrapf(errs.ToAggregate(), "invalid variables for Cluster %q using ClusterClass %q", cluster.Name, clusterClass.Name)
+		}
+
+		if resolved {
+			if err := setTopologyVariables(obj, cluster.Spec.Topology.Variables); err != nil {
+				return errors.Wrapf(err, "failed to set variables for Cluster %q", cluster.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// getClusterClass returns a ClusterClass from the template or, if not included in the template, from the
+// management cluster; nil is returned if the ClusterClass is not available.
+func getClusterClass(template Template, c client.Client, name, namespace string) (*clusterv1.ClusterClass, error) {
+	if namespace == "" {
+		namespace = template.TargetNamespace()
+	}
+
+	for i := range template.Objs() {
+		obj := template.Objs()[i]
+		if obj.GroupVersionKind().GroupKind() != clusterv1.GroupVersion.WithKind("ClusterClass").GroupKind() {
+			continue
+		}
+		if obj.GetName() != name || (obj.GetNamespace() != "" && obj.GetNamespace() != namespace) {
+			continue
+		}
+		clusterClass := &clusterv1.ClusterClass{}
+		if err := scheme.Scheme.Convert(&obj, clusterClass, nil); err != nil {
+			return nil, errors.Wrap(err, "failed to convert object to ClusterClass")
+		}
+		return clusterClass, nil
+	}
+
+	if c == nil {
+		return nil, nil
+	}
+	clusterClass := &clusterv1.ClusterClass{}
+	if err := c.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: namespace}, clusterClass); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get ClusterClass %q from the cluster", name)
+	}
+	return clusterClass, nil
+}
+
+func hasTopologyVariable(clusterVariables []clusterv1.ClusterVariable, name string) bool {
+	for _, variable := range clusterVariables {
+		if variable.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setTopologyVariables sets the topology variables of a Cluster object in unstructured format, leaving
+// all the other fields untouched.
+func setTopologyVariables(obj *unstructured.Unstructured, clusterVariables []clusterv1.ClusterVariable) error {
+	values := make([]interface{}, 0, len(clusterVariables))
+	for i := range clusterVariables {
+		value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&clusterVariables[i])
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+	return unstructured.SetNestedSlice(obj.Object, values, "spec", "topology", "variables")
+}
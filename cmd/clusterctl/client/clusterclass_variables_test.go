@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+func TestResolveTopologyVariables(t *testing.T) {
+	clusterClassWithVariablesYAML := []byte(fmt.Sprintf("apiVersion: %s\n", clusterv1.GroupVersion.String()) +
+		"kind: ClusterClass\n" +
+		"metadata:\n" +
+		"  name: dev\n" +
+		"  namespace: ns1\n" +
+		"spec:\n" +
+		"  variables:\n" +
+		"  - name: region\n" +
+		"    required: true\n" +
+		"    schema:\n" +
+		"      openAPIV3Schema:\n" +
+		"        type: string\n" +
+		"  - name: replicas\n" +
+		"    required: true\n" +
+		"    schema:\n" +
+		"      openAPIV3Schema:\n" +
+		"        type: integer\n" +
+		"        default: 1\n")
+	clusterWithVariablesYAML := func(variables string) []byte {
+		return []byte(string(mangedTopologyTemplateYAML("ns1", "cluster1", "dev")) + "\n" + variables)
+	}
+
+	clusterClass := &clusterv1.ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dev",
+			Namespace: "ns1",
+		},
+		Spec: clusterv1.ClusterClassSpec{
+			Variables: []clusterv1.ClusterClassVariable{
+				{
+					Name:     "region",
+					Required: true,
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"},
+					},
+				},
+			},
+		},
+	}
+
+	regionResolver := func(value string) TopologyVariableResolver {
+		return func(clusterName string, variable clusterv1.ClusterClassVariable) (*apiextensionsv1.JSON, error) {
+			return &apiextensionsv1.JSON{Raw: []byte(value)}, nil
+		}
+	}
+
+	tests := []struct {
+		name          string
+		templateYAML  []byte
+		objs          []client.Object
+		resolver      TopologyVariableResolver
+		wantVariables []interface{}
+		wantErr       bool
+	}{
+		{
+			name:         "Pass if the ClusterClass is not available",
+			templateYAML: clusterWithVariablesYAML(""),
+			wantErr:      false,
+		},
+		{
+			name:         "Pass if the variables are valid according to a ClusterClass in the template",
+			templateYAML: utilyaml.JoinYaml(clusterClassWithVariablesYAML, clusterWithVariablesYAML("    variables:\n    - name: region\n      value: us-east-1")),
+			wantErr:      false,
+		},
+		{
+			name:         "Fail if a required variable is missing",
+			templateYAML: utilyaml.JoinYaml(clusterClassWithVariablesYAML, clusterWithVariablesYAML("")),
+			wantErr:      true,
+		},
+		{
+			name:         "Fail if a variable has an invalid type",
+			templateYAML: utilyaml.JoinYaml(clusterClassWithVariablesYAML, clusterWithVariablesYAML("    variables:\n    - name: region\n      value: us-east-1\n    - name: replicas\n      value: three")),
+			wantErr:      true,
+		},
+		{
+			name:         "Fail if a variable is invalid according to a ClusterClass in the management cluster",
+			templateYAML: clusterWithVariablesYAML("    variables:\n    - name: region\n      value: 1"),
+			objs:         []client.Object{clusterClass},
+			wantErr:      true,
+		},
+		{
+			name:         "Resolve the required variables which are missing",
+			templateYAML: utilyaml.JoinYaml(clusterClassWithVariablesYAML, clusterWithVariablesYAML("")),
+			resolver:     regionResolver(`"us-east-1"`),
+			wantVariables: []interface{}{
+				map[string]interface{}{"name": "region", "value": "us-east-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name:         "Fail if a resolved variable is invalid",
+			templateYAML: utilyaml.JoinYaml(clusterClassWithVariablesYAML, clusterWithVariablesYAML("")),
+			resolver:     regionResolver(`1`),
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			template, err := repository.NewTemplate(repository.TemplateInput{
+				RawArtifact:           tt.templateYAML,
+				ConfigVariablesClient: test.NewFakeVariableClient(),
+				Processor:             yaml.NewSimpleProcessor(),
+				TargetNamespace:       "ns1",
+			})
+			g.Expect(err).NotTo(HaveOccurred())
+
+			clusterClient := newFakeCluster(cluster.Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}, newFakeConfig()).WithObjs(tt.objs...)
+
+			err = resolveTopologyVariables(template, clusterClient, tt.resolver)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tt.wantVariables != nil {
+				var clusterObj *unstructured.Unstructured
+				for i := range template.Objs() {
+					if template.Objs()[i].GetKind() == "Cluster" {
+						clusterObj = &template.Objs()[i]
+					}
+				}
+				g.Expect(clusterObj).NotTo(BeNil())
+				variables, _, err := unstructured.NestedSlice(clusterObj.Object, "spec", "topology", "variables")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(variables).To(Equal(tt.wantVariables))
+			}
+		})
+	}
+}
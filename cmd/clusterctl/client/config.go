@@ -152,6 +152,11 @@ type GetClusterTemplateOptions struct {
 	// YamlProcessor defines the yaml processor to use for the cluster
 	// template processing. If not defined, SimpleProcessor will be used.
 	YamlProcessor Processor
+
+	// TopologyVariableResolver defines the func to be used for getting values for the required ClusterClass variables
+	// which are not set in the topology of the Clusters in the template. If not defined, missing required variables
+	// are reported as an error.
+	TopologyVariableResolver TopologyVariableResolver
 }
 
 // numSources return the number of template sources currently set on a GetClusterTemplateOptions.
@@ -243,6 +248,23 @@ func (c *clusterctlClient) GetClusterTemplate(options GetClusterTemplateOptions)
 	}
 
 	// Gets the workload cluster template from the selected source
+	template, err := c.getTemplate(clusterClient, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validates the topology variables of the Clusters in the template against the ClusterClass variable schemas,
+	// so invalid or missing variables are surfaced now instead of when applying the template.
+	if !options.ListVariablesOnly {
+		if err := resolveTopologyVariables(template, clusterClient, options.TopologyVariableResolver); err != nil {
+			return nil, err
+		}
+	}
+	return template, nil
+}
+
+// getTemplate returns a workload cluster template from the source selected in the options.
+func (c *clusterctlClient) getTemplate(clusterClient cluster.Client, options GetClusterTemplateOptions) (Template, error) {
 	if options.ProviderRepositorySource != nil {
 		// Ensure this command only runs against management clusters with the current Cluster API contract.
 		// NOTE: This command tolerates also not existing cluster (Kubeconfig.Path=="") or clusters not yet initialized in order to allow
@@ -26,6 +26,7 @@ import (
 // 2. The configuration of the providers (name, type and URL of the provider repository)
 // 3. Variables used when installing providers/creating clusters. Variables can be read from the environment or from the config file
 // 4. The configuration about image overrides.
+// 5. The configuration about deployment overrides.
 type Client interface {
 	// CertManager provide access to the cert-manager configurations.
 	CertManager() CertManagerClient
@@ -38,6 +39,9 @@ type Client interface {
 
 	// ImageMeta provide access to image meta configurations.
 	ImageMeta() ImageMetaClient
+
+	// DeploymentMeta provide access to deployment meta configurations.
+	DeploymentMeta() DeploymentMetaClient
 }
 
 // configClient implements Client.
@@ -64,6 +68,10 @@ func (c *configClient) ImageMeta() ImageMetaClient {
 	return newImageMetaClient(c.reader)
 }
 
+func (c *configClient) DeploymentMeta() DeploymentMetaClient {
+	return newDeploymentMetaClient(c.reader)
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*configClient)
 
@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	deploymentsConfigKey = "deployments"
+	allDeploymentConfig  = "all"
+)
+
+// DeploymentMetaClient has methods to work with deployment meta configurations.
+type DeploymentMetaClient interface {
+	// AlterPodSpec alters the pod spec of a provider deployment according to the current deployment override configurations.
+	AlterPodSpec(component string, podSpec *corev1.PodSpec) error
+}
+
+// deploymentMetaClient implements DeploymentMetaClient.
+type deploymentMetaClient struct {
+	reader              Reader
+	deploymentMetaCache map[string]*deploymentMeta
+}
+
+// ensure deploymentMetaClient implements DeploymentMetaClient.
+var _ DeploymentMetaClient = &deploymentMetaClient{}
+
+func newDeploymentMetaClient(reader Reader) *deploymentMetaClient {
+	return &deploymentMetaClient{
+		reader:              reader,
+		deploymentMetaCache: map[string]*deploymentMeta{},
+	}
+}
+
+func (p *deploymentMetaClient) AlterPodSpec(component string, podSpec *corev1.PodSpec) error {
+	// Gets the deployment meta that applies to the selected component; if none, returns early
+	meta, err := p.getDeploymentMeta(component)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return nil
+	}
+
+	// Apply the deployment meta to the pod spec
+	meta.ApplyToPodSpec(podSpec)
+	return nil
+}
+
+// getDeploymentMeta returns the deployment meta that applies to the selected component.
+func (p *deploymentMetaClient) getDeploymentMeta(component string) (*deploymentMeta, error) {
+	// if the deployment meta for the component is already known, return it
+	if dm, ok := p.deploymentMetaCache[component]; ok {
+		return dm, nil
+	}
+
+	// Otherwise read the deployment override configurations.
+	// NOTE: The configuration is read as a generic map and then converted using JSON, so fields with custom
+	// unmarshalling logic, like resource quantities, can be read.
+	var rawMeta map[string]interface{}
+	if err := p.reader.UnmarshalKey(deploymentsConfigKey, &rawMeta); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal deployment override configurations")
+	}
+
+	// If there are not deployment override configurations, return.
+	if rawMeta == nil {
+		p.deploymentMetaCache[component] = nil
+		return nil, nil
+	}
+
+	var meta map[string]deploymentMeta
+	data, err := json.Marshal(rawMeta)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert deployment override configurations")
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal deployment override configurations")
+	}
+
+	// Gets the deployment configuration for:
+	//	- all the components,
+	//	- the selected component
+	//	and returns the union of all the above.
+	m := &deploymentMeta{}
+	if allMeta, ok := meta[allDeploymentConfig]; ok {
+		m.Union(&allMeta)
+	}
+
+	if componentMeta, ok := meta[component]; ok {
+		m.Union(&componentMeta)
+	}
+	p.deploymentMetaCache[component] = m
+
+	return m, nil
+}
+
+// deploymentMeta allows to define transformations to apply to the deployments contained in the YAML manifests.
+type deploymentMeta struct {
+	// NodeSelector is added to the node selector of the deployments.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are added to the tolerations of the deployments.
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Resources sets the resource requests and limits of all the containers in the deployments.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// Union allows to merge two deploymentMeta transformation; in case both the deploymentMeta defines new values for the same field,
+// the other transformation takes precedence on the existing one.
+func (d *deploymentMeta) Union(other *deploymentMeta) {
+	d.NodeSelector = mergeStringMap(d.NodeSelector, other.NodeSelector)
+	d.Tolerations = mergeTolerations(d.Tolerations, other.Tolerations)
+	d.Resources.Requests = mergeResourceList(d.Resources.Requests, other.Resources.Requests)
+	d.Resources.Limits = mergeResourceList(d.Resources.Limits, other.Resources.Limits)
+}
+
+// ApplyToPodSpec changes a pod spec applying the transformations defined in the current deploymentMeta.
+func (d *deploymentMeta) ApplyToPodSpec(podSpec *corev1.PodSpec) {
+	podSpec.NodeSelector = mergeStringMap(podSpec.NodeSelector, d.NodeSelector)
+	podSpec.Tolerations = mergeTolerations(podSpec.Tolerations, d.Tolerations)
+	for i := range podSpec.Containers {
+		c := &podSpec.Containers[i]
+		c.Resources.Requests = mergeResourceList(c.Resources.Requests, d.Resources.Requests)
+		c.Resources.Limits = mergeResourceList(c.Resources.Limits, d.Resources.Limits)
+	}
+}
+
+func mergeStringMap(base, other map[string]string) map[string]string {
+	if len(other) == 0 {
+		return base
+	}
+	if base == nil {
+		base = map[string]string{}
+	}
+	for k, v := range other {
+		base[k] = v
+	}
+	return base
+}
+
+func mergeTolerations(base, other []corev1.Toleration) []corev1.Toleration {
+	for _, t := range other {
+		found := false
+		for _, b := range base {
+			if reflect.DeepEqual(b, t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			base = append(base, t)
+		}
+	}
+	return base
+}
+
+func mergeResourceList(base, other corev1.ResourceList) corev1.ResourceList {
+	if len(other) == 0 {
+		return base
+	}
+	if base == nil {
+		base = corev1.ResourceList{}
+	}
+	for k, v := range other {
+		base[k] = v.DeepCopy()
+	}
+	return base
+}
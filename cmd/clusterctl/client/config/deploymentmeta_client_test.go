@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_deploymentMetaClient_AlterPodSpec(t *testing.T) {
+	deploymentsConfig := `
+all:
+  nodeSelector:
+    kubernetes.io/os: linux
+  tolerations:
+  - key: node-role.kubernetes.io/control-plane
+    effect: NoSchedule
+infrastructure-foo:
+  nodeSelector:
+    kubernetes.io/os: foo
+  resources:
+    limits:
+      cpu: 500m
+      memory: 256Mi
+`
+
+	tests := []struct {
+		name      string
+		reader    Reader
+		component string
+		podSpec   corev1.PodSpec
+		want      corev1.PodSpec
+		wantErr   bool
+	}{
+		{
+			name:      "no deployment config: pod spec should not be changed",
+			reader:    test.NewFakeReader(),
+			component: "infrastructure-foo",
+			podSpec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager"}},
+			},
+			want: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager"}},
+			},
+		},
+		{
+			name:      "deployment config for all: pod spec should be changed",
+			reader:    test.NewFakeReader().WithVar(deploymentsConfigKey, deploymentsConfig),
+			component: "bootstrap-bar",
+			podSpec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "manager"}},
+			},
+			want: corev1.PodSpec{
+				NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+				Tolerations: []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+				},
+				Containers: []corev1.Container{{Name: "manager"}},
+			},
+		},
+		{
+			name:      "deployment config for all and for the component: pod spec should be changed, component config takes precedence",
+			reader:    test.NewFakeReader().WithVar(deploymentsConfigKey, deploymentsConfig),
+			component: "infrastructure-foo",
+			podSpec: corev1.PodSpec{
+				Tolerations: []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+				},
+				Containers: []corev1.Container{{
+					Name: "manager",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("100m"),
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("10m"),
+						},
+					},
+				}},
+			},
+			want: corev1.PodSpec{
+				NodeSelector: map[string]string{"kubernetes.io/os": "foo"},
+				Tolerations: []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+				},
+				Containers: []corev1.Container{{
+					Name: "manager",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("256Mi"),
+						},
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("10m"),
+						},
+					},
+				}},
+			},
+		},
+		{
+			name:      "invalid deployment config: error",
+			reader:    test.NewFakeReader().WithVar(deploymentsConfigKey, "all:\n  resources:\n    limits:\n      cpu: foo\n"),
+			component: "infrastructure-foo",
+			podSpec:   corev1.PodSpec{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			p := newDeploymentMetaClient(tt.reader)
+
+			podSpec := tt.podSpec.DeepCopy()
+			err := p.AlterPodSpec(tt.component, podSpec)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(podSpec.NodeSelector).To(Equal(tt.want.NodeSelector))
+			g.Expect(podSpec.Tolerations).To(Equal(tt.want.Tolerations))
+			g.Expect(podSpec.Containers).To(HaveLen(len(tt.want.Containers)))
+			for i := range tt.want.Containers {
+				g.Expect(podSpec.Containers[i].Resources.Limits).To(HaveLen(len(tt.want.Containers[i].Resources.Limits)))
+				for k, v := range tt.want.Containers[i].Resources.Limits {
+					g.Expect(podSpec.Containers[i].Resources.Limits[k].Equal(v)).To(BeTrue())
+				}
+				g.Expect(podSpec.Containers[i].Resources.Requests).To(HaveLen(len(tt.want.Containers[i].Resources.Requests)))
+				for k, v := range tt.want.Containers[i].Resources.Requests {
+					g.Expect(podSpec.Containers[i].Resources.Requests[k].Equal(v)).To(BeTrue())
+				}
+			}
+		})
+	}
+}
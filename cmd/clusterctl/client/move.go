@@ -44,6 +44,11 @@ type MoveOptions struct {
 	// ToDirectory save configuration to directory.
 	ToDirectory string
 
+	// ToNamespace is the namespace where the objects describing the workload cluster are moved in the target
+	// management cluster. If unspecified, objects are moved to the same namespace they exist in the source
+	// management cluster.
+	ToNamespace string
+
 	// DryRun means the move action is a dry run, no real action will be performed.
 	DryRun bool
 }
@@ -82,6 +87,11 @@ func (c *clusterctlClient) Move(options MoveOptions) error {
 		return errors.Errorf("can't set both FromDirectory and ToDirectory")
 	}
 
+	// Moving to another namespace is supported only when moving objects between management clusters.
+	if options.ToNamespace != "" && (options.FromDirectory != "" || options.ToDirectory != "") {
+		return errors.Errorf("can't set ToNamespace together with FromDirectory or ToDirectory")
+	}
+
 	if !options.DryRun &&
 		options.FromDirectory == "" &&
 		options.ToDirectory == "" &&
@@ -122,7 +132,12 @@ func (c *clusterctlClient) move(options MoveOptions) error {
 		}
 	}
 
-	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun)
+	var mutators []cluster.ResourceMutatorFunc
+	if options.ToNamespace != "" && options.ToNamespace != options.Namespace {
+		mutators = append(mutators, cluster.NewNamespaceMutator(options.Namespace, options.ToNamespace))
+	}
+
+	return fromCluster.ObjectMover().Move(options.Namespace, toCluster, options.DryRun, mutators...)
 }
 
 func (c *clusterctlClient) fromDirectory(options MoveOptions) error {
@@ -93,6 +93,34 @@ func Test_clusterctlClient_Move(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "does not return error if ToNamespace is set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					Namespace:      "foo",
+					ToNamespace:    "bar",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if both ToNamespace and ToDirectory are set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					ToDirectory: "/var/cache/toDirectory",
+					ToNamespace: "bar",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "returns an error if neither FromDirectory, ToDirectory, or ToKubeconfig is set",
 			fields: fields{
@@ -298,7 +326,7 @@ type fakeObjectMover struct {
 	fromDirectoryErr error
 }
 
-func (f *fakeObjectMover) Move(_ string, _ cluster.Client, _ bool) error {
+func (f *fakeObjectMover) Move(_ string, _ cluster.Client, _ bool, _ ...cluster.ResourceMutatorFunc) error {
 	return f.moveErr
 }
 
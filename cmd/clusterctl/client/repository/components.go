@@ -23,6 +23,7 @@ import (
 	"github.com/pkg/errors"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
@@ -192,6 +193,8 @@ type ComponentsInput struct {
 // 3. Ensure all the provider components are deployed in the target namespace (apply only to namespaced objects)
 // 4. Ensure all the ClusterRoleBinding which are referencing namespaced objects have the name prefixed with the namespace name
 // 5. Adds labels to all the components in order to allow easy identification of the provider objects.
+//
+// Image and deployment overrides defined in the clusterctl configuration are applied as well.
 func NewComponents(input ComponentsInput) (Components, error) {
 	variables, err := input.Processor.GetVariables(input.RawYaml)
 	if err != nil {
@@ -222,6 +225,14 @@ func NewComponents(input ComponentsInput) (Components, error) {
 		return nil, errors.Wrap(err, "failed to apply image overrides")
 	}
 
+	// Apply deployment overrides, if defined
+	objs, err = util.FixDeployments(objs, func(podSpec *corev1.PodSpec) error {
+		return input.ConfigClient.DeploymentMeta().AlterPodSpec(input.Provider.ManifestLabel(), podSpec)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply deployment overrides")
+	}
+
 	// Inspect the list of objects for the images required by the provider component.
 	images, err := util.InspectImages(objs)
 	if err != nil {
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+// SupportBundleOptions define options for SupportBundle.
+type SupportBundleOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace is the namespace of the Cluster. If empty, the current namespace is used.
+	Namespace string
+
+	// ClusterName is the name of the Cluster.
+	ClusterName string
+
+	// LogsTailLines is the number of lines of the controller logs to collect; all the lines are collected if nil.
+	LogsTailLines *int64
+
+	// IncludeWorkloadCluster, if true, adds a summary of the Nodes and Pods of the workload cluster.
+	IncludeWorkloadCluster bool
+
+	// Output is where the support bundle archive is written.
+	Output io.Writer
+}
+
+// SupportBundle collects the Cluster API objects, provider objects, controller logs, events and optionally
+// a summary of the workload cluster for a Cluster into a redacted archive, to be attached to bug reports.
+func (c *clusterctlClient) SupportBundle(options SupportBundleOptions) error {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.SupportBundle().Collect(&cluster.SupportBundleInput{
+		Namespace:              options.Namespace,
+		ClusterName:            options.ClusterName,
+		LogsTailLines:          options.LogsTailLines,
+		IncludeWorkloadCluster: options.IncludeWorkloadCluster,
+		Output:                 options.Output,
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
@@ -60,3 +61,191 @@ func (c *clusterctlClient) TopologyPlan(options TopologyPlanOptions) (*TopologyP
 
 	return out, err
 }
+
+// TopologyOwnershipOptions define options for TopologyOwnership.
+type TopologyOwnershipOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace is the namespace of the Clusters to inspect.
+	// If empty, Clusters in all the namespaces are inspected.
+	Namespace string
+
+	// ClusterName is the name of the Cluster to inspect.
+	// If empty, all the Clusters with a managed topology are inspected.
+	ClusterName string
+}
+
+// TopologyOwnershipOutput defines the output of the topology ownership operation.
+type TopologyOwnershipOutput = cluster.TopologyOwnershipOutput
+
+// TopologyOwnership reports, for each field of the objects owned by the topology of one or more Clusters,
+// which managers own it.
+func (c *clusterctlClient) TopologyOwnership(options TopologyOwnershipOptions) (*TopologyOwnershipOutput, error) {
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ClusterName != "" && options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.Topology().Ownership(&cluster.TopologyOwnershipInput{
+		Namespace:   options.Namespace,
+		ClusterName: options.ClusterName,
+	})
+}
+
+// TopologyAdoptOptions define options for TopologyAdopt.
+type TopologyAdoptOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace is the namespace of the Cluster to adopt. If empty, the current namespace is used.
+	Namespace string
+
+	// ClusterName is the name of the Cluster to adopt.
+	ClusterName string
+
+	// ClassName is the name of the ClusterClass the Cluster should use after adoption.
+	ClassName string
+
+	// DryRun, if true, only checks that the Cluster can be adopted without changing any object.
+	DryRun bool
+}
+
+// TopologyAdoptOutput defines the output of the topology adopt operation.
+type TopologyAdoptOutput = cluster.TopologyAdoptOutput
+
+// TopologyAdopt adopts an existing Cluster, which is not using a managed topology, under the management of a ClusterClass.
+func (c *clusterctlClient) TopologyAdopt(options TopologyAdoptOptions) (*TopologyAdoptOutput, error) {
+	if options.ClusterName == "" {
+		return nil, errors.New("cluster name must be specified")
+	}
+	if options.ClassName == "" {
+		return nil, errors.New("class name must be specified")
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.Topology().Adopt(&cluster.TopologyAdoptInput{
+		Namespace:   options.Namespace,
+		ClusterName: options.ClusterName,
+		ClassName:   options.ClassName,
+		DryRun:      options.DryRun,
+	})
+}
+
+// TopologyExportOptions define options for TopologyExport.
+type TopologyExportOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace is the namespace of the ClusterClass to export. If empty, the current namespace is used.
+	Namespace string
+
+	// ClassName is the name of the ClusterClass to export.
+	ClassName string
+}
+
+// TopologyExportOutput defines the output of the topology export operation.
+type TopologyExportOutput = cluster.TopologyExportOutput
+
+// TopologyExport returns a ClusterClass and all the templates it references as a self-contained bundle.
+func (c *clusterctlClient) TopologyExport(options TopologyExportOptions) (*TopologyExportOutput, error) {
+	if options.ClassName == "" {
+		return nil, errors.New("class name must be specified")
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	return clusterClient.Topology().Export(&cluster.TopologyExportInput{
+		Namespace: options.Namespace,
+		ClassName: options.ClassName,
+	})
+}
+
+// TopologyImportOptions define options for TopologyImport.
+type TopologyImportOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Objs is the bundle to import, as generated by TopologyExport.
+	Objs []unstructured.Unstructured
+
+	// TargetNamespace is the namespace where the objects of the bundle should be created.
+	// If empty, the current namespace is used.
+	TargetNamespace string
+
+	// ClassName is the name of the imported ClusterClass. If empty, the name in the bundle is used.
+	ClassName string
+
+	// NamePrefix is a prefix added to the name of all the templates in the bundle.
+	NamePrefix string
+
+	// DryRun, if true, only computes the objects to be created, without creating them.
+	DryRun bool
+}
+
+// TopologyImportOutput defines the output of the topology import operation.
+type TopologyImportOutput = cluster.TopologyImportOutput
+
+// TopologyImport creates the ClusterClass and the templates of a bundle generated by TopologyExport,
+// remapping their namespace and names.
+func (c *clusterctlClient) TopologyImport(options TopologyImportOptions) (*TopologyImportOutput, error) {
+	if len(options.Objs) == 0 {
+		return nil, errors.New("bundle must not be empty")
+	}
+
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return nil, err
+	}
+
+	if options.TargetNamespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return nil, err
+		}
+		options.TargetNamespace = currentNamespace
+	}
+
+	return clusterClient.Topology().Import(&cluster.TopologyImportInput{
+		Objs:            options.Objs,
+		TargetNamespace: options.TargetNamespace,
+		ClassName:       options.ClassName,
+		NamePrefix:      options.NamePrefix,
+		DryRun:          options.DryRun,
+	})
+}
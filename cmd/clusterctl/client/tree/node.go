@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ObjectNode is a machine-readable representation of an object in an ObjectTree, and of its children,
+// e.g. to be serialized to JSON or YAML.
+type ObjectNode struct {
+	// Kind of the object; for group objects, it is the kind of the objects in the group followed by Group, e.g. MachineGroup.
+	Kind string `json:"kind"`
+
+	// APIVersion of the object; it is empty for virtual objects.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Namespace of the object.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object.
+	Name string `json:"name"`
+
+	// MetaName is the name used for the object in the presentation layer, if any, e.g. ControlPlane.
+	MetaName string `json:"metaName,omitempty"`
+
+	// Virtual is true if the object does not correspond to any real object, e.g. the Workers node or group objects.
+	Virtual bool `json:"virtual,omitempty"`
+
+	// GroupItems is the list of names of the objects in a group object.
+	GroupItems []string `json:"groupItems,omitempty"`
+
+	// Deleting is true if the object is being deleted.
+	Deleting bool `json:"deleting,omitempty"`
+
+	// Ready is the ready condition of the object, if any.
+	Ready *clusterv1.Condition `json:"ready,omitempty"`
+
+	// Conditions are the other conditions of the object.
+	Conditions []clusterv1.Condition `json:"conditions,omitempty"`
+
+	// Children are the objects depending on the object, sorted with the same order used when the tree is printed.
+	Children []ObjectNode `json:"children,omitempty"`
+}
+
+// GetRootNode returns a machine-readable representation of the tree, starting from its root.
+func (od ObjectTree) GetRootNode() ObjectNode {
+	return od.getNode(od.root)
+}
+
+func (od ObjectTree) getNode(obj client.Object) ObjectNode {
+	node := ObjectNode{
+		Kind:      obj.GetObjectKind().GroupVersionKind().Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		MetaName:  GetMetaName(obj),
+		Virtual:   IsVirtualObject(obj),
+		Deleting:  !obj.GetDeletionTimestamp().IsZero(),
+		Ready:     GetReadyCondition(obj),
+	}
+	if !node.Virtual {
+		node.APIVersion = obj.GetObjectKind().GroupVersionKind().GroupVersion().String()
+	}
+	if IsGroupObject(obj) {
+		node.GroupItems = strings.Split(GetGroupItems(obj), GroupItemsSeparator)
+	}
+	for _, c := range GetOtherConditions(obj) {
+		node.Conditions = append(node.Conditions, *c)
+	}
+
+	// Children are sorted by z-order and then by kind and name, consistently with the order used when the tree is printed.
+	children := od.GetObjectsByParent(obj.GetUID())
+	sort.Slice(children, func(i, j int) bool {
+		if GetZOrder(children[i]) != GetZOrder(children[j]) {
+			return GetZOrder(children[i]) > GetZOrder(children[j])
+		}
+		if children[i].GetObjectKind().GroupVersionKind().Kind != children[j].GetObjectKind().GroupVersionKind().Kind {
+			return children[i].GetObjectKind().GroupVersionKind().Kind < children[j].GetObjectKind().GroupVersionKind().Kind
+		}
+		return children[i].GetName() < children[j].GetName()
+	})
+	for _, child := range children {
+		node.Children = append(node.Children, od.getNode(child))
+	}
+	return node
+}
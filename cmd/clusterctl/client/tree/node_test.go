@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_GetRootNode(t *testing.T) {
+	g := NewWithT(t)
+
+	readyTrue := conditions.TrueCondition(clusterv1.ReadyCondition)
+	infrastructureReady := conditions.TrueCondition(clusterv1.InfrastructureReadyCondition)
+
+	root := fakeCluster("my-cluster",
+		withClusterCondition(readyTrue),
+		withClusterCondition(infrastructureReady),
+	)
+	tree := NewObjectTree(root, ObjectTreeOptions{Grouping: true})
+
+	workers := VirtualObject("ns", "WorkerGroup", "Workers")
+	tree.Add(root, workers, GroupingObject(true))
+	tree.Add(workers, fakeMachine("machine-b", withMachineCondition(readyTrue)))
+	tree.Add(workers, fakeMachine("machine-a", withMachineCondition(readyTrue)))
+	tree.Add(root, fakeMachine("machine-cp", withMachineCondition(readyTrue)), ObjectMetaName("ControlPlane"), ZOrder(1))
+
+	node := tree.GetRootNode()
+	g.Expect(node.Kind).To(Equal("Cluster"))
+	g.Expect(node.Name).To(Equal("my-cluster"))
+	g.Expect(node.Virtual).To(BeFalse())
+	g.Expect(node.Ready).ToNot(BeNil())
+	g.Expect(node.Ready.Status).To(Equal(readyTrue.Status))
+	g.Expect(node.Conditions).To(HaveLen(1))
+	g.Expect(node.Conditions[0].Type).To(Equal(clusterv1.InfrastructureReadyCondition))
+
+	// Children are sorted by z-order first.
+	g.Expect(node.Children).To(HaveLen(2))
+	g.Expect(node.Children[0].Name).To(Equal("machine-cp"))
+	g.Expect(node.Children[0].MetaName).To(Equal("ControlPlane"))
+	g.Expect(node.Children[1].Name).To(Equal("Workers"))
+	g.Expect(node.Children[1].Virtual).To(BeTrue())
+	g.Expect(node.Children[1].APIVersion).To(BeEmpty())
+
+	// Sibling machines with the same ready condition are grouped.
+	g.Expect(node.Children[1].Children).To(HaveLen(1))
+	group := node.Children[1].Children[0]
+	g.Expect(group.Kind).To(Equal("MachineGroup"))
+	g.Expect(group.GroupItems).To(Equal([]string{"machine-a", "machine-b"}))
+}
@@ -30,6 +30,7 @@ func init() {
 	// Alpha commands should be added here.
 	alphaCmd.AddCommand(rolloutCmd)
 	alphaCmd.AddCommand(topologyCmd)
+	alphaCmd.AddCommand(supportBundleCmd)
 
 	RootCmd.AddCommand(alphaCmd)
 }
@@ -17,7 +17,9 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -31,6 +33,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -66,6 +69,7 @@ type describeClusterOptions struct {
 	grouping                bool
 	disableGrouping         bool
 	color                   bool
+	output                  string
 }
 
 var dc = &describeClusterOptions{}
@@ -94,7 +98,10 @@ var describeClusterClusterCmd = &cobra.Command{
 
 		# Describe the cluster named test-1 disabling automatic echo suppression
         # e.g. show the infrastructure machine objects, no matter if the current state is already reported by the machine's Ready condition.
-		clusterctl describe cluster test-1 --disable-no-echo`),
+		clusterctl describe cluster test-1 --disable-no-echo
+
+		# Describe the cluster named test-1 in JSON format, e.g. to be consumed by scripts or dashboards.
+		clusterctl describe cluster test-1 -o json`),
 
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
@@ -137,6 +144,8 @@ func init() {
 	_ = describeClusterClusterCmd.Flags().MarkDeprecated("disable-grouping",
 		"use --grouping instead.")
 	describeClusterClusterCmd.Flags().BoolVarP(&dc.color, "color", "c", false, "Enable or disable color output; if not set color is enabled by default only if using tty. The flag is overridden by the NO_COLOR env variable if set.")
+	describeClusterClusterCmd.Flags().StringVarP(&dc.output, "output", "o", "",
+		"Output format; available options are 'json' and 'yaml'. If not set, the cluster is printed as a tree view.")
 
 	// completions
 	describeClusterClusterCmd.ValidArgsFunction = resourceNameCompletionFunc(
@@ -151,6 +160,10 @@ func init() {
 }
 
 func runDescribeCluster(cmd *cobra.Command, name string) error {
+	if dc.output != "" && dc.output != "json" && dc.output != "yaml" {
+		return errors.Errorf("invalid output format: %s", dc.output)
+	}
+
 	c, err := client.New(cfgFile)
 	if err != nil {
 		return err
@@ -176,10 +189,36 @@ func runDescribeCluster(cmd *cobra.Command, name string) error {
 		color.NoColor = !dc.color
 	}
 
+	if dc.output != "" {
+		return printObjectTreeNode(os.Stdout, tree, dc.output)
+	}
+
 	printObjectTree(tree)
 	return nil
 }
 
+// printObjectTreeNode prints the cluster status, including all the object's conditions, in JSON or YAML format.
+func printObjectTreeNode(out io.Writer, tree *tree.ObjectTree, output string) error {
+	node := tree.GetRootNode()
+
+	var b []byte
+	var err error
+	switch output {
+	case "json":
+		b, err = json.MarshalIndent(&node, "", "  ")
+		b = append(b, '\n')
+	case "yaml":
+		b, err = yaml.Marshal(&node)
+	default:
+		return errors.Errorf("invalid output format: %s", output)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the cluster status to %s", output)
+	}
+	_, err = out.Write(b)
+	return err
+}
+
 // printObjectTree prints the cluster status to stdout.
 func printObjectTree(tree *tree.ObjectTree) {
 	// Creates the output table
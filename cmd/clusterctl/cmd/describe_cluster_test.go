@@ -294,6 +294,47 @@ func Test_TreePrefix(t *testing.T) {
 	}
 }
 
+func Test_printObjectTreeNode(t *testing.T) {
+	root := fakeObject("root", withCondition(conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, "message")))
+	objectTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{})
+	objectTree.Add(root, fakeObject("child"))
+
+	tests := []struct {
+		output  string
+		expect  []string
+		wantErr bool
+	}{
+		{
+			output: "json",
+			expect: []string{`"kind": "Object"`, `"name": "root"`, `"reason": "Reason"`, `"children": [`, `"name": "child"`},
+		},
+		{
+			output: "yaml",
+			expect: []string{"kind: Object", "name: root", "reason: Reason", "children:", "name: child"},
+		},
+		{
+			output:  "wide",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var output bytes.Buffer
+			err := printObjectTreeNode(&output, objectTree, tt.output)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, e := range tt.expect {
+				g.Expect(output.String()).To(ContainSubstring(e))
+			}
+		})
+	}
+}
+
 type objectOption func(object ctrlclient.Object)
 
 func fakeObject(name string, options ...objectOption) ctrlclient.Object {
@@ -17,12 +17,21 @@ limitations under the License.
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/internal/topology/variables"
 )
 
 type generateClusterOptions struct {
@@ -42,6 +51,7 @@ type generateClusterOptions struct {
 	configMapDataKey   string
 
 	listVariables bool
+	interactive   bool
 }
 
 var gc = &generateClusterOptions{}
@@ -87,7 +97,11 @@ var generateClusterClusterCmd = &cobra.Command{
 		clusterctl generate cluster my-cluster --from ~/workspace/cluster-template.yaml
 
 		# Prints the list of variables required by the yaml file for creating workload cluster.
-		clusterctl generate cluster my-cluster --list-variables`),
+		clusterctl generate cluster my-cluster --list-variables
+
+		# Generates a yaml file for creating workload clusters prompting for the values of
+		# the required ClusterClass variables which are not set in the template.
+		clusterctl generate cluster my-cluster --flavor=development-topology --interactive`),
 
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
@@ -137,6 +151,8 @@ func init() {
 	// other flags
 	generateClusterClusterCmd.Flags().BoolVar(&gc.listVariables, "list-variables", false,
 		"Returns the list of variables expected by the template instead of the template yaml")
+	generateClusterClusterCmd.Flags().BoolVar(&gc.interactive, "interactive", false,
+		"Prompts for the values of the required ClusterClass variables which are not set in the template. Values are validated against the variable schemas.")
 
 	generateCmd.AddCommand(generateClusterClusterCmd)
 }
@@ -155,6 +171,13 @@ func runGenerateClusterTemplate(cmd *cobra.Command, name string) error {
 		ListVariablesOnly: gc.listVariables,
 	}
 
+	if gc.interactive {
+		if gc.url == "-" {
+			return errors.New("--interactive cannot be used when reading the workload cluster template from stdin")
+		}
+		templateOptions.TopologyVariableResolver = promptTopologyVariable(os.Stdin, os.Stderr)
+	}
+
 	if cmd.Flags().Changed("control-plane-machine-count") {
 		templateOptions.ControlPlaneMachineCount = &gc.controlPlaneMachineCount
 	}
@@ -194,3 +217,47 @@ func runGenerateClusterTemplate(cmd *cobra.Command, name string) error {
 
 	return printYamlOutput(template)
 }
+
+// promptTopologyVariable returns a TopologyVariableResolver prompting the user for the value of a ClusterClass variable
+// until a value which is valid according to the variable schema is entered.
+func promptTopologyVariable(in io.Reader, out io.Writer) client.TopologyVariableResolver {
+	reader := bufio.NewReader(in)
+	return func(clusterName string, variable clusterv1.ClusterClassVariable) (*apiextensionsv1.JSON, error) {
+		schema := variable.Schema.OpenAPIV3Schema
+		if schema.Description != "" {
+			fmt.Fprintf(out, "%s\n", schema.Description)
+		}
+		for {
+			fmt.Fprintf(out, "Enter value for variable %q of Cluster %q (type: %s): ", variable.Name, clusterName, schema.Type)
+			line, err := reader.ReadString('\n')
+			if err != nil && (err != io.EOF || line == "") {
+				return nil, errors.Wrapf(err, "failed to read value for variable %q", variable.Name)
+			}
+
+			value := topologyVariableValue(strings.TrimSpace(line), schema.Type)
+			clusterVariable := &clusterv1.ClusterVariable{Name: variable.Name, Value: *value}
+			errs := variables.ValidateClusterVariable(clusterVariable, &variable, field.NewPath(variable.Name))
+			if len(errs) == 0 {
+				return value, nil
+			}
+			if err == io.EOF {
+				return nil, errs.ToAggregate()
+			}
+			fmt.Fprintf(out, "Invalid value: %v\n", errs.ToAggregate())
+		}
+	}
+}
+
+// topologyVariableValue converts the value entered by the user into JSON; values for string variables
+// can be entered without quotes, while values for all the other types must be entered as JSON.
+func topologyVariableValue(input, schemaType string) *apiextensionsv1.JSON {
+	if schemaType != "string" && json.Valid([]byte(input)) {
+		return &apiextensionsv1.JSON{Raw: []byte(input)}
+	}
+	if schemaType == "string" && strings.HasPrefix(input, "\"") && json.Valid([]byte(input)) {
+		return &apiextensionsv1.JSON{Raw: []byte(input)}
+	}
+	// NOTE: Marshalling a string can't fail.
+	raw, _ := json.Marshal(input)
+	return &apiextensionsv1.JSON{Raw: raw}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func Test_promptTopologyVariable(t *testing.T) {
+	variable := func(schemaType string) clusterv1.ClusterClassVariable {
+		return clusterv1.ClusterClassVariable{
+			Name:     "var1",
+			Required: true,
+			Schema: clusterv1.VariableSchema{
+				OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+					Type:        schemaType,
+					Description: "The variable description.",
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		variable  clusterv1.ClusterClassVariable
+		input     string
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name:      "Strings can be entered without quotes",
+			variable:  variable("string"),
+			input:     "us-east-1\n",
+			wantValue: `"us-east-1"`,
+		},
+		{
+			name:      "Strings can be entered with quotes",
+			variable:  variable("string"),
+			input:     "\"us-east-1\"\n",
+			wantValue: `"us-east-1"`,
+		},
+		{
+			name:      "Other types are entered as JSON",
+			variable:  variable("object"),
+			input:     "{}\n",
+			wantValue: `{}`,
+		},
+		{
+			name:      "Prompt again if the value is invalid",
+			variable:  variable("integer"),
+			input:     "three\n3\n",
+			wantValue: `3`,
+		},
+		{
+			name:     "Fail if there is no valid value",
+			variable: variable("integer"),
+			input:    "three",
+			wantErr:  true,
+		},
+		{
+			name:     "Fail if there is no input",
+			variable: variable("integer"),
+			input:    "",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			out := &bytes.Buffer{}
+			resolver := promptTopologyVariable(strings.NewReader(tt.input), out)
+
+			got, err := resolver("cluster1", tt.variable)
+			g.Expect(out.String()).To(ContainSubstring("The variable description."))
+			g.Expect(out.String()).To(ContainSubstring(`Enter value for variable "var1" of Cluster "cluster1"`))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(got.Raw)).To(Equal(tt.wantValue))
+		})
+	}
+}
@@ -31,6 +31,7 @@ type moveOptions struct {
 	namespace             string
 	fromDirectory         string
 	toDirectory           string
+	toNamespace           string
 	dryRun                bool
 }
 
@@ -53,6 +54,9 @@ var moveCmd = &cobra.Command{
 
 		Read Cluster API objects and all dependencies from a directory into a management cluster.
 		clusterctl move --from-directory /tmp/backup-directory
+
+		Move Cluster API objects and all dependencies to another namespace of the destination management cluster.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --namespace foo --to-namespace bar
 	`),
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -77,10 +81,14 @@ func init() {
 		"Write Cluster API objects and all dependencies from a management cluster to directory.")
 	moveCmd.Flags().StringVar(&mo.fromDirectory, "from-directory", "",
 		"Read Cluster API objects and all dependencies from a directory into a management cluster.")
+	moveCmd.Flags().StringVar(&mo.toNamespace, "to-namespace", "",
+		"The namespace where the workload cluster is moved in the destination management cluster. If unspecified, the source namespace is used.")
 
 	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "to-kubeconfig")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "to-directory")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "kubeconfig")
+	moveCmd.MarkFlagsMutuallyExclusive("to-namespace", "to-directory")
+	moveCmd.MarkFlagsMutuallyExclusive("to-namespace", "from-directory")
 
 	RootCmd.AddCommand(moveCmd)
 }
@@ -104,6 +112,7 @@ func runMove() error {
 		FromDirectory:  mo.fromDirectory,
 		ToDirectory:    mo.toDirectory,
 		Namespace:      mo.namespace,
+		ToNamespace:    mo.toNamespace,
 		DryRun:         mo.dryRun,
 	})
 }
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type supportBundleOptions struct {
+	kubeconfig             string
+	kubeconfigContext      string
+	cluster                string
+	namespace              string
+	output                 string
+	logsTailLines          int64
+	includeWorkloadCluster bool
+}
+
+var sb = &supportBundleOptions{}
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Collect the information required to troubleshoot a Cluster into an archive",
+	Long: LongDesc(`
+		Collect the information required to troubleshoot a Cluster into a gzipped tar archive, to be attached
+		to bug reports or shared with vendor support.
+
+		The archive includes the Cluster API and provider objects belonging to the Cluster, the events for those objects,
+		the logs of the controllers of the providers installed in the management cluster and, optionally, a summary
+		of the Nodes and Pods of the workload cluster.
+
+		The data of Secrets, bootstrap tokens and the content of the files of kubeadm bootstrap configurations are
+		redacted, as well as any occurrence of those values in the other files, e.g. in logs; please review the archive before sharing it, given that other sensitive information,
+		e.g. host names or IP addresses, is not redacted.
+	`),
+	Example: Examples(`
+		# Collect a support bundle for the Cluster "my-cluster".
+		clusterctl alpha support-bundle --cluster my-cluster -n my-namespace
+
+		# Collect a support bundle including the last 1000 lines of the controller logs and a summary of the workload cluster.
+		clusterctl alpha support-bundle --cluster my-cluster --logs-tail 1000 --include-workload-cluster -o bundle.tar.gz`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSupportBundle()
+	},
+}
+
+func init() {
+	supportBundleCmd.Flags().StringVar(&sb.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	supportBundleCmd.Flags().StringVar(&sb.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	supportBundleCmd.Flags().StringVarP(&sb.cluster, "cluster", "c", "", "name of the Cluster to collect the support bundle for")
+	supportBundleCmd.Flags().StringVarP(&sb.namespace, "namespace", "n", "", "namespace of the Cluster. If unspecified, the current namespace will be used")
+	supportBundleCmd.Flags().StringVarP(&sb.output, "output", "o", "", "path of the archive to write. If unspecified, support-bundle-<cluster>-<timestamp>.tar.gz is written in the current directory")
+	supportBundleCmd.Flags().Int64Var(&sb.logsTailLines, "logs-tail", -1, "number of lines of the controller logs to collect; if negative, all the lines are collected")
+	supportBundleCmd.Flags().BoolVar(&sb.includeWorkloadCluster, "include-workload-cluster", false, "include a summary of the Nodes and Pods of the workload cluster")
+
+	if err := supportBundleCmd.MarkFlagRequired("cluster"); err != nil {
+		panic(err)
+	}
+}
+
+func runSupportBundle() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	output := sb.output
+	if output == "" {
+		output = fmt.Sprintf("support-bundle-%s-%s.tar.gz", sb.cluster, time.Now().UTC().Format("20060102-150405"))
+	}
+	f, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s", output)
+	}
+	defer f.Close()
+
+	var logsTailLines *int64
+	if sb.logsTailLines >= 0 {
+		logsTailLines = &sb.logsTailLines
+	}
+
+	if err := c.SupportBundle(client.SupportBundleOptions{
+		Kubeconfig:             client.Kubeconfig{Path: sb.kubeconfig, Context: sb.kubeconfigContext},
+		Namespace:              sb.namespace,
+		ClusterName:            sb.cluster,
+		LogsTailLines:          logsTailLines,
+		IncludeWorkloadCluster: sb.includeWorkloadCluster,
+		Output:                 f,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Support bundle written to %s\n", output)
+	return nil
+}
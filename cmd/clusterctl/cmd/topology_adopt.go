@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type topologyAdoptOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	cluster           string
+	class             string
+	namespace         string
+	dryRun            bool
+}
+
+var ta = &topologyAdoptOptions{}
+
+var topologyAdoptCmd = &cobra.Command{
+	Use:   "adopt",
+	Short: "Adopt an existing Cluster under the management of a ClusterClass",
+	Long: LongDesc(`
+		Adopt an existing Cluster, which is not using a managed topology, under the management of a ClusterClass.
+
+		The command checks that the InfrastructureCluster, the ControlPlane and the MachineDeployments of the Cluster
+		are compatible with the ClusterClass; if this is the case, all those objects are labeled as owned by the topology
+		and the Cluster topology is set with the current Kubernetes version and replicas.
+
+		The adopted objects keep their names; please note that the topology controller will roll out templates
+		if they differ from the ones computed from the ClusterClass. Use --dry-run to check compatibility and to
+		get a preview of the resulting topology first, and "clusterctl alpha topology plan" to preview the changes.
+	`),
+	Example: Examples(`
+		# Check if the Cluster "my-cluster" can be adopted using the ClusterClass "my-class".
+		clusterctl alpha topology adopt --cluster my-cluster --class my-class --dry-run
+
+		# Adopt the Cluster "my-cluster" using the ClusterClass "my-class".
+		clusterctl alpha topology adopt --cluster my-cluster --class my-class`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTopologyAdopt()
+	},
+}
+
+func init() {
+	topologyAdoptCmd.Flags().StringVar(&ta.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	topologyAdoptCmd.Flags().StringVar(&ta.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	topologyAdoptCmd.Flags().StringVarP(&ta.cluster, "cluster", "c", "", "name of the Cluster to adopt")
+	topologyAdoptCmd.Flags().StringVar(&ta.class, "class", "", "name of the ClusterClass to be used by the Cluster; it must exist in the namespace of the Cluster")
+	topologyAdoptCmd.Flags().StringVarP(&ta.namespace, "namespace", "n", "", "namespace of the Cluster. If unspecified, the current namespace will be used")
+	topologyAdoptCmd.Flags().BoolVar(&ta.dryRun, "dry-run", false, "only check if the Cluster can be adopted and print the resulting topology, without changing any object")
+
+	if err := topologyAdoptCmd.MarkFlagRequired("cluster"); err != nil {
+		panic(err)
+	}
+	if err := topologyAdoptCmd.MarkFlagRequired("class"); err != nil {
+		panic(err)
+	}
+
+	topologyCmd.AddCommand(topologyAdoptCmd)
+}
+
+func runTopologyAdopt() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	out, err := c.TopologyAdopt(client.TopologyAdoptOptions{
+		Kubeconfig:  client.Kubeconfig{Path: ta.kubeconfig, Context: ta.kubeconfigContext},
+		Namespace:   ta.namespace,
+		ClusterName: ta.cluster,
+		ClassName:   ta.class,
+		DryRun:      ta.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(out.Issues) > 0 {
+		fmt.Printf("Cluster %q is not compatible with ClusterClass %q:\n", ta.cluster, ta.class)
+		for _, issue := range out.Issues {
+			fmt.Printf(" ＊ %s\n", issue)
+		}
+		return errors.Errorf("Cluster %q cannot be adopted", ta.cluster)
+	}
+
+	if ta.dryRun {
+		fmt.Printf("Cluster %q is compatible with ClusterClass %q.\n", ta.cluster, ta.class)
+	} else {
+		fmt.Printf("Cluster %q adopted using ClusterClass %q.\n", ta.cluster, ta.class)
+	}
+
+	fmt.Printf("\nObjects owned by the topology:\n")
+	for _, o := range out.Objects {
+		fmt.Printf(" ＊ %s %s/%s\n", o.Kind, o.Namespace, o.Name)
+	}
+
+	fmt.Printf("\nTopology:\n")
+	fmt.Printf(" ＊ class: %s\n", out.Topology.Class)
+	fmt.Printf(" ＊ version: %s\n", out.Topology.Version)
+	if out.Topology.ControlPlane.Replicas != nil {
+		fmt.Printf(" ＊ controlPlane replicas: %d\n", *out.Topology.ControlPlane.Replicas)
+	}
+	if out.Topology.Workers != nil {
+		for _, md := range out.Topology.Workers.MachineDeployments {
+			replicas := "-"
+			if md.Replicas != nil {
+				replicas = fmt.Sprintf("%d", *md.Replicas)
+			}
+			fmt.Printf(" ＊ machineDeployment %s: class %s, replicas %s\n", md.Name, md.Class, replicas)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+type topologyExportOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	class             string
+	namespace         string
+	outFile           string
+}
+
+var te = &topologyExportOptions{}
+
+var topologyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a ClusterClass and all the templates it references as a single bundle",
+	Long: LongDesc(`
+		Export a ClusterClass and all the templates it references as a single, self-contained YAML bundle.
+
+		Objects in the bundle do not have a namespace, and all the metadata set by the management cluster,
+		e.g. resourceVersion, managedFields or ownerReferences, as well as the status are removed; the bundle
+		can be imported into another namespace or another management cluster using "clusterctl alpha topology import".
+	`),
+	Example: Examples(`
+		# Export the ClusterClass "my-class" to stdout.
+		clusterctl alpha topology export --class my-class
+
+		# Export the ClusterClass "my-class" from the namespace "classes" to a file.
+		clusterctl alpha topology export --class my-class -n classes -o my-class.yaml`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTopologyExport()
+	},
+}
+
+func init() {
+	topologyExportCmd.Flags().StringVar(&te.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	topologyExportCmd.Flags().StringVar(&te.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	topologyExportCmd.Flags().StringVar(&te.class, "class", "", "name of the ClusterClass to export")
+	topologyExportCmd.Flags().StringVarP(&te.namespace, "namespace", "n", "", "namespace of the ClusterClass. If unspecified, the current namespace will be used")
+	topologyExportCmd.Flags().StringVarP(&te.outFile, "output", "o", "", "path of the file to write the bundle to. If unspecified, the bundle is written to stdout")
+
+	if err := topologyExportCmd.MarkFlagRequired("class"); err != nil {
+		panic(err)
+	}
+
+	topologyCmd.AddCommand(topologyExportCmd)
+}
+
+func runTopologyExport() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	out, err := c.TopologyExport(client.TopologyExportOptions{
+		Kubeconfig: client.Kubeconfig{Path: te.kubeconfig, Context: te.kubeconfigContext},
+		Namespace:  te.namespace,
+		ClassName:  te.class,
+	})
+	if err != nil {
+		return err
+	}
+
+	bundle, err := utilyaml.FromUnstructured(out.Objs)
+	if err != nil {
+		return errors.Wrap(err, "failed to convert the bundle to YAML")
+	}
+
+	if te.outFile == "" {
+		fmt.Print(string(bundle))
+		return nil
+	}
+	if err := os.WriteFile(te.outFile, bundle, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write the bundle to %q", te.outFile)
+	}
+	return nil
+}
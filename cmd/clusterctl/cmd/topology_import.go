@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+type topologyImportOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	file              string
+	targetNamespace   string
+	class             string
+	namePrefix        string
+	dryRun            bool
+}
+
+var ti = &topologyImportOptions{}
+
+var topologyImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a ClusterClass bundle generated by \"clusterctl alpha topology export\"",
+	Long: LongDesc(`
+		Import a ClusterClass bundle generated by "clusterctl alpha topology export".
+
+		All the objects of the bundle are created in the target namespace; the ClusterClass can be renamed
+		and a prefix can be added to the names of all the templates, e.g. to avoid conflicts with existing
+		objects. The references from the ClusterClass to its templates are remapped accordingly.
+
+		The command fails without creating any object if one of the objects already exists.
+	`),
+	Example: Examples(`
+		# Import the bundle in my-class.yaml into the current namespace.
+		clusterctl alpha topology import -f my-class.yaml
+
+		# Import the bundle in my-class.yaml into the namespace "team-a", renaming the ClusterClass to
+		# "team-a-class" and adding the "team-a-" prefix to the templates.
+		clusterctl alpha topology import -f my-class.yaml --target-namespace team-a --class team-a-class --name-prefix team-a-`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTopologyImport()
+	},
+}
+
+func init() {
+	topologyImportCmd.Flags().StringVar(&ti.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	topologyImportCmd.Flags().StringVar(&ti.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	topologyImportCmd.Flags().StringVarP(&ti.file, "file", "f", "", "path to the file with the bundle to import")
+	topologyImportCmd.Flags().StringVarP(&ti.targetNamespace, "target-namespace", "n", "", "namespace where the objects of the bundle are created. If unspecified, the current namespace will be used")
+	topologyImportCmd.Flags().StringVar(&ti.class, "class", "", "name of the imported ClusterClass. If unspecified, the name in the bundle will be used")
+	topologyImportCmd.Flags().StringVar(&ti.namePrefix, "name-prefix", "", "prefix added to the names of all the templates in the bundle")
+	topologyImportCmd.Flags().BoolVar(&ti.dryRun, "dry-run", false, "only print the objects that would be created, without creating them")
+
+	if err := topologyImportCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+
+	topologyCmd.AddCommand(topologyImportCmd)
+}
+
+func runTopologyImport() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	raw, err := os.ReadFile(ti.file) //nolint:gosec
+	if err != nil {
+		return errors.Wrapf(err, "failed to read input file %q", ti.file)
+	}
+	objs, err := utilyaml.ToUnstructured(raw)
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert file %q to list of objects", ti.file)
+	}
+
+	out, err := c.TopologyImport(client.TopologyImportOptions{
+		Kubeconfig:      client.Kubeconfig{Path: ti.kubeconfig, Context: ti.kubeconfigContext},
+		Objs:            objs,
+		TargetNamespace: ti.targetNamespace,
+		ClassName:       ti.class,
+		NamePrefix:      ti.namePrefix,
+		DryRun:          ti.dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ti.dryRun {
+		fmt.Printf("Objects to be created:\n")
+	} else {
+		fmt.Printf("Objects created:\n")
+	}
+	for _, o := range out.Objs {
+		fmt.Printf(" ＊ %s %s/%s\n", o.GetKind(), o.GetNamespace(), o.GetName())
+	}
+	return nil
+}
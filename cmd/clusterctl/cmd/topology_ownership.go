@@ -0,0 +1,157 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+)
+
+type topologyOwnershipOptions struct {
+	kubeconfig        string
+	kubeconfigContext string
+	cluster           string
+	namespace         string
+	sharedOnly        bool
+}
+
+var to = &topologyOwnershipOptions{}
+
+var topologyOwnershipCmd = &cobra.Command{
+	Use:   "ownership",
+	Short: "Report which field managers own the fields of objects in managed topologies",
+	Long: LongDesc(`
+		Report, for each field of the objects owned by a managed topology, which field managers own it
+		according to the managedFields of the objects.
+
+		This helps to understand which fields are owned by the topology controller, by users or by other controllers,
+		e.g. autoscalers, and thus why server side apply conflicts might happen.
+
+		If a cluster is not specified, the report includes all the clusters with a managed topology
+		in the given namespace or in all the namespaces if the namespace is not specified.
+	`),
+	Example: Examples(`
+		# Report field ownership of all the objects managed by the topology of the cluster "my-cluster".
+		clusterctl alpha topology ownership --cluster my-cluster -n my-namespace
+
+		# Report only the fields owned both by the topology controller and by other managers.
+		clusterctl alpha topology ownership --cluster my-cluster --shared-only
+
+		# Report field ownership for all the clusters with a managed topology.
+		clusterctl alpha topology ownership`),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTopologyOwnership()
+	},
+}
+
+func init() {
+	topologyOwnershipCmd.Flags().StringVar(&to.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig for the management cluster. If unspecified, default discovery rules apply.")
+	topologyOwnershipCmd.Flags().StringVar(&to.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+
+	topologyOwnershipCmd.Flags().StringVarP(&to.cluster, "cluster", "c", "", "name of the target cluster; if empty, all the clusters with a managed topology are reported")
+	topologyOwnershipCmd.Flags().StringVarP(&to.namespace, "namespace", "n", "", "namespace of the target cluster(s); if empty and --cluster is set, the current namespace is used, otherwise all the namespaces are reported")
+	topologyOwnershipCmd.Flags().BoolVar(&to.sharedOnly, "shared-only", false, "report only the fields owned both by the topology controller and by other managers")
+
+	topologyCmd.AddCommand(topologyOwnershipCmd)
+}
+
+func runTopologyOwnership() error {
+	c, err := client.New(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	out, err := c.TopologyOwnership(client.TopologyOwnershipOptions{
+		Kubeconfig:  client.Kubeconfig{Path: to.kubeconfig, Context: to.kubeconfigContext},
+		Namespace:   to.namespace,
+		ClusterName: to.cluster,
+	})
+	if err != nil {
+		return err
+	}
+
+	printTopologyOwnershipOutput(os.Stdout, out, to.sharedOnly)
+	return nil
+}
+
+func printTopologyOwnershipOutput(w io.Writer, out *client.TopologyOwnershipOutput, sharedOnly bool) {
+	if len(out.Objects) == 0 {
+		fmt.Fprintf(w, "No objects owned by managed topologies found.\n")
+		return
+	}
+
+	for _, o := range out.Objects {
+		fields := o.Fields
+		if sharedOnly {
+			fields = sharedFields(fields)
+			if len(fields) == 0 {
+				continue
+			}
+		}
+
+		fmt.Fprintf(w, "%s %s/%s (Cluster %s):\n", o.Object.Kind, o.Object.Namespace, o.Object.Name, o.Cluster.String())
+		table := tablewriter.NewWriter(w)
+		table.SetHeader([]string{"Field", "Managers"})
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		table.SetCenterSeparator("")
+		table.SetColumnSeparator("")
+		table.SetRowSeparator("")
+		table.SetHeaderLine(false)
+		table.SetBorder(false)
+		table.SetAutoWrapText(false)
+		for _, f := range fields {
+			managers := make([]string, 0, len(f.Managers))
+			for _, m := range f.Managers {
+				managers = append(managers, m.String())
+			}
+			table.Append([]string{f.Path, strings.Join(managers, ", ")})
+		}
+		table.Render()
+		fmt.Fprintf(w, "\n")
+	}
+}
+
+// sharedFields returns the fields owned both by the topology controller and by other managers.
+func sharedFields(fields []cluster.FieldOwnership) []cluster.FieldOwnership {
+	res := []cluster.FieldOwnership{}
+	for _, f := range fields {
+		topology, others := false, false
+		for _, m := range f.Managers {
+			if m.IsTopology() {
+				topology = true
+			} else {
+				others = true
+			}
+		}
+		if topology && others {
+			res = append(res, f)
+		}
+	}
+	return res
+}
@@ -176,6 +176,34 @@ func fixContainersImage(containers []corev1.Container, alterImageFunc func(image
 	return nil
 }
 
+// FixDeployments alters the pod spec of deployments using the give alter func.
+// NB. The implemented approach is specific for the provider components YAML; it is not
+// intended to cover all the possible objects used to deploy containers existing in Kubernetes.
+func FixDeployments(objs []unstructured.Unstructured, alterPodSpecFunc func(podSpec *corev1.PodSpec) error) ([]unstructured.Unstructured, error) {
+	for i := range objs {
+		o := &objs[i]
+		if o.GetKind() != deploymentKind {
+			continue
+		}
+
+		// Convert Unstructured into a typed object
+		d := &appsv1.Deployment{}
+		if err := scheme.Scheme.Convert(o, d, nil); err != nil {
+			return nil, err
+		}
+
+		if err := alterPodSpecFunc(&d.Spec.Template.Spec); err != nil {
+			return nil, errors.Wrapf(err, "failed to fix pod spec in deployment %s", d.Name)
+		}
+
+		// Convert typed object back to Unstructured
+		if err := scheme.Scheme.Convert(d, o, nil); err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
 // IsDeploymentWithManager return true if obj is a deployment containing a pod with at least one container named 'manager',
 // that according to the clusterctl contract, identifies the provider's controller.
 func IsDeploymentWithManager(obj unstructured.Unstructured) bool {
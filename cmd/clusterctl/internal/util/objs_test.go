@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -274,6 +275,67 @@ func TestFixImages(t *testing.T) {
 	}
 }
 
+func TestFixDeployments(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := []unstructured.Unstructured{
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       deploymentKind,
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []map[string]interface{}{
+								{
+									"image": "container-image",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       daemonSetKind,
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []map[string]interface{}{
+								{
+									"image": "container-image",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := FixDeployments(objs, func(podSpec *corev1.PodSpec) error {
+		podSpec.NodeSelector = map[string]string{"foo": "bar"}
+		return nil
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	nodeSelector, _, err := unstructured.NestedStringMap(got[0].Object, "spec", "template", "spec", "nodeSelector")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(nodeSelector).To(Equal(map[string]string{"foo": "bar"}))
+
+	// Only deployments should be changed.
+	_, found, err := unstructured.NestedStringMap(got[1].Object, "spec", "template", "spec", "nodeSelector")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	_, err = FixDeployments(objs, func(_ *corev1.PodSpec) error {
+		return errors.New("failed")
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
 func TestIsDeploymentWithManager(t *testing.T) {
 	convertor := runtime.DefaultUnstructuredConverter
 
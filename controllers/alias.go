@@ -18,11 +18,13 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
+	"sigs.k8s.io/cluster-api/controllers/readiness"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	clustercontroller "sigs.k8s.io/cluster-api/internal/controllers/cluster"
 	clusterclasscontroller "sigs.k8s.io/cluster-api/internal/controllers/clusterclass"
@@ -31,6 +33,7 @@ import (
 	machinehealthcheckcontroller "sigs.k8s.io/cluster-api/internal/controllers/machinehealthcheck"
 	machinesetcontroller "sigs.k8s.io/cluster-api/internal/controllers/machineset"
 	clustertopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/cluster"
+	garbagecollectortopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/garbagecollector"
 	machinedeploymenttopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machinedeployment"
 	machinesettopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machineset"
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
@@ -46,13 +49,31 @@ type ClusterReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// SyncPeriod is the interval at which Clusters are periodically reconciled; if zero, Clusters are reconciled
+	// at the sync period of the manager.
+	SyncPeriod time.Duration
+
+	// CircuitBreakerThreshold is the number of consecutive reconcile failures after which the circuit is opened
+	// for a Cluster, and the Cluster is reconciled only every CircuitBreakerRequeueAfter; zero disables the circuit breaker.
+	CircuitBreakerThreshold int
+
+	// CircuitBreakerRequeueAfter is the interval at which Clusters are reconciled while their circuit is open.
+	CircuitBreakerRequeueAfter time.Duration
+
+	// Tracker is used to read the state of the probes of the connection to the workload clusters.
+	Tracker *remote.ClusterCacheTracker
 }
 
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&clustercontroller.Reconciler{
-		Client:           r.Client,
-		APIReader:        r.APIReader,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:                     r.Client,
+		APIReader:                  r.APIReader,
+		WatchFilterValue:           r.WatchFilterValue,
+		SyncPeriod:                 r.SyncPeriod,
+		CircuitBreakerThreshold:    r.CircuitBreakerThreshold,
+		CircuitBreakerRequeueAfter: r.CircuitBreakerRequeueAfter,
+		Tracker:                    r.Tracker,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -64,14 +85,35 @@ type MachineReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// SyncPeriod is the interval at which Machines are periodically reconciled; if zero, Machines are reconciled
+	// at the sync period of the manager.
+	SyncPeriod time.Duration
+
+	// RecordDeletionEvents enables recording an event with the deletion cause of each deleted Machine.
+	// NOTE: Events are retained only for the event TTL of the kube-apiserver.
+	RecordDeletionEvents bool
+
+	// InfrastructureReadinessCheckers are checks which must pass, in addition to the infrastructure provider
+	// reporting status.ready, before a Machine is marked as InfrastructureReady.
+	InfrastructureReadinessCheckers []readiness.InfrastructureChecker
+
+	// CrossNamespaceRefsAllowList is a list of <Machine namespace>=<referenced namespace> pairs defining the namespaces
+	// Machines can reference bootstrap configs and infrastructure machines in, if the MachineCrossNamespaceRefs
+	// feature flag is enabled.
+	CrossNamespaceRefsAllowList []string
 }
 
 func (r *MachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&machinecontroller.Reconciler{
-		Client:           r.Client,
-		APIReader:        r.APIReader,
-		Tracker:          r.Tracker,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:                          r.Client,
+		APIReader:                       r.APIReader,
+		Tracker:                         r.Tracker,
+		WatchFilterValue:                r.WatchFilterValue,
+		SyncPeriod:                      r.SyncPeriod,
+		RecordDeletionEvents:            r.RecordDeletionEvents,
+		InfrastructureReadinessCheckers: r.InfrastructureReadinessCheckers,
+		CrossNamespaceRefsAllowList:     r.CrossNamespaceRefsAllowList,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -143,15 +185,35 @@ type ClusterTopologyReconciler struct {
 	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
 	// thus allowing to optimize reads for templates or provider specific objects in a managed topology.
 	UnstructuredCachingClient client.Client
+
+	// SyncPeriod is the interval at which Clusters with a managed topology are periodically reconciled; if zero,
+	// Clusters are reconciled at the sync period of the manager.
+	SyncPeriod time.Duration
+
+	// DriftCheckInterval is the interval between periodic checks for out-of-band changes to the objects
+	// generated from the topology of Clusters with the topology.cluster.x-k8s.io/drift-policy annotation.
+	DriftCheckInterval time.Duration
+
+	// MaxConcurrentApplies is the maximum number of objects generated from the topology of a Cluster, e.g. MachineDeployments,
+	// which are reconciled concurrently.
+	MaxConcurrentApplies int
+
+	// TemplateReaderServiceAccount is the name of the ServiceAccount, in the namespace of each Cluster, impersonated
+	// when reading the templates referenced by its ClusterClass.
+	TemplateReaderServiceAccount string
 }
 
 func (r *ClusterTopologyReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&clustertopologycontroller.Reconciler{
-		Client:                    r.Client,
-		APIReader:                 r.APIReader,
-		RuntimeClient:             r.RuntimeClient,
-		UnstructuredCachingClient: r.UnstructuredCachingClient,
-		WatchFilterValue:          r.WatchFilterValue,
+		Client:                       r.Client,
+		APIReader:                    r.APIReader,
+		RuntimeClient:                r.RuntimeClient,
+		UnstructuredCachingClient:    r.UnstructuredCachingClient,
+		WatchFilterValue:             r.WatchFilterValue,
+		SyncPeriod:                   r.SyncPeriod,
+		DriftCheckInterval:           r.DriftCheckInterval,
+		MaxConcurrentApplies:         r.MaxConcurrentApplies,
+		TemplateReaderServiceAccount: r.TemplateReaderServiceAccount,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -195,6 +257,33 @@ func (r *MachineSetTopologyReconciler) SetupWithManager(ctx context.Context, mgr
 	}).SetupWithManager(ctx, mgr, options)
 }
 
+// TopologyGarbageCollectorReconciler deletes orphaned objects generated from a Cluster topology, i.e. objects with the
+// topology owned label for a Cluster which are no longer referenced by the Cluster, its ControlPlane, MachineDeployments
+// or MachineSets, e.g. templates left behind by failed template rotations or by interrupted reconciles.
+type TopologyGarbageCollectorReconciler struct {
+	Client client.Client
+	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
+	// thus allowing to optimize reads for templates or provider specific objects.
+	UnstructuredCachingClient client.Client
+	WatchFilterValue          string
+
+	// Interval is the interval between periodic checks for orphaned objects.
+	Interval time.Duration
+
+	// GracePeriod is the minimum age of an orphaned object before it is deleted.
+	GracePeriod time.Duration
+}
+
+func (r *TopologyGarbageCollectorReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	return (&garbagecollectortopologycontroller.Reconciler{
+		Client:                    r.Client,
+		UnstructuredCachingClient: r.UnstructuredCachingClient,
+		WatchFilterValue:          r.WatchFilterValue,
+		Interval:                  r.Interval,
+		GracePeriod:               r.GracePeriod,
+	}).SetupWithManager(ctx, mgr, options)
+}
+
 // ClusterClassReconciler reconciles the ClusterClass object.
 type ClusterClassReconciler struct {
 	Client    client.Client
@@ -36,6 +36,9 @@ type ObjectTracker struct {
 	m sync.Map
 
 	Controller controller.Controller
+
+	// Predicates are added to the predicates of all the watches issued by the ObjectTracker.
+	Predicates []predicate.Predicate
 }
 
 // Watch uses the controller to issue a Watch only if the object hasn't been seen before.
@@ -58,7 +61,7 @@ func (o *ObjectTracker) Watch(log logr.Logger, obj runtime.Object, handler handl
 	err := o.Controller.Watch(
 		&source.Kind{Type: u},
 		handler,
-		append(p, predicates.ResourceNotPaused(log))...,
+		append(append(p, o.Predicates...), predicates.ResourceNotPaused(log))...,
 	)
 	if err != nil {
 		o.m.Delete(key)
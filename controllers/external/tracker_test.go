@@ -47,6 +47,8 @@ type watchCountController struct {
 	// no.of times Watch was called
 	count      int
 	raiseError bool
+	// no.of predicates passed to the last Watch call
+	predicates int
 }
 
 func newWatchCountController(raiseError bool) *watchCountController {
@@ -55,8 +57,9 @@ func newWatchCountController(raiseError bool) *watchCountController {
 	}
 }
 
-func (c *watchCountController) Watch(_ source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error {
+func (c *watchCountController) Watch(_ source.Source, _ handler.EventHandler, p ...predicate.Predicate) error {
 	c.count++
+	c.predicates = len(p)
 	if c.raiseError {
 		return errors.New("injected failure")
 	}
@@ -96,3 +99,18 @@ func TestWatchMultipleTimes(t *testing.T) {
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(ctrl.count).Should(Equal(1))
 }
+
+func TestWatchPredicates(t *testing.T) {
+	g := NewWithT(t)
+	ctrl := &watchCountController{}
+	tracker := ObjectTracker{
+		Controller: ctrl,
+		Predicates: []predicate.Predicate{predicate.ResourceVersionChangedPredicate{}},
+	}
+
+	// The predicates of the ObjectTracker are added to the predicates of the watch and to the ResourceNotPaused predicate.
+	err := tracker.Watch(logger, &clusterv1.Cluster{}, nil, predicate.GenerationChangedPredicate{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ctrl.count).Should(Equal(1))
+	g.Expect(ctrl.predicates).Should(Equal(3))
+}
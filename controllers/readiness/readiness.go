@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// InfrastructureChecker checks if the infrastructure of a Machine is ready, on top of the ready flag
+// reported by the infrastructure provider; e.g. if the cloud instance passed the health checks of the cloud provider.
+// NOTE: Checkers are only called while the Machine is waiting for its infrastructure to become ready; once the
+// Machine is marked as InfrastructureReady, checkers are not called anymore.
+type InfrastructureChecker interface {
+	// Name returns the name of the checker, which is used in conditions and logs.
+	Name() string
+
+	// IsReady returns true if the infrastructure of the Machine is ready; if not, it returns a message
+	// documenting what the Machine is waiting for.
+	IsReady(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, infraMachine *unstructured.Unstructured) (bool, string, error)
+}
+
+// Registry holds a set of InfrastructureCheckers.
+type Registry struct {
+	lock     sync.RWMutex
+	checkers []InfrastructureChecker
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds an InfrastructureChecker to the Registry.
+// An error is returned if a checker with the same name is already registered.
+func (r *Registry) Register(checker InfrastructureChecker) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, c := range r.checkers {
+		if c.Name() == checker.Name() {
+			return errors.Errorf("infrastructure readiness checker %q is already registered", checker.Name())
+		}
+	}
+	r.checkers = append(r.checkers, checker)
+	return nil
+}
+
+// Checkers returns the InfrastructureCheckers in the Registry, in registration order.
+func (r *Registry) Checkers() []InfrastructureChecker {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]InfrastructureChecker{}, r.checkers...)
+}
+
+// DefaultRegistry is the Registry of the InfrastructureCheckers compiled into the manager;
+// downstream builds can add checkers to it using Register, e.g. from an init function.
+var DefaultRegistry = NewRegistry()
+
+// Register adds an InfrastructureChecker to the DefaultRegistry.
+func Register(checker InfrastructureChecker) error {
+	return DefaultRegistry.Register(checker)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+type fakeChecker struct {
+	name string
+}
+
+func (f fakeChecker) Name() string {
+	return f.name
+}
+
+func (f fakeChecker) IsReady(_ context.Context, _ *clusterv1.Cluster, _ *clusterv1.Machine, _ *unstructured.Unstructured) (bool, string, error) {
+	return true, "", nil
+}
+
+func TestRegistry(t *testing.T) {
+	g := NewWithT(t)
+
+	r := NewRegistry()
+	g.Expect(r.Checkers()).To(BeEmpty())
+
+	g.Expect(r.Register(fakeChecker{name: "checker1"})).To(Succeed())
+	g.Expect(r.Register(fakeChecker{name: "checker2"})).To(Succeed())
+	g.Expect(r.Register(fakeChecker{name: "checker1"})).ToNot(Succeed())
+
+	checkers := r.Checkers()
+	g.Expect(checkers).To(HaveLen(2))
+	g.Expect(checkers[0].Name()).To(Equal("checker1"))
+	g.Expect(checkers[1].Name()).To(Equal("checker2"))
+}
+
+func TestWebhookChecker(t *testing.T) {
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1"}}
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "machine1"}}
+	infraMachine := &unstructured.Unstructured{}
+	infraMachine.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+	infraMachine.SetKind("GenericInfrastructureMachine")
+	infraMachine.SetName("infra-machine1")
+
+	tests := []struct {
+		name        string
+		status      int
+		response    *WebhookResponse
+		wantReady   bool
+		wantMessage string
+		wantErr     bool
+	}{
+		{
+			name:      "ready",
+			status:    http.StatusOK,
+			response:  &WebhookResponse{Ready: true},
+			wantReady: true,
+		},
+		{
+			name:        "not ready with message",
+			status:      http.StatusOK,
+			response:    &WebhookResponse{Message: "instance health checks pending"},
+			wantMessage: "instance health checks pending",
+		},
+		{
+			name:        "not ready without message",
+			status:      http.StatusOK,
+			response:    &WebhookResponse{},
+			wantMessage: `waiting for infrastructure readiness webhook "webhook1"`,
+		},
+		{
+			name:    "error status",
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				request := &WebhookRequest{}
+				g.Expect(json.NewDecoder(r.Body).Decode(request)).To(Succeed())
+				g.Expect(request.Cluster.Name).To(Equal("cluster1"))
+				g.Expect(request.Machine.Name).To(Equal("machine1"))
+				g.Expect(request.InfrastructureMachine.GetName()).To(Equal("infra-machine1"))
+
+				w.WriteHeader(tt.status)
+				if tt.response != nil {
+					g.Expect(json.NewEncoder(w).Encode(tt.response)).To(Succeed())
+				}
+			}))
+			defer server.Close()
+
+			checker := NewWebhookChecker("webhook1", server.URL, 5*time.Second)
+			g.Expect(checker.Name()).To(Equal("webhook1"))
+
+			ready, message, err := checker.IsReady(context.Background(), cluster, machine, infraMachine)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ready).To(Equal(tt.wantReady))
+			g.Expect(message).To(Equal(tt.wantMessage))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// WebhookRequest is the body of the requests sent by a webhook InfrastructureChecker.
+type WebhookRequest struct {
+	Cluster               *clusterv1.Cluster         `json:"cluster"`
+	Machine               *clusterv1.Machine         `json:"machine"`
+	InfrastructureMachine *unstructured.Unstructured `json:"infrastructureMachine"`
+}
+
+// WebhookResponse is the body of the responses expected by a webhook InfrastructureChecker.
+type WebhookResponse struct {
+	// Ready is true if the infrastructure of the Machine is ready.
+	Ready bool `json:"ready"`
+
+	// Message documents what the Machine is waiting for if the infrastructure is not ready.
+	Message string `json:"message,omitempty"`
+}
+
+type webhookChecker struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewWebhookChecker returns an InfrastructureChecker which POSTs a WebhookRequest to the given URL,
+// expecting a WebhookResponse.
+func NewWebhookChecker(name, url string, timeout time.Duration) InfrastructureChecker {
+	return &webhookChecker{
+		name:   name,
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *webhookChecker) Name() string {
+	return w.name
+}
+
+func (w *webhookChecker) IsReady(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine, infraMachine *unstructured.Unstructured) (bool, string, error) {
+	body, err := json.Marshal(&WebhookRequest{
+		Cluster:               cluster,
+		Machine:               machine,
+		InfrastructureMachine: infraMachine,
+	})
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to marshal request for infrastructure readiness webhook %q", w.name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to create request for infrastructure readiness webhook %q", w.name)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to call infrastructure readiness webhook %q", w.name)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, "", errors.Wrapf(err, "failed to read response of infrastructure readiness webhook %q", w.name)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, "", errors.Errorf("infrastructure readiness webhook %q returned status %d: %s", w.name, resp.StatusCode, string(respBody))
+	}
+
+	response := &WebhookResponse{}
+	if err := json.Unmarshal(respBody, response); err != nil {
+		return false, "", errors.Wrapf(err, "failed to unmarshal response of infrastructure readiness webhook %q", w.name)
+	}
+	if !response.Ready && response.Message == "" {
+		response.Message = fmt.Sprintf("waiting for infrastructure readiness webhook %q", w.name)
+	}
+	return response.Ready, response.Message, nil
+}
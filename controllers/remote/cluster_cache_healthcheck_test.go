@@ -24,6 +24,7 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -144,6 +145,11 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 				_, ok := cct.loadAccessor(testClusterKey)
 				return ok
 			}, 5*time.Second, 1*time.Second).Should(BeTrue())
+
+			state, ok := cct.GetHealthCheckingState(testClusterKey)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(state.ConsecutiveFailures).To(Equal(0))
+			g.Expect(state.LastProbeSuccessTime.IsZero()).To(BeFalse())
 		})
 
 		t.Run("with an invalid path", func(t *testing.T) {
@@ -169,6 +175,11 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 				_, ok := cct.loadAccessor(testClusterKey)
 				return ok
 			}, 5*time.Second, 1*time.Second).Should(BeFalse())
+
+			state, ok := cct.GetHealthCheckingState(testClusterKey)
+			g.Expect(ok).To(BeTrue())
+			g.Expect(state.ConsecutiveFailures).To(BeNumerically(">=", testUnhealthyThreshold))
+			g.Expect(state.LastProbeError).To(HaveOccurred())
 		})
 
 		t.Run("with an invalid config", func(t *testing.T) {
@@ -206,3 +217,30 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 		})
 	})
 }
+
+func TestHealthCheckingState(t *testing.T) {
+	g := NewWithT(t)
+
+	cct := &ClusterCacheTracker{}
+	cluster := client.ObjectKey{Namespace: "default", Name: "test-cluster"}
+
+	_, ok := cct.GetHealthCheckingState(cluster)
+	g.Expect(ok).To(BeFalse())
+
+	t1 := time.Now()
+	cct.recordProbe(cluster, t1, time.Millisecond, nil)
+	state, ok := cct.GetHealthCheckingState(cluster)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(state).To(Equal(HealthCheckingState{LastProbeTime: t1, LastProbeSuccessTime: t1}))
+
+	t2 := t1.Add(time.Second)
+	probeErr := errors.New("connection refused")
+	cct.recordProbe(cluster, t2, time.Second, probeErr)
+	cct.recordProbe(cluster, t2, time.Second, probeErr)
+	state, _ = cct.GetHealthCheckingState(cluster)
+	g.Expect(state).To(Equal(HealthCheckingState{LastProbeTime: t2, LastProbeSuccessTime: t1, ConsecutiveFailures: 2, LastProbeError: probeErr}))
+
+	cct.deleteHealthCheckingState(cluster)
+	_, ok = cct.GetHealthCheckingState(cluster)
+	g.Expect(ok).To(BeFalse())
+}
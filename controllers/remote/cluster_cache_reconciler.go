@@ -74,6 +74,7 @@ func (r *ClusterCacheReconciler) Reconcile(ctx context.Context, req reconcile.Re
 	log.V(2).Info("Cluster no longer exists")
 
 	r.Tracker.deleteAccessor(ctx, req.NamespacedName)
+	r.Tracker.deleteHealthCheckingState(req.NamespacedName)
 
 	return reconcile.Result{}, nil
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -48,6 +49,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 )
 
 const (
@@ -59,8 +61,9 @@ const (
 )
 
 // ErrClusterLocked is returned in methods that require cluster-level locking
-// if the cluster is already locked by another concurrent call.
-var ErrClusterLocked = errors.New("cluster is locked already")
+// if the cluster is already locked by another concurrent call; it is a RequeueAfterError,
+// so reconciliation is retried as soon as possible when using utilerrors.ReconcileResult.
+var ErrClusterLocked error = &utilerrors.RequeueAfterError{Reason: "ClusterLocked", Message: "cluster is locked already"}
 
 // ClusterCacheTracker manages client caches for workload clusters.
 type ClusterCacheTracker struct {
@@ -84,6 +87,11 @@ type ClusterCacheTracker struct {
 	// This information will be used to detected if the controller is running on a workload cluster, so
 	// that we can then access the apiserver directly.
 	controllerPodMetadata *metav1.ObjectMeta
+
+	// healthCheckingStatesLock is used to lock the access to the healthCheckingStates map.
+	healthCheckingStatesLock sync.RWMutex
+	// healthCheckingStates is the map of the states of the health checks by cluster.
+	healthCheckingStates map[client.ObjectKey]HealthCheckingState
 }
 
 // ClusterCacheTrackerOptions defines options to configure
@@ -142,6 +150,7 @@ func NewClusterCacheTracker(manager ctrl.Manager, options ClusterCacheTrackerOpt
 		clusterAccessors:      make(map[client.ObjectKey]*clusterAccessor),
 		clusterLock:           newKeyedMutex(),
 		indexes:               options.Indexes,
+		healthCheckingStates:  make(map[client.ObjectKey]HealthCheckingState),
 	}, nil
 }
 
@@ -465,6 +474,68 @@ func (t *ClusterCacheTracker) Watch(ctx context.Context, input WatchInput) error
 	return nil
 }
 
+// HealthCheckingState is the state of the periodic probes of the connection to the API server of a workload cluster.
+type HealthCheckingState struct {
+	// LastProbeTime is the time of the last probe.
+	LastProbeTime time.Time
+
+	// LastProbeSuccessTime is the time of the last successful probe.
+	LastProbeSuccessTime time.Time
+
+	// ConsecutiveFailures is the number of consecutive failed probes.
+	ConsecutiveFailures int
+
+	// LastProbeError is the error of the last probe, if it failed.
+	LastProbeError error
+}
+
+// GetHealthCheckingState returns the state of the probes of the connection to the API server of the given cluster,
+// if the connection has been probed at least once.
+// NOTE: The state is preserved when the cache of a cluster is dropped because of failed probes, and it is deleted
+// only when the cluster is deleted.
+func (t *ClusterCacheTracker) GetHealthCheckingState(cluster client.ObjectKey) (HealthCheckingState, bool) {
+	t.healthCheckingStatesLock.RLock()
+	defer t.healthCheckingStatesLock.RUnlock()
+
+	state, ok := t.healthCheckingStates[cluster]
+	return state, ok
+}
+
+// recordProbe records the outcome of a probe of the connection to the API server of the given cluster.
+func (t *ClusterCacheTracker) recordProbe(cluster client.ObjectKey, probeTime time.Time, latency time.Duration, probeErr error) {
+	t.healthCheckingStatesLock.Lock()
+	defer t.healthCheckingStatesLock.Unlock()
+
+	if t.healthCheckingStates == nil {
+		t.healthCheckingStates = make(map[client.ObjectKey]HealthCheckingState)
+	}
+	state := t.healthCheckingStates[cluster]
+	state.LastProbeTime = probeTime
+	state.LastProbeError = probeErr
+	if probeErr == nil {
+		state.LastProbeSuccessTime = probeTime
+		state.ConsecutiveFailures = 0
+	} else {
+		state.ConsecutiveFailures++
+	}
+	t.healthCheckingStates[cluster] = state
+
+	result := "success"
+	if probeErr != nil {
+		result = "failure"
+	}
+	remoteConnectionProbeDuration.WithLabelValues(cluster.Namespace, cluster.Name, result).Observe(latency.Seconds())
+}
+
+// deleteHealthCheckingState deletes the state of the probes of the connection to the API server of the given cluster.
+func (t *ClusterCacheTracker) deleteHealthCheckingState(cluster client.ObjectKey) {
+	t.healthCheckingStatesLock.Lock()
+	defer t.healthCheckingStatesLock.Unlock()
+
+	delete(t.healthCheckingStates, cluster)
+	remoteConnectionProbeDuration.DeletePartialMatch(prometheus.Labels{"namespace": cluster.Namespace, "cluster": cluster.Name})
+}
+
 // healthCheckInput provides the input for the healthCheckCluster method.
 type healthCheckInput struct {
 	cluster            client.ObjectKey
@@ -535,7 +606,9 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 
 		// An error here means there was either an issue connecting or the API returned an error.
 		// If no error occurs, reset the unhealthy counter.
+		probeTime := time.Now()
 		_, err := restClient.Get().AbsPath(in.path).Timeout(in.requestTimeout).DoRaw(ctx)
+		t.recordProbe(in.cluster, probeTime, time.Since(probeTime), err)
 		if err != nil {
 			if apierrors.IsUnauthorized(err) {
 				// Unauthorized means that the underlying kubeconfig is not authorizing properly anymore, which
@@ -0,0 +1,35 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(remoteConnectionProbeDuration)
+}
+
+// remoteConnectionProbeDuration reports the latency of the probes of the connection to the API server of workload clusters.
+var remoteConnectionProbeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Subsystem: "capi_remote",
+	Name:      "connection_probe_duration_seconds",
+	Help:      "Latency of the probes of the connection to the API server of workload clusters, partitioned by Cluster and result of the probe.",
+	Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+}, []string{"namespace", "cluster", "result"})
@@ -43,7 +43,10 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	dst.Spec.KubeadmConfigSpec.Files = restored.Spec.KubeadmConfigSpec.Files
 	dst.Spec.KubeadmConfigSpec.Users = restored.Spec.KubeadmConfigSpec.Users
 	dst.Spec.MachineTemplate.NodeVolumeDetachTimeout = restored.Spec.MachineTemplate.NodeVolumeDetachTimeout
+	dst.Spec.MachineTemplate.AdditionalTags = restored.Spec.MachineTemplate.AdditionalTags
+	dst.Spec.MachineTemplate.FailureDomainInfrastructureRefs = restored.Spec.MachineTemplate.FailureDomainInfrastructureRefs
 	dst.Status.Version = restored.Status.Version
+	dst.Status.UpgradePlan = restored.Status.UpgradePlan
 
 	if restored.Spec.KubeadmConfigSpec.Users != nil {
 		for i := range restored.Spec.KubeadmConfigSpec.Users {
@@ -84,6 +87,7 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 	}
 
 	dst.Spec.RolloutBefore = restored.Spec.RolloutBefore
+	dst.Spec.RemediationStrategy = restored.Spec.RemediationStrategy
 
 	return nil
 }
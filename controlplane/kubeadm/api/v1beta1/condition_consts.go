@@ -132,6 +132,10 @@ const (
 	// EtcdMemberUnhealthyReason (Severity=Error) documents a Machine's etcd member is unhealthy.
 	EtcdMemberUnhealthyReason = "EtcdMemberUnhealthy"
 
+	// EtcdMemberNoSpaceAlarmReason (Severity=Error) documents a Machine's etcd member is reporting only a NOSPACE alarm,
+	// which can be disarmed by etcd maintenance once defragmentation has freed space.
+	EtcdMemberNoSpaceAlarmReason = "EtcdMemberNoSpaceAlarm"
+
 	// MachinesCreatedCondition documents that the machines controlled by the KubeadmControlPlane are created.
 	// When this condition is false, it indicates that there was an error when cloning the infrastructure/bootstrap template or
 	// when generating the machine object.
@@ -49,6 +49,14 @@ const (
 	// KubeadmClusterConfigurationAnnotation is a machine annotation that stores the json-marshalled string of KCP ClusterConfiguration.
 	// This annotation is used to detect any changes in ClusterConfiguration and trigger machine rollout in KCP.
 	KubeadmClusterConfigurationAnnotation = "controlplane.cluster.x-k8s.io/kubeadm-cluster-configuration"
+
+	// EtcdLastMaintenanceAnnotation is the annotation set by KCP with the time of the last etcd maintenance, successful
+	// or not, in RFC3339 format; it is used to schedule periodic etcd maintenance when enabled.
+	EtcdLastMaintenanceAnnotation = "controlplane.cluster.x-k8s.io/etcd-last-maintenance"
+
+	// ProvisioningRemediationRetriesAnnotation is the annotation set by KCP with the number of consecutive
+	// remediations of control plane machines failing provisioning.
+	ProvisioningRemediationRetriesAnnotation = "controlplane.cluster.x-k8s.io/provisioning-remediation-retries"
 )
 
 // KubeadmControlPlaneSpec defines the desired state of KubeadmControlPlane.
@@ -86,6 +94,11 @@ type KubeadmControlPlaneSpec struct {
 	// +optional
 	// +kubebuilder:default={type: "RollingUpdate", rollingUpdate: {maxSurge: 1}}
 	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// The RemediationStrategy that controls how control plane machines failing
+	// provisioning are remediated.
+	// +optional
+	RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
 }
 
 // KubeadmControlPlaneMachineTemplate defines the template for Machines
@@ -100,6 +113,21 @@ type KubeadmControlPlaneMachineTemplate struct {
 	// offered by an infrastructure provider.
 	InfrastructureRef corev1.ObjectReference `json:"infrastructureRef"`
 
+	// FailureDomainInfrastructureRefs is an optional list of references to custom resources offered by an
+	// infrastructure provider, to be used instead of InfrastructureRef for Machines in specific failure domains,
+	// e.g. to use different instance types in each zone.
+	// NOTE: Changing this field triggers a rollout of the control plane Machines in the affected failure domains.
+	// +optional
+	// +listType=map
+	// +listMapKey=failureDomain
+	FailureDomainInfrastructureRefs []FailureDomainInfrastructureRef `json:"failureDomainInfrastructureRefs,omitempty"`
+
+	// AdditionalTags is an optional set of tags to be added to the cloud resources created by the
+	// infrastructure provider for control plane Machines.
+	// NOTE: Changing this field triggers a rollout of the control plane Machines.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+
 	// NodeDrainTimeout is the total amount of time that the controller will spend on draining a controlplane node
 	// The default value is 0, meaning that the node can be drained without any time limitations.
 	// NOTE: NodeDrainTimeout is different from `kubectl drain --timeout`
@@ -118,6 +146,30 @@ type KubeadmControlPlaneMachineTemplate struct {
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
 }
 
+// FailureDomainInfrastructureRef defines the infrastructure template to be used for Machines in a failure domain.
+type FailureDomainInfrastructureRef struct {
+	// FailureDomain is the name of the failure domain.
+	// Must match a key in the FailureDomains map stored on the Cluster object.
+	FailureDomain string `json:"failureDomain"`
+
+	// InfrastructureRef is a required reference to a custom resource
+	// offered by an infrastructure provider.
+	InfrastructureRef corev1.ObjectReference `json:"infrastructureRef"`
+}
+
+// InfrastructureRefForFailureDomain returns the reference to the infrastructure template to be used for Machines
+// in the given failure domain; InfrastructureRef is returned if no specific template is defined for the failure domain.
+func (m *KubeadmControlPlaneMachineTemplate) InfrastructureRefForFailureDomain(failureDomain *string) *corev1.ObjectReference {
+	if failureDomain != nil {
+		for i := range m.FailureDomainInfrastructureRefs {
+			if m.FailureDomainInfrastructureRefs[i].FailureDomain == *failureDomain {
+				return &m.FailureDomainInfrastructureRefs[i].InfrastructureRef
+			}
+		}
+	}
+	return &m.InfrastructureRef
+}
+
 // RolloutBefore describes when a rollout should be performed on the KCP machines.
 type RolloutBefore struct {
 	// CertificatesExpiryDays indicates a rollout needs to be performed if the
@@ -153,6 +205,22 @@ type RollingUpdate struct {
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
+// RemediationStrategy allows to define how control plane machines failing provisioning are remediated.
+type RemediationStrategy struct {
+	// ProvisioningTimeout is the maximum time a control plane machine is allowed to stay without a Node
+	// before it is considered failed and it is remediated by deleting and recreating it; this applies
+	// also to the first control plane machine, when the control plane is not initialized yet.
+	// If not set, only machines marked as unhealthy by a MachineHealthCheck are remediated.
+	// +optional
+	ProvisioningTimeout *metav1.Duration `json:"provisioningTimeout,omitempty"`
+
+	// MaxRetry is the maximum number of consecutive remediations of control plane machines failing provisioning;
+	// the counter is reset once all the control plane machines have a Node.
+	// If not set, machines failing provisioning are remediated without limits.
+	// +optional
+	MaxRetry *int32 `json:"maxRetry,omitempty"`
+}
+
 // KubeadmControlPlaneStatus defines the observed state of KubeadmControlPlane.
 type KubeadmControlPlaneStatus struct {
 	// Selector is the label selector in string format to avoid introspection
@@ -218,6 +286,41 @@ type KubeadmControlPlaneStatus struct {
 	// Conditions defines current service state of the KubeadmControlPlane.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// UpgradePlan is the plan of the rollout in progress, if any; it is removed once
+	// all the control plane machines are up to date.
+	// +optional
+	UpgradePlan *UpgradePlan `json:"upgradePlan,omitempty"`
+}
+
+// UpgradePlan defines the ordered list of control plane machines to be replaced by a rollout.
+type UpgradePlan struct {
+	// Version is the Kubernetes version the control plane is rolled out to.
+	Version string `json:"version"`
+
+	// Steps is the ordered list of control plane machines to be replaced; machines are replaced one at a time.
+	// Steps are computed when the rollout starts, and they are recomputed only if machines not part of
+	// the plan require to be rolled out.
+	// +optional
+	Steps []UpgradePlanStep `json:"steps,omitempty"`
+
+	// CurrentStep is the index in Steps of the control plane machine currently being replaced.
+	// +optional
+	CurrentStep int32 `json:"currentStep"`
+}
+
+// UpgradePlanStep defines the replacement of a control plane machine.
+type UpgradePlanStep struct {
+	// MachineName is the name of the control plane machine to be replaced.
+	MachineName string `json:"machineName"`
+
+	// FailureDomain is the failure domain of the control plane machine to be replaced.
+	// +optional
+	FailureDomain *string `json:"failureDomain,omitempty"`
+
+	// TargetFailureDomain is the failure domain where the replacement control plane machine is created.
+	// +optional
+	TargetFailureDomain *string `json:"targetFailureDomain,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -35,6 +35,7 @@ import (
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/container"
 	"sigs.k8s.io/cluster-api/util/version"
+	capiversion "sigs.k8s.io/cluster-api/version"
 )
 
 func (in *KubeadmControlPlane) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -64,6 +65,12 @@ func defaultKubeadmControlPlaneSpec(s *KubeadmControlPlaneSpec, namespace string
 		s.MachineTemplate.InfrastructureRef.Namespace = namespace
 	}
 
+	for i := range s.MachineTemplate.FailureDomainInfrastructureRefs {
+		if s.MachineTemplate.FailureDomainInfrastructureRefs[i].InfrastructureRef.Namespace == "" {
+			s.MachineTemplate.FailureDomainInfrastructureRefs[i].InfrastructureRef.Namespace = namespace
+		}
+	}
+
 	if !strings.HasPrefix(s.Version, "v") {
 		s.Version = "v" + s.Version
 	}
@@ -102,6 +109,7 @@ func (in *KubeadmControlPlane) ValidateCreate() error {
 	allErrs := validateKubeadmControlPlaneSpec(spec, in.Namespace, field.NewPath("spec"))
 	allErrs = append(allErrs, validateClusterConfiguration(spec.KubeadmConfigSpec.ClusterConfiguration, nil, field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration"))...)
 	allErrs = append(allErrs, spec.KubeadmConfigSpec.Validate(field.NewPath("spec", "kubeadmConfigSpec"))...)
+	allErrs = append(allErrs, validateSupportedVersion(spec.Version)...)
 	if len(allErrs) > 0 {
 		return apierrors.NewInvalid(GroupVersion.WithKind("KubeadmControlPlane").GroupKind(), in.Name, allErrs)
 	}
@@ -162,6 +170,7 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, "machineTemplate", "infrastructureRef", "apiVersion"},
 		{spec, "machineTemplate", "infrastructureRef", "name"},
 		{spec, "machineTemplate", "infrastructureRef", "kind"},
+		{spec, "machineTemplate", "failureDomainInfrastructureRefs"},
 		{spec, "machineTemplate", "nodeDrainTimeout"},
 		{spec, "machineTemplate", "nodeVolumeDetachTimeout"},
 		{spec, "machineTemplate", "nodeDeletionTimeout"},
@@ -170,6 +179,7 @@ func (in *KubeadmControlPlane) ValidateUpdate(old runtime.Object) error {
 		{spec, "rolloutAfter"},
 		{spec, "rolloutBefore", "*"},
 		{spec, "rolloutStrategy", "*"},
+		{spec, "remediationStrategy", "*"},
 	}
 
 	allErrs := validateKubeadmControlPlaneSpec(in.Spec, in.Namespace, field.NewPath("spec"))
@@ -315,16 +325,46 @@ func validateKubeadmControlPlaneSpec(s KubeadmControlPlaneSpec, namespace string
 		)
 	}
 
+	allErrs = append(allErrs, validateFailureDomainInfrastructureRefs(s.MachineTemplate.FailureDomainInfrastructureRefs, namespace, pathPrefix.Child("machineTemplate", "failureDomainInfrastructureRefs"))...)
+
 	if !version.KubeSemver.MatchString(s.Version) {
 		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("version"), s.Version, "must be a valid semantic version"))
 	}
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, s.Replicas, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateRemediationStrategy(s.RemediationStrategy, pathPrefix.Child("remediationStrategy"))...)
 
 	return allErrs
 }
 
+func validateFailureDomainInfrastructureRefs(refs []FailureDomainInfrastructureRef, namespace string, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	failureDomains := map[string]bool{}
+	for i, ref := range refs {
+		if ref.FailureDomain == "" {
+			allErrs = append(allErrs, field.Required(pathPrefix.Index(i).Child("failureDomain"), "cannot be empty"))
+		} else if failureDomains[ref.FailureDomain] {
+			allErrs = append(allErrs, field.Duplicate(pathPrefix.Index(i).Child("failureDomain"), ref.FailureDomain))
+		}
+		failureDomains[ref.FailureDomain] = true
+
+		if ref.InfrastructureRef.APIVersion == "" {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Index(i).Child("infrastructureRef", "apiVersion"), ref.InfrastructureRef.APIVersion, "cannot be empty"))
+		}
+		if ref.InfrastructureRef.Kind == "" {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Index(i).Child("infrastructureRef", "kind"), ref.InfrastructureRef.Kind, "cannot be empty"))
+		}
+		if ref.InfrastructureRef.Name == "" {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Index(i).Child("infrastructureRef", "name"), ref.InfrastructureRef.Name, "cannot be empty"))
+		}
+		if ref.InfrastructureRef.Namespace != namespace {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Index(i).Child("infrastructureRef", "namespace"), ref.InfrastructureRef.Namespace, "must match metadata.namespace"))
+		}
+	}
+	return allErrs
+}
+
 func validateRolloutBefore(rolloutBefore *RolloutBefore, pathPrefix *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -384,6 +424,24 @@ func validateRolloutStrategy(rolloutStrategy *RolloutStrategy, replicas *int32,
 	return allErrs
 }
 
+func validateRemediationStrategy(remediationStrategy *RemediationStrategy, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if remediationStrategy == nil {
+		return allErrs
+	}
+
+	if remediationStrategy.ProvisioningTimeout != nil && remediationStrategy.ProvisioningTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("provisioningTimeout"), remediationStrategy.ProvisioningTimeout.Duration.String(), "must be greater than 0"))
+	}
+
+	if remediationStrategy.MaxRetry != nil && *remediationStrategy.MaxRetry < 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("maxRetry"), *remediationStrategy.MaxRetry, "must be greater than or equal to 0"))
+	}
+
+	return allErrs
+}
+
 func validateClusterConfiguration(newClusterConfiguration, oldClusterConfiguration *bootstrapv1.ClusterConfiguration, pathPrefix *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -598,6 +656,10 @@ func (in *KubeadmControlPlane) validateVersion(previousVersion string) (allErrs
 		return allErrs
 	}
 
+	if fromVersion.NE(toVersion) {
+		allErrs = append(allErrs, validateSupportedVersion(in.Spec.Version)...)
+	}
+
 	// Since upgrades to the next minor version are allowed, irrespective of the patch version.
 	ceilVersion := semver.Version{
 		Major: fromVersion.Major,
@@ -616,6 +678,21 @@ func (in *KubeadmControlPlane) validateVersion(previousVersion string) (allErrs
 	return allErrs
 }
 
+// validateSupportedVersion forbids Kubernetes versions newer than the ones supported by this release of Cluster API.
+// NOTE: Versions older than the supported ones are only reported by the KubernetesVersionSupported condition on the Cluster,
+// so it is still possible to upgrade out of them.
+func validateSupportedVersion(v string) (allErrs field.ErrorList) {
+	if compare, err := capiversion.SupportedWorkloadKubernetesVersions.Compare(v); err == nil && compare == 1 {
+		allErrs = append(allErrs,
+			field.Forbidden(
+				field.NewPath("spec", "version"),
+				fmt.Sprintf("Kubernetes version %s is not supported, supported versions are %s", v, capiversion.SupportedWorkloadKubernetesVersions),
+			),
+		)
+	}
+	return allErrs
+}
+
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
 func (in *KubeadmControlPlane) ValidateDelete() error {
 	return nil
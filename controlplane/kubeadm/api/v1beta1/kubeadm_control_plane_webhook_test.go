@@ -110,6 +110,28 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	invalidNamespace := valid.DeepCopy()
 	invalidNamespace.Spec.MachineTemplate.InfrastructureRef.Namespace = "bar"
 
+	validFailureDomainInfrastructureRefs := valid.DeepCopy()
+	validFailureDomainInfrastructureRefs.Spec.MachineTemplate.FailureDomainInfrastructureRefs = []FailureDomainInfrastructureRef{
+		{
+			FailureDomain: "fd1",
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "test/v1alpha1",
+				Kind:       "UnknownInfraMachine",
+				Namespace:  "foo",
+				Name:       "infraTemplate-fd1",
+			},
+		},
+	}
+
+	duplicateFailureDomainInfrastructureRefs := validFailureDomainInfrastructureRefs.DeepCopy()
+	duplicateFailureDomainInfrastructureRefs.Spec.MachineTemplate.FailureDomainInfrastructureRefs = append(
+		duplicateFailureDomainInfrastructureRefs.Spec.MachineTemplate.FailureDomainInfrastructureRefs,
+		duplicateFailureDomainInfrastructureRefs.Spec.MachineTemplate.FailureDomainInfrastructureRefs[0],
+	)
+
+	invalidNamespaceFailureDomainInfrastructureRefs := validFailureDomainInfrastructureRefs.DeepCopy()
+	invalidNamespaceFailureDomainInfrastructureRefs.Spec.MachineTemplate.FailureDomainInfrastructureRefs[0].InfrastructureRef.Namespace = "bar"
+
 	missingReplicas := valid.DeepCopy()
 	missingReplicas.Spec.Replicas = nil
 
@@ -137,6 +159,9 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	invalidVersion2 := valid.DeepCopy()
 	invalidVersion2.Spec.Version = "1.16.6"
 
+	unsupportedVersion := valid.DeepCopy()
+	unsupportedVersion.Spec.Version = "v1.27.0"
+
 	invalidCoreDNSVersion := valid.DeepCopy()
 	invalidCoreDNSVersion.Spec.KubeadmConfigSpec.ClusterConfiguration.DNS.ImageTag = "v1.7" // not a valid semantic version
 
@@ -145,6 +170,22 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 		CertificatesExpiryDays: pointer.Int32(5), // less than minimum
 	}
 
+	validRemediationStrategy := valid.DeepCopy()
+	validRemediationStrategy.Spec.RemediationStrategy = &RemediationStrategy{
+		ProvisioningTimeout: &metav1.Duration{Duration: 20 * time.Minute},
+		MaxRetry:            pointer.Int32(3),
+	}
+
+	invalidRemediationStrategyProvisioningTimeout := valid.DeepCopy()
+	invalidRemediationStrategyProvisioningTimeout.Spec.RemediationStrategy = &RemediationStrategy{
+		ProvisioningTimeout: &metav1.Duration{Duration: 0},
+	}
+
+	invalidRemediationStrategyMaxRetry := valid.DeepCopy()
+	invalidRemediationStrategyMaxRetry.Spec.RemediationStrategy = &RemediationStrategy{
+		MaxRetry: pointer.Int32(-1),
+	}
+
 	invalidIgnitionConfiguration := valid.DeepCopy()
 	invalidIgnitionConfiguration.Spec.KubeadmConfigSpec.Ignition = &bootstrapv1.IgnitionSpec{}
 
@@ -168,6 +209,21 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidNamespace,
 		},
+		{
+			name:      "should succeed when given valid failure domain infrastructure references",
+			expectErr: false,
+			kcp:       validFailureDomainInfrastructureRefs,
+		},
+		{
+			name:      "should return error when a failure domain has more than one infrastructure reference",
+			expectErr: true,
+			kcp:       duplicateFailureDomainInfrastructureRefs,
+		},
+		{
+			name:      "should return error when kubeadmControlPlane namespace and failure domain infrastructureRef namespace mismatch",
+			expectErr: true,
+			kcp:       invalidNamespaceFailureDomainInfrastructureRefs,
+		},
 		{
 			name:      "should return error when replicas is nil",
 			expectErr: true,
@@ -203,6 +259,11 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidVersion1,
 		},
+		{
+			name:      "should return error when given a version newer than the supported versions",
+			expectErr: true,
+			kcp:       unsupportedVersion,
+		},
 		{
 			name:      "should return error when given an invalid semantic CoreDNS version",
 			expectErr: true,
@@ -223,6 +284,21 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidRolloutBeforeCertificateExpiryDays,
 		},
+		{
+			name:      "should succeed when given a valid remediationStrategy",
+			expectErr: false,
+			kcp:       validRemediationStrategy,
+		},
+		{
+			name:      "should return error when remediationStrategy.provisioningTimeout is not greater than 0",
+			expectErr: true,
+			kcp:       invalidRemediationStrategyProvisioningTimeout,
+		},
+		{
+			name:      "should return error when remediationStrategy.maxRetry is negative",
+			expectErr: true,
+			kcp:       invalidRemediationStrategyMaxRetry,
+		},
 
 		{
 			name:                  "should return error when Ignition configuration is invalid",
@@ -348,6 +424,12 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	wrongReplicaCountForScaleIn := before.DeepCopy()
 	wrongReplicaCountForScaleIn.Spec.RolloutStrategy.RollingUpdate.MaxSurge.IntVal = int32(0)
 
+	updateRemediationStrategy := before.DeepCopy()
+	updateRemediationStrategy.Spec.RemediationStrategy = &RemediationStrategy{
+		ProvisioningTimeout: &metav1.Duration{Duration: 20 * time.Minute},
+		MaxRetry:            pointer.Int32(3),
+	}
+
 	invalidUpdateKubeadmConfigInit := before.DeepCopy()
 	invalidUpdateKubeadmConfigInit.Spec.KubeadmConfigSpec.InitConfiguration = &bootstrapv1.InitConfiguration{}
 
@@ -615,6 +697,10 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 	disallowedUpgrade119Version := before.DeepCopy()
 	disallowedUpgrade119Version.Spec.Version = "v1.19.0"
 
+	unsupportedUpgrade126Prev := prevKCPWithVersion("v1.26.3")
+	unsupportedUpgrade127Version := before.DeepCopy()
+	unsupportedUpgrade127Version.Spec.Version = "v1.27.0"
+
 	disallowedUpgrade120AlphaVersion := before.DeepCopy()
 	disallowedUpgrade120AlphaVersion.Spec.Version = "v1.20.0-alpha.0.734_ba502ee555924a"
 
@@ -943,6 +1029,12 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    disallowedUpgrade118Prev,
 			kcp:       disallowedUpgrade119Version,
 		},
+		{
+			name:      "should return error when trying to upgrade to a version newer than the supported versions",
+			expectErr: true,
+			before:    unsupportedUpgrade126Prev,
+			kcp:       unsupportedUpgrade127Version,
+		},
 		{
 			name:      "should return error when trying to upgrade two minor versions",
 			expectErr: true,
@@ -961,6 +1053,12 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    before,
 			kcp:       wrongReplicaCountForScaleIn,
 		},
+		{
+			name:      "should succeed when remediationStrategy is updated",
+			expectErr: false,
+			before:    before,
+			kcp:       updateRemediationStrategy,
+		},
 		{
 			name:      "should pass if NTP servers are updated",
 			expectErr: false,
@@ -36,6 +36,10 @@ type KubeadmControlPlaneReconciler struct {
 
 	EtcdDialTimeout time.Duration
 
+	// EtcdMaintenanceInterval is the interval between periodic etcd maintenance operations;
+	// if zero etcd maintenance is disabled.
+	EtcdMaintenanceInterval time.Duration
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
@@ -43,10 +47,11 @@ type KubeadmControlPlaneReconciler struct {
 // SetupWithManager sets up the reconciler with the Manager.
 func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&kubeadmcontrolplanecontrollers.KubeadmControlPlaneReconciler{
-		Client:           r.Client,
-		APIReader:        r.APIReader,
-		Tracker:          r.Tracker,
-		EtcdDialTimeout:  r.EtcdDialTimeout,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:                  r.Client,
+		APIReader:               r.APIReader,
+		Tracker:                 r.Tracker,
+		EtcdDialTimeout:         r.EtcdDialTimeout,
+		EtcdMaintenanceInterval: r.EtcdMaintenanceInterval,
+		WatchFilterValue:        r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -98,9 +98,10 @@ func (c *ControlPlane) Version() *string {
 	return &c.KCP.Spec.Version
 }
 
-// MachineInfrastructureTemplateRef returns the KubeadmControlPlane's infrastructure template for Machines.
-func (c *ControlPlane) MachineInfrastructureTemplateRef() *corev1.ObjectReference {
-	return &c.KCP.Spec.MachineTemplate.InfrastructureRef
+// MachineInfrastructureTemplateRef returns the KubeadmControlPlane's infrastructure template for Machines
+// in the given failure domain.
+func (c *ControlPlane) MachineInfrastructureTemplateRef(failureDomain *string) *corev1.ObjectReference {
+	return c.KCP.Spec.MachineTemplate.InfrastructureRefForFailureDomain(failureDomain)
 }
 
 // AsOwnerReference returns an owner reference to the KubeadmControlPlane.
@@ -263,6 +264,8 @@ func (c *ControlPlane) MachinesNeedingRollout() collections.Machines {
 		collections.ShouldRolloutAfter(&c.reconciliationTime, c.KCP.Spec.RolloutAfter),
 		// Machines that do not match with KCP config.
 		collections.Not(MatchesMachineSpec(c.infraResources, c.kubeadmConfigs, c.KCP)),
+		// Machines that have been bootstrapped with a different control plane endpoint.
+		collections.Not(MatchesControlPlaneEndpoint(c.kubeadmConfigs, c.KCP, c.controlPlaneEndpoint())),
 	)
 }
 
@@ -276,9 +279,19 @@ func (c *ControlPlane) UpToDateMachines() collections.Machines {
 		collections.Not(collections.ShouldRolloutAfter(&c.reconciliationTime, c.KCP.Spec.RolloutAfter)),
 		// Machines that match with KCP config.
 		MatchesMachineSpec(c.infraResources, c.kubeadmConfigs, c.KCP),
+		// Machines that have been bootstrapped with the current control plane endpoint.
+		MatchesControlPlaneEndpoint(c.kubeadmConfigs, c.KCP, c.controlPlaneEndpoint()),
 	)
 }
 
+// controlPlaneEndpoint returns the control plane endpoint of the Cluster, if any.
+func (c *ControlPlane) controlPlaneEndpoint() clusterv1.APIEndpoint {
+	if c.Cluster == nil {
+		return clusterv1.APIEndpoint{}
+	}
+	return c.Cluster.Spec.ControlPlaneEndpoint
+}
+
 // getInfraResources fetches the external infrastructure resource for each machine in the collection and returns a map of machine.Name -> infraResource.
 func getInfraResources(ctx context.Context, cl client.Client, machines collections.Machines) (map[string]*unstructured.Unstructured, error) {
 	result := map[string]*unstructured.Unstructured{}
@@ -44,16 +44,23 @@ import (
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/deletioncause"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
 	"sigs.k8s.io/cluster-api/util/version"
 )
 
+const (
+	// controllerName defines the controller name used when recording events and the Machines deletion cause.
+	controllerName = "kubeadm-control-plane-controller"
+)
+
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
@@ -70,6 +77,10 @@ type KubeadmControlPlaneReconciler struct {
 	Tracker         *remote.ClusterCacheTracker
 	EtcdDialTimeout time.Duration
 
+	// EtcdMaintenanceInterval is the interval between periodic etcd maintenance operations,
+	// i.e. defragmentation and NOSPACE alarms disarming; if zero etcd maintenance is disabled.
+	EtcdMaintenanceInterval time.Duration
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
@@ -101,7 +112,7 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 	}
 
 	r.controller = c
-	r.recorder = mgr.GetEventRecorderFor("kubeadm-control-plane-controller")
+	r.recorder = mgr.GetEventRecorderFor(controllerName)
 
 	if r.managementCluster == nil {
 		if r.Tracker == nil {
@@ -205,24 +216,16 @@ func (r *KubeadmControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.
 	if !kcp.ObjectMeta.DeletionTimestamp.IsZero() {
 		// Handle deletion reconciliation loop.
 		res, err = r.reconcileDelete(ctx, cluster, kcp)
-		// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
-		// the current cluster because of concurrent access.
-		if errors.Is(err, remote.ErrClusterLocked) {
-			log.V(5).Info("Requeueing because another worker has the lock on the ClusterCacheTracker")
-			return ctrl.Result{Requeue: true}, nil
-		}
-		return res, err
+		// Requeue if the reconcile failed because of a RequeueAfterError, e.g. because the ClusterCacheTracker
+		// was locked for the current cluster because of concurrent access.
+		return utilerrors.ReconcileResult(ctx, res, err)
 	}
 
 	// Handle normal reconciliation loop.
 	res, err = r.reconcile(ctx, cluster, kcp)
-	// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
-	// the current cluster because of concurrent access.
-	if errors.Is(err, remote.ErrClusterLocked) {
-		log.V(5).Info("Requeueing because another worker has the lock on the ClusterCacheTracker")
-		return ctrl.Result{Requeue: true}, nil
-	}
-	return res, err
+	// Requeue if the reconcile failed because of a RequeueAfterError, e.g. because the ClusterCacheTracker
+	// was locked for the current cluster because of concurrent access.
+	return utilerrors.ReconcileResult(ctx, res, err)
 }
 
 func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kcp *controlplanev1.KubeadmControlPlane) error {
@@ -260,10 +263,15 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("Reconcile KubeadmControlPlane")
 
-	// Make sure to reconcile the external infrastructure reference.
+	// Make sure to reconcile the external infrastructure references.
 	if err := r.reconcileExternalReference(ctx, cluster, &kcp.Spec.MachineTemplate.InfrastructureRef); err != nil {
 		return ctrl.Result{}, err
 	}
+	for i := range kcp.Spec.MachineTemplate.FailureDomainInfrastructureRefs {
+		if err := r.reconcileExternalReference(ctx, cluster, &kcp.Spec.MachineTemplate.FailureDomainInfrastructureRefs[i].InfrastructureRef); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	// Wait for the cluster infrastructure to be ready before creating machines
 	if !cluster.Status.InfrastructureReady {
@@ -341,6 +349,20 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return result, err
 	}
 
+	// Mark machines failing provisioning as unhealthy, so they are remediated even without a MachineHealthCheck.
+	provisioningRequeueAfter, err := r.reconcileMachinesFailingProvisioning(ctx, controlPlane)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if provisioningRequeueAfter > 0 {
+		defer func() {
+			// Make sure KCP is reconciled again when the provisioning timeout expires for machines still waiting for a Node.
+			if reterr == nil && !res.Requeue && (res.RequeueAfter <= 0 || res.RequeueAfter > provisioningRequeueAfter) {
+				res = ctrl.Result{RequeueAfter: provisioningRequeueAfter}
+			}
+		}()
+	}
+
 	// Reconcile unhealthy machines by triggering deletion and requeue if it is considered safe to remediate,
 	// otherwise continue with the other KCP operations.
 	if result, err := r.reconcileUnhealthyMachines(ctx, controlPlane); err != nil || !result.IsZero() {
@@ -421,7 +443,8 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, cluster *
 		return ctrl.Result{}, errors.Wrap(err, "failed to update CoreDNS deployment")
 	}
 
-	return ctrl.Result{}, nil
+	// Perform periodic etcd maintenance, if enabled.
+	return r.reconcileEtcdMaintenance(ctx, controlPlane, workloadCluster), nil
 }
 
 // reconcileDelete handles KubeadmControlPlane deletion.
@@ -483,7 +506,7 @@ func (r *KubeadmControlPlaneReconciler) reconcileDelete(ctx context.Context, clu
 	for i := range machinesToDelete {
 		m := machinesToDelete[i]
 		logger := log.WithValues("Machine", klog.KObj(m))
-		if err := r.Client.Delete(ctx, machinesToDelete[i]); err != nil && !apierrors.IsNotFound(err) {
+		if err := deletioncause.DeleteMachine(ctx, r.Client, machinesToDelete[i], clusterv1.MachineDeletionCauseOwnerDeletion, controllerName); err != nil && !apierrors.IsNotFound(err) {
 			logger.Error(err, "Failed to cleanup owned machine")
 			errs = append(errs, err)
 		}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(etcdMaintenanceTotal)
+	ctrlmetrics.Registry.MustRegister(etcdDefragmentedMembersTotal)
+	ctrlmetrics.Registry.MustRegister(etcdDisarmedAlarmsTotal)
+}
+
+var (
+	// etcdMaintenanceTotal reports etcd maintenance results.
+	etcdMaintenanceTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "capi_kubeadm_control_plane",
+		Name:      "etcd_maintenance_total",
+		Help:      "Number of etcd maintenance operations, partitioned by result.",
+	}, []string{"result"})
+
+	// etcdDefragmentedMembersTotal reports the number of defragmented etcd members.
+	etcdDefragmentedMembersTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: "capi_kubeadm_control_plane",
+		Name:      "etcd_defragmented_members_total",
+		Help:      "Number of etcd members defragmented by etcd maintenance.",
+	})
+
+	// etcdDisarmedAlarmsTotal reports the number of disarmed etcd alarms.
+	etcdDisarmedAlarmsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Subsystem: "capi_kubeadm_control_plane",
+		Name:      "etcd_disarmed_alarms_total",
+		Help:      "Number of etcd NOSPACE alarms disarmed by etcd maintenance.",
+	})
+)
+
+// reconcileEtcdMaintenance periodically defragments etcd members and disarms NOSPACE alarms, if etcd maintenance is enabled.
+// Maintenance is performed only when the control plane is stable, i.e. it is not scaling, it is not rolling out machines
+// and the etcd cluster is healthy or it is unhealthy only because of NOSPACE alarms; failures are reported but they do
+// not block other KCP operations.
+// The returned result requeues the KCP when the next maintenance is due.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdMaintenance(ctx context.Context, controlPlane *internal.ControlPlane, workloadCluster internal.WorkloadCluster) ctrl.Result {
+	log := ctrl.LoggerFrom(ctx)
+
+	if r.EtcdMaintenanceInterval <= 0 || !controlPlane.IsEtcdManaged() {
+		return ctrl.Result{}
+	}
+
+	kcp := controlPlane.KCP
+	if lastMaintenance, ok := kcp.GetAnnotations()[controlplanev1.EtcdLastMaintenanceAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, lastMaintenance); err == nil && time.Since(t) < r.EtcdMaintenanceInterval {
+			return ctrl.Result{RequeueAfter: r.EtcdMaintenanceInterval - time.Since(t)}
+		}
+	}
+
+	// Safety checks.
+	if !etcdHealthyOrNoSpaceAlarmsOnly(controlPlane) ||
+		controlPlane.HasDeletingMachine() ||
+		controlPlane.Machines.Len() != int(*kcp.Spec.Replicas) ||
+		len(controlPlane.MachinesNeedingRollout()) > 0 {
+		log.V(3).Info("Skipping etcd maintenance, control plane is not stable")
+		return ctrl.Result{RequeueAfter: r.EtcdMaintenanceInterval}
+	}
+
+	nodeNames := []string{}
+	for _, machine := range controlPlane.Machines {
+		if machine.Status.NodeRef == nil {
+			return ctrl.Result{RequeueAfter: r.EtcdMaintenanceInterval}
+		}
+		nodeNames = append(nodeNames, machine.Status.NodeRef.Name)
+	}
+
+	log.Info("Performing etcd maintenance")
+	result, err := workloadCluster.MaintainEtcd(ctx, nodeNames)
+
+	// Record the time of the maintenance also if it failed, so failed maintenance is retried at the next interval
+	// instead of at every reconcile, which could put additional load on an etcd cluster already in trouble.
+	annotations := kcp.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[controlplanev1.EtcdLastMaintenanceAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	kcp.SetAnnotations(annotations)
+
+	if result != nil {
+		etcdDefragmentedMembersTotal.Add(float64(len(result.DefragmentedMembers)))
+		etcdDisarmedAlarmsTotal.Add(float64(len(result.DisarmedAlarms)))
+	}
+	if err != nil {
+		etcdMaintenanceTotal.WithLabelValues("failure").Inc()
+		log.Error(err, "Failed to perform etcd maintenance")
+		r.recorder.Eventf(kcp, corev1.EventTypeWarning, "EtcdMaintenanceFailed", "Failed to perform etcd maintenance: %v", err)
+		return ctrl.Result{RequeueAfter: r.EtcdMaintenanceInterval}
+	}
+	etcdMaintenanceTotal.WithLabelValues("success").Inc()
+	r.recorder.Eventf(kcp, corev1.EventTypeNormal, "EtcdMaintenance", "Defragmented etcd members %s, disarmed alarms [%s]",
+		strings.Join(result.DefragmentedMembers, ","), strings.Join(result.DisarmedAlarms, ","))
+	return ctrl.Result{RequeueAfter: r.EtcdMaintenanceInterval}
+}
+
+// etcdHealthyOrNoSpaceAlarmsOnly returns true if the etcd cluster is healthy, or if the only reason for it being
+// unhealthy are NOSPACE alarms, which are the alarms etcd maintenance is meant to recover from.
+func etcdHealthyOrNoSpaceAlarmsOnly(controlPlane *internal.ControlPlane) bool {
+	if conditions.IsTrue(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition) {
+		return true
+	}
+	if !conditions.IsFalse(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition) ||
+		conditions.GetReason(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition) != controlplanev1.EtcdClusterUnhealthyReason {
+		return false
+	}
+
+	noSpaceAlarms := false
+	for _, machine := range controlPlane.Machines {
+		if conditions.IsTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition) {
+			continue
+		}
+		if conditions.GetReason(machine, controlplanev1.MachineEtcdMemberHealthyCondition) == controlplanev1.EtcdMemberNoSpaceAlarmReason {
+			noSpaceAlarms = true
+			continue
+		}
+		return false
+	}
+	return noSpaceAlarms
+}
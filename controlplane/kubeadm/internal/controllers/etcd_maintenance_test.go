@@ -0,0 +1,196 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileEtcdMaintenance(t *testing.T) {
+	// newControlPlane returns a control plane with a healthy etcd cluster if etcdMemberUnhealthyReason is empty,
+	// otherwise with an etcd cluster with a member unhealthy for the given reason.
+	newControlPlane := func(etcdMemberUnhealthyReason string, annotations map[string]string) *internal.ControlPlane {
+		kcp := &controlplanev1.KubeadmControlPlane{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas: pointer.Int32(1),
+				Version:  "v1.25.0",
+				KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+					ClusterConfiguration: &bootstrapv1.ClusterConfiguration{},
+				},
+			},
+		}
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "m1"},
+			Spec:       clusterv1.MachineSpec{Version: pointer.String("v1.25.0")},
+			Status:     clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "node1"}},
+		}
+		if etcdMemberUnhealthyReason == "" {
+			conditions.MarkTrue(kcp, controlplanev1.EtcdClusterHealthyCondition)
+			conditions.MarkTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+		} else {
+			conditions.MarkFalse(kcp, controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityError, "")
+			conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, etcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "")
+		}
+		return &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  &clusterv1.Cluster{},
+			Machines: collections.FromMachines(machine),
+		}
+	}
+
+	tests := []struct {
+		name                  string
+		interval              time.Duration
+		controlPlane          *internal.ControlPlane
+		maintenanceErr        error
+		expectMaintenanceDone bool
+		expectRequeue         bool
+	}{
+		{
+			name:         "does nothing if etcd maintenance is disabled",
+			interval:     0,
+			controlPlane: newControlPlane("", nil),
+		},
+		{
+			name:     "does nothing if the last maintenance happened within the interval",
+			interval: time.Hour,
+			controlPlane: newControlPlane("", map[string]string{
+				controlplanev1.EtcdLastMaintenanceAnnotation: time.Now().UTC().Format(time.RFC3339),
+			}),
+			expectRequeue: true,
+		},
+		{
+			name:          "does nothing if etcd is not healthy",
+			interval:      time.Hour,
+			controlPlane:  newControlPlane(controlplanev1.EtcdMemberUnhealthyReason, nil),
+			expectRequeue: true,
+		},
+		{
+			name:                  "performs maintenance if etcd is unhealthy only because of NOSPACE alarms",
+			interval:              time.Hour,
+			controlPlane:          newControlPlane(controlplanev1.EtcdMemberNoSpaceAlarmReason, nil),
+			expectMaintenanceDone: true,
+			expectRequeue:         true,
+		},
+		{
+			name:                  "records the maintenance time if maintenance fails",
+			interval:              time.Hour,
+			controlPlane:          newControlPlane("", nil),
+			maintenanceErr:        errors.New("failed to defragment"),
+			expectMaintenanceDone: true,
+			expectRequeue:         true,
+		},
+		{
+			name:     "performs maintenance if the last maintenance is older than the interval",
+			interval: time.Hour,
+			controlPlane: newControlPlane("", map[string]string{
+				controlplanev1.EtcdLastMaintenanceAnnotation: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+			}),
+			expectMaintenanceDone: true,
+			expectRequeue:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			lastMaintenance := tt.controlPlane.KCP.GetAnnotations()[controlplanev1.EtcdLastMaintenanceAnnotation]
+			r := &KubeadmControlPlaneReconciler{
+				recorder:                record.NewFakeRecorder(32),
+				EtcdMaintenanceInterval: tt.interval,
+			}
+			workloadCluster := fakeWorkloadCluster{
+				EtcdMaintenanceResult: &internal.EtcdMaintenanceResult{DefragmentedMembers: []string{"node1"}},
+				EtcdMaintenanceErr:    tt.maintenanceErr,
+			}
+
+			result := r.reconcileEtcdMaintenance(context.TODO(), tt.controlPlane, workloadCluster)
+			if tt.expectRequeue {
+				g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				g.Expect(result.RequeueAfter).To(BeNumerically("<=", tt.interval))
+			} else {
+				g.Expect(result.IsZero()).To(BeTrue())
+			}
+
+			got := tt.controlPlane.KCP.GetAnnotations()[controlplanev1.EtcdLastMaintenanceAnnotation]
+			if tt.expectMaintenanceDone {
+				g.Expect(got).ToNot(Equal(lastMaintenance))
+				return
+			}
+			g.Expect(got).To(Equal(lastMaintenance))
+		})
+	}
+}
+
+func TestReconcileEtcdMaintenanceFailureIsNotRetriedWithinInterval(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Replicas: pointer.Int32(1),
+			Version:  "v1.25.0",
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				ClusterConfiguration: &bootstrapv1.ClusterConfiguration{},
+			},
+		},
+	}
+	conditions.MarkTrue(kcp, controlplanev1.EtcdClusterHealthyCondition)
+	controlPlane := &internal.ControlPlane{
+		KCP:     kcp,
+		Cluster: &clusterv1.Cluster{},
+		Machines: collections.FromMachines(&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Name: "m1"},
+			Spec:       clusterv1.MachineSpec{Version: pointer.String("v1.25.0")},
+			Status:     clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "node1"}},
+		}),
+	}
+	recorder := record.NewFakeRecorder(32)
+	r := &KubeadmControlPlaneReconciler{
+		recorder:                recorder,
+		EtcdMaintenanceInterval: time.Hour,
+	}
+	workloadCluster := fakeWorkloadCluster{
+		EtcdMaintenanceErr: errors.New("failed to defragment"),
+	}
+
+	// The first attempt fails and it is reported with an event.
+	r.reconcileEtcdMaintenance(context.TODO(), controlPlane, workloadCluster)
+	g.Expect(kcp.GetAnnotations()).To(HaveKey(controlplanev1.EtcdLastMaintenanceAnnotation))
+	g.Expect(recorder.Events).To(HaveLen(1))
+	g.Expect(<-recorder.Events).To(ContainSubstring("EtcdMaintenanceFailed"))
+
+	// The maintenance is not attempted again until the interval expires.
+	r.reconcileEtcdMaintenance(context.TODO(), controlPlane, workloadCluster)
+	g.Expect(recorder.Events).To(BeEmpty())
+}
@@ -71,6 +71,12 @@ type fakeWorkloadCluster struct {
 	Status                     internal.ClusterStatus
 	EtcdMembersResult          []string
 	APIServerCertificateExpiry *time.Time
+	EtcdMaintenanceResult      *internal.EtcdMaintenanceResult
+	EtcdMaintenanceErr         error
+}
+
+func (f fakeWorkloadCluster) MaintainEtcd(_ context.Context, _ []string) (*internal.EtcdMaintenanceResult, error) {
+	return f.EtcdMaintenanceResult, f.EtcdMaintenanceErr
 }
 
 func (f fakeWorkloadCluster) ForwardEtcdLeadership(_ context.Context, _ *clusterv1.Machine, leaderCandidate *clusterv1.Machine) error {
@@ -112,6 +118,14 @@ func (f fakeWorkloadCluster) UpdateEtcdVersionInKubeadmConfigMap(_ context.Conte
 	return nil
 }
 
+func (f fakeWorkloadCluster) UpdateControlPlaneEndpointInKubeadmConfigMap(_ context.Context, _ string, _ semver.Version) error {
+	return nil
+}
+
+func (f fakeWorkloadCluster) UpdateClusterInfoConfigMap(_ context.Context, _ string) error {
+	return nil
+}
+
 func (f fakeWorkloadCluster) UpdateKubeletConfigMap(_ context.Context, _ semver.Version) error {
 	return nil
 }
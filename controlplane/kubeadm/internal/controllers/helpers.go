@@ -87,6 +87,43 @@ func (r *KubeadmControlPlaneReconciler) reconcileKubeconfig(ctx context.Context,
 		return ctrl.Result{}, nil
 	}
 
+	// if the control plane endpoint has been changed, regenerate the kubeconfig using the new endpoint;
+	// this implicitly takes care of rotating client certificates too.
+	needsEndpointUpdate, err := kubeconfig.NeedsEndpointUpdate(configSecret, endpoint.String())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if needsEndpointUpdate {
+		log.Info("updating kubeconfig secret with the new control plane endpoint", "endpoint", endpoint.String())
+		if err := kubeconfig.RegenerateSecretWithEndpoint(ctx, r.Client, configSecret, endpoint.String()); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to regenerate kubeconfig")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// if the cluster CA has been rotated, regenerate the kubeconfig so it trusts the new CA and the client
+	// certificate is signed by the new CA.
+	caSecret, err := secret.GetFromNamespacedName(ctx, r.Client, clusterName, secret.ClusterCA)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, errors.Wrap(err, "failed to retrieve cluster CA Secret")
+	}
+	needsCAUpdate := false
+	if err == nil {
+		needsCAUpdate, err = kubeconfig.NeedsClusterCAUpdate(configSecret, caSecret.Data[secret.TLSCrtDataName])
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if needsCAUpdate {
+		log.Info("updating kubeconfig secret with the new cluster CA")
+		if err := kubeconfig.RegenerateSecret(ctx, r.Client, configSecret); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to regenerate kubeconfig")
+		}
+		return ctrl.Result{}, nil
+	}
+
 	needsRotation, err := kubeconfig.NeedsClientCertRotation(configSecret, certs.ClientCertificateRenewalDuration)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -169,7 +206,7 @@ func (r *KubeadmControlPlaneReconciler) cloneConfigsAndGenerateMachine(ctx conte
 	// Clone the infrastructure template
 	infraRef, err := external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
 		Client:      r.Client,
-		TemplateRef: &kcp.Spec.MachineTemplate.InfrastructureRef,
+		TemplateRef: kcp.Spec.MachineTemplate.InfrastructureRefForFailureDomain(failureDomain),
 		Namespace:   kcp.Namespace,
 		OwnerRef:    infraCloneOwner,
 		ClusterName: cluster.Name,
@@ -287,6 +324,7 @@ func (r *KubeadmControlPlaneReconciler) generateMachine(ctx context.Context, kcp
 				ConfigRef: bootstrapRef,
 			},
 			FailureDomain:    failureDomain,
+			AdditionalTags:   kcp.Spec.MachineTemplate.AdditionalTags,
 			NodeDrainTimeout: kcp.Spec.MachineTemplate.NodeDrainTimeout,
 		},
 	}
@@ -323,6 +323,75 @@ func TestKubeadmControlPlaneReconciler_reconcileKubeconfig(t *testing.T) {
 	g.Expect(kubeconfigSecret.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, cluster.Name))
 }
 
+func TestKubeadmControlPlaneReconciler_reconcileKubeconfigWithRotatedCA(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "test.local", Port: 8443},
+		},
+	}
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "KubeadmControlPlane",
+			APIVersion: controlplanev1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.16.6",
+		},
+	}
+
+	caCert := &secret.Certificate{Purpose: secret.ClusterCA}
+	g.Expect(caCert.Generate()).To(Succeed())
+	existingCACertSecret := caCert.AsSecret(
+		client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "foo"},
+		*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind("KubeadmControlPlane")),
+	)
+
+	fakeClient := newFakeClient(kcp.DeepCopy(), existingCACertSecret.DeepCopy())
+	r := &KubeadmControlPlaneReconciler{
+		Client:   fakeClient,
+		recorder: record.NewFakeRecorder(32),
+	}
+	_, err := r.reconcileKubeconfig(ctx, cluster, kcp)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Rotate the cluster CA.
+	rotatedCACert := &secret.Certificate{Purpose: secret.ClusterCA}
+	g.Expect(rotatedCACert.Generate()).To(Succeed())
+	caSecret := &corev1.Secret{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(existingCACertSecret), caSecret)).To(Succeed())
+	caSecret.Data[secret.TLSCrtDataName] = rotatedCACert.KeyPair.Cert
+	caSecret.Data[secret.TLSKeyDataName] = rotatedCACert.KeyPair.Key
+	g.Expect(r.Client.Update(ctx, caSecret)).To(Succeed())
+
+	_, err = r.reconcileKubeconfig(ctx, cluster, kcp)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kubeconfigSecret := &corev1.Secret{}
+	secretName := client.ObjectKey{
+		Namespace: metav1.NamespaceDefault,
+		Name:      secret.Name(cluster.Name, secret.Kubeconfig),
+	}
+	g.Expect(r.Client.Get(ctx, secretName, kubeconfigSecret)).To(Succeed())
+	needsCAUpdate, err := kubeconfig.NeedsClusterCAUpdate(kubeconfigSecret, rotatedCACert.KeyPair.Cert)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(needsCAUpdate).To(BeFalse())
+}
+
 func TestCloneConfigsAndGenerateMachine(t *testing.T) {
 	g := NewWithT(t)
 
@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
@@ -30,7 +32,9 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/deletioncause"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
@@ -43,24 +47,20 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 	// Cleanup pending remediation actions not completed for any reasons (e.g. number of current replicas is less or equal to 1)
 	// if the underlying machine is now back to healthy / not deleting.
 	errList := []error{}
-	healthyMachines := controlPlane.HealthyMachines()
-	for _, m := range healthyMachines {
-		if conditions.IsTrue(m, clusterv1.MachineHealthCheckSucceededCondition) &&
-			conditions.IsFalse(m, clusterv1.MachineOwnerRemediatedCondition) &&
-			m.DeletionTimestamp.IsZero() {
-			patchHelper, err := patch.NewHelper(m, r.Client)
-			if err != nil {
-				errList = append(errList, errors.Wrapf(err, "failed to get PatchHelper for machine %s", m.Name))
-				continue
-			}
+	machinesBackToHealthy := controlPlane.HealthyMachines().Filter(collections.HasHealthyCondition, collections.NeedsRemediation, collections.ActiveMachines)
+	for _, m := range machinesBackToHealthy.SortedByName() {
+		patchHelper, err := patch.NewHelper(m, r.Client)
+		if err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to get PatchHelper for machine %s", m.Name))
+			continue
+		}
 
-			conditions.Delete(m, clusterv1.MachineOwnerRemediatedCondition)
+		conditions.Delete(m, clusterv1.MachineOwnerRemediatedCondition)
 
-			if err := patchHelper.Patch(ctx, m, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
-				clusterv1.MachineOwnerRemediatedCondition,
-			}}); err != nil {
-				errList = append(errList, errors.Wrapf(err, "failed to patch machine %s", m.Name))
-			}
+		if err := patchHelper.Patch(ctx, m, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.MachineOwnerRemediatedCondition,
+		}}); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to patch machine %s", m.Name))
 		}
 	}
 	if len(errList) > 0 {
@@ -110,8 +110,12 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 	log.WithValues("Machine", klog.KObj(machineToBeRemediated))
 	desiredReplicas := int(*controlPlane.KCP.Spec.Replicas)
 
+	// The first control plane machine can be remediated only if it failed provisioning; given that the control plane
+	// is not initialized yet there is no etcd member nor workload cluster state to preserve.
+	remediatingFirstMachine := !controlPlane.KCP.Status.Initialized && machineToBeRemediated.Status.NodeRef == nil
+
 	// The cluster MUST have more than one replica, because this is the smallest cluster size that allows any etcd failure tolerance.
-	if controlPlane.Machines.Len() <= 1 {
+	if controlPlane.Machines.Len() <= 1 && !remediatingFirstMachine {
 		log.Info("A control plane machine needs remediation, but the number of current replicas is less or equal to 1. Skipping remediation", "Replicas", controlPlane.Machines.Len())
 		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP can't remediate if current replicas are less or equal to 1")
 		return ctrl.Result{}, nil
@@ -119,7 +123,8 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 
 	// The number of replicas MUST be equal to or greater than the desired replicas. This rule ensures that when the cluster
 	// is missing replicas, we skip remediation and instead perform regular scale up/rollout operations first.
-	if controlPlane.Machines.Len() < desiredReplicas {
+	// Machines failing provisioning are an exception, because they would otherwise block scale up forever.
+	if controlPlane.Machines.Len() < desiredReplicas && machineToBeRemediated.Status.NodeRef != nil {
 		log.Info("A control plane machine needs remediation, but the current number of replicas is lower that expected. Skipping remediation", "Replicas", desiredReplicas, "CurrentReplicas", controlPlane.Machines.Len())
 		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "KCP waiting for having at least %d control plane machines before triggering remediation", desiredReplicas)
 		return ctrl.Result{}, nil
@@ -134,7 +139,7 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 
 	// Remediation MUST preserve etcd quorum. This rule ensures that we will not remove a member that would result in etcd
 	// losing a majority of members and thus become unable to field new requests.
-	if controlPlane.IsEtcdManaged() {
+	if controlPlane.IsEtcdManaged() && !remediatingFirstMachine {
 		canSafelyRemediate, err := r.canSafelyRemoveEtcdMember(ctx, controlPlane, machineToBeRemediated)
 		if err != nil {
 			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
@@ -147,48 +152,63 @@ func (r *KubeadmControlPlaneReconciler) reconcileUnhealthyMachines(ctx context.C
 		}
 	}
 
-	workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
-	if err != nil {
-		log.Error(err, "Failed to create client to workload cluster")
-		return ctrl.Result{}, errors.Wrapf(err, "failed to create client to workload cluster")
-	}
+	// Cleanup the workload cluster state for the machine, unless the control plane is not initialized yet.
+	if !remediatingFirstMachine {
+		workloadCluster, err := r.managementCluster.GetWorkloadCluster(ctx, util.ObjectKey(controlPlane.Cluster))
+		if err != nil {
+			log.Error(err, "Failed to create client to workload cluster")
+			return ctrl.Result{}, errors.Wrapf(err, "failed to create client to workload cluster")
+		}
 
-	// If the machine that is about to be deleted is the etcd leader, move it to the newest member available.
-	if controlPlane.IsEtcdManaged() {
-		etcdLeaderCandidate := controlPlane.HealthyMachines().Newest()
-		if etcdLeaderCandidate == nil {
-			log.Info("A control plane machine needs remediation, but there is no healthy machine to forward etcd leadership to")
-			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityWarning,
-				"A control plane machine needs remediation, but there is no healthy machine to forward etcd leadership to. Skipping remediation")
-			return ctrl.Result{}, nil
+		// If the machine that is about to be deleted is the etcd leader, move it to the newest member available.
+		if controlPlane.IsEtcdManaged() {
+			etcdLeaderCandidate := controlPlane.HealthyMachines().Newest()
+			if etcdLeaderCandidate == nil {
+				log.Info("A control plane machine needs remediation, but there is no healthy machine to forward etcd leadership to")
+				conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityWarning,
+					"A control plane machine needs remediation, but there is no healthy machine to forward etcd leadership to. Skipping remediation")
+				return ctrl.Result{}, nil
+			}
+			if err := workloadCluster.ForwardEtcdLeadership(ctx, machineToBeRemediated, etcdLeaderCandidate); err != nil {
+				log.Error(err, "Failed to move etcd leadership to candidate machine", "candidate", klog.KObj(etcdLeaderCandidate))
+				conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+				return ctrl.Result{}, err
+			}
+			if err := workloadCluster.RemoveEtcdMemberForMachine(ctx, machineToBeRemediated); err != nil {
+				log.Error(err, "Failed to remove etcd member for machine")
+				conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+				return ctrl.Result{}, err
+			}
 		}
-		if err := workloadCluster.ForwardEtcdLeadership(ctx, machineToBeRemediated, etcdLeaderCandidate); err != nil {
-			log.Error(err, "Failed to move etcd leadership to candidate machine", "candidate", klog.KObj(etcdLeaderCandidate))
-			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
-			return ctrl.Result{}, err
+
+		parsedVersion, err := semver.ParseTolerant(controlPlane.KCP.Spec.Version)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
 		}
-		if err := workloadCluster.RemoveEtcdMemberForMachine(ctx, machineToBeRemediated); err != nil {
-			log.Error(err, "Failed to remove etcd member for machine")
-			conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+
+		if err := workloadCluster.RemoveMachineFromKubeadmConfigMap(ctx, machineToBeRemediated, parsedVersion); err != nil {
+			log.Error(err, "Failed to remove machine from kubeadm ConfigMap")
 			return ctrl.Result{}, err
 		}
 	}
 
-	parsedVersion, err := semver.ParseTolerant(controlPlane.KCP.Spec.Version)
-	if err != nil {
-		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
-	}
-
-	if err := workloadCluster.RemoveMachineFromKubeadmConfigMap(ctx, machineToBeRemediated, parsedVersion); err != nil {
-		log.Error(err, "Failed to remove machine from kubeadm ConfigMap")
-		return ctrl.Result{}, err
+	// Record the deletion cause using the patch helper of the Machine, so the deferred patch of the
+	// MachineOwnerRemediated condition does not conflict with a separate patch of the Machine.
+	if deletioncause.SetCause(machineToBeRemediated, clusterv1.MachineDeletionCauseRemediation, controllerName) {
+		if err := patchHelper.Patch(ctx, machineToBeRemediated); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to record the deletion cause on Machine %s", machineToBeRemediated.Name)
+		}
 	}
-
 	if err := r.Client.Delete(ctx, machineToBeRemediated); err != nil {
 		conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationFailedReason, clusterv1.ConditionSeverityError, err.Error())
 		return ctrl.Result{}, errors.Wrapf(err, "failed to delete unhealthy machine %s", machineToBeRemediated.Name)
 	}
 
+	// Keep track of machines failing provisioning being remediated, so it is possible to enforce remediationStrategy.maxRetry.
+	if machineToBeRemediated.Status.NodeRef == nil {
+		setProvisioningRemediationRetries(controlPlane.KCP, getProvisioningRemediationRetries(controlPlane.KCP)+1)
+	}
+
 	log.Info("Remediating unhealthy machine")
 	conditions.MarkFalse(machineToBeRemediated, clusterv1.MachineOwnerRemediatedCondition, clusterv1.RemediationInProgressReason, clusterv1.ConditionSeverityWarning, "")
 	return ctrl.Result{Requeue: true}, nil
@@ -293,3 +313,86 @@ func (r *KubeadmControlPlaneReconciler) canSafelyRemoveEtcdMember(ctx context.Co
 
 	return canSafelyRemediate, nil
 }
+
+// reconcileMachinesFailingProvisioning marks as unhealthy the control plane machines that did not get a Node
+// within remediationStrategy.provisioningTimeout, so they are remediated by reconcileUnhealthyMachines even if
+// there is no MachineHealthCheck for the control plane; this includes the first control plane machine, which
+// otherwise would block the cluster creation forever.
+// It returns the time after which the next machine still waiting for a Node should be checked again, if any.
+func (r *KubeadmControlPlaneReconciler) reconcileMachinesFailingProvisioning(ctx context.Context, controlPlane *internal.ControlPlane) (time.Duration, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Reset the number of retries once all the control plane machines have a Node.
+	provisioningMachines := controlPlane.Machines.Filter(collections.Not(collections.HasNodeRef))
+	if len(provisioningMachines) == 0 {
+		if controlPlane.Machines.Len() > 0 {
+			delete(controlPlane.KCP.Annotations, controlplanev1.ProvisioningRemediationRetriesAnnotation)
+		}
+		return 0, nil
+	}
+
+	remediationStrategy := controlPlane.KCP.Spec.RemediationStrategy
+	if remediationStrategy == nil || remediationStrategy.ProvisioningTimeout == nil {
+		return 0, nil
+	}
+	provisioningTimeout := remediationStrategy.ProvisioningTimeout.Duration
+	retries := getProvisioningRemediationRetries(controlPlane.KCP)
+	maxRetryReached := remediationStrategy.MaxRetry != nil && retries >= int(*remediationStrategy.MaxRetry)
+
+	var requeueAfter time.Duration
+	errList := []error{}
+	// Skip machines already being deleted or already marked as unhealthy.
+	for _, m := range provisioningMachines.Filter(collections.ActiveMachines, collections.Not(collections.HasFailedHealthCheck)).SortedByName() {
+		if elapsed := time.Since(m.CreationTimestamp.Time); elapsed < provisioningTimeout {
+			if remaining := provisioningTimeout - elapsed; requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+
+		if maxRetryReached {
+			log.Info("A control plane machine failed provisioning, but the maximum number of retries has been reached. Skipping remediation", "Machine", klog.KObj(m), "Retries", retries)
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(m, r.Client)
+		if err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to get PatchHelper for machine %s", m.Name))
+			continue
+		}
+
+		log.Info("A control plane machine failed provisioning, marking it for remediation", "Machine", klog.KObj(m), "ProvisioningTimeout", provisioningTimeout)
+		conditions.MarkFalse(m, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.NodeStartupTimeoutReason, clusterv1.ConditionSeverityWarning, "Node failed to report startup in %s", provisioningTimeout)
+		conditions.MarkFalse(m, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+
+		if err := patchHelper.Patch(ctx, m, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.MachineHealthCheckSucceededCondition,
+			clusterv1.MachineOwnerRemediatedCondition,
+		}}); err != nil {
+			errList = append(errList, errors.Wrapf(err, "failed to patch machine %s", m.Name))
+		}
+	}
+	if len(errList) > 0 {
+		return 0, kerrors.NewAggregate(errList)
+	}
+	return requeueAfter, nil
+}
+
+// getProvisioningRemediationRetries returns the number of consecutive remediations of machines failing provisioning.
+func getProvisioningRemediationRetries(kcp *controlplanev1.KubeadmControlPlane) int {
+	retries, err := strconv.Atoi(kcp.Annotations[controlplanev1.ProvisioningRemediationRetriesAnnotation])
+	if err != nil {
+		return 0
+	}
+	return retries
+}
+
+// setProvisioningRemediationRetries sets the number of consecutive remediations of machines failing provisioning.
+func setProvisioningRemediationRetries(kcp *controlplanev1.KubeadmControlPlane, retries int) {
+	annotations := kcp.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[controlplanev1.ProvisioningRemediationRetriesAnnotation] = strconv.Itoa(retries)
+	kcp.SetAnnotations(annotations)
+}
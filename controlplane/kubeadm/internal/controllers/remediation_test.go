@@ -812,6 +812,175 @@ func TestCanSafelyRemoveEtcdMember(t *testing.T) {
 	})
 }
 
+func TestReconcileMachinesFailingProvisioning(t *testing.T) {
+	provisioningMachine := func(name string, age time.Duration) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         metav1.NamespaceDefault,
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+		}
+	}
+	kcpWithRemediationStrategy := func(maxRetry *int32, retries string) *controlplanev1.KubeadmControlPlane {
+		kcp := &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				RemediationStrategy: &controlplanev1.RemediationStrategy{
+					ProvisioningTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+					MaxRetry:            maxRetry,
+				},
+			},
+		}
+		if retries != "" {
+			kcp.Annotations = map[string]string{controlplanev1.ProvisioningRemediationRetriesAnnotation: retries}
+		}
+		return kcp
+	}
+
+	t.Run("Machines failing provisioning are marked for remediation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		failed := provisioningMachine("failed", 15*time.Minute)
+		provisioning := provisioningMachine("provisioning", 5*time.Minute)
+		r := &KubeadmControlPlaneReconciler{Client: newFakeClient(failed.DeepCopy(), provisioning.DeepCopy())}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcpWithRemediationStrategy(nil, ""),
+			Machines: collections.FromMachines(failed, provisioning),
+		}
+
+		requeueAfter, err := r.reconcileMachinesFailingProvisioning(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(requeueAfter).To(BeNumerically("~", 5*time.Minute, time.Minute))
+
+		g.Expect(conditions.GetReason(failed, clusterv1.MachineHealthCheckSucceededCondition)).To(Equal(clusterv1.NodeStartupTimeoutReason))
+		g.Expect(conditions.IsFalse(failed, clusterv1.MachineOwnerRemediatedCondition)).To(BeTrue())
+		g.Expect(controlPlane.UnhealthyMachines().Names()).To(ConsistOf("failed"))
+
+		updated := &clusterv1.Machine{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(failed), updated)).To(Succeed())
+		g.Expect(conditions.IsFalse(updated, clusterv1.MachineHealthCheckSucceededCondition)).To(BeTrue())
+	})
+	t.Run("Machines failing provisioning are not marked for remediation if remediationStrategy is not set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		failed := provisioningMachine("failed", 15*time.Minute)
+		r := &KubeadmControlPlaneReconciler{Client: newFakeClient(failed.DeepCopy())}
+		controlPlane := &internal.ControlPlane{
+			KCP:      &controlplanev1.KubeadmControlPlane{},
+			Machines: collections.FromMachines(failed),
+		}
+
+		requeueAfter, err := r.reconcileMachinesFailingProvisioning(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(requeueAfter).To(BeZero())
+		g.Expect(conditions.Has(failed, clusterv1.MachineHealthCheckSucceededCondition)).To(BeFalse())
+	})
+	t.Run("Machines failing provisioning are not marked for remediation if maxRetry is reached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		failed := provisioningMachine("failed", 15*time.Minute)
+		r := &KubeadmControlPlaneReconciler{Client: newFakeClient(failed.DeepCopy())}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcpWithRemediationStrategy(utilpointer.Int32(2), "2"),
+			Machines: collections.FromMachines(failed),
+		}
+
+		_, err := r.reconcileMachinesFailingProvisioning(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.Has(failed, clusterv1.MachineHealthCheckSucceededCondition)).To(BeFalse())
+		g.Expect(getProvisioningRemediationRetries(controlPlane.KCP)).To(Equal(2))
+	})
+	t.Run("Retries are reset once all the machines have a Node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := provisioningMachine("provisioned", 15*time.Minute)
+		withNodeRef("node")(m)
+		r := &KubeadmControlPlaneReconciler{Client: newFakeClient(m.DeepCopy())}
+		controlPlane := &internal.ControlPlane{
+			KCP:      kcpWithRemediationStrategy(utilpointer.Int32(2), "2"),
+			Machines: collections.FromMachines(m),
+		}
+
+		_, err := r.reconcileMachinesFailingProvisioning(ctx, controlPlane)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(controlPlane.KCP.Annotations).ToNot(HaveKey(controlplanev1.ProvisioningRemediationRetriesAnnotation))
+	})
+}
+
+func TestReconcileUnhealthyMachinesFirstMachineFailingProvisioning(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  metav1.NamespaceDefault,
+			Name:       "m1-failing-provisioning",
+			Finalizers: []string{clusterv1.MachineFinalizer},
+		},
+	}
+	withMachineHealthCheckFailed()(m)
+	r := &KubeadmControlPlaneReconciler{
+		Client:   newFakeClient(m.DeepCopy()),
+		recorder: record.NewFakeRecorder(32),
+	}
+	controlPlane := &internal.ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas: utilpointer.Int32(3),
+			},
+		},
+		Cluster:  &clusterv1.Cluster{},
+		Machines: collections.FromMachines(m),
+	}
+
+	// The first machine is remediated without connecting to the workload cluster, given that the control plane is not initialized yet.
+	ret, err := r.reconcileUnhealthyMachines(ctx, controlPlane)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ret.Requeue).To(BeTrue())
+	g.Expect(getProvisioningRemediationRetries(controlPlane.KCP)).To(Equal(1))
+
+	deleted := &clusterv1.Machine{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(m), deleted)).To(Succeed())
+	g.Expect(deleted.DeletionTimestamp.IsZero()).To(BeFalse())
+	g.Expect(conditions.GetReason(deleted, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(clusterv1.RemediationInProgressReason))
+}
+
+func TestReconcileUnhealthyMachinesRecordsDeletionCause(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  metav1.NamespaceDefault,
+			Name:       "m1-unhealthy",
+			Finalizers: []string{clusterv1.MachineFinalizer},
+		},
+	}
+	withMachineHealthCheckFailed()(m)
+	r := &KubeadmControlPlaneReconciler{
+		Client:   newFakeClient(m.DeepCopy()),
+		recorder: record.NewFakeRecorder(32),
+	}
+	controlPlane := &internal.ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				Replicas: utilpointer.Int32(3),
+			},
+		},
+		Cluster:  &clusterv1.Cluster{},
+		Machines: collections.FromMachines(m),
+	}
+
+	_, err := r.reconcileUnhealthyMachines(ctx, controlPlane)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Both the deletion cause and the MachineOwnerRemediated condition must be persisted on the remediated Machine.
+	deleted := &clusterv1.Machine{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(m), deleted)).To(Succeed())
+	g.Expect(deleted.DeletionTimestamp.IsZero()).To(BeFalse())
+	g.Expect(deleted.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletionCauseAnnotation, clusterv1.MachineDeletionCauseRemediation))
+	g.Expect(deleted.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletedByAnnotation, controllerName))
+	g.Expect(conditions.GetReason(deleted, clusterv1.MachineOwnerRemediatedCondition)).To(Equal(clusterv1.RemediationInProgressReason))
+}
+
 func nodes(machines collections.Machines) []string {
 	nodes := make([]string, 0, machines.Len())
 	for _, m := range machines {
@@ -20,6 +20,7 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -52,6 +53,10 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *c
 	}
 	kcp.Status.UpdatedReplicas = int32(len(controlPlane.UpToDateMachines()))
 
+	if err := setUpgradePlan(kcp, controlPlane); err != nil {
+		return errors.Wrap(err, "failed to compute the upgrade plan")
+	}
+
 	replicas := int32(len(ownedMachines))
 	desiredReplicas := *kcp.Spec.Replicas
 
@@ -118,3 +123,42 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, kcp *c
 
 	return nil
 }
+
+// setUpgradePlan sets the plan of the rollout in progress, if any, in the KCP status.
+// The plan is computed when the rollout starts, and it is then preserved and only the current step is updated
+// as machines are replaced, unless machines not part of the plan require to be rolled out (e.g. because the
+// KCP spec has been changed again while the rollout is in progress).
+func setUpgradePlan(kcp *controlplanev1.KubeadmControlPlane, controlPlane *internal.ControlPlane) error {
+	machinesNeedingRollout := controlPlane.MachinesNeedingRollout()
+	if !kcp.DeletionTimestamp.IsZero() || machinesNeedingRollout.Len() == 0 {
+		kcp.Status.UpgradePlan = nil
+		return nil
+	}
+
+	if plan := kcp.Status.UpgradePlan; plan != nil && plan.Version == kcp.Spec.Version {
+		planned := sets.NewString()
+		for _, step := range plan.Steps {
+			planned.Insert(step.MachineName)
+		}
+		if planned.HasAll(machinesNeedingRollout.Names()...) {
+			// The current step is the first step of the plan whose machine has not been replaced yet.
+			for i, step := range plan.Steps {
+				if _, ok := machinesNeedingRollout[step.MachineName]; ok {
+					plan.CurrentStep = int32(i)
+					break
+				}
+			}
+			return nil
+		}
+	}
+
+	steps, err := computeUpgradePlanSteps(controlPlane, machinesNeedingRollout)
+	if err != nil {
+		return err
+	}
+	kcp.Status.UpgradePlan = &controlplanev1.UpgradePlan{
+		Version: kcp.Spec.Version,
+		Steps:   steps,
+	}
+	return nil
+}
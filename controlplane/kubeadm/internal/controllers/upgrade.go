@@ -18,9 +18,11 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -28,6 +30,7 @@ import (
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/failuredomains"
 	"sigs.k8s.io/cluster-api/util/version"
 )
 
@@ -116,6 +119,27 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 		}
 	}
 
+	// Ensure new nodes are joining using the current control plane endpoint, which could be changed after the
+	// cluster has been created, e.g. in case of planned endpoint migrations.
+	// NOTE: If the control plane endpoint is explicitly set in the KCP ClusterConfiguration, it takes precedence
+	// over the one defined in the Cluster, like in the kubeadm bootstrap provider.
+	controlPlaneEndpoint := ""
+	if cluster.Spec.ControlPlaneEndpoint.IsValid() {
+		controlPlaneEndpoint = cluster.Spec.ControlPlaneEndpoint.String()
+	}
+	if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration != nil && kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.ControlPlaneEndpoint != "" {
+		controlPlaneEndpoint = kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.ControlPlaneEndpoint
+	}
+	if controlPlaneEndpoint != "" {
+		if err := workloadCluster.UpdateControlPlaneEndpointInKubeadmConfigMap(ctx, controlPlaneEndpoint, parsedVersion); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to update the control plane endpoint in the kubeadm config map")
+		}
+
+		if err := workloadCluster.UpdateClusterInfoConfigMap(ctx, controlPlaneEndpoint); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to update the control plane endpoint in the cluster-info config map")
+		}
+	}
+
 	if err := workloadCluster.UpdateKubeletConfigMap(ctx, parsedVersion); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to upgrade kubelet config map")
 	}
@@ -135,3 +159,48 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 		return ctrl.Result{}, nil
 	}
 }
+
+// computeUpgradePlanSteps returns the ordered list of control plane machines to be replaced by a rollout, by simulating
+// the sequence of scale up and scale down operations performed by upgradeControlPlane.
+// NOTE: The plan is computed assuming a MaxSurge of 1, which is the only value currently supported.
+func computeUpgradePlanSteps(controlPlane *internal.ControlPlane, machinesNeedingRollout collections.Machines) ([]controlplanev1.UpgradePlanStep, error) {
+	// Create a copy of the control plane, to be used for simulating the rollout without changing the original one.
+	simulated := *controlPlane
+	simulated.Machines = controlPlane.Machines.Filter(collections.Not(collections.HasDeletionTimestamp))
+	upToDate := controlPlane.UpToDateMachines().Filter(collections.Not(collections.HasDeletionTimestamp))
+	outdated := machinesNeedingRollout.Filter(collections.Not(collections.HasDeletionTimestamp))
+	failureDomains := controlPlane.FailureDomains().FilterControlPlane()
+
+	steps := []controlplanev1.UpgradePlanStep{}
+	for i := 0; outdated.Len() > 0; i++ {
+		// Simulate the scale up, creating the replacement machine in the failure domain with the fewest up-to-date machines.
+		var targetFailureDomain *string
+		if len(failureDomains) > 0 {
+			targetFailureDomain = failuredomains.PickFewest(failureDomains, upToDate)
+		}
+		replacement := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("%s-replacement-%d", controlPlane.KCP.Name, i),
+			},
+			Spec: clusterv1.MachineSpec{
+				FailureDomain: targetFailureDomain,
+			},
+		}
+		simulated.Machines.Insert(replacement)
+		upToDate.Insert(replacement)
+
+		// Simulate the scale down, deleting the outdated machine selected by the same logic used during the rollout.
+		machineToReplace, err := selectMachineForScaleDown(&simulated, outdated)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, controlplanev1.UpgradePlanStep{
+			MachineName:         machineToReplace.Name,
+			FailureDomain:       machineToReplace.Spec.FailureDomain,
+			TargetFailureDomain: targetFailureDomain,
+		})
+		delete(simulated.Machines, machineToReplace.Name)
+		delete(outdated, machineToReplace.Name)
+	}
+	return steps, nil
+}
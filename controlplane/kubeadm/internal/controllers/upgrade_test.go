@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -31,6 +32,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	"sigs.k8s.io/cluster-api/util/collections"
 )
@@ -214,6 +216,88 @@ func TestKubeadmControlPlaneReconciler_RolloutStrategy_ScaleDown(t *testing.T) {
 	g.Expect(remainingMachines.Items).To(HaveLen(2))
 }
 
+func TestSetUpgradePlan(t *testing.T) {
+	startDate := time.Date(2000, 1, 1, 1, 0, 0, 0, time.UTC)
+	withVersion := func(version string) machineOpt {
+		return func(m *clusterv1.Machine) {
+			m.Spec.Version = pointer.String(version)
+		}
+	}
+	m1 := machine("machine-1", withFailureDomain("fd1"), withVersion("v1.20.0"), withTimestamp(startDate.Add(-3*time.Hour)))
+	m2 := machine("machine-2", withFailureDomain("fd2"), withVersion("v1.20.0"), withTimestamp(startDate.Add(-2*time.Hour)))
+	m3 := machine("machine-3", withFailureDomain("fd3"), withVersion("v1.20.0"), withTimestamp(startDate.Add(-1*time.Hour)))
+	fds := clusterv1.FailureDomains{
+		"fd1": failureDomain(true),
+		"fd2": failureDomain(true),
+		"fd3": failureDomain(true),
+	}
+	newControlPlane := func(kcp *controlplanev1.KubeadmControlPlane, machines ...*clusterv1.Machine) *internal.ControlPlane {
+		return &internal.ControlPlane{
+			KCP:      kcp,
+			Cluster:  &clusterv1.Cluster{Status: clusterv1.ClusterStatus{FailureDomains: fds}},
+			Machines: collections.FromMachines(machines...),
+		}
+	}
+	step := func(name, failureDomain, targetFailureDomain string) controlplanev1.UpgradePlanStep {
+		return controlplanev1.UpgradePlanStep{
+			MachineName:         name,
+			FailureDomain:       pointer.String(failureDomain),
+			TargetFailureDomain: pointer.String(targetFailureDomain),
+		}
+	}
+
+	t.Run("Computes the plan when the rollout starts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kcp := &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{Version: "v1.21.0"}}
+		g.Expect(setUpgradePlan(kcp, newControlPlane(kcp, m1, m2, m3))).To(Succeed())
+		g.Expect(kcp.Status.UpgradePlan).To(Equal(&controlplanev1.UpgradePlan{
+			Version: "v1.21.0",
+			Steps: []controlplanev1.UpgradePlanStep{
+				step("machine-1", "fd1", "fd1"),
+				step("machine-2", "fd2", "fd2"),
+				step("machine-3", "fd3", "fd3"),
+			},
+		}))
+	})
+
+	t.Run("Updates the current step as machines are replaced", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kcp := &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{Version: "v1.21.0"}}
+		g.Expect(setUpgradePlan(kcp, newControlPlane(kcp, m1, m2, m3))).To(Succeed())
+		steps := kcp.Status.UpgradePlan.Steps
+
+		replacement := machine("machine-4", withFailureDomain("fd1"), withVersion("v1.21.0"), withTimestamp(startDate))
+		g.Expect(setUpgradePlan(kcp, newControlPlane(kcp, replacement, m2, m3))).To(Succeed())
+		g.Expect(kcp.Status.UpgradePlan.Steps).To(Equal(steps))
+		g.Expect(kcp.Status.UpgradePlan.CurrentStep).To(Equal(int32(1)))
+	})
+
+	t.Run("Recomputes the plan if machines not part of the plan require to be rolled out", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kcp := &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{Version: "v1.21.0"}}
+		g.Expect(setUpgradePlan(kcp, newControlPlane(kcp, m1, m2, m3))).To(Succeed())
+
+		replacement := machine("machine-4", withFailureDomain("fd1"), withVersion("v1.21.0"), withTimestamp(startDate))
+		kcp.Spec.Version = "v1.22.0"
+		g.Expect(setUpgradePlan(kcp, newControlPlane(kcp, replacement, m2, m3))).To(Succeed())
+		g.Expect(kcp.Status.UpgradePlan.Version).To(Equal("v1.22.0"))
+		g.Expect(kcp.Status.UpgradePlan.Steps).To(HaveLen(3))
+		g.Expect(kcp.Status.UpgradePlan.CurrentStep).To(Equal(int32(0)))
+	})
+
+	t.Run("Removes the plan when all the machines are up to date", func(t *testing.T) {
+		g := NewWithT(t)
+
+		kcp := &controlplanev1.KubeadmControlPlane{Spec: controlplanev1.KubeadmControlPlaneSpec{Version: "v1.20.0"}}
+		kcp.Status.UpgradePlan = &controlplanev1.UpgradePlan{Version: "v1.20.0"}
+		g.Expect(setUpgradePlan(kcp, newControlPlane(kcp, m1, m2, m3))).To(Succeed())
+		g.Expect(kcp.Status.UpgradePlan).To(BeNil())
+	})
+}
+
 type machineOpt func(*clusterv1.Machine)
 
 func machine(name string, opts ...machineOpt) *clusterv1.Machine {
@@ -37,8 +37,10 @@ type GRPCDial func(ctx context.Context, addr string) (net.Conn, error)
 // etcd wraps the etcd client from etcd's clientv3 package.
 // This interface is implemented by both the clientv3 package and the backoff adapter that adds retries to the client.
 type etcd interface {
+	AlarmDisarm(ctx context.Context, m *clientv3.AlarmMember) (*clientv3.AlarmResponse, error)
 	AlarmList(ctx context.Context) (*clientv3.AlarmResponse, error)
 	Close() error
+	Defragment(ctx context.Context, endpoint string) (*clientv3.DefragmentResponse, error)
 	Endpoints() []string
 	MemberList(ctx context.Context) (*clientv3.MemberListResponse, error)
 	MemberRemove(ctx context.Context, id uint64) (*clientv3.MemberRemoveResponse, error)
@@ -256,3 +258,19 @@ func (c *Client) Alarms(ctx context.Context) ([]MemberAlarm, error) {
 
 	return memberAlarms, nil
 }
+
+// DisarmAlarm disarms an alarm raised on a member.
+func (c *Client) DisarmAlarm(ctx context.Context, alarm MemberAlarm) error {
+	_, err := c.EtcdClient.AlarmDisarm(ctx, &clientv3.AlarmMember{
+		MemberID: alarm.MemberID,
+		Alarm:    etcdserverpb.AlarmType(alarm.Type),
+	})
+	return errors.Wrapf(err, "failed to disarm %s alarm on member %v", AlarmTypeName[alarm.Type], alarm.MemberID)
+}
+
+// Defragment defragments the storage of the member the client is connected to.
+// NOTE: Defragmentation blocks reads and writes on the member while it is in progress.
+func (c *Client) Defragment(ctx context.Context) error {
+	_, err := c.EtcdClient.Defragment(ctx, c.Endpoint)
+	return errors.Wrapf(err, "failed to defragment etcd member at %s", c.Endpoint)
+}
@@ -34,6 +34,8 @@ type FakeEtcdClient struct { //nolint:revive
 	ErrorResponse        error
 	MovedLeader          uint64
 	RemovedMember        uint64
+	DisarmedAlarms       []*clientv3.AlarmMember
+	DefragmentedEndpoint string
 }
 
 func (c *FakeEtcdClient) Endpoints() []string {
@@ -49,6 +51,16 @@ func (c *FakeEtcdClient) Close() error {
 	return nil
 }
 
+func (c *FakeEtcdClient) AlarmDisarm(_ context.Context, m *clientv3.AlarmMember) (*clientv3.AlarmResponse, error) {
+	c.DisarmedAlarms = append(c.DisarmedAlarms, m)
+	return c.AlarmResponse, c.ErrorResponse
+}
+
+func (c *FakeEtcdClient) Defragment(_ context.Context, endpoint string) (*clientv3.DefragmentResponse, error) {
+	c.DefragmentedEndpoint = endpoint
+	return &clientv3.DefragmentResponse{}, c.ErrorResponse
+}
+
 func (c *FakeEtcdClient) AlarmList(_ context.Context) (*clientv3.AlarmResponse, error) {
 	return c.AlarmResponse, c.ErrorResponse
 }
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"reflect"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -36,6 +37,9 @@ func MatchesMachineSpec(infraConfigs map[string]*unstructured.Unstructured, mach
 		func(machine *clusterv1.Machine) bool {
 			return matchMachineTemplateMetadata(kcp, machine)
 		},
+		func(machine *clusterv1.Machine) bool {
+			return matchMachineAdditionalTags(kcp, machine)
+		},
 		collections.MatchesKubernetesVersion(kcp.Spec.Version),
 		MatchesKubeadmBootstrapConfig(machineConfigs, kcp),
 		MatchesTemplateClonedFrom(infraConfigs, kcp),
@@ -63,9 +67,11 @@ func MatchesTemplateClonedFrom(infraConfigs map[string]*unstructured.Unstructure
 			return true
 		}
 
-		// Check if the machine's infrastructure reference has been created from the current KCP infrastructure template.
-		if clonedFromName != kcp.Spec.MachineTemplate.InfrastructureRef.Name ||
-			clonedFromGroupKind != kcp.Spec.MachineTemplate.InfrastructureRef.GroupVersionKind().GroupKind().String() {
+		// Check if the machine's infrastructure reference has been created from the current KCP infrastructure template
+		// for the failure domain of the machine.
+		infraTemplateRef := kcp.Spec.MachineTemplate.InfrastructureRefForFailureDomain(machine.Spec.FailureDomain)
+		if clonedFromName != infraTemplateRef.Name ||
+			clonedFromGroupKind != infraTemplateRef.GroupVersionKind().GroupKind().String() {
 			return false
 		}
 
@@ -115,6 +121,55 @@ func MatchesKubeadmBootstrapConfig(machineConfigs map[string]*bootstrapv1.Kubead
 	}
 }
 
+// MatchesControlPlaneEndpoint returns a filter to find all machines that have been bootstrapped using the given
+// control plane endpoint, thus allowing to detect machines to be rolled out after the control plane endpoint
+// of the Cluster has been changed.
+// NOTE: If the control plane endpoint is explicitly set in the KCP KubeadmConfigSpec, changes are already detected
+// by MatchesKubeadmBootstrapConfig.
+func MatchesControlPlaneEndpoint(machineConfigs map[string]*bootstrapv1.KubeadmConfig, kcp *controlplanev1.KubeadmControlPlane, endpoint clusterv1.APIEndpoint) collections.Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil {
+			return false
+		}
+
+		if !endpoint.IsValid() {
+			// Return true here because the control plane endpoint is not yet known.
+			return true
+		}
+
+		if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration != nil && kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.ControlPlaneEndpoint != "" {
+			return true
+		}
+
+		machineConfig, found := machineConfigs[machine.Name]
+		if !found {
+			// Return true here because failing to get KubeadmConfig should not be considered as unmatching.
+			// This is a safety precaution to avoid rolling out machines if the client or the api-server is misbehaving.
+			return true
+		}
+
+		machineEndpoint := getMachineControlPlaneEndpoint(machineConfig)
+		if machineEndpoint == "" {
+			// The control plane endpoint has not been set yet by the bootstrap provider; don't trigger a rollout.
+			return true
+		}
+		return machineEndpoint == endpoint.String()
+	}
+}
+
+// getMachineControlPlaneEndpoint returns the control plane endpoint a machine has been bootstrapped with,
+// which is stored in the ClusterConfiguration for the initial control plane node and in the JoinConfiguration
+// discovery for joining control plane nodes.
+func getMachineControlPlaneEndpoint(machineConfig *bootstrapv1.KubeadmConfig) string {
+	if machineConfig.Spec.ClusterConfiguration != nil && machineConfig.Spec.ClusterConfiguration.ControlPlaneEndpoint != "" {
+		return machineConfig.Spec.ClusterConfiguration.ControlPlaneEndpoint
+	}
+	if machineConfig.Spec.JoinConfiguration != nil && machineConfig.Spec.JoinConfiguration.Discovery.BootstrapToken != nil {
+		return machineConfig.Spec.JoinConfiguration.Discovery.BootstrapToken.APIServerEndpoint
+	}
+	return ""
+}
+
 // matchClusterConfiguration verifies if KCP and machine ClusterConfiguration matches.
 // NOTE: Machines that have KubeadmClusterConfigurationAnnotation will have to match with KCP ClusterConfiguration.
 // If the annotation is not present (machine is either old or adopted), we won't roll out on any possible changes
@@ -256,6 +311,16 @@ func matchMachineTemplateMetadata(kcp *controlplanev1.KubeadmControlPlane, obj c
 	return true
 }
 
+// matchMachineAdditionalTags matches the machine template additional tags against the ones of a machine.
+// NOTE: Tags are expected to be applied only at creation time by infrastructure providers, so any difference,
+// including removed tags, requires a rollout.
+func matchMachineAdditionalTags(kcp *controlplanev1.KubeadmControlPlane, machine *clusterv1.Machine) bool {
+	if machine == nil {
+		return false
+	}
+	return apiequality.Semantic.DeepEqual(kcp.Spec.MachineTemplate.AdditionalTags, machine.Spec.AdditionalTags)
+}
+
 func isSubsetMapOf(base map[string]string, existing map[string]string) bool {
 loopBase:
 	for key, value := range base {
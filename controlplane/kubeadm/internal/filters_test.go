@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
@@ -971,6 +972,118 @@ func TestMatchesKubeadmBootstrapConfig(t *testing.T) {
 	})
 }
 
+func TestMatchesControlPlaneEndpoint(t *testing.T) {
+	endpoint := clusterv1.APIEndpoint{Host: "new.example.com", Port: 6443}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	initConfig := func(controlPlaneEndpoint string) *bootstrapv1.KubeadmConfig {
+		return &bootstrapv1.KubeadmConfig{
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+					ControlPlaneEndpoint: controlPlaneEndpoint,
+				},
+				InitConfiguration: &bootstrapv1.InitConfiguration{},
+			},
+		}
+	}
+	joinConfig := func(apiServerEndpoint string) *bootstrapv1.KubeadmConfig {
+		return &bootstrapv1.KubeadmConfig{
+			Spec: bootstrapv1.KubeadmConfigSpec{
+				ClusterConfiguration: &bootstrapv1.ClusterConfiguration{},
+				JoinConfiguration: &bootstrapv1.JoinConfiguration{
+					Discovery: bootstrapv1.Discovery{
+						BootstrapToken: &bootstrapv1.BootstrapTokenDiscovery{
+							APIServerEndpoint: apiServerEndpoint,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		kcp          *controlplanev1.KubeadmControlPlane
+		machineConfs map[string]*bootstrapv1.KubeadmConfig
+		endpoint     clusterv1.APIEndpoint
+		want         bool
+	}{
+		{
+			name:         "should match if the control plane endpoint is not set",
+			kcp:          &controlplanev1.KubeadmControlPlane{},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{"test": initConfig("old.example.com:6443")},
+			endpoint:     clusterv1.APIEndpoint{},
+			want:         true,
+		},
+		{
+			name:         "should match if the KubeadmConfig is not found",
+			kcp:          &controlplanev1.KubeadmControlPlane{},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{},
+			endpoint:     endpoint,
+			want:         true,
+		},
+		{
+			name:         "should match if the KubeadmConfig has not been bootstrapped yet",
+			kcp:          &controlplanev1.KubeadmControlPlane{},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{"test": initConfig("")},
+			endpoint:     endpoint,
+			want:         true,
+		},
+		{
+			name: "should match if the control plane endpoint is set in KCP",
+			kcp: &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: &bootstrapv1.ClusterConfiguration{
+							ControlPlaneEndpoint: "old.example.com:6443",
+						},
+					},
+				},
+			},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{"test": initConfig("old.example.com:6443")},
+			endpoint:     endpoint,
+			want:         true,
+		},
+		{
+			name:         "should match if the initial control plane node uses the same endpoint",
+			kcp:          &controlplanev1.KubeadmControlPlane{},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{"test": initConfig("new.example.com:6443")},
+			endpoint:     endpoint,
+			want:         true,
+		},
+		{
+			name:         "should not match if the initial control plane node uses a different endpoint",
+			kcp:          &controlplanev1.KubeadmControlPlane{},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{"test": initConfig("old.example.com:6443")},
+			endpoint:     endpoint,
+			want:         false,
+		},
+		{
+			name:         "should match if the joining control plane node uses the same endpoint",
+			kcp:          &controlplanev1.KubeadmControlPlane{},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{"test": joinConfig("new.example.com:6443")},
+			endpoint:     endpoint,
+			want:         true,
+		},
+		{
+			name:         "should not match if the joining control plane node uses a different endpoint",
+			kcp:          &controlplanev1.KubeadmControlPlane{},
+			machineConfs: map[string]*bootstrapv1.KubeadmConfig{"test": joinConfig("old.example.com:6443")},
+			endpoint:     endpoint,
+			want:         false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(MatchesControlPlaneEndpoint(tt.machineConfs, tt.kcp, tt.endpoint)(machine)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestMatchesTemplateClonedFrom(t *testing.T) {
 	t.Run("nil machine returns false", func(t *testing.T) {
 		g := NewWithT(t)
@@ -1177,3 +1290,160 @@ func TestMatchesTemplateClonedFrom_WithClonedFromAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchesTemplateClonedFrom_WithFailureDomainInfrastructureRefs(t *testing.T) {
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{
+					Kind:       "GenericMachineTemplate",
+					Namespace:  "default",
+					Name:       "infra-foo",
+					APIVersion: "generic.io/v1",
+				},
+				FailureDomainInfrastructureRefs: []controlplanev1.FailureDomainInfrastructureRef{
+					{
+						FailureDomain: "fd1",
+						InfrastructureRef: corev1.ObjectReference{
+							Kind:       "GenericMachineTemplate",
+							Namespace:  "default",
+							Name:       "infra-foo-fd1",
+							APIVersion: "generic.io/v1",
+						},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		failureDomain *string
+		clonedFrom    string
+		expectMatch   bool
+	}{
+		{
+			name:          "returns true if cloned from the template of the failure domain",
+			failureDomain: pointer.String("fd1"),
+			clonedFrom:    "infra-foo-fd1",
+			expectMatch:   true,
+		},
+		{
+			name:          "returns false if cloned from the default template in a failure domain with a specific template",
+			failureDomain: pointer.String("fd1"),
+			clonedFrom:    "infra-foo",
+			expectMatch:   false,
+		},
+		{
+			name:          "returns true if cloned from the default template in a failure domain without a specific template",
+			failureDomain: pointer.String("fd2"),
+			clonedFrom:    "infra-foo",
+			expectMatch:   true,
+		},
+		{
+			name:          "returns false if cloned from the template of another failure domain",
+			failureDomain: pointer.String("fd2"),
+			clonedFrom:    "infra-foo-fd1",
+			expectMatch:   false,
+		},
+		{
+			name:          "returns true if cloned from the default template without a failure domain",
+			failureDomain: nil,
+			clonedFrom:    "infra-foo",
+			expectMatch:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			machine := &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{
+					FailureDomain: tt.failureDomain,
+				},
+			}
+			infraConfigs := map[string]*unstructured.Unstructured{
+				machine.Name: {
+					Object: map[string]interface{}{
+						"kind":       "InfrastructureMachine",
+						"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+						"metadata": map[string]interface{}{
+							"name":      "infra-config1",
+							"namespace": "default",
+							"annotations": map[string]interface{}{
+								clusterv1.TemplateClonedFromNameAnnotation:      tt.clonedFrom,
+								clusterv1.TemplateClonedFromGroupKindAnnotation: "GenericMachineTemplate.generic.io",
+							},
+						},
+					},
+				},
+			}
+			g.Expect(
+				MatchesTemplateClonedFrom(infraConfigs, kcp)(machine),
+			).To(Equal(tt.expectMatch))
+		})
+	}
+}
+
+func TestMatchMachineAdditionalTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		kcpTags     map[string]string
+		machineTags map[string]string
+		expectMatch bool
+	}{
+		{
+			name:        "should match if both KCP and machine do not have tags",
+			expectMatch: true,
+		},
+		{
+			name:        "should match if tags are equal",
+			kcpTags:     map[string]string{"cost-center": "foo"},
+			machineTags: map[string]string{"cost-center": "foo"},
+			expectMatch: true,
+		},
+		{
+			name:        "should match empty and nil tags",
+			kcpTags:     map[string]string{},
+			expectMatch: true,
+		},
+		{
+			name:        "should not match if a tag has a different value",
+			kcpTags:     map[string]string{"cost-center": "foo"},
+			machineTags: map[string]string{"cost-center": "bar"},
+			expectMatch: false,
+		},
+		{
+			name:        "should not match if a tag has been added",
+			kcpTags:     map[string]string{"cost-center": "foo", "team": "bar"},
+			machineTags: map[string]string{"cost-center": "foo"},
+			expectMatch: false,
+		},
+		{
+			name:        "should not match if a tag has been removed",
+			kcpTags:     map[string]string{"cost-center": "foo"},
+			machineTags: map[string]string{"cost-center": "foo", "team": "bar"},
+			expectMatch: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			kcp := &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+						AdditionalTags: tt.kcpTags,
+					},
+				},
+			}
+			machine := &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{
+					AdditionalTags: tt.machineTags,
+				},
+			}
+			g.Expect(matchMachineAdditionalTags(kcp, machine)).To(Equal(tt.expectMatch))
+		})
+	}
+}
@@ -38,7 +38,9 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/retry"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
@@ -119,6 +121,8 @@ type WorkloadCluster interface {
 	UpdateAPIServerInKubeadmConfigMap(ctx context.Context, apiServer bootstrapv1.APIServer, version semver.Version) error
 	UpdateControllerManagerInKubeadmConfigMap(ctx context.Context, controllerManager bootstrapv1.ControlPlaneComponent, version semver.Version) error
 	UpdateSchedulerInKubeadmConfigMap(ctx context.Context, scheduler bootstrapv1.ControlPlaneComponent, version semver.Version) error
+	UpdateControlPlaneEndpointInKubeadmConfigMap(ctx context.Context, endpoint string, version semver.Version) error
+	UpdateClusterInfoConfigMap(ctx context.Context, endpoint string) error
 	UpdateKubeletConfigMap(ctx context.Context, version semver.Version) error
 	UpdateKubeProxyImageInfo(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error
 	UpdateCoreDNS(ctx context.Context, kcp *controlplanev1.KubeadmControlPlane, version semver.Version) error
@@ -130,6 +134,9 @@ type WorkloadCluster interface {
 
 	// State recovery tasks.
 	ReconcileEtcdMembers(ctx context.Context, nodeNames []string, version semver.Version) ([]string, error)
+
+	// Maintenance tasks.
+	MaintainEtcd(ctx context.Context, nodeNames []string) (*EtcdMaintenanceResult, error)
 }
 
 // Workload defines operations on workload clusters.
@@ -395,6 +402,67 @@ func (w *Workload) updateClusterConfiguration(ctx context.Context, mutator func(
 	})
 }
 
+// UpdateControlPlaneEndpointInKubeadmConfigMap updates the control plane endpoint in the kubeadm config map.
+// NOTE: The control plane endpoint in the kubeadm config map is used by kubeadm join when generating the kubeconfig
+// files for new control plane nodes.
+func (w *Workload) UpdateControlPlaneEndpointInKubeadmConfigMap(ctx context.Context, endpoint string, version semver.Version) error {
+	return w.updateClusterConfiguration(ctx, func(c *bootstrapv1.ClusterConfiguration) {
+		if endpoint == "" {
+			return
+		}
+		c.ControlPlaneEndpoint = endpoint
+	}, version)
+}
+
+// UpdateClusterInfoConfigMap updates the server in the kubeconfig stored in the cluster-info config map.
+// NOTE: The cluster-info config map is used by kubeadm join for discovering the API server to be used for the
+// TLS bootstrap of new nodes; the bootstrap signer takes care of re-signing the config map after changes.
+func (w *Workload) UpdateClusterInfoConfigMap(ctx context.Context, endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	server := fmt.Sprintf("https://%s", endpoint)
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		key := ctrlclient.ObjectKey{Name: bootstrapapi.ConfigMapClusterInfo, Namespace: metav1.NamespacePublic}
+		configMap, err := w.getConfigMap(ctx, key)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get %s ConfigMap", bootstrapapi.ConfigMapClusterInfo)
+		}
+
+		data, ok := configMap.Data[bootstrapapi.KubeConfigKey]
+		if !ok {
+			return errors.Errorf("unable to find %q in the %s ConfigMap", bootstrapapi.KubeConfigKey, bootstrapapi.ConfigMapClusterInfo)
+		}
+
+		config, err := clientcmd.Load([]byte(data))
+		if err != nil {
+			return errors.Wrapf(err, "unable to decode %q in the %s ConfigMap", bootstrapapi.KubeConfigKey, bootstrapapi.ConfigMapClusterInfo)
+		}
+
+		changed := false
+		for _, cluster := range config.Clusters {
+			if cluster.Server != server {
+				cluster.Server = server
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+
+		updatedData, err := clientcmd.Write(*config)
+		if err != nil {
+			return errors.Wrapf(err, "unable to encode %q in the %s ConfigMap", bootstrapapi.KubeConfigKey, bootstrapapi.ConfigMapClusterInfo)
+		}
+		configMap.Data[bootstrapapi.KubeConfigKey] = string(updatedData)
+		if err := w.Client.Update(ctx, configMap); err != nil {
+			return errors.Wrapf(err, "failed to update the %s ConfigMap", bootstrapapi.ConfigMapClusterInfo)
+		}
+		return nil
+	})
+}
+
 // ClusterStatus holds stats information about the cluster.
 type ClusterStatus struct {
 	// Nodes are a total count of nodes
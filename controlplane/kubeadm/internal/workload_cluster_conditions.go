@@ -131,6 +131,10 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 					alarmList = append(alarmList, etcd.AlarmTypeName[alarm])
 				}
 			}
+			if len(alarmList) == 1 && alarmList[0] == etcd.AlarmTypeName[etcd.AlarmNoSpace] {
+				conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberNoSpaceAlarmReason, clusterv1.ConditionSeverityError, "Etcd member reports alarms: %s", strings.Join(alarmList, ", "))
+				continue
+			}
 			if len(alarmList) > 0 {
 				conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Etcd member reports alarms: %s", strings.Join(alarmList, ", "))
 				continue
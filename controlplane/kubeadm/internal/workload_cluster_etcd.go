@@ -18,10 +18,14 @@ package internal
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
@@ -247,3 +251,133 @@ func (w *Workload) EtcdMembers(ctx context.Context) ([]string, error) {
 	}
 	return names, nil
 }
+
+var (
+	// etcdMemberHealthyPollInterval is the interval between checks of the health of a defragmented etcd member.
+	etcdMemberHealthyPollInterval = 5 * time.Second
+
+	// etcdMemberHealthyTimeout is the maximum time to wait for a defragmented etcd member to become healthy.
+	etcdMemberHealthyTimeout = 2 * time.Minute
+)
+
+// EtcdMaintenanceResult reports the operations performed by MaintainEtcd.
+type EtcdMaintenanceResult struct {
+	// DefragmentedMembers is the list of members which have been defragmented.
+	DefragmentedMembers []string
+
+	// DisarmedAlarms is the list of alarms which have been disarmed, in the <member>:<alarm> format.
+	DisarmedAlarms []string
+}
+
+// MaintainEtcd defragments the etcd members one by one, starting from the followers and ending with the leader,
+// and then disarms NOSPACE alarms freed by defragmentation.
+// Maintenance is skipped if the list of members does not match the list of nodes or if any member has a CORRUPT alarm,
+// and it stops at the first failure; after defragmenting a member it waits for the member to be healthy before moving
+// to the next one, thus never having more than one member unavailable at time.
+func (w *Workload) MaintainEtcd(ctx context.Context, nodeNames []string) (*EtcdMaintenanceResult, error) {
+	etcdClient, err := w.etcdClientGenerator.forLeader(ctx, nodeNames)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create etcd client")
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.Members(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list etcd members using etcd client")
+	}
+	alarms, err := etcdClient.Alarms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Safety checks.
+	if len(members) != len(nodeNames) {
+		return nil, errors.Errorf("skipping etcd maintenance: etcd has %d members, expected %d", len(members), len(nodeNames))
+	}
+	for _, nodeName := range nodeNames {
+		if etcdutil.MemberForName(members, nodeName) == nil {
+			return nil, errors.Errorf("skipping etcd maintenance: node %s does not have a corresponding etcd member", nodeName)
+		}
+	}
+	for _, alarm := range alarms {
+		if alarm.Type == etcd.AlarmCorrupt {
+			return nil, errors.Errorf("skipping etcd maintenance: etcd member %v has a %s alarm", alarm.MemberID, etcd.AlarmTypeName[alarm.Type])
+		}
+	}
+
+	// Defragment followers first and the leader last, so the leader is unavailable only once all the followers are healthy.
+	orderedNodeNames := make([]string, 0, len(nodeNames))
+	leaderNodeName := ""
+	for _, nodeName := range nodeNames {
+		if etcdutil.MemberForName(members, nodeName).ID == etcdClient.LeaderID {
+			leaderNodeName = nodeName
+			continue
+		}
+		orderedNodeNames = append(orderedNodeNames, nodeName)
+	}
+	if leaderNodeName != "" {
+		orderedNodeNames = append(orderedNodeNames, leaderNodeName)
+	}
+
+	result := &EtcdMaintenanceResult{}
+	for _, nodeName := range orderedNodeNames {
+		if err := w.defragmentMemberForNode(ctx, nodeName); err != nil {
+			return result, err
+		}
+		result.DefragmentedMembers = append(result.DefragmentedMembers, nodeName)
+		if err := w.waitForEtcdMemberHealthy(ctx, nodeName); err != nil {
+			return result, err
+		}
+	}
+
+	// Disarm NOSPACE alarms, now that defragmentation has freed space.
+	for _, alarm := range alarms {
+		if alarm.Type != etcd.AlarmNoSpace {
+			continue
+		}
+		if err := etcdClient.DisarmAlarm(ctx, alarm); err != nil {
+			return result, err
+		}
+		result.DisarmedAlarms = append(result.DisarmedAlarms, fmt.Sprintf("%v:%s", alarm.MemberID, etcd.AlarmTypeName[alarm.Type]))
+	}
+	return result, nil
+}
+
+func (w *Workload) defragmentMemberForNode(ctx context.Context, nodeName string) error {
+	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{nodeName})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create etcd client for node %s", nodeName)
+	}
+	defer etcdClient.Close()
+
+	return etcdClient.Defragment(ctx)
+}
+
+// waitForEtcdMemberHealthy waits for the etcd member hosted on the given node to answer requests without reporting errors.
+func (w *Workload) waitForEtcdMemberHealthy(ctx context.Context, nodeName string) error {
+	var lastErr error
+	err := wait.PollImmediate(etcdMemberHealthyPollInterval, etcdMemberHealthyTimeout, func() (bool, error) {
+		lastErr = w.checkEtcdMemberHealthy(ctx, nodeName)
+		return lastErr == nil, nil
+	})
+	if err != nil {
+		return errors.Wrapf(kerrors.NewAggregate([]error{err, lastErr}), "etcd member for node %s did not become healthy after defragmentation", nodeName)
+	}
+	return nil
+}
+
+func (w *Workload) checkEtcdMemberHealthy(ctx context.Context, nodeName string) error {
+	etcdClient, err := w.etcdClientGenerator.forFirstAvailableNode(ctx, []string{nodeName})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create etcd client for node %s", nodeName)
+	}
+	defer etcdClient.Close()
+
+	if len(etcdClient.Errors) > 0 {
+		return errors.Errorf("etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
+	}
+	if _, err := etcdClient.Members(ctx); err != nil {
+		return errors.Wrap(err, "failed to list etcd members using etcd client")
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/google/go-cmp/cmp"
@@ -527,6 +528,111 @@ func TestForwardEtcdLeadership(t *testing.T) {
 	})
 }
 
+func TestMaintainEtcd(t *testing.T) {
+	members := []*pb.Member{
+		{Name: "leader-node", ID: uint64(101)},
+		{Name: "other-node", ID: uint64(102)},
+	}
+
+	defer func(interval, timeout time.Duration) {
+		etcdMemberHealthyPollInterval = interval
+		etcdMemberHealthyTimeout = timeout
+	}(etcdMemberHealthyPollInterval, etcdMemberHealthyTimeout)
+	etcdMemberHealthyPollInterval = 10 * time.Millisecond
+	etcdMemberHealthyTimeout = 50 * time.Millisecond
+
+	tests := []struct {
+		name                    string
+		nodeNames               []string
+		alarms                  []*pb.AlarmMember
+		unhealthyNodes          []string
+		expectErr               bool
+		expectDefragmentedNodes []string
+		expectDisarmedAlarms    []string
+	}{
+		{
+			name:                    "defragments followers first and the leader last, then disarms NOSPACE alarms",
+			nodeNames:               []string{"leader-node", "other-node"},
+			alarms:                  []*pb.AlarmMember{{MemberID: 102, Alarm: pb.AlarmType_NOSPACE}},
+			expectDefragmentedNodes: []string{"other-node", "leader-node"},
+			expectDisarmedAlarms:    []string{"102:NOSPACE"},
+		},
+		{
+			name:      "skips maintenance if members do not match nodes",
+			nodeNames: []string{"leader-node"},
+			expectErr: true,
+		},
+		{
+			name:      "skips maintenance if a member has a CORRUPT alarm",
+			nodeNames: []string{"leader-node", "other-node"},
+			alarms:    []*pb.AlarmMember{{MemberID: 102, Alarm: pb.AlarmType_CORRUPT}},
+			expectErr: true,
+		},
+		{
+			name:                    "stops maintenance if a defragmented member does not become healthy",
+			nodeNames:               []string{"leader-node", "other-node"},
+			alarms:                  []*pb.AlarmMember{{MemberID: 102, Alarm: pb.AlarmType_NOSPACE}},
+			unhealthyNodes:          []string{"other-node"},
+			expectErr:               true,
+			expectDefragmentedNodes: []string{"other-node"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			leaderEtcdClient := &fake2.FakeEtcdClient{
+				MemberListResponse: &clientv3.MemberListResponse{Members: members},
+				AlarmResponse:      &clientv3.AlarmResponse{Alarms: tt.alarms},
+			}
+			nodeEtcdClients := []*fake2.FakeEtcdClient{}
+			etcdClientGenerator := &fakeEtcdClientGenerator{
+				forLeaderClient: &etcd.Client{
+					EtcdClient: leaderEtcdClient,
+					LeaderID:   101,
+				},
+				forNodesClientFunc: func(n []string) (*etcd.Client, error) {
+					nodeEtcdClient := &fake2.FakeEtcdClient{
+						MemberListResponse: &clientv3.MemberListResponse{Members: members},
+						AlarmResponse:      &clientv3.AlarmResponse{Alarms: tt.alarms},
+					}
+					nodeEtcdClients = append(nodeEtcdClients, nodeEtcdClient)
+					var errs []string
+					for _, unhealthyNode := range tt.unhealthyNodes {
+						if unhealthyNode == n[0] {
+							errs = append(errs, "member is unhealthy")
+						}
+					}
+					return &etcd.Client{EtcdClient: nodeEtcdClient, Endpoint: n[0], Errors: errs}, nil
+				},
+			}
+
+			w := &Workload{
+				etcdClientGenerator: etcdClientGenerator,
+			}
+			result, err := w.MaintainEtcd(ctx, tt.nodeNames)
+
+			defragmentedNodes := []string{}
+			for _, nodeEtcdClient := range nodeEtcdClients {
+				if nodeEtcdClient.DefragmentedEndpoint != "" {
+					defragmentedNodes = append(defragmentedNodes, nodeEtcdClient.DefragmentedEndpoint)
+				}
+			}
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(defragmentedNodes).To(ConsistOf(tt.expectDefragmentedNodes))
+				g.Expect(leaderEtcdClient.DisarmedAlarms).To(BeEmpty())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(defragmentedNodes).To(Equal(tt.expectDefragmentedNodes))
+			g.Expect(result.DefragmentedMembers).To(Equal(tt.expectDefragmentedNodes))
+			g.Expect(result.DisarmedAlarms).To(Equal(tt.expectDisarmedAlarms))
+			g.Expect(leaderEtcdClient.DisarmedAlarms).To(HaveLen(len(tt.expectDisarmedAlarms)))
+		})
+	}
+}
+
 func TestReconcileEtcdMembers(t *testing.T) {
 	kubeadmConfig := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -28,6 +28,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -1220,6 +1221,161 @@ func TestUpdateSchedulerInKubeadmConfigMap(t *testing.T) {
 	}
 }
 
+func TestUpdateControlPlaneEndpointInKubeadmConfigMap(t *testing.T) {
+	tests := []struct {
+		name                     string
+		clusterConfigurationData string
+		newEndpoint              string
+		wantClusterConfiguration string
+	}{
+		{
+			name: "it should set the control plane endpoint",
+			clusterConfigurationData: yaml.Raw(`
+				apiVersion: kubeadm.k8s.io/v1beta2
+				kind: ClusterConfiguration
+				controlPlaneEndpoint: old.example.com:6443
+				`),
+			newEndpoint: "new.example.com:6443",
+			wantClusterConfiguration: yaml.Raw(`
+				apiServer: {}
+				apiVersion: kubeadm.k8s.io/v1beta2
+				controlPlaneEndpoint: new.example.com:6443
+				controllerManager: {}
+				dns: {}
+				etcd: {}
+				kind: ClusterConfiguration
+				networking: {}
+				scheduler: {}
+				`),
+		},
+		{
+			name: "it shouldn't write empty control plane endpoint",
+			clusterConfigurationData: yaml.Raw(`
+				apiVersion: kubeadm.k8s.io/v1beta2
+				kind: ClusterConfiguration
+				controlPlaneEndpoint: old.example.com:6443
+				`),
+			newEndpoint: "",
+			wantClusterConfiguration: yaml.Raw(`
+				apiVersion: kubeadm.k8s.io/v1beta2
+				kind: ClusterConfiguration
+				controlPlaneEndpoint: old.example.com:6443
+				`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubeadmConfigKey,
+					Namespace: metav1.NamespaceSystem,
+				},
+				Data: map[string]string{
+					clusterConfigurationKey: tt.clusterConfigurationData,
+				},
+			}).Build()
+
+			w := &Workload{
+				Client: fakeClient,
+			}
+			err := w.UpdateControlPlaneEndpointInKubeadmConfigMap(ctx, tt.newEndpoint, semver.MustParse("1.19.1"))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var actualConfig corev1.ConfigMap
+			g.Expect(w.Client.Get(
+				ctx,
+				client.ObjectKey{Name: kubeadmConfigKey, Namespace: metav1.NamespaceSystem},
+				&actualConfig,
+			)).To(Succeed())
+			g.Expect(actualConfig.Data[clusterConfigurationKey]).Should(Equal(tt.wantClusterConfiguration), cmp.Diff(tt.wantClusterConfiguration, actualConfig.Data[clusterConfigurationKey]))
+		})
+	}
+}
+
+func TestUpdateClusterInfoConfigMap(t *testing.T) {
+	clusterInfoKubeconfig := yaml.Raw(`
+		apiVersion: v1
+		clusters:
+		- cluster:
+		    certificate-authority-data: Q0EK
+		    server: https://old.example.com:6443
+		  name: ""
+		contexts: null
+		current-context: ""
+		kind: Config
+		preferences: {}
+		users: null
+		`)
+
+	tests := []struct {
+		name        string
+		objs        []client.Object
+		newEndpoint string
+		wantServer  string
+		wantErr     bool
+	}{
+		{
+			name: "it should set the server",
+			objs: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster-info",
+					Namespace: metav1.NamespacePublic,
+				},
+				Data: map[string]string{
+					"kubeconfig": clusterInfoKubeconfig,
+				},
+			}},
+			newEndpoint: "new.example.com:6443",
+			wantServer:  "https://new.example.com:6443",
+		},
+		{
+			name: "it should preserve the server if the endpoint is unchanged",
+			objs: []client.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster-info",
+					Namespace: metav1.NamespacePublic,
+				},
+				Data: map[string]string{
+					"kubeconfig": clusterInfoKubeconfig,
+				},
+			}},
+			newEndpoint: "old.example.com:6443",
+			wantServer:  "https://old.example.com:6443",
+		},
+		{
+			name:        "it should fail if the cluster-info config map does not exist",
+			newEndpoint: "new.example.com:6443",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			w := &Workload{
+				Client: fake.NewClientBuilder().WithObjects(tt.objs...).Build(),
+			}
+			err := w.UpdateClusterInfoConfigMap(ctx, tt.newEndpoint)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var actualConfig corev1.ConfigMap
+			g.Expect(w.Client.Get(
+				ctx,
+				client.ObjectKey{Name: "cluster-info", Namespace: metav1.NamespacePublic},
+				&actualConfig,
+			)).To(Succeed())
+			config, err := clientcmd.Load([]byte(actualConfig.Data["kubeconfig"]))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(config.Clusters).To(HaveKey(""))
+			g.Expect(config.Clusters[""].Server).To(Equal(tt.wantServer))
+		})
+	}
+}
+
 func TestClusterStatus(t *testing.T) {
 	node1 := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -90,6 +90,7 @@ var (
 	webhookCertDir                 string
 	healthAddr                     string
 	etcdDialTimeout                time.Duration
+	etcdMaintenanceInterval        time.Duration
 	tlsOptions                     = flags.TLSOptions{}
 	logOptions                     = logs.NewOptions()
 )
@@ -141,6 +142,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&etcdDialTimeout, "etcd-dial-timeout-duration", 10*time.Second,
 		"Duration that the etcd client waits at most to establish a connection with etcd")
 
+	fs.DurationVar(&etcdMaintenanceInterval, "etcd-maintenance-interval", 0,
+		"Interval between periodic etcd maintenance operations, i.e. defragmentation of etcd members and disarming of NOSPACE alarms (e.g. 24h). If unspecified or zero, etcd maintenance is disabled.")
+
 	flags.AddTLSOptions(fs, &tlsOptions)
 
 	feature.MutableGates.AddFlag(fs)
@@ -261,11 +265,12 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 
 	if err := (&kubeadmcontrolplanecontrollers.KubeadmControlPlaneReconciler{
-		Client:           mgr.GetClient(),
-		APIReader:        mgr.GetAPIReader(),
-		Tracker:          tracker,
-		WatchFilterValue: watchFilterValue,
-		EtcdDialTimeout:  etcdDialTimeout,
+		Client:                  mgr.GetClient(),
+		APIReader:               mgr.GetAPIReader(),
+		Tracker:                 tracker,
+		WatchFilterValue:        watchFilterValue,
+		EtcdDialTimeout:         etcdDialTimeout,
+		EtcdMaintenanceInterval: etcdMaintenanceInterval,
 	}).SetupWithManager(ctx, mgr, concurrency(kubeadmControlPlaneConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmControlPlane")
 		os.Exit(1)
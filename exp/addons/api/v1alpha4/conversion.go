@@ -17,9 +17,11 @@ limitations under the License.
 package v1alpha4
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 )
 
 func (src *ClusterResourceSet) ConvertTo(dstRaw conversion.Hub) error {
@@ -49,13 +51,42 @@ func (dst *ClusterResourceSetList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *ClusterResourceSetBinding) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*addonsv1.ClusterResourceSetBinding)
 
-	return Convert_v1alpha4_ClusterResourceSetBinding_To_v1beta1_ClusterResourceSetBinding(src, dst, nil)
+	if err := Convert_v1alpha4_ClusterResourceSetBinding_To_v1beta1_ClusterResourceSetBinding(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &addonsv1.ClusterResourceSetBinding{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	for i := range restored.Spec.Bindings {
+		if i >= len(dst.Spec.Bindings) || restored.Spec.Bindings[i] == nil || dst.Spec.Bindings[i] == nil {
+			break
+		}
+		for j := range restored.Spec.Bindings[i].Resources {
+			if j >= len(dst.Spec.Bindings[i].Resources) {
+				break
+			}
+			dst.Spec.Bindings[i].Resources[j].SkippedObjects = restored.Spec.Bindings[i].Resources[j].SkippedObjects
+			dst.Spec.Bindings[i].Resources[j].Conflicts = restored.Spec.Bindings[i].Resources[j].Conflicts
+		}
+	}
+	dst.Status = restored.Status
+
+	return nil
 }
 
 func (dst *ClusterResourceSetBinding) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*addonsv1.ClusterResourceSetBinding)
 
-	return Convert_v1beta1_ClusterResourceSetBinding_To_v1alpha4_ClusterResourceSetBinding(src, dst, nil)
+	if err := Convert_v1beta1_ClusterResourceSetBinding_To_v1alpha4_ClusterResourceSetBinding(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *ClusterResourceSetBindingList) ConvertTo(dstRaw conversion.Hub) error {
@@ -69,3 +100,49 @@ func (dst *ClusterResourceSetBindingList) ConvertFrom(srcRaw conversion.Hub) err
 
 	return Convert_v1beta1_ClusterResourceSetBindingList_To_v1alpha4_ClusterResourceSetBindingList(src, dst, nil)
 }
+
+func Convert_v1beta1_ClusterResourceSetBinding_To_v1alpha4_ClusterResourceSetBinding(in *addonsv1.ClusterResourceSetBinding, out *ClusterResourceSetBinding, s apiconversion.Scope) error {
+	// status has been added with v1beta1.
+	return autoConvert_v1beta1_ClusterResourceSetBinding_To_v1alpha4_ClusterResourceSetBinding(in, out, s)
+}
+
+func Convert_v1beta1_ResourceBinding_To_v1alpha4_ResourceBinding(in *addonsv1.ResourceBinding, out *ResourceBinding, s apiconversion.Scope) error {
+	// resources.{skippedObjects,conflicts} have been added with v1beta1.
+	return autoConvert_v1beta1_ResourceBinding_To_v1alpha4_ResourceBinding(in, out, s)
+}
+
+func Convert_v1alpha4_ClusterResourceSetBindingSpec_To_v1beta1_ClusterResourceSetBindingSpec(in *ClusterResourceSetBindingSpec, out *addonsv1.ClusterResourceSetBindingSpec, s apiconversion.Scope) error {
+	if in.Bindings == nil {
+		out.Bindings = nil
+		return nil
+	}
+	out.Bindings = make([]*addonsv1.ResourceSetBinding, len(in.Bindings))
+	for i := range in.Bindings {
+		if in.Bindings[i] == nil {
+			continue
+		}
+		out.Bindings[i] = &addonsv1.ResourceSetBinding{}
+		if err := Convert_v1alpha4_ResourceSetBinding_To_v1beta1_ResourceSetBinding(in.Bindings[i], out.Bindings[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func Convert_v1beta1_ClusterResourceSetBindingSpec_To_v1alpha4_ClusterResourceSetBindingSpec(in *addonsv1.ClusterResourceSetBindingSpec, out *ClusterResourceSetBindingSpec, s apiconversion.Scope) error {
+	if in.Bindings == nil {
+		out.Bindings = nil
+		return nil
+	}
+	out.Bindings = make([]*ResourceSetBinding, len(in.Bindings))
+	for i := range in.Bindings {
+		if in.Bindings[i] == nil {
+			continue
+		}
+		out.Bindings[i] = &ResourceSetBinding{}
+		if err := Convert_v1beta1_ResourceSetBinding_To_v1alpha4_ResourceSetBinding(in.Bindings[i], out.Bindings[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
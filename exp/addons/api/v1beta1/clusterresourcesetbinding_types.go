@@ -21,6 +21,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 )
 
@@ -42,6 +43,16 @@ type ResourceBinding struct {
 
 	// Applied is to track if a resource is applied to the cluster or not.
 	Applied bool `json:"applied"`
+
+	// SkippedObjects is a list of objects defined in the resource that were not applied to the cluster
+	// because they already exist and are not managed by the ClusterResourceSet.
+	// +optional
+	SkippedObjects []string `json:"skippedObjects,omitempty"`
+
+	// Conflicts is a list of objects defined in the resource that were not applied to the cluster
+	// because some of their fields are owned by other field managers, together with the conflicting fields.
+	// +optional
+	Conflicts []string `json:"conflicts,omitempty"`
 }
 
 // ANCHOR_END: ResourceBinding
@@ -68,6 +79,16 @@ func (r *ResourceSetBinding) IsApplied(resourceRef ResourceRef) bool {
 	return false
 }
 
+// GetBinding returns a copy of the resourceBinding for a resource in resourceSetBinding, if any.
+func (r *ResourceSetBinding) GetBinding(resourceRef ResourceRef) *ResourceBinding {
+	for i := range r.Resources {
+		if reflect.DeepEqual(r.Resources[i].ResourceRef, resourceRef) {
+			return r.Resources[i].DeepCopy()
+		}
+	}
+	return nil
+}
+
 // SetBinding sets resourceBinding for a resource in resourceSetbinding either by updating the existing one or
 // creating a new one.
 func (r *ResourceSetBinding) SetBinding(resourceBinding ResourceBinding) {
@@ -119,7 +140,8 @@ func (c *ClusterResourceSetBinding) DeleteBinding(clusterResourceSet *ClusterRes
 type ClusterResourceSetBinding struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              ClusterResourceSetBindingSpec `json:"spec,omitempty"`
+	Spec              ClusterResourceSetBindingSpec   `json:"spec,omitempty"`
+	Status            ClusterResourceSetBindingStatus `json:"status,omitempty"`
 }
 
 // ANCHOR: ClusterResourceSetBindingSpec
@@ -133,6 +155,52 @@ type ClusterResourceSetBindingSpec struct {
 
 // ANCHOR_END: ClusterResourceSetBindingSpec
 
+// ANCHOR: ClusterResourceSetBindingStatus
+
+// ClusterResourceSetBindingStatus defines the observed state of ClusterResourceSetBinding.
+type ClusterResourceSetBindingStatus struct {
+	// Resources reports the health of the objects applied to the cluster, for each resource of the ClusterResourceSets.
+	// +optional
+	Resources []ResourceHealth `json:"resources,omitempty"`
+
+	// LastHealthCheckTime identifies when the health check last detected a change in the health of the resources.
+	// +optional
+	LastHealthCheckTime *metav1.Time `json:"lastHealthCheckTime,omitempty"`
+
+	// Conditions defines current state of the ClusterResourceSetBinding.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// ResourceHealth shows the health of the objects defined in a resource of a ClusterResourceSet, as observed in the cluster.
+type ResourceHealth struct {
+	// ClusterResourceSetName is the name of the ClusterResourceSet the resource belongs to.
+	ClusterResourceSetName string `json:"clusterResourceSetName"`
+
+	// ResourceRef specifies a resource.
+	ResourceRef `json:",inline"`
+
+	// Healthy is true if all the objects defined in the resource are healthy, e.g. Deployments are available
+	// and DaemonSets are ready; objects of other kinds are healthy if they exist.
+	Healthy bool `json:"healthy"`
+
+	// UnhealthyObjects is a list of objects defined in the resource which are not healthy, together with the reason.
+	// +optional
+	UnhealthyObjects []string `json:"unhealthyObjects,omitempty"`
+}
+
+// ANCHOR_END: ClusterResourceSetBindingStatus
+
+// GetConditions returns the set of conditions for this object.
+func (c *ClusterResourceSetBinding) GetConditions() clusterv1.Conditions {
+	return c.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (c *ClusterResourceSetBinding) SetConditions(conditions clusterv1.Conditions) {
+	c.Status.Conditions = conditions
+}
+
 // +kubebuilder:object:root=true
 
 // ClusterResourceSetBindingList contains a list of ClusterResourceSetBinding.
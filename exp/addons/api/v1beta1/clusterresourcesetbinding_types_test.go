@@ -90,6 +90,35 @@ func TestIsResourceApplied(t *testing.T) {
 	}
 }
 
+func TestGetResourceBinding(t *testing.T) {
+	g := NewWithT(t)
+
+	resourceRef := ResourceRef{
+		Name: "applied",
+		Kind: "Secret",
+	}
+	CRSBinding := &ResourceSetBinding{
+		ClusterResourceSetName: "test-clusterResourceSet",
+		Resources: []ResourceBinding{
+			{
+				ResourceRef: resourceRef,
+				Applied:     true,
+				Hash:        "xyz",
+			},
+		},
+	}
+
+	binding := CRSBinding.GetBinding(resourceRef)
+	g.Expect(binding).ToNot(BeNil())
+	g.Expect(binding.Hash).To(Equal("xyz"))
+
+	// The returned binding is a copy.
+	binding.Hash = ""
+	g.Expect(CRSBinding.Resources[0].Hash).To(Equal("xyz"))
+
+	g.Expect(CRSBinding.GetBinding(ResourceRef{Name: "notExisting", Kind: "Secret"})).To(BeNil())
+}
+
 func TestSetResourceBinding(t *testing.T) {
 	resourceRefApplyFailed := ResourceRef{
 		Name: "applyFailed",
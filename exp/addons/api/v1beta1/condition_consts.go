@@ -34,9 +34,27 @@ const (
 	// ApplyFailedReason (Severity=Warning) documents applying at least one of the resources to one of the matching clusters is failed.
 	ApplyFailedReason = "ApplyFailed"
 
+	// ApplyConflictReason (Severity=Warning) documents at least one of the objects in the resources is not applied
+	// to one of the matching clusters because some of its fields are owned by other field managers.
+	ApplyConflictReason = "ApplyConflict"
+
 	// RetrievingResourceFailedReason (Severity=Warning) documents at least one of the resources are not successfully retrieved.
 	RetrievingResourceFailedReason = "RetrievingResourceFailed"
 
 	// WrongSecretTypeReason (Severity=Warning) documents at least one of the Secret's type in the resource list is not supported.
 	WrongSecretTypeReason = "WrongSecretType"
 )
+
+// Conditions and condition Reasons for the ClusterResourceSetBinding object.
+
+const (
+	// ResourcesHealthyCondition documents that all the objects applied to the cluster by the ClusterResourceSets
+	// are healthy, e.g. Deployments are available and DaemonSets are ready.
+	ResourcesHealthyCondition clusterv1.ConditionType = "ResourcesHealthy"
+
+	// ResourcesUnhealthyReason (Severity=Warning) documents at least one of the objects applied to the cluster is not healthy.
+	ResourcesUnhealthyReason = "ResourcesUnhealthy"
+
+	// HealthCheckFailedReason (Severity=Warning) documents a failure while checking the health of the objects applied to the cluster.
+	HealthCheckFailedReason = "HealthCheckFailed"
+)
@@ -46,7 +46,8 @@ func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr
 
 // ClusterResourceSetBindingReconciler reconciles a ClusterResourceSetBinding object.
 type ClusterResourceSetBindingReconciler struct {
-	Client client.Client
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
@@ -55,6 +56,7 @@ type ClusterResourceSetBindingReconciler struct {
 func (r *ClusterResourceSetBindingReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&clusterresourcesets.ClusterResourceSetBindingReconciler{
 		Client:           r.Client,
+		Tracker:          r.Tracker,
 		WatchFilterValue: r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -47,6 +47,7 @@ import (
 	resourcepredicates "sigs.k8s.io/cluster-api/exp/addons/internal/controllers/predicates"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
@@ -152,13 +153,9 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	for _, cluster := range clusters {
 		if err := r.ApplyClusterResourceSet(ctx, cluster, clusterResourceSet); err != nil {
-			// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
-			// the current cluster because of concurrent access.
-			if errors.Is(err, remote.ErrClusterLocked) {
-				log.V(5).Info("Requeueing because another worker has the lock on the ClusterCacheTracker")
-				return ctrl.Result{Requeue: true}, nil
-			}
-			return ctrl.Result{}, err
+			// Requeue if the reconcile failed because of a RequeueAfterError, e.g. because the ClusterCacheTracker
+			// was locked for the current cluster because of concurrent access.
+			return utilerrors.ReconcileResult(ctx, ctrl.Result{}, err)
 		}
 	}
 
@@ -241,7 +238,8 @@ func (r *ClusterResourceSetReconciler) getClustersByClusterResourceSetSelector(c
 // cluster's ClusterResourceSetBinding.
 // In ApplyOnce strategy, resources are applied only once to a particular cluster. ClusterResourceSetBinding is used to check if a resource is applied before.
 // It applies resources best effort and continue on scenarios like: unsupported resource types, failure during creation, missing resources.
-// TODO: If a resource already exists in the cluster but not applied by ClusterResourceSet, the resource will be updated ?
+// Resources are applied using server side apply; objects already existing in the cluster but not applied by ClusterResourceSet are skipped,
+// while objects with fields owned by other field managers are not applied and reported as conflicts in the ClusterResourceSetBinding.
 func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) error {
 	log := ctrl.LoggerFrom(ctx, "Cluster", klog.KObj(cluster))
 	ctx = ctrl.LoggerInto(ctx, log)
@@ -274,6 +272,7 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 	// Ensure that the owner references are set on the ClusterResourceSetBinding.
 	clusterResourceSetBinding.OwnerReferences = ensureOwnerRefs(clusterResourceSetBinding, clusterResourceSet, cluster)
 	errList := []error{}
+	hasConflicts := false
 	resourceSetBinding := clusterResourceSetBinding.GetOrCreateBinding(clusterResourceSet)
 
 	// Iterate all resources and apply them to the cluster and update the resource status in the ClusterResourceSetBinding object.
@@ -299,6 +298,10 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			continue
 		}
 
+		// Keep track of the status of the resource recorded in the ClusterResourceSetBinding before this attempt,
+		// which is used to detect objects created by a previous version of the ClusterResourceSet controller.
+		previousBinding := resourceSetBinding.GetBinding(resource)
+
 		// Set status in ClusterResourceSetBinding in case of early continue due to a failure.
 		// Set only when resource is retrieved successfully.
 		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
@@ -345,31 +348,62 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			dataList = append(dataList, byteArr)
 		}
 
+		// The status recorded in the ClusterResourceSetBinding is used to detect objects created by a previous version
+		// of the ClusterResourceSet controller only if the data of the resource did not change since then; otherwise
+		// objects which are now defined in the resource could be wrongly considered as created by the ClusterResourceSet.
+		hash := computeHash(dataList)
+		if previousBinding != nil && previousBinding.Hash != hash {
+			previousBinding = nil
+		}
+
 		// Apply all values in the key-value pair of the resource to the cluster.
 		// As there can be multiple key-value pairs in a resource, each value may have multiple objects in it.
 		isSuccessful := true
+		var skippedObjects, conflicts []string
 		for i := range dataList {
 			data := dataList[i]
 
-			if err := apply(ctx, remoteClient, data); err != nil {
+			result, err := apply(ctx, remoteClient, data, previousBinding)
+			if err != nil {
 				isSuccessful = false
 				log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
 				conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 				errList = append(errList, err)
 			}
+			if result != nil {
+				skippedObjects = append(skippedObjects, result.Skipped...)
+				conflicts = append(conflicts, result.Conflicts...)
+			}
+		}
+
+		// Objects with conflicts are not applied, so the resource is not considered applied; conflicts
+		// are reported in the ClusterResourceSetBinding instead of failing the reconcile, given that they
+		// require a change by the user to be solved.
+		if len(conflicts) > 0 {
+			isSuccessful = false
+			hasConflicts = true
+			log.Info("Some objects of the ClusterResourceSet resource have not been applied because of conflicts", "Resource kind", resource.Kind, "Resource name", resource.Name, "conflicts", conflicts)
 		}
 
 		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
 			ResourceRef:     resource,
-			Hash:            computeHash(dataList),
+			Hash:            hash,
 			Applied:         isSuccessful,
 			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+			SkippedObjects:  skippedObjects,
+			Conflicts:       conflicts,
 		})
 	}
 	if len(errList) > 0 {
 		return kerrors.NewAggregate(errList)
 	}
 
+	if hasConflicts {
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyConflictReason, clusterv1.ConditionSeverityWarning,
+			"Some objects have not been applied to Cluster %s because of conflicts, check ClusterResourceSetBinding %s for details", cluster.Name, clusterResourceSetBinding.Name)
+		return nil
+	}
+
 	conditions.MarkTrue(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
 
 	return nil
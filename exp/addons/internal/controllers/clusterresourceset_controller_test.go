@@ -553,6 +553,87 @@ metadata:
 		g.Expect(env.Delete(ctx, testCluster)).To(Succeed())
 	})
 
+	t.Run("Should skip objects already existing in the cluster and not created by the ClusterResourceSet", func(t *testing.T) {
+		g := NewWithT(t)
+		ns := setup(t, g)
+		defer teardown(t, g, ns)
+
+		t.Log("Creating a ConfigMap in the cluster, which is not managed by the ClusterResourceSet")
+		existingConfigmap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing-configmap",
+				Namespace: ns.Name,
+			},
+		}
+		g.Expect(env.Create(ctx, existingConfigmap)).To(Succeed())
+		defer func() {
+			g.Expect(env.Delete(ctx, existingConfigmap)).To(Succeed())
+		}()
+
+		resourceConfigmapName := "test-configmap-existing"
+		resourceConfigmap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceConfigmapName,
+				Namespace: ns.Name,
+			},
+			Data: map[string]string{
+				"cm": fmt.Sprintf(`metadata:
+ name: existing-configmap
+ namespace: %s
+kind: ConfigMap
+apiVersion: v1
+data:
+ foo: bar`, ns.Name),
+			},
+		}
+		g.Expect(env.Create(ctx, resourceConfigmap)).To(Succeed())
+		defer func() {
+			g.Expect(env.Delete(ctx, resourceConfigmap)).To(Succeed())
+		}()
+
+		testCluster.SetLabels(labels)
+		g.Expect(env.Update(ctx, testCluster)).To(Succeed())
+
+		clusterResourceSetInstance := &addonsv1.ClusterResourceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      clusterResourceSetName,
+				Namespace: ns.Name,
+			},
+			Spec: addonsv1.ClusterResourceSetSpec{
+				ClusterSelector: metav1.LabelSelector{
+					MatchLabels: labels,
+				},
+				Resources: []addonsv1.ResourceRef{{Name: resourceConfigmapName, Kind: "ConfigMap"}},
+			},
+		}
+		// Create the ClusterResourceSet.
+		g.Expect(env.Create(ctx, clusterResourceSetInstance)).To(Succeed())
+
+		t.Log("Verifying the existing ConfigMap is reported as skipped in the ClusterResourceSetBinding")
+		g.Eventually(func() bool {
+			binding := &addonsv1.ClusterResourceSetBinding{}
+			clusterResourceSetBindingKey := client.ObjectKey{
+				Namespace: testCluster.Namespace,
+				Name:      testCluster.Name,
+			}
+			if err := env.Get(ctx, clusterResourceSetBindingKey, binding); err != nil {
+				return false
+			}
+			if len(binding.Spec.Bindings) != 1 || len(binding.Spec.Bindings[0].Resources) != 1 {
+				return false
+			}
+			resource := binding.Spec.Bindings[0].Resources[0]
+			return resource.Applied && len(resource.SkippedObjects) == 1 && len(resource.Conflicts) == 0
+		}, timeout).Should(BeTrue())
+
+		t.Log("Verifying the existing ConfigMap has not been changed")
+		g.Expect(env.Get(ctx, client.ObjectKeyFromObject(existingConfigmap), existingConfigmap)).To(Succeed())
+		g.Expect(existingConfigmap.Data).To(BeEmpty())
+
+		t.Log("Deleting the Cluster")
+		g.Expect(env.Delete(ctx, testCluster)).To(Succeed())
+	})
+
 	t.Run("Should add finalizer after reconcile", func(t *testing.T) {
 		g := NewWithT(t)
 		ns := setup(t, g)
@@ -23,6 +23,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"unicode"
 
 	"github.com/pkg/errors"
@@ -31,13 +32,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
-	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
-	utilresource "sigs.k8s.io/cluster-api/util/resource"
+	utilapply "sigs.k8s.io/cluster-api/util/apply"
 	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
 )
 
@@ -55,10 +55,36 @@ func isJSONList(data []byte) (bool, error) {
 	return bytes.HasPrefix(trim, jsonListPrefix), nil
 }
 
-func apply(ctx context.Context, c client.Client, data []byte) error {
+// clusterResourceSetManagerName is the field manager used by the ClusterResourceSet controller
+// when applying objects to the workload clusters.
+const clusterResourceSetManagerName = "capi-clusterresourceset"
+
+// apply applies the objects defined in the data of a ClusterResourceSet resource; previousBinding is the status of the
+// resource recorded in the ClusterResourceSetBinding before this attempt for the same data, if any.
+func apply(ctx context.Context, c client.Client, data []byte, previousBinding *addonsv1.ResourceBinding) (*utilapply.Result, error) {
+	objs, err := objsFromData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the objects on the API server using server side apply.
+	// NOTE: Objects already existing in the cluster which have not been created by the ClusterResourceSet are skipped,
+	// so the ClusterResourceSet never takes over objects managed by someone else. Objects are applied without forcing
+	// ownership, so fields changed by other field managers are reported as conflicts.
+	// TODO: Errors are only logged. If needed, exponential backoff or requeuing could be used here for remedying connection glitches etc.
+	return utilapply.Apply(ctx, c, objs, utilapply.Options{
+		FieldManager: clusterResourceSetManagerName,
+		SkipExisting: func(current *unstructured.Unstructured) bool {
+			return !isManagedByClusterResourceSet(current, previousBinding)
+		},
+	})
+}
+
+// objsFromData converts the data of a resource, in JSON list, JSON or YAML format, to unstructured objects.
+func objsFromData(data []byte) ([]unstructured.Unstructured, error) {
 	isJSONList, err := isJSONList(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	objs := []unstructured.Unstructured{}
 	// If it is a json list, convert each list element to an unstructured object.
@@ -72,40 +98,41 @@ func apply(ctx context.Context, c client.Client, data []byte) error {
 				objs = append(objs, u)
 			}
 		}
-	} else {
-		// If it is not a json list, data is either json or yaml format.
-		objs, err = utilyaml.ToUnstructured(data)
-		if err != nil {
-			return errors.Wrapf(err, "failed converting data to unstructured objects")
-		}
+		return objs, nil
 	}
 
-	errList := []error{}
-	sortedObjs := utilresource.SortForCreate(objs)
-	for i := range sortedObjs {
-		if err := applyUnstructured(ctx, c, &sortedObjs[i]); err != nil {
-			errList = append(errList, err)
-		}
+	// If it is not a json list, data is either json or yaml format.
+	objs, err = utilyaml.ToUnstructured(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed converting data to unstructured objects")
 	}
-	return kerrors.NewAggregate(errList)
+	return objs, nil
 }
 
-func applyUnstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
-	// Create the object on the API server.
-	// TODO: Errors are only logged. If needed, exponential backoff or requeuing could be used here for remedying connection glitches etc.
-	if err := c.Create(ctx, obj); err != nil {
-		// The create call is idempotent, so if the object already exists
-		// then do not consider it to be an error.
-		if !apierrors.IsAlreadyExists(err) {
-			return errors.Wrapf(
-				err,
-				"failed to create object %s %s/%s",
-				obj.GroupVersionKind(),
-				obj.GetNamespace(),
-				obj.GetName())
+// isManagedByClusterResourceSet returns true if an object existing in the cluster has been applied by the ClusterResourceSet,
+// i.e. if the ClusterResourceSet field manager owns fields of the object, or if the object has been created by a previous
+// version of the ClusterResourceSet controller; the latter is the case if the ClusterResourceSetBinding records a previous
+// attempt to apply the same data of the resource defining the object, and the object has not been skipped by it.
+func isManagedByClusterResourceSet(obj client.Object, previousBinding *addonsv1.ResourceBinding) bool {
+	for _, managedField := range obj.GetManagedFields() {
+		if managedField.Manager == clusterResourceSetManagerName {
+			return true
+		}
+	}
+
+	// NOTE: Previous versions of the ClusterResourceSet controller recorded the hash of the resource only after
+	// attempting to apply it, and they did not record skipped objects.
+	if previousBinding == nil || previousBinding.Hash == "" {
+		return false
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		for _, skipped := range previousBinding.SkippedObjects {
+			if skipped == utilapply.ObjectID(u) {
+				return false
+			}
 		}
 	}
-	return nil
+	return true
 }
 
 // getOrCreateClusterResourceSetBinding retrieves ClusterResourceSetBinding resource owned by the cluster or create a new one if not found.
@@ -186,6 +213,44 @@ func getSecret(ctx context.Context, c client.Client, secretName types.Namespaced
 	return secret, nil
 }
 
+// getResourceData retrieves the data of a ClusterResourceSet resource, i.e. a ConfigMap or a Secret, ordered by key.
+func getResourceData(ctx context.Context, c client.Client, resourceRef addonsv1.ResourceRef, namespace string) ([][]byte, error) {
+	resourceName := types.NamespacedName{Name: resourceRef.Name, Namespace: namespace}
+
+	data := map[string][]byte{}
+	switch resourceRef.Kind {
+	case string(addonsv1.ConfigMapClusterResourceSetResourceKind):
+		configMap, err := getConfigMap(ctx, c, resourceName)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range configMap.Data {
+			data[key] = []byte(value)
+		}
+	case string(addonsv1.SecretClusterResourceSetResourceKind):
+		secret, err := getSecret(ctx, c, resourceName)
+		if err != nil {
+			return nil, err
+		}
+		if secret.Type != addonsv1.ClusterResourceSetSecretType {
+			return nil, ErrSecretTypeNotSupported
+		}
+		data = secret.Data
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dataList := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		dataList = append(dataList, data[key])
+	}
+	return dataList, nil
+}
+
 func computeHash(dataArr [][]byte) string {
 	hash := sha256.New()
 	for i := range dataArr {
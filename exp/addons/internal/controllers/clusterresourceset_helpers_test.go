@@ -24,12 +24,14 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	utilapply "sigs.k8s.io/cluster-api/util/apply"
 )
 
 const (
@@ -219,3 +221,116 @@ func TestGetConfigMapFromNamespacedName(t *testing.T) {
 		})
 	}
 }
+
+func TestIsManagedByClusterResourceSet(t *testing.T) {
+	objectID := "/v1, Kind=ConfigMap default/foo"
+
+	tests := []struct {
+		name            string
+		managedFields   []metav1.ManagedFieldsEntry
+		previousBinding *addonsv1.ResourceBinding
+		want            bool
+	}{
+		{
+			name: "object without managed fields",
+			want: false,
+		},
+		{
+			name:          "object managed by other field managers",
+			managedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			want:          false,
+		},
+		{
+			name:          "object managed by the ClusterResourceSet",
+			managedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}, {Manager: clusterResourceSetManagerName}},
+			want:          true,
+		},
+		{
+			name:          "object managed by a generic field manager, e.g. another controller built with controller-runtime",
+			managedFields: []metav1.ManagedFieldsEntry{{Manager: "manager", Operation: metav1.ManagedFieldsOperationUpdate}},
+			want:          false,
+		},
+		{
+			name:            "object not managed by the ClusterResourceSet, resource never applied",
+			managedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			previousBinding: &addonsv1.ResourceBinding{Hash: ""},
+			want:            false,
+		},
+		{
+			name:            "object not managed by the ClusterResourceSet, resource recorded as applied in the ClusterResourceSetBinding",
+			managedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			previousBinding: &addonsv1.ResourceBinding{Hash: "xyz", Applied: true},
+			want:            true,
+		},
+		{
+			name:            "object not managed by the ClusterResourceSet, object skipped by the previous attempt to apply the resource",
+			managedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			previousBinding: &addonsv1.ResourceBinding{Hash: "xyz", SkippedObjects: []string{objectID}},
+			want:            false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion("v1")
+			obj.SetKind("ConfigMap")
+			obj.SetNamespace(metav1.NamespaceDefault)
+			obj.SetName("foo")
+			obj.SetManagedFields(tt.managedFields)
+			g.Expect(utilapply.ObjectID(obj)).To(Equal(objectID))
+			g.Expect(isManagedByClusterResourceSet(obj, tt.previousBinding)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestApplySkipsObjectsNotManagedByClusterResourceSet(t *testing.T) {
+	g := NewWithT(t)
+
+	// An object created by a previous version of the ClusterResourceSet controller, which did not use server side apply
+	// and thus is recorded with the generic field manager of controller-runtime, and an object created by the user.
+	legacy := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "legacy",
+			Namespace:     metav1.NamespaceDefault,
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "manager", Operation: metav1.ManagedFieldsOperationUpdate}},
+		},
+	}
+	user := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "user",
+			Namespace:     metav1.NamespaceDefault,
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate}},
+		},
+	}
+	data := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: legacy
+  namespace: default
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: user
+  namespace: default
+`)
+	c := fake.NewClientBuilder().WithObjects(legacy, user).Build()
+
+	// NOTE: The fake client does not support server side apply, so only the objects which are skipped are checked.
+	// Objects are skipped if they are not managed by the ClusterResourceSet field manager and there is no record
+	// of a previous attempt to apply the resource; the field manager alone is not enough to identify the objects
+	// created by a previous version of the ClusterResourceSet controller.
+	result, _ := apply(ctx, c, data, nil)
+	g.Expect(result).ToNot(BeNil())
+	g.Expect(result.Skipped).To(ConsistOf("/v1, Kind=ConfigMap default/legacy", "/v1, Kind=ConfigMap default/user"))
+
+	// Objects are not skipped if the ClusterResourceSetBinding records a previous attempt to apply the resource,
+	// e.g. by a previous version of the ClusterResourceSet controller.
+	result, _ = apply(ctx, c, data, &addonsv1.ResourceBinding{Hash: "xyz", Applied: true})
+	g.Expect(result).ToNot(BeNil())
+	g.Expect(result.Skipped).To(BeEmpty())
+}
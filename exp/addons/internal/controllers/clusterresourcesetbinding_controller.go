@@ -18,9 +18,14 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -29,18 +34,27 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	"sigs.k8s.io/cluster-api/util"
+	utilapply "sigs.k8s.io/cluster-api/util/apply"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
 // +kubebuilder:rbac:groups=addons.cluster.x-k8s.io,resources=*,verbs=get;list;watch;create;update;patch;delete
 
+// resourcesHealthCheckInterval is the interval at which the health of the objects applied to the Cluster
+// by the ClusterResourceSets is checked.
+const resourcesHealthCheckInterval = 1 * time.Minute
+
 // ClusterResourceSetBindingReconciler reconciles a ClusterResourceSetBinding object.
 type ClusterResourceSetBindingReconciler struct {
-	Client client.Client
+	Client  client.Client
+	Tracker *remote.ClusterCacheTracker
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
@@ -103,7 +117,130 @@ func (r *ClusterResourceSetBindingReconciler) Reconcile(ctx context.Context, req
 		return ctrl.Result{}, r.Client.Delete(ctx, binding)
 	}
 
-	return ctrl.Result{}, nil
+	return r.reconcileHealth(ctx, cluster, binding)
+}
+
+// reconcileHealth checks the health of the objects applied to the Cluster by the ClusterResourceSets, and reports
+// it in the ClusterResourceSetBinding status, so failures of the addons are visible in the management cluster.
+// The health is checked again every resourcesHealthCheckInterval, but the ClusterResourceSetBinding is patched
+// only when the health changes.
+func (r *ClusterResourceSetBindingReconciler) reconcileHealth(ctx context.Context, cluster *clusterv1.Cluster, binding *addonsv1.ClusterResourceSetBinding) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// The health can't be checked until the workload cluster is reachable.
+	if r.Tracker == nil || !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(binding, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		// Always attempt to Patch the ClusterResourceSetBinding status after checking the health of the resources.
+		if err := patchHelper.Patch(ctx, binding, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			addonsv1.ResourcesHealthyCondition,
+		}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		conditions.MarkFalse(binding, addonsv1.ResourcesHealthyCondition, addonsv1.RemoteClusterClientFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	resources := []addonsv1.ResourceHealth{}
+	errList := []error{}
+	unhealthy := 0
+	for _, resourceSetBinding := range binding.Spec.Bindings {
+		if resourceSetBinding == nil {
+			continue
+		}
+		for _, resource := range resourceSetBinding.Resources {
+			// Resources which are not applied yet are not checked.
+			if !resource.Applied {
+				continue
+			}
+
+			unhealthyObjects, err := r.getUnhealthyObjects(ctx, remoteClient, resource.ResourceRef, binding.Namespace)
+			if err != nil {
+				// Resources deleted from the management cluster are not checked anymore; this is
+				// reported by the ResourcesApplied condition of the ClusterResourceSet.
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				errList = append(errList, errors.Wrapf(err, "failed to check the health of %s %s", resource.Kind, resource.Name))
+				continue
+			}
+			if len(unhealthyObjects) > 0 {
+				unhealthy++
+				log.V(4).Info("Some objects of the ClusterResourceSet resource are not healthy", "Resource kind", resource.Kind, "Resource name", resource.Name, "unhealthyObjects", unhealthyObjects)
+			}
+			resources = append(resources, addonsv1.ResourceHealth{
+				ClusterResourceSetName: resourceSetBinding.ClusterResourceSetName,
+				ResourceRef:            resource.ResourceRef,
+				Healthy:                len(unhealthyObjects) == 0,
+				UnhealthyObjects:       unhealthyObjects,
+			})
+		}
+	}
+	setResourcesHealth(binding, resources)
+
+	if len(errList) > 0 {
+		err := kerrors.NewAggregate(errList)
+		conditions.MarkFalse(binding, addonsv1.ResourcesHealthyCondition, addonsv1.HealthCheckFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if unhealthy > 0 {
+		conditions.MarkFalse(binding, addonsv1.ResourcesHealthyCondition, addonsv1.ResourcesUnhealthyReason, clusterv1.ConditionSeverityWarning,
+			"%d of %d resources have objects which are not healthy, check status.resources for details", unhealthy, len(resources))
+	} else {
+		conditions.MarkTrue(binding, addonsv1.ResourcesHealthyCondition)
+	}
+	return ctrl.Result{RequeueAfter: resourcesHealthCheckInterval}, nil
+}
+
+// setResourcesHealth sets the health of the resources in the ClusterResourceSetBinding status, updating
+// LastHealthCheckTime only if the health changed; this avoids patching the ClusterResourceSetBinding, and thus
+// triggering a new reconcile, at every health check.
+func setResourcesHealth(binding *addonsv1.ClusterResourceSetBinding, resources []addonsv1.ResourceHealth) {
+	if len(binding.Status.Resources) == 0 && len(resources) == 0 {
+		return
+	}
+	if reflect.DeepEqual(binding.Status.Resources, resources) {
+		return
+	}
+	binding.Status.Resources = resources
+	binding.Status.LastHealthCheckTime = &metav1.Time{Time: time.Now().UTC()}
+}
+
+// getUnhealthyObjects returns the objects defined in a ClusterResourceSet resource which are not healthy in the
+// workload cluster, together with the reason; objects are healthy when their status is utilapply.CurrentStatus.
+func (r *ClusterResourceSetBindingReconciler) getUnhealthyObjects(ctx context.Context, remoteClient client.Reader, resourceRef addonsv1.ResourceRef, namespace string) ([]string, error) {
+	dataList, err := getResourceData(ctx, r.Client, resourceRef, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var unhealthyObjects []string
+	for _, data := range dataList {
+		objs, err := objsFromData(data)
+		if err != nil {
+			return nil, err
+		}
+		for i := range objs {
+			status, err := utilapply.GetStatus(ctx, remoteClient, &objs[i])
+			if err != nil {
+				return nil, err
+			}
+			if status.Status != utilapply.CurrentStatus {
+				unhealthyObjects = append(unhealthyObjects, fmt.Sprintf("%s: %s: %s", utilapply.ObjectID(&objs[i]), status.Status, status.Message))
+			}
+		}
+	}
+	return unhealthyObjects, nil
 }
 
 // clusterToClusterResourceSetBinding is mapper function that maps clusters to ClusterResourceSetBinding.
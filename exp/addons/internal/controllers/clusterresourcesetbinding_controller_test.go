@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestClusterResourceSetBindingGetUnhealthyObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+
+	resources := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "resources",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string]string{
+			"configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: addon-config
+  namespace: kube-system`,
+			"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: addon
+  namespace: kube-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: missing-addon
+  namespace: kube-system`,
+		},
+	}
+	invalidSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-secret",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	managementClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(resources, invalidSecret).Build()
+
+	remoteClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "addon-config",
+				Namespace: metav1.NamespaceSystem,
+			},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "addon",
+				Namespace:  metav1.NamespaceSystem,
+				Generation: 1,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32(1),
+			},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 1,
+				Replicas:           1,
+				UpdatedReplicas:    1,
+				ReadyReplicas:      1,
+				AvailableReplicas:  1,
+			},
+		},
+	).Build()
+
+	r := &ClusterResourceSetBindingReconciler{Client: managementClient}
+
+	t.Run("Reports objects which are not healthy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		unhealthyObjects, err := r.getUnhealthyObjects(context.TODO(), remoteClient, addonsv1.ResourceRef{Name: "resources", Kind: "ConfigMap"}, metav1.NamespaceDefault)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(unhealthyObjects).To(HaveLen(1))
+		g.Expect(unhealthyObjects[0]).To(HavePrefix("apps/v1, Kind=Deployment kube-system/missing-addon: NotFound"))
+	})
+
+	t.Run("Returns a not found error for resources which do not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := r.getUnhealthyObjects(context.TODO(), remoteClient, addonsv1.ResourceRef{Name: "missing", Kind: "ConfigMap"}, metav1.NamespaceDefault)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	t.Run("Returns an error for Secrets with an unsupported type", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := r.getUnhealthyObjects(context.TODO(), remoteClient, addonsv1.ResourceRef{Name: "invalid-secret", Kind: "Secret"}, metav1.NamespaceDefault)
+		g.Expect(err).To(MatchError(ErrSecretTypeNotSupported))
+	})
+}
+
+func TestSetResourcesHealth(t *testing.T) {
+	g := NewWithT(t)
+
+	binding := &addonsv1.ClusterResourceSetBinding{}
+	resources := []addonsv1.ResourceHealth{
+		{
+			ClusterResourceSetName: "crs",
+			ResourceRef:            addonsv1.ResourceRef{Name: "resources", Kind: "ConfigMap"},
+			Healthy:                true,
+		},
+	}
+
+	// No resources, nothing changes.
+	setResourcesHealth(binding, []addonsv1.ResourceHealth{})
+	g.Expect(binding.Status.LastHealthCheckTime).To(BeNil())
+
+	// The health changed, it is recorded.
+	setResourcesHealth(binding, resources)
+	g.Expect(binding.Status.Resources).To(Equal(resources))
+	g.Expect(binding.Status.LastHealthCheckTime).ToNot(BeNil())
+
+	// The health did not change, the status is not updated.
+	lastHealthCheckTime := metav1.NewTime(time.Now().Add(-time.Hour))
+	binding.Status.LastHealthCheckTime = &lastHealthCheckTime
+	setResourcesHealth(binding, []addonsv1.ResourceHealth{*resources[0].DeepCopy()})
+	g.Expect(binding.Status.LastHealthCheckTime).To(Equal(&lastHealthCheckTime))
+}
@@ -60,7 +60,8 @@ func TestMain(m *testing.M) {
 			panic(fmt.Sprintf("Failed to set up cluster resource set reconciler: %v", err))
 		}
 		bindingReconciler := ClusterResourceSetBindingReconciler{
-			Client: mgr.GetClient(),
+			Client:  mgr.GetClient(),
+			Tracker: tracker,
 		}
 		if err = bindingReconciler.SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: 1}); err != nil {
 			panic(fmt.Sprintf("Failed to set up cluster resource set binding reconciler: %v", err))
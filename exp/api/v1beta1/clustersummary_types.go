@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ANCHOR: ClusterSummaryStatus
+
+// ClusterSummaryStatus defines the observed state of a Cluster, aggregated from the Cluster and its objects.
+type ClusterSummaryStatus struct {
+	// Phase represents the current phase of the Cluster.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// Version is the desired Kubernetes version of the Cluster, read from the Cluster topology
+	// or, if the Cluster does not have a topology, from the control plane.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ControlPlaneVersion is the Kubernetes version currently running on the control plane.
+	// +optional
+	ControlPlaneVersion string `json:"controlPlaneVersion,omitempty"`
+
+	// InfrastructureReady is the state of the infrastructure provider.
+	// +optional
+	InfrastructureReady bool `json:"infrastructureReady"`
+
+	// ControlPlaneReady defines if the control plane is ready.
+	// +optional
+	ControlPlaneReady bool `json:"controlPlaneReady"`
+
+	// ControlPlane summarizes the replicas of the control plane.
+	// +optional
+	ControlPlane *ClusterSummaryReplicas `json:"controlPlane,omitempty"`
+
+	// Workers summarizes the replicas of all the MachineDeployments and MachinePools of the Cluster.
+	// +optional
+	Workers *ClusterSummaryReplicas `json:"workers,omitempty"`
+
+	// Conditions contains the Ready condition of the Cluster, which is the roll-up of all the Cluster conditions.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the latest generation of the Cluster summarized.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ClusterSummaryReplicas summarizes the replicas of a group of machines.
+type ClusterSummaryReplicas struct {
+	// Desired is the desired number of replicas.
+	// +optional
+	Desired int32 `json:"desired"`
+
+	// Replicas is the current number of replicas.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// Ready is the number of ready replicas.
+	// +optional
+	Ready int32 `json:"ready"`
+}
+
+// ANCHOR_END: ClusterSummaryStatus
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clustersummaries,shortName=cs,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Cluster status such as Pending/Provisioning/Provisioned/Deleting/Failed"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="Kubernetes version of the Cluster"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Cluster readiness"
+// +kubebuilder:printcolumn:name="ControlPlane",type="integer",JSONPath=".status.controlPlane.ready",description="Number of ready control plane machines"
+// +kubebuilder:printcolumn:name="Workers",type="integer",JSONPath=".status.workers.ready",description="Number of ready worker machines"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ClusterSummary"
+// +k8s:conversion-gen=false
+
+// ClusterSummary is a lightweight, read-only summary of a Cluster maintained by Cluster API.
+// A ClusterSummary has the same name and namespace of the Cluster it summarizes, and it is owned by it.
+type ClusterSummary struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status ClusterSummaryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterSummaryList contains a list of ClusterSummary.
+type ClusterSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSummary `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterSummary{}, &ClusterSummaryList{})
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ANCHOR: ClusterUpgradeRolloutSpec
+
+// ClusterUpgradeRolloutSpec defines the desired state of a ClusterUpgradeRollout.
+type ClusterUpgradeRolloutSpec struct {
+	// ClusterSelector selects the Clusters with a managed topology to upgrade, in the namespace of the ClusterUpgradeRollout.
+	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
+
+	// Version is the target Kubernetes version, which is set as topology.version of the selected Clusters.
+	// Clusters already running a newer version are not downgraded.
+	// +kubebuilder:validation:MinLength=1
+	Version string `json:"version"`
+
+	// MaxConcurrency is the maximum number of Clusters being upgraded at the same time.
+	// Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
+
+	// MaxFailures is the maximum number of Clusters which can fail the upgrade before the rollout is halted,
+	// i.e. before the upgrade of further Clusters is stopped.
+	// Defaults to 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxFailures *int32 `json:"maxFailures,omitempty"`
+
+	// ProgressDeadline is the maximum time for a Cluster to complete the upgrade and pass the health gates,
+	// after which the upgrade of the Cluster is considered failed.
+	// Defaults to 1h.
+	// +optional
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
+
+	// Paused stops the upgrade of further Clusters; Clusters already being upgraded complete their upgrade.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// ANCHOR_END: ClusterUpgradeRolloutSpec
+
+// ANCHOR: ClusterUpgradeRolloutStatus
+
+// ClusterUpgradeRolloutStatus defines the observed state of a ClusterUpgradeRollout.
+type ClusterUpgradeRolloutStatus struct {
+	// Clusters is the number of Clusters selected by the ClusterUpgradeRollout.
+	// +optional
+	Clusters int32 `json:"clusters"`
+
+	// UpgradedClusters is the number of selected Clusters running the target version and passing the health gates.
+	// +optional
+	UpgradedClusters int32 `json:"upgradedClusters"`
+
+	// Upgrading lists the Clusters being upgraded.
+	// +optional
+	Upgrading []ClusterUpgradeRolloutCluster `json:"upgrading,omitempty"`
+
+	// Failed lists the Clusters which could not be upgraded or did not complete the upgrade within the progress deadline.
+	// +optional
+	Failed []ClusterUpgradeRolloutCluster `json:"failed,omitempty"`
+
+	// Conditions defines current service state of the ClusterUpgradeRollout.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// ClusterUpgradeRolloutCluster documents the upgrade of a Cluster by a ClusterUpgradeRollout.
+type ClusterUpgradeRolloutCluster struct {
+	// Name is the name of the Cluster.
+	Name string `json:"name"`
+
+	// Version is the version the Cluster is upgraded to.
+	Version string `json:"version"`
+
+	// StartTime is the time the upgrade of the Cluster started.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// ANCHOR_END: ClusterUpgradeRolloutStatus
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=clusterupgraderollouts,shortName=cur,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".spec.version",description="Target Kubernetes version"
+// +kubebuilder:printcolumn:name="Clusters",type="integer",JSONPath=".status.clusters",description="Number of selected Clusters"
+// +kubebuilder:printcolumn:name="Upgraded",type="integer",JSONPath=".status.upgradedClusters",description="Number of upgraded Clusters"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="Rollout completion"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ClusterUpgradeRollout"
+// +k8s:conversion-gen=false
+
+// ClusterUpgradeRollout upgrades the Kubernetes version of a set of Clusters with a managed topology,
+// one group of Clusters at a time, waiting for each Cluster to complete the upgrade before moving on.
+type ClusterUpgradeRollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterUpgradeRolloutSpec   `json:"spec,omitempty"`
+	Status ClusterUpgradeRolloutStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the set of conditions for this object.
+func (r *ClusterUpgradeRollout) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (r *ClusterUpgradeRollout) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterUpgradeRolloutList contains a list of ClusterUpgradeRollout.
+type ClusterUpgradeRolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterUpgradeRollout `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterUpgradeRollout{}, &ClusterUpgradeRolloutList{})
+}
@@ -28,3 +28,23 @@ const (
 	// to be ready.
 	WaitingForReplicasReadyReason = "WaitingForReplicasReady"
 )
+
+// Conditions and condition Reasons for the ClusterUpgradeRollout object.
+
+const (
+	// ClusterUpgradeRolloutInProgressReason (Severity=Info) documents a ClusterUpgradeRollout with Clusters
+	// still being upgraded or waiting to be upgraded.
+	ClusterUpgradeRolloutInProgressReason = "RolloutInProgress"
+
+	// ClusterUpgradeRolloutPausedReason (Severity=Info) documents a paused ClusterUpgradeRollout with Clusters
+	// waiting to be upgraded.
+	ClusterUpgradeRolloutPausedReason = "RolloutPaused"
+
+	// ClusterUpgradeRolloutHaltedReason (Severity=Error) documents a ClusterUpgradeRollout which stopped upgrading
+	// Clusters because more Clusters than maxFailures failed the upgrade.
+	ClusterUpgradeRolloutHaltedReason = "RolloutHalted"
+
+	// ClusterUpgradeRolloutClustersFailedReason (Severity=Warning) documents a ClusterUpgradeRollout which
+	// upgraded all the Clusters, except for Clusters which failed the upgrade.
+	ClusterUpgradeRolloutClustersFailedReason = "ClustersFailed"
+)
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -27,6 +28,10 @@ import (
 const (
 	// MachinePoolFinalizer is used to ensure deletion of dependencies (nodes, infra).
 	MachinePoolFinalizer = "machinepool.cluster.x-k8s.io"
+
+	// MachinePoolNameLabel is the label set on the Nodes of a MachinePool; it is used in the label selector
+	// exposed by the scale subresource of the MachinePool.
+	MachinePoolNameLabel = "cluster.x-k8s.io/pool-name"
 )
 
 // ANCHOR: MachinePoolSpec
@@ -60,10 +65,74 @@ type MachinePoolSpec struct {
 	// FailureDomains is the list of failure domains this MachinePool should be attached to.
 	// +optional
 	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// Strategy defines how to replace existing machine instances with new ones, e.g. when the Kubernetes version
+	// or the infrastructure template changes.
+	// If not set, the upgrade behavior is defined by the infrastructure provider.
+	// NOTE: The strategy is implemented by infrastructure providers, which are expected to read it from the MachinePool
+	// owning the infrastructure machine pool.
+	// +optional
+	Strategy *MachinePoolStrategy `json:"strategy,omitempty"`
 }
 
 // ANCHOR_END: MachinePoolSpec
 
+// ANCHOR: MachinePoolStrategy
+
+// MachinePoolStrategyType defines the type of MachinePool rollout strategies.
+type MachinePoolStrategyType string
+
+const (
+	// RollingUpdateMachinePoolStrategyType replaces machine instances one by one, honoring
+	// the maxSurge and maxUnavailable constraints.
+	RollingUpdateMachinePoolStrategyType MachinePoolStrategyType = "RollingUpdate"
+
+	// OnDeleteMachinePoolStrategyType replaces machine instances only when they are deleted.
+	OnDeleteMachinePoolStrategyType MachinePoolStrategyType = "OnDelete"
+)
+
+// MachinePoolStrategy describes how to replace existing machine instances with new ones.
+type MachinePoolStrategy struct {
+	// Type of rollout. Allowed values are RollingUpdate and OnDelete.
+	// Default is RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	// +optional
+	Type MachinePoolStrategyType `json:"type,omitempty"`
+
+	// Rolling update config params. Present only if
+	// MachinePoolStrategyType = RollingUpdate.
+	// +optional
+	RollingUpdate *MachinePoolRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// ANCHOR_END: MachinePoolStrategy
+
+// ANCHOR: MachinePoolRollingUpdate
+
+// MachinePoolRollingUpdate is used to control the desired behavior of rolling update.
+type MachinePoolRollingUpdate struct {
+	// The maximum number of machine instances that can be unavailable during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of desired
+	// machine instances (ex: 10%).
+	// Absolute number is calculated from percentage by rounding down.
+	// This can not be 0 if MaxSurge is 0.
+	// Defaults to 0.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// The maximum number of machine instances that can be scheduled above the
+	// desired number of machine instances.
+	// Value can be an absolute number (ex: 5) or a percentage of
+	// desired machine instances (ex: 10%).
+	// This can not be 0 if MaxUnavailable is 0.
+	// Absolute number is calculated from percentage by rounding up.
+	// Defaults to 1.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
+// ANCHOR_END: MachinePoolRollingUpdate
+
 // ANCHOR: MachinePoolStatus
 
 // MachinePoolStatus defines the observed state of MachinePool.
@@ -76,6 +145,13 @@ type MachinePoolStatus struct {
 	// +optional
 	Replicas int32 `json:"replicas"`
 
+	// Selector is the label selector, in the string format, of the Nodes of the MachinePool in the workload cluster;
+	// it is exposed by the scale subresource, so autoscalers can identify the Nodes they are scaling.
+	// The string will be in the same format as the query-param syntax.
+	// More info about label selectors: http://kubernetes.io/docs/user-guide/labels#label-selectors
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
 	// The number of ready replicas for this MachinePool. A machine is considered ready when the node has been created and is "Ready".
 	// +optional
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
@@ -208,7 +284,7 @@ func (m *MachinePoolStatus) GetTypedPhase() MachinePoolPhase {
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=machinepools,shortName=mp,scope=Namespaced,categories=cluster-api
 // +kubebuilder:subresource:status
-// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster"
 // +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=".spec.replicas",description="Total number of machines desired by this MachinePool",priority=10
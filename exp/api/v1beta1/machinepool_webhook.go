@@ -22,6 +22,7 @@ import (
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -72,6 +73,29 @@ func (m *MachinePool) Default() {
 		normalizedVersion := "v" + *m.Spec.Template.Spec.Version
 		m.Spec.Template.Spec.Version = &normalizedVersion
 	}
+
+	// Default the strategy only if it is set, given that a nil strategy means the upgrade behavior
+	// is defined by the infrastructure provider.
+	if m.Spec.Strategy != nil {
+		if m.Spec.Strategy.Type == "" {
+			m.Spec.Strategy.Type = RollingUpdateMachinePoolStrategyType
+		}
+
+		// Default RollingUpdate strategy only if strategy type is RollingUpdate.
+		if m.Spec.Strategy.Type == RollingUpdateMachinePoolStrategyType {
+			if m.Spec.Strategy.RollingUpdate == nil {
+				m.Spec.Strategy.RollingUpdate = &MachinePoolRollingUpdate{}
+			}
+			if m.Spec.Strategy.RollingUpdate.MaxSurge == nil {
+				ios1 := intstr.FromInt(1)
+				m.Spec.Strategy.RollingUpdate.MaxSurge = &ios1
+			}
+			if m.Spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+				ios0 := intstr.FromInt(0)
+				m.Spec.Strategy.RollingUpdate.MaxUnavailable = &ios0
+			}
+		}
+	}
 }
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
@@ -145,6 +169,10 @@ func (m *MachinePool) validate(old *MachinePool) error {
 		)
 	}
 
+	if m.Spec.Strategy != nil {
+		allErrs = append(allErrs, m.validateStrategy(specPath.Child("strategy"))...)
+	}
+
 	if m.Spec.Template.Spec.Version != nil {
 		if !version.KubeSemver.MatchString(*m.Spec.Template.Spec.Version) {
 			allErrs = append(allErrs, field.Invalid(specPath.Child("template", "spec", "version"), *m.Spec.Template.Spec.Version, "must be a valid semantic version"))
@@ -156,3 +184,63 @@ func (m *MachinePool) validate(old *MachinePool) error {
 	}
 	return apierrors.NewInvalid(GroupVersion.WithKind("MachinePool").GroupKind(), m.Name, allErrs)
 }
+
+func (m *MachinePool) validateStrategy(strategyPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	strategy := m.Spec.Strategy
+
+	if strategy.RollingUpdate == nil {
+		return nil
+	}
+
+	if strategy.Type == OnDeleteMachinePoolStrategyType {
+		return append(allErrs, field.Forbidden(strategyPath.Child("rollingUpdate"), "cannot be set when strategy type is OnDelete"))
+	}
+
+	total := 1
+	if m.Spec.Replicas != nil {
+		total = int(*m.Spec.Replicas)
+	}
+
+	if strategy.RollingUpdate.MaxSurge != nil {
+		v, err := intstr.GetScaledValueFromIntOrPercent(strategy.RollingUpdate.MaxSurge, total, true)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(strategyPath.Child("rollingUpdate", "maxSurge"),
+				strategy.RollingUpdate.MaxSurge, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())))
+		case v < 0:
+			allErrs = append(allErrs, field.Invalid(strategyPath.Child("rollingUpdate", "maxSurge"),
+				strategy.RollingUpdate.MaxSurge, "must be greater than or equal to 0"))
+		}
+	}
+
+	if strategy.RollingUpdate.MaxUnavailable != nil {
+		v, err := intstr.GetScaledValueFromIntOrPercent(strategy.RollingUpdate.MaxUnavailable, total, false)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(strategyPath.Child("rollingUpdate", "maxUnavailable"),
+				strategy.RollingUpdate.MaxUnavailable, fmt.Sprintf("must be either an int or a percentage: %v", err.Error())))
+		case v < 0:
+			allErrs = append(allErrs, field.Invalid(strategyPath.Child("rollingUpdate", "maxUnavailable"),
+				strategy.RollingUpdate.MaxUnavailable, "must be greater than or equal to 0"))
+		}
+	}
+
+	// NOTE: maxSurge defaults to 1 and maxUnavailable defaults to 0.
+	if len(allErrs) == 0 && isZeroIntOrPercent(strategy.RollingUpdate.MaxSurge) &&
+		(strategy.RollingUpdate.MaxUnavailable == nil || isZeroIntOrPercent(strategy.RollingUpdate.MaxUnavailable)) {
+		allErrs = append(allErrs, field.Invalid(strategyPath.Child("rollingUpdate", "maxUnavailable"),
+			strategy.RollingUpdate.MaxUnavailable, "cannot be 0 when maxSurge is 0"))
+	}
+
+	return allErrs
+}
+
+// isZeroIntOrPercent returns true if the value is set and it is equal to 0 or 0%.
+func isZeroIntOrPercent(v *intstr.IntOrString) bool {
+	if v == nil {
+		return false
+	}
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(v, 100, true)
+	return err == nil && scaled == 0
+}
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 
@@ -61,6 +62,74 @@ func TestMachinePoolDefault(t *testing.T) {
 	g.Expect(m.Spec.Template.Spec.Version).To(Equal(pointer.String("v1.20.0")))
 }
 
+func TestMachinePoolStrategyDefault(t *testing.T) {
+	ios0 := intstr.FromInt(0)
+	ios1 := intstr.FromInt(1)
+	ios5 := intstr.FromInt(5)
+
+	tests := []struct {
+		name     string
+		strategy *MachinePoolStrategy
+		expected *MachinePoolStrategy
+	}{
+		{
+			name:     "should not default a nil strategy",
+			strategy: nil,
+			expected: nil,
+		},
+		{
+			name:     "should default an empty strategy to RollingUpdate",
+			strategy: &MachinePoolStrategy{},
+			expected: &MachinePoolStrategy{
+				Type: RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{
+					MaxSurge:       &ios1,
+					MaxUnavailable: &ios0,
+				},
+			},
+		},
+		{
+			name: "should preserve rollingUpdate params",
+			strategy: &MachinePoolStrategy{
+				RollingUpdate: &MachinePoolRollingUpdate{
+					MaxUnavailable: &ios5,
+				},
+			},
+			expected: &MachinePoolStrategy{
+				Type: RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{
+					MaxSurge:       &ios1,
+					MaxUnavailable: &ios5,
+				},
+			},
+		},
+		{
+			name: "should not default rollingUpdate params for OnDelete",
+			strategy: &MachinePoolStrategy{
+				Type: OnDeleteMachinePoolStrategyType,
+			},
+			expected: &MachinePoolStrategy{
+				Type: OnDeleteMachinePoolStrategyType,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &MachinePool{
+				Spec: MachinePoolSpec{
+					Strategy: tt.strategy,
+				},
+			}
+			m.Default()
+
+			g.Expect(m.Spec.Strategy).To(Equal(tt.expected))
+		})
+	}
+}
+
 func TestMachinePoolBootstrapValidation(t *testing.T) {
 	// NOTE: MachinePool feature flag is disabled by default, thus preventing to create or update MachinePool.
 	// Enabling the feature flag temporarily for this test.
@@ -293,3 +362,104 @@ func TestMachinePoolVersionValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestMachinePoolStrategyValidation(t *testing.T) {
+	// NOTE: MachinePool feature flag is disabled by default, thus preventing to create or update MachinePool.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+
+	ios0 := intstr.FromInt(0)
+	ios1 := intstr.FromInt(1)
+	iosNegative := intstr.FromInt(-1)
+	ios0Percent := intstr.FromString("0%")
+	ios10Percent := intstr.FromString("10%")
+	iosInvalid := intstr.FromString("foo")
+
+	tests := []struct {
+		name      string
+		expectErr bool
+		strategy  *MachinePoolStrategy
+	}{
+		{
+			name:      "should succeed if strategy is not set",
+			expectErr: false,
+			strategy:  nil,
+		},
+		{
+			name:      "should succeed with valid rollingUpdate params",
+			expectErr: false,
+			strategy: &MachinePoolStrategy{
+				Type:          RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{MaxSurge: &ios0, MaxUnavailable: &ios10Percent},
+			},
+		},
+		{
+			name:      "should succeed with OnDelete and no rollingUpdate params",
+			expectErr: false,
+			strategy: &MachinePoolStrategy{
+				Type: OnDeleteMachinePoolStrategyType,
+			},
+		},
+		{
+			name:      "should fail with OnDelete and rollingUpdate params",
+			expectErr: true,
+			strategy: &MachinePoolStrategy{
+				Type:          OnDeleteMachinePoolStrategyType,
+				RollingUpdate: &MachinePoolRollingUpdate{MaxSurge: &ios1},
+			},
+		},
+		{
+			name:      "should fail if maxSurge is not an int or a percentage",
+			expectErr: true,
+			strategy: &MachinePoolStrategy{
+				RollingUpdate: &MachinePoolRollingUpdate{MaxSurge: &iosInvalid},
+			},
+		},
+		{
+			name:      "should fail if maxUnavailable is negative",
+			expectErr: true,
+			strategy: &MachinePoolStrategy{
+				RollingUpdate: &MachinePoolRollingUpdate{MaxUnavailable: &iosNegative},
+			},
+		},
+		{
+			name:      "should fail if both maxSurge and maxUnavailable are 0",
+			expectErr: true,
+			strategy: &MachinePoolStrategy{
+				RollingUpdate: &MachinePoolRollingUpdate{MaxSurge: &ios0Percent, MaxUnavailable: &ios0},
+			},
+		},
+		{
+			name:      "should fail if maxSurge is 0 and maxUnavailable is not set",
+			expectErr: true,
+			strategy: &MachinePoolStrategy{
+				RollingUpdate: &MachinePoolRollingUpdate{MaxSurge: &ios0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			m := &MachinePool{
+				Spec: MachinePoolSpec{
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Bootstrap: clusterv1.Bootstrap{ConfigRef: &corev1.ObjectReference{}},
+						},
+					},
+					Strategy: tt.strategy,
+				},
+			}
+
+			if tt.expectErr {
+				g.Expect(m.ValidateCreate()).NotTo(Succeed())
+				g.Expect(m.ValidateUpdate(m)).NotTo(Succeed())
+			} else {
+				g.Expect(m.ValidateCreate()).To(Succeed())
+				g.Expect(m.ValidateUpdate(m)).To(Succeed())
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clustersummaries;clustersummaries/status,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;machinedeployments;machinepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch
+
+// ClusterSummaryReconciler maintains a ClusterSummary for each Cluster, aggregating the status of the Cluster,
+// of its control plane and of its MachineDeployments and MachinePools.
+type ClusterSummaryReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	externalTracker external.ObjectTracker
+}
+
+func (r *ClusterSummaryReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&clusterv1.Cluster{}).
+		Named("clustersummary").
+		Owns(&expv1.ClusterSummary{}).
+		Watches(
+			&source.Kind{Type: &clusterv1.MachineDeployment{}},
+			handler.EnqueueRequestsFromMapFunc(objectToCluster),
+		)
+	if feature.Gates.Enabled(feature.MachinePool) {
+		b = b.Watches(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(objectToCluster),
+		)
+	}
+
+	// NOTE: Paused Clusters are summarized as well, so the filter only checks the watch filter label.
+	c, err := b.
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Build(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	r.externalTracker = external.ObjectTracker{
+		Controller: c,
+	}
+	return nil
+}
+
+func (r *ClusterSummaryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return. The ClusterSummary is garbage collected together with the Cluster.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	var controlPlane *unstructured.Unstructured
+	if cluster.Spec.ControlPlaneRef != nil {
+		obj, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
+			return ctrl.Result{}, err
+		}
+		if err == nil {
+			controlPlane = obj
+			// Ensure we add a watch to the control plane object, so replicas and versions changes are picked up.
+			if err := r.externalTracker.Watch(log, controlPlane, &handler.EnqueueRequestForOwner{OwnerType: &clusterv1.Cluster{}}); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list MachineDeployments for Cluster %s", klog.KObj(cluster))
+	}
+
+	machinePools := &expv1.MachinePoolList{}
+	if feature.Gates.Enabled(feature.MachinePool) {
+		if err := r.Client.List(ctx, machinePools, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to list MachinePools for Cluster %s", klog.KObj(cluster))
+		}
+	}
+
+	status := computeClusterSummaryStatus(cluster, controlPlane, machineDeployments.Items, machinePools.Items)
+	return ctrl.Result{}, r.reconcileClusterSummary(ctx, cluster, status)
+}
+
+// reconcileClusterSummary creates the ClusterSummary for a Cluster if it does not exist, and sets its status.
+func (r *ClusterSummaryReconciler) reconcileClusterSummary(ctx context.Context, cluster *clusterv1.Cluster, status expv1.ClusterSummaryStatus) error {
+	summary := &expv1.ClusterSummary{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cluster), summary); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get ClusterSummary %s", klog.KObj(cluster))
+		}
+		summary = &expv1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: cluster.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(cluster, clusterv1.GroupVersion.WithKind("Cluster")),
+				},
+			},
+		}
+		if err := r.Client.Create(ctx, summary); err != nil {
+			return errors.Wrapf(err, "failed to create ClusterSummary %s", klog.KObj(summary))
+		}
+	}
+
+	patchHelper, err := patch.NewHelper(summary, r.Client)
+	if err != nil {
+		return err
+	}
+	summary.Status = status
+	if err := patchHelper.Patch(ctx, summary); err != nil {
+		return errors.Wrapf(err, "failed to patch ClusterSummary %s", klog.KObj(summary))
+	}
+	return nil
+}
+
+// computeClusterSummaryStatus aggregates the status of a Cluster, of its control plane and of its
+// MachineDeployments and MachinePools into a ClusterSummaryStatus.
+func computeClusterSummaryStatus(cluster *clusterv1.Cluster, controlPlane *unstructured.Unstructured, machineDeployments []clusterv1.MachineDeployment, machinePools []expv1.MachinePool) expv1.ClusterSummaryStatus {
+	status := expv1.ClusterSummaryStatus{
+		Phase:               cluster.Status.Phase,
+		InfrastructureReady: cluster.Status.InfrastructureReady,
+		ControlPlaneReady:   cluster.Status.ControlPlaneReady,
+		ObservedGeneration:  cluster.Generation,
+	}
+	if cluster.Spec.Topology != nil {
+		status.Version = cluster.Spec.Topology.Version
+	}
+	if c := conditions.Get(cluster, clusterv1.ReadyCondition); c != nil {
+		status.Conditions = clusterv1.Conditions{*c}
+	}
+
+	// NOTE: Version and replicas are optional in the control plane contract, so missing fields are ignored.
+	if controlPlane != nil {
+		if version, err := contract.ControlPlane().Version().Get(controlPlane); err == nil && status.Version == "" {
+			status.Version = *version
+		}
+		if version, err := contract.ControlPlane().StatusVersion().Get(controlPlane); err == nil {
+			status.ControlPlaneVersion = *version
+		}
+		if desired, err := contract.ControlPlane().Replicas().Get(controlPlane); err == nil {
+			status.ControlPlane = &expv1.ClusterSummaryReplicas{Desired: int32(*desired)}
+			if replicas, err := contract.ControlPlane().StatusReplicas().Get(controlPlane); err == nil {
+				status.ControlPlane.Replicas = int32(*replicas)
+			}
+			if ready, err := contract.ControlPlane().ReadyReplicas().Get(controlPlane); err == nil {
+				status.ControlPlane.Ready = int32(*ready)
+			}
+		}
+	}
+
+	if len(machineDeployments) > 0 || len(machinePools) > 0 {
+		status.Workers = &expv1.ClusterSummaryReplicas{}
+		for _, md := range machineDeployments {
+			if md.Spec.Replicas != nil {
+				status.Workers.Desired += *md.Spec.Replicas
+			}
+			status.Workers.Replicas += md.Status.Replicas
+			status.Workers.Ready += md.Status.ReadyReplicas
+		}
+		for _, mp := range machinePools {
+			if mp.Spec.Replicas != nil {
+				status.Workers.Desired += *mp.Spec.Replicas
+			}
+			status.Workers.Replicas += mp.Status.Replicas
+			status.Workers.Ready += mp.Status.ReadyReplicas
+		}
+	}
+
+	return status
+}
+
+// objectToCluster maps an object with the cluster-name label to a reconcile request for its Cluster.
+func objectToCluster(o client.Object) []ctrl.Request {
+	clusterName, ok := o.GetLabels()[clusterv1.ClusterLabelName]
+	if !ok || clusterName == "" {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Namespace: o.GetNamespace(), Name: clusterName}}}
+}
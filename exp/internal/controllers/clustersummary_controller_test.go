@@ -0,0 +1,229 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func TestComputeClusterSummaryStatus(t *testing.T) {
+	readyCondition := clusterv1.Condition{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue}
+	controlPlane := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"version":  "v1.24.0",
+			"replicas": int64(3),
+		},
+		"status": map[string]interface{}{
+			"version":       "v1.23.0",
+			"replicas":      int64(3),
+			"readyReplicas": int64(2),
+		},
+	}}
+
+	tests := []struct {
+		name               string
+		cluster            *clusterv1.Cluster
+		controlPlane       *unstructured.Unstructured
+		machineDeployments []clusterv1.MachineDeployment
+		machinePools       []expv1.MachinePool
+		want               expv1.ClusterSummaryStatus
+	}{
+		{
+			name: "Cluster without control plane and workers",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: clusterv1.ClusterStatus{
+					Phase:               string(clusterv1.ClusterPhaseProvisioning),
+					InfrastructureReady: true,
+					Conditions: clusterv1.Conditions{
+						readyCondition,
+						{Type: clusterv1.InfrastructureReadyCondition, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			want: expv1.ClusterSummaryStatus{
+				Phase:               string(clusterv1.ClusterPhaseProvisioning),
+				InfrastructureReady: true,
+				Conditions:          clusterv1.Conditions{readyCondition},
+				ObservedGeneration:  2,
+			},
+		},
+		{
+			name: "Cluster with control plane, MachineDeployments and MachinePools",
+			cluster: &clusterv1.Cluster{
+				Status: clusterv1.ClusterStatus{
+					Phase:             string(clusterv1.ClusterPhaseProvisioned),
+					ControlPlaneReady: true,
+				},
+			},
+			controlPlane: controlPlane,
+			machineDeployments: []clusterv1.MachineDeployment{
+				{
+					Spec:   clusterv1.MachineDeploymentSpec{Replicas: pointer.Int32(3)},
+					Status: clusterv1.MachineDeploymentStatus{Replicas: 3, ReadyReplicas: 3},
+				},
+				{
+					Spec:   clusterv1.MachineDeploymentSpec{Replicas: pointer.Int32(2)},
+					Status: clusterv1.MachineDeploymentStatus{Replicas: 1, ReadyReplicas: 0},
+				},
+			},
+			machinePools: []expv1.MachinePool{
+				{
+					Spec:   expv1.MachinePoolSpec{Replicas: pointer.Int32(4)},
+					Status: expv1.MachinePoolStatus{Replicas: 4, ReadyReplicas: 2},
+				},
+			},
+			want: expv1.ClusterSummaryStatus{
+				Phase:               string(clusterv1.ClusterPhaseProvisioned),
+				ControlPlaneReady:   true,
+				Version:             "v1.24.0",
+				ControlPlaneVersion: "v1.23.0",
+				ControlPlane:        &expv1.ClusterSummaryReplicas{Desired: 3, Replicas: 3, Ready: 2},
+				Workers:             &expv1.ClusterSummaryReplicas{Desired: 9, Replicas: 8, Ready: 5},
+			},
+		},
+		{
+			name: "Cluster topology version takes precedence over the control plane version",
+			cluster: &clusterv1.Cluster{
+				Spec: clusterv1.ClusterSpec{
+					Topology: &clusterv1.Topology{Version: "v1.25.0"},
+				},
+			},
+			controlPlane: controlPlane,
+			want: expv1.ClusterSummaryStatus{
+				Version:             "v1.25.0",
+				ControlPlaneVersion: "v1.23.0",
+				ControlPlane:        &expv1.ClusterSummaryReplicas{Desired: 3, Replicas: 3, Ready: 2},
+			},
+		},
+		{
+			name:    "Control plane without replicas",
+			cluster: &clusterv1.Cluster{},
+			controlPlane: &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"version": "v1.24.0",
+				},
+			}},
+			want: expv1.ClusterSummaryStatus{
+				Version: "v1.24.0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := computeClusterSummaryStatus(tt.cluster, tt.controlPlane, tt.machineDeployments, tt.machinePools)
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestClusterSummaryReconcile(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "cluster1",
+			UID:       "uid",
+		},
+		Status: clusterv1.ClusterStatus{
+			Phase: string(clusterv1.ClusterPhaseProvisioned),
+		},
+	}
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "md1",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+		},
+		Spec:   clusterv1.MachineDeploymentSpec{Replicas: pointer.Int32(3)},
+		Status: clusterv1.MachineDeploymentStatus{Replicas: 3, ReadyReplicas: 1},
+	}
+	otherMD := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "md2",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "other-cluster"},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{Replicas: pointer.Int32(5)},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, md, otherMD).Build()
+	r := &ClusterSummaryReconciler{Client: c}
+
+	// The ClusterSummary is created on the first reconcile.
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	summary := &expv1.ClusterSummary{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), summary)).To(Succeed())
+	g.Expect(summary.Labels).To(HaveKeyWithValue(clusterv1.ClusterLabelName, cluster.Name))
+	g.Expect(summary.OwnerReferences).To(HaveLen(1))
+	g.Expect(summary.OwnerReferences[0].Name).To(Equal(cluster.Name))
+	g.Expect(summary.Status.Phase).To(Equal(string(clusterv1.ClusterPhaseProvisioned)))
+	g.Expect(summary.Status.Workers).To(Equal(&expv1.ClusterSummaryReplicas{Desired: 3, Replicas: 3, Ready: 1}))
+
+	// The ClusterSummary is updated when the status of the MachineDeployments change.
+	md.Status.ReadyReplicas = 3
+	g.Expect(c.Status().Update(ctx, md)).To(Succeed())
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(cluster), summary)).To(Succeed())
+	g.Expect(summary.Status.Workers).To(Equal(&expv1.ClusterSummaryReplicas{Desired: 3, Replicas: 3, Ready: 3}))
+}
+
+func TestObjectToCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "md1",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster1"},
+		},
+	}
+	g.Expect(objectToCluster(md)).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster1"}}))
+
+	md.Labels = nil
+	g.Expect(objectToCluster(md)).To(BeEmpty())
+}
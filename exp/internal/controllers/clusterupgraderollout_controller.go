@@ -0,0 +1,412 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusterupgraderollouts;clusterupgraderollouts/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch
+
+const (
+	// defaultClusterUpgradeRolloutProgressDeadline is the default time for a Cluster to complete the upgrade.
+	defaultClusterUpgradeRolloutProgressDeadline = time.Hour
+
+	// clusterUpgradeRolloutRequeueAfter is the interval at which ClusterUpgradeRollouts with Clusters being upgraded
+	// are reconciled, so that Clusters exceeding the progress deadline are detected.
+	clusterUpgradeRolloutRequeueAfter = time.Minute
+)
+
+// ClusterUpgradeRolloutReconciler upgrades the Clusters selected by ClusterUpgradeRollouts.
+type ClusterUpgradeRolloutReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *ClusterUpgradeRolloutReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&expv1.ClusterUpgradeRollout{}).
+		Watches(
+			&source.Kind{Type: &clusterv1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterToClusterUpgradeRollouts),
+		).
+		Watches(
+			&source.Kind{Type: &clusterv1.MachineDeployment{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterObjectToClusterUpgradeRollouts),
+		)
+	if feature.Gates.Enabled(feature.MachinePool) {
+		b = b.Watches(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterObjectToClusterUpgradeRollouts),
+		)
+	}
+
+	err := b.
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *ClusterUpgradeRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	rollout := &expv1.ClusterUpgradeRollout{}
+	if err := r.Client.Get(ctx, req.NamespacedName, rollout); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Object not found, return. Clusters being upgraded complete their upgrade.
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !rollout.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(rollout, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer func() {
+		// Always attempt to patch the status after each reconciliation.
+		// Patch ObservedGeneration only if the reconciliation completed successfully.
+		patchOpts := []patch.Option{
+			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{clusterv1.ReadyCondition}},
+		}
+		if reterr == nil {
+			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
+		}
+		if err := patchHelper.Patch(ctx, rollout, patchOpts...); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	return r.reconcile(ctx, rollout)
+}
+
+func (r *ClusterUpgradeRolloutReconciler) reconcile(ctx context.Context, rollout *expv1.ClusterUpgradeRollout) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	selector, err := metav1.LabelSelectorAsSelector(&rollout.Spec.ClusterSelector)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to parse clusterSelector of ClusterUpgradeRollout %s", klog.KObj(rollout))
+	}
+	clusters := &clusterv1.ClusterList{}
+	if err := r.Client.List(ctx, clusters, client.InNamespace(rollout.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list Clusters for ClusterUpgradeRollout %s", klog.KObj(rollout))
+	}
+	// Upgrade Clusters in a predictable order.
+	sort.Slice(clusters.Items, func(i, j int) bool {
+		return clusters.Items[i].Name < clusters.Items[j].Name
+	})
+
+	version := rollout.Spec.Version
+	now := metav1.Now()
+	progressDeadline := defaultClusterUpgradeRolloutProgressDeadline
+	if rollout.Spec.ProgressDeadline != nil {
+		progressDeadline = rollout.Spec.ProgressDeadline.Duration
+	}
+
+	// Clusters tracked for a different version, e.g. before the target version of the ClusterUpgradeRollout was changed, are ignored.
+	upgrading := clusterUpgradeRolloutClustersForVersion(rollout.Status.Upgrading, version)
+	failed := clusterUpgradeRolloutClustersForVersion(rollout.Status.Failed, version)
+
+	status := expv1.ClusterUpgradeRolloutStatus{
+		Conditions: rollout.Status.Conditions,
+	}
+	pending := []*clusterv1.Cluster{}
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if cluster.Spec.Topology == nil {
+			continue
+		}
+		status.Clusters++
+
+		if cluster.Spec.Topology.Version != version {
+			// Clusters already running a newer version are not downgraded.
+			if isNewerVersion(cluster.Spec.Topology.Version, version) {
+				status.UpgradedClusters++
+				continue
+			}
+			// Clusters which could not be upgraded are not retried.
+			if c, ok := failed[cluster.Name]; ok {
+				status.Failed = append(status.Failed, c)
+				continue
+			}
+			pending = append(pending, cluster)
+			continue
+		}
+
+		upgraded, err := r.clusterPassesHealthGates(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if upgraded {
+			status.UpgradedClusters++
+			continue
+		}
+
+		if c, ok := failed[cluster.Name]; ok {
+			status.Failed = append(status.Failed, c)
+			continue
+		}
+
+		// NOTE: Clusters with the target version, but not tracked as being upgraded, e.g. because their upgrade was
+		// started outside of the ClusterUpgradeRollout, are tracked from now on.
+		c, ok := upgrading[cluster.Name]
+		if !ok {
+			c = expv1.ClusterUpgradeRolloutCluster{Name: cluster.Name, Version: version, StartTime: now}
+		}
+		if now.Sub(c.StartTime.Time) > progressDeadline {
+			log.Info("Cluster did not complete the upgrade within the progress deadline", "Cluster", klog.KObj(cluster), "version", version)
+			status.Failed = append(status.Failed, c)
+			continue
+		}
+		status.Upgrading = append(status.Upgrading, c)
+	}
+
+	maxFailures := pointer.Int32Deref(rollout.Spec.MaxFailures, 0)
+	halted := int32(len(status.Failed)) > maxFailures
+	if !halted && !rollout.Spec.Paused {
+		maxConcurrency := int(pointer.Int32Deref(rollout.Spec.MaxConcurrency, 1))
+		for _, cluster := range pending {
+			if len(status.Upgrading) >= maxConcurrency || halted {
+				break
+			}
+			c := expv1.ClusterUpgradeRolloutCluster{Name: cluster.Name, Version: version, StartTime: now}
+			// NOTE: A Cluster which cannot be upgraded is tracked as failed, so the other Clusters are still upgraded
+			// as long as the failures do not exceed maxFailures.
+			if err := r.upgradeCluster(ctx, cluster, version); err != nil {
+				log.Error(err, "Failed to upgrade Cluster", "Cluster", klog.KObj(cluster), "version", version)
+				status.Failed = append(status.Failed, c)
+				halted = int32(len(status.Failed)) > maxFailures
+				continue
+			}
+			status.Upgrading = append(status.Upgrading, c)
+		}
+	}
+
+	rollout.Status = status
+	switch {
+	case halted:
+		conditions.MarkFalse(rollout, clusterv1.ReadyCondition, expv1.ClusterUpgradeRolloutHaltedReason, clusterv1.ConditionSeverityError,
+			"%d Clusters failed the upgrade to %s, more than maxFailures", len(status.Failed), version)
+	case len(pending) > 0 && rollout.Spec.Paused && len(status.Upgrading) == 0:
+		conditions.MarkFalse(rollout, clusterv1.ReadyCondition, expv1.ClusterUpgradeRolloutPausedReason, clusterv1.ConditionSeverityInfo,
+			"%d Clusters waiting to be upgraded to %s", len(pending), version)
+	case len(pending) > 0 || len(status.Upgrading) > 0:
+		conditions.MarkFalse(rollout, clusterv1.ReadyCondition, expv1.ClusterUpgradeRolloutInProgressReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d Clusters upgraded to %s", status.UpgradedClusters, status.Clusters, version)
+	case len(status.Failed) > 0:
+		conditions.MarkFalse(rollout, clusterv1.ReadyCondition, expv1.ClusterUpgradeRolloutClustersFailedReason, clusterv1.ConditionSeverityWarning,
+			"%d Clusters failed the upgrade to %s", len(status.Failed), version)
+	default:
+		conditions.MarkTrue(rollout, clusterv1.ReadyCondition)
+	}
+
+	if len(status.Upgrading) > 0 {
+		return ctrl.Result{RequeueAfter: clusterUpgradeRolloutRequeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// upgradeCluster sets the version of the topology of a Cluster.
+func (r *ClusterUpgradeRolloutReconciler) upgradeCluster(ctx context.Context, cluster *clusterv1.Cluster, version string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	patchHelper, err := patch.NewHelper(cluster, r.Client)
+	if err != nil {
+		return err
+	}
+	log.Info("Upgrading Cluster", "Cluster", klog.KObj(cluster), "from", cluster.Spec.Topology.Version, "to", version)
+	cluster.Spec.Topology.Version = version
+	if err := patchHelper.Patch(ctx, cluster); err != nil {
+		return errors.Wrapf(err, "failed to upgrade Cluster %s to %s", klog.KObj(cluster), version)
+	}
+	return nil
+}
+
+// clusterPassesHealthGates returns true if a Cluster completed the upgrade to the version of its topology, i.e. if the
+// topology controller observed the current generation of the Cluster and reconciled it, the Cluster is ready, and
+// the control plane, all the MachineDeployments and all the MachinePools rolled out the version.
+func (r *ClusterUpgradeRolloutReconciler) clusterPassesHealthGates(ctx context.Context, cluster *clusterv1.Cluster) (bool, error) {
+	if cluster.Status.TopologyObservedGeneration < cluster.Generation {
+		return false, nil
+	}
+	if !conditions.IsTrue(cluster, clusterv1.TopologyReconciledCondition) || !conditions.IsTrue(cluster, clusterv1.ReadyCondition) {
+		return false, nil
+	}
+	version := cluster.Spec.Topology.Version
+
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get control plane for Cluster %s", klog.KObj(cluster))
+		}
+		specVersion, err := contract.ControlPlane().Version().Get(controlPlane)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get the version of the control plane of Cluster %s", klog.KObj(cluster))
+		}
+		if *specVersion != version {
+			return false, nil
+		}
+		provisioning, err := contract.ControlPlane().IsProvisioning(controlPlane)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if the control plane of Cluster %s is provisioning", klog.KObj(cluster))
+		}
+		upgrading, err := contract.ControlPlane().IsUpgrading(controlPlane)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if the control plane of Cluster %s is upgrading", klog.KObj(cluster))
+		}
+		scaling, err := contract.ControlPlane().IsScaling(controlPlane)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if the control plane of Cluster %s is scaling", klog.KObj(cluster))
+		}
+		if provisioning || upgrading || scaling {
+			return false, nil
+		}
+	}
+
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return false, errors.Wrapf(err, "failed to list MachineDeployments for Cluster %s", klog.KObj(cluster))
+	}
+	for _, md := range machineDeployments.Items {
+		if md.Spec.Template.Spec.Version == nil || *md.Spec.Template.Spec.Version != version {
+			return false, nil
+		}
+		if md.Status.ObservedGeneration < md.Generation {
+			return false, nil
+		}
+		replicas := pointer.Int32Deref(md.Spec.Replicas, 1)
+		if md.Status.Replicas != replicas || md.Status.UpdatedReplicas != replicas || md.Status.AvailableReplicas != replicas {
+			return false, nil
+		}
+	}
+
+	if !feature.Gates.Enabled(feature.MachinePool) {
+		return true, nil
+	}
+	machinePools := &expv1.MachinePoolList{}
+	if err := r.Client.List(ctx, machinePools, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return false, errors.Wrapf(err, "failed to list MachinePools for Cluster %s", klog.KObj(cluster))
+	}
+	for _, mp := range machinePools.Items {
+		if mp.Spec.Template.Spec.Version == nil || *mp.Spec.Template.Spec.Version != version {
+			return false, nil
+		}
+		if mp.Status.ObservedGeneration < mp.Generation {
+			return false, nil
+		}
+		replicas := pointer.Int32Deref(mp.Spec.Replicas, 1)
+		if mp.Status.Replicas != replicas || mp.Status.ReadyReplicas != replicas || mp.Status.AvailableReplicas != replicas {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// clusterUpgradeRolloutClustersForVersion returns the Clusters with the given version, indexed by name.
+func clusterUpgradeRolloutClustersForVersion(clusters []expv1.ClusterUpgradeRolloutCluster, version string) map[string]expv1.ClusterUpgradeRolloutCluster {
+	res := map[string]expv1.ClusterUpgradeRolloutCluster{}
+	for _, c := range clusters {
+		if c.Version == version {
+			res[c.Name] = c
+		}
+	}
+	return res
+}
+
+// isNewerVersion returns true if the current version is newer than the target version.
+func isNewerVersion(current, target string) bool {
+	currentVersion, err := semver.ParseTolerant(current)
+	if err != nil {
+		return false
+	}
+	targetVersion, err := semver.ParseTolerant(target)
+	if err != nil {
+		return false
+	}
+	return currentVersion.GT(targetVersion)
+}
+
+// clusterToClusterUpgradeRollouts maps a Cluster to the ClusterUpgradeRollouts selecting it.
+func (r *ClusterUpgradeRolloutReconciler) clusterToClusterUpgradeRollouts(o client.Object) []ctrl.Request {
+	rollouts := &expv1.ClusterUpgradeRolloutList{}
+	if err := r.Client.List(context.TODO(), rollouts, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	result := []ctrl.Request{}
+	for _, rollout := range rollouts.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&rollout.Spec.ClusterSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(o.GetLabels())) {
+			result = append(result, ctrl.Request{NamespacedName: client.ObjectKey{Namespace: rollout.Namespace, Name: rollout.Name}})
+		}
+	}
+	return result
+}
+
+// clusterObjectToClusterUpgradeRollouts maps an object of a Cluster, e.g. a MachineDeployment or a MachinePool, to the
+// ClusterUpgradeRollouts selecting the Cluster.
+func (r *ClusterUpgradeRolloutReconciler) clusterObjectToClusterUpgradeRollouts(o client.Object) []ctrl.Request {
+	clusterName, ok := o.GetLabels()[clusterv1.ClusterLabelName]
+	if !ok || clusterName == "" {
+		return nil
+	}
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(context.TODO(), client.ObjectKey{Namespace: o.GetNamespace(), Name: clusterName}, cluster); err != nil {
+		return nil
+	}
+	return r.clusterToClusterUpgradeRollouts(cluster)
+}
@@ -0,0 +1,449 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestClusterUpgradeRolloutReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+	_ = expv1.AddToScheme(scheme)
+
+	newCluster := func(name, version string, healthy bool) *clusterv1.Cluster {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{"env": "prod"},
+			},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{Class: "class", Version: version},
+			},
+		}
+		if healthy {
+			cluster.Status.Conditions = clusterv1.Conditions{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue},
+				{Type: clusterv1.TopologyReconciledCondition, Status: corev1.ConditionTrue},
+			}
+		}
+		return cluster
+	}
+	newRollout := func() *expv1.ClusterUpgradeRollout {
+		return &expv1.ClusterUpgradeRollout{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "rollout"},
+			Spec: expv1.ClusterUpgradeRolloutSpec{
+				ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				Version:         "v1.24.0",
+				MaxConcurrency:  pointer.Int32(2),
+				MaxFailures:     pointer.Int32(0),
+			},
+		}
+	}
+	expired := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+
+	tests := []struct {
+		name          string
+		clusters      []client.Object
+		rollout       func(*expv1.ClusterUpgradeRollout)
+		failUpgrade   []string
+		wantUpgraded  []string
+		wantUpgrading []string
+		wantFailed    []string
+		wantStatus    expv1.ClusterUpgradeRolloutStatus
+		wantReason    string
+		wantRequeue   bool
+	}{
+		{
+			name: "Upgrades at most maxConcurrency Clusters at a time",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.23.0", true),
+				newCluster("cluster2", "v1.23.0", true),
+				newCluster("cluster3", "v1.23.0", true),
+			},
+			wantUpgraded:  []string{"cluster1", "cluster2"},
+			wantUpgrading: []string{"cluster1", "cluster2"},
+			wantStatus:    expv1.ClusterUpgradeRolloutStatus{Clusters: 3},
+			wantReason:    expv1.ClusterUpgradeRolloutInProgressReason,
+			wantRequeue:   true,
+		},
+		{
+			name: "Counts Clusters passing the health gates as upgraded and upgrades the next ones",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.24.0", true),
+				newCluster("cluster2", "v1.24.0", false),
+				newCluster("cluster3", "v1.23.0", true),
+				newCluster("cluster4", "v1.23.0", true),
+			},
+			rollout: func(r *expv1.ClusterUpgradeRollout) {
+				r.Status.Upgrading = []expv1.ClusterUpgradeRolloutCluster{
+					{Name: "cluster1", Version: "v1.24.0", StartTime: metav1.Now()},
+					{Name: "cluster2", Version: "v1.24.0", StartTime: metav1.Now()},
+				}
+			},
+			wantUpgraded:  []string{"cluster3"},
+			wantUpgrading: []string{"cluster2", "cluster3"},
+			wantStatus:    expv1.ClusterUpgradeRolloutStatus{Clusters: 4, UpgradedClusters: 1},
+			wantReason:    expv1.ClusterUpgradeRolloutInProgressReason,
+			wantRequeue:   true,
+		},
+		{
+			name: "Does not downgrade Clusters and ignores Clusters without a managed topology",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.25.0", false),
+				func() client.Object {
+					c := newCluster("cluster2", "", false)
+					c.Spec.Topology = nil
+					return c
+				}(),
+			},
+			wantStatus: expv1.ClusterUpgradeRolloutStatus{Clusters: 1, UpgradedClusters: 1},
+		},
+		{
+			name: "Halts the rollout when more than maxFailures Clusters exceed the progress deadline",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.24.0", false),
+				newCluster("cluster2", "v1.23.0", true),
+			},
+			rollout: func(r *expv1.ClusterUpgradeRollout) {
+				r.Status.Upgrading = []expv1.ClusterUpgradeRolloutCluster{
+					{Name: "cluster1", Version: "v1.24.0", StartTime: expired},
+				}
+			},
+			wantFailed: []string{"cluster1"},
+			wantStatus: expv1.ClusterUpgradeRolloutStatus{Clusters: 2},
+			wantReason: expv1.ClusterUpgradeRolloutHaltedReason,
+		},
+		{
+			name: "Continues the rollout when failed Clusters do not exceed maxFailures",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.24.0", false),
+				newCluster("cluster2", "v1.23.0", true),
+			},
+			rollout: func(r *expv1.ClusterUpgradeRollout) {
+				r.Spec.MaxFailures = pointer.Int32(1)
+				r.Status.Failed = []expv1.ClusterUpgradeRolloutCluster{
+					{Name: "cluster1", Version: "v1.24.0", StartTime: expired},
+				}
+			},
+			wantUpgraded:  []string{"cluster2"},
+			wantUpgrading: []string{"cluster2"},
+			wantFailed:    []string{"cluster1"},
+			wantStatus:    expv1.ClusterUpgradeRolloutStatus{Clusters: 2},
+			wantReason:    expv1.ClusterUpgradeRolloutInProgressReason,
+			wantRequeue:   true,
+		},
+		{
+			name: "Tracks Clusters which cannot be upgraded as failed and upgrades the other Clusters",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.23.0", true),
+				newCluster("cluster2", "v1.23.0", true),
+				newCluster("cluster3", "v1.23.0", true),
+			},
+			rollout: func(r *expv1.ClusterUpgradeRollout) {
+				r.Spec.MaxFailures = pointer.Int32(1)
+			},
+			failUpgrade:   []string{"cluster1"},
+			wantUpgraded:  []string{"cluster2", "cluster3"},
+			wantUpgrading: []string{"cluster2", "cluster3"},
+			wantFailed:    []string{"cluster1"},
+			wantStatus:    expv1.ClusterUpgradeRolloutStatus{Clusters: 3},
+			wantReason:    expv1.ClusterUpgradeRolloutInProgressReason,
+			wantRequeue:   true,
+		},
+		{
+			name: "Halts the rollout when more than maxFailures Clusters cannot be upgraded",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.23.0", true),
+				newCluster("cluster2", "v1.23.0", true),
+			},
+			failUpgrade: []string{"cluster1"},
+			wantFailed:  []string{"cluster1"},
+			wantStatus:  expv1.ClusterUpgradeRolloutStatus{Clusters: 2},
+			wantReason:  expv1.ClusterUpgradeRolloutHaltedReason,
+		},
+		{
+			name: "Does not retry Clusters which could not be upgraded",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.23.0", true),
+				newCluster("cluster2", "v1.23.0", true),
+			},
+			rollout: func(r *expv1.ClusterUpgradeRollout) {
+				r.Spec.MaxFailures = pointer.Int32(1)
+				r.Status.Failed = []expv1.ClusterUpgradeRolloutCluster{
+					{Name: "cluster1", Version: "v1.24.0", StartTime: metav1.Now()},
+				}
+			},
+			wantUpgraded:  []string{"cluster2"},
+			wantUpgrading: []string{"cluster2"},
+			wantFailed:    []string{"cluster1"},
+			wantStatus:    expv1.ClusterUpgradeRolloutStatus{Clusters: 2},
+			wantReason:    expv1.ClusterUpgradeRolloutInProgressReason,
+			wantRequeue:   true,
+		},
+		{
+			name: "Does not upgrade Clusters when paused",
+			clusters: []client.Object{
+				newCluster("cluster1", "v1.23.0", true),
+			},
+			rollout: func(r *expv1.ClusterUpgradeRollout) {
+				r.Spec.Paused = true
+			},
+			wantStatus: expv1.ClusterUpgradeRolloutStatus{Clusters: 1},
+			wantReason: expv1.ClusterUpgradeRolloutPausedReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			rollout := newRollout()
+			if tt.rollout != nil {
+				tt.rollout(rollout)
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.clusters...).Build()
+			r := &ClusterUpgradeRolloutReconciler{Client: &failingPatchClient{Client: c, names: sets.NewString(tt.failUpgrade...)}}
+
+			res, err := r.reconcile(ctx, rollout)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res.RequeueAfter > 0).To(Equal(tt.wantRequeue))
+
+			g.Expect(rollout.Status.Clusters).To(Equal(tt.wantStatus.Clusters))
+			g.Expect(rollout.Status.UpgradedClusters).To(Equal(tt.wantStatus.UpgradedClusters))
+			g.Expect(clusterUpgradeRolloutClusterNames(rollout.Status.Upgrading)).To(ConsistOf(tt.wantUpgrading))
+			g.Expect(clusterUpgradeRolloutClusterNames(rollout.Status.Failed)).To(ConsistOf(tt.wantFailed))
+
+			if tt.wantReason == "" {
+				g.Expect(conditions.IsTrue(rollout, clusterv1.ReadyCondition)).To(BeTrue())
+			} else {
+				g.Expect(conditions.GetReason(rollout, clusterv1.ReadyCondition)).To(Equal(tt.wantReason))
+			}
+
+			for _, name := range tt.wantUpgraded {
+				cluster := &clusterv1.Cluster{}
+				g.Expect(c.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: name}, cluster)).To(Succeed())
+				g.Expect(cluster.Spec.Topology.Version).To(Equal("v1.24.0"))
+			}
+		})
+	}
+}
+
+// failingPatchClient is a client failing to patch the objects with the given names.
+type failingPatchClient struct {
+	client.Client
+	names sets.String
+}
+
+func (c *failingPatchClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.names.Has(obj.GetName()) {
+		return errors.Errorf("failed to patch %s", obj.GetName())
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func clusterUpgradeRolloutClusterNames(clusters []expv1.ClusterUpgradeRolloutCluster) []string {
+	names := []string{}
+	for _, c := range clusters {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestClusterUpgradeRolloutHealthGates(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme)).To(Succeed())
+
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp1").
+		WithVersion("v1.24.0").
+		WithReplicas(3).
+		WithStatusFields(map[string]interface{}{
+			"status.version":         "v1.23.0",
+			"status.replicas":        int64(3),
+			"status.updatedReplicas": int64(3),
+			"status.readyReplicas":   int64(3),
+		}).
+		Build()
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1", Generation: 2},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: contract.ObjToRef(controlPlane),
+			Topology:        &clusterv1.Topology{Class: "class", Version: "v1.24.0"},
+		},
+		Status: clusterv1.ClusterStatus{
+			ObservedGeneration:         2,
+			TopologyObservedGeneration: 1,
+			Conditions: clusterv1.Conditions{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue},
+				{Type: clusterv1.TopologyReconciledCondition, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "md1",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster1"},
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			Replicas: pointer.Int32(2),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{Version: pointer.String("v1.24.0")},
+			},
+		},
+		Status: clusterv1.MachineDeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+	}
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "mp1",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster1"},
+		},
+		Spec: expv1.MachinePoolSpec{
+			Replicas: pointer.Int32(2),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{Version: pointer.String("v1.23.0")},
+			},
+		},
+		Status: expv1.MachinePoolStatus{Replicas: 2, ReadyReplicas: 2, AvailableReplicas: 2},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(controlPlane, md, mp).Build()
+	r := &ClusterUpgradeRolloutReconciler{Client: c}
+
+	passes := func() bool {
+		ok, err := r.clusterPassesHealthGates(ctx, cluster)
+		g.Expect(err).ToNot(HaveOccurred())
+		return ok
+	}
+
+	// The topology controller did not observe the current generation of the Cluster yet, i.e. the Ready and
+	// TopologyReconciled conditions are stale.
+	g.Expect(passes()).To(BeFalse())
+	cluster.Status.TopologyObservedGeneration = 2
+
+	// The control plane did not complete the upgrade yet.
+	g.Expect(passes()).To(BeFalse())
+	g.Expect(unstructured.SetNestedField(controlPlane.Object, "v1.24.0", "status", "version")).To(Succeed())
+	g.Expect(c.Update(ctx, controlPlane)).To(Succeed())
+
+	// The MachineDeployment did not complete the rollout yet.
+	g.Expect(passes()).To(BeFalse())
+	md.Status.AvailableReplicas = 2
+	g.Expect(c.Update(ctx, md)).To(Succeed())
+
+	// The MachinePool did not pick up the version yet.
+	g.Expect(passes()).To(BeFalse())
+	mp.Spec.Template.Spec.Version = pointer.String("v1.24.0")
+	g.Expect(c.Update(ctx, mp)).To(Succeed())
+	g.Expect(passes()).To(BeTrue())
+
+	// The MachinePool did not complete the rollout yet.
+	mp.Status.ReadyReplicas = 1
+	g.Expect(c.Update(ctx, mp)).To(Succeed())
+	g.Expect(passes()).To(BeFalse())
+	mp.Status.ReadyReplicas = 2
+	g.Expect(c.Update(ctx, mp)).To(Succeed())
+	g.Expect(passes()).To(BeTrue())
+
+	// The Cluster topology is not reconciled.
+	conditions.MarkFalse(cluster, clusterv1.TopologyReconciledCondition, "Reason", clusterv1.ConditionSeverityInfo, "")
+	g.Expect(passes()).To(BeFalse())
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(isNewerVersion("v1.25.0", "v1.24.0")).To(BeTrue())
+	g.Expect(isNewerVersion("v1.24.1", "v1.24.0")).To(BeTrue())
+	g.Expect(isNewerVersion("v1.24.0", "v1.24.0")).To(BeFalse())
+	g.Expect(isNewerVersion("v1.23.0", "v1.24.0")).To(BeFalse())
+	g.Expect(isNewerVersion("invalid", "v1.24.0")).To(BeFalse())
+}
+
+func TestClusterToClusterUpgradeRollouts(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	g.Expect(expv1.AddToScheme(scheme)).To(Succeed())
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "cluster1",
+			Labels:    map[string]string{"env": "prod"},
+		},
+	}
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "md1",
+			Labels:    map[string]string{clusterv1.ClusterLabelName: "cluster1"},
+		},
+	}
+	matching := &expv1.ClusterUpgradeRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "matching"},
+		Spec: expv1.ClusterUpgradeRolloutSpec{
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+	}
+	notMatching := &expv1.ClusterUpgradeRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "not-matching"},
+		Spec: expv1.ClusterUpgradeRolloutSpec{
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+		},
+	}
+	otherNamespace := &expv1.ClusterUpgradeRollout{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "other-namespace"},
+		Spec: expv1.ClusterUpgradeRolloutSpec{
+			ClusterSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, md, matching, notMatching, otherNamespace).Build()
+	r := &ClusterUpgradeRolloutReconciler{Client: c}
+
+	want := []ctrl.Request{{NamespacedName: client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "matching"}}}
+	g.Expect(r.clusterToClusterUpgradeRollouts(cluster)).To(Equal(want))
+	g.Expect(r.clusterObjectToClusterUpgradeRollouts(md)).To(Equal(want))
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -33,6 +34,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	labelsutil "sigs.k8s.io/cluster-api/util/labels"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -53,8 +55,24 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 		return ctrl.Result{}, nil
 	}
 
+	// Set the selector of the Nodes of the MachinePool, exposed by the scale subresource; the MachinePoolNameLabel
+	// is applied to the Nodes below.
+	// NOTE: Names which are not valid label values, e.g. longer than 63 characters, are hashed.
+	mp.Status.Selector = labels.SelectorFromSet(labels.Set{expv1.MachinePoolNameLabel: labelsutil.MustFormatValue(mp.Name)}).String()
+
 	// Check that the Machine doesn't already have a NodeRefs.
 	if mp.Status.Replicas == mp.Status.ReadyReplicas && len(mp.Status.NodeRefs) == int(mp.Status.ReadyReplicas) {
+		// Reconcile the annotations and labels of the Nodes anyway, so the selector keeps matching the Nodes
+		// even if the label has been removed from the Nodes.
+		if len(mp.Status.NodeRefs) > 0 {
+			clusterClient, err := remote.NewClusterClient(ctx, MachinePoolControllerName, r.Client, util.ObjectKey(cluster))
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := r.patchNodes(ctx, clusterClient, mp.Status.NodeRefs, mp); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
 		return ctrl.Result{}, nil
 	}
@@ -93,16 +111,34 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 	log.Info("Set MachinePools's NodeRefs", "noderefs", mp.Status.NodeRefs)
 	r.recorder.Event(mp, corev1.EventTypeNormal, "SuccessfulSetNodeRefs", fmt.Sprintf("%+v", mp.Status.NodeRefs))
 
-	// Reconcile node annotations.
-	for _, nodeRef := range nodeRefsResult.references {
+	// Reconcile node annotations and labels.
+	if err := r.patchNodes(ctx, clusterClient, nodeRefsResult.references, mp); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if mp.Status.Replicas != mp.Status.ReadyReplicas || len(nodeRefsResult.references) != int(mp.Status.ReadyReplicas) {
+		log.Info("NodeRefs != ReadyReplicas", "NodeRefs", len(nodeRefsResult.references), "ReadyReplicas", mp.Status.ReadyReplicas)
+		conditions.MarkFalse(mp, expv1.ReplicasReadyCondition, expv1.WaitingForReplicasReadyReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
+	// At this point, the required number of replicas are ready
+	conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
+	return ctrl.Result{}, nil
+}
+
+// patchNodes sets the annotations and the MachinePoolNameLabel of the MachinePool on the referenced Nodes.
+func (r *MachinePoolReconciler) patchNodes(ctx context.Context, c client.Client, references []corev1.ObjectReference, mp *expv1.MachinePool) error {
+	log := ctrl.LoggerFrom(ctx)
+	for _, nodeRef := range references {
 		node := &corev1.Node{}
-		if err := clusterClient.Get(ctx, client.ObjectKey{Name: nodeRef.Name}, node); err != nil {
+		if err := c.Get(ctx, client.ObjectKey{Name: nodeRef.Name}, node); err != nil {
 			log.V(2).Info("Failed to get Node, skipping setting annotations", "err", err, "nodeRef.Name", nodeRef.Name)
 			continue
 		}
-		patchHelper, err := patch.NewHelper(node, clusterClient)
+		patchHelper, err := patch.NewHelper(node, c)
 		if err != nil {
-			return ctrl.Result{}, err
+			return err
 		}
 		desired := map[string]string{
 			clusterv1.ClusterNameAnnotation:      mp.Spec.ClusterName,
@@ -110,23 +146,16 @@ func (r *MachinePoolReconciler) reconcileNodeRefs(ctx context.Context, cluster *
 			clusterv1.OwnerKindAnnotation:        mp.Kind,
 			clusterv1.OwnerNameAnnotation:        mp.Name,
 		}
-		if annotations.AddAnnotations(node, desired) {
+		annotationsChanged := annotations.AddAnnotations(node, desired)
+		labelsChanged := addNodeLabel(node, expv1.MachinePoolNameLabel, labelsutil.MustFormatValue(mp.Name))
+		if annotationsChanged || labelsChanged {
 			if err := patchHelper.Patch(ctx, node); err != nil {
-				log.V(2).Info("Failed patch node to set annotations", "err", err, "node name", node.Name)
-				return ctrl.Result{}, err
+				log.V(2).Info("Failed patch node to set annotations and labels", "err", err, "node name", node.Name)
+				return err
 			}
 		}
 	}
-
-	if mp.Status.Replicas != mp.Status.ReadyReplicas || len(nodeRefsResult.references) != int(mp.Status.ReadyReplicas) {
-		log.Info("NodeRefs != ReadyReplicas", "NodeRefs", len(nodeRefsResult.references), "ReadyReplicas", mp.Status.ReadyReplicas)
-		conditions.MarkFalse(mp, expv1.ReplicasReadyCondition, expv1.WaitingForReplicasReadyReason, clusterv1.ConditionSeverityInfo, "")
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
-	}
-
-	// At this point, the required number of replicas are ready
-	conditions.MarkTrue(mp, expv1.ReplicasReadyCondition)
-	return ctrl.Result{}, nil
+	return nil
 }
 
 // deleteRetiredNodes deletes nodes that don't have a corresponding ProviderID in Spec.ProviderIDList.
@@ -227,3 +256,15 @@ func nodeIsReady(node *corev1.Node) bool {
 	}
 	return false
 }
+
+// addNodeLabel sets a label on a Node, returning true if the Node has been changed.
+func addNodeLabel(node *corev1.Node, key, value string) bool {
+	if node.Labels[key] == value {
+		return false
+	}
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	node.Labels[key] = value
+	return true
+}
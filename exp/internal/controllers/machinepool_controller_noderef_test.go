@@ -17,14 +17,22 @@ limitations under the License.
 package controllers
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	labelsutil "sigs.k8s.io/cluster-api/util/labels"
 )
 
 func TestMachinePoolGetNodeReference(t *testing.T) {
@@ -199,3 +207,95 @@ func TestMachinePoolGetNodeReference(t *testing.T) {
 		})
 	}
 }
+
+func TestMachinePoolReconcileNodeRefsSetsSelector(t *testing.T) {
+	testCases := []struct {
+		name         string
+		machinePool  *expv1.MachinePool
+		wantSelector string
+	}{
+		{
+			name: "MachinePool without ProviderIDs",
+			machinePool: &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: "machinepool-test", Namespace: metav1.NamespaceDefault},
+				Spec:       expv1.MachinePoolSpec{Replicas: pointer.Int32(1)},
+			},
+			wantSelector: expv1.MachinePoolNameLabel + "=machinepool-test",
+		},
+		{
+			name: "MachinePool with a name longer than 63 characters",
+			machinePool: &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 64), Namespace: metav1.NamespaceDefault},
+				Spec:       expv1.MachinePoolSpec{Replicas: pointer.Int32(1)},
+			},
+			wantSelector: expv1.MachinePoolNameLabel + "=" + labelsutil.MustFormatValue(strings.Repeat("a", 64)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &MachinePoolReconciler{
+				Client:   fake.NewClientBuilder().Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			_, err := r.reconcileNodeRefs(ctx, &clusterv1.Cluster{}, tc.machinePool)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tc.machinePool.Status.Selector).To(Equal(tc.wantSelector))
+		})
+	}
+}
+
+func TestMachinePoolReconcileNodeRefsReconcilesNodeLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	ns, err := env.CreateNamespace(ctx, "test-machinepool-node-labels")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer func() {
+		g.Expect(env.Cleanup(ctx, ns)).To(Succeed())
+	}()
+
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: ns.Name}}
+	kubeconfigSecret := kubeconfig.GenerateSecret(cluster, kubeconfig.FromEnvTestConfig(env.Config, cluster))
+
+	// The Node of the MachinePool doesn't have the MachinePoolNameLabel, e.g. because it has been removed.
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "machinepool-test-node-"},
+		Spec:       corev1.NodeSpec{ProviderID: "test://id-1"},
+	}
+	g.Expect(env.Create(ctx, node)).To(Succeed())
+	defer func() {
+		g.Expect(env.Cleanup(ctx, node)).To(Succeed())
+	}()
+
+	// All the NodeRefs of the MachinePool are already set.
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 64), Namespace: ns.Name},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName:    cluster.Name,
+			Replicas:       pointer.Int32(1),
+			ProviderIDList: []string{"test://id-1"},
+		},
+		Status: expv1.MachinePoolStatus{
+			Replicas:      1,
+			ReadyReplicas: 1,
+			NodeRefs:      []corev1.ObjectReference{{Kind: "Node", Name: node.Name}},
+		},
+	}
+
+	r := &MachinePoolReconciler{
+		Client:   fake.NewClientBuilder().WithObjects(cluster, kubeconfigSecret).Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	_, err = r.reconcileNodeRefs(ctx, cluster, mp)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(conditions.IsTrue(mp, expv1.ReplicasReadyCondition)).To(BeTrue())
+
+	// The label is set on the Node, using the hashed MachinePool name given that it is longer than 63 characters.
+	got := &corev1.Node{}
+	g.Expect(env.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(node), got)).To(Succeed())
+	g.Expect(got.Labels).To(HaveKeyWithValue(expv1.MachinePoolNameLabel, labelsutil.MustFormatValue(mp.Name)))
+}
@@ -1143,9 +1143,11 @@ func TestReconcileMachinePoolScaleToFromZero(t *testing.T) {
 		r.reconcilePhase(machinepool)
 
 		g.Expect(machinepool.Status.GetTypedPhase()).To(Equal(expv1.MachinePoolPhaseScalingDown))
+		g.Expect(machinepool.Status.Selector).To(Equal(expv1.MachinePoolNameLabel + "=machinepool-test"))
 
 		delNode := &corev1.Node{}
 		g.Expect(env.Get(ctx, client.ObjectKeyFromObject(node), delNode)).To(BeNil())
+		g.Expect(delNode.Labels).To(HaveKeyWithValue(expv1.MachinePoolNameLabel, "machinepool-test"))
 	})
 
 	t.Run("Should delete retired nodes when scaled to zero", func(t *testing.T) {
@@ -34,6 +34,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
 )
 
 func TestMachinePoolFinalizer(t *testing.T) {
@@ -410,8 +411,11 @@ func TestReconcileMachinePoolRequest(t *testing.T) {
 		t.Run("machinePool should be "+tc.machinePool.Name, func(t *testing.T) {
 			g := NewWithT(t)
 
+			// NOTE: The kubeconfig is required because the MachinePool controller reconciles the labels of the Nodes
+			// in the workload cluster on every reconcile.
 			clientFake := fake.NewClientBuilder().WithObjects(
 				&testCluster,
+				kubeconfig.GenerateSecret(&testCluster, kubeconfig.FromEnvTestConfig(env.Config, &testCluster)),
 				&tc.machinePool,
 				&infraConfig,
 				bootstrapConfig,
@@ -843,8 +847,11 @@ func TestMachinePoolConditions(t *testing.T) {
 
 			g.Expect(clusterv1.AddToScheme(scheme.Scheme)).To(Succeed())
 
+			// NOTE: The kubeconfig is required because the MachinePool controller reconciles the labels of the Nodes
+			// in the workload cluster on every reconcile.
 			clientFake := fake.NewClientBuilder().WithObjects(
 				testCluster,
+				kubeconfig.GenerateSecret(testCluster, kubeconfig.FromEnvTestConfig(env.Config, testCluster)),
 				mp,
 				infra,
 				bootstrap,
@@ -46,6 +46,34 @@ type BeforeClusterCreateResponse struct {
 // BeforeClusterCreate is the hook that will be called right before the topology of the Cluster is created.
 func BeforeClusterCreate(*BeforeClusterCreateRequest, *BeforeClusterCreateResponse) {}
 
+// AfterControlPlaneEndpointSetRequest is the request of the AfterControlPlaneEndpointSet hook.
+// +kubebuilder:object:root=true
+type AfterControlPlaneEndpointSetRequest struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Cluster is the cluster object the lifecycle hook corresponds to.
+	Cluster clusterv1.Cluster `json:"cluster"`
+
+	// ControlPlaneEndpoint is the endpoint of the API server of the Cluster.
+	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
+}
+
+var _ ResponseObject = &AfterControlPlaneEndpointSetResponse{}
+
+// AfterControlPlaneEndpointSetResponse is the response of the AfterControlPlaneEndpointSet hook.
+// +kubebuilder:object:root=true
+type AfterControlPlaneEndpointSetResponse struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// CommonResponse contains Status and Message fields common to all response types.
+	CommonResponse `json:",inline"`
+}
+
+// AfterControlPlaneEndpointSet is the hook that will be called after the control plane endpoint of the Cluster
+// is set for the first time, e.g. to create a DNS record for the API server of the Cluster.
+func AfterControlPlaneEndpointSet(*AfterControlPlaneEndpointSetRequest, *AfterControlPlaneEndpointSetResponse) {
+}
+
 // AfterControlPlaneInitializedRequest is the request of the AfterControlPlaneInitialized hook.
 // +kubebuilder:object:root=true
 type AfterControlPlaneInitializedRequest struct {
@@ -192,6 +220,18 @@ func init() {
 			"tasks before the objects which are part of a Cluster's topology are created",
 	})
 
+	catalogBuilder.RegisterHook(AfterControlPlaneEndpointSet, &runtimecatalog.HookMeta{
+		Tags:    []string{"Lifecycle Hooks"},
+		Summary: "Cluster API Runtime will call this hook after the control plane endpoint of the Cluster is set",
+		Description: "Cluster API Runtime will call this hook after the control plane endpoint of the Cluster has been set " +
+			"for the first time by the infrastructure provider, thus allowing to e.g. create a DNS record for the API server of the Cluster.\n" +
+			"\n" +
+			"Notes:\n" +
+			"- This hook will be called only for Clusters with a managed topology\n" +
+			"- The call's request contains the Cluster object and the control plane endpoint\n" +
+			"- This is a non-blocking hook",
+	})
+
 	catalogBuilder.RegisterHook(AfterControlPlaneInitialized, &runtimecatalog.HookMeta{
 		Tags:    []string{"Lifecycle Hooks"},
 		Summary: "Cluster API Runtime will call this hook after the control plane is reachable for the first time",
@@ -75,6 +75,57 @@ func (in *AfterClusterUpgradeResponse) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AfterControlPlaneEndpointSetRequest) DeepCopyInto(out *AfterControlPlaneEndpointSetRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AfterControlPlaneEndpointSetRequest.
+func (in *AfterControlPlaneEndpointSetRequest) DeepCopy() *AfterControlPlaneEndpointSetRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(AfterControlPlaneEndpointSetRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AfterControlPlaneEndpointSetRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AfterControlPlaneEndpointSetResponse) DeepCopyInto(out *AfterControlPlaneEndpointSetResponse) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.CommonResponse = in.CommonResponse
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AfterControlPlaneEndpointSetResponse.
+func (in *AfterControlPlaneEndpointSetResponse) DeepCopy() *AfterControlPlaneEndpointSetResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(AfterControlPlaneEndpointSetResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AfterControlPlaneEndpointSetResponse) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AfterControlPlaneInitializedRequest) DeepCopyInto(out *AfterControlPlaneInitializedRequest) {
 	*out = *in
@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -91,3 +92,42 @@ func MachinePoolToInfrastructureMapFunc(gvk schema.GroupVersionKind, log logr.Lo
 		}
 	}
 }
+
+// ResolveRollingUpdateFenceposts returns the absolute values of maxSurge and maxUnavailable to be used by infrastructure
+// providers when rolling out the machine instances of a MachinePool; ok is false if the MachinePool does not define
+// a RollingUpdate strategy, and thus the upgrade behavior is defined by the infrastructure provider.
+// NOTE: If both values resolve to zero, maxUnavailable is set to 1 on the theory that surge might not work due to quota.
+func ResolveRollingUpdateFenceposts(mp *expv1.MachinePool) (maxSurge, maxUnavailable int32, ok bool, err error) {
+	strategy := mp.Spec.Strategy
+	if strategy == nil || (strategy.Type != "" && strategy.Type != expv1.RollingUpdateMachinePoolStrategyType) {
+		return 0, 0, false, nil
+	}
+
+	desired := 1
+	if mp.Spec.Replicas != nil {
+		desired = int(*mp.Spec.Replicas)
+	}
+
+	surgeValue, unavailableValue := intstr.FromInt(1), intstr.FromInt(0)
+	if strategy.RollingUpdate != nil && strategy.RollingUpdate.MaxSurge != nil {
+		surgeValue = *strategy.RollingUpdate.MaxSurge
+	}
+	if strategy.RollingUpdate != nil && strategy.RollingUpdate.MaxUnavailable != nil {
+		unavailableValue = *strategy.RollingUpdate.MaxUnavailable
+	}
+
+	surge, err := intstr.GetScaledValueFromIntOrPercent(&surgeValue, desired, true)
+	if err != nil {
+		return 0, 0, false, errors.Wrap(err, "failed to resolve maxSurge")
+	}
+	unavailable, err := intstr.GetScaledValueFromIntOrPercent(&unavailableValue, desired, false)
+	if err != nil {
+		return 0, 0, false, errors.Wrap(err, "failed to resolve maxUnavailable")
+	}
+
+	if surge == 0 && unavailable == 0 {
+		unavailable = 1
+	}
+
+	return int32(surge), int32(unavailable), true, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+func TestResolveRollingUpdateFenceposts(t *testing.T) {
+	ios0 := intstr.FromInt(0)
+	ios25Percent := intstr.FromString("25%")
+	iosInvalid := intstr.FromString("foo")
+
+	tests := []struct {
+		name                   string
+		replicas               *int32
+		strategy               *expv1.MachinePoolStrategy
+		expectedOk             bool
+		expectedMaxSurge       int32
+		expectedMaxUnavailable int32
+		expectErr              bool
+	}{
+		{
+			name:       "no strategy",
+			strategy:   nil,
+			expectedOk: false,
+		},
+		{
+			name:       "OnDelete strategy",
+			strategy:   &expv1.MachinePoolStrategy{Type: expv1.OnDeleteMachinePoolStrategyType},
+			expectedOk: false,
+		},
+		{
+			name:                   "RollingUpdate strategy without params uses defaults",
+			replicas:               pointer.Int32(3),
+			strategy:               &expv1.MachinePoolStrategy{Type: expv1.RollingUpdateMachinePoolStrategyType},
+			expectedOk:             true,
+			expectedMaxSurge:       1,
+			expectedMaxUnavailable: 0,
+		},
+		{
+			name:     "RollingUpdate strategy with percentages",
+			replicas: pointer.Int32(10),
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxSurge: &ios25Percent, MaxUnavailable: &ios25Percent},
+			},
+			expectedOk:             true,
+			expectedMaxSurge:       3,
+			expectedMaxUnavailable: 2,
+		},
+		{
+			name:     "maxUnavailable is set to 1 if both values resolve to 0",
+			replicas: pointer.Int32(3),
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxSurge: &ios0, MaxUnavailable: &ios25Percent},
+			},
+			expectedOk:             true,
+			expectedMaxSurge:       0,
+			expectedMaxUnavailable: 1,
+		},
+		{
+			name: "invalid value",
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxSurge: &iosInvalid},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := &expv1.MachinePool{
+				Spec: expv1.MachinePoolSpec{
+					Replicas: tt.replicas,
+					Strategy: tt.strategy,
+				},
+			}
+
+			maxSurge, maxUnavailable, ok, err := ResolveRollingUpdateFenceposts(mp)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ok).To(Equal(tt.expectedOk))
+			g.Expect(maxSurge).To(Equal(tt.expectedMaxSurge))
+			g.Expect(maxUnavailable).To(Equal(tt.expectedMaxUnavailable))
+		})
+	}
+}
@@ -55,6 +55,23 @@ const (
 	//
 	// alpha: v1.1
 	KubeadmBootstrapFormatIgnition featuregate.Feature = "KubeadmBootstrapFormatIgnition"
+
+	// ClusterSummary is a feature gate for the ClusterSummary functionality.
+	//
+	// alpha: v1.3
+	ClusterSummary featuregate.Feature = "ClusterSummary"
+
+	// MachineCrossNamespaceRefs is a feature gate allowing Machines to reference bootstrap configs and
+	// infrastructure machines in a different namespace than the Machine.
+	//
+	// alpha: v1.3
+	MachineCrossNamespaceRefs featuregate.Feature = "MachineCrossNamespaceRefs"
+
+	// ClusterUpgradeRollout is a feature gate for the ClusterUpgradeRollout functionality.
+	// NOTE: ClusterUpgradeRollouts upgrade Clusters with a managed topology, so this feature requires ClusterTopology.
+	//
+	// alpha: v1.3
+	ClusterUpgradeRollout featuregate.Feature = "ClusterUpgradeRollout"
 )
 
 func init() {
@@ -70,4 +87,7 @@ var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	ClusterTopology:                {Default: false, PreRelease: featuregate.Alpha},
 	KubeadmBootstrapFormatIgnition: {Default: false, PreRelease: featuregate.Alpha},
 	RuntimeSDK:                     {Default: false, PreRelease: featuregate.Alpha},
+	ClusterSummary:                 {Default: false, PreRelease: featuregate.Alpha},
+	MachineCrossNamespaceRefs:      {Default: false, PreRelease: featuregate.Alpha},
+	ClusterUpgradeRollout:          {Default: false, PreRelease: featuregate.Alpha},
 }
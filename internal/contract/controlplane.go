@@ -263,6 +263,17 @@ func (c *ControlPlaneMachineTemplate) InfrastructureRef() *Ref {
 	}
 }
 
+// FailureDomainInfrastructureRefs provides access to the failureDomainInfrastructureRefs of a MachineTemplate.
+// NOTE: When working with unstructured there is no way to understand if the ControlPlane provider
+// supports this field; the field is set only if the ClusterClass defines infrastructure machine templates
+// for specific failure domains.
+func (c *ControlPlaneMachineTemplate) FailureDomainInfrastructureRefs() *FailureDomainRefs {
+	return &FailureDomainRefs{
+		path:     Path{"spec", "machineTemplate", "failureDomainInfrastructureRefs"},
+		refField: "infrastructureRef",
+	}
+}
+
 // Metadata provides access to the metadata of a MachineTemplate.
 func (c *ControlPlaneMachineTemplate) Metadata() *Metadata {
 	return &Metadata{
@@ -270,6 +281,13 @@ func (c *ControlPlaneMachineTemplate) Metadata() *Metadata {
 	}
 }
 
+// AdditionalTags provides access to the additionalTags of a MachineTemplate.
+func (c *ControlPlaneMachineTemplate) AdditionalTags() *StringMap {
+	return &StringMap{
+		path: Path{"spec", "machineTemplate", "additionalTags"},
+	}
+}
+
 // NodeDrainTimeout provides access to the nodeDrainTimeout of a MachineTemplate.
 func (c *ControlPlaneMachineTemplate) NodeDrainTimeout() *Duration {
 	return &Duration{
@@ -178,6 +178,36 @@ func TestControlPlane(t *testing.T) {
 		g.Expect(got.Name).To(Equal(refObj.GetName()))
 		g.Expect(got.Namespace).To(Equal(refObj.GetNamespace()))
 	})
+	t.Run("Manages spec.machineTemplate.failureDomainInfrastructureRefs", func(t *testing.T) {
+		g := NewWithT(t)
+
+		refObj := fooRefBuilder()
+
+		g.Expect(ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Path()).To(Equal(Path{"spec", "machineTemplate", "failureDomainInfrastructureRefs"}))
+		g.Expect(ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().RefPath()).To(Equal(Path{"spec", "machineTemplate", "failureDomainInfrastructureRefs", "infrastructureRef"}))
+
+		got, err := ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(BeEmpty())
+
+		err = ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Set(obj, map[string]*unstructured.Unstructured{"fd2": refObj, "fd1": refObj})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		items, _, err := unstructured.NestedSlice(obj.UnstructuredContent(), "spec", "machineTemplate", "failureDomainInfrastructureRefs")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(items).To(HaveLen(2))
+		g.Expect(items[0]).To(HaveKeyWithValue("failureDomain", "fd1"))
+		g.Expect(items[1]).To(HaveKeyWithValue("failureDomain", "fd2"))
+
+		got, err = ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(HaveLen(2))
+		g.Expect(got).To(HaveKey("fd1"))
+		g.Expect(got["fd1"].APIVersion).To(Equal(refObj.GetAPIVersion()))
+		g.Expect(got["fd1"].Kind).To(Equal(refObj.GetKind()))
+		g.Expect(got["fd1"].Name).To(Equal(refObj.GetName()))
+		g.Expect(got["fd1"].Namespace).To(Equal(refObj.GetNamespace()))
+	})
 	t.Run("Manages spec.machineTemplate.metadata", func(t *testing.T) {
 		g := NewWithT(t)
 
@@ -266,6 +296,21 @@ func TestControlPlane(t *testing.T) {
 		g.Expect(found).To(BeTrue())
 		g.Expect(durationString).To(Equal(expectedDurationString))
 	})
+
+	t.Run("Manages spec.machineTemplate.additionalTags", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tags := map[string]string{"cost-center": "foo"}
+
+		g.Expect(ControlPlane().MachineTemplate().AdditionalTags().Path()).To(Equal(Path{"spec", "machineTemplate", "additionalTags"}))
+
+		err := ControlPlane().MachineTemplate().AdditionalTags().Set(obj, tags)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got, err := ControlPlane().MachineTemplate().AdditionalTags().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(Equal(tags))
+	})
 }
 
 func TestControlPlaneIsUpgrading(t *testing.T) {
@@ -17,6 +17,7 @@ limitations under the License.
 package contract
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -46,6 +47,80 @@ func (r *Ref) Set(obj, refObj *unstructured.Unstructured) error {
 	return SetNestedRef(obj, refObj, r.path...)
 }
 
+// FailureDomainRefs provide a helper struct for working with a list of references to be used in specific failure domains
+// in Unstructured objects, e.g. [{failureDomain: "fd1", infrastructureRef: {...}}].
+type FailureDomainRefs struct {
+	path     Path
+	refField string
+}
+
+// Path returns the path of the list of references.
+func (r *FailureDomainRefs) Path() Path {
+	return r.path
+}
+
+// RefPath returns the path of the references, traversing the items of the list.
+func (r *FailureDomainRefs) RefPath() Path {
+	return r.path.Append(r.refField)
+}
+
+// Get gets the references by failure domain from the Unstructured object.
+func (r *FailureDomainRefs) Get(obj *unstructured.Unstructured) (map[string]*corev1.ObjectReference, error) {
+	items, ok, err := unstructured.NestedSlice(obj.UnstructuredContent(), r.path...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s from %s", r.path, obj.GetKind())
+	}
+	refs := map[string]*corev1.ObjectReference{}
+	if !ok {
+		return refs, nil
+	}
+	for i, item := range items {
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("failed to get %s[%d] from %s: item is not an object", r.path, i, obj.GetKind())
+		}
+		failureDomain, ok, err := unstructured.NestedString(itemObj, "failureDomain")
+		if !ok || err != nil {
+			return nil, errors.Errorf("failed to get %s[%d].failureDomain from %s", r.path, i, obj.GetKind())
+		}
+		ref, err := GetNestedRef(&unstructured.Unstructured{Object: itemObj}, r.refField)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get %s[%d] from %s", r.path, i, obj.GetKind())
+		}
+		refs[failureDomain] = ref
+	}
+	return refs, nil
+}
+
+// Set sets the references by failure domain in the Unstructured object, pointing to the refObjs provided.
+// NOTE: The list is sorted by failure domain, so the resulting object does not depend on the map iteration order.
+func (r *FailureDomainRefs) Set(obj *unstructured.Unstructured, refObjs map[string]*unstructured.Unstructured) error {
+	failureDomains := make([]string, 0, len(refObjs))
+	for failureDomain := range refObjs {
+		failureDomains = append(failureDomains, failureDomain)
+	}
+	sort.Strings(failureDomains)
+
+	items := make([]interface{}, 0, len(failureDomains))
+	for _, failureDomain := range failureDomains {
+		refObj := refObjs[failureDomain]
+		items = append(items, map[string]interface{}{
+			"failureDomain": failureDomain,
+			r.refField: map[string]interface{}{
+				"kind":       refObj.GetKind(),
+				"namespace":  refObj.GetNamespace(),
+				"name":       refObj.GetName(),
+				"apiVersion": refObj.GetAPIVersion(),
+			},
+		})
+	}
+	if err := unstructured.SetNestedSlice(obj.UnstructuredContent(), items, r.path...); err != nil {
+		return errors.Wrapf(err, "failed to set failure domain references on object %v %s",
+			obj.GroupVersionKind(), klog.KObj(obj))
+	}
+	return nil
+}
+
 // GetNestedRef returns the ref value from a nested field in an Unstructured object.
 func GetNestedRef(obj *unstructured.Unstructured, fields ...string) (*corev1.ObjectReference, error) {
 	ref := &corev1.ObjectReference{}
@@ -71,6 +71,29 @@ func (p Path) String() string {
 	return strings.Join(p, ".")
 }
 
+// ParsePaths parses a comma separated list of dotted paths, e.g. "spec.foo,spec.bar.baz".
+// All the paths are required to be nested inside spec.
+func ParsePaths(value string) ([]Path, error) {
+	paths := []Path{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		path := Path(strings.Split(s, "."))
+		for _, f := range path {
+			if f == "" {
+				return nil, errors.Errorf("invalid path %q: path must not contain empty fields", s)
+			}
+		}
+		if len(path) < 2 || path[0] != "spec" {
+			return nil, errors.Errorf("invalid path %q: path must be nested inside spec", s)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
 // Int64 represents an accessor to an int64 path value.
 type Int64 struct {
 	path Path
@@ -161,6 +184,36 @@ func (s *String) Set(obj *unstructured.Unstructured, value string) error {
 	return nil
 }
 
+// StringMap represents an accessor to a map[string]string path value.
+type StringMap struct {
+	path Path
+}
+
+// Path returns the path to the map[string]string value.
+func (s *StringMap) Path() Path {
+	return s.path
+}
+
+// Get gets the map[string]string value.
+func (s *StringMap) Get(obj *unstructured.Unstructured) (map[string]string, error) {
+	value, ok, err := unstructured.NestedStringMap(obj.UnstructuredContent(), s.path...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get %s from object", "."+strings.Join(s.path, "."))
+	}
+	if !ok {
+		return nil, errors.Wrapf(errNotFound, "path %s", "."+strings.Join(s.path, "."))
+	}
+	return value, nil
+}
+
+// Set sets the map[string]string value in the path.
+func (s *StringMap) Set(obj *unstructured.Unstructured, value map[string]string) error {
+	if err := unstructured.SetNestedStringMap(obj.UnstructuredContent(), value, s.path...); err != nil {
+		return errors.Wrapf(err, "failed to set path %s of object %v", "."+strings.Join(s.path, "."), obj.GroupVersionKind())
+	}
+	return nil
+}
+
 // Duration represents an accessor to a metav1.Duration path value.
 type Duration struct {
 	path Path
@@ -159,3 +159,56 @@ func TestPath_Overlaps(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []Path
+		wantErr bool
+	}{
+		{
+			name:  "Empty value",
+			value: "",
+			want:  []Path{},
+		},
+		{
+			name:  "Single path",
+			value: "spec.foo",
+			want:  []Path{{"spec", "foo"}},
+		},
+		{
+			name:  "Many paths with spaces",
+			value: "spec.foo, spec.bar.baz ,",
+			want:  []Path{{"spec", "foo"}, {"spec", "bar", "baz"}},
+		},
+		{
+			name:    "Fails for path not nested inside spec",
+			value:   "status.foo",
+			wantErr: true,
+		},
+		{
+			name:    "Fails for spec",
+			value:   "spec",
+			wantErr: true,
+		},
+		{
+			name:    "Fails for path with empty fields",
+			value:   "spec..foo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ParsePaths(tt.value)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
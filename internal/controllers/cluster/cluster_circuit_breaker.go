@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(reconcileCircuitOpen)
+}
+
+// reconcileCircuitOpen reports the Clusters for which the reconcile circuit is open.
+var reconcileCircuitOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Subsystem: "capi_cluster",
+	Name:      "reconcile_circuit_open",
+	Help:      "Whether the reconcile circuit is open for a Cluster, i.e. the Cluster failed reconciliation too many consecutive times and it is reconciled only at a long interval.",
+}, []string{"namespace", "cluster"})
+
+// circuitBreaker tracks the consecutive reconcile failures of Clusters, and opens the circuit for Clusters
+// failing too many consecutive times so they are reconciled only at a long interval.
+// The circuit is closed as soon as the reconciliation of the Cluster succeeds again, or when the Cluster
+// spec is changed, e.g. to fix the issue causing the failures.
+type circuitBreaker struct {
+	// threshold is the number of consecutive failures after which the circuit is opened; zero disables the circuit breaker.
+	threshold int
+
+	// requeueAfter is the interval at which Clusters are reconciled while the circuit is open.
+	requeueAfter time.Duration
+
+	lock     sync.Mutex
+	clusters map[types.NamespacedName]*circuitState
+}
+
+// circuitState is the state of the circuit for a Cluster.
+type circuitState struct {
+	failures   int
+	generation int64
+	openUntil  time.Time
+}
+
+func newCircuitBreaker(threshold int, requeueAfter time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold:    threshold,
+		requeueAfter: requeueAfter,
+		clusters:     map[types.NamespacedName]*circuitState{},
+	}
+}
+
+func (c *circuitBreaker) enabled() bool {
+	return c != nil && c.threshold > 0 && c.requeueAfter > 0
+}
+
+// isOpen returns true, and the time left before the Cluster should be reconciled again,
+// if the circuit is open for a Cluster and the Cluster spec has not been changed since it was opened.
+func (c *circuitBreaker) isOpen(key types.NamespacedName, generation int64, now time.Time) (time.Duration, bool) {
+	if !c.enabled() {
+		return 0, false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	state, ok := c.clusters[key]
+	if !ok || state.generation != generation || !now.Before(state.openUntil) {
+		return 0, false
+	}
+	return state.openUntil.Sub(now), true
+}
+
+// recordFailure records a reconcile failure for a Cluster, and returns true if the circuit is open.
+// Failures observed for a previous generation of the Cluster are not taken into account.
+func (c *circuitBreaker) recordFailure(key types.NamespacedName, generation int64, now time.Time) bool {
+	if !c.enabled() {
+		return false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	state, ok := c.clusters[key]
+	if !ok || state.generation != generation {
+		state = &circuitState{generation: generation}
+		c.clusters[key] = state
+	}
+	state.failures++
+	if state.failures < c.threshold {
+		return false
+	}
+	state.openUntil = now.Add(c.requeueAfter)
+	reconcileCircuitOpen.WithLabelValues(key.Namespace, key.Name).Set(1)
+	return true
+}
+
+// reset closes the circuit for a Cluster, and forgets about its previous failures.
+func (c *circuitBreaker) reset(key types.NamespacedName) {
+	if !c.enabled() {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.clusters, key)
+	reconcileCircuitOpen.DeleteLabelValues(key.Namespace, key.Name)
+}
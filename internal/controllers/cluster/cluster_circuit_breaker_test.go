@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	key := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "circuit-breaker"}
+	now := time.Now()
+
+	t.Run("Circuit is opened after the threshold is reached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newCircuitBreaker(3, time.Minute)
+		g.Expect(c.recordFailure(key, 1, now)).To(BeFalse())
+		g.Expect(c.recordFailure(key, 1, now)).To(BeFalse())
+		_, open := c.isOpen(key, 1, now)
+		g.Expect(open).To(BeFalse())
+
+		g.Expect(c.recordFailure(key, 1, now)).To(BeTrue())
+		requeueAfter, open := c.isOpen(key, 1, now.Add(10*time.Second))
+		g.Expect(open).To(BeTrue())
+		g.Expect(requeueAfter).To(Equal(50 * time.Second))
+		g.Expect(testutil.ToFloat64(reconcileCircuitOpen.WithLabelValues(key.Namespace, key.Name))).To(Equal(float64(1)))
+
+		// The Cluster is reconciled again once the interval is expired, and a new failure opens the circuit again.
+		_, open = c.isOpen(key, 1, now.Add(time.Minute))
+		g.Expect(open).To(BeFalse())
+		g.Expect(c.recordFailure(key, 1, now.Add(time.Minute))).To(BeTrue())
+
+		c.reset(key)
+		_, open = c.isOpen(key, 1, now)
+		g.Expect(open).To(BeFalse())
+		g.Expect(testutil.CollectAndCount(reconcileCircuitOpen)).To(Equal(0))
+	})
+
+	t.Run("Circuit is closed when the Cluster spec is changed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := newCircuitBreaker(1, time.Minute)
+		g.Expect(c.recordFailure(key, 1, now)).To(BeTrue())
+		_, open := c.isOpen(key, 2, now)
+		g.Expect(open).To(BeFalse())
+
+		// Failures for the previous generation are not taken into account.
+		c.threshold = 2
+		g.Expect(c.recordFailure(key, 2, now)).To(BeFalse())
+		c.reset(key)
+	})
+
+	t.Run("Circuit breaker is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var nilCircuitBreaker *circuitBreaker
+		for _, c := range []*circuitBreaker{nilCircuitBreaker, newCircuitBreaker(0, time.Minute)} {
+			g.Expect(c.recordFailure(key, 1, now)).To(BeFalse())
+			_, open := c.isOpen(key, 1, now)
+			g.Expect(open).To(BeFalse())
+			c.reset(key)
+		}
+	})
+}
+
+func TestReconcileCircuitBreaker(t *testing.T) {
+	g := NewWithT(t)
+
+	key := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "circuit-breaker"}
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name, Generation: 1}}
+	r := &Reconciler{circuitBreaker: newCircuitBreaker(2, time.Minute)}
+	reconcileErr := errors.New("failed to reconcile")
+
+	// The error is returned until the threshold is reached.
+	res, err := r.reconcileCircuitBreaker(ctx, key, cluster, ctrl.Result{}, reconcileErr)
+	g.Expect(err).To(Equal(reconcileErr))
+	g.Expect(res).To(Equal(ctrl.Result{}))
+	g.Expect(conditions.Has(cluster, clusterv1.ReconcileCircuitClosedCondition)).To(BeFalse())
+
+	// The Cluster is requeued at a long interval once the circuit is opened.
+	res, err = r.reconcileCircuitBreaker(ctx, key, cluster, ctrl.Result{}, reconcileErr)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+	g.Expect(conditions.IsFalse(cluster, clusterv1.ReconcileCircuitClosedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.ReconcileCircuitClosedCondition)).To(Equal(clusterv1.ReconcileCircuitOpenReason))
+
+	// The circuit is closed when the reconciliation succeeds.
+	res, err = r.reconcileCircuitBreaker(ctx, key, cluster, ctrl.Result{}, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{}))
+	g.Expect(conditions.IsTrue(cluster, clusterv1.ReconcileCircuitClosedCondition)).To(BeTrue())
+	_, open := r.circuitBreaker.isOpen(key, 1, time.Now())
+	g.Expect(open).To(BeFalse())
+}
@@ -27,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -34,18 +35,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/remote"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/addresses"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
+	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
@@ -71,18 +77,42 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// SyncPeriod is the interval at which Clusters are periodically reconciled; if set, the resync events of the
+	// manager are ignored, so the interval can be both shorter and longer than the sync period of the manager.
+	// If zero, Clusters are reconciled at the sync period of the manager.
+	SyncPeriod time.Duration
+
+	// CircuitBreakerThreshold is the number of consecutive reconcile failures after which the circuit is opened
+	// for a Cluster, and the Cluster is reconciled only every CircuitBreakerRequeueAfter; zero disables the circuit breaker.
+	CircuitBreakerThreshold int
+
+	// CircuitBreakerRequeueAfter is the interval at which Clusters are reconciled while their circuit is open.
+	CircuitBreakerRequeueAfter time.Duration
+
+	// Tracker is used to read the state of the probes of the connection to the workload clusters;
+	// if not set, the RemoteConnectionProbe condition is not reported.
+	Tracker *remote.ClusterCacheTracker
+
 	recorder        record.EventRecorder
 	externalTracker external.ObjectTracker
+	circuitBreaker  *circuitBreaker
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	controller, err := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.Cluster{}).
 		Watches(
 			&source.Kind{Type: &clusterv1.Machine{}},
 			handler.EnqueueRequestsFromMapFunc(r.controlPlaneMachineToCluster),
-		).
-		WithOptions(options).
+		)
+	var resyncPredicates []predicate.Predicate
+	if r.SyncPeriod > 0 {
+		// Clusters are requeued after SyncPeriod, so the resync events of the manager are ignored.
+		resyncPredicate := predicate.ResourceVersionChangedPredicate{}
+		b = b.WithEventFilter(resyncPredicate)
+		resyncPredicates = append(resyncPredicates, resyncPredicate)
+	}
+	controller, err := b.WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Build(r)
 
@@ -91,13 +121,15 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 	}
 
 	r.recorder = mgr.GetEventRecorderFor("cluster-controller")
+	r.circuitBreaker = newCircuitBreaker(r.CircuitBreakerThreshold, r.CircuitBreakerRequeueAfter)
 	r.externalTracker = external.ObjectTracker{
 		Controller: controller,
+		Predicates: resyncPredicates,
 	}
 	return nil
 }
 
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (retRes ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Fetch the Cluster instance.
@@ -106,6 +138,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if apierrors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.circuitBreaker.reset(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 
@@ -113,12 +146,30 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Periodically reconcile the Cluster, if a sync period is set for the controller.
+	defer func() {
+		if reterr == nil && r.SyncPeriod > 0 {
+			retRes = util.LowestNonZeroResult(retRes, ctrl.Result{RequeueAfter: r.SyncPeriod})
+		}
+	}()
+
+	// Add the objects referenced by the Cluster to the logger.
+	ctx, _ = clog.AddObjectRef(ctx, cluster.Spec.InfrastructureRef)
+	ctx, log = clog.AddObjectRef(ctx, cluster.Spec.ControlPlaneRef)
+
 	// Return early if the object or Cluster is paused.
 	if annotations.IsPaused(cluster, cluster) {
 		log.Info("Reconciliation is paused for this object")
 		return ctrl.Result{}, nil
 	}
 
+	// Return early if the circuit is open, i.e. the Cluster failed reconciliation too many consecutive times,
+	// so a broken Cluster does not consume a disproportionate share of the controller workers.
+	if requeueAfter, open := r.circuitBreaker.isOpen(req.NamespacedName, cluster.Generation, time.Now()); open {
+		log.V(4).Info("Reconcile circuit is open, skipping reconciliation", "requeueAfter", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
 	// Initialize the patch helper.
 	patchHelper, err := patch.NewHelper(cluster, r.Client)
 	if err != nil {
@@ -135,6 +186,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		if reterr == nil {
 			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
 		}
+
+		// Track consecutive reconcile failures, and open the circuit for the Cluster if required.
+		retRes, reterr = r.reconcileCircuitBreaker(ctx, req.NamespacedName, cluster, retRes, reterr)
+
 		if err := patchCluster(ctx, patchHelper, cluster, patchOpts...); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, err})
 		}
@@ -148,11 +203,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	// Handle deletion reconciliation loop.
 	if !cluster.ObjectMeta.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, cluster)
+		res, err := r.reconcileDelete(ctx, cluster)
+		// Requeue without error if the reconcile failed because of a RequeueAfterError or of an
+		// ExternalDependencyNotReadyError, and drop terminal errors.
+		return utilerrors.ReconcileResult(ctx, res, err)
 	}
 
 	// Handle normal reconciliation loop.
-	return r.reconcile(ctx, cluster)
+	res, err := r.reconcile(ctx, cluster)
+	// Requeue without error if the reconcile failed because of a RequeueAfterError or of an
+	// ExternalDependencyNotReadyError, e.g. because the InfrastructureCluster does not exist yet, and drop terminal errors.
+	return utilerrors.ReconcileResult(ctx, res, err)
 }
 
 func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clusterv1.Cluster, options ...patch.Option) error {
@@ -172,11 +233,42 @@ func patchCluster(ctx context.Context, patchHelper *patch.Helper, cluster *clust
 			clusterv1.ReadyCondition,
 			clusterv1.ControlPlaneReadyCondition,
 			clusterv1.InfrastructureReadyCondition,
+			clusterv1.ReconcileCircuitClosedCondition,
+			clusterv1.RemoteConnectionProbeCondition,
+			clusterv1.KubernetesVersionSupportedCondition,
 		}},
 	)
 	return patchHelper.Patch(ctx, cluster, options...)
 }
 
+// reconcileCircuitBreaker records the outcome of the reconciliation of a Cluster; if the Cluster failed
+// reconciliation too many consecutive times, the circuit is opened, the error is logged and the Cluster is requeued
+// at a long interval instead of being retried with the usual exponential backoff.
+func (r *Reconciler) reconcileCircuitBreaker(ctx context.Context, key types.NamespacedName, cluster *clusterv1.Cluster, res ctrl.Result, reconcileErr error) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if reconcileErr == nil {
+		r.circuitBreaker.reset(key)
+		if conditions.Has(cluster, clusterv1.ReconcileCircuitClosedCondition) {
+			conditions.MarkTrue(cluster, clusterv1.ReconcileCircuitClosedCondition)
+		}
+		return res, nil
+	}
+
+	if !r.circuitBreaker.recordFailure(key, cluster.Generation, time.Now()) {
+		if conditions.Has(cluster, clusterv1.ReconcileCircuitClosedCondition) {
+			conditions.MarkTrue(cluster, clusterv1.ReconcileCircuitClosedCondition)
+		}
+		return res, reconcileErr
+	}
+
+	requeueAfter := r.circuitBreaker.requeueAfter
+	log.Error(reconcileErr, fmt.Sprintf("Reconcile failed %d or more consecutive times, opening the reconcile circuit", r.circuitBreaker.threshold), "requeueAfter", requeueAfter)
+	conditions.MarkFalse(cluster, clusterv1.ReconcileCircuitClosedCondition, clusterv1.ReconcileCircuitOpenReason, clusterv1.ConditionSeverityWarning,
+		"Reconcile failed %d or more consecutive times, retrying in %s: %v", r.circuitBreaker.threshold, requeueAfter, reconcileErr)
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
 // reconcile handles cluster reconciliation.
 func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -192,8 +284,11 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster)
 	phases := []func(context.Context, *clusterv1.Cluster) (ctrl.Result, error){
 		r.reconcileInfrastructure,
 		r.reconcileControlPlane,
+		r.reconcileKubernetesVersion,
+		r.reconcileControlPlaneAddresses,
 		r.reconcileKubeconfig,
 		r.reconcileControlPlaneInitialized,
+		r.reconcileRemoteConnectionProbe,
 	}
 
 	res := ctrl.Result{}
@@ -506,7 +601,7 @@ func (r *Reconciler) reconcileControlPlaneInitialized(ctx context.Context, clust
 }
 
 // controlPlaneMachineToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
-// for Cluster to update its status.controlPlaneInitialized field.
+// for Cluster to update its status.controlPlaneInitialized and status.controlPlaneAddresses fields.
 func (r *Reconciler) controlPlaneMachineToCluster(o client.Object) []ctrl.Request {
 	m, ok := o.(*clusterv1.Machine)
 	if !ok {
@@ -515,16 +610,14 @@ func (r *Reconciler) controlPlaneMachineToCluster(o client.Object) []ctrl.Reques
 	if !util.IsControlPlaneMachine(m) {
 		return nil
 	}
-	if m.Status.NodeRef == nil {
-		return nil
-	}
 
 	cluster, err := util.GetClusterByName(context.TODO(), r.Client, m.Namespace, m.Spec.ClusterName)
 	if err != nil {
 		return nil
 	}
 
-	if conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+	initializing := m.Status.NodeRef != nil && !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	if !initializing && !controlPlaneAddressesChanged(cluster, m) {
 		return nil
 	}
 
@@ -532,3 +625,28 @@ func (r *Reconciler) controlPlaneMachineToCluster(o client.Object) []ctrl.Reques
 		NamespacedName: util.ObjectKey(cluster),
 	}}
 }
+
+// controlPlaneAddressesChanged returns true if the addresses of a control plane Machine are not reported
+// in the Cluster status, or if the Machine is being deleted and its addresses must be removed.
+func controlPlaneAddressesChanged(cluster *clusterv1.Cluster, m *clusterv1.Machine) bool {
+	machineAddresses := addresses.Normalize(m.Status.Addresses)
+	if len(machineAddresses) == 0 {
+		return false
+	}
+	if !m.DeletionTimestamp.IsZero() {
+		return true
+	}
+	for _, a := range machineAddresses {
+		found := false
+		for _, c := range cluster.Status.ControlPlaneAddresses {
+			if a == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return true
+		}
+	}
+	return false
+}
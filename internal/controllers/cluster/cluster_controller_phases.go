@@ -24,6 +24,8 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -33,12 +35,16 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/addresses"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/cluster-api/version"
 )
 
 func (r *Reconciler) reconcilePhase(_ context.Context, cluster *clusterv1.Cluster) {
@@ -74,8 +80,7 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 	obj, err := external.Get(ctx, r.Client, ref, cluster.Namespace)
 	if err != nil {
 		if apierrors.IsNotFound(errors.Cause(err)) {
-			log.Info("Could not find external object for cluster, requeuing", "refGroupVersionKind", ref.GroupVersionKind(), "refName", ref.Name)
-			return external.ReconcileOutput{RequeueAfter: 30 * time.Second}, nil
+			return external.ReconcileOutput{}, utilerrors.NewExternalDependencyNotReadyError(fmt.Sprintf("%s %s", ref.Kind, klog.KRef(cluster.Namespace, ref.Name)), 30*time.Second)
 		}
 		return external.ReconcileOutput{}, err
 	}
@@ -169,10 +174,12 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 	}
 	cluster.Status.InfrastructureReady = ready
 
-	// Report a summary of current status of the infrastructure object defined for this cluster.
+	// Report a summary of current status of the infrastructure object defined for this cluster, including
+	// details from the conditions of the infrastructure object when it is not ready.
 	conditions.SetMirror(cluster, clusterv1.InfrastructureReadyCondition,
 		conditions.UnstructuredGetter(infraConfig),
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
+		conditions.WithDetails(),
 	)
 
 	if !ready {
@@ -232,10 +239,12 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, cluster *cluster
 	}
 	cluster.Status.ControlPlaneReady = ready
 
-	// Report a summary of current status of the control plane object defined for this cluster.
+	// Report a summary of current status of the control plane object defined for this cluster, including
+	// details from the conditions of the control plane object when it is not ready.
 	conditions.SetMirror(cluster, clusterv1.ControlPlaneReadyCondition,
 		conditions.UnstructuredGetter(controlPlaneConfig),
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForControlPlaneFallbackReason, clusterv1.ConditionSeverityInfo, ""),
+		conditions.WithDetails(),
 	)
 
 	// Update cluster.Status.ControlPlaneInitialized if it hasn't already been set
@@ -255,6 +264,109 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, cluster *cluster
 	return ctrl.Result{}, nil
 }
 
+// reconcileKubernetesVersion reports whether the Kubernetes version of the Cluster is supported by the installed
+// Cluster API release and by the providers of the InfrastructureCluster and of the ControlPlane, as defined by the
+// annotations on their CRDs; this surfaces unsupported versions before they cause failures, e.g. during upgrades.
+func (r *Reconciler) reconcileKubernetesVersion(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	kubernetesVersion, err := r.getKubernetesVersion(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if kubernetesVersion == "" {
+		conditions.Delete(cluster, clusterv1.KubernetesVersionSupportedCondition)
+		return ctrl.Result{}, nil
+	}
+
+	type supportedVersions struct {
+		source   string
+		versions version.KubernetesVersionRange
+	}
+	supported := []supportedVersions{{source: "Cluster API", versions: version.SupportedWorkloadKubernetesVersions}}
+	for _, ref := range []*corev1.ObjectReference{cluster.Spec.InfrastructureRef, cluster.Spec.ControlPlaneRef} {
+		if ref == nil {
+			continue
+		}
+		crdMetadata, err := util.GetGVKMetadata(ctx, r.Client, ref.GroupVersionKind())
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				continue
+			}
+			return ctrl.Result{}, err
+		}
+		versions := version.KubernetesVersionRange{
+			Minimum: crdMetadata.GetAnnotations()[clusterv1.MinimumKubernetesVersionAnnotation],
+			Maximum: crdMetadata.GetAnnotations()[clusterv1.MaximumKubernetesVersionAnnotation],
+		}
+		if versions != (version.KubernetesVersionRange{}) {
+			supported = append(supported, supportedVersions{source: ref.Kind, versions: versions})
+		}
+	}
+
+	for _, s := range supported {
+		compare, err := s.versions.Compare(kubernetesVersion)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to check the Kubernetes versions supported by %s", s.source)
+		}
+		switch compare {
+		case -1:
+			conditions.MarkFalse(cluster, clusterv1.KubernetesVersionSupportedCondition, clusterv1.KubernetesVersionTooOldReason, clusterv1.ConditionSeverityWarning,
+				"Kubernetes version %s is not supported by %s, supported versions are %s", kubernetesVersion, s.source, s.versions)
+			return ctrl.Result{}, nil
+		case 1:
+			conditions.MarkFalse(cluster, clusterv1.KubernetesVersionSupportedCondition, clusterv1.KubernetesVersionTooNewReason, clusterv1.ConditionSeverityWarning,
+				"Kubernetes version %s is not supported by %s, supported versions are %s", kubernetesVersion, s.source, s.versions)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	conditions.MarkTrue(cluster, clusterv1.KubernetesVersionSupportedCondition)
+	return ctrl.Result{}, nil
+}
+
+// reconcileControlPlaneAddresses reports the addresses of the control plane Machines, and their IP families, in the
+// Cluster status; addresses are normalized and ordered with the primary IP family first, so tooling consuming them
+// gets consistent, dual-stack aware addresses regardless of the infrastructure provider.
+func (r *Reconciler) reconcileControlPlaneAddresses(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	machines, err := collections.GetFilteredMachinesForCluster(ctx, r.Client, cluster, collections.ControlPlaneMachines(cluster.Name), collections.ActiveMachines)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to list control plane Machines for Cluster %s", klog.KObj(cluster))
+	}
+
+	// NOTE: Machines are sorted by creation timestamp, so the primary IP family is the one of the oldest Machine.
+	machineAddresses := [][]clusterv1.MachineAddress{}
+	for _, m := range machines.SortedByCreationTimestamp() {
+		machineAddresses = append(machineAddresses, m.Status.Addresses)
+	}
+	cluster.Status.ControlPlaneAddresses = addresses.Merge(machineAddresses...)
+	cluster.Status.IPFamilies = addresses.IPFamilies(cluster.Status.ControlPlaneAddresses)
+	return ctrl.Result{}, nil
+}
+
+// getKubernetesVersion returns the Kubernetes version of the Cluster, as defined in the Cluster topology or in the
+// ControlPlane object; an empty string is returned if the version is not known.
+func (r *Reconciler) getKubernetesVersion(ctx context.Context, cluster *clusterv1.Cluster) (string, error) {
+	if cluster.Spec.Topology != nil {
+		return cluster.Spec.Topology.Version, nil
+	}
+	if cluster.Spec.ControlPlaneRef == nil {
+		return "", nil
+	}
+
+	controlPlane, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return "", nil
+		}
+		return "", err
+	}
+	// NOTE: spec.version is optional in the control plane contract.
+	controlPlaneVersion, _, err := unstructured.NestedString(controlPlane.Object, "spec", "version")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the version of %s %s", controlPlane.GetKind(), klog.KObj(controlPlane))
+	}
+	return controlPlaneVersion, nil
+}
+
 func (r *Reconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -269,7 +381,7 @@ func (r *Reconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1
 		return ctrl.Result{}, nil
 	}
 
-	_, err := secret.Get(ctx, r.Client, util.ObjectKey(cluster), secret.Kubeconfig)
+	configSecret, err := secret.Get(ctx, r.Client, util.ObjectKey(cluster), secret.Kubeconfig)
 	switch {
 	case apierrors.IsNotFound(err):
 		if err := kubeconfig.CreateSecret(ctx, r.Client, cluster); err != nil {
@@ -279,9 +391,27 @@ func (r *Reconciler) reconcileKubeconfig(ctx context.Context, cluster *clusterv1
 			}
 			return ctrl.Result{}, err
 		}
+		return ctrl.Result{}, nil
 	case err != nil:
 		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve Kubeconfig Secret for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
 	}
 
+	// Only update the Kubeconfig if it is owned by the Cluster, e.g. it has not been provided by the user.
+	if !util.IsOwnedByObject(configSecret, cluster) {
+		return ctrl.Result{}, nil
+	}
+
+	// If the control plane endpoint has been changed, regenerate the Kubeconfig using the new endpoint.
+	needsEndpointUpdate, err := kubeconfig.NeedsEndpointUpdate(configSecret, cluster.Spec.ControlPlaneEndpoint.String())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if needsEndpointUpdate {
+		log.Info("Updating Kubeconfig Secret with the new control plane endpoint", "Secret", klog.KObj(configSecret))
+		if err := kubeconfig.RegenerateSecretWithEndpoint(ctx, r.Client, configSecret, cluster.Spec.ControlPlaneEndpoint.String()); err != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to regenerate Kubeconfig Secret for Cluster %q in namespace %q", cluster.Name, cluster.Namespace)
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
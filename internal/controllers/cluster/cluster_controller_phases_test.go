@@ -31,6 +31,8 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 )
 
 func TestClusterReconcilePhases(t *testing.T) {
@@ -62,6 +64,8 @@ func TestClusterReconcilePhases(t *testing.T) {
 			infraRef     map[string]interface{}
 			expectErr    bool
 			expectResult ctrl.Result
+			// expectMappedResult is the result returned to controller-runtime after mapping typed reconcile errors.
+			expectMappedResult ctrl.Result
 		}{
 			{
 				name:      "returns no error if infrastructure ref is nil",
@@ -69,10 +73,10 @@ func TestClusterReconcilePhases(t *testing.T) {
 				expectErr: false,
 			},
 			{
-				name:         "returns error if unable to reconcile infrastructure ref",
-				cluster:      cluster,
-				expectErr:    false,
-				expectResult: ctrl.Result{RequeueAfter: 30 * time.Second},
+				name:               "returns an ExternalDependencyNotReady error if the infrastructure ref can't be found",
+				cluster:            cluster,
+				expectErr:          true,
+				expectMappedResult: ctrl.Result{RequeueAfter: 30 * time.Second},
 			},
 			{
 				name:    "returns no error if infra config is marked for deletion",
@@ -146,6 +150,10 @@ func TestClusterReconcilePhases(t *testing.T) {
 				} else {
 					g.Expect(err).NotTo(HaveOccurred())
 				}
+
+				res, err = utilerrors.ReconcileResult(ctx, res, err)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(res).To(Equal(tt.expectMappedResult))
 			})
 		}
 	})
@@ -489,6 +497,167 @@ func TestClusterReconcilePhases_reconcileFailureDomains(t *testing.T) {
 	}
 }
 
+func TestClusterReconcilePhases_reconcileKubernetesVersion(t *testing.T) {
+	controlPlaneRef := &corev1.ObjectReference{
+		APIVersion: builder.ControlPlaneGroupVersion.String(),
+		Kind:       builder.GenericControlPlaneKind,
+		Name:       "test-control-plane",
+		Namespace:  "test-namespace",
+	}
+
+	controlPlaneCRDWithMaximumVersion := builder.GenericControlPlaneCRD.DeepCopy()
+	controlPlaneCRDWithMaximumVersion.Annotations = map[string]string{
+		clusterv1.MaximumKubernetesVersionAnnotation: "v1.24",
+	}
+
+	tests := []struct {
+		name            string
+		topology        *clusterv1.Topology
+		controlPlane    *unstructured.Unstructured
+		controlPlaneCRD client.Object
+		expectStatus    corev1.ConditionStatus
+		expectReason    string
+	}{
+		{
+			name:            "version unknown if there is no topology and the control plane does not exist",
+			controlPlaneCRD: builder.GenericControlPlaneCRD.DeepCopy(),
+		},
+		{
+			name:            "supported topology version",
+			topology:        &clusterv1.Topology{Version: "v1.24.1"},
+			controlPlaneCRD: builder.GenericControlPlaneCRD.DeepCopy(),
+			expectStatus:    corev1.ConditionTrue,
+		},
+		{
+			name:            "topology version older than the ones supported by Cluster API",
+			topology:        &clusterv1.Topology{Version: "v1.19.1"},
+			controlPlaneCRD: builder.GenericControlPlaneCRD.DeepCopy(),
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    clusterv1.KubernetesVersionTooOldReason,
+		},
+		{
+			name:            "topology version newer than the ones supported by Cluster API",
+			topology:        &clusterv1.Topology{Version: "v1.27.0"},
+			controlPlaneCRD: builder.GenericControlPlaneCRD.DeepCopy(),
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    clusterv1.KubernetesVersionTooNewReason,
+		},
+		{
+			name:            "control plane version supported by the control plane provider",
+			controlPlane:    builder.ControlPlane("test-namespace", "test-control-plane").WithVersion("v1.24.3").Build(),
+			controlPlaneCRD: controlPlaneCRDWithMaximumVersion,
+			expectStatus:    corev1.ConditionTrue,
+		},
+		{
+			name:            "control plane version newer than the ones supported by the control plane provider",
+			controlPlane:    builder.ControlPlane("test-namespace", "test-control-plane").WithVersion("v1.25.0").Build(),
+			controlPlaneCRD: controlPlaneCRDWithMaximumVersion,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    clusterv1.KubernetesVersionTooNewReason,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-namespace",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef: controlPlaneRef,
+					Topology:        tt.topology,
+				},
+			}
+
+			objs := []client.Object{tt.controlPlaneCRD, cluster}
+			if tt.controlPlane != nil {
+				objs = append(objs, tt.controlPlane)
+			}
+
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithObjects(objs...).Build(),
+			}
+
+			_, err := r.reconcileKubernetesVersion(ctx, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			if tt.expectStatus == "" {
+				g.Expect(conditions.Has(cluster, clusterv1.KubernetesVersionSupportedCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(conditions.Get(cluster, clusterv1.KubernetesVersionSupportedCondition).Status).To(Equal(tt.expectStatus))
+			g.Expect(conditions.GetReason(cluster, clusterv1.KubernetesVersionSupportedCondition)).To(Equal(tt.expectReason))
+		})
+	}
+}
+
+func TestClusterReconcilePhases_reconcileControlPlaneAddresses(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+		},
+	}
+	controlPlaneMachine := func(name string, creationTimestamp time.Time, addresses ...clusterv1.MachineAddress) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "test-namespace",
+				CreationTimestamp: metav1.NewTime(creationTimestamp),
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName:             "test-cluster",
+					clusterv1.MachineControlPlaneLabelName: "",
+				},
+			},
+			Spec:   clusterv1.MachineSpec{ClusterName: "test-cluster"},
+			Status: clusterv1.MachineStatus{Addresses: addresses},
+		}
+	}
+	now := time.Now()
+	objs := []client.Object{
+		cluster,
+		controlPlaneMachine("machine2", now,
+			clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"},
+			clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "fd00::2"},
+		),
+		controlPlaneMachine("machine1", now.Add(-time.Minute),
+			clusterv1.MachineAddress{Type: clusterv1.MachineHostName, Address: "machine1"},
+			clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "FD00::1"},
+			clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		),
+		// Addresses of worker Machines are not reported.
+		&clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "worker",
+				Namespace: "test-namespace",
+				Labels:    map[string]string{clusterv1.ClusterLabelName: "test-cluster"},
+			},
+			Spec:   clusterv1.MachineSpec{ClusterName: "test-cluster"},
+			Status: clusterv1.MachineStatus{Addresses: clusterv1.MachineAddresses{{Type: clusterv1.MachineInternalIP, Address: "10.0.0.3"}}},
+		},
+	}
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(objs...).Build(),
+	}
+
+	_, err := r.reconcileControlPlaneAddresses(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cluster.Status.ControlPlaneAddresses).To(Equal(clusterv1.MachineAddresses{
+		{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+		{Type: clusterv1.MachineInternalIP, Address: "fd00::2"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"},
+		{Type: clusterv1.MachineHostName, Address: "machine1"},
+	}))
+	g.Expect(cluster.Status.IPFamilies).To(Equal([]clusterv1.AddressIPFamily{clusterv1.IPv6AddressFamily, clusterv1.IPv4AddressFamily}))
+}
+
 func generateInfraRef(withFailureDomain bool) map[string]interface{} {
 	infraRef := map[string]interface{}{
 		"kind":       "GenericInfrastructureCluster",
@@ -18,6 +18,7 @@ package cluster
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -401,6 +402,34 @@ func TestClusterReconciler_reconcileDelete(t *testing.T) {
 	}
 }
 
+func TestClusterReconcilerMapsReconcileErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	// The InfrastructureCluster referenced by the Cluster does not exist yet.
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithInfrastructureCluster(builder.InfrastructureCluster(metav1.NamespaceDefault, "infra-cluster1").Build()).
+		Build()
+	cluster.Finalizers = []string{clusterv1.ClusterFinalizer}
+
+	fakeClient := fake.NewClientBuilder().WithObjects(builder.GenericInfrastructureClusterCRD.DeepCopy(), cluster).Build()
+	r := &Reconciler{
+		Client:    fakeClient,
+		APIReader: fakeClient,
+		// The circuit is opened after the first failure, so the test fails if the missing InfrastructureCluster
+		// is handled as a reconcile failure.
+		circuitBreaker: newCircuitBreaker(1, time.Hour),
+	}
+
+	// The missing InfrastructureCluster is an ExternalDependencyNotReadyError, so the Cluster is requeued without error.
+	res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: util.ObjectKey(cluster)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res).To(Equal(ctrl.Result{RequeueAfter: 30 * time.Second}))
+
+	got := &clusterv1.Cluster{}
+	g.Expect(fakeClient.Get(ctx, util.ObjectKey(cluster), got)).To(Succeed())
+	g.Expect(conditions.Has(got, clusterv1.ReconcileCircuitClosedCondition)).To(BeFalse())
+}
+
 func TestClusterReconcilerNodeRef(t *testing.T) {
 	t.Run("machine to cluster", func(t *testing.T) {
 		cluster := &clusterv1.Cluster{
@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// remoteConnectionProbeRequeueAfter is the interval at which Clusters are reconciled to surface the result of the
+// probes of the connection to the workload cluster, given that probes do not trigger a reconcile of the Cluster.
+const remoteConnectionProbeRequeueAfter = 1 * time.Minute
+
+// reconcileRemoteConnectionProbe surfaces the result of the probes of the connection to the API server of
+// the workload cluster in the RemoteConnectionProbe condition.
+func (r *Reconciler) reconcileRemoteConnectionProbe(_ context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	if r.Tracker == nil {
+		return ctrl.Result{}, nil
+	}
+
+	state, ok := r.Tracker.GetHealthCheckingState(util.ObjectKey(cluster))
+	if !ok {
+		return ctrl.Result{}, nil
+	}
+	setRemoteConnectionProbeCondition(cluster, state)
+	return ctrl.Result{RequeueAfter: remoteConnectionProbeRequeueAfter}, nil
+}
+
+// setRemoteConnectionProbeCondition sets the RemoteConnectionProbe condition according to the state of the probes.
+func setRemoteConnectionProbeCondition(cluster *clusterv1.Cluster, state remote.HealthCheckingState) {
+	if state.ConsecutiveFailures == 0 {
+		conditions.MarkTrue(cluster, clusterv1.RemoteConnectionProbeCondition)
+		return
+	}
+
+	lastSuccess := "never"
+	if !state.LastProbeSuccessTime.IsZero() {
+		lastSuccess = state.LastProbeSuccessTime.UTC().Format(time.RFC3339)
+	}
+	conditions.MarkFalse(cluster, clusterv1.RemoteConnectionProbeCondition, clusterv1.RemoteConnectionFailedReason, clusterv1.ConditionSeverityWarning,
+		"%d consecutive probes of the workload cluster API server failed, last successful probe: %s, last error: %v", state.ConsecutiveFailures, lastSuccess, state.LastProbeError)
+}
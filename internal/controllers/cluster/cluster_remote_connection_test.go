@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestSetRemoteConnectionProbeCondition(t *testing.T) {
+	now := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		state       remote.HealthCheckingState
+		wantStatus  corev1.ConditionStatus
+		wantMessage string
+	}{
+		{
+			name: "True if the last probe succeeded",
+			state: remote.HealthCheckingState{
+				LastProbeTime:        now,
+				LastProbeSuccessTime: now,
+			},
+			wantStatus: corev1.ConditionTrue,
+		},
+		{
+			name: "False if the last probes failed",
+			state: remote.HealthCheckingState{
+				LastProbeTime:        now,
+				LastProbeSuccessTime: now.Add(-time.Minute),
+				ConsecutiveFailures:  3,
+				LastProbeError:       errors.New("connection refused"),
+			},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "3 consecutive probes of the workload cluster API server failed, last successful probe: 2022-10-01T09:59:00Z, last error: connection refused",
+		},
+		{
+			name: "False if no probe ever succeeded",
+			state: remote.HealthCheckingState{
+				LastProbeTime:       now,
+				ConsecutiveFailures: 1,
+				LastProbeError:      errors.New("timeout"),
+			},
+			wantStatus:  corev1.ConditionFalse,
+			wantMessage: "1 consecutive probes of the workload cluster API server failed, last successful probe: never, last error: timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{}
+			setRemoteConnectionProbeCondition(cluster, tt.state)
+
+			c := conditions.Get(cluster, clusterv1.RemoteConnectionProbeCondition)
+			g.Expect(c).ToNot(BeNil())
+			g.Expect(c.Status).To(Equal(tt.wantStatus))
+			g.Expect(c.Message).To(Equal(tt.wantMessage))
+			if tt.wantStatus == corev1.ConditionFalse {
+				g.Expect(c.Reason).To(Equal(clusterv1.RemoteConnectionFailedReason))
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package clusterclass
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -26,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -56,6 +58,8 @@ type Reconciler struct {
 	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
 	// thus allowing to optimize reads for templates or provider specific objects.
 	UnstructuredCachingClient client.Client
+
+	recorder record.EventRecorder
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -68,6 +72,8 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
+
+	r.recorder = mgr.GetEventRecorderFor("clusterclass-controller")
 	return nil
 }
 
@@ -99,7 +105,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}
 
 	defer func() {
-		if err := patchHelper.Patch(ctx, clusterClass); err != nil {
+		// Patch ObservedGeneration only if the reconciliation completed successfully.
+		patchOpts := []patch.Option{}
+		if reterr == nil {
+			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
+		}
+		if err := patchHelper.Patch(ctx, clusterClass, patchOpts...); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: clusterClass})})
 			return
 		}
@@ -122,6 +133,11 @@ func (r *Reconciler) reconcile(ctx context.Context, clusterClass *clusterv1.Clus
 	if clusterClass.Spec.ControlPlane.MachineInfrastructure != nil && clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref != nil {
 		refs = append(refs, clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref)
 	}
+	for _, machineInfrastructure := range clusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure {
+		if machineInfrastructure.Ref != nil {
+			refs = append(refs, machineInfrastructure.Ref)
+		}
+	}
 
 	for _, mdClass := range clusterClass.Spec.Workers.MachineDeployments {
 		if mdClass.Template.Bootstrap.Ref != nil {
@@ -173,24 +189,40 @@ func (r *Reconciler) reconcile(ctx context.Context, clusterClass *clusterv1.Clus
 		return ctrl.Result{}, kerrors.NewAggregate(errs)
 	}
 
+	r.recordOutdatedRefsEvents(clusterClass, outdatedRefs)
 	reconcileConditions(clusterClass, outdatedRefs)
 
 	return ctrl.Result{}, nil
 }
 
+// recordOutdatedRefsEvents records a warning event for every template reference using an apiVersion which is not the latest
+// for the current CAPI contract, so administrators can notice providers are going to drop old apiVersions before upgrades fail.
+// NOTE: Events are recorded only when the outdated references change, to avoid recording the same events at every reconcile.
+func (r *Reconciler) recordOutdatedRefsEvents(clusterClass *clusterv1.ClusterClass, outdatedRefs map[*corev1.ObjectReference]*corev1.ObjectReference) {
+	if r.recorder == nil || len(outdatedRefs) == 0 {
+		return
+	}
+	if conditions.IsFalse(clusterClass, clusterv1.ClusterClassRefVersionsUpToDateCondition) &&
+		conditions.GetMessage(clusterClass, clusterv1.ClusterClassRefVersionsUpToDateCondition) == outdatedRefsMessage(outdatedRefs) {
+		return
+	}
+
+	for currentRef, updatedRef := range outdatedRefs {
+		r.recorder.Eventf(clusterClass, corev1.EventTypeWarning, clusterv1.ClusterClassOutdatedRefVersionsReason,
+			"Template %s %s/%s is referenced using apiVersion %s; it should be updated to apiVersion %s",
+			currentRef.Kind, currentRef.Namespace, currentRef.Name, currentRef.APIVersion, updatedRef.APIVersion)
+	}
+}
+
 func reconcileConditions(clusterClass *clusterv1.ClusterClass, outdatedRefs map[*corev1.ObjectReference]*corev1.ObjectReference) {
 	if len(outdatedRefs) > 0 {
-		var msg []string
-		for currentRef, updatedRef := range outdatedRefs {
-			msg = append(msg, fmt.Sprintf("Ref %q should be %q", refString(currentRef), refString(updatedRef)))
-		}
 		conditions.Set(
 			clusterClass,
 			conditions.FalseCondition(
 				clusterv1.ClusterClassRefVersionsUpToDateCondition,
 				clusterv1.ClusterClassOutdatedRefVersionsReason,
 				clusterv1.ConditionSeverityWarning,
-				strings.Join(msg, ", "),
+				outdatedRefsMessage(outdatedRefs),
 			),
 		)
 		return
@@ -202,6 +234,16 @@ func reconcileConditions(clusterClass *clusterv1.ClusterClass, outdatedRefs map[
 	)
 }
 
+// outdatedRefsMessage returns a message listing all the outdated references, sorted to ensure the message is stable across reconciles.
+func outdatedRefsMessage(outdatedRefs map[*corev1.ObjectReference]*corev1.ObjectReference) string {
+	msg := make([]string, 0, len(outdatedRefs))
+	for currentRef, updatedRef := range outdatedRefs {
+		msg = append(msg, fmt.Sprintf("Ref %q should be %q", refString(currentRef), refString(updatedRef)))
+	}
+	sort.Strings(msg)
+	return strings.Join(msg, ", ")
+}
+
 func refString(ref *corev1.ObjectReference) string {
 	return fmt.Sprintf("%s %s/%s", ref.GroupVersionKind().String(), ref.Namespace, ref.Name)
 }
@@ -25,11 +25,13 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 func TestClusterClassReconciler_reconcile(t *testing.T) {
@@ -262,3 +264,52 @@ func isOwnerReferenceEqual(a, b metav1.OwnerReference) bool {
 	}
 	return true
 }
+
+func TestReconciler_recordOutdatedRefsEvents(t *testing.T) {
+	currentRef := &corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1alpha4",
+		Kind:       "DockerMachineTemplate",
+		Namespace:  metav1.NamespaceDefault,
+		Name:       "md-template",
+	}
+	updatedRef := currentRef.DeepCopy()
+	updatedRef.APIVersion = "infrastructure.cluster.x-k8s.io/v1beta1"
+	outdatedRefs := map[*corev1.ObjectReference]*corev1.ObjectReference{currentRef: updatedRef}
+
+	t.Run("records an event when outdated references are detected", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{recorder: recorder}
+		clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+
+		r.recordOutdatedRefsEvents(clusterClass, outdatedRefs)
+		reconcileConditions(clusterClass, outdatedRefs)
+
+		g.Expect(recorder.Events).To(Receive(And(
+			ContainSubstring(clusterv1.ClusterClassOutdatedRefVersionsReason),
+			ContainSubstring("DockerMachineTemplate default/md-template"),
+			ContainSubstring("infrastructure.cluster.x-k8s.io/v1alpha4"),
+			ContainSubstring("infrastructure.cluster.x-k8s.io/v1beta1"),
+		)))
+		g.Expect(conditions.IsFalse(clusterClass, clusterv1.ClusterClassRefVersionsUpToDateCondition)).To(BeTrue())
+
+		// The event is not recorded again if the outdated references did not change.
+		r.recordOutdatedRefsEvents(clusterClass, outdatedRefs)
+		g.Expect(recorder.Events).ToNot(Receive())
+	})
+
+	t.Run("does not record events when references are up to date", func(t *testing.T) {
+		g := NewWithT(t)
+
+		recorder := record.NewFakeRecorder(10)
+		r := &Reconciler{recorder: recorder}
+		clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+
+		r.recordOutdatedRefsEvents(clusterClass, map[*corev1.ObjectReference]*corev1.ObjectReference{})
+		reconcileConditions(clusterClass, map[*corev1.ObjectReference]*corev1.ObjectReference{})
+
+		g.Expect(recorder.Events).ToNot(Receive())
+		g.Expect(conditions.IsTrue(clusterClass, clusterv1.ClusterClassRefVersionsUpToDateCondition)).To(BeTrue())
+	})
+}
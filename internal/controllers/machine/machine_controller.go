@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
@@ -38,6 +39,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -45,11 +47,13 @@ import (
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
+	"sigs.k8s.io/cluster-api/controllers/readiness"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
@@ -84,6 +88,24 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// SyncPeriod is the interval at which Machines are periodically reconciled; if set, the resync events of the
+	// manager are ignored, so the interval can be both shorter and longer than the sync period of the manager.
+	// If zero, Machines are reconciled at the sync period of the manager.
+	SyncPeriod time.Duration
+
+	// RecordDeletionEvents enables recording an event with the deletion cause of each deleted Machine.
+	// NOTE: Events are retained only for the event TTL of the kube-apiserver.
+	RecordDeletionEvents bool
+
+	// InfrastructureReadinessCheckers are checks which must pass, in addition to the infrastructure provider
+	// reporting status.ready, before a Machine is marked as InfrastructureReady.
+	InfrastructureReadinessCheckers []readiness.InfrastructureChecker
+
+	// CrossNamespaceRefsAllowList is a list of <Machine namespace>=<referenced namespace> pairs, e.g. tenant-a=platform-infra,
+	// defining the namespaces Machines can reference bootstrap configs and infrastructure machines in, other than their own;
+	// it is used only if the MachineCrossNamespaceRefs feature flag is enabled, and all other cross-namespace references are rejected.
+	CrossNamespaceRefsAllowList []string
+
 	controller      controller.Controller
 	recorder        record.EventRecorder
 	externalTracker external.ObjectTracker
@@ -91,6 +113,10 @@ type Reconciler struct {
 	// nodeDeletionRetryTimeout determines how long the controller will retry deleting a node
 	// during a single reconciliation.
 	nodeDeletionRetryTimeout time.Duration
+
+	// crossNamespaceRefsAllowed maps each Machine namespace to the namespaces Machines can reference, as defined by
+	// CrossNamespaceRefsAllowList.
+	crossNamespaceRefsAllowed map[string]sets.String
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -103,9 +129,21 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		r.nodeDeletionRetryTimeout = 10 * time.Second
 	}
 
-	controller, err := ctrl.NewControllerManagedBy(mgr).
-		For(&clusterv1.Machine{}).
-		WithOptions(options).
+	r.crossNamespaceRefsAllowed, err = parseCrossNamespaceRefsAllowList(r.CrossNamespaceRefsAllowList)
+	if err != nil {
+		return err
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&clusterv1.Machine{})
+	var resyncPredicates []predicate.Predicate
+	if r.SyncPeriod > 0 {
+		// Machines are requeued after SyncPeriod, so the resync events of the manager are ignored.
+		resyncPredicate := predicate.ResourceVersionChangedPredicate{}
+		b = b.WithEventFilter(resyncPredicate)
+		resyncPredicates = append(resyncPredicates, resyncPredicate)
+	}
+	controller, err := b.WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Build(r)
 	if err != nil {
@@ -133,11 +171,12 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 	r.recorder = mgr.GetEventRecorderFor("machine-controller")
 	r.externalTracker = external.ObjectTracker{
 		Controller: controller,
+		Predicates: resyncPredicates,
 	}
 	return nil
 }
 
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (retRes ctrl.Result, reterr error) {
 	// Fetch the Machine instance
 	m := &clusterv1.Machine{}
 	if err := r.Client.Get(ctx, req.NamespacedName, m); err != nil {
@@ -151,6 +190,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	// Periodically reconcile the Machine, if a sync period is set for the controller.
+	defer func() {
+		if reterr == nil && r.SyncPeriod > 0 {
+			retRes = util.LowestNonZeroResult(retRes, ctrl.Result{RequeueAfter: r.SyncPeriod})
+		}
+	}()
+
 	// AddOwners adds the owners of Machine as k/v pairs to the logger.
 	// Specifically, it will add KubeadmControlPlane, MachineSet and MachineDeployment.
 	ctx, log, err := clog.AddOwners(ctx, r.Client, m)
@@ -158,8 +204,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	log = log.WithValues("Cluster", klog.KRef(m.ObjectMeta.Namespace, m.Spec.ClusterName))
-	ctx = ctrl.LoggerInto(ctx, log)
+	// Add the Cluster and the objects referenced by the Machine as k/v pairs to the logger.
+	ctx, _ = clog.AddCluster(ctx, m.ObjectMeta.Namespace, m.Spec.ClusterName)
+	ctx, _ = clog.AddObjectRef(ctx, m.Spec.Bootstrap.ConfigRef)
+	ctx, log = clog.AddObjectRef(ctx, &m.Spec.InfrastructureRef)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, m.ObjectMeta.Namespace, m.Spec.ClusterName)
 	if err != nil {
@@ -205,27 +253,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
+	// Ensure the Machine does not reference objects in namespaces it is not allowed to, given that external objects
+	// are read, updated and deleted with the permissions of the controller.
+	// NOTE: This applies to deleted Machines too; the allow list must be fixed before they can be deleted.
+	if err := r.validateExternalRefNamespaces(m); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Handle deletion reconciliation loop.
 	if !m.ObjectMeta.DeletionTimestamp.IsZero() {
 		res, err := r.reconcileDelete(ctx, cluster, m)
-		// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
-		// the current cluster because of concurrent access.
-		if errors.Is(err, remote.ErrClusterLocked) {
-			log.V(5).Info("Requeueing because another worker has the lock on the ClusterCacheTracker")
-			return ctrl.Result{Requeue: true}, nil
-		}
-		return res, err
+		// Requeue if the reconcile failed because of a RequeueAfterError, e.g. because the ClusterCacheTracker
+		// was locked for the current cluster because of concurrent access.
+		return utilerrors.ReconcileResult(ctx, res, err)
 	}
 
 	// Handle normal reconciliation loop.
 	res, err := r.reconcile(ctx, cluster, m)
-	// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
-	// the current cluster because of concurrent access.
-	if errors.Is(err, remote.ErrClusterLocked) {
-		log.V(5).Info("Requeueing because another worker has the lock on the ClusterCacheTracker")
-		return ctrl.Result{Requeue: true}, nil
-	}
-	return res, err
+	// Requeue if the reconcile failed because of a RequeueAfterError, e.g. because the ClusterCacheTracker
+	// was locked for the current cluster because of concurrent access.
+	return utilerrors.ReconcileResult(ctx, res, err)
 }
 
 func patchMachine(ctx context.Context, patchHelper *patch.Helper, machine *clusterv1.Machine, options ...patch.Option) error {
@@ -352,7 +399,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 				return ctrl.Result{}, errors.Wrap(err, "failed to patch Machine")
 			}
 
-			if result, err := r.drainNode(ctx, cluster, m.Status.NodeRef.Name); !result.IsZero() || err != nil {
+			if result, err := r.drainNode(ctx, cluster, m); !result.IsZero() || err != nil {
 				if err != nil {
 					conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 					r.recorder.Eventf(m, corev1.EventTypeWarning, "FailedDrainNode", "error draining Machine's node %q: %v", m.Status.NodeRef.Name, err)
@@ -410,12 +457,14 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		return ctrl.Result{}, errors.Wrap(err, "failed to patch Machine")
 	}
 
+	// NOTE: External objects in a different namespace than the Machine are not watched, so the Machine is
+	// requeued to check if they are gone.
 	if ok, err := r.reconcileDeleteInfrastructure(ctx, m); !ok || err != nil {
-		return ctrl.Result{}, err
+		return crossNamespaceRefResult(m, &m.Spec.InfrastructureRef), err
 	}
 
 	if ok, err := r.reconcileDeleteBootstrap(ctx, m); !ok || err != nil {
-		return ctrl.Result{}, err
+		return crossNamespaceRefResult(m, m.Spec.Bootstrap.ConfigRef), err
 	}
 
 	// We only delete the node after the underlying infrastructure is gone.
@@ -443,6 +492,7 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 		}
 	}
 
+	r.recordDeletion(m)
 	controllerutil.RemoveFinalizer(m, clusterv1.MachineFinalizer)
 	return ctrl.Result{}, nil
 }
@@ -572,7 +622,8 @@ func (r *Reconciler) isDeleteNodeAllowed(ctx context.Context, cluster *clusterv1
 	return nil
 }
 
-func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, nodeName string) (ctrl.Result, error) {
+func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
+	nodeName := m.Status.NodeRef.Name
 	log := ctrl.LoggerFrom(ctx, "Node", klog.KRef("", nodeName))
 
 	restConfig, err := remote.RESTConfig(ctx, controllerName, r.Client, util.ObjectKey(cluster))
@@ -631,11 +682,36 @@ func (r *Reconciler) drainNode(ctx context.Context, cluster *clusterv1.Cluster,
 		return ctrl.Result{}, errors.Wrapf(err, "unable to cordon node %v", node.Name)
 	}
 
-	if err := kubedrain.RunNodeDrain(drainer, node.Name); err != nil {
+	podDeleteList, errs := drainer.GetPodsForDeletion(node.Name)
+	if len(errs) > 0 {
 		// Machine will be re-reconciled after a drain failure.
-		log.Error(err, "Drain failed, retry in 20s")
+		log.Error(kerrors.NewAggregate(errs), "Drain failed, retry in 20s")
 		return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
 	}
+	if warnings := podDeleteList.Warnings(); warnings != "" {
+		log.Info(fmt.Sprintf("WARNING: %s", warnings))
+	}
+
+	// Evict pods ordered by priority, lowest first, moving to pods with an higher priority only when
+	// all the pods with a lower priority are gone.
+	for _, pods := range podsByPriority(podDeleteList.Pods()) {
+		if err := drainer.DeleteOrEvictPods(pods); err != nil {
+			// Machine will be re-reconciled after a drain failure.
+			log.Error(err, "Drain failed, retry in 20s")
+
+			// Surface the PodDisruptionBudgets blocking the drain, if any, to make long drains understandable.
+			message, pdbErr := blockingPodDisruptionBudgets(ctx, kubeClient, pods)
+			if pdbErr != nil {
+				log.Error(pdbErr, "Failed to check PodDisruptionBudgets blocking the drain")
+			}
+			if message != "" {
+				setDrainingCondition(m, clusterv1.DrainingBlockedByPodDisruptionBudgetReason, message)
+			} else {
+				setDrainingCondition(m, clusterv1.DrainingReason, "Draining the node before deletion")
+			}
+			return ctrl.Result{RequeueAfter: 20 * time.Second}, nil
+		}
+	}
 
 	log.Info("Drain successful")
 	return ctrl.Result{}, nil
@@ -734,7 +810,7 @@ func (r *Reconciler) reconcileDeleteExternal(ctx context.Context, m *clusterv1.M
 	}
 
 	// get the external object
-	obj, err := external.Get(ctx, r.Client, ref, m.Namespace)
+	obj, err := external.Get(ctx, r.Client, ref, externalRefNamespace(m, ref))
 	if err != nil && !apierrors.IsNotFound(errors.Cause(err)) {
 		return nil, errors.Wrapf(err, "failed to get %s %q for Machine %q in namespace %q",
 			ref.GroupVersionKind(), ref.Name, m.Name, m.Namespace)
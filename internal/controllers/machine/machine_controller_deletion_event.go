@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/deletioncause"
+)
+
+const (
+	// deletionEventReason is the reason of the events recorded when the deletion of a Machine is completed.
+	deletionEventReason = "MachineDeleted"
+
+	// deletionEventNodeNameAnnotation is the annotation of the deletion event with the name of the Node of the Machine.
+	deletionEventNodeNameAnnotation = "machine.cluster.x-k8s.io/node-name"
+
+	// deletionEventProviderIDAnnotation is the annotation of the deletion event with the provider ID of the Machine.
+	deletionEventProviderIDAnnotation = "machine.cluster.x-k8s.io/provider-id"
+
+	// deletionEventLifetimeAnnotation is the annotation of the deletion event with the time elapsed between the creation
+	// and the deletion of the Machine, in seconds.
+	deletionEventLifetimeAnnotation = "machine.cluster.x-k8s.io/lifetime-seconds"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(machinesDeletedTotal)
+}
+
+// machinesDeletedTotal reports the number of Machines deleted.
+var machinesDeletedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Subsystem: "capi_machine",
+	Name:      "deleted_total",
+	Help:      "Number of Machines deleted, partitioned by Cluster, deletion cause and controller which deleted the Machine.",
+}, []string{"namespace", "cluster", "cause", "deleted_by"})
+
+// recordDeletion reports a Machine whose deletion is completed in the capi_machine_deleted_total metric and,
+// if enabled, records a deletion event with structured annotations about why the Machine has been deleted.
+// NOTE: Deletion events are not stored durably; like any other event they are retained only for the event TTL
+// of the kube-apiserver (one hour by default), after which the deletion cause is only reported by the metric.
+func (r *Reconciler) recordDeletion(m *clusterv1.Machine) {
+	cause, deletedBy := deletioncause.Cause(m)
+	machinesDeletedTotal.WithLabelValues(m.Namespace, m.Spec.ClusterName, cause, deletedBy).Inc()
+
+	if !r.RecordDeletionEvents {
+		return
+	}
+
+	eventAnnotations := map[string]string{
+		clusterv1.ClusterLabelName:               m.Spec.ClusterName,
+		clusterv1.MachineDeletionCauseAnnotation: cause,
+		clusterv1.MachineDeletedByAnnotation:     deletedBy,
+		deletionEventLifetimeAnnotation:          lifetimeSeconds(m),
+	}
+	if owner := metav1.GetControllerOf(m); owner != nil {
+		eventAnnotations[clusterv1.OwnerKindAnnotation] = owner.Kind
+		eventAnnotations[clusterv1.OwnerNameAnnotation] = owner.Name
+	}
+	if m.Status.NodeRef != nil {
+		eventAnnotations[deletionEventNodeNameAnnotation] = m.Status.NodeRef.Name
+	}
+	if m.Spec.ProviderID != nil {
+		eventAnnotations[deletionEventProviderIDAnnotation] = *m.Spec.ProviderID
+	}
+
+	if deletedBy == "" {
+		deletedBy = "unknown"
+	}
+	r.recorder.AnnotatedEventf(m, eventAnnotations, corev1.EventTypeNormal, deletionEventReason,
+		"Machine deleted, cause: %s, deleted by: %s", cause, deletedBy)
+}
+
+// lifetimeSeconds returns the time elapsed between the creation and the deletion of a Machine, in seconds.
+func lifetimeSeconds(m *clusterv1.Machine) string {
+	deletionTime := time.Now()
+	if m.DeletionTimestamp != nil {
+		deletionTime = m.DeletionTimestamp.Time
+	}
+	return strconv.Itoa(int(deletionTime.Sub(m.CreationTimestamp.Time).Seconds()))
+}
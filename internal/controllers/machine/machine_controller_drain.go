@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// podsByPriority groups pods by priority, sorted from the lowest to the highest priority, so pods can be
+// evicted in order, e.g. to make room for more important workloads on other nodes before evicting them.
+// Pods without a priority are considered to have the default priority, zero.
+func podsByPriority(pods []corev1.Pod) [][]corev1.Pod {
+	groups := map[int32][]corev1.Pod{}
+	for _, pod := range pods {
+		groups[podPriority(pod)] = append(groups[podPriority(pod)], pod)
+	}
+
+	priorities := make([]int32, 0, len(groups))
+	for priority := range groups {
+		priorities = append(priorities, priority)
+	}
+	sort.Slice(priorities, func(i, j int) bool { return priorities[i] < priorities[j] })
+
+	podGroups := make([][]corev1.Pod, 0, len(priorities))
+	for _, priority := range priorities {
+		podGroups = append(podGroups, groups[priority])
+	}
+	return podGroups
+}
+
+func podPriority(pod corev1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// blockingPodDisruptionBudgets returns a message listing the PodDisruptionBudgets not allowing the eviction of
+// the pods which are not yet evicted, if any.
+func blockingPodDisruptionBudgets(ctx context.Context, kubeClient kubernetes.Interface, pods []corev1.Pod) (string, error) {
+	blockedPods := map[string][]string{}
+	for _, p := range pods {
+		pod, err := kubeClient.CoreV1().Pods(p.Namespace).Get(ctx, p.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", errors.Wrapf(err, "failed to get Pod %s/%s", p.Namespace, p.Name)
+		}
+		if !pod.DeletionTimestamp.IsZero() || pod.UID != p.UID {
+			continue
+		}
+
+		pdbs, err := kubeClient.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to list PodDisruptionBudgets in namespace %s", pod.Namespace)
+		}
+		for _, pdb := range pdbs.Items {
+			if pdb.Status.DisruptionsAllowed > 0 {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+			if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s", pdb.Namespace, pdb.Name)
+			blockedPods[key] = append(blockedPods[key], fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+		}
+	}
+
+	if len(blockedPods) == 0 {
+		return "", nil
+	}
+	keys := make([]string, 0, len(blockedPods))
+	for key := range blockedPods {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("PodDisruptionBudget %s blocks the eviction of Pods %s", key, strings.Join(blockedPods[key], ", ")))
+	}
+	return strings.Join(messages, "; "), nil
+}
+
+// setDrainingCondition sets the DrainingSucceeded condition to false with the given reason and message, preserving
+// its last transition time, which records the first time draining and is used to enforce the NodeDrainTimeout.
+func setDrainingCondition(m *clusterv1.Machine, reason, message string) {
+	condition := conditions.FalseCondition(clusterv1.DrainingSucceededCondition, reason, clusterv1.ConditionSeverityInfo, "%s", message)
+	if lastTransitionTime := conditions.GetLastTransitionTime(m, clusterv1.DrainingSucceededCondition); lastTransitionTime != nil {
+		condition.LastTransitionTime = *lastTransitionTime
+	}
+	conditions.Delete(m, clusterv1.DrainingSucceededCondition)
+	conditions.Set(m, condition)
+}
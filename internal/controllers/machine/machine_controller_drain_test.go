@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	fakeclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestPodsByPriority(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := func(name string, priority *int32) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec:       corev1.PodSpec{Priority: priority},
+		}
+	}
+
+	groups := podsByPriority([]corev1.Pod{
+		pod("critical", pointer.Int32(2000000000)),
+		pod("default-1", nil),
+		pod("low", pointer.Int32(-10)),
+		pod("default-2", pointer.Int32(0)),
+	})
+	g.Expect(groups).To(HaveLen(3))
+	g.Expect(podNames(groups[0])).To(Equal([]string{"low"}))
+	g.Expect(podNames(groups[1])).To(Equal([]string{"default-1", "default-2"}))
+	g.Expect(podNames(groups[2])).To(Equal([]string{"critical"}))
+
+	g.Expect(podsByPriority(nil)).To(BeEmpty())
+}
+
+func TestBlockingPodDisruptionBudgets(t *testing.T) {
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name, UID: types.UID("uid-" + name), Labels: labels}}
+	}
+	pdb := func(name string, selector *metav1.LabelSelector, disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MinAvailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+				Selector:     selector,
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+		}
+	}
+	appSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+
+	deletingPod := pod("deleting", map[string]string{"app": "foo"})
+	deletingPod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deletingPod.Finalizers = []string{"test"}
+
+	tests := []struct {
+		name    string
+		pods    []*corev1.Pod
+		objs    []*policyv1.PodDisruptionBudget
+		want    string
+		evicted []string
+	}{
+		{
+			name: "Pod blocked by a PodDisruptionBudget",
+			pods: []*corev1.Pod{pod("foo-1", map[string]string{"app": "foo"}), pod("foo-2", map[string]string{"app": "foo"}), pod("bar", map[string]string{"app": "bar"})},
+			objs: []*policyv1.PodDisruptionBudget{pdb("foo", appSelector, 0), pdb("bar", &metav1.LabelSelector{MatchLabels: map[string]string{"app": "bar"}}, 1)},
+			want: "PodDisruptionBudget default/foo blocks the eviction of Pods default/foo-1, default/foo-2",
+		},
+		{
+			name: "Pods matched by an empty selector",
+			pods: []*corev1.Pod{pod("foo", map[string]string{"app": "foo"})},
+			objs: []*policyv1.PodDisruptionBudget{pdb("all", &metav1.LabelSelector{}, 0), pdb("none", nil, 0)},
+			want: "PodDisruptionBudget default/all blocks the eviction of Pods default/foo",
+		},
+		{
+			name:    "Pods already evicted or being deleted are ignored",
+			pods:    []*corev1.Pod{pod("foo", map[string]string{"app": "foo"}), deletingPod},
+			objs:    []*policyv1.PodDisruptionBudget{pdb("foo", appSelector, 0)},
+			evicted: []string{"foo"},
+			want:    "",
+		},
+		{
+			name: "No PodDisruptionBudgets",
+			pods: []*corev1.Pod{pod("foo", map[string]string{"app": "foo"})},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kubeClient := fakeclient.NewSimpleClientset()
+			pods := []corev1.Pod{}
+			for _, p := range tt.pods {
+				pods = append(pods, *p)
+				if sets.NewString(tt.evicted...).Has(p.Name) {
+					continue
+				}
+				_, err := kubeClient.CoreV1().Pods(p.Namespace).Create(ctx, p, metav1.CreateOptions{})
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			for _, o := range tt.objs {
+				_, err := kubeClient.PolicyV1().PodDisruptionBudgets(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			got, err := blockingPodDisruptionBudgets(ctx, kubeClient, pods)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSetDrainingCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{}
+	conditions.MarkFalse(m, clusterv1.DrainingSucceededCondition, clusterv1.DrainingReason, clusterv1.ConditionSeverityInfo, "Draining the node before deletion")
+	firstDrain := metav1.NewTime(time.Now().Add(-time.Hour).UTC().Truncate(time.Second))
+	m.Status.Conditions[0].LastTransitionTime = firstDrain
+
+	setDrainingCondition(m, clusterv1.DrainingBlockedByPodDisruptionBudgetReason, "blocked")
+	g.Expect(conditions.GetReason(m, clusterv1.DrainingSucceededCondition)).To(Equal(clusterv1.DrainingBlockedByPodDisruptionBudgetReason))
+	g.Expect(conditions.GetMessage(m, clusterv1.DrainingSucceededCondition)).To(Equal("blocked"))
+	// The last transition time is preserved, so the NodeDrainTimeout is computed from the first time draining.
+	g.Expect(conditions.GetLastTransitionTime(m, clusterv1.DrainingSucceededCondition)).To(Equal(&firstDrain))
+}
+
+func podNames(pods []corev1.Pod) []string {
+	names := []string{}
+	for _, p := range pods {
+		names = append(names, p.Name)
+	}
+	return names
+}
@@ -21,7 +21,6 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -39,7 +38,7 @@ func (r *Reconciler) reconcileInterruptibleNodeLabel(ctx context.Context, cluste
 	}
 
 	// Get the infrastructure object
-	infra, err := external.Get(ctx, r.Client, &machine.Spec.InfrastructureRef, machine.Namespace)
+	infra, err := external.Get(ctx, r.Client, &machine.Spec.InfrastructureRef, externalRefNamespace(machine, &machine.Spec.InfrastructureRef))
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -49,7 +48,7 @@ func (r *Reconciler) reconcileInterruptibleNodeLabel(ctx context.Context, cluste
 	// Get interruptible instance status from the infrastructure provider.
 	interruptible, _, err := unstructured.NestedBool(infra.Object, "status", "interruptible")
 	if err != nil {
-		log.V(1).Error(err, "Failed to get interruptible status from infrastructure provider", "Machine", klog.KObj(machine))
+		log.V(1).Error(err, "Failed to get interruptible status from infrastructure provider")
 		return ctrl.Result{}, nil
 	}
 	if !interruptible {
@@ -19,6 +19,7 @@ package machine
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -46,7 +47,7 @@ func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Clust
 
 	// Check that the Machine has a valid ProviderID.
 	if machine.Spec.ProviderID == nil || *machine.Spec.ProviderID == "" {
-		log.Info("Waiting for infrastructure provider to report spec.providerID", machine.Spec.InfrastructureRef.Kind, klog.KRef(machine.Spec.InfrastructureRef.Namespace, machine.Spec.InfrastructureRef.Name))
+		log.Info("Waiting for infrastructure provider to report spec.providerID")
 		conditions.MarkFalse(machine, clusterv1.MachineNodeHealthyCondition, clusterv1.WaitingForNodeRefReason, clusterv1.ConditionSeverityInfo, "")
 		return ctrl.Result{}, nil
 	}
@@ -88,7 +89,7 @@ func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Clust
 			Name:       node.Name,
 			UID:        node.UID,
 		}
-		log.Info("Infrastructure provider reporting spec.providerID, Kubernetes node is now available", machine.Spec.InfrastructureRef.Kind, klog.KRef(machine.Spec.InfrastructureRef.Namespace, machine.Spec.InfrastructureRef.Name), "providerID", providerID, "node", klog.KRef("", machine.Status.NodeRef.Name))
+		log.Info("Infrastructure provider reporting spec.providerID, Kubernetes node is now available", "providerID", providerID, "Node", klog.KRef("", machine.Status.NodeRef.Name))
 		r.recorder.Event(machine, corev1.EventTypeNormal, "SuccessfulSetNodeRef", machine.Status.NodeRef.Name)
 	}
 
@@ -100,18 +101,24 @@ func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Clust
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	desired := map[string]string{
-		clusterv1.ClusterNameAnnotation:      machine.Spec.ClusterName,
-		clusterv1.ClusterNamespaceAnnotation: machine.GetNamespace(),
-		clusterv1.MachineAnnotation:          machine.Name,
-	}
-	if owner := metav1.GetControllerOfNoCopy(machine); owner != nil {
-		desired[clusterv1.OwnerKindAnnotation] = owner.Kind
-		desired[clusterv1.OwnerNameAnnotation] = owner.Name
+	annotationsChanged := reconcileNodeAnnotations(node, machine)
+
+	// Reconcile node labels, propagating the Machine labels in the ManagedNodeLabelDomain.
+	labelsChanged := false
+	for k, v := range getManagedNodeLabels(machine.Labels) {
+		if current, ok := node.Labels[k]; ok && current == v {
+			continue
+		}
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[k] = v
+		labelsChanged = true
 	}
-	if annotations.AddAnnotations(node, desired) {
+
+	if annotationsChanged || labelsChanged {
 		if err := patchHelper.Patch(ctx, node); err != nil {
-			log.V(2).Info("Failed patch node to set annotations", "err", err, "node name", node.Name)
+			log.V(2).Info("Failed patch node to set annotations and labels", "err", err, "node name", node.Name)
 			return ctrl.Result{}, err
 		}
 	}
@@ -131,6 +138,47 @@ func (r *Reconciler) reconcileNode(ctx context.Context, cluster *clusterv1.Clust
 	return ctrl.Result{}, nil
 }
 
+// getManagedNodeLabels returns the labels to be kept in sync from a Machine to its Node, i.e. the labels
+// in the ManagedNodeLabelDomain or in one of its subdomains.
+func getManagedNodeLabels(machineLabels map[string]string) map[string]string {
+	managed := map[string]string{}
+	for k, v := range machineLabels {
+		domain := strings.Split(k, "/")[0]
+		if domain == clusterv1.ManagedNodeLabelDomain || strings.HasSuffix(domain, "."+clusterv1.ManagedNodeLabelDomain) {
+			managed[k] = v
+		}
+	}
+	return managed
+}
+
+// reconcileNodeAnnotations sets the annotations identifying the Machine, its owner, its MachineDeployment and its Cluster
+// on the Node, so tools running in the workload cluster can map the Node back to the management cluster objects;
+// it returns true if the annotations of the Node have been changed.
+func reconcileNodeAnnotations(node *corev1.Node, machine *clusterv1.Machine) bool {
+	desired := map[string]string{
+		clusterv1.ClusterNameAnnotation:      machine.Spec.ClusterName,
+		clusterv1.ClusterNamespaceAnnotation: machine.GetNamespace(),
+		clusterv1.MachineAnnotation:          machine.Name,
+	}
+	if owner := metav1.GetControllerOfNoCopy(machine); owner != nil {
+		desired[clusterv1.OwnerKindAnnotation] = owner.Kind
+		desired[clusterv1.OwnerNameAnnotation] = owner.Name
+	}
+	if machineDeploymentName, ok := machine.Labels[clusterv1.MachineDeploymentLabelName]; ok {
+		desired[clusterv1.MachineDeploymentAnnotation] = machineDeploymentName
+	}
+	changed := annotations.AddAnnotations(node, desired)
+
+	// Remove the MachineDeployment annotation if the Machine does not belong to a MachineDeployment anymore.
+	if _, ok := desired[clusterv1.MachineDeploymentAnnotation]; !ok {
+		if _, ok := node.Annotations[clusterv1.MachineDeploymentAnnotation]; ok {
+			delete(node.Annotations, clusterv1.MachineDeploymentAnnotation)
+			changed = true
+		}
+	}
+	return changed
+}
+
 // summarizeNodeConditions summarizes a Node's conditions and returns the summary of condition statuses and concatenate failed condition messages:
 // if there is at least 1 semantically-negative condition, summarized status = False;
 // if there is at least 1 semantically-positive condition when there is 0 semantically negative condition, summarized status = True;
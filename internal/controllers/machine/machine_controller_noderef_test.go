@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -227,3 +228,66 @@ func TestSummarizeNodeConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestGetManagedNodeLabels(t *testing.T) {
+	g := NewWithT(t)
+
+	machineLabels := map[string]string{
+		clusterv1.ClusterLabelName:              "cluster-1",
+		"node.cluster.x-k8s.io/pool":            "gpu",
+		"pool.node.cluster.x-k8s.io/name":       "a",
+		"other.node.cluster.x-k8s.io.tld/label": "ignored",
+		"foo":                                   "bar",
+	}
+
+	g.Expect(getManagedNodeLabels(machineLabels)).To(Equal(map[string]string{
+		"node.cluster.x-k8s.io/pool":      "gpu",
+		"pool.node.cluster.x-k8s.io/name": "a",
+	}))
+}
+
+func TestReconcileNodeAnnotations(t *testing.T) {
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "machine-1",
+			Labels:    map[string]string{clusterv1.MachineDeploymentLabelName: "md-1"},
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "MachineSet", Name: "ms-1", Controller: pointer.Bool(true)},
+			},
+		},
+		Spec: clusterv1.MachineSpec{ClusterName: "cluster-1"},
+	}
+	wantAnnotations := map[string]string{
+		"foo":                                 "bar",
+		clusterv1.ClusterNameAnnotation:       "cluster-1",
+		clusterv1.ClusterNamespaceAnnotation:  "ns1",
+		clusterv1.MachineAnnotation:           "machine-1",
+		clusterv1.OwnerKindAnnotation:         "MachineSet",
+		clusterv1.OwnerNameAnnotation:         "ms-1",
+		clusterv1.MachineDeploymentAnnotation: "md-1",
+	}
+
+	t.Run("sets the annotations on the Node", func(t *testing.T) {
+		g := NewWithT(t)
+
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}}}
+		g.Expect(reconcileNodeAnnotations(node, machine)).To(BeTrue())
+		g.Expect(node.Annotations).To(Equal(wantAnnotations))
+
+		// No changes are reported if the annotations are already in sync.
+		g.Expect(reconcileNodeAnnotations(node, machine)).To(BeFalse())
+	})
+
+	t.Run("removes the MachineDeployment annotation if the Machine does not belong to a MachineDeployment", func(t *testing.T) {
+		g := NewWithT(t)
+
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}}}
+		g.Expect(reconcileNodeAnnotations(node, machine)).To(BeTrue())
+
+		standaloneMachine := machine.DeepCopy()
+		standaloneMachine.Labels = nil
+		g.Expect(reconcileNodeAnnotations(node, standaloneMachine)).To(BeTrue())
+		g.Expect(node.Annotations).ToNot(HaveKey(clusterv1.MachineDeploymentAnnotation))
+	})
+}
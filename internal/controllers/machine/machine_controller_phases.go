@@ -19,6 +19,7 @@ package machine
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -27,19 +28,24 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/addresses"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -95,10 +101,10 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 		return external.ReconcileOutput{}, err
 	}
 
-	obj, err := external.Get(ctx, r.Client, ref, m.Namespace)
+	obj, err := external.Get(ctx, r.Client, ref, externalRefNamespace(m, ref))
 	if err != nil {
 		if apierrors.IsNotFound(errors.Cause(err)) {
-			log.Info("could not find external ref, requeueing", ref.Kind, klog.KRef(m.Namespace, ref.Name))
+			log.Info("could not find external ref, requeueing", ref.Kind, klog.KRef(externalRefNamespace(m, ref), ref.Name))
 			return external.ReconcileOutput{RequeueAfter: externalReadyWait}, nil
 		}
 		return external.ReconcileOutput{}, err
@@ -130,8 +136,13 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 	}
 
 	// Set external object ControllerReference to the Machine.
-	if err := controllerutil.SetControllerReference(m, obj, r.Client.Scheme()); err != nil {
-		return external.ReconcileOutput{}, err
+	// NOTE: Owner references can't cross namespaces, so external objects in a different namespace than the Machine
+	// are not owned by the Machine; they are deleted explicitly when the Machine is deleted.
+	crossNamespace := obj.GetNamespace() != m.Namespace
+	if !crossNamespace {
+		if err := controllerutil.SetControllerReference(m, obj, r.Client.Scheme()); err != nil {
+			return external.ReconcileOutput{}, err
+		}
 	}
 
 	// Set the Cluster label.
@@ -148,8 +159,12 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 	}
 
 	// Ensure we add a watcher to the external object.
-	if err := r.externalTracker.Watch(log, obj, &handler.EnqueueRequestForOwner{OwnerType: &clusterv1.Machine{}}); err != nil {
-		return external.ReconcileOutput{}, err
+	// NOTE: External objects in a different namespace than the Machine can't be mapped to the Machine via owner
+	// references, so changes to those objects are picked up when the Machine is requeued or resynced.
+	if !crossNamespace {
+		if err := r.externalTracker.Watch(log, obj, &handler.EnqueueRequestForOwner{OwnerType: &clusterv1.Machine{}}); err != nil {
+			return external.ReconcileOutput{}, err
+		}
 	}
 
 	// Set failure reason and message, if any.
@@ -171,6 +186,66 @@ func (r *Reconciler) reconcileExternal(ctx context.Context, cluster *clusterv1.C
 	return external.ReconcileOutput{Result: obj}, nil
 }
 
+// externalRefNamespace returns the namespace of an object referenced by a Machine; objects are in the same
+// namespace as the Machine unless the reference sets a different namespace, which is allowed only if the
+// MachineCrossNamespaceRefs feature flag is enabled and the namespace is in the cross-namespace references allow list.
+func externalRefNamespace(m *clusterv1.Machine, ref *corev1.ObjectReference) string {
+	if ref.Namespace != "" {
+		return ref.Namespace
+	}
+	return m.Namespace
+}
+
+// parseCrossNamespaceRefsAllowList parses a list of <Machine namespace>=<referenced namespace> pairs into a map
+// from each Machine namespace to the namespaces Machines can reference.
+func parseCrossNamespaceRefsAllowList(allowList []string) (map[string]sets.String, error) {
+	allowed := map[string]sets.String{}
+	for _, entry := range allowList {
+		namespace, refNamespace, ok := strings.Cut(entry, "=")
+		if !ok || namespace == "" || refNamespace == "" {
+			return nil, errors.Errorf("invalid cross-namespace references allow list entry %q: must be <Machine namespace>=<referenced namespace>", entry)
+		}
+		if _, ok := allowed[namespace]; !ok {
+			allowed[namespace] = sets.NewString()
+		}
+		allowed[namespace].Insert(refNamespace)
+	}
+	return allowed, nil
+}
+
+// validateExternalRefNamespaces returns an error if a Machine references a bootstrap config or an infrastructure
+// machine in a different namespace than the Machine, unless the MachineCrossNamespaceRefs feature flag is enabled
+// and the namespace is allowed for Machines in the namespace of the Machine.
+func (r *Reconciler) validateExternalRefNamespaces(m *clusterv1.Machine) error {
+	for _, ref := range []*corev1.ObjectReference{m.Spec.Bootstrap.ConfigRef, &m.Spec.InfrastructureRef} {
+		if ref == nil {
+			continue
+		}
+		namespace := externalRefNamespace(m, ref)
+		if namespace == m.Namespace {
+			continue
+		}
+		if !feature.Gates.Enabled(feature.MachineCrossNamespaceRefs) {
+			return errors.Errorf("%s %s is in a different namespace than the Machine, and the MachineCrossNamespaceRefs feature flag is disabled",
+				ref.Kind, klog.KRef(namespace, ref.Name))
+		}
+		if !r.crossNamespaceRefsAllowed[m.Namespace].Has(namespace) {
+			return errors.Errorf("%s %s is in namespace %q, which is not allowed for Machines in namespace %q by the cross-namespace references allow list",
+				ref.Kind, klog.KRef(namespace, ref.Name), namespace, m.Namespace)
+		}
+	}
+	return nil
+}
+
+// crossNamespaceRefResult returns a result requeueing the Machine if the given object is in a different namespace
+// than the Machine, given that changes to those objects do not trigger the reconciliation of the Machine.
+func crossNamespaceRefResult(m *clusterv1.Machine, ref *corev1.ObjectReference) ctrl.Result {
+	if ref == nil || externalRefNamespace(m, ref) == m.Namespace {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: externalReadyWait}
+}
+
 // reconcileBootstrap reconciles the Spec.Bootstrap.ConfigRef object on a Machine.
 func (r *Reconciler) reconcileBootstrap(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -222,7 +297,7 @@ func (r *Reconciler) reconcileBootstrap(ctx context.Context, cluster *clusterv1.
 
 	// If the bootstrap provider is not ready, requeue.
 	if !ready {
-		log.Info("Waiting for bootstrap provider to generate data secret and report status.ready", bootstrapConfig.GetKind(), klog.KObj(bootstrapConfig))
+		log.Info("Waiting for bootstrap provider to generate data secret and report status.ready")
 		return ctrl.Result{RequeueAfter: externalReadyWait}, nil
 	}
 
@@ -233,14 +308,72 @@ func (r *Reconciler) reconcileBootstrap(ctx context.Context, cluster *clusterv1.
 	} else if secretName == "" {
 		return ctrl.Result{}, errors.Errorf("retrieved empty dataSecretName from bootstrap provider for Machine %q in namespace %q", m.Name, m.Namespace)
 	}
+
+	// Check that the bootstrap data has the format expected by the infrastructure provider; if not, the data secret
+	// is not handed over to the infrastructure provider, so machines are not created with bootstrap data they can't use.
+	message, err := r.validateBootstrapDataFormat(ctx, m, secretName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if message != "" {
+		log.Info("Bootstrap data has an unexpected format", "reason", message)
+		conditions.MarkFalse(m, clusterv1.BootstrapReadyCondition, clusterv1.BootstrapDataFormatMismatchReason, clusterv1.ConditionSeverityError, message)
+		return ctrl.Result{}, nil
+	}
+
 	m.Spec.Bootstrap.DataSecretName = pointer.String(secretName)
 	if !m.Status.BootstrapReady {
-		log.Info("Bootstrap provider generated data secret and reports status.ready", bootstrapConfig.GetKind(), klog.KObj(bootstrapConfig), "Secret", klog.KRef(m.Namespace, secretName))
+		log.Info("Bootstrap provider generated data secret and reports status.ready", "Secret", klog.KRef(m.Namespace, secretName))
 	}
 	m.Status.BootstrapReady = true
 	return ctrl.Result{}, nil
 }
 
+// validateBootstrapDataFormat checks the format reported in the bootstrap data secret, if any; the format must be
+// one of the formats defined by the bootstrap contract and, if the InfrastructureMachine reports the format expected
+// by the machine image in status.bootstrapDataFormat, the two formats must match.
+// The returned message is empty if the format is valid.
+func (r *Reconciler) validateBootstrapDataFormat(ctx context.Context, m *clusterv1.Machine, secretName string) (string, error) {
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: secretName}, secret); err != nil {
+		// NOTE: If the secret can't be found there is nothing to check; the infrastructure provider is going to report the problem.
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get bootstrap data secret %s", klog.KRef(m.Namespace, secretName))
+	}
+
+	// If the bootstrap provider does not report the format, there is nothing to check.
+	format := clusterv1.BootstrapDataFormat(secret.Data[clusterv1.BootstrapDataSecretFormatKey])
+	if format == "" {
+		return "", nil
+	}
+	if !format.IsValid() {
+		return fmt.Sprintf("Bootstrap data secret %s has an invalid format %q", secretName, format), nil
+	}
+
+	if m.Spec.InfrastructureRef.Name == "" {
+		return "", nil
+	}
+	infraMachine, err := external.Get(ctx, r.Client, &m.Spec.InfrastructureRef, externalRefNamespace(m, &m.Spec.InfrastructureRef))
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return "", nil
+		}
+		return "", err
+	}
+	// NOTE: status.bootstrapDataFormat is optional, so there is nothing to check if it is not set.
+	expectedFormat, _, err := unstructured.NestedString(infraMachine.Object, "status", "bootstrapDataFormat")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve bootstrapDataFormat from %s %s", infraMachine.GetKind(), klog.KObj(infraMachine))
+	}
+	if expectedFormat != "" && clusterv1.BootstrapDataFormat(expectedFormat) != format {
+		return fmt.Sprintf("Bootstrap data secret %s has format %q, while %s %s expects format %q",
+			secretName, format, infraMachine.GetKind(), infraMachine.GetName(), expectedFormat), nil
+	}
+	return "", nil
+}
+
 // reconcileInfrastructure reconciles the Spec.InfrastructureRef object on a Machine.
 func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -257,7 +390,9 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 			m.Status.FailureReason = capierrors.MachineStatusErrorPtr(capierrors.InvalidConfigurationMachineError)
 			m.Status.FailureMessage = pointer.String(fmt.Sprintf("Machine infrastructure resource %v with name %q has been deleted after being ready",
 				m.Spec.InfrastructureRef.GroupVersionKind(), m.Spec.InfrastructureRef.Name))
-			return ctrl.Result{}, errors.Errorf("could not find %v %q for Machine %q in namespace %q, requeueing", m.Spec.InfrastructureRef.GroupVersionKind().String(), m.Spec.InfrastructureRef.Name, m.Name, m.Namespace)
+			// NOTE: This is a terminal error, given that retrying the reconcile won't bring back the infrastructure.
+			return ctrl.Result{}, utilerrors.NewTerminalError(string(capierrors.InvalidConfigurationMachineError),
+				errors.Errorf("could not find %v %q for Machine %q in namespace %q", m.Spec.InfrastructureRef.GroupVersionKind().String(), m.Spec.InfrastructureRef.Name, m.Name, m.Namespace))
 		}
 		return ctrl.Result{RequeueAfter: infraReconcileResult.RequeueAfter}, nil
 	}
@@ -271,15 +406,22 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 		return ctrl.Result{}, nil
 	}
 
+	// Revalidate the Machine status if the underlying instance has been replaced or restarted outside of Cluster API.
+	r.reconcileInstanceRefresh(ctx, m, infraConfig)
+
+	// Hand over reboot requests to the infrastructure provider, and clear them once acknowledged.
+	if err := r.reconcileReboot(ctx, m, infraConfig); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Determine if the infrastructure provider is ready.
 	ready, err := external.IsReady(infraConfig)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	if ready && !m.Status.InfrastructureReady {
-		log.Info("Infrastructure provider has completed machine infrastructure provisioning and reports status.ready", infraConfig.GetKind(), klog.KObj(infraConfig))
+		log.Info("Infrastructure provider has completed machine infrastructure provisioning and reports status.ready")
 	}
-	m.Status.InfrastructureReady = ready
 
 	// Report a summary of current status of the infrastructure object defined for this machine.
 	conditions.SetMirror(m, clusterv1.InfrastructureReadyCondition,
@@ -287,9 +429,19 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 		conditions.WithFallbackValue(ready, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
 	)
 
+	// If the infrastructure provider is ready, but the machine is not yet marked as InfrastructureReady,
+	// run the additional infrastructure readiness checks, if any.
+	if ready && !m.Status.InfrastructureReady {
+		ready, err = r.runInfrastructureReadinessCheckers(ctx, cluster, m, infraConfig)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	m.Status.InfrastructureReady = ready
+
 	// If the infrastructure provider is not ready, return early.
 	if !ready {
-		log.Info("Waiting for infrastructure provider to create machine infrastructure and report status.ready", infraConfig.GetKind(), klog.KObj(infraConfig))
+		log.Info("Waiting for infrastructure provider to create machine infrastructure and report status.ready")
 		return ctrl.Result{RequeueAfter: externalReadyWait}, nil
 	}
 
@@ -301,11 +453,13 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 		return ctrl.Result{}, errors.Errorf("retrieved empty Spec.ProviderID from infrastructure provider for Machine %q in namespace %q", m.Name, m.Namespace)
 	}
 
-	// Get and set Status.Addresses from the infrastructure provider.
+	// Get and set Status.Addresses from the infrastructure provider; addresses are normalized, so they are reported
+	// consistently, including the order of dual-stack addresses, regardless of the infrastructure provider.
 	err = util.UnstructuredUnmarshalField(infraConfig, &m.Status.Addresses, "status", "addresses")
 	if err != nil && err != util.ErrUnstructuredFieldNotFound {
 		return ctrl.Result{}, errors.Wrapf(err, "failed to retrieve addresses from infrastructure provider for Machine %q in namespace %q", m.Name, m.Namespace)
 	}
+	m.Status.Addresses = addresses.Normalize(m.Status.Addresses)
 
 	// Get and set the failure domain from the infrastructure provider.
 	var failureDomain string
@@ -322,6 +476,102 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, cluster *clust
 	return ctrl.Result{}, nil
 }
 
+// runInfrastructureReadinessCheckers runs the infrastructure readiness checkers of the Machine controller, and returns
+// false if any of them reports the infrastructure of the Machine is not ready yet; in this case the InfrastructureReady
+// condition is set to false with the message of the first failing checker.
+func (r *Reconciler) runInfrastructureReadinessCheckers(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine, infraConfig *unstructured.Unstructured) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	for _, checker := range r.InfrastructureReadinessCheckers {
+		ready, message, err := checker.IsReady(ctx, cluster, m, infraConfig)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to run infrastructure readiness checker %q for Machine %q in namespace %q", checker.Name(), m.Name, m.Namespace)
+		}
+		if !ready {
+			log.Info("Waiting for infrastructure readiness checker to pass", "checker", checker.Name(), "message", message)
+			conditions.MarkFalse(m, clusterv1.InfrastructureReadyCondition, clusterv1.WaitingForInfrastructureReadinessChecksReason, clusterv1.ConditionSeverityInfo,
+				"Waiting for infrastructure readiness check %s: %s", checker.Name(), message)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// reconcileInstanceRefresh resets the status derived from the underlying instance when the infrastructure provider
+// reports, via the InstanceRefreshedAnnotation, that the instance has been replaced or restarted outside of Cluster API.
+// NodeRef and NodeInfo are then re-computed by reconcileNode, while ProviderID and Addresses are re-read
+// from the InfraMachine by reconcileInfrastructure.
+func (r *Reconciler) reconcileInstanceRefresh(ctx context.Context, m *clusterv1.Machine, infraConfig *unstructured.Unstructured) {
+	log := ctrl.LoggerFrom(ctx)
+
+	refresh, ok := infraConfig.GetAnnotations()[clusterv1.InstanceRefreshedAnnotation]
+	if !ok || refresh == m.GetAnnotations()[clusterv1.ObservedInstanceRefreshAnnotation] {
+		return
+	}
+
+	log.Info("Infrastructure provider reported the instance has been replaced or restarted, revalidating Machine status")
+	r.recorder.Eventf(m, corev1.EventTypeNormal, "InstanceRefreshed", "Infrastructure provider reported the instance has been replaced or restarted (%s)", refresh)
+
+	m.Status.NodeRef = nil
+	m.Status.NodeInfo = nil
+	m.Status.Addresses = nil
+	conditions.MarkUnknown(m, clusterv1.MachineNodeHealthyCondition, clusterv1.InstanceRefreshedReason, "Revalidating the Node after the instance has been replaced or restarted")
+	annotations.AddAnnotations(m, map[string]string{clusterv1.ObservedInstanceRefreshAnnotation: refresh})
+}
+
+// reconcileReboot copies the MachineRebootRequestedAnnotation of the Machine to the InfraMachine, so the infrastructure
+// provider can reboot the underlying instance, and removes it from both once the provider acknowledges the reboot by
+// setting the MachineRebootAcknowledgedAnnotation with the same value. While the reboot is pending, the
+// RebootCompleted condition is set to false.
+func (r *Reconciler) reconcileReboot(ctx context.Context, m *clusterv1.Machine, infraConfig *unstructured.Unstructured) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	requested := m.GetAnnotations()[clusterv1.MachineRebootRequestedAnnotation]
+	infraRequested, hasInfraRequested := infraConfig.GetAnnotations()[clusterv1.MachineRebootRequestedAnnotation]
+	acknowledged := requested != "" && infraConfig.GetAnnotations()[clusterv1.MachineRebootAcknowledgedAnnotation] == requested
+
+	// Nothing to do if there is no reboot request, or if the pending request has already been handed over.
+	if requested == "" && !hasInfraRequested {
+		conditions.Delete(m, clusterv1.MachineRebootCompletedCondition)
+		return nil
+	}
+	if requested != "" && !acknowledged && infraRequested == requested {
+		conditions.MarkFalse(m, clusterv1.MachineRebootCompletedCondition, clusterv1.WaitingForRebootReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for infrastructure provider to reboot the instance")
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(infraConfig, r.Client)
+	if err != nil {
+		return err
+	}
+	switch {
+	case requested == "":
+		// The reboot request has been removed from the Machine before being acknowledged, remove it from the InfraMachine too.
+		infraAnnotations := infraConfig.GetAnnotations()
+		delete(infraAnnotations, clusterv1.MachineRebootRequestedAnnotation)
+		infraConfig.SetAnnotations(infraAnnotations)
+		conditions.Delete(m, clusterv1.MachineRebootCompletedCondition)
+	case acknowledged:
+		log.Info("Infrastructure provider acknowledged the reboot of the instance")
+		r.recorder.Eventf(m, corev1.EventTypeNormal, "Rebooted", "Infrastructure provider acknowledged the reboot of the instance (%s)", requested)
+		infraAnnotations := infraConfig.GetAnnotations()
+		delete(infraAnnotations, clusterv1.MachineRebootRequestedAnnotation)
+		infraConfig.SetAnnotations(infraAnnotations)
+		delete(m.Annotations, clusterv1.MachineRebootRequestedAnnotation)
+		conditions.Delete(m, clusterv1.MachineRebootCompletedCondition)
+	default:
+		log.Info("Requesting the reboot of the instance to the infrastructure provider")
+		annotations.AddAnnotations(infraConfig, map[string]string{clusterv1.MachineRebootRequestedAnnotation: requested})
+		conditions.MarkFalse(m, clusterv1.MachineRebootCompletedCondition, clusterv1.WaitingForRebootReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for infrastructure provider to reboot the instance")
+	}
+	if err := patchHelper.Patch(ctx, infraConfig); err != nil {
+		return errors.Wrapf(err, "failed to patch %s %s", infraConfig.GetKind(), klog.KObj(infraConfig))
+	}
+	return nil
+}
+
 func (r *Reconciler) reconcileCertificateExpiry(ctx context.Context, _ *clusterv1.Cluster, m *clusterv1.Machine) (ctrl.Result, error) {
 	var annotations map[string]string
 
@@ -346,7 +596,7 @@ func (r *Reconciler) reconcileCertificateExpiry(ctx context.Context, _ *clusterv
 	} else if m.Spec.Bootstrap.ConfigRef != nil {
 		// If the expiry information is not available on the machine annotation
 		// look for it on the bootstrap config.
-		bootstrapConfig, err := external.Get(ctx, r.Client, m.Spec.Bootstrap.ConfigRef, m.Namespace)
+		bootstrapConfig, err := external.Get(ctx, r.Client, m.Spec.Bootstrap.ConfigRef, externalRefNamespace(m, m.Spec.Bootstrap.ConfigRef))
 		if err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to reconcile certificates expiry")
 		}
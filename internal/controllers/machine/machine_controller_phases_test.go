@@ -17,6 +17,7 @@ limitations under the License.
 package machine
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -36,9 +38,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/readiness"
 	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	"sigs.k8s.io/cluster-api/util/kubeconfig"
 )
 
@@ -617,10 +622,62 @@ func TestReconcileBootstrap(t *testing.T) {
 		},
 	}
 
+	readyBootstrapConfig := func() map[string]interface{} {
+		return map[string]interface{}{
+			"kind":       "GenericBootstrapConfig",
+			"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "bootstrap-config1",
+				"namespace": metav1.NamespaceDefault,
+			},
+			"spec": map[string]interface{}{},
+			"status": map[string]interface{}{
+				"ready":          true,
+				"dataSecretName": "secret-data",
+			},
+		}
+	}
+
+	bootstrapDataSecret := func(format string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret-data",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Data: map[string][]byte{
+				"value":                                []byte("data"),
+				clusterv1.BootstrapDataSecretFormatKey: []byte(format),
+			},
+		}
+	}
+
+	infraMachine := func(format string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":       "GenericInfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "infra-config1",
+				"namespace": metav1.NamespaceDefault,
+			},
+			"spec": map[string]interface{}{},
+			"status": map[string]interface{}{
+				"bootstrapDataFormat": format,
+			},
+		}}
+	}
+
+	machineWithInfrastructureRef := defaultMachine.DeepCopy()
+	machineWithInfrastructureRef.Spec.InfrastructureRef = corev1.ObjectReference{
+		APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+		Kind:       "GenericInfrastructureMachine",
+		Name:       "infra-config1",
+	}
+
 	testCases := []struct {
 		name            string
 		bootstrapConfig map[string]interface{}
 		machine         *clusterv1.Machine
+		objects         []client.Object
 		expectResult    ctrl.Result
 		expectError     bool
 		expected        func(g *WithT, m *clusterv1.Machine)
@@ -648,6 +705,44 @@ func TestReconcileBootstrap(t *testing.T) {
 				g.Expect(*m.Spec.Bootstrap.DataSecretName).To(ContainSubstring("secret-data"))
 			},
 		},
+		{
+			name:            "new machine, bootstrap data format matching the infrastructure machine",
+			bootstrapConfig: readyBootstrapConfig(),
+			machine:         machineWithInfrastructureRef.DeepCopy(),
+			objects:         []client.Object{bootstrapDataSecret("ignition"), infraMachine("ignition")},
+			expectResult:    ctrl.Result{},
+			expectError:     false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.BootstrapReady).To(BeTrue())
+				g.Expect(m.Spec.Bootstrap.DataSecretName).To(Equal(pointer.String("secret-data")))
+			},
+		},
+		{
+			name:            "new machine, bootstrap data format not matching the infrastructure machine",
+			bootstrapConfig: readyBootstrapConfig(),
+			machine:         machineWithInfrastructureRef.DeepCopy(),
+			objects:         []client.Object{bootstrapDataSecret("cloud-config"), infraMachine("ignition")},
+			expectResult:    ctrl.Result{},
+			expectError:     false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.BootstrapReady).To(BeFalse())
+				g.Expect(m.Spec.Bootstrap.DataSecretName).To(BeNil())
+				g.Expect(conditions.IsFalse(m, clusterv1.BootstrapReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(m, clusterv1.BootstrapReadyCondition)).To(Equal(clusterv1.BootstrapDataFormatMismatchReason))
+			},
+		},
+		{
+			name:            "new machine, bootstrap data with an invalid format",
+			bootstrapConfig: readyBootstrapConfig(),
+			objects:         []client.Object{bootstrapDataSecret("yaml")},
+			expectResult:    ctrl.Result{},
+			expectError:     false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.BootstrapReady).To(BeFalse())
+				g.Expect(m.Spec.Bootstrap.DataSecretName).To(BeNil())
+				g.Expect(conditions.GetReason(m, clusterv1.BootstrapReadyCondition)).To(Equal(clusterv1.BootstrapDataFormatMismatchReason))
+			},
+		},
 		{
 			name: "new machine, bootstrap config ready with no data",
 			bootstrapConfig: map[string]interface{}{
@@ -872,7 +967,9 @@ func TestReconcileBootstrap(t *testing.T) {
 						builder.GenericBootstrapConfigCRD.DeepCopy(),
 						builder.GenericInfrastructureMachineCRD.DeepCopy(),
 						bootstrapConfig,
-					).Build(),
+					).
+					WithObjects(tc.objects...).
+					Build(),
 			}
 
 			res, err := r.reconcileBootstrap(ctx, defaultCluster, tc.machine)
@@ -923,14 +1020,16 @@ func TestReconcileInfrastructure(t *testing.T) {
 	}
 
 	testCases := []struct {
-		name            string
-		bootstrapConfig map[string]interface{}
-		infraConfig     map[string]interface{}
-		machine         *clusterv1.Machine
-		expectResult    ctrl.Result
-		expectError     bool
-		expectChanged   bool
-		expected        func(g *WithT, m *clusterv1.Machine)
+		name                string
+		bootstrapConfig     map[string]interface{}
+		infraConfig         map[string]interface{}
+		machine             *clusterv1.Machine
+		checkers            []readiness.InfrastructureChecker
+		expectResult        ctrl.Result
+		expectError         bool
+		expectTerminalError bool
+		expectChanged       bool
+		expected            func(g *WithT, m *clusterv1.Machine)
 	}{
 		{
 			name: "new machine, infrastructure config ready",
@@ -975,6 +1074,63 @@ func TestReconcileInfrastructure(t *testing.T) {
 				g.Expect(m.GetOwnerReferences()).NotTo(ContainRefOfGroupKind("cluster.x-k8s.io", "MachineSet"))
 			},
 		},
+		{
+			name: "new machine, infrastructure config ready, readiness checker not passing",
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+				},
+			},
+			checkers: []readiness.InfrastructureChecker{
+				fakeReadinessChecker{name: "passing", ready: true},
+				fakeReadinessChecker{name: "health-checks", message: "instance health checks pending"},
+			},
+			expectResult: ctrl.Result{RequeueAfter: externalReadyWait},
+			expectError:  false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeFalse())
+				g.Expect(m.Spec.ProviderID).To(BeNil())
+				g.Expect(conditions.IsFalse(m, clusterv1.InfrastructureReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(m, clusterv1.InfrastructureReadyCondition)).To(Equal(clusterv1.WaitingForInfrastructureReadinessChecksReason))
+				g.Expect(conditions.GetMessage(m, clusterv1.InfrastructureReadyCondition)).To(ContainSubstring("instance health checks pending"))
+			},
+		},
+		{
+			name: "new machine, infrastructure config ready, readiness checkers passing",
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+				},
+			},
+			checkers: []readiness.InfrastructureChecker{
+				fakeReadinessChecker{name: "health-checks", ready: true},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.InfrastructureReady).To(BeTrue())
+				g.Expect(*m.Spec.ProviderID).To(Equal("test://id-1"))
+			},
+		},
 		{
 			name: "ready bootstrap, infra, and nodeRef, machine is running, infra object is deleted, expect failed",
 			machine: &clusterv1.Machine{
@@ -1020,8 +1176,9 @@ func TestReconcileInfrastructure(t *testing.T) {
 				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
 				"metadata":   map[string]interface{}{},
 			},
-			expectResult: ctrl.Result{},
-			expectError:  true,
+			expectResult:        ctrl.Result{},
+			expectError:         true,
+			expectTerminalError: true,
 			expected: func(g *WithT, m *clusterv1.Machine) {
 				g.Expect(m.Status.InfrastructureReady).To(BeTrue())
 				g.Expect(m.Status.FailureMessage).NotTo(BeNil())
@@ -1029,6 +1186,106 @@ func TestReconcileInfrastructure(t *testing.T) {
 				g.Expect(m.Status.GetTypedPhase()).To(Equal(clusterv1.MachinePhaseFailed))
 			},
 		},
+		{
+			name: "infrastructure reports the instance has been refreshed, expect machine status to be revalidated",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine-test",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: clusterv1.MachineSpec{
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "GenericInfrastructureMachine",
+						Name:       "infra-config1",
+					},
+					ProviderID: pointer.String("test://id-1"),
+				},
+				Status: clusterv1.MachineStatus{
+					InfrastructureReady: true,
+					NodeRef:             &corev1.ObjectReference{Kind: "Node", Name: "machine-test-node"},
+					NodeInfo:            &corev1.NodeSystemInfo{},
+					Addresses: clusterv1.MachineAddresses{
+						{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+					},
+				},
+			},
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+					"annotations": map[string]interface{}{
+						clusterv1.InstanceRefreshedAnnotation: "2022-01-01T00:00:00Z",
+					},
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-2",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+				},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: true,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.NodeRef).To(BeNil())
+				g.Expect(m.Status.NodeInfo).To(BeNil())
+				g.Expect(m.Status.Addresses).To(BeEmpty())
+				g.Expect(*m.Spec.ProviderID).To(Equal("test://id-2"))
+				g.Expect(conditions.GetReason(m, clusterv1.MachineNodeHealthyCondition)).To(Equal(clusterv1.InstanceRefreshedReason))
+				g.Expect(m.Annotations).To(HaveKeyWithValue(clusterv1.ObservedInstanceRefreshAnnotation, "2022-01-01T00:00:00Z"))
+			},
+		},
+		{
+			name: "infrastructure reports an instance refresh which has already been observed, expect machine status to be preserved",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine-test",
+					Namespace: metav1.NamespaceDefault,
+					Annotations: map[string]string{
+						clusterv1.ObservedInstanceRefreshAnnotation: "2022-01-01T00:00:00Z",
+					},
+				},
+				Spec: clusterv1.MachineSpec{
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "GenericInfrastructureMachine",
+						Name:       "infra-config1",
+					},
+				},
+				Status: clusterv1.MachineStatus{
+					InfrastructureReady: true,
+					NodeRef:             &corev1.ObjectReference{Kind: "Node", Name: "machine-test-node"},
+				},
+			},
+			infraConfig: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+					"annotations": map[string]interface{}{
+						clusterv1.InstanceRefreshedAnnotation: "2022-01-01T00:00:00Z",
+					},
+				},
+				"spec": map[string]interface{}{
+					"providerID": "test://id-1",
+				},
+				"status": map[string]interface{}{
+					"ready": true,
+				},
+			},
+			expectResult:  ctrl.Result{},
+			expectError:   false,
+			expectChanged: false,
+			expected: func(g *WithT, m *clusterv1.Machine) {
+				g.Expect(m.Status.NodeRef).NotTo(BeNil())
+				g.Expect(conditions.Has(m, clusterv1.MachineNodeHealthyCondition)).To(BeFalse())
+			},
+		},
 		{
 			name: "infrastructure ref is paused",
 			infraConfig: map[string]interface{}{
@@ -1083,6 +1340,8 @@ func TestReconcileInfrastructure(t *testing.T) {
 						builder.GenericInfrastructureMachineCRD.DeepCopy(),
 						infraConfig,
 					).Build(),
+				recorder:                        record.NewFakeRecorder(32),
+				InfrastructureReadinessCheckers: tc.checkers,
 			}
 
 			result, err := r.reconcileInfrastructure(ctx, defaultCluster, tc.machine)
@@ -1090,6 +1349,7 @@ func TestReconcileInfrastructure(t *testing.T) {
 			g.Expect(result).To(Equal(tc.expectResult))
 			if tc.expectError {
 				g.Expect(err).NotTo(BeNil())
+				g.Expect(utilerrors.IsTerminal(err)).To(Equal(tc.expectTerminalError))
 			} else {
 				g.Expect(err).To(BeNil())
 			}
@@ -1101,6 +1361,268 @@ func TestReconcileInfrastructure(t *testing.T) {
 	}
 }
 
+type fakeReadinessChecker struct {
+	name    string
+	ready   bool
+	message string
+}
+
+func (f fakeReadinessChecker) Name() string {
+	return f.name
+}
+
+func (f fakeReadinessChecker) IsReady(_ context.Context, _ *clusterv1.Cluster, _ *clusterv1.Machine, _ *unstructured.Unstructured) (bool, string, error) {
+	return f.ready, f.message, nil
+}
+
+func TestReconcileExternalCrossNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "GenericInfrastructureMachine",
+				Name:       "infra-config1",
+				Namespace:  "infra-namespace",
+			},
+		},
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	infraConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind":       "GenericInfrastructureMachine",
+		"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+		"metadata": map[string]interface{}{
+			"name":      "infra-config1",
+			"namespace": "infra-namespace",
+		},
+	}}
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().
+			WithObjects(machine,
+				builder.GenericInfrastructureMachineCRD.DeepCopy(),
+				infraConfig,
+			).Build(),
+	}
+
+	res, err := r.reconcileExternal(ctx, cluster, machine, &machine.Spec.InfrastructureRef)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.Result).ToNot(BeNil())
+
+	// Objects in a different namespace than the Machine are not owned by the Machine, given that owner references
+	// can't cross namespaces.
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(infraConfig), infraConfig)).To(Succeed())
+	g.Expect(infraConfig.GetOwnerReferences()).To(BeEmpty())
+	g.Expect(infraConfig.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterLabelName, "test-cluster"))
+
+	// Machines are requeued while waiting for objects in a different namespace.
+	g.Expect(crossNamespaceRefResult(machine, &machine.Spec.InfrastructureRef)).To(Equal(ctrl.Result{RequeueAfter: externalReadyWait}))
+	g.Expect(crossNamespaceRefResult(machine, &corev1.ObjectReference{Namespace: metav1.NamespaceDefault})).To(Equal(ctrl.Result{}))
+	g.Expect(crossNamespaceRefResult(machine, nil)).To(Equal(ctrl.Result{}))
+}
+
+func TestReconcileReboot(t *testing.T) {
+	tests := []struct {
+		name                    string
+		machineAnnotations      map[string]string
+		infraAnnotations        map[string]string
+		wantMachineAnnotations  map[string]string
+		wantInfraAnnotations    map[string]string
+		wantRebootCompletedCond bool
+	}{
+		{
+			name:                    "No reboot request",
+			wantRebootCompletedCond: false,
+		},
+		{
+			name:                    "Reboot request is copied to the InfraMachine",
+			machineAnnotations:      map[string]string{clusterv1.MachineRebootRequestedAnnotation: "2022-01-01T00:00:00Z"},
+			wantMachineAnnotations:  map[string]string{clusterv1.MachineRebootRequestedAnnotation: "2022-01-01T00:00:00Z"},
+			wantInfraAnnotations:    map[string]string{clusterv1.MachineRebootRequestedAnnotation: "2022-01-01T00:00:00Z"},
+			wantRebootCompletedCond: true,
+		},
+		{
+			name:               "New reboot request replaces an acknowledged one",
+			machineAnnotations: map[string]string{clusterv1.MachineRebootRequestedAnnotation: "2022-01-02T00:00:00Z"},
+			infraAnnotations:   map[string]string{clusterv1.MachineRebootAcknowledgedAnnotation: "2022-01-01T00:00:00Z"},
+			wantMachineAnnotations: map[string]string{
+				clusterv1.MachineRebootRequestedAnnotation: "2022-01-02T00:00:00Z",
+			},
+			wantInfraAnnotations: map[string]string{
+				clusterv1.MachineRebootRequestedAnnotation:    "2022-01-02T00:00:00Z",
+				clusterv1.MachineRebootAcknowledgedAnnotation: "2022-01-01T00:00:00Z",
+			},
+			wantRebootCompletedCond: true,
+		},
+		{
+			name:               "Acknowledged reboot request is removed",
+			machineAnnotations: map[string]string{clusterv1.MachineRebootRequestedAnnotation: "2022-01-01T00:00:00Z"},
+			infraAnnotations: map[string]string{
+				clusterv1.MachineRebootRequestedAnnotation:    "2022-01-01T00:00:00Z",
+				clusterv1.MachineRebootAcknowledgedAnnotation: "2022-01-01T00:00:00Z",
+			},
+			wantInfraAnnotations:    map[string]string{clusterv1.MachineRebootAcknowledgedAnnotation: "2022-01-01T00:00:00Z"},
+			wantRebootCompletedCond: false,
+		},
+		{
+			name:                    "Reboot request removed from the Machine is removed from the InfraMachine",
+			infraAnnotations:        map[string]string{clusterv1.MachineRebootRequestedAnnotation: "2022-01-01T00:00:00Z"},
+			wantRebootCompletedCond: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "machine-test",
+					Namespace:   metav1.NamespaceDefault,
+					Annotations: tt.machineAnnotations,
+				},
+			}
+			infraConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+				"kind":       "GenericInfrastructureMachine",
+				"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+				"metadata": map[string]interface{}{
+					"name":      "infra-config1",
+					"namespace": metav1.NamespaceDefault,
+				},
+			}}
+			infraConfig.SetAnnotations(tt.infraAnnotations)
+
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().
+					WithObjects(machine.DeepCopy(),
+						builder.GenericInfrastructureMachineCRD.DeepCopy(),
+						infraConfig.DeepCopy(),
+					).Build(),
+				recorder: record.NewFakeRecorder(32),
+			}
+
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(infraConfig), infraConfig)).To(Succeed())
+			g.Expect(r.reconcileReboot(ctx, machine, infraConfig)).To(Succeed())
+			if len(tt.wantMachineAnnotations) == 0 {
+				g.Expect(machine.GetAnnotations()).To(BeEmpty())
+			} else {
+				g.Expect(machine.GetAnnotations()).To(Equal(tt.wantMachineAnnotations))
+			}
+			g.Expect(conditions.Has(machine, clusterv1.MachineRebootCompletedCondition)).To(Equal(tt.wantRebootCompletedCond))
+			if tt.wantRebootCompletedCond {
+				g.Expect(conditions.IsFalse(machine, clusterv1.MachineRebootCompletedCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(machine, clusterv1.MachineRebootCompletedCondition)).To(Equal(clusterv1.WaitingForRebootReason))
+			}
+
+			g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(infraConfig), infraConfig)).To(Succeed())
+			if len(tt.wantInfraAnnotations) == 0 {
+				g.Expect(infraConfig.GetAnnotations()).To(BeEmpty())
+			} else {
+				g.Expect(infraConfig.GetAnnotations()).To(Equal(tt.wantInfraAnnotations))
+			}
+		})
+	}
+}
+
+func TestParseCrossNamespaceRefsAllowList(t *testing.T) {
+	g := NewWithT(t)
+
+	allowed, err := parseCrossNamespaceRefsAllowList([]string{"tenant-a=platform-infra", "tenant-a=shared", "tenant-b=platform-infra"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(allowed).To(HaveLen(2))
+	g.Expect(allowed["tenant-a"].List()).To(Equal([]string{"platform-infra", "shared"}))
+	g.Expect(allowed["tenant-b"].List()).To(Equal([]string{"platform-infra"}))
+
+	for _, entry := range []string{"tenant-a", "tenant-a=", "=platform-infra"} {
+		_, err := parseCrossNamespaceRefsAllowList([]string{entry})
+		g.Expect(err).To(HaveOccurred(), entry)
+	}
+}
+
+func TestValidateExternalRefNamespaces(t *testing.T) {
+	machineWithRefs := func(bootstrapNamespace, infraNamespace string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine-test",
+				Namespace: "tenant-a",
+			},
+			Spec: clusterv1.MachineSpec{
+				Bootstrap: clusterv1.Bootstrap{
+					ConfigRef: &corev1.ObjectReference{Kind: "GenericBootstrapConfig", Name: "bootstrap-config1", Namespace: bootstrapNamespace},
+				},
+				InfrastructureRef: corev1.ObjectReference{Kind: "GenericInfrastructureMachine", Name: "infra-config1", Namespace: infraNamespace},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		featureGate   bool
+		allowList     []string
+		machine       *clusterv1.Machine
+		expectedError bool
+	}{
+		{
+			name:          "refs in the namespace of the Machine are always allowed",
+			machine:       machineWithRefs("tenant-a", ""),
+			expectedError: false,
+		},
+		{
+			name:          "cross-namespace refs are rejected if the feature gate is disabled",
+			allowList:     []string{"tenant-a=platform-infra"},
+			machine:       machineWithRefs("tenant-a", "platform-infra"),
+			expectedError: true,
+		},
+		{
+			name:          "cross-namespace refs are rejected if the namespace is not in the allow list",
+			featureGate:   true,
+			machine:       machineWithRefs("tenant-a", "platform-infra"),
+			expectedError: true,
+		},
+		{
+			name:          "cross-namespace refs are rejected if the namespace is allowed only for other namespaces",
+			featureGate:   true,
+			allowList:     []string{"tenant-b=platform-infra", "tenant-a=shared"},
+			machine:       machineWithRefs("platform-infra", "shared"),
+			expectedError: true,
+		},
+		{
+			name:          "cross-namespace refs are allowed if the namespace is in the allow list",
+			featureGate:   true,
+			allowList:     []string{"tenant-a=platform-infra", "tenant-a=shared"},
+			machine:       machineWithRefs("shared", "platform-infra"),
+			expectedError: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachineCrossNamespaceRefs, tt.featureGate)()
+
+			allowed, err := parseCrossNamespaceRefsAllowList(tt.allowList)
+			g.Expect(err).ToNot(HaveOccurred())
+			r := &Reconciler{crossNamespaceRefsAllowed: allowed}
+
+			err = r.validateExternalRefNamespaces(tt.machine)
+			if tt.expectedError {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
 func TestReconcileCertificateExpiry(t *testing.T) {
 	fakeTimeString := "2020-01-01T00:00:00Z"
 	fakeTime, _ := time.Parse(time.RFC3339, fakeTimeString)
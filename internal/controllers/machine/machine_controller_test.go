@@ -685,6 +685,34 @@ func TestReconcileRequest(t *testing.T) {
 				err:    false,
 			},
 		},
+		{
+			// The InfrastructureMachine has been deleted after being ready; this is a terminal error, so the
+			// Machine is not requeued.
+			machine: clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "failed",
+					Namespace:  metav1.NamespaceDefault,
+					Finalizers: []string{clusterv1.MachineFinalizer},
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName: "test-cluster",
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+						Kind:       "GenericInfrastructureMachine",
+						Name:       "infra-config-deleted",
+					},
+					Bootstrap: clusterv1.Bootstrap{DataSecretName: pointer.String("data")},
+				},
+				Status: clusterv1.MachineStatus{
+					InfrastructureReady: true,
+					ObservedGeneration:  1,
+				},
+			},
+			expected: expected{
+				result: reconcile.Result{},
+				err:    false,
+			},
+		},
 		{
 			machine: clusterv1.Machine{
 				ObjectMeta: metav1.ObjectMeta{
@@ -742,6 +770,84 @@ func TestReconcileRequest(t *testing.T) {
 	}
 }
 
+func TestReconcileRequestSyncPeriod(t *testing.T) {
+	g := NewWithT(t)
+
+	infraConfig := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "GenericInfrastructureMachine",
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"metadata": map[string]interface{}{
+				"name":      "infra-config1",
+				"namespace": metav1.NamespaceDefault,
+			},
+			"spec": map[string]interface{}{
+				"providerID": "test://id-1",
+			},
+			"status": map[string]interface{}{
+				"ready": true,
+			},
+		},
+	}
+	testCluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+		Spec: corev1.NodeSpec{ProviderID: "test://id-1"},
+	}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "created",
+			Namespace:  metav1.NamespaceDefault,
+			Finalizers: []string{clusterv1.MachineFinalizer},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "test-cluster",
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "GenericInfrastructureMachine",
+				Name:       "infra-config1",
+			},
+			Bootstrap: clusterv1.Bootstrap{DataSecretName: pointer.String("data")},
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{
+				Name: "test",
+			},
+		},
+	}
+
+	clientFake := fake.NewClientBuilder().WithObjects(
+		node,
+		testCluster,
+		machine,
+		builder.GenericInfrastructureMachineCRD.DeepCopy(),
+		infraConfig,
+	).Build()
+
+	r := &Reconciler{
+		Client:     clientFake,
+		Tracker:    remote.NewTestClusterCacheTracker(logr.New(log.NullLogSink{}), clientFake, scheme.Scheme, client.ObjectKey{Name: testCluster.Name, Namespace: testCluster.Namespace}),
+		SyncPeriod: 5 * time.Minute,
+	}
+
+	// Machines are requeued after the sync period of the controller.
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: util.ObjectKey(machine)})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Minute}))
+
+	// Machines which do not exist are not requeued.
+	result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKey{Name: "does-not-exist", Namespace: metav1.NamespaceDefault}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(result).To(Equal(reconcile.Result{}))
+}
+
 func TestMachineConditions(t *testing.T) {
 	infraConfig := func(ready bool) *unstructured.Unstructured {
 		return &unstructured.Unstructured{
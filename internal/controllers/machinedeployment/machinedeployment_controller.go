@@ -32,6 +32,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -41,6 +42,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
@@ -119,8 +121,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	log = log.WithValues("Cluster", klog.KRef(deployment.Namespace, deployment.Spec.ClusterName))
-	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, log = clog.AddCluster(ctx, deployment.Namespace, deployment.Spec.ClusterName)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, deployment.Namespace, deployment.Spec.ClusterName)
 	if err != nil {
@@ -151,10 +152,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		}
 	}()
 
-	// Ignore deleted MachineDeployments, this can happen when foregroundDeletion
-	// is enabled
+	// Handle deletion of MachineDeployments orphaning their Machines; other deleted MachineDeployments are ignored,
+	// this can happen when foregroundDeletion is enabled.
 	if !deployment.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.reconcileDelete(ctx, deployment)
+	}
+
+	// Add the finalizer required to orphan Machines on deletion if requested, remove it otherwise.
+	if _, ok := deployment.Annotations[clusterv1.OrphanMachinesOnDeleteAnnotation]; ok {
+		controllerutil.AddFinalizer(deployment, clusterv1.MachineDeploymentOrphanMachinesFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(deployment, clusterv1.MachineDeploymentOrphanMachinesFinalizer)
 	}
 
 	result, err := r.reconcile(ctx, cluster, deployment)
@@ -178,6 +186,8 @@ func patchMachineDeployment(ctx context.Context, patchHelper *patch.Helper, d *c
 		patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 			clusterv1.ReadyCondition,
 			clusterv1.MachineDeploymentAvailableCondition,
+			clusterv1.MachineDeploymentProgressingCondition,
+			clusterv1.MachinesCreatedCondition,
 		}},
 	)
 	return patchHelper.Patch(ctx, d, options...)
@@ -238,7 +248,10 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		if d.Spec.Strategy.RollingUpdate == nil {
 			return ctrl.Result{}, errors.Errorf("missing MachineDeployment settings for strategy type: %s", d.Spec.Strategy.Type)
 		}
-		return ctrl.Result{}, r.rolloutRolling(ctx, d, msList)
+		if err := r.rolloutRolling(ctx, d, msList); err != nil {
+			return ctrl.Result{}, err
+		}
+		return r.reconcileProgressDeadline(ctx, d, msList)
 	}
 
 	if d.Spec.Strategy.Type == clusterv1.OnDeleteMachineDeploymentStrategyType {
@@ -261,7 +274,7 @@ func (r *Reconciler) getMachineSetsForDeployment(ctx context.Context, d *cluster
 	filtered := make([]*clusterv1.MachineSet, 0, len(machineSets.Items))
 	for idx := range machineSets.Items {
 		ms := &machineSets.Items[idx]
-		log.WithValues("MachineSet", klog.KObj(ms))
+		log := log.WithValues("MachineSet", klog.KObj(ms))
 		selector, err := metav1.LabelSelectorAsSelector(&d.Spec.Selector)
 		if err != nil {
 			log.Error(err, "Skipping MachineSet, failed to get label selector from spec selector")
@@ -309,6 +322,37 @@ func (r *Reconciler) adoptOrphan(ctx context.Context, deployment *clusterv1.Mach
 	return r.Client.Patch(ctx, machineSet, patch)
 }
 
+// reconcileDelete handles the deletion of a MachineDeployment with the OrphanMachinesOnDeleteAnnotation, by
+// propagating the annotation and the corresponding finalizer to its MachineSets before removing the finalizer;
+// this ensures the MachineSets orphan their Machines when they are deleted by the garbage collector.
+func (r *Reconciler) reconcileDelete(ctx context.Context, deployment *clusterv1.MachineDeployment) error {
+	if !controllerutil.ContainsFinalizer(deployment, clusterv1.MachineDeploymentOrphanMachinesFinalizer) {
+		return nil
+	}
+
+	if _, ok := deployment.Annotations[clusterv1.OrphanMachinesOnDeleteAnnotation]; ok {
+		machineSets := &clusterv1.MachineSetList{}
+		if err := r.Client.List(ctx, machineSets, client.InNamespace(deployment.Namespace)); err != nil {
+			return errors.Wrap(err, "failed to list MachineSets")
+		}
+		for i := range machineSets.Items {
+			ms := &machineSets.Items[i]
+			if !metav1.IsControlledBy(ms, deployment) || !ms.DeletionTimestamp.IsZero() {
+				continue
+			}
+			patch := client.MergeFrom(ms.DeepCopy())
+			annotations.AddAnnotations(ms, map[string]string{clusterv1.OrphanMachinesOnDeleteAnnotation: ""})
+			controllerutil.AddFinalizer(ms, clusterv1.MachineSetOrphanMachinesFinalizer)
+			if err := r.Client.Patch(ctx, ms, patch); err != nil {
+				return errors.Wrapf(err, "failed to patch MachineSet %s", ms.Name)
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(deployment, clusterv1.MachineDeploymentOrphanMachinesFinalizer)
+	return nil
+}
+
 // getMachineDeploymentsForMachineSet returns a list of MachineDeployments that could potentially match a MachineSet.
 func (r *Reconciler) getMachineDeploymentsForMachineSet(ctx context.Context, ms *clusterv1.MachineSet) []*clusterv1.MachineDeployment {
 	log := ctrl.LoggerFrom(ctx)
@@ -736,3 +736,52 @@ func TestGetMachineSetsForDeployment(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileDeleteOrphanMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "md",
+			Namespace:   metav1.NamespaceDefault,
+			UID:         "md-uid",
+			Annotations: map[string]string{clusterv1.OrphanMachinesOnDeleteAnnotation: ""},
+			Finalizers:  []string{clusterv1.MachineDeploymentOrphanMachinesFinalizer},
+		},
+	}
+	machineSet := func(name string, owner *clusterv1.MachineDeployment) *clusterv1.MachineSet {
+		ms := &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		if owner != nil {
+			ms.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, machineDeploymentKind)}
+		}
+		return ms
+	}
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			machineSet("ms1", md),
+			machineSet("ms2", md),
+			machineSet("not-owned", nil),
+		).Build(),
+	}
+
+	g.Expect(r.reconcileDelete(ctx, md)).To(Succeed())
+	g.Expect(md.Finalizers).To(BeEmpty())
+
+	// The annotation and the finalizer are propagated to the MachineSets owned by the MachineDeployment only.
+	for _, name := range []string{"ms1", "ms2"} {
+		ms := &clusterv1.MachineSet{}
+		g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: name}, ms)).To(Succeed())
+		g.Expect(ms.Annotations).To(HaveKey(clusterv1.OrphanMachinesOnDeleteAnnotation))
+		g.Expect(ms.Finalizers).To(ConsistOf(clusterv1.MachineSetOrphanMachinesFinalizer))
+	}
+	ms := &clusterv1.MachineSet{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "not-owned"}, ms)).To(Succeed())
+	g.Expect(ms.Annotations).ToNot(HaveKey(clusterv1.OrphanMachinesOnDeleteAnnotation))
+	g.Expect(ms.Finalizers).To(BeEmpty())
+}
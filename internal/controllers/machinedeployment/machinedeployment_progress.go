@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// defaultProgressDeadline is the progress deadline used when MachineDeployment.Spec.ProgressDeadlineSeconds is not set.
+const defaultProgressDeadline = 600 * time.Second
+
+// reconcileProgressDeadline checks if the rollout of a MachineDeployment is making progress, and surfaces it in the
+// MachineDeploymentProgressingCondition. If the rollout does not make progress within the progress deadline and
+// AutoRollback is set, the MachineDeployment is rolled back to the previous MachineSet revision.
+func (r *Reconciler) reconcileProgressDeadline(ctx context.Context, d *clusterv1.MachineDeployment, msList []*clusterv1.MachineSet) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// NOTE: The new MachineSet could not be in msList yet if it has just been created; in this case, the progress
+	// will be checked at the next reconcile, triggered by the MachineSet creation.
+	newMS := mdutil.FindNewMachineSet(d, msList)
+	if newMS == nil {
+		return ctrl.Result{}, nil
+	}
+	_, oldMSs := mdutil.FindOldMachineSets(d, msList)
+
+	// If the rollout is completed, there is nothing to check.
+	if newMS.Status.AvailableReplicas >= *d.Spec.Replicas && mdutil.GetActualReplicaCountForMachineSets(oldMSs) == 0 {
+		conditions.MarkTrue(d, clusterv1.MachineDeploymentProgressingCondition)
+		return ctrl.Result{}, nil
+	}
+
+	lastProgress, err := r.getLastProgressTime(ctx, d, newMS)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	deadline := defaultProgressDeadline
+	if d.Spec.ProgressDeadlineSeconds != nil {
+		deadline = time.Duration(*d.Spec.ProgressDeadlineSeconds) * time.Second
+	}
+
+	// If the rollout is still within the progress deadline, requeue to check it again after the deadline.
+	if remaining := time.Until(lastProgress.Add(deadline)); remaining > 0 {
+		// NOTE: The RolledBack reason is preserved until the rollout completes, so users can be aware of it.
+		if conditions.GetReason(d, clusterv1.MachineDeploymentProgressingCondition) != clusterv1.RolledBackReason {
+			conditions.MarkTrue(d, clusterv1.MachineDeploymentProgressingCondition)
+		}
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	// The rollout did not make progress within the progress deadline; if required, roll back to the previous MachineSet revision.
+	previousMS := previousMachineSet(oldMSs)
+	if !d.Spec.AutoRollback || previousMS == nil || newMS.Annotations[clusterv1.RolledBackAnnotation] != "" {
+		conditions.MarkFalse(d, clusterv1.MachineDeploymentProgressingCondition, clusterv1.ProgressDeadlineExceededReason, clusterv1.ConditionSeverityWarning,
+			"MachineSet %s did not make progress in the last %s", newMS.Name, deadline)
+		return ctrl.Result{}, nil
+	}
+
+	log.Info("Rolling back MachineDeployment to the previous MachineSet revision", "MachineSet", klog.KObj(newMS), "previousMachineSet", klog.KObj(previousMS))
+
+	// Mark the new MachineSet as rolled back, so it won't be used as a target for other rollbacks.
+	patchHelper, err := patch.NewHelper(newMS, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if newMS.Annotations == nil {
+		newMS.Annotations = map[string]string{}
+	}
+	newMS.Annotations[clusterv1.RolledBackAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if err := patchHelper.Patch(ctx, newMS); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to mark MachineSet %s as rolled back", klog.KObj(newMS))
+	}
+
+	// Restore the template of the previous MachineSet; the MachineDeployment is patched at the end of the reconcile.
+	template := previousMS.Spec.Template.DeepCopy()
+	delete(template.Labels, clusterv1.MachineDeploymentUniqueLabel)
+	d.Spec.Template = *template
+
+	conditions.MarkFalse(d, clusterv1.MachineDeploymentProgressingCondition, clusterv1.RolledBackReason, clusterv1.ConditionSeverityWarning,
+		"Rolled back to MachineSet %s because MachineSet %s did not make progress in the last %s", previousMS.Name, newMS.Name, deadline)
+	r.recorder.Eventf(d, corev1.EventTypeWarning, "RolledBack", "Rolled back to MachineSet %q because MachineSet %q did not make progress in the last %s", previousMS.Name, newMS.Name, deadline)
+	return ctrl.Result{}, nil
+}
+
+// getLastProgressTime returns the last time the rollout of a MachineDeployment made progress, that is the last time
+// the new MachineSet or one of its machines has been created, or one of its machines became ready.
+// NOTE: If the MachineDeployment has been rolled back, the rollback is considered as progress.
+func (r *Reconciler) getLastProgressTime(ctx context.Context, d *clusterv1.MachineDeployment, newMS *clusterv1.MachineSet) (time.Time, error) {
+	lastProgress := newMS.CreationTimestamp.Time
+
+	if c := conditions.Get(d, clusterv1.MachineDeploymentProgressingCondition); c != nil && c.Reason == clusterv1.RolledBackReason && c.LastTransitionTime.After(lastProgress) {
+		lastProgress = c.LastTransitionTime.Time
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(newMS.Namespace), client.MatchingLabels(newMS.Spec.Selector.MatchLabels)); err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to list machines for MachineSet %s", klog.KObj(newMS))
+	}
+	for _, m := range collections.FromMachineList(machines).Filter(collections.ControlledMachines(newMS)).UnsortedList() {
+		if m.CreationTimestamp.After(lastProgress) {
+			lastProgress = m.CreationTimestamp.Time
+		}
+		if c := conditions.Get(m, clusterv1.ReadyCondition); c != nil && c.Status == corev1.ConditionTrue && c.LastTransitionTime.After(lastProgress) {
+			lastProgress = c.LastTransitionTime.Time
+		}
+	}
+	return lastProgress, nil
+}
+
+// previousMachineSet returns the MachineSet with the highest revision among the old MachineSets,
+// excluding MachineSets which have already been rolled back.
+func previousMachineSet(oldMSs []*clusterv1.MachineSet) *clusterv1.MachineSet {
+	var previous *clusterv1.MachineSet
+	var previousRevision int64
+	for _, ms := range oldMSs {
+		if ms.Annotations[clusterv1.RolledBackAnnotation] != "" {
+			continue
+		}
+		revision, err := mdutil.Revision(ms)
+		if err != nil {
+			continue
+		}
+		if previous == nil || revision > previousRevision {
+			previous, previousRevision = ms, revision
+		}
+	}
+	return previous
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinedeployment
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileProgressDeadline(t *testing.T) {
+	newTemplate := func(infraName, hash string) clusterv1.MachineTemplateSpec {
+		return clusterv1.MachineTemplateSpec{
+			ObjectMeta: clusterv1.ObjectMeta{
+				Labels: map[string]string{
+					"foo":                                  hash,
+					clusterv1.MachineDeploymentUniqueLabel: hash,
+				},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName:       "test",
+				InfrastructureRef: corev1.ObjectReference{Name: infraName},
+			},
+		}
+	}
+	newMachineSet := func(name, revision string, availableReplicas int32, created time.Time) *clusterv1.MachineSet {
+		template := newTemplate(name, name)
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         metav1.NamespaceDefault,
+				UID:               types.UID("uid-" + name),
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations: map[string]string{
+					clusterv1.RevisionAnnotation: revision,
+				},
+			},
+			Spec: clusterv1.MachineSetSpec{
+				Replicas: pointer.Int32(1),
+				Selector: metav1.LabelSelector{MatchLabels: template.Labels},
+				Template: template,
+			},
+			Status: clusterv1.MachineSetStatus{
+				Replicas:          1,
+				AvailableReplicas: availableReplicas,
+			},
+		}
+	}
+	newMachineDeployment := func(autoRollback bool) *clusterv1.MachineDeployment {
+		template := newTemplate("new", "new")
+		delete(template.Labels, clusterv1.MachineDeploymentUniqueLabel)
+		return &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "md",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: clusterv1.MachineDeploymentSpec{
+				Replicas:                pointer.Int32(1),
+				ProgressDeadlineSeconds: pointer.Int32(600),
+				AutoRollback:            autoRollback,
+				Template:                template,
+			},
+		}
+	}
+
+	t.Run("Progressing is true when the rollout is completed", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := newMachineDeployment(true)
+		newMS := newMachineSet("new", "2", 1, time.Now().Add(-time.Hour))
+		oldMS := newMachineSet("old", "1", 0, time.Now().Add(-2*time.Hour))
+		oldMS.Status.Replicas = 0
+
+		r := &Reconciler{Client: fake.NewClientBuilder().Build(), recorder: record.NewFakeRecorder(32)}
+		res, err := r.reconcileProgressDeadline(ctx, d, []*clusterv1.MachineSet{newMS, oldMS})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.RequeueAfter).To(BeZero())
+		g.Expect(conditions.IsTrue(d, clusterv1.MachineDeploymentProgressingCondition)).To(BeTrue())
+	})
+
+	t.Run("Progressing is true and requeues when the rollout is within the progress deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := newMachineDeployment(true)
+		newMS := newMachineSet("new", "2", 0, time.Now().Add(-time.Hour))
+		oldMS := newMachineSet("old", "1", 1, time.Now().Add(-2*time.Hour))
+		// A machine of the new MachineSet became ready recently.
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "m1",
+				Namespace:         metav1.NamespaceDefault,
+				Labels:            newMS.Spec.Selector.MatchLabels,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+				OwnerReferences:   []metav1.OwnerReference{*metav1.NewControllerRef(newMS, clusterv1.GroupVersion.WithKind("MachineSet"))},
+			},
+			Status: clusterv1.MachineStatus{
+				Conditions: clusterv1.Conditions{{
+					Type:               clusterv1.ReadyCondition,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+				}},
+			},
+		}
+
+		r := &Reconciler{Client: fake.NewClientBuilder().WithObjects(machine).Build(), recorder: record.NewFakeRecorder(32)}
+		res, err := r.reconcileProgressDeadline(ctx, d, []*clusterv1.MachineSet{newMS, oldMS})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+		g.Expect(conditions.IsTrue(d, clusterv1.MachineDeploymentProgressingCondition)).To(BeTrue())
+	})
+
+	t.Run("Progressing is false when the rollout exceeds the progress deadline without auto rollback", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := newMachineDeployment(false)
+		newMS := newMachineSet("new", "2", 0, time.Now().Add(-time.Hour))
+		oldMS := newMachineSet("old", "1", 1, time.Now().Add(-2*time.Hour))
+
+		r := &Reconciler{Client: fake.NewClientBuilder().Build(), recorder: record.NewFakeRecorder(32)}
+		_, err := r.reconcileProgressDeadline(ctx, d, []*clusterv1.MachineSet{newMS, oldMS})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.GetReason(d, clusterv1.MachineDeploymentProgressingCondition)).To(Equal(clusterv1.ProgressDeadlineExceededReason))
+		g.Expect(d.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("new"))
+	})
+
+	t.Run("Rolls back to the previous MachineSet when the rollout exceeds the progress deadline with auto rollback", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := newMachineDeployment(true)
+		newMS := newMachineSet("new", "2", 0, time.Now().Add(-time.Hour))
+		oldMS := newMachineSet("old", "1", 1, time.Now().Add(-2*time.Hour))
+
+		c := fake.NewClientBuilder().WithObjects(newMS.DeepCopy(), oldMS.DeepCopy()).Build()
+		r := &Reconciler{Client: c, recorder: record.NewFakeRecorder(32)}
+		_, err := r.reconcileProgressDeadline(ctx, d, []*clusterv1.MachineSet{newMS, oldMS})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.GetReason(d, clusterv1.MachineDeploymentProgressingCondition)).To(Equal(clusterv1.RolledBackReason))
+		g.Expect(d.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("old"))
+		g.Expect(d.Spec.Template.Labels).ToNot(HaveKey(clusterv1.MachineDeploymentUniqueLabel))
+
+		gotMS := &clusterv1.MachineSet{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(newMS), gotMS)).To(Succeed())
+		g.Expect(gotMS.Annotations).To(HaveKey(clusterv1.RolledBackAnnotation))
+	})
+
+	t.Run("Does not roll back to a MachineSet which has already been rolled back", func(t *testing.T) {
+		g := NewWithT(t)
+
+		d := newMachineDeployment(true)
+		newMS := newMachineSet("new", "3", 0, time.Now().Add(-time.Hour))
+		oldMS := newMachineSet("old", "2", 1, time.Now().Add(-2*time.Hour))
+		oldMS.Annotations[clusterv1.RolledBackAnnotation] = time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+		r := &Reconciler{Client: fake.NewClientBuilder().Build(), recorder: record.NewFakeRecorder(32)}
+		_, err := r.reconcileProgressDeadline(ctx, d, []*clusterv1.MachineSet{newMS, oldMS})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conditions.GetReason(d, clusterv1.MachineDeploymentProgressingCondition)).To(Equal(clusterv1.ProgressDeadlineExceededReason))
+		g.Expect(d.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("new"))
+	})
+}
@@ -28,6 +28,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/patch"
 )
 
@@ -121,7 +122,7 @@ func (r *Reconciler) reconcileOldMachineSetsOnDelete(ctx context.Context, oldMSs
 		}
 		totalMachineCount := int32(len(allMachinesInOldMS.Items))
 		log.V(4).Info("Retrieved machines", "totalMachineCount", totalMachineCount)
-		updatedReplicaCount := totalMachineCount - mdutil.GetDeletingMachineCount(allMachinesInOldMS)
+		updatedReplicaCount := totalMachineCount - int32(collections.FromMachineList(allMachinesInOldMS).Filter(collections.HasDeletionTimestamp).Len())
 		if updatedReplicaCount < 0 {
 			return errors.Errorf("negative updated replica count %d for MachineSet %q, this is unexpected", updatedReplicaCount, oldMS.Name)
 		}
@@ -367,6 +367,18 @@ func (r *Reconciler) syncDeploymentStatus(allMSs []*clusterv1.MachineSet, newMS
 	} else {
 		conditions.MarkFalse(d, clusterv1.MachineDeploymentAvailableCondition, clusterv1.WaitingForAvailableMachinesReason, clusterv1.ConditionSeverityWarning, "Minimum availability requires %d replicas, current %d available", minReplicasNeeded, d.Status.AvailableReplicas)
 	}
+
+	// Surface MachineSets which are not creating Machines because of an infrastructure quota, so e.g. the topology
+	// controller can hold off rollouts for this MachineDeployment.
+	conditions.MarkTrue(d, clusterv1.MachinesCreatedCondition)
+	for _, ms := range allMSs {
+		if conditions.IsFalse(ms, clusterv1.MachinesCreatedCondition) &&
+			conditions.GetReason(ms, clusterv1.MachinesCreatedCondition) == clusterv1.InfrastructureQuotaExceededReason {
+			conditions.MarkFalse(d, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureQuotaExceededReason, clusterv1.ConditionSeverityWarning,
+				"MachineSet %s: %s", ms.Name, conditions.GetMessage(ms, clusterv1.MachinesCreatedCondition))
+			break
+		}
+	}
 	return nil
 }
 
@@ -478,6 +478,25 @@ func TestSyncDeploymentStatus(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "Deployment with a MachineSet blocked by an infrastructure quota: MachinesCreatedCondition should exist and be false",
+			d:              newTestMachineDeployment(&pds, 3, 2, 2, 2, clusterv1.Conditions{}),
+			oldMachineSets: []*clusterv1.MachineSet{},
+			newMachineSet: func() *clusterv1.MachineSet {
+				ms := newTestMachinesetWithReplicas("foo", 3, 2, 2)
+				conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureQuotaExceededReason, clusterv1.ConditionSeverityWarning, "1 Machines blocked by an infrastructure quota")
+				return ms
+			}(),
+			expectedConditions: []*clusterv1.Condition{
+				{
+					Type:     clusterv1.MachinesCreatedCondition,
+					Status:   corev1.ConditionFalse,
+					Severity: clusterv1.ConditionSeverityWarning,
+					Reason:   clusterv1.InfrastructureQuotaExceededReason,
+					Message:  "MachineSet foo: 1 Machines blocked by an infrastructure quota",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
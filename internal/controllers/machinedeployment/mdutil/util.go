@@ -128,6 +128,12 @@ var annotationsToSkip = map[string]bool{
 	clusterv1.DesiredReplicasAnnotation: true,
 	clusterv1.MaxReplicasAnnotation:     true,
 
+	// Exclude the orphan machines annotation, which is propagated to MachineSets only when the MachineDeployment is deleted.
+	clusterv1.OrphanMachinesOnDeleteAnnotation: true,
+
+	// Exclude the desired state hash annotation, which is relevant only for the MachineDeployment.
+	clusterv1.ClusterTopologyDesiredStateHashAnnotation: true,
+
 	// Exclude the conversion annotation, to avoid infinite loops between the conversion webhook
 	// and the MachineDeployment controller syncing the annotations between a MachineDeployment
 	// and its linked MachineSets.
@@ -697,15 +703,3 @@ func ComputeSpewHash(objectToWrite interface{}) (uint32, error) {
 	}
 	return machineTemplateSpecHasher.Sum32(), nil
 }
-
-// GetDeletingMachineCount gets the number of machines that are in the process of being deleted
-// in a machineList.
-func GetDeletingMachineCount(machineList *clusterv1.MachineList) int32 {
-	var deletingMachineCount int32
-	for _, machine := range machineList.Items {
-		if !machine.GetDeletionTimestamp().IsZero() {
-			deletingMachineCount++
-		}
-	}
-	return deletingMachineCount
-}
@@ -49,7 +49,9 @@ import (
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
@@ -70,6 +72,7 @@ const (
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinehealthchecks;machinehealthchecks/status;machinehealthchecks/finalizers,verbs=get;list;watch;update;patch
 
 // Reconciler reconciles a MachineHealthCheck object.
@@ -173,13 +176,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	m.Labels[clusterv1.ClusterLabelName] = m.Spec.ClusterName
 
 	result, err := r.reconcile(ctx, log, cluster, m)
+	// Requeue if the reconcile failed because of a RequeueAfterError, e.g. because the ClusterCacheTracker
+	// was locked for the current cluster because of concurrent access.
+	result, err = utilerrors.ReconcileResult(ctx, result, err)
 	if err != nil {
-		// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
-		// the current cluster because of concurrent access.
-		if errors.Is(err, remote.ErrClusterLocked) {
-			log.V(5).Info("Requeueing because another worker has the lock on the ClusterCacheTracker")
-			return ctrl.Result{Requeue: true}, nil
-		}
 		log.Error(err, "Failed to reconcile MachineHealthCheck")
 		r.recorder.Eventf(m, corev1.EventTypeWarning, "ReconcileError", "%v", err)
 
@@ -317,6 +317,19 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 	m.Status.RemediationsAllowed = remediationCount
 	conditions.MarkTrue(m, clusterv1.RemediationAllowedCondition)
 
+	// If requested, defer the remediation of unhealthy machines which are being replaced by a rollout.
+	remediationDeferred := false
+	if shouldPauseRemediationDuringRollout(m) {
+		for i := range unhealthy {
+			rolloutInProgress, err := r.getRolloutInProgress(ctx, cluster, unhealthy[i].Machine)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "error checking if a rollout is in progress for machine %s", unhealthy[i].Machine.Name)
+			}
+			unhealthy[i].rolloutInProgress = rolloutInProgress
+			remediationDeferred = remediationDeferred || rolloutInProgress != ""
+		}
+	}
+
 	errList := r.patchUnhealthyTargets(ctx, logger, unhealthy, cluster, m)
 	errList = append(errList, r.patchHealthyTargets(ctx, logger, healthy, m)...)
 
@@ -326,6 +339,11 @@ func (r *Reconciler) reconcile(ctx context.Context, logger logr.Logger, cluster
 		return reconcile.Result{}, kerrors.NewAggregate(errList)
 	}
 
+	if remediationDeferred {
+		logger.V(3).Info("Remediation of some targets is deferred until the rollout is completed. Ensuring a requeue happens", "requeueIn", remediationDeferredRequeueAfter.String())
+		nextCheckTimes = append(nextCheckTimes, remediationDeferredRequeueAfter)
+	}
+
 	if minNextCheck := minDuration(nextCheckTimes); minNextCheck > 0 {
 		logger.V(3).Info("Some targets might go unhealthy. Ensuring a requeue happens", "requeueIn", minNextCheck.Truncate(time.Second).String())
 		return ctrl.Result{RequeueAfter: minNextCheck}, nil
@@ -377,6 +395,8 @@ func (r *Reconciler) patchUnhealthyTargets(ctx context.Context, logger logr.Logg
 
 		if annotations.IsPaused(cluster, t.Machine) {
 			logger.Info("Machine has failed health check, but machine is paused so skipping remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message)
+		} else if t.rolloutInProgress != "" {
+			logger.Info("Machine has failed health check, but a rollout is in progress so deferring remediation", "target", t.string(), "reason", condition.Reason, "message", condition.Message, "rollout", t.rolloutInProgress)
 		} else {
 			if m.Spec.RemediationTemplate != nil {
 				// If external remediation request already exists,
@@ -548,27 +568,13 @@ func getMachineFromNode(ctx context.Context, c client.Client, nodeName string) (
 	); err != nil {
 		return nil, errors.Wrap(err, "failed getting machine list")
 	}
-	// TODO(vincepri): Remove this loop once controller runtime fake client supports
+	// TODO(vincepri): Remove this filter once controller runtime fake client supports
 	// adding indexes on objects.
-	items := []*clusterv1.Machine{}
-	for i := range machineList.Items {
-		machine := &machineList.Items[i]
-		if machine.Status.NodeRef != nil && machine.Status.NodeRef.Name == nodeName {
-			items = append(items, machine)
-		}
-	}
-	if len(items) != 1 {
-		return nil, errors.Errorf("expecting one machine for node %v, got %v", nodeName, machineNames(items))
-	}
-	return items[0], nil
-}
-
-func machineNames(machines []*clusterv1.Machine) []string {
-	result := make([]string, 0, len(machines))
-	for _, m := range machines {
-		result = append(result, m.Name)
+	machines := collections.FromMachineList(machineList).Filter(collections.HasNodeRefName(nodeName))
+	if machines.Len() != 1 {
+		return nil, errors.Errorf("expecting one machine for node %v, got %v", nodeName, machines.Names())
 	}
-	return result
+	return machines.UnsortedList()[0], nil
 }
 
 // isAllowedRemediation checks the value of the MaxUnhealthy field to determine
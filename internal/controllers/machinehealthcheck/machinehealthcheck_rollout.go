@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// remediationDeferredRequeueAfter is how often a MachineHealthCheck is reconciled while the remediation of
+// unhealthy machines is deferred, so remediation resumes shortly after the rollout is completed.
+const remediationDeferredRequeueAfter = 30 * time.Second
+
+// shouldPauseRemediationDuringRollout returns true if the MachineHealthCheck defers the remediation of
+// machines which are being replaced by a rollout.
+func shouldPauseRemediationDuringRollout(m *clusterv1.MachineHealthCheck) bool {
+	return m.Spec.PauseRemediationDuringRollout != nil && *m.Spec.PauseRemediationDuringRollout
+}
+
+// getRolloutInProgress returns a message describing the rollout which is going to replace the machine,
+// or an empty string if there is no such rollout in progress.
+// A machine is going to be replaced by a rollout if:
+//   - The Cluster uses a managed topology, the upgrade to a new version is not completed yet and the machine
+//     is not running the new version.
+//   - The machine belongs to a control plane which is upgrading or which has machines not yet updated, and the
+//     machine is not running the version or does not use the infrastructure machine template of the control plane.
+//   - The machine belongs to a MachineDeployment which has machines not yet updated, and the machine does not
+//     belong to the MachineSet using the template of the MachineDeployment.
+//
+// NOTE: Machines which have already been created by the rollout are not deferred, so they are remediated as usual.
+func (r *Reconciler) getRolloutInProgress(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (string, error) {
+	if cluster.Spec.Topology != nil {
+		reason := conditions.GetReason(cluster, clusterv1.TopologyReconciledCondition)
+		upgradePending := reason == clusterv1.TopologyReconciledControlPlaneUpgradePendingReason || reason == clusterv1.TopologyReconciledMachineDeploymentsUpgradePendingReason
+		if upgradePending && (machine.Spec.Version == nil || *machine.Spec.Version != cluster.Spec.Topology.Version) {
+			return fmt.Sprintf("Cluster %s is being upgraded to version %s", cluster.Name, cluster.Spec.Topology.Version), nil
+		}
+	}
+
+	if util.IsControlPlaneMachine(machine) {
+		if cluster.Spec.ControlPlaneRef == nil {
+			return "", nil
+		}
+		controlPlane, err := external.Get(ctx, r.Client, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if err != nil {
+			if apierrors.IsNotFound(errors.Cause(err)) {
+				return "", nil
+			}
+			return "", errors.Wrapf(err, "failed to get control plane for Cluster %s", cluster.Name)
+		}
+		rollingOut, err := isControlPlaneRollingOut(controlPlane)
+		if err != nil || !rollingOut {
+			return "", err
+		}
+		outdated, err := r.isControlPlaneMachineOutdated(ctx, controlPlane, machine)
+		if err != nil || !outdated {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s is rolling out machines", controlPlane.GetKind(), controlPlane.GetName()), nil
+	}
+
+	mdName, ok := machine.Labels[clusterv1.MachineDeploymentLabelName]
+	if !ok {
+		return "", nil
+	}
+	md := &clusterv1.MachineDeployment{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: mdName}, md); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get MachineDeployment %s", mdName)
+	}
+	if !isMachineDeploymentRollingOut(md) {
+		return "", nil
+	}
+	outdated, err := r.isMachineDeploymentMachineOutdated(ctx, md, machine)
+	if err != nil || !outdated {
+		return "", err
+	}
+	return fmt.Sprintf("MachineDeployment %s is rolling out machines", md.Name), nil
+}
+
+// isControlPlaneMachineOutdated returns true if the machine is not running the version of the control plane, or if
+// its infrastructure machine has not been cloned from the current infrastructure machine template of the control plane.
+func (r *Reconciler) isControlPlaneMachineOutdated(ctx context.Context, controlPlane *unstructured.Unstructured, machine *clusterv1.Machine) (bool, error) {
+	version, err := contract.ControlPlane().Version().Get(controlPlane)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get version from %s %s", controlPlane.GetKind(), controlPlane.GetName())
+	}
+	if machine.Spec.Version == nil || *machine.Spec.Version != *version {
+		return true, nil
+	}
+
+	// Skip control planes without an infrastructure machine template, there is no template change to check for.
+	infraTemplateRef, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(controlPlane)
+	if err != nil {
+		return false, nil //nolint:nilerr
+	}
+	if machine.Spec.FailureDomain != nil {
+		failureDomainRefs, err := contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Get(controlPlane)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get infrastructure machine templates by failure domain from %s %s", controlPlane.GetKind(), controlPlane.GetName())
+		}
+		if ref, ok := failureDomainRefs[*machine.Spec.FailureDomain]; ok {
+			infraTemplateRef = ref
+		}
+	}
+
+	infraMachine, err := external.Get(ctx, r.Client, &machine.Spec.InfrastructureRef, machine.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get infrastructure machine for Machine %s", machine.Name)
+	}
+	clonedFromName, ok1 := infraMachine.GetAnnotations()[clusterv1.TemplateClonedFromNameAnnotation]
+	clonedFromGroupKind, ok2 := infraMachine.GetAnnotations()[clusterv1.TemplateClonedFromGroupKindAnnotation]
+	if !ok1 || !ok2 {
+		// Infrastructure machines not cloned from a template, e.g. adopted machines, cannot be compared to the template.
+		return false, nil
+	}
+	return clonedFromName != infraTemplateRef.Name || clonedFromGroupKind != infraTemplateRef.GroupVersionKind().GroupKind().String(), nil
+}
+
+// isMachineDeploymentMachineOutdated returns true if the machine belongs to a MachineSet whose template is not the
+// template of the MachineDeployment.
+func (r *Reconciler) isMachineDeploymentMachineOutdated(ctx context.Context, md *clusterv1.MachineDeployment, machine *clusterv1.Machine) (bool, error) {
+	owner := metav1.GetControllerOf(machine)
+	if owner == nil || owner.Kind != "MachineSet" {
+		return false, nil
+	}
+	ms := &clusterv1.MachineSet{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: machine.Namespace, Name: owner.Name}, ms); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get MachineSet %s", owner.Name)
+	}
+	return !mdutil.EqualMachineTemplate(&ms.Spec.Template, &md.Spec.Template), nil
+}
+
+// isControlPlaneRollingOut returns true if the control plane is upgrading or if some of its machines are not yet updated.
+func isControlPlaneRollingOut(controlPlane *unstructured.Unstructured) (bool, error) {
+	// Skip control planes not reporting a version, there is no upgrade to check for.
+	if _, err := contract.ControlPlane().Version().Get(controlPlane); err != nil {
+		return false, nil //nolint:nilerr
+	}
+	upgrading, err := contract.ControlPlane().IsUpgrading(controlPlane)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if %s %s is upgrading", controlPlane.GetKind(), controlPlane.GetName())
+	}
+	if upgrading {
+		return true, nil
+	}
+
+	replicas, err := contract.ControlPlane().StatusReplicas().Get(controlPlane)
+	if err != nil {
+		return false, nil //nolint:nilerr // status is not yet reported, the control plane is still provisioning.
+	}
+	updatedReplicas, err := contract.ControlPlane().UpdatedReplicas().Get(controlPlane)
+	if err != nil {
+		return false, nil //nolint:nilerr // status is not yet reported, the control plane is still provisioning.
+	}
+	return *updatedReplicas < *replicas, nil
+}
+
+// isMachineDeploymentRollingOut returns true if the MachineDeployment has changes not yet observed or if some of its machines are not yet updated.
+func isMachineDeploymentRollingOut(md *clusterv1.MachineDeployment) bool {
+	return md.Status.ObservedGeneration < md.Generation || md.Status.UpdatedReplicas < md.Status.Replicas
+}
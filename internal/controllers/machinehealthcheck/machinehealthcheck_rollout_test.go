@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinehealthcheck
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestGetRolloutInProgress(t *testing.T) {
+	infrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-template1").Build()
+	controlPlane := func(version, statusVersion string, replicas, updatedReplicas int64) client.Object {
+		return builder.ControlPlane(metav1.NamespaceDefault, "cp1").
+			WithVersion(version).
+			WithInfrastructureMachineTemplate(infrastructureMachineTemplate).
+			WithStatusFields(map[string]interface{}{
+				"status.version":         statusVersion,
+				"status.replicas":        replicas,
+				"status.updatedReplicas": updatedReplicas,
+			}).
+			Build()
+	}
+	infrastructureMachine := func(name, clonedFrom string) client.Object {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(builder.InfrastructureGroupVersion.String())
+		obj.SetKind(builder.GenericInfrastructureMachineKind)
+		obj.SetNamespace(metav1.NamespaceDefault)
+		obj.SetName(name)
+		obj.SetAnnotations(map[string]string{
+			clusterv1.TemplateClonedFromNameAnnotation:      clonedFrom,
+			clusterv1.TemplateClonedFromGroupKindAnnotation: infrastructureMachineTemplate.GroupVersionKind().GroupKind().String(),
+		})
+		return obj
+	}
+	machineTemplate := func(version string) clusterv1.MachineTemplateSpec {
+		return clusterv1.MachineTemplateSpec{Spec: clusterv1.MachineSpec{ClusterName: "cluster1", Version: pointer.String(version)}}
+	}
+	machineDeployment := func(generation, observedGeneration int64, replicas, updatedReplicas int32) client.Object {
+		return &clusterv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "md1", Generation: generation},
+			Spec:       clusterv1.MachineDeploymentSpec{Template: machineTemplate("v1.22.0")},
+			Status: clusterv1.MachineDeploymentStatus{
+				ObservedGeneration: observedGeneration,
+				Replicas:           replicas,
+				UpdatedReplicas:    updatedReplicas,
+			},
+		}
+	}
+	machineSet := func(name, version string) client.Object {
+		return &clusterv1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: name},
+			Spec:       clusterv1.MachineSetSpec{Template: machineTemplate(version)},
+		}
+	}
+	controlPlaneMachine := func(name, version string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      name,
+				Labels:    map[string]string{clusterv1.MachineControlPlaneLabelName: ""},
+			},
+			Spec: clusterv1.MachineSpec{
+				Version: pointer.String(version),
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: builder.InfrastructureGroupVersion.String(),
+					Kind:       builder.GenericInfrastructureMachineKind,
+					Name:       name,
+				},
+			},
+		}
+	}
+	workerMachine := func(machineSetName string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      "worker-machine",
+				Labels:    map[string]string{clusterv1.MachineDeploymentLabelName: "md1"},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: clusterv1.GroupVersion.String(), Kind: "MachineSet", Name: machineSetName, Controller: pointer.Bool(true)},
+				},
+			},
+		}
+	}
+	standaloneMachine := func(version string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "standalone-machine"},
+			Spec:       clusterv1.MachineSpec{Version: pointer.String(version)},
+		}
+	}
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithControlPlane(builder.ControlPlane(metav1.NamespaceDefault, "cp1").Build()).
+		Build()
+	upgradingCluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.22.0").Build()).
+		Build()
+	conditions.MarkFalse(upgradingCluster, clusterv1.TopologyReconciledCondition, clusterv1.TopologyReconciledMachineDeploymentsUpgradePendingReason, clusterv1.ConditionSeverityInfo, "")
+
+	tests := []struct {
+		name    string
+		cluster *clusterv1.Cluster
+		machine *clusterv1.Machine
+		objs    []client.Object
+		want    string
+	}{
+		{
+			name:    "Cluster is being upgraded and the machine is not running the new version",
+			cluster: upgradingCluster,
+			machine: standaloneMachine("v1.21.0"),
+			want:    "Cluster cluster1 is being upgraded to version v1.22.0",
+		},
+		{
+			name:    "Cluster is being upgraded and the machine is already running the new version",
+			cluster: upgradingCluster,
+			machine: standaloneMachine("v1.22.0"),
+			want:    "",
+		},
+		{
+			name:    "Control plane is upgrading and the machine is not running the new version",
+			cluster: cluster,
+			machine: controlPlaneMachine("cp-machine", "v1.21.0"),
+			objs:    []client.Object{controlPlane("v1.22.0", "v1.21.0", 3, 3)},
+			want:    "GenericControlPlane cp1 is rolling out machines",
+		},
+		{
+			name:    "Control plane is upgrading and the machine has already been created by the rollout",
+			cluster: cluster,
+			machine: controlPlaneMachine("cp-machine", "v1.22.0"),
+			objs:    []client.Object{controlPlane("v1.22.0", "v1.21.0", 3, 3), infrastructureMachine("cp-machine", "infra-template1")},
+			want:    "",
+		},
+		{
+			name:    "Control plane has machines not yet updated and the machine uses an old infrastructure machine template",
+			cluster: cluster,
+			machine: controlPlaneMachine("cp-machine", "v1.22.0"),
+			objs:    []client.Object{controlPlane("v1.22.0", "v1.22.0", 4, 1), infrastructureMachine("cp-machine", "infra-template0")},
+			want:    "GenericControlPlane cp1 is rolling out machines",
+		},
+		{
+			name:    "Control plane is not rolling out",
+			cluster: cluster,
+			machine: controlPlaneMachine("cp-machine", "v1.21.0"),
+			objs:    []client.Object{controlPlane("v1.22.0", "v1.22.0", 3, 3)},
+			want:    "",
+		},
+		{
+			name:    "MachineDeployment has changes not yet observed and the machine belongs to an old MachineSet",
+			cluster: cluster,
+			machine: workerMachine("ms-old"),
+			objs:    []client.Object{machineDeployment(2, 1, 3, 3), machineSet("ms-old", "v1.21.0")},
+			want:    "MachineDeployment md1 is rolling out machines",
+		},
+		{
+			name:    "MachineDeployment has machines not yet updated and the machine belongs to an old MachineSet",
+			cluster: cluster,
+			machine: workerMachine("ms-old"),
+			objs:    []client.Object{machineDeployment(2, 2, 4, 3), machineSet("ms-old", "v1.21.0")},
+			want:    "MachineDeployment md1 is rolling out machines",
+		},
+		{
+			name:    "MachineDeployment has machines not yet updated and the machine belongs to the new MachineSet",
+			cluster: cluster,
+			machine: workerMachine("ms-new"),
+			objs:    []client.Object{machineDeployment(2, 2, 4, 3), machineSet("ms-new", "v1.22.0")},
+			want:    "",
+		},
+		{
+			name:    "MachineDeployment is not rolling out",
+			cluster: cluster,
+			machine: workerMachine("ms-old"),
+			objs:    []client.Object{machineDeployment(2, 2, 3, 3), machineSet("ms-old", "v1.21.0")},
+			want:    "",
+		},
+		{
+			name:    "MachineDeployment does not exist",
+			cluster: cluster,
+			machine: workerMachine("ms-old"),
+			want:    "",
+		},
+		{
+			name:    "Machine not owned by a control plane or by a MachineDeployment",
+			cluster: cluster,
+			machine: standaloneMachine("v1.21.0"),
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(tt.objs...).Build(),
+			}
+
+			got, err := r.getRolloutInProgress(ctx, tt.cluster, tt.machine)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestIsControlPlaneRollingOutWithoutStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp1").WithVersion("v1.22.0").Build()
+
+	rollingOut, err := isControlPlaneRollingOut(controlPlane)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rollingOut).To(BeFalse())
+}
@@ -60,6 +60,10 @@ type healthCheckTarget struct {
 	MHC         *clusterv1.MachineHealthCheck
 	patchHelper *patch.Helper
 	nodeMissing bool
+
+	// rolloutInProgress describes the rollout replacing the machine, if any; it is set only if the
+	// MachineHealthCheck defers remediation during rollouts.
+	rolloutInProgress string
 }
 
 func (t *healthCheckTarget) string() string {
@@ -85,6 +89,8 @@ func (t *healthCheckTarget) nodeName() string {
 // - The Machine did not get a node before `timeoutForMachineToHaveNode` elapses
 // - The Node has gone away
 // - Any condition on the node is matched for the given timeout
+// Machines with a pending reboot request are not evaluated until the reboot completes,
+// or until `timeoutForMachineToHaveNode` elapses since the reboot has been requested.
 // If the target doesn't currently need rememdiation, provide a duration after
 // which the target should next be checked.
 // The target should be requeued after this duration.
@@ -104,6 +110,27 @@ func (t *healthCheckTarget) needsRemediation(logger logr.Logger, timeoutForMachi
 		return true, time.Duration(0)
 	}
 
+	// The Node is expected to be unhealthy while the underlying instance is rebooted; if the reboot does not complete
+	// within the timeout, the target is evaluated, so it can be remediated.
+	if annotations.HasRebootRequested(t.Machine) {
+		rebootTimeout := timeoutForMachineToHaveNode.Duration
+		if timeoutForMachineToHaveNode == disabledNodeStartupTimeout {
+			rebootTimeout = clusterv1.DefaultNodeStartupTimeout.Duration
+		}
+
+		// NOTE: If the Machine controller did not hand over the request to the infrastructure provider yet, the reboot
+		// is considered as just requested.
+		rebootRequestedTime := now
+		if lastTransitionTime := conditions.GetLastTransitionTime(t.Machine, clusterv1.MachineRebootCompletedCondition); lastTransitionTime != nil {
+			rebootRequestedTime = lastTransitionTime.Time
+		}
+		if !rebootRequestedTime.Add(rebootTimeout).Before(now) {
+			logger.V(3).Info("Not evaluating target health because the reboot of the instance is in progress")
+			return false, rebootTimeout - now.Sub(rebootRequestedTime) + time.Second
+		}
+		logger.V(3).Info("Evaluating target health because the reboot of the instance did not complete within the timeout", "timeout", rebootTimeout)
+	}
+
 	// the node does not exist
 	if t.nodeMissing {
 		logger.V(3).Info("Target is unhealthy: node is missing")
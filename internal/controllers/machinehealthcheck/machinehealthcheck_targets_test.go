@@ -340,6 +340,38 @@ func TestHealthCheckTargets(t *testing.T) {
 	}
 	machineFailureMsgCondition := newFailedHealthCheckCondition(clusterv1.MachineHasFailureReason, "FailureMessage: %s", failureMsg)
 
+	// Targets for when the reboot of the instance has been requested, and the node is not ready
+	testMachineRebootRequested := testMachine.DeepCopy()
+	testMachineRebootRequested.Annotations = map[string]string{clusterv1.MachineRebootRequestedAnnotation: "2022-01-01T00:00:00Z"}
+	rebootNotHandedOver := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     testMHC,
+		Machine: testMachineRebootRequested,
+		Node:    testNodeUnknown400,
+	}
+
+	testMachineRebooting200s := testMachineRebootRequested.DeepCopy()
+	testMachineRebooting200s.Status.Conditions = clusterv1.Conditions{
+		{Type: clusterv1.MachineRebootCompletedCondition, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(time.Now().Add(-200 * time.Second))},
+	}
+	rebooting200s := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     testMHC,
+		Machine: testMachineRebooting200s,
+		Node:    testNodeUnknown400,
+	}
+
+	testMachineRebooting1200s := testMachineRebootRequested.DeepCopy()
+	testMachineRebooting1200s.Status.Conditions = clusterv1.Conditions{
+		{Type: clusterv1.MachineRebootCompletedCondition, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(time.Now().Add(-1200 * time.Second))},
+	}
+	rebooting1200s := healthCheckTarget{
+		Cluster: cluster,
+		MHC:     testMHC,
+		Machine: testMachineRebooting1200s,
+		Node:    testNodeUnknown400,
+	}
+
 	testCases := []struct {
 		desc                              string
 		targets                           []healthCheckTarget
@@ -426,6 +458,36 @@ func TestHealthCheckTargets(t *testing.T) {
 			expectedNeedsRemediationCondition: []clusterv1.Condition{machineFailureMsgCondition},
 			expectedNextCheckTimes:            []time.Duration{},
 		},
+		{
+			desc:                     "when the reboot of the instance has been requested but not yet handed over to the infrastructure provider",
+			targets:                  []healthCheckTarget{rebootNotHandedOver},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{timeoutForMachineToHaveNode + time.Second},
+		},
+		{
+			desc:                     "when the instance has been rebooting for shorter than the timeout",
+			targets:                  []healthCheckTarget{rebooting200s},
+			expectedHealthy:          []healthCheckTarget{},
+			expectedNeedsRemediation: []healthCheckTarget{},
+			expectedNextCheckTimes:   []time.Duration{timeoutForMachineToHaveNode - 200*time.Second},
+		},
+		{
+			desc:                        "when the instance has been rebooting for shorter than the default timeout and the startup timeout is disabled",
+			targets:                     []healthCheckTarget{rebooting200s},
+			timeoutForMachineToHaveNode: &disabledTimeoutForMachineToHaveNode,
+			expectedHealthy:             []healthCheckTarget{},
+			expectedNeedsRemediation:    []healthCheckTarget{},
+			expectedNextCheckTimes:      []time.Duration{clusterv1.DefaultNodeStartupTimeout.Duration - 200*time.Second},
+		},
+		{
+			desc:                              "when the instance has been rebooting for longer than the timeout",
+			targets:                           []healthCheckTarget{rebooting1200s},
+			expectedHealthy:                   []healthCheckTarget{},
+			expectedNeedsRemediation:          []healthCheckTarget{rebooting1200s},
+			expectedNeedsRemediationCondition: []clusterv1.Condition{nodeUnknown400Condition},
+			expectedNextCheckTimes:            []time.Duration{},
+		},
 	}
 
 	for _, tc := range testCases {
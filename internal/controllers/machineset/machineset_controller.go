@@ -28,11 +28,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -41,16 +44,24 @@ import (
 	"sigs.k8s.io/cluster-api/controllers/noderefutil"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	"sigs.k8s.io/cluster-api/internal/controllers/machine"
+	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
+	"sigs.k8s.io/cluster-api/internal/deletioncause"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
 	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
+	utilerrors "sigs.k8s.io/cluster-api/util/errors"
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
+const (
+	// controllerName defines the controller name used when recording events and the Machines deletion cause.
+	controllerName = "machineset-controller"
+)
+
 var (
 	// machineSetKind contains the schema.GroupVersionKind for the MachineSet type.
 	machineSetKind = clusterv1.GroupVersion.WithKind("MachineSet")
@@ -61,6 +72,10 @@ var (
 	// stateConfirmationInterval is the amount of time between polling for the desired state.
 	// The polling is against a local memory cache.
 	stateConfirmationInterval = 100 * time.Millisecond
+
+	// quotaExceededRequeueAfter is the amount of time to wait before checking again a MachineSet with Machines
+	// blocked by an infrastructure quota; quota usually frees up slowly, so a long backoff is used.
+	quotaExceededRequeueAfter = 5 * time.Minute
 )
 
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
@@ -114,7 +129,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return errors.Wrap(err, "failed to add Watch for Clusters to controller manager")
 	}
 
-	r.recorder = mgr.GetEventRecorderFor("machineset-controller")
+	r.recorder = mgr.GetEventRecorderFor(controllerName)
 	return nil
 }
 
@@ -137,8 +152,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 		return ctrl.Result{}, err
 	}
 
-	log = log.WithValues("Cluster", klog.KRef(machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName))
-	ctx = ctrl.LoggerInto(ctx, log)
+	ctx, log = clog.AddCluster(ctx, machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName)
 
 	cluster, err := util.GetClusterByName(ctx, r.Client, machineSet.ObjectMeta.Namespace, machineSet.Spec.ClusterName)
 	if err != nil {
@@ -159,25 +173,34 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 
 	defer func() {
 		// Always attempt to patch the object and status after each reconciliation.
-		if err := patchMachineSet(ctx, patchHelper, machineSet); err != nil {
+		// Patch ObservedGeneration only if the reconciliation completed successfully.
+		patchOpts := []patch.Option{}
+		if reterr == nil {
+			patchOpts = append(patchOpts, patch.WithStatusObservedGeneration{})
+		}
+		if err := patchMachineSet(ctx, patchHelper, machineSet, patchOpts...); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, err})
 		}
 	}()
 
-	// Ignore deleted MachineSets, this can happen when foregroundDeletion
-	// is enabled
+	// Handle deletion of MachineSets orphaning their Machines; other deleted MachineSets are ignored,
+	// this can happen when foregroundDeletion is enabled.
 	if !machineSet.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, r.reconcileDelete(ctx, machineSet)
+	}
+
+	// Add the finalizer required to orphan Machines on deletion if requested, remove it otherwise.
+	if _, ok := machineSet.Annotations[clusterv1.OrphanMachinesOnDeleteAnnotation]; ok {
+		controllerutil.AddFinalizer(machineSet, clusterv1.MachineSetOrphanMachinesFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(machineSet, clusterv1.MachineSetOrphanMachinesFinalizer)
 	}
 
 	result, err := r.reconcile(ctx, cluster, machineSet)
+	// Requeue if the reconcile failed because of a RequeueAfterError, e.g. because the ClusterCacheTracker
+	// was locked for the current cluster because of concurrent access.
+	result, err = utilerrors.ReconcileResult(ctx, result, err)
 	if err != nil {
-		// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
-		// the current cluster because of concurrent access.
-		if errors.Is(err, remote.ErrClusterLocked) {
-			log.V(5).Info("Requeueing because another worker has the lock on the ClusterCacheTracker")
-			return ctrl.Result{Requeue: true}, nil
-		}
 		log.Error(err, "Failed to reconcile MachineSet")
 		r.recorder.Eventf(machineSet, corev1.EventTypeWarning, "ReconcileError", "%v", err)
 	}
@@ -289,25 +312,30 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		filteredMachines = append(filteredMachines, machine)
 	}
 
+	// Propagate in-place the labels and annotations of the machine template to the existing Machines, so changes
+	// to the metadata only do not leave existing Machines with stale metadata until they are replaced.
+	if err := r.syncMachinesMetadata(ctx, machineSet, filteredMachines); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to sync Machines metadata")
+	}
+
+	// filteredMachines contains machines in deleting status to calculate correct status.
+	// skip remediation for those in deleting status.
+	machinesToRemediate := collections.FromMachines(filteredMachines...).Filter(collections.ActiveMachines, collections.NeedsRemediation)
+
 	var errs []error
-	for _, machine := range filteredMachines {
+	for _, machine := range machinesToRemediate.SortedByName() {
 		log := log.WithValues("Machine", klog.KObj(machine))
-		// filteredMachines contains machines in deleting status to calculate correct status.
-		// skip remediation for those in deleting status.
-		if !machine.DeletionTimestamp.IsZero() {
+		log.Info("Deleting machine because marked as unhealthy by the MachineHealthCheck controller")
+		if err := deletioncause.DeleteMachine(ctx, r.Client, machine, clusterv1.MachineDeletionCauseRemediation, controllerName); err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to delete"))
 			continue
 		}
-		if conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition) {
-			log.Info("Deleting machine because marked as unhealthy by the MachineHealthCheck controller")
-			patch := client.MergeFrom(machine.DeepCopy())
-			if err := r.Client.Delete(ctx, machine); err != nil {
-				errs = append(errs, errors.Wrap(err, "failed to delete"))
-				continue
-			}
-			conditions.MarkTrue(machine, clusterv1.MachineOwnerRemediatedCondition)
-			if err := r.Client.Status().Patch(ctx, machine, patch); err != nil && !apierrors.IsNotFound(err) {
-				errs = append(errs, errors.Wrap(err, "failed to update status"))
-			}
+		// NOTE: The patch is computed after recording the deletion cause, so it does not include the resourceVersion
+		// changed by the deletion cause patch, which is outdated after the deletion.
+		patch := client.MergeFrom(machine.DeepCopy())
+		conditions.MarkTrue(machine, clusterv1.MachineOwnerRemediatedCondition)
+		if err := r.Client.Status().Patch(ctx, machine, patch); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrap(err, "failed to update status"))
 		}
 	}
 
@@ -346,6 +374,12 @@ func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster,
 		return ctrl.Result{RequeueAfter: time.Duration(machineSet.Spec.MinReadySeconds) * time.Second}, nil
 	}
 
+	// Slowly reconcile while Machines are blocked by an infrastructure quota, so the provider is not flooded with
+	// requests that are going to fail; changes to the blocked Machines trigger a reconcile anyway.
+	if len(quotaExceededMachines(filteredMachines)) > 0 {
+		return ctrl.Result{RequeueAfter: quotaExceededRequeueAfter}, nil
+	}
+
 	// Quickly reconcile until the nodes become Ready.
 	if machineSet.Status.ReadyReplicas != replicas {
 		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
@@ -371,16 +405,48 @@ func (r *Reconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet,
 				return nil
 			}
 		}
+		// If the infrastructure provider reports existing Machines blocked by a quota, new Machines would be blocked
+		// as well; wait for the blocked Machines to be provisioned or deleted before creating more.
+		if blocked := quotaExceededMachines(machines); len(blocked) > 0 {
+			log.Info(fmt.Sprintf("Creation of new machines deferred, %d machines are blocked by an infrastructure quota", len(blocked)), "machine", blocked[0].Name)
+			return nil
+		}
 		var (
 			machineList []*clusterv1.Machine
 			errs        []error
 		)
 
+		// If the MachineSet pre-allocates provider IDs to new machines, get the provider IDs still available in the pool.
+		pool, usePool := ms.Annotations[clusterv1.ProviderIDPoolAnnotation]
+		var freeProviderIDs []string
+		if usePool {
+			var err error
+			freeProviderIDs, err = r.getFreeProviderIDs(ctx, ms, pool)
+			if err != nil {
+				return err
+			}
+		}
+
 		for i := 0; i < diff; i++ {
 			// Create a new logger so the global logger is not modified.
 			log := log
 			machine := r.getNewMachine(ms)
 
+			if usePool {
+				if len(freeProviderIDs) == 0 {
+					err := errors.Errorf("failed to create machine %d of %d: no free provider IDs left in the %s annotation", i+1, diff, clusterv1.ProviderIDPoolAnnotation)
+					log.Error(err, "Unable to create machine")
+					r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedCreate", "Failed to create machine: %v", err)
+					conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.ProviderIDPoolExhaustedReason,
+						clusterv1.ConditionSeverityWarning, err.Error())
+					errs = append(errs, err)
+					break
+				}
+				machine.Spec.ProviderID = pointer.String(freeProviderIDs[0])
+				freeProviderIDs = freeProviderIDs[1:]
+				log = log.WithValues("providerID", *machine.Spec.ProviderID)
+			}
+
 			// Clone and set the infrastructure and bootstrap references.
 			var (
 				infraRef, bootstrapRef *corev1.ObjectReference
@@ -467,13 +533,18 @@ func (r *Reconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet,
 			return err
 		}
 
+		deletionCause, err := r.getDeletionCause(ctx, ms)
+		if err != nil {
+			return err
+		}
+
 		var errs []error
 		machinesToDelete := getMachinesToDeletePrioritized(machines, diff, deletePriorityFunc)
 		for i, machine := range machinesToDelete {
 			log := log.WithValues("Machine", klog.KObj(machine))
 			if machine.GetDeletionTimestamp().IsZero() {
 				log.Info(fmt.Sprintf("Deleting machine %d of %d", i+1, diff))
-				if err := r.Client.Delete(ctx, machine); err != nil {
+				if err := deletioncause.DeleteMachine(ctx, r.Client, machine, deletionCause, controllerName); err != nil {
 					log.Error(err, "Unable to delete Machine")
 					r.recorder.Eventf(ms, corev1.EventTypeWarning, "FailedDelete", "Failed to delete machine %q: %v", machine.Name, err)
 					errs = append(errs, err)
@@ -494,6 +565,30 @@ func (r *Reconciler) syncReplicas(ctx context.Context, ms *clusterv1.MachineSet,
 	return nil
 }
 
+// getDeletionCause returns why Machines are deleted when scaling down a MachineSet: Machines of a MachineSet
+// owned by a MachineDeployment with a different machine template are deleted because of a rollout.
+func (r *Reconciler) getDeletionCause(ctx context.Context, ms *clusterv1.MachineSet) (string, error) {
+	for _, ref := range ms.OwnerReferences {
+		if ref.Kind != "MachineDeployment" || ref.APIVersion != clusterv1.GroupVersion.String() {
+			continue
+		}
+		md := &clusterv1.MachineDeployment{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: ms.Namespace, Name: ref.Name}, md); err != nil {
+			if apierrors.IsNotFound(err) {
+				return clusterv1.MachineDeletionCauseOwnerDeletion, nil
+			}
+			return "", errors.Wrapf(err, "failed to get MachineDeployment %s", klog.KRef(ms.Namespace, ref.Name))
+		}
+		if !md.DeletionTimestamp.IsZero() {
+			return clusterv1.MachineDeletionCauseOwnerDeletion, nil
+		}
+		if !mdutil.EqualMachineTemplate(&ms.Spec.Template, &md.Spec.Template) {
+			return clusterv1.MachineDeletionCauseRollout, nil
+		}
+	}
+	return clusterv1.MachineDeletionCauseScaleDown, nil
+}
+
 // getNewMachine creates a new Machine object. The name of the newly created resource is going
 // to be created by the API server, we set the generateName field.
 func (r *Reconciler) getNewMachine(machineSet *clusterv1.MachineSet) *clusterv1.Machine {
@@ -519,6 +614,75 @@ func (r *Reconciler) getNewMachine(machineSet *clusterv1.MachineSet) *clusterv1.
 	return machine
 }
 
+// syncMachinesMetadata adds or updates the labels and annotations of the MachineSet's machine template on the
+// existing Machines; Machines being deleted are skipped.
+// NOTE: Labels and annotations removed from the machine template are not removed from the existing Machines, because
+// it is not possible to tell them apart from the ones set on the Machines by other actors.
+func (r *Reconciler) syncMachinesMetadata(ctx context.Context, machineSet *clusterv1.MachineSet, machines []*clusterv1.Machine) error {
+	var errs []error
+	for _, machine := range machines {
+		if !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		patch := client.MergeFrom(machine.DeepCopy())
+		labelsChanged := mergeMetadata(&machine.Labels, machineSet.Spec.Template.Labels)
+		annotationsChanged := mergeMetadata(&machine.Annotations, machineSet.Spec.Template.Annotations)
+		if !labelsChanged && !annotationsChanged {
+			continue
+		}
+
+		if err := r.Client.Patch(ctx, machine, patch); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to patch Machine %s", klog.KObj(machine)))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// mergeMetadata adds or updates the entries of from into to, returning true if to has been changed.
+func mergeMetadata(to *map[string]string, from map[string]string) bool {
+	changed := false
+	for k, v := range from {
+		if current, ok := (*to)[k]; ok && current == v {
+			continue
+		}
+		if *to == nil {
+			*to = map[string]string{}
+		}
+		(*to)[k] = v
+		changed = true
+	}
+	return changed
+}
+
+// getFreeProviderIDs returns the provider IDs in a provider ID pool which are not used by any Machine in the Cluster
+// the MachineSet belongs to, preserving the order defined in the pool.
+func (r *Reconciler) getFreeProviderIDs(ctx context.Context, machineSet *clusterv1.MachineSet, pool string) ([]string, error) {
+	machines := &clusterv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(machineSet.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: machineSet.Spec.ClusterName}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list machines in cluster %s", klog.KRef(machineSet.Namespace, machineSet.Spec.ClusterName))
+	}
+
+	used := sets.NewString()
+	for _, m := range machines.Items {
+		if m.Spec.ProviderID != nil {
+			used.Insert(*m.Spec.ProviderID)
+		}
+	}
+
+	free := []string{}
+	for _, providerID := range strings.Split(pool, ",") {
+		providerID = strings.TrimSpace(providerID)
+		if providerID == "" || used.Has(providerID) {
+			continue
+		}
+		// Ensure the same provider ID is not allocated twice if it is repeated in the pool.
+		used.Insert(providerID)
+		free = append(free, providerID)
+	}
+	return free, nil
+}
+
 // shouldExcludeMachine returns true if the machine should be filtered out, false otherwise.
 func shouldExcludeMachine(machineSet *clusterv1.MachineSet, machine *clusterv1.Machine) bool {
 	if metav1.GetControllerOf(machine) != nil && !metav1.IsControlledBy(machine, machineSet) {
@@ -536,6 +700,44 @@ func (r *Reconciler) adoptOrphan(ctx context.Context, machineSet *clusterv1.Mach
 	return r.Client.Patch(ctx, machine, patch)
 }
 
+// reconcileDelete orphans the Machines of a MachineSet being deleted with the OrphanMachinesOnDeleteAnnotation,
+// by removing the MachineSet OwnerReference from them before removing the finalizer; this prevents the garbage
+// collector from deleting the Machines once the MachineSet is gone.
+func (r *Reconciler) reconcileDelete(ctx context.Context, machineSet *clusterv1.MachineSet) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !controllerutil.ContainsFinalizer(machineSet, clusterv1.MachineSetOrphanMachinesFinalizer) {
+		return nil
+	}
+
+	if _, ok := machineSet.Annotations[clusterv1.OrphanMachinesOnDeleteAnnotation]; ok {
+		machines := &clusterv1.MachineList{}
+		if err := r.Client.List(ctx, machines, client.InNamespace(machineSet.Namespace)); err != nil {
+			return errors.Wrap(err, "failed to list machines")
+		}
+		for i := range machines.Items {
+			machine := &machines.Items[i]
+			if !metav1.IsControlledBy(machine, machineSet) {
+				continue
+			}
+			if err := r.orphan(ctx, machineSet, machine); err != nil {
+				return errors.Wrapf(err, "failed to orphan Machine %s", machine.Name)
+			}
+			log.Info("Orphaned Machine", "Machine", klog.KObj(machine))
+		}
+	}
+
+	controllerutil.RemoveFinalizer(machineSet, clusterv1.MachineSetOrphanMachinesFinalizer)
+	return nil
+}
+
+// orphan removes the MachineSet OwnerReference from the Machine.
+func (r *Reconciler) orphan(ctx context.Context, machineSet *clusterv1.MachineSet, machine *clusterv1.Machine) error {
+	patch := client.MergeFrom(machine.DeepCopy())
+	machine.OwnerReferences = util.RemoveOwnerRef(machine.OwnerReferences, *metav1.NewControllerRef(machineSet, machineSetKind))
+	return r.Client.Patch(ctx, machine, patch)
+}
+
 func (r *Reconciler) waitForMachineCreation(ctx context.Context, machineList []*clusterv1.Machine) error {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -724,17 +926,15 @@ func (r *Reconciler) updateStatus(ctx context.Context, cluster *clusterv1.Cluste
 		ms.Status.FullyLabeledReplicas != newStatus.FullyLabeledReplicas ||
 		ms.Status.ReadyReplicas != newStatus.ReadyReplicas ||
 		ms.Status.AvailableReplicas != newStatus.AvailableReplicas ||
-		ms.Generation != ms.Status.ObservedGeneration {
+		ms.Status.Selector != newStatus.Selector {
 		log.V(4).Info("Updating status: " +
 			fmt.Sprintf("replicas %d->%d (need %d), ", ms.Status.Replicas, newStatus.Replicas, desiredReplicas) +
 			fmt.Sprintf("fullyLabeledReplicas %d->%d, ", ms.Status.FullyLabeledReplicas, newStatus.FullyLabeledReplicas) +
 			fmt.Sprintf("readyReplicas %d->%d, ", ms.Status.ReadyReplicas, newStatus.ReadyReplicas) +
-			fmt.Sprintf("availableReplicas %d->%d, ", ms.Status.AvailableReplicas, newStatus.AvailableReplicas) +
-			fmt.Sprintf("observedGeneration %v->%v", ms.Status.ObservedGeneration, ms.Generation))
+			fmt.Sprintf("availableReplicas %d->%d", ms.Status.AvailableReplicas, newStatus.AvailableReplicas))
 
-		// Save the generation number we acted on, otherwise we might wrongfully indicate
-		// that we've seen a spec update when we retry.
-		newStatus.ObservedGeneration = ms.Generation
+		// NOTE: ObservedGeneration is set when patching the MachineSet, only if the reconciliation completed successfully,
+		// otherwise we might wrongfully indicate that we've seen a spec update when we retry.
 		newStatus.DeepCopyInto(&ms.Status)
 	}
 	switch {
@@ -760,6 +960,13 @@ func (r *Reconciler) updateStatus(ctx context.Context, cluster *clusterv1.Cluste
 		conditions.MarkTrue(ms, clusterv1.MachinesCreatedCondition)
 	}
 
+	// Surface Machines blocked by an infrastructure quota, given that no new Machines are created until they are
+	// provisioned or deleted.
+	if blocked := quotaExceededMachines(filteredMachines); len(blocked) > 0 {
+		conditions.MarkFalse(ms, clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureQuotaExceededReason, clusterv1.ConditionSeverityWarning,
+			"%d Machines blocked by an infrastructure quota, Machine %s: %s", len(blocked), blocked[0].Name, conditions.GetMessage(blocked[0], clusterv1.InfrastructureReadyCondition))
+	}
+
 	// Aggregate the operational state of all the machines; while aggregating we are adding the
 	// source ref (reason@machine/name) so the problem can be easily tracked down to its source machine.
 	conditions.SetAggregate(ms, clusterv1.MachinesReadyCondition, collections.FromMachines(filteredMachines...).ConditionGetters(), conditions.AddSourceRef(), conditions.WithStepCounterIf(false))
@@ -767,6 +974,22 @@ func (r *Reconciler) updateStatus(ctx context.Context, cluster *clusterv1.Cluste
 	return nil
 }
 
+// quotaExceededMachines returns the Machines not being deleted whose infrastructure cannot be provisioned
+// because a quota of the underlying infrastructure is exceeded, as reported by the infrastructure provider.
+func quotaExceededMachines(machines []*clusterv1.Machine) []*clusterv1.Machine {
+	var blocked []*clusterv1.Machine
+	for _, m := range machines {
+		if !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if conditions.IsFalse(m, clusterv1.InfrastructureReadyCondition) &&
+			conditions.GetReason(m, clusterv1.InfrastructureReadyCondition) == clusterv1.InfrastructureQuotaExceededReason {
+			blocked = append(blocked, m)
+		}
+	}
+	return blocked
+}
+
 func (r *Reconciler) getMachineNode(ctx context.Context, cluster *clusterv1.Cluster, machine *clusterv1.Machine) (*corev1.Node, error) {
 	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
 	if err != nil {
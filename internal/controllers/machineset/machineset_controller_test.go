@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -495,6 +496,7 @@ func TestMachineSetReconcile(t *testing.T) {
 		g := NewWithT(t)
 
 		ms := newMachineSet("machineset1", testClusterName, int32(0))
+		ms.Generation = 2
 		ms.Labels = nil
 		ms.Spec.Selector.MatchLabels = nil
 		ms.Spec.Template.Labels = nil
@@ -510,6 +512,10 @@ func TestMachineSetReconcile(t *testing.T) {
 		}
 		_, err := msr.Reconcile(ctx, request)
 		g.Expect(err).NotTo(HaveOccurred())
+
+		// ObservedGeneration is set when the reconciliation completes successfully.
+		g.Expect(msr.Client.Get(ctx, util.ObjectKey(ms), ms)).To(Succeed())
+		g.Expect(ms.Status.ObservedGeneration).To(Equal(int64(2)))
 	})
 }
 
@@ -788,8 +794,9 @@ func TestMachineSetReconcile_MachinesCreatedConditionFalseOnBadInfraRef(t *testi
 
 	ms := &clusterv1.MachineSet{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "ms-foo",
-			Namespace: metav1.NamespaceDefault,
+			Name:       "ms-foo",
+			Namespace:  metav1.NamespaceDefault,
+			Generation: 2,
 			Labels: map[string]string{
 				clusterv1.ClusterLabelName: cluster.Name,
 			},
@@ -839,6 +846,8 @@ func TestMachineSetReconcile_MachinesCreatedConditionFalseOnBadInfraRef(t *testi
 	g.Expect(gotCond).ToNot(BeNil())
 	g.Expect(gotCond.Status).To(Equal(corev1.ConditionFalse))
 	g.Expect(gotCond.Reason).To(Equal(clusterv1.InfrastructureTemplateCloningFailedReason))
+	// ObservedGeneration is not set when the reconciliation fails.
+	g.Expect(ms.Status.ObservedGeneration).To(BeZero())
 }
 
 func TestMachineSetReconciler_updateStatusResizedCondition(t *testing.T) {
@@ -899,3 +908,302 @@ func TestMachineSetReconciler_updateStatusResizedCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestMachineSetReconciler_getFreeProviderIDs(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := newMachineSet("ms", testClusterName, int32(1))
+	machineWithProviderID := func(name, cluster, providerID string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: cluster,
+				},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: cluster,
+				ProviderID:  pointer.String(providerID),
+			},
+		}
+	}
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(
+			machineWithProviderID("machine-a", testClusterName, "host-a"),
+			// Provider IDs used by machines in other clusters are not considered.
+			machineWithProviderID("machine-c", "another-cluster", "host-c"),
+		).Build(),
+	}
+
+	got, err := r.getFreeProviderIDs(ctx, ms, "host-a, host-b,host-c,,host-b")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal([]string{"host-b", "host-c"}))
+}
+
+func TestMachineSetReconciler_syncReplicasProviderIDPoolExhausted(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := newMachineSet("ms", testClusterName, int32(1))
+	ms.Annotations = map[string]string{clusterv1.ProviderIDPoolAnnotation: "host-a"}
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-a",
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: testClusterName,
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: testClusterName,
+			ProviderID:  pointer.String("host-a"),
+		},
+	}
+
+	r := &Reconciler{
+		Client:   fake.NewClientBuilder().WithObjects(ms, machine).Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	err := r.syncReplicas(ctx, ms, []*clusterv1.Machine{})
+	g.Expect(err).To(HaveOccurred())
+
+	gotCond := conditions.Get(ms, clusterv1.MachinesCreatedCondition)
+	g.Expect(gotCond).ToNot(BeNil())
+	g.Expect(gotCond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(gotCond.Reason).To(Equal(clusterv1.ProviderIDPoolExhaustedReason))
+}
+
+func TestMachineSetReconciler_syncReplicasInfrastructureQuotaExceeded(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := newMachineSet("ms", testClusterName, int32(3))
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-a",
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				clusterv1.ClusterLabelName: testClusterName,
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: testClusterName,
+		},
+		Status: clusterv1.MachineStatus{
+			Conditions: clusterv1.Conditions{
+				*conditions.FalseCondition(clusterv1.InfrastructureReadyCondition, clusterv1.InfrastructureQuotaExceededReason, clusterv1.ConditionSeverityWarning, "instances quota exceeded"),
+			},
+		},
+	}
+
+	r := &Reconciler{
+		Client:   fake.NewClientBuilder().WithObjects(ms, machine).Build(),
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	// No Machines are created while existing Machines are blocked by an infrastructure quota.
+	err := r.syncReplicas(ctx, ms, []*clusterv1.Machine{machine})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	machineList := &clusterv1.MachineList{}
+	g.Expect(r.Client.List(ctx, machineList)).To(Succeed())
+	g.Expect(machineList.Items).To(HaveLen(1))
+
+	err = r.updateStatus(ctx, &clusterv1.Cluster{}, ms, []*clusterv1.Machine{machine})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	gotCond := conditions.Get(ms, clusterv1.MachinesCreatedCondition)
+	g.Expect(gotCond).ToNot(BeNil())
+	g.Expect(gotCond.Status).To(Equal(corev1.ConditionFalse))
+	g.Expect(gotCond.Reason).To(Equal(clusterv1.InfrastructureQuotaExceededReason))
+	g.Expect(gotCond.Message).To(Equal("1 Machines blocked by an infrastructure quota, Machine machine-a: instances quota exceeded"))
+}
+
+func TestMachineSetReconciler_reconcileDeleteOrphanMachines(t *testing.T) {
+	machine := func(name string, ownerRefs ...metav1.OwnerReference) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       metav1.NamespaceDefault,
+				OwnerReferences: ownerRefs,
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: testClusterName,
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantOrphaned bool
+	}{
+		{
+			name:         "Machines are orphaned if the MachineSet has the orphan machines annotation",
+			annotations:  map[string]string{clusterv1.OrphanMachinesOnDeleteAnnotation: ""},
+			wantOrphaned: true,
+		},
+		{
+			name:         "Machines are not orphaned if the orphan machines annotation has been removed",
+			wantOrphaned: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ms := newMachineSet("ms", testClusterName, int32(1))
+			ms.UID = "ms-uid"
+			ms.Annotations = tt.annotations
+			ms.Finalizers = []string{clusterv1.MachineSetOrphanMachinesFinalizer}
+			ownerRef := *metav1.NewControllerRef(ms, machineSetKind)
+
+			otherMS := newMachineSet("other-ms", testClusterName, int32(1))
+			otherMS.UID = "other-ms-uid"
+			otherOwnerRef := *metav1.NewControllerRef(otherMS, machineSetKind)
+
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithObjects(
+					machine("mine", ownerRef),
+					machine("others", otherOwnerRef),
+				).Build(),
+			}
+
+			g.Expect(r.reconcileDelete(ctx, ms)).To(Succeed())
+			g.Expect(ms.Finalizers).To(BeEmpty())
+
+			mine := &clusterv1.Machine{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "mine"}, mine)).To(Succeed())
+			if tt.wantOrphaned {
+				g.Expect(mine.OwnerReferences).To(BeEmpty())
+			} else {
+				g.Expect(mine.OwnerReferences).To(ConsistOf(ownerRef))
+			}
+
+			others := &clusterv1.Machine{}
+			g.Expect(r.Client.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "others"}, others)).To(Succeed())
+			g.Expect(others.OwnerReferences).To(ConsistOf(otherOwnerRef))
+		})
+	}
+}
+
+func TestMachineSetReconciler_syncMachinesMetadata(t *testing.T) {
+	g := NewWithT(t)
+
+	ms := newMachineSet("ms", testClusterName, int32(1))
+	ms.Spec.Template.Labels["foo"] = "bar-new"
+	ms.Spec.Template.Annotations = map[string]string{"baz": "qux"}
+
+	machine := func(name string, deleting bool) *clusterv1.Machine {
+		m := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: metav1.NamespaceDefault,
+				Labels: map[string]string{
+					clusterv1.ClusterLabelName: testClusterName,
+					"foo":                      "bar-old",
+					"other":                    "label",
+				},
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: testClusterName,
+			},
+		}
+		if deleting {
+			m.Finalizers = []string{"block-deletion"}
+			m.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		return m
+	}
+	current := machine("current", false)
+	deleting := machine("deleting", true)
+
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().WithObjects(current, deleting).Build(),
+	}
+	g.Expect(r.syncMachinesMetadata(ctx, ms, []*clusterv1.Machine{current, deleting})).To(Succeed())
+
+	// The labels and annotations of the machine template are propagated to existing Machines, preserving other labels.
+	got := &clusterv1.Machine{}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(current), got)).To(Succeed())
+	g.Expect(got.Labels).To(Equal(map[string]string{
+		clusterv1.ClusterLabelName: testClusterName,
+		"foo":                      "bar-new",
+		"other":                    "label",
+	}))
+	g.Expect(got.Annotations).To(HaveKeyWithValue("baz", "qux"))
+
+	// Machines being deleted are left untouched.
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(deleting), got)).To(Succeed())
+	g.Expect(got.Labels).To(HaveKeyWithValue("foo", "bar-old"))
+	g.Expect(got.Annotations).ToNot(HaveKey("baz"))
+}
+
+func TestMachineSetReconciler_getDeletionCause(t *testing.T) {
+	md := &clusterv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "md",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.MachineDeploymentSpec{
+			ClusterName: testClusterName,
+			Template:    *newMachineSet("ms", testClusterName, int32(1)).Spec.Template.DeepCopy(),
+		},
+	}
+	md.Spec.Template.Spec.Version = pointer.String("v1.25.0")
+	machineSet := func(version string, ownerRefs ...metav1.OwnerReference) *clusterv1.MachineSet {
+		ms := newMachineSet("ms", testClusterName, int32(1))
+		ms.OwnerReferences = ownerRefs
+		ms.Spec.Template.Spec.Version = pointer.String(version)
+		return ms
+	}
+	mdOwnerRef := metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "MachineDeployment",
+		Name:       md.Name,
+	}
+
+	tests := []struct {
+		name string
+		ms   *clusterv1.MachineSet
+		objs []client.Object
+		want string
+	}{
+		{
+			name: "ScaleDown for MachineSets without a MachineDeployment",
+			ms:   machineSet("v1.25.0"),
+			want: clusterv1.MachineDeletionCauseScaleDown,
+		},
+		{
+			name: "ScaleDown for MachineSets with the machine template of the MachineDeployment",
+			ms:   machineSet("v1.25.0", mdOwnerRef),
+			objs: []client.Object{md},
+			want: clusterv1.MachineDeletionCauseScaleDown,
+		},
+		{
+			name: "Rollout for MachineSets with an old machine template of the MachineDeployment",
+			ms:   machineSet("v1.24.0", mdOwnerRef),
+			objs: []client.Object{md},
+			want: clusterv1.MachineDeletionCauseRollout,
+		},
+		{
+			name: "OwnerDeletion for MachineSets whose MachineDeployment is gone",
+			ms:   machineSet("v1.25.0", mdOwnerRef),
+			want: clusterv1.MachineDeletionCauseOwnerDeletion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().WithObjects(tt.objs...).Build(),
+			}
+
+			got, err := r.getDeletionCause(ctx, tt.ms)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
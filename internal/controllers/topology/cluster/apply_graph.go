@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// applyTask is a step of the apply of a Cluster topology, e.g. the reconcile of the ControlPlane.
+type applyTask struct {
+	name      string
+	dependsOn []string
+	run       func(ctx context.Context) error
+}
+
+// applyGraph is a dependency graph of the steps required to apply a Cluster topology.
+// Steps without a dependency between each other, e.g. the reconcile of two MachineDeployments, are executed
+// concurrently, while a step is executed only after all the steps it depends on are successfully completed.
+// NOTE: Steps can only depend on steps previously added to the graph, thus ensuring the graph is acyclic.
+type applyGraph struct {
+	tasks []*applyTask
+	index map[string]*applyTask
+	err   error
+}
+
+// newApplyGraph returns an empty applyGraph.
+func newApplyGraph() *applyGraph {
+	return &applyGraph{
+		index: map[string]*applyTask{},
+	}
+}
+
+// Add adds a step to the graph, to be executed after all the steps it depends on are successfully completed.
+func (g *applyGraph) Add(name string, run func(ctx context.Context) error, dependsOn ...string) {
+	if g.err != nil {
+		return
+	}
+	if _, ok := g.index[name]; ok {
+		g.err = errors.Errorf("step %q is already part of the apply graph", name)
+		return
+	}
+	for _, d := range dependsOn {
+		if _, ok := g.index[d]; !ok {
+			g.err = errors.Errorf("step %q depends on step %q which is not part of the apply graph", name, d)
+			return
+		}
+	}
+
+	t := &applyTask{name: name, dependsOn: dependsOn, run: run}
+	g.tasks = append(g.tasks, t)
+	g.index[name] = t
+}
+
+// Run executes all the steps of the graph, running at most maxConcurrency steps at the same time.
+// Steps depending on a failed step are not executed, while steps not depending on it are still executed.
+// If a single step fails its error is returned as is, so callers can inspect it, e.g. with errors.As; otherwise
+// all the errors are aggregated in the order the steps have been added to the graph, so the message is stable.
+func (g *applyGraph) Run(ctx context.Context, maxConcurrency int) error {
+	if g.err != nil {
+		return g.err
+	}
+	if maxConcurrency < 1 {
+		maxConcurrency = 1
+	}
+
+	done := make(map[string]chan struct{}, len(g.tasks))
+	for _, t := range g.tasks {
+		done[t.name] = make(chan struct{})
+	}
+	errs := make([]error, len(g.tasks))
+	failed := make(map[string]bool, len(g.tasks))
+	var failedLock sync.Mutex
+
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	for i, t := range g.tasks {
+		wg.Add(1)
+		go func(i int, t *applyTask) {
+			defer wg.Done()
+			defer close(done[t.name])
+
+			// Wait for the steps this step depends on, and skip it if any of them failed.
+			for _, d := range t.dependsOn {
+				<-done[d]
+			}
+			failedLock.Lock()
+			for _, d := range t.dependsOn {
+				if failed[d] {
+					failed[t.name] = true
+				}
+			}
+			skip := failed[t.name]
+			failedLock.Unlock()
+			if skip {
+				return
+			}
+
+			sem <- struct{}{}
+			err := t.run(ctx)
+			<-sem
+
+			if err != nil {
+				errs[i] = err
+				failedLock.Lock()
+				failed[t.name] = true
+				failedLock.Unlock()
+			}
+		}(i, t)
+	}
+	wg.Wait()
+
+	var failures []error
+	for _, err := range errs {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	if len(failures) == 1 {
+		return failures[0]
+	}
+	return kerrors.NewAggregate(failures)
+}
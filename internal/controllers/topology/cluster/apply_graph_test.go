@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestApplyGraph(t *testing.T) {
+	t.Run("Runs steps after the steps they depend on", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var lock sync.Mutex
+		var order []string
+		step := func(name string) func(context.Context) error {
+			return func(context.Context) error {
+				lock.Lock()
+				defer lock.Unlock()
+				order = append(order, name)
+				return nil
+			}
+		}
+
+		graph := newApplyGraph()
+		graph.Add("a", step("a"))
+		graph.Add("b", step("b"))
+		graph.Add("c", step("c"), "a", "b")
+		graph.Add("d", step("d"), "c")
+
+		g.Expect(graph.Run(ctx, 5)).To(Succeed())
+		g.Expect(order).To(HaveLen(4))
+		g.Expect(order[:2]).To(ConsistOf("a", "b"))
+		g.Expect(order[2:]).To(Equal([]string{"c", "d"}))
+	})
+
+	t.Run("Runs independent steps concurrently", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// Each step waits for the other one to start, thus the graph completes only if they run concurrently.
+		var started sync.WaitGroup
+		started.Add(2)
+		step := func(context.Context) error {
+			started.Done()
+			started.Wait()
+			return nil
+		}
+
+		graph := newApplyGraph()
+		graph.Add("a", step)
+		graph.Add("b", step)
+
+		g.Expect(graph.Run(ctx, 2)).To(Succeed())
+	})
+
+	t.Run("Skips steps depending on a failed step and returns all the errors", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var lock sync.Mutex
+		var run []string
+		step := func(name string, err error) func(context.Context) error {
+			return func(context.Context) error {
+				lock.Lock()
+				defer lock.Unlock()
+				run = append(run, name)
+				return err
+			}
+		}
+
+		graph := newApplyGraph()
+		graph.Add("a", step("a", errors.New("a failed")))
+		graph.Add("b", step("b", errors.New("b failed")))
+		graph.Add("c", step("c", nil))
+		graph.Add("d", step("d", nil), "a")
+		graph.Add("e", step("e", nil), "d")
+
+		err := graph.Run(ctx, 1)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(Equal("[a failed, b failed]"))
+		g.Expect(run).To(ConsistOf("a", "b", "c"))
+	})
+
+	t.Run("Returns the error of a single failed step as is", func(t *testing.T) {
+		g := NewWithT(t)
+
+		stepErr := &ownershipError{Object: "a", Reason: "is not topology owned"}
+
+		graph := newApplyGraph()
+		graph.Add("a", func(context.Context) error { return errors.Wrap(stepErr, "failed to reconcile a") })
+		graph.Add("b", func(context.Context) error { return nil })
+
+		err := graph.Run(ctx, 2)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(Equal("failed to reconcile a: a is not topology owned"))
+		g.Expect(getOwnershipError(err)).To(Equal(stepErr))
+	})
+
+	t.Run("Fails if a step depends on a step not part of the graph", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newApplyGraph()
+		graph.Add("a", func(context.Context) error { return nil }, "b")
+
+		g.Expect(graph.Run(ctx, 1)).ToNot(Succeed())
+	})
+
+	t.Run("Fails if a step is added twice", func(t *testing.T) {
+		g := NewWithT(t)
+
+		graph := newApplyGraph()
+		graph.Add("a", func(context.Context) error { return nil })
+		graph.Add("a", func(context.Context) error { return nil })
+
+		g.Expect(graph.Run(ctx, 1)).ToNot(Succeed())
+	})
+}
@@ -20,11 +20,13 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/util"
 )
 
 // getBlueprint gets a ClusterBlueprint with the ClusterClass and the referenced templates to be used for a managed Cluster topology.
@@ -35,6 +37,7 @@ func (r *Reconciler) getBlueprint(ctx context.Context, cluster *clusterv1.Cluste
 		Topology:           cluster.Spec.Topology,
 		ClusterClass:       &clusterv1.ClusterClass{},
 		MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{},
+		MachinePools:       map[string]*scope.MachinePoolBlueprint{},
 	}
 
 	// Get ClusterClass.
@@ -43,28 +46,65 @@ func (r *Reconciler) getBlueprint(ctx context.Context, cluster *clusterv1.Cluste
 		return nil, errors.Wrapf(err, "failed to retrieve ClusterClass/%s", cluster.Spec.Topology.Class)
 	}
 
+	// Resolve the values of the topology variables read from Secrets or ConfigMaps.
 	var err error
+	blueprint.Topology, err = r.resolveTopologyVariables(ctx, cluster, blueprint.ClusterClass)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve variables for %s", tlog.KObj{Obj: cluster})
+	}
+
+	// Get the reader to be used for reading the templates referenced by the ClusterClass.
+	templateReader, err := r.getTemplateReader(cluster.Namespace)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get templates for %s", tlog.KObj{Obj: blueprint.ClusterClass})
+	}
+
 	// Get ClusterClass.spec.infrastructure.
-	blueprint.InfrastructureClusterTemplate, err = r.getReference(ctx, blueprint.ClusterClass.Spec.Infrastructure.Ref)
+	blueprint.InfrastructureClusterTemplate, err = getReferenceFrom(ctx, templateReader, blueprint.ClusterClass.Spec.Infrastructure.Ref)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get infrastructure cluster template for %s", tlog.KObj{Obj: blueprint.ClusterClass})
 	}
 
+	// If the controlPlaneLoadBalancer builtin patch is enabled, read the fields of the control plane load balancer
+	// declared by the annotations of the CustomResourceDefinition of the InfrastructureClusterTemplate.
+	for _, patch := range blueprint.BuiltinPatches() {
+		if patch != clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer {
+			continue
+		}
+		crdMetadata, err := util.GetGVKMetadata(ctx, r.Client, blueprint.InfrastructureClusterTemplate.GroupVersionKind())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get CustomResourceDefinition of %s", tlog.KObj{Obj: blueprint.InfrastructureClusterTemplate})
+		}
+		blueprint.InfrastructureClusterTemplateCRDAnnotations = crdMetadata.GetAnnotations()
+	}
+
 	// Get ClusterClass.spec.controlPlane.
 	blueprint.ControlPlane = &scope.ControlPlaneBlueprint{}
-	blueprint.ControlPlane.Template, err = r.getReference(ctx, blueprint.ClusterClass.Spec.ControlPlane.Ref)
+	blueprint.ControlPlane.Template, err = getReferenceFrom(ctx, templateReader, blueprint.ClusterClass.Spec.ControlPlane.Ref)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get control plane template for %s", tlog.KObj{Obj: blueprint.ClusterClass})
 	}
 
 	// If the clusterClass mandates the controlPlane has infrastructureMachines, read it.
 	if blueprint.HasControlPlaneInfrastructureMachine() {
-		blueprint.ControlPlane.InfrastructureMachineTemplate, err = r.getReference(ctx, blueprint.ClusterClass.Spec.ControlPlane.MachineInfrastructure.Ref)
+		blueprint.ControlPlane.InfrastructureMachineTemplate, err = getReferenceFrom(ctx, templateReader, blueprint.ClusterClass.Spec.ControlPlane.MachineInfrastructure.Ref)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get control plane's machine template for %s", tlog.KObj{Obj: blueprint.ClusterClass})
 		}
 	}
 
+	// If the clusterClass defines infrastructureMachines for the controlPlane in specific failure domains, read them.
+	if blueprint.HasControlPlaneFailureDomainInfrastructureMachines() {
+		blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates = map[string]*unstructured.Unstructured{}
+		for _, machineInfrastructure := range blueprint.ClusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure {
+			template, err := getReferenceFrom(ctx, templateReader, machineInfrastructure.Ref)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get control plane's machine template for %s, failure domain %q", tlog.KObj{Obj: blueprint.ClusterClass}, machineInfrastructure.FailureDomain)
+			}
+			blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates[machineInfrastructure.FailureDomain] = template
+		}
+	}
+
 	// If the clusterClass defines a valid MachineHealthCheck (including a defined MachineInfrastructure) set the blueprint MachineHealthCheck.
 	if blueprint.HasControlPlaneMachineHealthCheck() {
 		blueprint.ControlPlane.MachineHealthCheck = blueprint.ClusterClass.Spec.ControlPlane.MachineHealthCheck
@@ -81,17 +121,27 @@ func (r *Reconciler) getBlueprint(ctx context.Context, cluster *clusterv1.Cluste
 		machineDeploymentClass.Template.Metadata.DeepCopyInto(&machineDeploymentBlueprint.Metadata)
 
 		// Get the infrastructure machine template.
-		machineDeploymentBlueprint.InfrastructureMachineTemplate, err = r.getReference(ctx, machineDeploymentClass.Template.Infrastructure.Ref)
+		machineDeploymentBlueprint.InfrastructureMachineTemplate, err = getReferenceFrom(ctx, templateReader, machineDeploymentClass.Template.Infrastructure.Ref)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get infrastructure machine template for %s, MachineDeployment class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machineDeploymentClass.Class)
 		}
 
 		// Get the bootstrap machine template.
-		machineDeploymentBlueprint.BootstrapTemplate, err = r.getReference(ctx, machineDeploymentClass.Template.Bootstrap.Ref)
+		machineDeploymentBlueprint.BootstrapTemplate, err = getReferenceFrom(ctx, templateReader, machineDeploymentClass.Template.Bootstrap.Ref)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get bootstrap machine template for %s, MachineDeployment class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machineDeploymentClass.Class)
 		}
 
+		// Validate the templates of each MachineDeployment class independently, given that different classes
+		// can use different bootstrap and infrastructure providers, e.g. kubeadm for Linux nodes and a
+		// different bootstrap provider for Windows nodes.
+		if err := validateMachineTemplate(machineDeploymentBlueprint.InfrastructureMachineTemplate); err != nil {
+			return nil, errors.Wrapf(err, "invalid infrastructure machine template for %s, MachineDeployment class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machineDeploymentClass.Class)
+		}
+		if err := validateMachineTemplate(machineDeploymentBlueprint.BootstrapTemplate); err != nil {
+			return nil, errors.Wrapf(err, "invalid bootstrap machine template for %s, MachineDeployment class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machineDeploymentClass.Class)
+		}
+
 		// If the machineDeploymentClass defines a MachineHealthCheck add it to the blueprint.
 		if machineDeploymentClass.MachineHealthCheck != nil {
 			machineDeploymentBlueprint.MachineHealthCheck = machineDeploymentClass.MachineHealthCheck
@@ -99,5 +149,52 @@ func (r *Reconciler) getBlueprint(ctx context.Context, cluster *clusterv1.Cluste
 		blueprint.MachineDeployments[machineDeploymentClass.Class] = machineDeploymentBlueprint
 	}
 
+	// Loop over the machine pools classes in ClusterClass
+	// and fetch the related templates.
+	for _, machinePoolClass := range blueprint.ClusterClass.Spec.Workers.MachinePools {
+		machinePoolBlueprint := &scope.MachinePoolBlueprint{}
+
+		// Make sure to copy the metadata from the blueprint, which is later layered
+		// with the additional metadata defined in the Cluster's topology section
+		// for the MachinePool that is created or updated.
+		machinePoolClass.Template.Metadata.DeepCopyInto(&machinePoolBlueprint.Metadata)
+
+		// Get the infrastructure machine pool template.
+		machinePoolBlueprint.InfrastructureMachinePoolTemplate, err = getReferenceFrom(ctx, templateReader, machinePoolClass.Template.Infrastructure.Ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get infrastructure machine pool template for %s, MachinePool class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machinePoolClass.Class)
+		}
+
+		// Get the bootstrap config template.
+		machinePoolBlueprint.BootstrapTemplate, err = getReferenceFrom(ctx, templateReader, machinePoolClass.Template.Bootstrap.Ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get bootstrap config template for %s, MachinePool class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machinePoolClass.Class)
+		}
+
+		// The bootstrap config and the infrastructure machine pool are cloned from spec.template of the templates.
+		if err := validateMachineTemplate(machinePoolBlueprint.InfrastructureMachinePoolTemplate); err != nil {
+			return nil, errors.Wrapf(err, "invalid infrastructure machine pool template for %s, MachinePool class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machinePoolClass.Class)
+		}
+		if err := validateMachineTemplate(machinePoolBlueprint.BootstrapTemplate); err != nil {
+			return nil, errors.Wrapf(err, "invalid bootstrap config template for %s, MachinePool class %q", tlog.KObj{Obj: blueprint.ClusterClass}, machinePoolClass.Class)
+		}
+		blueprint.MachinePools[machinePoolClass.Class] = machinePoolBlueprint
+	}
+
 	return blueprint, nil
 }
+
+// validateMachineTemplate checks that a template used to create the bootstrap configs or the infrastructure machines
+// of a MachineDeployment or a MachinePool satisfies the contract for templates, i.e. it defines spec.template; this is
+// the part of the template cloned by the MachineSet controller or by the topology controller, no matter of the provider
+// implementing the template.
+func validateMachineTemplate(template *unstructured.Unstructured) error {
+	_, found, err := unstructured.NestedMap(template.Object, "spec", "template")
+	if err != nil {
+		return errors.Wrapf(err, "failed to read spec.template from %s", tlog.KObj{Obj: template})
+	}
+	if !found {
+		return errors.Errorf("%s does not satisfy the contract for templates: spec.template is not set", tlog.KObj{Obj: template})
+	}
+	return nil
+}
@@ -39,6 +39,8 @@ func TestGetBlueprint(t *testing.T) {
 		builder.GenericInfrastructureMachineCRD,
 		builder.GenericControlPlaneTemplateCRD,
 		builder.GenericBootstrapConfigTemplateCRD,
+		builder.TestInfrastructureMachineTemplateCRD,
+		builder.TestBootstrapConfigTemplateCRD,
 	}
 
 	// The following is a block creating a number of objects for use in the test cases.
@@ -73,6 +75,25 @@ func TestGetBlueprint(t *testing.T) {
 
 	mds := []clusterv1.MachineDeploymentClass{*machineDeployment}
 
+	// A MachineDeployment class using different bootstrap and infrastructure providers.
+	otherWorkerInfrastructureMachineTemplate := builder.TestInfrastructureMachineTemplate(metav1.NamespaceDefault, "otherworkerinframachinetemplate1").
+		Build()
+	otherWorkerBootstrapTemplate := builder.TestBootstrapTemplate(metav1.NamespaceDefault, "otherworkerbootstraptemplate1").
+		Build()
+	otherMachineDeployment := builder.MachineDeploymentClass("workerclass2").
+		WithInfrastructureTemplate(otherWorkerInfrastructureMachineTemplate).
+		WithBootstrapTemplate(otherWorkerBootstrapTemplate).
+		Build()
+
+	// A bootstrap template which does not satisfy the contract for templates.
+	invalidWorkerBootstrapTemplate := builder.TestBootstrapTemplate(metav1.NamespaceDefault, "invalidworkerbootstraptemplate1").
+		Build()
+	delete(invalidWorkerBootstrapTemplate.Object, "spec")
+	invalidMachineDeployment := builder.MachineDeploymentClass("workerclass3").
+		WithInfrastructureTemplate(otherWorkerInfrastructureMachineTemplate).
+		WithBootstrapTemplate(invalidWorkerBootstrapTemplate).
+		Build()
+
 	// Define test cases.
 	tests := []struct {
 		name         string
@@ -223,6 +244,65 @@ func TestGetBlueprint(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Should read a ClusterClass with MachineDeploymentClasses using different bootstrap and infrastructure providers",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(infraClusterTemplate).
+				WithControlPlaneTemplate(controlPlaneTemplate).
+				WithWorkerMachineDeploymentClasses(*machineDeployment, *otherMachineDeployment).
+				Build(),
+			objects: []client.Object{
+				infraClusterTemplate,
+				controlPlaneTemplate,
+				workerInfrastructureMachineTemplate,
+				workerBootstrapTemplate,
+				otherWorkerInfrastructureMachineTemplate,
+				otherWorkerBootstrapTemplate,
+			},
+			want: &scope.ClusterBlueprint{
+				ClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+					WithInfrastructureClusterTemplate(infraClusterTemplate).
+					WithControlPlaneTemplate(controlPlaneTemplate).
+					WithWorkerMachineDeploymentClasses(*machineDeployment, *otherMachineDeployment).
+					Build(),
+				InfrastructureClusterTemplate: infraClusterTemplate,
+				ControlPlane: &scope.ControlPlaneBlueprint{
+					Template: controlPlaneTemplate,
+				},
+				MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+					"workerclass1": {
+						Metadata: clusterv1.ObjectMeta{
+							Labels:      map[string]string{"foo": "bar"},
+							Annotations: map[string]string{"a": "b"},
+						},
+						InfrastructureMachineTemplate: workerInfrastructureMachineTemplate,
+						BootstrapTemplate:             workerBootstrapTemplate,
+						MachineHealthCheck:            machineHealthCheck,
+					},
+					"workerclass2": {
+						InfrastructureMachineTemplate: otherWorkerInfrastructureMachineTemplate,
+						BootstrapTemplate:             otherWorkerBootstrapTemplate,
+					},
+				},
+			},
+		},
+		{
+			name: "Fails if ClusterClass has a MachineDeploymentClass referencing a BootstrapTemplate which does not satisfy the contract",
+			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(infraClusterTemplate).
+				WithControlPlaneTemplate(controlPlaneTemplate).
+				WithWorkerMachineDeploymentClasses(*machineDeployment, *invalidMachineDeployment).
+				Build(),
+			objects: []client.Object{
+				infraClusterTemplate,
+				controlPlaneTemplate,
+				workerInfrastructureMachineTemplate,
+				workerBootstrapTemplate,
+				otherWorkerInfrastructureMachineTemplate,
+				invalidWorkerBootstrapTemplate,
+			},
+			wantErr: true,
+		},
 		{
 			name: "Fails if ClusterClass has a MachineDeploymentClass referencing a BootstrapTemplate that does not exist",
 			clusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").
@@ -341,3 +421,56 @@ func TestGetBlueprint(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBlueprintInfrastructureClusterTemplateCRDAnnotations(t *testing.T) {
+	infraClusterTemplateCRD := builder.GenericInfrastructureClusterTemplateCRD.DeepCopy()
+	infraClusterTemplateCRD.SetAnnotations(map[string]string{
+		clusterv1.ControlPlaneLoadBalancerSchemePathAnnotation: "spec.template.spec.controlPlaneLoadBalancer.scheme",
+	})
+	crds := []client.Object{
+		infraClusterTemplateCRD,
+		builder.GenericControlPlaneTemplateCRD,
+	}
+	infraClusterTemplate := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infraclustertemplate1").Build()
+	controlPlaneTemplate := builder.ControlPlaneTemplate(metav1.NamespaceDefault, "controlplanetemplate1").Build()
+
+	tests := []struct {
+		name            string
+		builtinPatches  string
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "Read the annotations if the controlPlaneLoadBalancer builtin patch is enabled",
+			builtinPatches:  clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer,
+			wantAnnotations: infraClusterTemplateCRD.GetAnnotations(),
+		},
+		{
+			name:           "Do not read the annotations if the controlPlaneLoadBalancer builtin patch is not enabled",
+			builtinPatches: clusterv1.ClusterTopologyBuiltinPatchProxy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(infraClusterTemplate).
+				WithControlPlaneTemplate(controlPlaneTemplate).
+				Build()
+			clusterClass.SetAnnotations(map[string]string{clusterv1.ClusterTopologyBuiltinPatchesAnnotation: tt.builtinPatches})
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass(clusterClass.Name).Build()).
+				Build()
+
+			objs := append([]client.Object{clusterClass, infraClusterTemplate, controlPlaneTemplate}, crds...)
+			fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(objs...).Build()
+			r := &Reconciler{
+				Client:                    fakeClient,
+				UnstructuredCachingClient: fakeClient,
+			}
+			got, err := r.getBlueprint(ctx, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.InfrastructureClusterTemplateCRDAnnotations).To(Equal(tt.wantAnnotations))
+		})
+	}
+}
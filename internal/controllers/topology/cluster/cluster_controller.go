@@ -23,20 +23,24 @@ import (
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
@@ -48,6 +52,7 @@ import (
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
@@ -57,6 +62,7 @@ import (
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusterclasses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinehealthchecks,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;create;delete
 
@@ -83,10 +89,37 @@ type Reconciler struct {
 	patchEngine patches.Engine
 
 	patchHelperFactory structuredmerge.PatchHelperFactoryFunc
+
+	// SyncPeriod is the interval at which Clusters with a managed topology are periodically reconciled; if set, the
+	// resync events of the manager are ignored, so the interval can be both shorter and longer than the sync period
+	// of the manager. If zero, Clusters are reconciled at the sync period of the manager.
+	SyncPeriod time.Duration
+
+	// DriftCheckInterval is the interval between periodic checks for out-of-band changes to the objects
+	// generated from the topology of Clusters with the topology.cluster.x-k8s.io/drift-policy annotation.
+	DriftCheckInterval time.Duration
+
+	// MaxConcurrentApplies is the maximum number of objects generated from the topology of a Cluster, e.g. MachineDeployments,
+	// which are reconciled concurrently. Defaults to 1.
+	MaxConcurrentApplies int
+
+	// TemplateReaderServiceAccount is the name of the ServiceAccount impersonated when reading the templates referenced
+	// by the ClusterClass of a Cluster; the ServiceAccount is looked up in the namespace of the Cluster.
+	// If empty, templates are read with the permissions of the controller.
+	// NOTE: The permission to impersonate the ServiceAccount is not granted by default; it is provided by the opt-in
+	// config/template-reader kustomization, restricted to ServiceAccounts with the given name.
+	TemplateReaderServiceAccount string
+
+	// templateReader returns the reader to be used for reading the templates referenced by a ClusterClass.
+	templateReader templateReaderFunc
+
+	// reconcileCache is used to skip the computation and the apply of the desired state of Cluster topologies
+	// not changed since they were last reconciled into a steady state; if nil, Cluster topologies are always reconciled.
+	reconcileCache *reconcileCache
 }
 
 func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
-	c, err := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.Cluster{}, builder.WithPredicates(
 			// Only reconcile Cluster with topology.
 			predicates.ClusterHasTopology(ctrl.LoggerFrom(ctx)),
@@ -101,8 +134,23 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 			handler.EnqueueRequestsFromMapFunc(r.machineDeploymentToCluster),
 			// Only trigger Cluster reconciliation if the MachineDeployment is topology owned.
 			builder.WithPredicates(predicates.ResourceIsTopologyOwned(ctrl.LoggerFrom(ctx))),
-		).
-		WithOptions(options).
+		)
+	if feature.Gates.Enabled(feature.MachinePool) {
+		b = b.Watches(
+			&source.Kind{Type: &expv1.MachinePool{}},
+			handler.EnqueueRequestsFromMapFunc(r.machinePoolToCluster),
+			// Only trigger Cluster reconciliation if the MachinePool is topology owned.
+			builder.WithPredicates(predicates.ResourceIsTopologyOwned(ctrl.LoggerFrom(ctx))),
+		)
+	}
+	var resyncPredicates []predicate.Predicate
+	if r.SyncPeriod > 0 {
+		// Clusters are requeued after SyncPeriod, so the resync events of the manager are ignored.
+		resyncPredicate := predicate.ResourceVersionChangedPredicate{}
+		b = b.WithEventFilter(resyncPredicate)
+		resyncPredicates = append(resyncPredicates, resyncPredicate)
+	}
+	c, err := b.WithOptions(options).
 		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
 		Build(r)
 
@@ -112,12 +160,17 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 
 	r.externalTracker = external.ObjectTracker{
 		Controller: c,
+		Predicates: resyncPredicates,
 	}
 	r.patchEngine = patches.NewEngine(r.RuntimeClient)
 	r.recorder = mgr.GetEventRecorderFor("topology/cluster")
+	if r.TemplateReaderServiceAccount != "" {
+		r.templateReader = impersonatingTemplateReader(mgr.GetConfig(), mgr.GetScheme(), mgr.GetRESTMapper(), r.TemplateReaderServiceAccount)
+	}
 	if r.patchHelperFactory == nil {
 		r.patchHelperFactory = serverSideApplyPatchHelperFactory(r.Client)
 	}
+	r.reconcileCache = newReconcileCache()
 	return nil
 }
 
@@ -128,7 +181,7 @@ func (r *Reconciler) SetupForDryRun(recorder record.EventRecorder) {
 	r.patchHelperFactory = dryRunPatchHelperFactory(r.Client)
 }
 
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (retRes ctrl.Result, reterr error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	// Fetch the Cluster instance.
@@ -139,6 +192,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	// do not use the live client the second reconcile loop could potentially pick up the stale cluster object from the cache.
 	if err := r.APIReader.Get(ctx, req.NamespacedName, cluster); err != nil {
 		if apierrors.IsNotFound(err) {
+			deleteMachineMetrics(req.Namespace, req.Name)
+			r.forgetReconciledState(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -152,9 +207,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	// there are MachineDeployments which have the topology owned label, but the corresponding
 	// cluster is not topology owned.
 	if cluster.Spec.Topology == nil {
+		deleteMachineMetrics(cluster.Namespace, cluster.Name)
+		r.forgetReconciledState(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
+	ctx, log = clog.AddValues(ctx, "ClusterClass", klog.KRef(cluster.Namespace, cluster.Spec.Topology.Class))
+
+	// Periodically reconcile the Cluster, if a sync period is set for the controller.
+	defer func() {
+		if reterr == nil && r.SyncPeriod > 0 {
+			retRes = util.LowestNonZeroResult(retRes, ctrl.Result{RequeueAfter: r.SyncPeriod})
+		}
+	}()
+
 	// Return early if the Cluster is paused.
 	// TODO: What should we do if the cluster class is paused?
 	if annotations.IsPaused(cluster, cluster) {
@@ -177,14 +243,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	// additional information will be added about the Cluster blueprint, current state and desired state.
 	s := scope.New(cluster)
 
+	var reconcileErr error
 	defer func() {
-		if err := r.reconcileConditions(s, cluster, reterr); err != nil {
+		if err := r.reconcileConditions(s, cluster, reconcileErr); err != nil {
 			reterr = kerrors.NewAggregate([]error{reterr, errors.Wrap(err, "failed to reconcile cluster topology conditions")})
 			return
 		}
+		// Record the generation the topology conditions refer to, so clients can tell if they reflect the latest spec.
+		cluster.Status.TopologyObservedGeneration = cluster.Generation
 		options := []patch.Option{
 			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyInSyncCondition,
+				clusterv1.TopologyMetadataConsistentCondition,
 			}},
 			patch.WithForceOverwriteConditions{},
 		}
@@ -195,13 +266,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Re
 	}()
 
 	// Handle normal reconciliation loop.
-	return r.reconcile(ctx, s)
+	var result ctrl.Result
+	result, reconcileErr = r.reconcile(ctx, s)
+
+	// If a webhook is not available, e.g. while a provider is being upgraded, requeue with a jittered backoff instead
+	// of returning the error, which would lead to hot retries; the TopologyReconciled condition reports the webhook.
+	if webhook := getUnavailableWebhook(reconcileErr); webhook != nil {
+		log.Info("Reconcile blocked by a webhook which is not available, retrying later", "webhook", webhook.Webhook, "service", webhook.Service, "reason", reconcileErr.Error())
+		return ctrl.Result{RequeueAfter: webhookUnavailableBackoff()}, nil
+	}
+	return result, reconcileErr
 }
 
 // reconcile handles cluster reconciliation.
 func (r *Reconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result, error) {
 	var err error
 
+	// Return early if the reconciliation of the Cluster topology is paused; the TopologyReconciled condition
+	// reports the topology is paused.
+	if isTopologyPaused(s.Current.Cluster) {
+		ctrl.LoggerFrom(ctx).Info("Reconciliation of the Cluster topology is paused")
+		return ctrl.Result{}, nil
+	}
+
 	// Gets the blueprint with the ClusterClass and the referenced templates
 	// and store it in the request scope.
 	s.Blueprint, err = r.getBlueprint(ctx, s.Current.Cluster)
@@ -215,6 +302,14 @@ func (r *Reconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result
 		return ctrl.Result{}, errors.Wrap(err, "error reading current state of the Cluster topology")
 	}
 
+	// Report metrics about Machines of the Cluster topology, e.g. to track the progress of upgrades.
+	reportMachineMetrics(s)
+
+	// If the Cluster topology is in plan mode, report the changes which would be applied without applying them.
+	if isTopologyPlan(s.Current.Cluster) {
+		return ctrl.Result{}, r.reconcilePlan(ctx, s)
+	}
+
 	// The cluster topology is yet to be created. Call the BeforeClusterCreate hook before proceeding.
 	if feature.Gates.Enabled(feature.RuntimeSDK) {
 		res, err := r.callBeforeClusterCreateHook(ctx, s)
@@ -231,6 +326,25 @@ func (r *Reconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result
 		return ctrl.Result{}, errors.Wrap(err, "error creating dynamic watch")
 	}
 
+	// Skip computing and applying the desired state if the Cluster topology, the blueprint and the current state
+	// did not change since the Cluster topology was last reconciled into a steady state.
+	clusterKey := client.ObjectKeyFromObject(s.Current.Cluster)
+	var reconcileHash string
+	if r.reconcileCache != nil {
+		hash, cacheable, err := computeReconcileHash(s, time.Now())
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "error computing the hash of the Cluster topology state")
+		}
+		if cacheable && r.reconcileCache.Has(clusterKey, hash) {
+			ctrl.LoggerFrom(ctx).V(5).Info("Cluster topology not changed since it was last reconciled, skipping")
+			return ctrl.Result{RequeueAfter: nextMachineDeploymentRolloutAfter(s.Current.Cluster, time.Now())}, nil
+		}
+		if cacheable {
+			reconcileHash = hash
+		}
+		r.reconcileCache.Delete(clusterKey)
+	}
+
 	// Computes the desired state of the Cluster and store it in the request scope.
 	s.Desired, err = r.computeDesiredState(ctx, s)
 	if err != nil {
@@ -248,10 +362,34 @@ func (r *Reconciler) reconcile(ctx context.Context, s *scope.Scope) (ctrl.Result
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
-	return ctrl.Result{}, nil
+	// Remember the state of Cluster topologies reconciled into a steady state, so next reconciles can be skipped
+	// until something changes.
+	if reconcileHash != "" && isSteadyState(s) {
+		r.reconcileCache.Set(clusterKey, reconcileHash)
+	}
+
+	// If the rollout of a MachineDeployment is requested for a later time, reconcile again when it is reached.
+	requeueAfter = nextMachineDeploymentRolloutAfter(s.Current.Cluster, time.Now())
+
+	// If drift detection is enabled, periodically check the objects generated from the Cluster topology
+	// for out-of-band changes.
+	if getDriftPolicy(s.Current.Cluster) != "" && r.DriftCheckInterval > 0 && (requeueAfter == 0 || r.DriftCheckInterval < requeueAfter) {
+		requeueAfter = r.DriftCheckInterval
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// forgetReconciledState removes the state last reconciled for a Cluster topology from the reconcile cache.
+func (r *Reconciler) forgetReconciledState(key client.ObjectKey) {
+	if r.reconcileCache != nil {
+		r.reconcileCache.Delete(key)
+		r.reconcileCache.DeleteObjects(key)
+	}
 }
 
-// setupDynamicWatches create watches for InfrastructureCluster and ControlPlane CRs when they exist.
+// setupDynamicWatches create watches for InfrastructureCluster and ControlPlane CRs when they exist,
+// and for the templates referenced by the ClusterClass.
 func (r *Reconciler) setupDynamicWatches(ctx context.Context, s *scope.Scope) error {
 	if s.Current.InfrastructureCluster != nil {
 		if err := r.externalTracker.Watch(ctrl.LoggerFrom(ctx), s.Current.InfrastructureCluster,
@@ -269,6 +407,15 @@ func (r *Reconciler) setupDynamicWatches(ctx context.Context, s *scope.Scope) er
 			return errors.Wrap(err, "error watching ControlPlane CR")
 		}
 	}
+
+	// Watch the templates referenced by the ClusterClass, so changes to the templates are immediately
+	// applied to the Clusters using the ClusterClass.
+	for _, template := range s.Blueprint.Templates() {
+		if err := r.externalTracker.Watch(ctrl.LoggerFrom(ctx), template,
+			handler.EnqueueRequestsFromMapFunc(r.templateToCluster)); err != nil {
+			return errors.Wrapf(err, "error watching %s", template.GetKind())
+		}
+	}
 	return nil
 }
 
@@ -301,12 +448,29 @@ func (r *Reconciler) clusterClassToCluster(o client.Object) []ctrl.Request {
 		panic(fmt.Sprintf("Expected a ClusterClass but got a %T", o))
 	}
 
+	return r.clusterClassToClusterRequests(clusterClass.Namespace, clusterClass.Name)
+}
+
+// templateToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update when a template referenced by its own ClusterClass gets updated.
+// NOTE: The ClusterClasses referencing a template are identified via owner references, which are
+// set by the ClusterClass controller.
+func (r *Reconciler) templateToCluster(o client.Object) []ctrl.Request {
+	requests := []ctrl.Request{}
+	for _, clusterClassName := range clusterClassOwnerNames(o) {
+		requests = append(requests, r.clusterClassToClusterRequests(o.GetNamespace(), clusterClassName)...)
+	}
+	return requests
+}
+
+// clusterClassToClusterRequests returns a request for each of the Clusters using a ClusterClass.
+func (r *Reconciler) clusterClassToClusterRequests(namespace, clusterClassName string) []ctrl.Request {
 	clusterList := &clusterv1.ClusterList{}
 	if err := r.Client.List(
 		context.TODO(),
 		clusterList,
-		client.MatchingFields{index.ClusterClassNameField: clusterClass.Name},
-		client.InNamespace(clusterClass.Namespace),
+		client.MatchingFields{index.ClusterClassNameField: clusterClassName},
+		client.InNamespace(namespace),
 	); err != nil {
 		return nil
 	}
@@ -320,6 +484,22 @@ func (r *Reconciler) clusterClassToCluster(o client.Object) []ctrl.Request {
 	return requests
 }
 
+// clusterClassOwnerNames returns the names of the ClusterClasses owning an object.
+func clusterClassOwnerNames(o client.Object) []string {
+	names := []string{}
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.Kind != "ClusterClass" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != clusterv1.GroupVersion.Group {
+			continue
+		}
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
 // machineDeploymentToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
 // for Cluster to update when one of its own MachineDeployments gets updated.
 func (r *Reconciler) machineDeploymentToCluster(o client.Object) []ctrl.Request {
@@ -339,10 +519,31 @@ func (r *Reconciler) machineDeploymentToCluster(o client.Object) []ctrl.Request
 	}}
 }
 
+// machinePoolToCluster is a handler.ToRequestsFunc to be used to enqueue requests for reconciliation
+// for Cluster to update when one of its own MachinePools gets updated.
+func (r *Reconciler) machinePoolToCluster(o client.Object) []ctrl.Request {
+	mp, ok := o.(*expv1.MachinePool)
+	if !ok {
+		panic(fmt.Sprintf("Expected a MachinePool but got a %T", o))
+	}
+	if mp.Spec.ClusterName == "" {
+		return nil
+	}
+
+	return []ctrl.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: mp.Namespace,
+			Name:      mp.Spec.ClusterName,
+		},
+	}}
+}
+
 func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
 	// Call the BeforeClusterDelete hook if the 'ok-to-delete' annotation is not set
 	// and add the annotation to the cluster after receiving a successful non-blocking response.
 	log := tlog.LoggerFrom(ctx)
+	deleteMachineMetrics(cluster.Namespace, cluster.Name)
+	r.forgetReconciledState(client.ObjectKeyFromObject(cluster))
 	if feature.Gates.Enabled(feature.RuntimeSDK) {
 		if !hooks.IsOkToDelete(cluster) {
 			hookRequest := &runtimehooksv1.BeforeClusterDeleteRequest{
@@ -366,6 +567,12 @@ func (r *Reconciler) reconcileDelete(ctx context.Context, cluster *clusterv1.Clu
 	return ctrl.Result{}, nil
 }
 
+// isTopologyPaused returns true if the reconciliation of the Cluster topology is paused.
+func isTopologyPaused(cluster *clusterv1.Cluster) bool {
+	_, ok := cluster.GetAnnotations()[clusterv1.ClusterTopologyPausedAnnotation]
+	return ok
+}
+
 // serverSideApplyPatchHelperFactory makes use of managed fields provided by server side apply and is used by the controller.
 func serverSideApplyPatchHelperFactory(c client.Client) structuredmerge.PatchHelperFactoryFunc {
 	return func(ctx context.Context, original, modified client.Object, opts ...structuredmerge.HelperOption) (structuredmerge.PatchHelper, error) {
@@ -320,6 +320,69 @@ func TestClusterReconciler_reconcileUpdatesOnClusterClass(t *testing.T) {
 	}, timeout).Should(Succeed())
 }
 
+func TestClusterReconciler_reconcileUpdatesOnClusterClassTemplates(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+	g := NewWithT(t)
+	timeout := 5 * time.Second
+
+	ns, err := env.CreateNamespace(ctx, "test-topology-cluster-reconcile")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create the objects needed for the integration test:
+	// - a ClusterClass with all the related templates
+	// - a Cluster using the above ClusterClass
+	// - a second Cluster using the same ClusterClass
+	cleanup, err := setupTestEnvForIntegrationTests(ns)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Defer a cleanup function that deletes each of the objects created during setupTestEnvForIntegrationTests.
+	defer func() {
+		g.Expect(cleanup()).To(Succeed())
+	}()
+
+	g.Eventually(func(g Gomega) error {
+		for _, name := range []string{clusterName1, clusterName2} {
+			actualCluster := &clusterv1.Cluster{}
+			if err := env.Get(ctx, client.ObjectKey{Name: name, Namespace: ns.Name}, actualCluster); err != nil {
+				return err
+			}
+
+			// Check if InfrastructureCluster has been created and has the correct labels and annotations.
+			g.Expect(assertInfrastructureClusterReconcile(actualCluster)).Should(Succeed())
+
+			// Check if the Cluster has the relevant TopologyReconciledCondition.
+			g.Expect(assertClusterTopologyReconciledCondition(actualCluster)).Should(Succeed())
+		}
+		return nil
+	}, timeout).Should(Succeed())
+
+	// Change the InfrastructureClusterTemplate referenced by the ClusterClass in place, without changing the ClusterClass.
+	template := builder.TestInfrastructureClusterTemplate(ns.Name, "infraclustertemplate1").Build()
+	g.Expect(env.Get(ctx, client.ObjectKeyFromObject(template), template)).To(Succeed())
+	patchHelper, err := patch.NewHelper(template, env.Client)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(unstructured.SetNestedField(template.Object, "changed", "spec", "template", "spec", "foo")).To(Succeed())
+	g.Expect(patchHelper.Patch(ctx, template)).To(Succeed())
+
+	// Check that the template change has been reconciled into the InfrastructureCluster of all the Clusters
+	// using the ClusterClass.
+	g.Eventually(func(g Gomega) error {
+		for _, name := range []string{clusterName1, clusterName2} {
+			actualCluster := &clusterv1.Cluster{}
+			if err := env.Get(ctx, client.ObjectKey{Name: name, Namespace: ns.Name}, actualCluster); err != nil {
+				return err
+			}
+
+			infrastructureCluster, err := getAndAssertLabelsAndAnnotations(*actualCluster.Spec.InfrastructureRef, actualCluster.Name)
+			g.Expect(err).ToNot(HaveOccurred())
+			foo, _, err := unstructured.NestedString(infrastructureCluster.Object, "spec", "foo")
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(foo).To(Equal("changed"))
+		}
+		return nil
+	}, timeout).Should(Succeed())
+}
+
 func TestClusterReconciler_reconcileClusterClassRebase(t *testing.T) {
 	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
 	g := NewWithT(t)
@@ -783,17 +846,18 @@ func setupTestEnvForIntegrationTests(ns *corev1.Namespace) (func() error, error)
 
 	// Create a set of setupTestEnvForIntegrationTests from the objects above to add to the API server when the test environment starts.
 	// The objects are created for every test, though some e.g. infrastructureMachineTemplate2 may not be used in every test.
+	// NOTE: Templates are created first, because they must exist when the ClusterClasses are created.
 	initObjs := []client.Object{
-		clusterClass,
-		clusterClassForRebase,
-		cluster1,
-		cluster2,
 		infrastructureClusterTemplate1,
 		infrastructureClusterTemplate2,
 		infrastructureMachineTemplate1,
 		infrastructureMachineTemplate2,
 		bootstrapTemplate,
 		controlPlaneTemplate,
+		clusterClass,
+		clusterClassForRebase,
+		cluster1,
+		cluster2,
 	}
 	cleanup := func() error {
 		// Delete Objects in reverse, because we cannot delete a ClusterCLass if it is still used by a Cluster.
@@ -842,6 +906,11 @@ func assertClusterReconcile(cluster *clusterv1.Cluster) error {
 		builder.ControlPlaneGroupVersion); err != nil {
 		return err
 	}
+
+	// Check if the topology conditions refer to the latest generation of the Cluster.
+	if cluster.Status.TopologyObservedGeneration != cluster.Generation {
+		return fmt.Errorf("cluster %s has topologyObservedGeneration %d, expected %d", cluster.Name, cluster.Status.TopologyObservedGeneration, cluster.Generation)
+	}
 	return nil
 }
 
@@ -1073,3 +1142,44 @@ func referenceExistsWithCorrectKindAndAPIVersion(reference *corev1.ObjectReferen
 	}
 	return nil
 }
+
+func TestClusterClassOwnerNames(t *testing.T) {
+	tests := []struct {
+		name            string
+		ownerReferences []metav1.OwnerReference
+		want            []string
+	}{
+		{
+			name:            "No owner references",
+			ownerReferences: nil,
+			want:            []string{},
+		},
+		{
+			name: "ClusterClass owner references",
+			ownerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "ClusterClass", Name: clusterClassName1},
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "ClusterClass", Name: clusterClassName2},
+			},
+			want: []string{clusterClassName1, clusterClassName2},
+		},
+		{
+			name: "Ignore owner references which are not ClusterClasses",
+			ownerReferences: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: clusterName1},
+				{APIVersion: "foo.example.com/v1", Kind: "ClusterClass", Name: clusterClassName2},
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "ClusterClass", Name: clusterClassName1},
+			},
+			want: []string{clusterClassName1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			template := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, infrastructureMachineTemplateName1).Build()
+			template.SetOwnerReferences(tt.ownerReferences)
+
+			g.Expect(clusterClassOwnerNames(template)).To(Equal(tt.want))
+		})
+	}
+}
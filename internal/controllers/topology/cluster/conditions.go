@@ -29,19 +29,99 @@ import (
 )
 
 func (r *Reconciler) reconcileConditions(s *scope.Scope, cluster *clusterv1.Cluster, reconcileErr error) error {
+	reconcileTopologyInSyncCondition(s, cluster, reconcileErr)
 	return r.reconcileTopologyReconciledCondition(s, cluster, reconcileErr)
 }
 
+// reconcileTopologyInSyncCondition sets the TopologyInSync condition on Clusters with a drift policy.
+// The condition is false if out-of-band changes have been detected in any of the objects generated from the Cluster topology.
+// NOTE: If an error occurred during the reconcile process, the condition is left untouched, given that
+// not all the objects could have been checked.
+func reconcileTopologyInSyncCondition(s *scope.Scope, cluster *clusterv1.Cluster, reconcileErr error) {
+	policy := getDriftPolicy(cluster)
+	if policy == "" {
+		conditions.Delete(cluster, clusterv1.TopologyInSyncCondition)
+		return
+	}
+
+	// If the Cluster topology is paused or in plan mode, the objects are not checked, so the condition is left untouched.
+	if isTopologyPaused(cluster) || isTopologyPlan(cluster) {
+		return
+	}
+
+	if s.DriftTracker.IsDrifted() {
+		action := "reverted"
+		if policy == clusterv1.ClusterTopologyDriftPolicyReport {
+			action = "not reverted as required by the drift policy"
+		}
+		conditions.MarkFalse(cluster, clusterv1.TopologyInSyncCondition, clusterv1.TopologyDriftDetectedReason, clusterv1.ConditionSeverityWarning,
+			"Out-of-band changes detected and %s: %s", action, s.DriftTracker.AggregateMessage())
+		return
+	}
+
+	if reconcileErr != nil {
+		return
+	}
+	conditions.MarkTrue(cluster, clusterv1.TopologyInSyncCondition)
+}
+
 // reconcileTopologyReconciledCondition sets the TopologyReconciled condition on the cluster.
 // The TopologyReconciled condition is considered true if spec of all the objects associated with the
 // cluster are in sync with the topology defined in the cluster.
 // The condition is false under the following conditions:
+// - The reconciliation of the cluster topology is paused.
+// - The cluster topology is in plan mode.
+// - A webhook required to reconcile the cluster topology is not available.
 // - An error occurred during the reconcile process of the cluster topology.
 // - The cluster upgrade has not yet propagated to all the components of the cluster.
 //   - For a managed topology cluster the version upgrade is propagated one component at a time.
 //     In such a case, since some of the component's spec would be adrift from the topology the
 //     topology cannot be considered fully reconciled.
 func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluster *clusterv1.Cluster, reconcileErr error) error {
+	// If the reconciliation of the Cluster topology is paused set the TopologyReconciled condition to false.
+	if isTopologyPaused(cluster) {
+		conditions.Set(
+			cluster,
+			conditions.FalseCondition(
+				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyReconciledPausedReason,
+				clusterv1.ConditionSeverityInfo,
+				"Cluster topology is paused",
+			),
+		)
+		return nil
+	}
+
+	// If a webhook required to reconcile the topology is not available set the TopologyReconciled condition to false,
+	// reporting the webhook and its service.
+	if webhook := getUnavailableWebhook(reconcileErr); webhook != nil {
+		conditions.Set(
+			cluster,
+			conditions.FalseCondition(
+				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyBlockedByWebhookReason,
+				clusterv1.ConditionSeverityWarning,
+				webhook.Message(),
+			),
+		)
+		return nil
+	}
+
+	// If an object referenced from the Cluster is not owned by the topology of the Cluster set the TopologyReconciled
+	// condition to false, reporting the object; the object is not adopted, so it is not overwritten.
+	if ownershipErr := getOwnershipError(reconcileErr); ownershipErr != nil {
+		conditions.Set(
+			cluster,
+			conditions.FalseCondition(
+				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyReconciledObjectNotOwnedReason,
+				clusterv1.ConditionSeverityWarning,
+				ownershipErr.Error(),
+			),
+		)
+		return nil
+	}
+
 	// If an error occurred during reconciliation set the TopologyReconciled condition to false.
 	// Add the error message from the reconcile function to the message of the condition.
 	if reconcileErr != nil {
@@ -58,6 +138,25 @@ func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluste
 		return nil
 	}
 
+	// If the Cluster topology is in plan mode set the TopologyReconciled condition to false, reporting the changes
+	// which would be applied.
+	if isTopologyPlan(cluster) {
+		message := "Cluster topology is in plan mode, no pending changes"
+		if s.PlanTracker.HasChanges() {
+			message = fmt.Sprintf("Cluster topology is in plan mode, pending changes: %s", s.PlanTracker.AggregateMessage())
+		}
+		conditions.Set(
+			cluster,
+			conditions.FalseCondition(
+				clusterv1.TopologyReconciledCondition,
+				clusterv1.TopologyReconciledPlanReason,
+				clusterv1.ConditionSeverityInfo,
+				message,
+			),
+		)
+		return nil
+	}
+
 	// If any of the lifecycle hooks are blocking any part of the reconciliation then topology
 	// is not considered as fully reconciled.
 	if s.HookResponseTracker.AggregateRetryAfter() != 0 {
@@ -74,20 +173,27 @@ func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluste
 		return nil
 	}
 
-	// If either the Control Plane or any of the MachineDeployments are still pending to pick up the new version (generally
-	// happens when upgrading the cluster) then the topology is not considered as fully reconciled.
-	if s.UpgradeTracker.ControlPlane.PendingUpgrade || s.UpgradeTracker.MachineDeployments.PendingUpgrade() {
+	// If either the Control Plane or any of the MachineDeployments or MachinePools are still pending to pick up the new
+	// version (generally happens when upgrading the cluster) then the topology is not considered as fully reconciled.
+	if s.UpgradeTracker.ControlPlane.PendingUpgrade || s.UpgradeTracker.MachineDeployments.PendingUpgrade() ||
+		s.UpgradeTracker.MachinePools.PendingUpgrade() {
 		msgBuilder := &strings.Builder{}
 		var reason string
 		if s.UpgradeTracker.ControlPlane.PendingUpgrade {
 			msgBuilder.WriteString(fmt.Sprintf("Control plane upgrade to %s on hold. ", s.Blueprint.Topology.Version))
 			reason = clusterv1.TopologyReconciledControlPlaneUpgradePendingReason
-		} else {
+		} else if s.UpgradeTracker.MachineDeployments.PendingUpgrade() {
 			msgBuilder.WriteString(fmt.Sprintf("MachineDeployment(s) %s upgrade to version %s on hold. ",
 				strings.Join(s.UpgradeTracker.MachineDeployments.PendingUpgradeNames(), ", "),
 				s.Blueprint.Topology.Version,
 			))
 			reason = clusterv1.TopologyReconciledMachineDeploymentsUpgradePendingReason
+		} else {
+			msgBuilder.WriteString(fmt.Sprintf("MachinePool(s) %s upgrade to version %s on hold. ",
+				strings.Join(s.UpgradeTracker.MachinePools.PendingUpgradeNames(), ", "),
+				s.Blueprint.Topology.Version,
+			))
+			reason = clusterv1.TopologyReconciledMachinePoolsUpgradePendingReason
 		}
 
 		switch {
@@ -108,6 +214,11 @@ func (r *Reconciler) reconcileTopologyReconciledCondition(s *scope.Scope, cluste
 			msgBuilder.WriteString(fmt.Sprintf("MachineDeployment(s) %s are rolling out", strings.Join(
 				s.UpgradeTracker.MachineDeployments.RolloutNames(), ", ",
 			)))
+
+		case s.Current.MachinePools.IsAnyRollingOut():
+			msgBuilder.WriteString(fmt.Sprintf("MachinePool(s) %s are rolling out", strings.Join(
+				s.Current.MachinePools.RollingOut(), ", ",
+			)))
 		}
 
 		conditions.Set(
@@ -22,8 +22,10 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
@@ -48,6 +50,57 @@ func TestReconcileTopologyReconciledCondition(t *testing.T) {
 			wantConditionReason: clusterv1.TopologyReconcileFailedReason,
 			wantErr:             false,
 		},
+		{
+			name:         "should set the condition to false if the cluster topology is paused",
+			reconcileErr: nil,
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{clusterv1.ClusterTopologyPausedAnnotation: ""},
+				},
+			},
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyReconciledPausedReason,
+			wantErr:             false,
+		},
+		{
+			name:         "should set the condition to false if the cluster topology is in plan mode",
+			reconcileErr: nil,
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{clusterv1.ClusterTopologyPlanAnnotation: ""},
+				},
+			},
+			s: &scope.Scope{
+				PlanTracker: func() *scope.PlanTracker {
+					pt := scope.NewPlanTracker()
+					pt.Add("update", "MachineDeployment", "md1", `{"spec":{"replicas":3}}`)
+					return pt
+				}(),
+			},
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyReconciledPlanReason,
+			wantErr:             false,
+		},
+		{
+			name: "should set the condition to false if a webhook is not available",
+			reconcileErr: errors.New(`failed calling webhook "default.dockercluster.infrastructure.cluster.x-k8s.io": ` +
+				`failed to call webhook: Post "https://capd-webhook-service.capd-system.svc:443/mutate": connect: connection refused`),
+			cluster:             &clusterv1.Cluster{},
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyBlockedByWebhookReason,
+			wantErr:             false,
+		},
+		{
+			name: "should set the condition to false if an object referenced from the cluster is not topology owned",
+			reconcileErr: errors.Wrap(&ownershipError{
+				Object: "control plane object default/cp1 referenced from cluster default/cluster1",
+				Reason: "is not topology owned",
+			}, "error reading current state of the Cluster topology"),
+			cluster:             &clusterv1.Cluster{},
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyReconciledObjectNotOwnedReason,
+			wantErr:             false,
+		},
 		{
 			name:         "should set the condition to false if the there is a blocking hook",
 			reconcileErr: nil,
@@ -285,6 +338,48 @@ func TestReconcileTopologyReconciledCondition(t *testing.T) {
 			wantConditionStatus: corev1.ConditionFalse,
 			wantConditionReason: clusterv1.TopologyReconciledMachineDeploymentsUpgradePendingReason,
 		},
+		{
+			name:         "should set the condition to false if control plane picked the new version but machine pools did not because control plane is scaling",
+			reconcileErr: nil,
+			cluster:      &clusterv1.Cluster{},
+			s: &scope.Scope{
+				Blueprint: &scope.ClusterBlueprint{
+					Topology: &clusterv1.Topology{
+						Version: "v1.22.0",
+					},
+				},
+				Current: &scope.ClusterState{
+					Cluster: &clusterv1.Cluster{},
+					ControlPlane: &scope.ControlPlaneState{
+						Object: builder.ControlPlane("ns1", "controlplane1").
+							WithVersion("v1.22.0").
+							WithReplicas(3).
+							Build(),
+					},
+					MachinePools: scope.MachinePoolsStateMap{
+						"mp0": &scope.MachinePoolState{
+							Object: builder.MachinePool("ns1", "mp0-abc123").
+								WithReplicas(2).
+								WithStatus(expv1.MachinePoolStatus{
+									Replicas:      int32(2),
+									ReadyReplicas: int32(2),
+								}).
+								Build(),
+						},
+					},
+				},
+				UpgradeTracker: func() *scope.UpgradeTracker {
+					ut := scope.NewUpgradeTracker()
+					ut.ControlPlane.PendingUpgrade = false
+					ut.ControlPlane.IsScaling = true
+					ut.MachinePools.MarkPendingUpgrade("mp0-abc123")
+					return ut
+				}(),
+				HookResponseTracker: scope.NewHookResponseTracker(),
+			},
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyReconciledMachinePoolsUpgradePendingReason,
+		},
 		{
 			name:         "should set the condition to true if control plane picked the new version and is upgrading but there are no machine deployments",
 			reconcileErr: nil,
@@ -474,3 +569,93 @@ func TestReconcileTopologyReconciledCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileTopologyInSyncCondition(t *testing.T) {
+	driftTracker := func(drifted bool) *scope.DriftTracker {
+		dt := scope.NewDriftTracker()
+		if drifted {
+			dt.Add("MachineDeployment", "md1", []string{"kubectl-edit: spec.replicas"})
+		}
+		return dt
+	}
+
+	tests := []struct {
+		name                string
+		policy              string
+		drifted             bool
+		paused              bool
+		reconcileErr        error
+		wantCondition       bool
+		wantConditionStatus corev1.ConditionStatus
+		wantConditionReason string
+	}{
+		{
+			name:          "should not set the condition if drift detection is disabled",
+			policy:        "",
+			drifted:       false,
+			wantCondition: false,
+		},
+		{
+			name:                "should set the condition to true if there is no drift",
+			policy:              clusterv1.ClusterTopologyDriftPolicyReport,
+			drifted:             false,
+			wantCondition:       true,
+			wantConditionStatus: corev1.ConditionTrue,
+		},
+		{
+			name:                "should set the condition to false if there is drift",
+			policy:              clusterv1.ClusterTopologyDriftPolicyEnforce,
+			drifted:             true,
+			wantCondition:       true,
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyDriftDetectedReason,
+		},
+		{
+			name:                "should leave the condition untouched if the cluster topology is paused",
+			policy:              clusterv1.ClusterTopologyDriftPolicyReport,
+			paused:              true,
+			wantCondition:       true,
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyDriftDetectedReason,
+		},
+		{
+			name:                "should leave the condition untouched if there is a reconcile error",
+			policy:              clusterv1.ClusterTopologyDriftPolicyReport,
+			drifted:             false,
+			reconcileErr:        errors.New("reconcile error"),
+			wantCondition:       true,
+			wantConditionStatus: corev1.ConditionFalse,
+			wantConditionReason: clusterv1.TopologyDriftDetectedReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := builder.Cluster("default", "cluster1").Build()
+			if tt.policy != "" {
+				cluster.Annotations = map[string]string{clusterv1.ClusterTopologyDriftPolicyAnnotation: tt.policy}
+			}
+			if tt.paused {
+				cluster.Annotations[clusterv1.ClusterTopologyPausedAnnotation] = ""
+			}
+			// Set a pre-existing condition, to check it is preserved or removed as expected.
+			conditions.MarkFalse(cluster, clusterv1.TopologyInSyncCondition, clusterv1.TopologyDriftDetectedReason, clusterv1.ConditionSeverityWarning, "")
+
+			s := &scope.Scope{DriftTracker: driftTracker(tt.drifted)}
+			reconcileTopologyInSyncCondition(s, cluster, tt.reconcileErr)
+
+			actualCondition := conditions.Get(cluster, clusterv1.TopologyInSyncCondition)
+			if !tt.wantCondition {
+				g.Expect(actualCondition).To(BeNil())
+				return
+			}
+			g.Expect(actualCondition).ToNot(BeNil())
+			g.Expect(actualCondition.Status).To(Equal(tt.wantConditionStatus))
+			g.Expect(actualCondition.Reason).To(Equal(tt.wantConditionReason))
+			if tt.drifted {
+				g.Expect(actualCondition.Message).To(ContainSubstring("MachineDeployment md1 (kubectl-edit: spec.replicas)"))
+			}
+		})
+	}
+}
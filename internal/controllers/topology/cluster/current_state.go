@@ -19,6 +19,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
@@ -35,15 +38,24 @@ import (
 )
 
 // getCurrentState gets information about the current state of a Cluster by inspecting the state of the InfrastructureCluster,
-// the ControlPlane, and the MachineDeployments associated with the Cluster.
+// the ControlPlane, and the MachineDeployments and MachinePools associated with the Cluster.
 func (r *Reconciler) getCurrentState(ctx context.Context, s *scope.Scope) (*scope.ClusterState, error) {
 	// NOTE: current scope has been already initialized with the Cluster.
 	currentState := s.Current
 
 	// Reference to the InfrastructureCluster can be nil and is expected to be on the first reconcile.
-	// In this case the method should still be allowed to continue.
-	if currentState.Cluster.Spec.InfrastructureRef != nil {
-		infra, err := r.getCurrentInfrastructureClusterState(ctx, s.Blueprint.InfrastructureClusterTemplate, currentState.Cluster)
+	// In this case the method should still be allowed to continue, adopting the InfrastructureCluster created by
+	// a previous reconcile which has been interrupted before setting the reference, if any.
+	infrastructureRef := currentState.Cluster.Spec.InfrastructureRef
+	if infrastructureRef == nil && s.Blueprint.InfrastructureClusterTemplate != nil {
+		ref, err := r.getAdoptableObjectRef(ctx, s.Blueprint.InfrastructureClusterTemplate, currentState.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		infrastructureRef = ref
+	}
+	if infrastructureRef != nil {
+		infra, err := r.getCurrentInfrastructureClusterState(ctx, s.Blueprint.InfrastructureClusterTemplate, infrastructureRef, currentState.Cluster)
 		if err != nil {
 			return nil, err
 		}
@@ -51,10 +63,19 @@ func (r *Reconciler) getCurrentState(ctx context.Context, s *scope.Scope) (*scop
 	}
 
 	// Reference to the ControlPlane can be nil, and is expected to be on the first reconcile. In this case the method
-	// should still be allowed to continue.
+	// should still be allowed to continue, adopting the ControlPlane created by a previous reconcile which has been
+	// interrupted before setting the reference, if any.
 	currentState.ControlPlane = &scope.ControlPlaneState{}
-	if currentState.Cluster.Spec.ControlPlaneRef != nil {
-		cp, err := r.getCurrentControlPlaneState(ctx, s.Blueprint.ControlPlane, s.Blueprint.HasControlPlaneInfrastructureMachine(), currentState.Cluster)
+	controlPlaneRef := currentState.Cluster.Spec.ControlPlaneRef
+	if controlPlaneRef == nil && s.Blueprint.ControlPlane != nil && s.Blueprint.ControlPlane.Template != nil {
+		ref, err := r.getAdoptableObjectRef(ctx, s.Blueprint.ControlPlane.Template, currentState.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		controlPlaneRef = ref
+	}
+	if controlPlaneRef != nil {
+		cp, err := r.getCurrentControlPlaneState(ctx, s.Blueprint.ControlPlane, s.Blueprint.HasControlPlaneInfrastructureMachine(), controlPlaneRef, currentState.Cluster)
 		if err != nil {
 			return nil, err
 		}
@@ -69,25 +90,128 @@ func (r *Reconciler) getCurrentState(ctx context.Context, s *scope.Scope) (*scop
 	}
 	currentState.MachineDeployments = m
 
+	// A Cluster may have zero or more MachinePools and a Cluster is expected to have zero MachinePools on
+	// first reconcile; MachinePools are only read if the MachinePool feature is enabled.
+	if feature.Gates.Enabled(feature.MachinePool) {
+		mp, err := r.getCurrentMachinePoolState(ctx, s.Blueprint.MachinePools, currentState.Cluster)
+		if err != nil {
+			return nil, err
+		}
+		currentState.MachinePools = mp
+	}
+
 	return currentState, nil
 }
 
+// getAdoptableObjectRef returns the reference to an object generated from the given template for the Cluster by a
+// previous reconcile, which has been interrupted before setting the reference to the object in the Cluster; this allows
+// to adopt the object instead of creating a new one with a different name, thus leaving the first one orphaned.
+// If there are many adoptable objects, the oldest one is picked, and the others are left to the topology garbage collector.
+// NOTE: Objects are read directly from the API server, because this is not on the hot path and the cache could be
+// stale right after the creation of the object.
+func (r *Reconciler) getAdoptableObjectRef(ctx context.Context, template *unstructured.Unstructured, cluster *clusterv1.Cluster) (*corev1.ObjectReference, error) {
+	gvk := template.GroupVersionKind()
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, clusterv1.TemplateSuffix) + "List"
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk)
+	if err := r.APIReader.List(ctx, list,
+		client.MatchingLabels{
+			clusterv1.ClusterLabelName:          cluster.Name,
+			clusterv1.ClusterTopologyOwnedLabel: "",
+		},
+		client.InNamespace(cluster.Namespace),
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s for %s", strings.TrimSuffix(gvk.Kind, "List"), tlog.KObj{Obj: cluster})
+	}
+
+	var adoptable *unstructured.Unstructured
+	for i := range list.Items {
+		obj := &list.Items[i]
+		if !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if adoptable == nil || isOlder(obj, adoptable) {
+			adoptable = obj
+		}
+	}
+	if adoptable == nil {
+		return nil, nil
+	}
+	return contract.ObjToRef(adoptable), nil
+}
+
+// isOlder returns true if a has been created before b, using names to break ties.
+func isOlder(a, b client.Object) bool {
+	aTimestamp, bTimestamp := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !aTimestamp.Equal(&bTimestamp) {
+		return aTimestamp.Before(&bTimestamp)
+	}
+	return a.GetName() < b.GetName()
+}
+
+// ownershipError is returned when an object referenced from a Cluster topology is not owned by the topology of the
+// Cluster; such objects are never adopted into the topology state, so they are not overwritten by the topology controller.
+type ownershipError struct {
+	// Object describes the object and where it is referenced from.
+	Object string
+
+	// Reason describes why the object is not owned by the topology of the Cluster.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ownershipError) Error() string {
+	return fmt.Sprintf("%s %s", e.Object, e.Reason)
+}
+
+// getOwnershipError returns the ownershipError if the error, which can be a wrapped error, is caused by an object
+// not owned by the topology of the Cluster; nil otherwise.
+func getOwnershipError(err error) *ownershipError {
+	var ownershipErr *ownershipError
+	if errors.As(err, &ownershipErr) {
+		return ownershipErr
+	}
+	return nil
+}
+
+// checkTopologyOwnership checks that an object is owned by the topology of the Cluster, i.e. it has the
+// ClusterTopologyOwnedLabel and, if it has a cluster name label or an owner reference to a Cluster, they refer to the
+// Cluster; description describes the object in the returned error.
+func checkTopologyOwnership(obj client.Object, cluster *clusterv1.Cluster, description string) error {
+	if !labels.IsTopologyOwned(obj) {
+		return &ownershipError{Object: description, Reason: "is not topology owned"}
+	}
+	if clusterName, ok := obj.GetLabels()[clusterv1.ClusterLabelName]; ok && clusterName != cluster.Name {
+		return &ownershipError{Object: description, Reason: fmt.Sprintf("belongs to cluster %q according to the %s label", clusterName, clusterv1.ClusterLabelName)}
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || gv.Group != clusterv1.GroupVersion.Group || ref.Kind != "Cluster" {
+			continue
+		}
+		if ref.Name != cluster.Name {
+			return &ownershipError{Object: description, Reason: fmt.Sprintf("is owned by cluster %q", ref.Name)}
+		}
+	}
+	return nil
+}
+
 // getCurrentInfrastructureClusterState looks for the state of the InfrastructureCluster. If a reference is set but not
 // found, either from an error or the object not being found, an error is thrown.
-func (r *Reconciler) getCurrentInfrastructureClusterState(ctx context.Context, blueprintInfrastructureClusterTemplate *unstructured.Unstructured, cluster *clusterv1.Cluster) (*unstructured.Unstructured, error) {
-	ref, err := alignRefAPIVersion(blueprintInfrastructureClusterTemplate, cluster.Spec.InfrastructureRef)
+func (r *Reconciler) getCurrentInfrastructureClusterState(ctx context.Context, blueprintInfrastructureClusterTemplate *unstructured.Unstructured, infrastructureRef *corev1.ObjectReference, cluster *clusterv1.Cluster) (*unstructured.Unstructured, error) {
+	ref, err := alignRefAPIVersion(blueprintInfrastructureClusterTemplate, infrastructureRef)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: cluster.Spec.InfrastructureRef})
+		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: infrastructureRef})
 	}
 	infra, err := r.getReference(ctx, ref)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: cluster.Spec.InfrastructureRef})
+		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: infrastructureRef})
 	}
-	// check that the referenced object has the ClusterTopologyOwnedLabel label.
+	// check that the referenced object is owned by the topology of the Cluster.
 	// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
 	// owned by the topology.
-	if !labels.IsTopologyOwned(infra) {
-		return nil, fmt.Errorf("infra cluster object %s referenced from cluster %s is not topology owned", tlog.KObj{Obj: infra}, tlog.KObj{Obj: cluster})
+	if err := checkTopologyOwnership(infra, cluster, fmt.Sprintf("infra cluster object %s referenced from cluster %s", tlog.KObj{Obj: infra}, tlog.KObj{Obj: cluster})); err != nil {
+		return nil, err
 	}
 	return infra, nil
 }
@@ -95,24 +219,24 @@ func (r *Reconciler) getCurrentInfrastructureClusterState(ctx context.Context, b
 // getCurrentControlPlaneState returns information on the ControlPlane being used by the Cluster. If a reference is not found,
 // an error is thrown. If the ControlPlane requires MachineInfrastructure according to its ClusterClass an error will be
 // thrown if the ControlPlane has no MachineTemplates.
-func (r *Reconciler) getCurrentControlPlaneState(ctx context.Context, blueprintControlPlane *scope.ControlPlaneBlueprint, blueprintHasControlPlaneInfrastructureMachine bool, cluster *clusterv1.Cluster) (*scope.ControlPlaneState, error) {
+func (r *Reconciler) getCurrentControlPlaneState(ctx context.Context, blueprintControlPlane *scope.ControlPlaneBlueprint, blueprintHasControlPlaneInfrastructureMachine bool, controlPlaneRef *corev1.ObjectReference, cluster *clusterv1.Cluster) (*scope.ControlPlaneState, error) {
 	var err error
 	res := &scope.ControlPlaneState{}
 
 	// Get the control plane object.
-	ref, err := alignRefAPIVersion(blueprintControlPlane.Template, cluster.Spec.ControlPlaneRef)
+	ref, err := alignRefAPIVersion(blueprintControlPlane.Template, controlPlaneRef)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: cluster.Spec.ControlPlaneRef})
+		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: controlPlaneRef})
 	}
 	res.Object, err = r.getReference(ctx, ref)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: cluster.Spec.ControlPlaneRef})
+		return nil, errors.Wrapf(err, "failed to read %s", tlog.KRef{Ref: controlPlaneRef})
 	}
-	// check that the referenced object has the ClusterTopologyOwnedLabel label.
+	// check that the referenced object is owned by the topology of the Cluster.
 	// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
 	// owned by the topology.
-	if !labels.IsTopologyOwned(res.Object) {
-		return nil, fmt.Errorf("control plane object %s referenced from cluster %s is not topology owned", tlog.KObj{Obj: res.Object}, tlog.KObj{Obj: cluster})
+	if err := checkTopologyOwnership(res.Object, cluster, fmt.Sprintf("control plane object %s referenced from cluster %s", tlog.KObj{Obj: res.Object}, tlog.KObj{Obj: cluster})); err != nil {
+		return nil, err
 	}
 
 	// If the clusterClass does not mandate the controlPlane has infrastructureMachines, return.
@@ -133,11 +257,38 @@ func (r *Reconciler) getCurrentControlPlaneState(ctx context.Context, blueprintC
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get InfrastructureMachineTemplate for %s", tlog.KObj{Obj: res.Object})
 	}
-	// check that the referenced object has the ClusterTopologyOwnedLabel label.
+	// check that the referenced object is owned by the topology of the Cluster.
 	// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
 	// owned by the topology.
-	if !labels.IsTopologyOwned(res.InfrastructureMachineTemplate) {
-		return nil, fmt.Errorf("control plane InfrastructureMachineTemplate object %s referenced from cluster %s is not topology owned", tlog.KObj{Obj: res.InfrastructureMachineTemplate}, tlog.KObj{Obj: cluster})
+	if err := checkTopologyOwnership(res.InfrastructureMachineTemplate, cluster, fmt.Sprintf("control plane InfrastructureMachineTemplate object %s referenced from cluster %s", tlog.KObj{Obj: res.InfrastructureMachineTemplate}, tlog.KObj{Obj: cluster})); err != nil {
+		return nil, err
+	}
+
+	// Get the control plane machine infrastructureMachine templates for specific failure domains, if any.
+	failureDomainInfrastructureRefs, err := contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Get(res.Object)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get failure domain InfrastructureMachineTemplate references for %s", tlog.KObj{Obj: res.Object})
+	}
+	if len(failureDomainInfrastructureRefs) > 0 {
+		res.FailureDomainInfrastructureMachineTemplates = map[string]*unstructured.Unstructured{}
+	}
+	for failureDomain, failureDomainInfrastructureRef := range failureDomainInfrastructureRefs {
+		ref := failureDomainInfrastructureRef
+		if template, ok := blueprintControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain]; ok {
+			ref, err = alignRefAPIVersion(template, failureDomainInfrastructureRef)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get InfrastructureMachineTemplate for %s, failure domain %q", tlog.KObj{Obj: res.Object}, failureDomain)
+			}
+		}
+		template, err := r.getReference(ctx, ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get InfrastructureMachineTemplate for %s, failure domain %q", tlog.KObj{Obj: res.Object}, failureDomain)
+		}
+		// check that the referenced object is owned by the topology of the Cluster.
+		if err := checkTopologyOwnership(template, cluster, fmt.Sprintf("control plane InfrastructureMachineTemplate object %s for failure domain %q referenced from cluster %s", tlog.KObj{Obj: template}, failureDomain, tlog.KObj{Obj: cluster})); err != nil {
+			return nil, err
+		}
+		res.FailureDomainInfrastructureMachineTemplates[failureDomain] = template
 	}
 
 	mhc := &clusterv1.MachineHealthCheck{}
@@ -227,11 +378,11 @@ func (r *Reconciler) getCurrentMachineDeploymentState(ctx context.Context, bluep
 		if err != nil {
 			return nil, errors.Wrap(err, fmt.Sprintf("%s Bootstrap reference could not be retrieved", tlog.KObj{Obj: m}))
 		}
-		// check that the referenced object has the ClusterTopologyOwnedLabel label.
+		// check that the referenced object is owned by the topology of the Cluster.
 		// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
 		// owned by the topology.
-		if !labels.IsTopologyOwned(bootstrapTemplate) {
-			return nil, fmt.Errorf("BootstrapTemplate object %s referenced from MD %s is not topology owned", tlog.KObj{Obj: bootstrapTemplate}, tlog.KObj{Obj: m})
+		if err := checkTopologyOwnership(bootstrapTemplate, cluster, fmt.Sprintf("BootstrapTemplate object %s referenced from MD %s", tlog.KObj{Obj: bootstrapTemplate}, tlog.KObj{Obj: m})); err != nil {
+			return nil, err
 		}
 
 		// Get the InfraMachineTemplate.
@@ -239,11 +390,11 @@ func (r *Reconciler) getCurrentMachineDeploymentState(ctx context.Context, bluep
 		if err != nil {
 			return nil, errors.Wrap(err, fmt.Sprintf("%s Infrastructure reference could not be retrieved", tlog.KObj{Obj: m}))
 		}
-		// check that the referenced object has the ClusterTopologyOwnedLabel label.
+		// check that the referenced object is owned by the topology of the Cluster.
 		// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
 		// owned by the topology.
-		if !labels.IsTopologyOwned(infraMachineTemplate) {
-			return nil, fmt.Errorf("InfrastructureMachineTemplate object %s referenced from MD %s is not topology owned", tlog.KObj{Obj: infraMachineTemplate}, tlog.KObj{Obj: m})
+		if err := checkTopologyOwnership(infraMachineTemplate, cluster, fmt.Sprintf("InfrastructureMachineTemplate object %s referenced from MD %s", tlog.KObj{Obj: infraMachineTemplate}, tlog.KObj{Obj: m})); err != nil {
+			return nil, err
 		}
 
 		// Gets the MachineHealthCheck.
@@ -269,6 +420,107 @@ func (r *Reconciler) getCurrentMachineDeploymentState(ctx context.Context, bluep
 	return state, nil
 }
 
+// getCurrentMachinePoolState queries for all MachinePools and filters them for their linked Cluster and
+// whether they are managed by a ClusterClass using labels. A Cluster may have zero or more MachinePools. Zero is
+// expected on first reconcile. If MachinePools are found for the Cluster their Infrastructure and Bootstrap references
+// are inspected. Where these are not found the function will throw an error.
+func (r *Reconciler) getCurrentMachinePoolState(ctx context.Context, blueprintMachinePools map[string]*scope.MachinePoolBlueprint, cluster *clusterv1.Cluster) (scope.MachinePoolsStateMap, error) {
+	state := make(scope.MachinePoolsStateMap)
+
+	// List all the machine pools in the current cluster and in a managed topology.
+	mp := &expv1.MachinePoolList{}
+	err := r.APIReader.List(ctx, mp,
+		client.MatchingLabels{
+			clusterv1.ClusterLabelName:          cluster.Name,
+			clusterv1.ClusterTopologyOwnedLabel: "",
+		},
+		client.InNamespace(cluster.Namespace),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read MachinePools for managed topology")
+	}
+
+	// Loop over each machine pool and create the current
+	// state by retrieving all required references.
+	for i := range mp.Items {
+		m := &mp.Items[i]
+
+		// Retrieve the name which is assigned in Cluster's topology
+		// from a well-defined label.
+		mpTopologyName, ok := m.ObjectMeta.Labels[clusterv1.ClusterTopologyMachinePoolNameLabel]
+		if !ok || mpTopologyName == "" {
+			return nil, fmt.Errorf("failed to find label %s in %s", clusterv1.ClusterTopologyMachinePoolNameLabel, tlog.KObj{Obj: m})
+		}
+
+		// Make sure that the name of the MachinePool stays unique.
+		// If we've already seen a MachinePool with the same name
+		// this is an error, probably caused from manual modifications or a race condition.
+		if _, ok := state[mpTopologyName]; ok {
+			return nil, fmt.Errorf("duplicate %s found for label %s: %s", tlog.KObj{Obj: m}, clusterv1.ClusterTopologyMachinePoolNameLabel, mpTopologyName)
+		}
+
+		// Gets the bootstrapRef.
+		bootstrapRef := m.Spec.Template.Spec.Bootstrap.ConfigRef
+		if bootstrapRef == nil {
+			return nil, fmt.Errorf("%s does not have a reference to a Bootstrap Config", tlog.KObj{Obj: m})
+		}
+		// Gets the infraRef.
+		infraRef := &m.Spec.Template.Spec.InfrastructureRef
+		if infraRef.Name == "" {
+			return nil, fmt.Errorf("%s does not have a reference to a InfrastructureMachinePool", tlog.KObj{Obj: m})
+		}
+
+		// If the mpTopology exists in the Cluster, lookup the corresponding mpBluePrint and align
+		// the apiVersions in the bootstrapRef and infraRef.
+		// If the mpTopology doesn't exist, do nothing (this can happen if the mpTopology was deleted).
+		if mpTopologyExistsInCluster, mpClassName := getMPClassName(cluster, mpTopologyName); mpTopologyExistsInCluster {
+			mpBluePrint, ok := blueprintMachinePools[mpClassName]
+			if !ok {
+				return nil, fmt.Errorf("failed to find MachinePool class %s in ClusterClass", mpClassName)
+			}
+			bootstrapRef, err = alignRefAPIVersion(mpBluePrint.BootstrapTemplate, bootstrapRef)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("%s Bootstrap reference could not be retrieved", tlog.KObj{Obj: m}))
+			}
+			infraRef, err = alignRefAPIVersion(mpBluePrint.InfrastructureMachinePoolTemplate, infraRef)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("%s Infrastructure reference could not be retrieved", tlog.KObj{Obj: m}))
+			}
+		}
+
+		// Get the bootstrap config.
+		bootstrapObject, err := r.getReference(ctx, bootstrapRef)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("%s Bootstrap reference could not be retrieved", tlog.KObj{Obj: m}))
+		}
+		// check that the referenced object is owned by the topology of the Cluster.
+		// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
+		// owned by the topology.
+		if err := checkTopologyOwnership(bootstrapObject, cluster, fmt.Sprintf("bootstrap object %s referenced from MP %s", tlog.KObj{Obj: bootstrapObject}, tlog.KObj{Obj: m})); err != nil {
+			return nil, err
+		}
+
+		// Get the InfraMachinePool.
+		infraMachinePool, err := r.getReference(ctx, infraRef)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("%s Infrastructure reference could not be retrieved", tlog.KObj{Obj: m}))
+		}
+		// check that the referenced object is owned by the topology of the Cluster.
+		// Nb. This is to make sure that a managed topology cluster does not have a reference to an object that is not
+		// owned by the topology.
+		if err := checkTopologyOwnership(infraMachinePool, cluster, fmt.Sprintf("InfrastructureMachinePool object %s referenced from MP %s", tlog.KObj{Obj: infraMachinePool}, tlog.KObj{Obj: m})); err != nil {
+			return nil, err
+		}
+
+		state[mpTopologyName] = &scope.MachinePoolState{
+			Object:                          m,
+			BootstrapObject:                 bootstrapObject,
+			InfrastructureMachinePoolObject: infraMachinePool,
+		}
+	}
+	return state, nil
+}
+
 // alignRefAPIVersion returns an aligned copy of the currentRef so it matches the apiVersion in ClusterClass.
 // This is required so the topology controller can diff current and desired state objects of the same
 // version during reconcile.
@@ -309,3 +561,17 @@ func getMDClassName(cluster *clusterv1.Cluster, mdTopologyName string) (bool, st
 	}
 	return false, ""
 }
+
+// getMPClassName retrieves the MPClass name by looking up the MPTopology in the Cluster.
+func getMPClassName(cluster *clusterv1.Cluster, mpTopologyName string) (bool, string) {
+	if cluster.Spec.Topology.Workers == nil {
+		return false, ""
+	}
+
+	for _, mpTopology := range cluster.Spec.Topology.Workers.MachinePools {
+		if mpTopology.Name == mpTopologyName {
+			return true, mpTopology.Class
+		}
+	}
+	return false, ""
+}
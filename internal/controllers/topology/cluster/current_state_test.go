@@ -17,11 +17,13 @@ limitations under the License.
 package cluster
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -55,6 +57,18 @@ func TestGetCurrentState(t *testing.T) {
 		Build()
 	infraClusterTemplate := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infraTemplateOne").
 		Build()
+	adoptableLabels := map[string]string{clusterv1.ClusterLabelName: "cluster1", clusterv1.ClusterTopologyOwnedLabel: ""}
+	adoptableInfraCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infraAdoptable").
+		Build()
+	adoptableInfraCluster.SetLabels(adoptableLabels)
+	adoptableInfraCluster.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-time.Hour)))
+	newerAdoptableInfraCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infraAdoptableNewer").
+		Build()
+	newerAdoptableInfraCluster.SetLabels(adoptableLabels)
+	newerAdoptableInfraCluster.SetCreationTimestamp(metav1.NewTime(time.Now()))
+	infraClusterOfOtherCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infraOfOtherCluster").
+		Build()
+	infraClusterOfOtherCluster.SetLabels(map[string]string{clusterv1.ClusterLabelName: "cluster2", clusterv1.ClusterTopologyOwnedLabel: ""})
 
 	// ControlPlane and ControlPlaneInfrastructureMachineTemplate objects.
 	controlPlaneInfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfraTemplate").
@@ -80,6 +94,9 @@ func TestGetCurrentState(t *testing.T) {
 		Build()
 	controlPlaneNotTopologyOwned := builder.ControlPlane(metav1.NamespaceDefault, "cp1").
 		Build()
+	adoptableControlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cpAdoptable").
+		Build()
+	adoptableControlPlane.SetLabels(adoptableLabels)
 
 	// ClusterClass  objects.
 	clusterClassWithControlPlaneInfra := builder.ClusterClass(metav1.NamespaceDefault, "class1").
@@ -179,6 +196,32 @@ func TestGetCurrentState(t *testing.T) {
 				MachineDeployments:    emptyMachineDeployments,
 			},
 		},
+		{
+			name:    "Should adopt the InfrastructureCluster and the ControlPlane created by an interrupted reconcile (without references)",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").Build(),
+			blueprint: &scope.ClusterBlueprint{
+				ClusterClass:                  clusterClassWithNoControlPlaneInfra,
+				InfrastructureClusterTemplate: infraClusterTemplate,
+				ControlPlane: &scope.ControlPlaneBlueprint{
+					Template: controlPlaneTemplateWithInfrastructureMachine,
+				},
+			},
+			objects: []client.Object{
+				newerAdoptableInfraCluster,
+				adoptableInfraCluster,
+				infraClusterOfOtherCluster,
+				adoptableControlPlane,
+			},
+			// Expecting the oldest InfrastructureCluster and the ControlPlane of the Cluster to be adopted.
+			want: &scope.ClusterState{
+				Cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+					// No InfrastructureCluster or ControlPlane references!
+					Build(),
+				ControlPlane:          &scope.ControlPlaneState{Object: adoptableControlPlane},
+				InfrastructureCluster: adoptableInfraCluster,
+				MachineDeployments:    emptyMachineDeployments,
+			},
+		},
 		{
 			name: "Fails if the Cluster references an InfrastructureCluster that does not exist",
 			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
@@ -205,6 +248,19 @@ func TestGetCurrentState(t *testing.T) {
 			},
 			wantErr: true, // this test fails as partial reconcile is undefined.
 		},
+		{
+			name: "Fails if the Cluster references an InfrastructureCluster of another Cluster",
+			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithInfrastructureCluster(infraClusterOfOtherCluster).
+				Build(),
+			blueprint: &scope.ClusterBlueprint{
+				InfrastructureClusterTemplate: infraClusterTemplate,
+			},
+			objects: []client.Object{
+				infraClusterOfOtherCluster,
+			},
+			wantErr: true,
+		},
 		{
 			name: "Fails if the Cluster references an Control Plane that is not topology owned",
 			cluster: builder.Cluster(metav1.NamespaceDefault, "cluster1").
@@ -843,6 +899,74 @@ func TestGetCurrentState(t *testing.T) {
 	}
 }
 
+func TestCheckTopologyOwnership(t *testing.T) {
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		ownerRefs  []metav1.OwnerReference
+		wantReason string
+	}{
+		{
+			name:   "Owned by the topology of the Cluster",
+			labels: map[string]string{clusterv1.ClusterLabelName: "cluster1", clusterv1.ClusterTopologyOwnedLabel: ""},
+			ownerRefs: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "cluster1"},
+			},
+		},
+		{
+			name:   "Owned by the topology without the cluster name label",
+			labels: map[string]string{clusterv1.ClusterTopologyOwnedLabel: ""},
+		},
+		{
+			name:       "Not topology owned",
+			labels:     map[string]string{clusterv1.ClusterLabelName: "cluster1"},
+			wantReason: "is not topology owned",
+		},
+		{
+			name:       "Cluster name label of another Cluster",
+			labels:     map[string]string{clusterv1.ClusterLabelName: "cluster2", clusterv1.ClusterTopologyOwnedLabel: ""},
+			wantReason: fmt.Sprintf("belongs to cluster \"cluster2\" according to the %s label", clusterv1.ClusterLabelName),
+		},
+		{
+			name:   "Owner reference to another Cluster",
+			labels: map[string]string{clusterv1.ClusterTopologyOwnedLabel: ""},
+			ownerRefs: []metav1.OwnerReference{
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "cluster2"},
+			},
+			wantReason: "is owned by cluster \"cluster2\"",
+		},
+		{
+			name:   "Owner reference to another kind named as another Cluster",
+			labels: map[string]string{clusterv1.ClusterTopologyOwnedLabel: ""},
+			ownerRefs: []metav1.OwnerReference{
+				{APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1", Kind: "Cluster", Name: "cluster2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := builder.InfrastructureCluster(metav1.NamespaceDefault, "infra1").Build()
+			obj.SetLabels(tt.labels)
+			obj.SetOwnerReferences(tt.ownerRefs)
+
+			err := checkTopologyOwnership(obj, cluster, "infra cluster object default/infra1")
+			if tt.wantReason == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(HaveOccurred())
+			ownershipErr := getOwnershipError(errors.Wrap(err, "failed to read current state"))
+			g.Expect(ownershipErr).ToNot(BeNil())
+			g.Expect(ownershipErr.Object).To(Equal("infra cluster object default/infra1"))
+			g.Expect(ownershipErr.Reason).To(Equal(tt.wantReason))
+		})
+	}
+}
+
 func TestAlignRefAPIVersion(t *testing.T) {
 	tests := []struct {
 		name                     string
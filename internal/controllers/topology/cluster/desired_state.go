@@ -19,7 +19,11 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +35,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
@@ -38,6 +43,9 @@ import (
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/internal/topology/upgrade"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/version"
 )
 
 // computeDesiredState computes the desired state of the cluster topology.
@@ -50,6 +58,9 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 		ControlPlane: &scope.ControlPlaneState{},
 	}
 
+	// Report labels and annotations with different values in the Cluster topology and in the ClusterClass.
+	r.reportTopologyMetadataConflicts(s)
+
 	// Compute the desired state of the InfrastructureCluster object.
 	if desiredState.InfrastructureCluster, err = computeInfrastructureCluster(ctx, s); err != nil {
 		return nil, errors.Wrapf(err, "failed to compute InfrastructureCluster")
@@ -62,12 +73,27 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 		}
 	}
 
+	// If the clusterClass defines infrastructureMachines for the controlPlane in specific failure domains,
+	// compute the corresponding InfrastructureMachineTemplates for the ControlPlane.
+	if s.Blueprint.HasControlPlaneFailureDomainInfrastructureMachines() {
+		if desiredState.ControlPlane.FailureDomainInfrastructureMachineTemplates, err = computeControlPlaneFailureDomainInfrastructureMachineTemplates(ctx, s); err != nil {
+			return nil, errors.Wrapf(err, "failed to compute ControlPlane InfrastructureMachineTemplates for failure domains")
+		}
+	}
+
 	// Compute the desired state of the ControlPlane object, eventually adding a reference to the
 	// InfrastructureMachineTemplate generated by the previous step.
 	if desiredState.ControlPlane.Object, err = r.computeControlPlane(ctx, s, desiredState.ControlPlane.InfrastructureMachineTemplate); err != nil {
 		return nil, errors.Wrapf(err, "failed to compute ControlPlane")
 	}
 
+	// Add the references to the InfrastructureMachineTemplates for specific failure domains, if any.
+	if len(desiredState.ControlPlane.FailureDomainInfrastructureMachineTemplates) > 0 {
+		if err := contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Set(desiredState.ControlPlane.Object, desiredState.ControlPlane.FailureDomainInfrastructureMachineTemplates); err != nil {
+			return nil, errors.Wrapf(err, "failed to compute ControlPlane")
+		}
+	}
+
 	// Compute the desired state of the ControlPlane MachineHealthCheck if defined.
 	// The MachineHealthCheck will have the same name as the ControlPlane Object and a selector for the ControlPlane InfrastructureMachines.
 	if s.Blueprint.IsControlPlaneMachineHealthCheckEnabled() {
@@ -94,6 +120,15 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 		}
 	}
 
+	// If required, compute the desired state of the MachinePools from the list of MachinePoolTopologies
+	// defined in the cluster.
+	if s.Blueprint.HasMachinePools() {
+		desiredState.MachinePools, err = computeMachinePools(ctx, s, desiredState.ControlPlane)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute MachinePools")
+		}
+	}
+
 	// Apply patches the desired state according to the patches from the ClusterClass, variables from the Cluster
 	// and builtin variables.
 	// NOTE: We have to make sure all spec fields that were explicitly set in desired objects during the computation above
@@ -104,6 +139,12 @@ func (r *Reconciler) computeDesiredState(ctx context.Context, s *scope.Scope) (*
 		return nil, errors.Wrap(err, "failed to apply patches")
 	}
 
+	// Record the inputs used to compute the desired state of the objects generated from the Cluster topology,
+	// thus allowing to tell which objects have been changed because of a change to their inputs.
+	if err := setInputsHashes(s, desiredState); err != nil {
+		return nil, err
+	}
+
 	return desiredState, nil
 }
 
@@ -114,6 +155,10 @@ func computeInfrastructureCluster(_ context.Context, s *scope.Scope) (*unstructu
 	templateClonedFromRef := s.Blueprint.ClusterClass.Spec.Infrastructure.Ref
 	cluster := s.Current.Cluster
 	currentRef := cluster.Spec.InfrastructureRef
+	// Re-use the name of the InfrastructureCluster adopted from a previous reconcile, if any.
+	if currentRef == nil && s.Current.InfrastructureCluster != nil {
+		currentRef = contract.ObjToRef(s.Current.InfrastructureCluster)
+	}
 
 	infrastructureCluster, err := templateToObject(templateToInput{
 		template:              template,
@@ -157,7 +202,7 @@ func computeControlPlaneInfrastructureMachineTemplate(_ context.Context, s *scop
 		}
 	}
 
-	controlPlaneInfrastructureMachineTemplate := templateToTemplate(templateToInput{
+	return templateToTemplate(templateToInput{
 		template:              template,
 		templateClonedFromRef: templateClonedFromRef,
 		cluster:               cluster,
@@ -168,7 +213,42 @@ func computeControlPlaneInfrastructureMachineTemplate(_ context.Context, s *scop
 		// with the reference to the ControlPlane object using this template.
 		ownerRef: ownerReferenceTo(s.Current.Cluster),
 	})
-	return controlPlaneInfrastructureMachineTemplate, nil
+}
+
+// computeControlPlaneFailureDomainInfrastructureMachineTemplates computes the desired state for the InfrastructureMachineTemplates
+// that should be referenced by the ControlPlane object for specific failure domains, indexed by failure domain.
+func computeControlPlaneFailureDomainInfrastructureMachineTemplates(_ context.Context, s *scope.Scope) (map[string]*unstructured.Unstructured, error) {
+	cluster := s.Current.Cluster
+
+	// Check if the current control plane object has machineTemplate.failureDomainInfrastructureRefs already defined.
+	currentRefs := map[string]*corev1.ObjectReference{}
+	if s.Current.ControlPlane != nil && s.Current.ControlPlane.Object != nil {
+		var err error
+		if currentRefs, err = contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Get(s.Current.ControlPlane.Object); err != nil {
+			return nil, errors.Wrap(err, "failed to get spec.machineTemplate.failureDomainInfrastructureRefs for the current ControlPlane object")
+		}
+	}
+
+	templates := map[string]*unstructured.Unstructured{}
+	for _, machineInfrastructure := range s.Blueprint.ClusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure {
+		template, err := templateToTemplate(templateToInput{
+			template:              s.Blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates[machineInfrastructure.FailureDomain],
+			templateClonedFromRef: machineInfrastructure.Ref,
+			cluster:               cluster,
+			namePrefix:            controlPlaneInfrastructureMachineTemplateNamePrefix(cluster.Name),
+			nameDiscriminator:     controlPlaneFailureDomainInfrastructureMachineTemplateNameDiscriminator(machineInfrastructure.FailureDomain),
+			currentObjectRef:      currentRefs[machineInfrastructure.FailureDomain],
+			// Note: we are adding an ownerRef to Cluster so the template will be automatically garbage collected
+			// in case of errors in between creating this template and updating the ControlPlane object
+			// with the reference to this template.
+			ownerRef: ownerReferenceTo(s.Current.Cluster),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute InfrastructureMachineTemplate of the control plane for failure domain %q", machineInfrastructure.FailureDomain)
+		}
+		templates[machineInfrastructure.FailureDomain] = template
+	}
+	return templates, nil
 }
 
 // computeControlPlane computes the desired state for the ControlPlane object starting from the
@@ -178,6 +258,10 @@ func (r *Reconciler) computeControlPlane(ctx context.Context, s *scope.Scope, in
 	templateClonedFromRef := s.Blueprint.ClusterClass.Spec.ControlPlane.Ref
 	cluster := s.Current.Cluster
 	currentRef := cluster.Spec.ControlPlaneRef
+	// Re-use the name of the ControlPlane adopted from a previous reconcile, if any.
+	if currentRef == nil && s.Current.ControlPlane != nil && s.Current.ControlPlane.Object != nil {
+		currentRef = contract.ObjToRef(s.Current.ControlPlane.Object)
+	}
 
 	controlPlane, err := templateToObject(templateToInput{
 		template:              template,
@@ -230,7 +314,7 @@ func (r *Reconciler) computeControlPlane(ctx context.Context, s *scope.Scope, in
 		topologyMetadata := s.Blueprint.Topology.ControlPlane.Metadata
 		clusterClassMetadata := s.Blueprint.ClusterClass.Spec.ControlPlane.Metadata
 
-		machineLabels := mergeMap(topologyMetadata.Labels, clusterClassMetadata.Labels)
+		machineLabels := mergeTopologyMetadataMap(cluster, topologyMetadata.Labels, clusterClassMetadata.Labels)
 		if machineLabels == nil {
 			machineLabels = map[string]string{}
 		}
@@ -239,7 +323,7 @@ func (r *Reconciler) computeControlPlane(ctx context.Context, s *scope.Scope, in
 		if err := contract.ControlPlane().MachineTemplate().Metadata().Set(controlPlane,
 			&clusterv1.ObjectMeta{
 				Labels:      machineLabels,
-				Annotations: mergeMap(topologyMetadata.Annotations, clusterClassMetadata.Annotations),
+				Annotations: mergeTopologyMetadataMap(cluster, topologyMetadata.Annotations, clusterClassMetadata.Annotations),
 			}); err != nil {
 			return nil, errors.Wrap(err, "failed to set spec.machineTemplate.metadata in the ControlPlane object")
 		}
@@ -287,6 +371,14 @@ func (r *Reconciler) computeControlPlane(ctx context.Context, s *scope.Scope, in
 		}
 	}
 
+	// If it is required to manage the AdditionalTags for the control plane, set the corresponding field.
+	// NOTE: tags from the topology are merged with the ones from the ClusterClass, taking precedence in case of conflicts.
+	if additionalTags := mergeMap(s.Blueprint.Topology.ControlPlane.AdditionalTags, s.Blueprint.ClusterClass.Spec.ControlPlane.AdditionalTags); additionalTags != nil {
+		if err := contract.ControlPlane().MachineTemplate().AdditionalTags().Set(controlPlane, additionalTags); err != nil {
+			return nil, errors.Wrap(err, "failed to set spec.machineTemplate.additionalTags in the ControlPlane object")
+		}
+	}
+
 	// Sets the desired Kubernetes version for the control plane.
 	version, err := r.computeControlPlaneVersion(ctx, s)
 	if err != nil {
@@ -299,12 +391,99 @@ func (r *Reconciler) computeControlPlane(ctx context.Context, s *scope.Scope, in
 	return controlPlane, nil
 }
 
+// computeTopologyVersion calculates the version the control plane and the MachineDeployments are upgraded to.
+// This is the version defined in the topology or, if the Cluster defines intermediate versions to upgrade through,
+// the next version of the upgrade path after the version of the current control plane, so the Cluster is upgraded
+// to each intermediate version before moving to the next one. The control plane moves to the next version only when
+// the MachineDeployments and the MachinePools have been upgraded to the version of the control plane; MachineDeployments
+// with a max version skew can lag behind, as long as the next version does not exceed the skew.
+func computeTopologyVersion(s *scope.Scope) (string, error) {
+	if s.Current.Cluster == nil || len(upgrade.IntermediateVersions(s.Current.Cluster)) == 0 || s.Current.ControlPlane == nil || s.Current.ControlPlane.Object == nil {
+		return s.Blueprint.Topology.Version, nil
+	}
+
+	currentVersion, err := contract.ControlPlane().Version().Get(s.Current.ControlPlane.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the version from control plane spec")
+	}
+	controlPlaneVersion, err := semver.ParseTolerant(*currentVersion)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the version %q from control plane spec", *currentVersion)
+	}
+
+	path, err := upgrade.Path(s.Current.Cluster, *currentVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute the upgrade path")
+	}
+	nextVersion, err := semver.ParseTolerant(path[0])
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the version %q from the upgrade path", path[0])
+	}
+
+	for mdTopologyName, md := range s.Current.MachineDeployments {
+		if md.Object.Spec.Template.Spec.Version == nil {
+			continue
+		}
+		mdVersion, err := semver.ParseTolerant(*md.Object.Spec.Template.Spec.Version)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse the version %q from %s", *md.Object.Spec.Template.Spec.Version, tlog.KObj{Obj: md.Object})
+		}
+		if maxVersionSkew := machineDeploymentMaxVersionSkew(s.Blueprint.Topology, mdTopologyName); maxVersionSkew > 0 {
+			if exceedsVersionSkew(nextVersion, mdVersion, maxVersionSkew) {
+				return *currentVersion, nil
+			}
+			continue
+		}
+		if version.Compare(mdVersion, controlPlaneVersion, version.WithBuildTags()) < 0 {
+			return *currentVersion, nil
+		}
+	}
+	for _, mp := range s.Current.MachinePools {
+		if mp.Object.Spec.Template.Spec.Version == nil {
+			continue
+		}
+		mpVersion, err := semver.ParseTolerant(*mp.Object.Spec.Template.Spec.Version)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse the version %q from %s", *mp.Object.Spec.Template.Spec.Version, tlog.KObj{Obj: mp.Object})
+		}
+		if version.Compare(mpVersion, controlPlaneVersion, version.WithBuildTags()) < 0 {
+			return *currentVersion, nil
+		}
+	}
+	return path[0], nil
+}
+
+// machineDeploymentMaxVersionSkew returns the max version skew of a MachineDeployment topology, or 0 if not set.
+func machineDeploymentMaxVersionSkew(topology *clusterv1.Topology, mdTopologyName string) int32 {
+	if topology.Workers == nil {
+		return 0
+	}
+	for _, md := range topology.Workers.MachineDeployments {
+		if md.Name == mdTopologyName && md.MaxVersionSkew != nil {
+			return *md.MaxVersionSkew
+		}
+	}
+	return 0
+}
+
+// exceedsVersionSkew returns true if the control plane version is more than maxVersionSkew minor versions
+// ahead of the machine version.
+func exceedsVersionSkew(controlPlaneVersion, machineVersion semver.Version, maxVersionSkew int32) bool {
+	if controlPlaneVersion.Major != machineVersion.Major {
+		return controlPlaneVersion.Major > machineVersion.Major
+	}
+	return controlPlaneVersion.Minor > machineVersion.Minor+uint64(maxVersionSkew)
+}
+
 // computeControlPlaneVersion calculates the version of the desired control plane.
 // The version is calculated using the state of the current machine deployments, the current control plane
 // and the version defined in the topology.
 func (r *Reconciler) computeControlPlaneVersion(ctx context.Context, s *scope.Scope) (string, error) {
 	log := tlog.LoggerFrom(ctx)
-	desiredVersion := s.Blueprint.Topology.Version
+	desiredVersion, err := computeTopologyVersion(s)
+	if err != nil {
+		return "", err
+	}
 	// If we are creating the control plane object (current control plane is nil), use version from topology.
 	if s.Current.ControlPlane == nil || s.Current.ControlPlane.Object == nil {
 		return desiredVersion, nil
@@ -361,7 +540,8 @@ func (r *Reconciler) computeControlPlaneVersion(ctx context.Context, s *scope.Sc
 		// is required when updating the TopologyReconciled condition on the cluster.
 
 		// Call the AfterControlPlaneUpgrade now that the control plane is upgraded.
-		if feature.Gates.Enabled(feature.RuntimeSDK) {
+		// NOTE: Lifecycle hooks are not called when the Cluster topology is in plan mode.
+		if feature.Gates.Enabled(feature.RuntimeSDK) && !isTopologyPlan(s.Current.Cluster) {
 			// Call the hook only if we are tracking the intent to do so. If it is not tracked it means we don't need to call the
 			// hook because we didn't go through an upgrade or we already called the hook after the upgrade.
 			if hooks.IsPending(runtimehooksv1.AfterControlPlaneUpgrade, s.Current.Cluster) {
@@ -415,7 +595,8 @@ func (r *Reconciler) computeControlPlaneVersion(ctx context.Context, s *scope.Sc
 		return *currentVersion, nil
 	}
 
-	if feature.Gates.Enabled(feature.RuntimeSDK) {
+	// NOTE: Lifecycle hooks are not called when the Cluster topology is in plan mode.
+	if feature.Gates.Enabled(feature.RuntimeSDK) && !isTopologyPlan(s.Current.Cluster) {
 		// At this point the control plane and the machine deployments are stable and we are almost ready to pick
 		// up the desiredVersion. Call the BeforeClusterUpgrade hook before picking up the desired version.
 		hookRequest := &runtimehooksv1.BeforeClusterUpgradeRequest{
@@ -463,6 +644,12 @@ func computeCluster(_ context.Context, s *scope.Scope, infrastructureCluster, co
 	cluster.Labels[clusterv1.ClusterLabelName] = cluster.Name
 	cluster.Labels[clusterv1.ClusterTopologyOwnedLabel] = ""
 
+	// Set the profile label, so ClusterResourceSets, webhooks and other policies can target sets of Clusters.
+	// NOTE: If the profile is removed from the topology, the label is removed as well, because it is owned by the topology controller.
+	if cluster.Spec.Topology != nil && cluster.Spec.Topology.Profile != "" {
+		cluster.Labels[clusterv1.ClusterProfileLabelName] = cluster.Spec.Topology.Profile
+	}
+
 	// Set the references to the infrastructureCluster and controlPlane objects.
 	// NOTE: Once set for the first time, the references are not expected to change.
 	var err error
@@ -475,9 +662,42 @@ func computeCluster(_ context.Context, s *scope.Scope, infrastructureCluster, co
 		return nil, errors.Wrapf(err, "failed to calculate controlPlaneRef")
 	}
 
+	// Apply the cluster network defaults from the ClusterClass to the fields not set in the Cluster.
+	cluster.Spec.ClusterNetwork = computeClusterNetwork(cluster.Spec.ClusterNetwork, s.Blueprint.ClusterClass.Spec.ClusterNetwork)
+
 	return cluster, nil
 }
 
+// computeClusterNetwork returns the desired cluster network, applying the defaults defined in the ClusterClass
+// to the fields not set in the Cluster.
+// NOTE: Values defined in the Cluster always take precedence over the defaults, and once set the values are not
+// changed anymore by the topology controller, even if the defaults in the ClusterClass change.
+func computeClusterNetwork(clusterNetwork *clusterv1.ClusterNetwork, defaults *clusterv1.ClusterClassNetwork) *clusterv1.ClusterNetwork {
+	if defaults == nil {
+		return clusterNetwork
+	}
+
+	desired := clusterNetwork.DeepCopy()
+	if desired == nil {
+		desired = &clusterv1.ClusterNetwork{}
+	}
+	if (desired.Services == nil || len(desired.Services.CIDRBlocks) == 0) && defaults.Services != nil && len(defaults.Services.CIDRBlocks) > 0 {
+		desired.Services = &clusterv1.NetworkRanges{CIDRBlocks: append([]string{}, defaults.Services.CIDRBlocks...)}
+	}
+	if (desired.Pods == nil || len(desired.Pods.CIDRBlocks) == 0) && defaults.Pods != nil && len(defaults.Pods.CIDRBlocks) > 0 {
+		desired.Pods = &clusterv1.NetworkRanges{CIDRBlocks: append([]string{}, defaults.Pods.CIDRBlocks...)}
+	}
+	if desired.ServiceDomain == "" {
+		desired.ServiceDomain = defaults.ServiceDomain
+	}
+
+	// Do not add an empty cluster network if there are no defaults to apply.
+	if clusterNetwork == nil && desired.Services == nil && desired.Pods == nil && desired.ServiceDomain == "" {
+		return nil
+	}
+	return desired
+}
+
 // calculateRefDesiredAPIVersion returns the desired ref calculated from desiredReferencedObject
 // so it doesn't override the version in apiVersion stored in the currentRef, if any.
 // This is required because the apiVersion in the desired ref is aligned to the apiVersion used
@@ -555,7 +775,8 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 	if currentMachineDeployment != nil && currentMachineDeployment.BootstrapTemplate != nil {
 		currentBootstrapTemplateRef = currentMachineDeployment.Object.Spec.Template.Spec.Bootstrap.ConfigRef
 	}
-	desiredMachineDeployment.BootstrapTemplate = templateToTemplate(templateToInput{
+	var err error
+	desiredMachineDeployment.BootstrapTemplate, err = templateToTemplate(templateToInput{
 		template:              machineDeploymentBlueprint.BootstrapTemplate,
 		templateClonedFromRef: contract.ObjToRef(machineDeploymentBlueprint.BootstrapTemplate),
 		cluster:               s.Current.Cluster,
@@ -566,6 +787,9 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 		// with the reference to the ControlPlane object using this template.
 		ownerRef: ownerReferenceTo(s.Current.Cluster),
 	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute bootstrap template for %s", machineDeploymentTopology.Name)
+	}
 
 	bootstrapTemplateLabels := desiredMachineDeployment.BootstrapTemplate.GetLabels()
 	if bootstrapTemplateLabels == nil {
@@ -580,7 +804,7 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 	if currentMachineDeployment != nil && currentMachineDeployment.InfrastructureMachineTemplate != nil {
 		currentInfraMachineTemplateRef = &currentMachineDeployment.Object.Spec.Template.Spec.InfrastructureRef
 	}
-	desiredMachineDeployment.InfrastructureMachineTemplate = templateToTemplate(templateToInput{
+	desiredMachineDeployment.InfrastructureMachineTemplate, err = templateToTemplate(templateToInput{
 		template:              machineDeploymentBlueprint.InfrastructureMachineTemplate,
 		templateClonedFromRef: contract.ObjToRef(machineDeploymentBlueprint.InfrastructureMachineTemplate),
 		cluster:               s.Current.Cluster,
@@ -591,6 +815,9 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 		// with the reference to the ControlPlane object using this template.
 		ownerRef: ownerReferenceTo(s.Current.Cluster),
 	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute infrastructure machine template for %s", machineDeploymentTopology.Name)
+	}
 
 	infraMachineTemplateLabels := desiredMachineDeployment.InfrastructureMachineTemplate.GetLabels()
 	if infraMachineTemplateLabels == nil {
@@ -615,6 +842,20 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 		strategy = machineDeploymentTopology.Strategy
 	}
 
+	// If a DeletePolicy is set in the MachineDeploymentTopology, it takes precedence over the delete policy
+	// defined in the strategy; the strategy is copied in order to not modify the ClusterClass or the Cluster.
+	if machineDeploymentTopology.DeletePolicy != nil {
+		if strategy == nil {
+			strategy = &clusterv1.MachineDeploymentStrategy{}
+		} else {
+			strategy = strategy.DeepCopy()
+		}
+		if strategy.RollingUpdate == nil {
+			strategy.RollingUpdate = &clusterv1.MachineRollingUpdateDeployment{}
+		}
+		strategy.RollingUpdate.DeletePolicy = machineDeploymentTopology.DeletePolicy
+	}
+
 	failureDomain := machineDeploymentClass.FailureDomain
 	if machineDeploymentTopology.FailureDomain != nil {
 		failureDomain = machineDeploymentTopology.FailureDomain
@@ -660,8 +901,8 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 			Strategy:        strategy,
 			Template: clusterv1.MachineTemplateSpec{
 				ObjectMeta: clusterv1.ObjectMeta{
-					Labels:      mergeMap(machineDeploymentTopology.Metadata.Labels, machineDeploymentBlueprint.Metadata.Labels),
-					Annotations: mergeMap(machineDeploymentTopology.Metadata.Annotations, machineDeploymentBlueprint.Metadata.Annotations),
+					Labels:      mergeTopologyMetadataMap(s.Current.Cluster, machineDeploymentTopology.Metadata.Labels, machineDeploymentBlueprint.Metadata.Labels),
+					Annotations: mergeTopologyMetadataMap(s.Current.Cluster, machineDeploymentTopology.Metadata.Annotations, machineDeploymentBlueprint.Metadata.Annotations),
 				},
 				Spec: clusterv1.MachineSpec{
 					ClusterName:             s.Current.Cluster.Name,
@@ -669,6 +910,7 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 					Bootstrap:               clusterv1.Bootstrap{ConfigRef: desiredBootstrapTemplateRef},
 					InfrastructureRef:       *desiredInfraMachineTemplateRef,
 					FailureDomain:           failureDomain,
+					AdditionalTags:          mergeMap(machineDeploymentTopology.AdditionalTags, machineDeploymentClass.AdditionalTags),
 					NodeDrainTimeout:        nodeDrainTimeout,
 					NodeVolumeDetachTimeout: nodeVolumeDetachTimeout,
 					NodeDeletionTimeout:     nodeDeletionTimeout,
@@ -677,6 +919,14 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 		},
 	}
 
+	// If a rollout of the MachineDeployment has been requested, set the corresponding annotation on the Machine template.
+	if rolloutAfter := computeMachineDeploymentRolloutAfter(machineDeploymentTopology, currentMachineDeployment, time.Now()); rolloutAfter != "" {
+		if desiredMachineDeploymentObj.Spec.Template.Annotations == nil {
+			desiredMachineDeploymentObj.Spec.Template.Annotations = map[string]string{}
+		}
+		desiredMachineDeploymentObj.Spec.Template.Annotations[clusterv1.ClusterTopologyMachineDeploymentRolloutAfterAnnotation] = rolloutAfter
+	}
+
 	// If an existing MachineDeployment is present, override the MachineDeployment generate name
 	// re-using the existing name (this will help in reconcile).
 	if currentMachineDeployment != nil && currentMachineDeployment.Object != nil {
@@ -689,6 +939,7 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 	labels := map[string]string{}
 	labels[clusterv1.ClusterLabelName] = s.Current.Cluster.Name
 	labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+	setClassNameLabel(labels, s.Current.Cluster)
 	labels[clusterv1.ClusterTopologyMachineDeploymentLabelName] = machineDeploymentTopology.Name
 	desiredMachineDeploymentObj.SetLabels(labels)
 
@@ -727,6 +978,38 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 	return desiredMachineDeployment, nil
 }
 
+// computeMachineDeploymentRolloutAfter computes the value of the ClusterTopologyMachineDeploymentRolloutAfterAnnotation
+// for the Machine template of a MachineDeployment: the rolloutAfter value of the MachineDeployment topology if it is
+// reached, the value of the current MachineDeployment otherwise, so that a rollout is triggered only when a new rolloutAfter
+// value is reached and not when rolloutAfter is removed or set to a time in the future.
+func computeMachineDeploymentRolloutAfter(machineDeploymentTopology clusterv1.MachineDeploymentTopology, currentMachineDeployment *scope.MachineDeploymentState, now time.Time) string {
+	if machineDeploymentTopology.RolloutAfter != nil && !machineDeploymentTopology.RolloutAfter.After(now) {
+		return machineDeploymentTopology.RolloutAfter.UTC().Format(time.RFC3339)
+	}
+	if currentMachineDeployment != nil && currentMachineDeployment.Object != nil {
+		return currentMachineDeployment.Object.Spec.Template.Annotations[clusterv1.ClusterTopologyMachineDeploymentRolloutAfterAnnotation]
+	}
+	return ""
+}
+
+// nextMachineDeploymentRolloutAfter returns how long to wait for the earliest rolloutAfter of the MachineDeployment
+// topologies which is not yet reached, or zero if there is none.
+func nextMachineDeploymentRolloutAfter(cluster *clusterv1.Cluster, now time.Time) time.Duration {
+	if cluster.Spec.Topology == nil || cluster.Spec.Topology.Workers == nil {
+		return 0
+	}
+	var next time.Duration
+	for _, md := range cluster.Spec.Topology.Workers.MachineDeployments {
+		if md.RolloutAfter == nil || !md.RolloutAfter.After(now) {
+			continue
+		}
+		if d := md.RolloutAfter.Sub(now); next == 0 || d < next {
+			next = d
+		}
+	}
+	return next
+}
+
 // computeMachineDeploymentVersion calculates the version of the desired machine deployment.
 // The version is calculated using the state of the current machine deployments,
 // the current control plane and the version defined in the topology.
@@ -734,7 +1017,10 @@ func computeMachineDeployment(_ context.Context, s *scope.Scope, desiredControlP
 // of an upgrade. Even if the number of MachineDeployments that are being upgraded is less
 // than the number of allowed concurrent upgrades.
 func computeMachineDeploymentVersion(s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState, currentMDState *scope.MachineDeploymentState) (string, error) {
-	desiredVersion := s.Blueprint.Topology.Version
+	desiredVersion, err := computeTopologyVersion(s)
+	if err != nil {
+		return "", err
+	}
 	// If creating a new machine deployment, we can pick up the desired version
 	// Note: We are not blocking the creation of new machine deployments when
 	// the control plane or any of the machine deployments are upgrading/scaling.
@@ -751,6 +1037,15 @@ func computeMachineDeploymentVersion(s *scope.Scope, desiredControlPlaneState *s
 		return currentVersion, nil
 	}
 
+	// If the machine deployment is not creating machines because of an infrastructure quota, do not pick up the
+	// desiredVersion yet; a rollout would create new machines which are going to be blocked by the same quota.
+	// We will pick up the new version once the quota frees up.
+	if conditions.IsFalse(currentMDState.Object, clusterv1.MachinesCreatedCondition) &&
+		conditions.GetReason(currentMDState.Object, clusterv1.MachinesCreatedCondition) == clusterv1.InfrastructureQuotaExceededReason {
+		s.UpgradeTracker.MachineDeployments.MarkPendingUpgrade(currentMDState.Object.Name)
+		return currentVersion, nil
+	}
+
 	// Return early if we are not allowed to upgrade the machine deployment.
 	if !s.UpgradeTracker.MachineDeployments.AllowUpgrade() {
 		s.UpgradeTracker.MachineDeployments.MarkPendingUpgrade(currentMDState.Object.Name)
@@ -820,8 +1115,11 @@ func computeMachineDeploymentVersion(s *scope.Scope, desiredControlPlaneState *s
 
 	// At this point the control plane is stable (not scaling, not upgrading, not being upgraded).
 	// Checking to see if the machine deployments are also stable.
-	// If any of the MachineDeployments is rolling out, do not upgrade the machine deployment yet.
-	if s.Current.MachineDeployments.IsAnyRollingOut() {
+	// If any of the MachineDeployments is rolling out, do not upgrade the machine deployment yet,
+	// unless MachineDeployments are upgraded in parallel.
+	// NOTE: A MachineDeployment is rolling out until all of its Machines are available, i.e. ready for at least
+	// MinReadySeconds, so the next MachineDeployment is upgraded only when the previous one is fully ready.
+	if !isParallelMachineDeploymentsUpgrade(s.Blueprint.Topology) && s.Current.MachineDeployments.IsAnyRollingOut() {
 		s.UpgradeTracker.MachineDeployments.MarkPendingUpgrade(currentMDState.Object.Name)
 		return currentVersion, nil
 	}
@@ -832,12 +1130,285 @@ func computeMachineDeploymentVersion(s *scope.Scope, desiredControlPlaneState *s
 	return desiredVersion, nil
 }
 
+// isParallelMachineDeploymentsUpgrade returns true if all the MachineDeployments of the topology
+// should pick up a new version at the same time.
+func isParallelMachineDeploymentsUpgrade(topology *clusterv1.Topology) bool {
+	return topology.Workers != nil && topology.Workers.MachineDeploymentsUpgradePolicy == clusterv1.ParallelMachineDeploymentsUpgradePolicy
+}
+
+// computeMachinePools computes the desired state of the list of MachinePools.
+func computeMachinePools(ctx context.Context, s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState) (scope.MachinePoolsStateMap, error) {
+	machinePoolsStateMap := make(scope.MachinePoolsStateMap)
+	for _, mpTopology := range s.Blueprint.Topology.Workers.MachinePools {
+		desiredMachinePool, err := computeMachinePool(ctx, s, desiredControlPlaneState, mpTopology)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute MachinePool for topology %q", mpTopology.Name)
+		}
+		machinePoolsStateMap[mpTopology.Name] = desiredMachinePool
+	}
+	return machinePoolsStateMap, nil
+}
+
+// computeMachinePool computes the desired state for a MachinePoolTopology.
+// The generated MachinePool object is calculated using the values from the machinePoolTopology and
+// the machinePool class; differently from MachineDeployments, the bootstrap config and the infrastructure machine pool
+// referenced by the MachinePool are objects cloned from the templates defined in the ClusterClass, not templates.
+func computeMachinePool(_ context.Context, s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState, machinePoolTopology clusterv1.MachinePoolTopology) (*scope.MachinePoolState, error) {
+	desiredMachinePool := &scope.MachinePoolState{}
+
+	// Gets the blueprint for the MachinePool class.
+	className := machinePoolTopology.Class
+	machinePoolBlueprint, ok := s.Blueprint.MachinePools[className]
+	if !ok {
+		return nil, errors.Errorf("MachinePool class %s not found in %s", className, tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+
+	var machinePoolClass *clusterv1.MachinePoolClass
+	for _, mpClass := range s.Blueprint.ClusterClass.Spec.Workers.MachinePools {
+		mpClass := mpClass
+		if mpClass.Class == className {
+			machinePoolClass = &mpClass
+			break
+		}
+	}
+	if machinePoolClass == nil {
+		return nil, errors.Errorf("MachinePool class %s not found in %s", className, tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+
+	// Compute the bootstrap config.
+	currentMachinePool := s.Current.MachinePools[machinePoolTopology.Name]
+	var currentBootstrapConfigRef *corev1.ObjectReference
+	if currentMachinePool != nil && currentMachinePool.BootstrapObject != nil {
+		currentBootstrapConfigRef = currentMachinePool.Object.Spec.Template.Spec.Bootstrap.ConfigRef
+	}
+	var err error
+	desiredMachinePool.BootstrapObject, err = templateToObject(templateToInput{
+		template:              machinePoolBlueprint.BootstrapTemplate,
+		templateClonedFromRef: contract.ObjToRef(machinePoolBlueprint.BootstrapTemplate),
+		cluster:               s.Current.Cluster,
+		namePrefix:            bootstrapConfigNamePrefix(s.Current.Cluster.Name, machinePoolTopology.Name),
+		currentObjectRef:      currentBootstrapConfigRef,
+		// Note: we are adding an ownerRef to Cluster so the bootstrap config will be automatically garbage collected
+		// in case of errors in between creating this object and creating/updating the MachinePool object
+		// with the reference to this object.
+		ownerRef: ownerReferenceTo(s.Current.Cluster),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute bootstrap object for topology %q", machinePoolTopology.Name)
+	}
+
+	bootstrapObjectLabels := desiredMachinePool.BootstrapObject.GetLabels()
+	if bootstrapObjectLabels == nil {
+		bootstrapObjectLabels = map[string]string{}
+	}
+	// Add ClusterTopologyMachinePoolNameLabel to the generated bootstrap config.
+	bootstrapObjectLabels[clusterv1.ClusterTopologyMachinePoolNameLabel] = machinePoolTopology.Name
+	desiredMachinePool.BootstrapObject.SetLabels(bootstrapObjectLabels)
+
+	// Compute the infrastructure machine pool.
+	var currentInfraMachinePoolRef *corev1.ObjectReference
+	if currentMachinePool != nil && currentMachinePool.InfrastructureMachinePoolObject != nil {
+		currentInfraMachinePoolRef = &currentMachinePool.Object.Spec.Template.Spec.InfrastructureRef
+	}
+	desiredMachinePool.InfrastructureMachinePoolObject, err = templateToObject(templateToInput{
+		template:              machinePoolBlueprint.InfrastructureMachinePoolTemplate,
+		templateClonedFromRef: contract.ObjToRef(machinePoolBlueprint.InfrastructureMachinePoolTemplate),
+		cluster:               s.Current.Cluster,
+		namePrefix:            infrastructureMachinePoolNamePrefix(s.Current.Cluster.Name, machinePoolTopology.Name),
+		currentObjectRef:      currentInfraMachinePoolRef,
+		// Note: we are adding an ownerRef to Cluster so the infrastructure machine pool will be automatically garbage
+		// collected in case of errors in between creating this object and creating/updating the MachinePool object
+		// with the reference to this object.
+		ownerRef: ownerReferenceTo(s.Current.Cluster),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute infrastructure object for topology %q", machinePoolTopology.Name)
+	}
+
+	infraMachinePoolObjectLabels := desiredMachinePool.InfrastructureMachinePoolObject.GetLabels()
+	if infraMachinePoolObjectLabels == nil {
+		infraMachinePoolObjectLabels = map[string]string{}
+	}
+	// Add ClusterTopologyMachinePoolNameLabel to the generated infrastructure machine pool.
+	infraMachinePoolObjectLabels[clusterv1.ClusterTopologyMachinePoolNameLabel] = machinePoolTopology.Name
+	desiredMachinePool.InfrastructureMachinePoolObject.SetLabels(infraMachinePoolObjectLabels)
+
+	version, err := computeMachinePoolVersion(s, desiredControlPlaneState, currentMachinePool)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute version for %s", machinePoolTopology.Name)
+	}
+
+	// Compute values that can be set both in the MachinePoolClass and in the MachinePoolTopology
+	minReadySeconds := machinePoolClass.MinReadySeconds
+	if machinePoolTopology.MinReadySeconds != nil {
+		minReadySeconds = machinePoolTopology.MinReadySeconds
+	}
+
+	failureDomains := machinePoolClass.FailureDomains
+	if machinePoolTopology.FailureDomains != nil {
+		failureDomains = machinePoolTopology.FailureDomains
+	}
+
+	nodeDrainTimeout := machinePoolClass.NodeDrainTimeout
+	if machinePoolTopology.NodeDrainTimeout != nil {
+		nodeDrainTimeout = machinePoolTopology.NodeDrainTimeout
+	}
+
+	nodeVolumeDetachTimeout := machinePoolClass.NodeVolumeDetachTimeout
+	if machinePoolTopology.NodeVolumeDetachTimeout != nil {
+		nodeVolumeDetachTimeout = machinePoolTopology.NodeVolumeDetachTimeout
+	}
+
+	nodeDeletionTimeout := machinePoolClass.NodeDeletionTimeout
+	if machinePoolTopology.NodeDeletionTimeout != nil {
+		nodeDeletionTimeout = machinePoolTopology.NodeDeletionTimeout
+	}
+
+	// Compute the MachinePool object.
+	desiredBootstrapConfigRef, err := calculateRefDesiredAPIVersion(currentBootstrapConfigRef, desiredMachinePool.BootstrapObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate desired bootstrap config ref")
+	}
+	desiredInfraMachinePoolRef, err := calculateRefDesiredAPIVersion(currentInfraMachinePoolRef, desiredMachinePool.InfrastructureMachinePoolObject)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate desired infrastructure machine pool ref")
+	}
+
+	desiredMachinePoolObj := &expv1.MachinePool{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       expv1.GroupVersion.WithKind("MachinePool").Kind,
+			APIVersion: expv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      names.SimpleNameGenerator.GenerateName(fmt.Sprintf("%s-%s-", s.Current.Cluster.Name, machinePoolTopology.Name)),
+			Namespace: s.Current.Cluster.Namespace,
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName:     s.Current.Cluster.Name,
+			MinReadySeconds: minReadySeconds,
+			FailureDomains:  failureDomains,
+			Template: clusterv1.MachineTemplateSpec{
+				ObjectMeta: clusterv1.ObjectMeta{
+					Labels:      mergeTopologyMetadataMap(s.Current.Cluster, machinePoolTopology.Metadata.Labels, machinePoolBlueprint.Metadata.Labels),
+					Annotations: mergeTopologyMetadataMap(s.Current.Cluster, machinePoolTopology.Metadata.Annotations, machinePoolBlueprint.Metadata.Annotations),
+				},
+				Spec: clusterv1.MachineSpec{
+					ClusterName:             s.Current.Cluster.Name,
+					Version:                 pointer.String(version),
+					Bootstrap:               clusterv1.Bootstrap{ConfigRef: desiredBootstrapConfigRef},
+					InfrastructureRef:       *desiredInfraMachinePoolRef,
+					NodeDrainTimeout:        nodeDrainTimeout,
+					NodeVolumeDetachTimeout: nodeVolumeDetachTimeout,
+					NodeDeletionTimeout:     nodeDeletionTimeout,
+				},
+			},
+		},
+	}
+
+	// If an existing MachinePool is present, override the MachinePool generate name
+	// re-using the existing name (this will help in reconcile).
+	if currentMachinePool != nil && currentMachinePool.Object != nil {
+		desiredMachinePoolObj.SetName(currentMachinePool.Object.Name)
+	}
+
+	// Apply Labels
+	// NOTE: On top of all the labels applied to managed objects we are applying the ClusterTopologyMachinePoolNameLabel
+	// keeping track of the MachinePool name from the Topology; this will be used to identify the object in next reconcile loops.
+	labels := map[string]string{}
+	labels[clusterv1.ClusterLabelName] = s.Current.Cluster.Name
+	labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+	setClassNameLabel(labels, s.Current.Cluster)
+	labels[clusterv1.ClusterTopologyMachinePoolNameLabel] = machinePoolTopology.Name
+	desiredMachinePoolObj.SetLabels(labels)
+
+	// Also set the labels in .spec.template.labels so that they are propagated to the Nodes of the MachinePool.
+	if desiredMachinePoolObj.Spec.Template.Labels == nil {
+		desiredMachinePoolObj.Spec.Template.Labels = map[string]string{}
+	}
+	desiredMachinePoolObj.Spec.Template.Labels[clusterv1.ClusterLabelName] = s.Current.Cluster.Name
+	desiredMachinePoolObj.Spec.Template.Labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+	desiredMachinePoolObj.Spec.Template.Labels[clusterv1.ClusterTopologyMachinePoolNameLabel] = machinePoolTopology.Name
+
+	// Set the desired replicas.
+	desiredMachinePoolObj.Spec.Replicas = machinePoolTopology.Replicas
+
+	desiredMachinePool.Object = desiredMachinePoolObj
+	return desiredMachinePool, nil
+}
+
+// computeMachinePoolVersion calculates the version of the desired machine pool.
+// New MachinePools pick up the version defined in the topology immediately, while existing MachinePools
+// pick it up only once the control plane is stable, i.e. it is not upgrading, scaling or about to be upgraded.
+// NOTE: MachinePools are upgraded in parallel; MachinePools on hold are marked as pending upgrade and MachinePools
+// picking up the new version are marked as upgrading in the upgrade tracker.
+func computeMachinePoolVersion(s *scope.Scope, desiredControlPlaneState *scope.ControlPlaneState, currentMPState *scope.MachinePoolState) (string, error) {
+	desiredVersion, err := computeTopologyVersion(s)
+	if err != nil {
+		return "", err
+	}
+	// If creating a new machine pool, we can pick up the desired version.
+	if currentMPState == nil || currentMPState.Object == nil || currentMPState.Object.Spec.Template.Spec.Version == nil {
+		return desiredVersion, nil
+	}
+
+	// Return early if the current version is already equal to the desired version.
+	currentVersion := *currentMPState.Object.Spec.Template.Spec.Version
+	if currentVersion == desiredVersion {
+		return currentVersion, nil
+	}
+
+	// If the control plane is being created, do not perform any machine pool upgrade.
+	if s.Current.ControlPlane == nil || s.Current.ControlPlane.Object == nil {
+		s.UpgradeTracker.MachinePools.MarkPendingUpgrade(currentMPState.Object.Name)
+		return currentVersion, nil
+	}
+
+	// If the control plane is upgrading or scaling, or if it is about to be upgraded, do not pick up the
+	// desired version yet; we will pick it up after the control plane is stable.
+	cpUpgrading, err := contract.ControlPlane().IsUpgrading(s.Current.ControlPlane.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to check if control plane is upgrading")
+	}
+	if cpUpgrading {
+		s.UpgradeTracker.MachinePools.MarkPendingUpgrade(currentMPState.Object.Name)
+		return currentVersion, nil
+	}
+	if s.Blueprint.Topology.ControlPlane.Replicas != nil {
+		cpScaling, err := contract.ControlPlane().IsScaling(s.Current.ControlPlane.Object)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to check if the control plane is scaling")
+		}
+		if cpScaling {
+			s.UpgradeTracker.MachinePools.MarkPendingUpgrade(currentMPState.Object.Name)
+			return currentVersion, nil
+		}
+	}
+	currentCPVersion, err := contract.ControlPlane().Version().Get(s.Current.ControlPlane.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get version of current control plane")
+	}
+	desiredCPVersion, err := contract.ControlPlane().Version().Get(desiredControlPlaneState.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get version of desired control plane")
+	}
+	if *currentCPVersion != *desiredCPVersion || s.UpgradeTracker.ControlPlane.PendingUpgrade {
+		s.UpgradeTracker.MachinePools.MarkPendingUpgrade(currentMPState.Object.Name)
+		return currentVersion, nil
+	}
+
+	// Control plane is stable, ready to pick up the topology version.
+	s.UpgradeTracker.MachinePools.MarkUpgrading(currentMPState.Object.Name)
+	return desiredVersion, nil
+}
+
 type templateToInput struct {
 	template              *unstructured.Unstructured
 	templateClonedFromRef *corev1.ObjectReference
 	cluster               *clusterv1.Cluster
 	namePrefix            string
-	currentObjectRef      *corev1.ObjectReference
+	// nameDiscriminator is an optional discriminator for the name of templates generated by templateToTemplate.
+	nameDiscriminator string
+	currentObjectRef  *corev1.ObjectReference
 	// OwnerRef is an optional OwnerReference to attach to the cloned object.
 	ownerRef *metav1.OwnerReference
 }
@@ -851,6 +1422,7 @@ func templateToObject(in templateToInput) (*unstructured.Unstructured, error) {
 	labels := map[string]string{}
 	labels[clusterv1.ClusterLabelName] = in.cluster.Name
 	labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+	setClassNameLabel(labels, in.cluster)
 
 	// Generate the object from the template.
 	// NOTE: OwnerRef can't be set at this stage; other controllers are going to add OwnerReferences when
@@ -883,7 +1455,7 @@ func templateToObject(in templateToInput) (*unstructured.Unstructured, error) {
 // and assigning a meaningful name (or reusing current reference name).
 // NOTE: We are creating a copy of the ClusterClass template for each cluster so
 // it is possible to add cluster specific information without affecting the original object.
-func templateToTemplate(in templateToInput) *unstructured.Unstructured {
+func templateToTemplate(in templateToInput) (*unstructured.Unstructured, error) {
 	template := &unstructured.Unstructured{}
 	in.template.DeepCopyInto(template)
 
@@ -903,6 +1475,7 @@ func templateToTemplate(in templateToInput) *unstructured.Unstructured {
 	}
 	labels[clusterv1.ClusterLabelName] = in.cluster.Name
 	labels[clusterv1.ClusterTopologyOwnedLabel] = ""
+	setClassNameLabel(labels, in.cluster)
 	template.SetLabels(labels)
 
 	// Enforce cloned from annotations and removes the kubectl last-applied-configuration annotation
@@ -921,15 +1494,20 @@ func templateToTemplate(in templateToInput) *unstructured.Unstructured {
 		template.SetOwnerReferences([]metav1.OwnerReference{*in.ownerRef})
 	}
 
-	// Ensure the generated template gets a meaningful name.
+	// Ensure the generated template gets a meaningful name, including a hash of its content.
 	// NOTE: In case there is already an object ref to this template, it is required to re-use the same name
 	// in order to simplify compare at later stages of the reconcile process.
-	template.SetName(names.SimpleNameGenerator.GenerateName(in.namePrefix))
 	if in.currentObjectRef != nil && len(in.currentObjectRef.Name) > 0 {
 		template.SetName(in.currentObjectRef.Name)
+		return template, nil
 	}
+	name, err := templateNameWithHash(in.namePrefix, in.nameDiscriminator, template)
+	if err != nil {
+		return nil, err
+	}
+	template.SetName(name)
 
-	return template
+	return template, nil
 }
 
 // mergeMap merges two maps into another one.
@@ -952,6 +1530,77 @@ func mergeMap(a, b map[string]string) map[string]string {
 	return m
 }
 
+// mergeTopologyMetadataMap merges labels or annotations defined in the Cluster topology with the ones defined in the ClusterClass.
+// By default values from the Cluster topology take precedence, unless the Cluster has the
+// ClusterTopologyMetadataPrecedenceAnnotation set to ClusterClass.
+func mergeTopologyMetadataMap(cluster *clusterv1.Cluster, topology, clusterClass map[string]string) map[string]string {
+	if cluster.GetAnnotations()[clusterv1.ClusterTopologyMetadataPrecedenceAnnotation] == clusterv1.ClusterTopologyMetadataPrecedenceClusterClass {
+		return mergeMap(clusterClass, topology)
+	}
+	return mergeMap(topology, clusterClass)
+}
+
+// metadataConflicts returns the sorted list of keys defined both in topology and clusterClass with different values.
+func metadataConflicts(topology, clusterClass map[string]string) []string {
+	conflicts := []string{}
+	for k, v := range topology {
+		if classValue, ok := clusterClass[k]; ok && classValue != v {
+			conflicts = append(conflicts, k)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// reportTopologyMetadataConflicts sets the TopologyMetadataConsistent condition on the Cluster, reporting labels or
+// annotations defined both in the Cluster topology and in the ClusterClass with different values, unless an explicit
+// precedence has been chosen by setting the ClusterTopologyMetadataPrecedenceAnnotation on the Cluster.
+// A warning event is recorded on the Cluster only when the conflicts change, so it is not repeated at every reconcile.
+func (r *Reconciler) reportTopologyMetadataConflicts(s *scope.Scope) {
+	cluster := s.Current.Cluster
+	if _, ok := cluster.GetAnnotations()[clusterv1.ClusterTopologyMetadataPrecedenceAnnotation]; ok {
+		conditions.Delete(cluster, clusterv1.TopologyMetadataConsistentCondition)
+		return
+	}
+
+	conflicts := []string{}
+	addConflicts := func(kind, object string, topology, clusterClass map[string]string) {
+		if keys := metadataConflicts(topology, clusterClass); len(keys) > 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s %s", object, kind, strings.Join(keys, ",")))
+		}
+	}
+
+	controlPlaneMetadata := s.Blueprint.Topology.ControlPlane.Metadata
+	controlPlaneClassMetadata := s.Blueprint.ClusterClass.Spec.ControlPlane.Metadata
+	addConflicts("labels", "controlPlane", controlPlaneMetadata.Labels, controlPlaneClassMetadata.Labels)
+	addConflicts("annotations", "controlPlane", controlPlaneMetadata.Annotations, controlPlaneClassMetadata.Annotations)
+
+	if s.Blueprint.Topology.Workers != nil {
+		for _, md := range s.Blueprint.Topology.Workers.MachineDeployments {
+			mdBlueprint, ok := s.Blueprint.MachineDeployments[md.Class]
+			if !ok {
+				continue
+			}
+			object := fmt.Sprintf("machineDeployment %s", md.Name)
+			addConflicts("labels", object, md.Metadata.Labels, mdBlueprint.Metadata.Labels)
+			addConflicts("annotations", object, md.Metadata.Annotations, mdBlueprint.Metadata.Annotations)
+		}
+	}
+
+	if len(conflicts) == 0 {
+		conditions.MarkTrue(cluster, clusterv1.TopologyMetadataConsistentCondition)
+		return
+	}
+
+	message := fmt.Sprintf("Metadata defined both in Cluster topology and in ClusterClass %s with different values, values from the Cluster topology are used: %s; set the %s annotation to choose the precedence explicitly",
+		s.Blueprint.ClusterClass.Name, strings.Join(conflicts, "; "), clusterv1.ClusterTopologyMetadataPrecedenceAnnotation)
+	if conditions.GetReason(cluster, clusterv1.TopologyMetadataConsistentCondition) != clusterv1.TopologyMetadataConflictReason ||
+		conditions.GetMessage(cluster, clusterv1.TopologyMetadataConsistentCondition) != message {
+		r.recorder.Event(cluster, corev1.EventTypeWarning, metadataConflictEventReason, message)
+	}
+	conditions.MarkFalse(cluster, clusterv1.TopologyMetadataConsistentCondition, clusterv1.TopologyMetadataConflictReason, clusterv1.ConditionSeverityWarning, "%s", message)
+}
+
 func ownerReferenceTo(obj client.Object) *metav1.OwnerReference {
 	return &metav1.OwnerReference{
 		Kind:       obj.GetObjectKind().GroupVersionKind().Kind,
@@ -1010,3 +1659,11 @@ func selectorForMachineDeploymentMHC(md *clusterv1.MachineDeployment) *metav1.La
 	},
 	}
 }
+
+// setClassNameLabel sets the label tracking the ClusterClass an object has been generated from.
+func setClassNameLabel(labels map[string]string, cluster *clusterv1.Cluster) {
+	if cluster.Spec.Topology == nil || cluster.Spec.Topology.Class == "" {
+		return
+	}
+	labels[clusterv1.ClusterTopologyClassNameLabel] = cluster.Spec.Topology.Class
+}
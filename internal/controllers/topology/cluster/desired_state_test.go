@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -41,6 +42,7 @@ import (
 	"sigs.k8s.io/cluster-api/internal/hooks"
 	fakeruntimeclient "sigs.k8s.io/cluster-api/internal/runtime/client/fake"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 var (
@@ -130,6 +132,19 @@ func TestComputeInfrastructureCluster(t *testing.T) {
 			obj:         obj,
 		})
 	})
+	t.Run("If an infrastructureCluster has been adopted, it preserves its name", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// aggregating current cluster objects into ClusterState (simulating getCurrentState adopting an InfrastructureCluster)
+		scope := scope.New(cluster)
+		scope.Current.InfrastructureCluster = builder.InfrastructureCluster(metav1.NamespaceDefault, "adopted").Build()
+		scope.Blueprint = blueprint
+
+		obj, err := computeInfrastructureCluster(ctx, scope)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(obj).ToNot(BeNil())
+		g.Expect(obj.GetName()).To(Equal("adopted"))
+	})
 	t.Run("Carry over the owner reference to ClusterShim, if any", func(t *testing.T) {
 		g := NewWithT(t)
 		shim := clusterShim(cluster)
@@ -245,6 +260,114 @@ func TestComputeControlPlaneInfrastructureMachineTemplate(t *testing.T) {
 	})
 }
 
+func TestComputeControlPlaneFailureDomainInfrastructureMachineTemplates(t *testing.T) {
+	// current cluster objects
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{},
+		},
+	}
+
+	infrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "template1").
+		Build()
+	fd1InfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "template1-fd1").
+		Build()
+	fd2InfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "template1-fd2").
+		Build()
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithControlPlaneInfrastructureMachineTemplate(infrastructureMachineTemplate).Build()
+	clusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure = []clusterv1.FailureDomainMachineInfrastructure{
+		{FailureDomain: "fd1", LocalObjectTemplate: clusterv1.LocalObjectTemplate{Ref: contract.ObjToRef(fd1InfrastructureMachineTemplate)}},
+		{FailureDomain: "fd2", LocalObjectTemplate: clusterv1.LocalObjectTemplate{Ref: contract.ObjToRef(fd2InfrastructureMachineTemplate)}},
+	}
+
+	// aggregating templates and cluster class into a blueprint (simulating getBlueprint)
+	blueprint := &scope.ClusterBlueprint{
+		Topology:     cluster.Spec.Topology,
+		ClusterClass: clusterClass,
+		ControlPlane: &scope.ControlPlaneBlueprint{
+			InfrastructureMachineTemplate: infrastructureMachineTemplate,
+			FailureDomainInfrastructureMachineTemplates: map[string]*unstructured.Unstructured{
+				"fd1": fd1InfrastructureMachineTemplate,
+				"fd2": fd2InfrastructureMachineTemplate,
+			},
+		},
+	}
+
+	t.Run("Generates the infrastructureMachineTemplates from the templates", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// aggregating current cluster objects into ClusterState (simulating getCurrentState)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+
+		objs, err := computeControlPlaneFailureDomainInfrastructureMachineTemplates(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objs).To(HaveLen(2))
+
+		for _, failureDomain := range []string{"fd1", "fd2"} {
+			g.Expect(objs).To(HaveKey(failureDomain))
+			assertTemplateToTemplate(g, assertTemplateInput{
+				cluster:     s.Current.Cluster,
+				templateRef: contract.ObjToRef(blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain]),
+				template:    blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain],
+				currentRef:  nil,
+				obj:         objs[failureDomain],
+			})
+
+			// Ensure Cluster ownership is added to generated InfrastructureMachineTemplates.
+			g.Expect(objs[failureDomain].GetOwnerReferences()).To(HaveLen(1))
+			g.Expect(objs[failureDomain].GetOwnerReferences()[0].Kind).To(Equal("Cluster"))
+			g.Expect(objs[failureDomain].GetOwnerReferences()[0].Name).To(Equal(cluster.Name))
+		}
+	})
+	t.Run("If there are already references to the infrastructureMachineTemplates, it preserves the reference names", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// current cluster objects for the test scenario
+		currentFD1InfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cluster1-template1-fd1").Build()
+
+		controlPlane := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		err := contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Set(controlPlane, map[string]*unstructured.Unstructured{
+			"fd1": currentFD1InfrastructureMachineTemplate,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// aggregating current cluster objects into ClusterState (simulating getCurrentState)
+		s := scope.New(cluster)
+		s.Current.ControlPlane = &scope.ControlPlaneState{
+			Object: controlPlane,
+			FailureDomainInfrastructureMachineTemplates: map[string]*unstructured.Unstructured{
+				"fd1": currentFD1InfrastructureMachineTemplate,
+			},
+		}
+		s.Blueprint = blueprint
+
+		objs, err := computeControlPlaneFailureDomainInfrastructureMachineTemplates(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(objs).To(HaveLen(2))
+
+		assertTemplateToTemplate(g, assertTemplateInput{
+			cluster:     s.Current.Cluster,
+			templateRef: contract.ObjToRef(fd1InfrastructureMachineTemplate),
+			template:    fd1InfrastructureMachineTemplate,
+			currentRef:  contract.ObjToRef(currentFD1InfrastructureMachineTemplate),
+			obj:         objs["fd1"],
+		})
+		assertTemplateToTemplate(g, assertTemplateInput{
+			cluster:     s.Current.Cluster,
+			templateRef: contract.ObjToRef(fd2InfrastructureMachineTemplate),
+			template:    fd2InfrastructureMachineTemplate,
+			currentRef:  nil,
+			obj:         objs["fd2"],
+		})
+	})
+}
+
 func TestComputeControlPlane(t *testing.T) {
 	// templates and ClusterClass
 	labels := map[string]string{"l1": ""}
@@ -259,6 +382,7 @@ func TestComputeControlPlane(t *testing.T) {
 		WithControlPlaneNodeDrainTimeout(&metav1.Duration{Duration: clusterClassDuration}).
 		WithControlPlaneNodeVolumeDetachTimeout(&metav1.Duration{Duration: clusterClassDuration}).
 		WithControlPlaneNodeDeletionTimeout(&metav1.Duration{Duration: clusterClassDuration}).
+		WithControlPlaneAdditionalTags(map[string]string{"t1": "cc", "t2": "cc"}).
 		Build()
 	// TODO: Replace with object builder.
 	// current cluster objects
@@ -285,6 +409,7 @@ func TestComputeControlPlane(t *testing.T) {
 					NodeDrainTimeout:        &nodeDrainTimeout,
 					NodeVolumeDetachTimeout: &nodeVolumeDetachTimeout,
 					NodeDeletionTimeout:     &nodeDeletionTimeout,
+					AdditionalTags:          map[string]string{"t2": "topology", "t3": "topology"},
 				},
 			},
 		},
@@ -324,6 +449,7 @@ func TestComputeControlPlane(t *testing.T) {
 		assertNestedField(g, obj, topologyDuration.String(), contract.ControlPlane().MachineTemplate().NodeDrainTimeout().Path()...)
 		assertNestedField(g, obj, topologyDuration.String(), contract.ControlPlane().MachineTemplate().NodeVolumeDetachTimeout().Path()...)
 		assertNestedField(g, obj, topologyDuration.String(), contract.ControlPlane().MachineTemplate().NodeDeletionTimeout().Path()...)
+		assertNestedField(g, obj, map[string]interface{}{"t1": "cc", "t2": "topology", "t3": "topology"}, contract.ControlPlane().MachineTemplate().AdditionalTags().Path()...)
 		assertNestedFieldUnset(g, obj, contract.ControlPlane().MachineTemplate().InfrastructureRef().Path()...)
 
 		// Ensure no ownership is added to generated ControlPlane.
@@ -346,7 +472,7 @@ func TestComputeControlPlane(t *testing.T) {
 							Annotations: map[string]string{"a2": ""},
 						},
 						Replicas: &replicas,
-						// no values for NodeDrainTimeout, NodeVolumeDetachTimeout, NodeDeletionTimeout
+						// no values for NodeDrainTimeout, NodeVolumeDetachTimeout, NodeDeletionTimeout, AdditionalTags
 					},
 				},
 			},
@@ -374,6 +500,7 @@ func TestComputeControlPlane(t *testing.T) {
 		assertNestedField(g, obj, clusterClassDuration.String(), contract.ControlPlane().MachineTemplate().NodeDrainTimeout().Path()...)
 		assertNestedField(g, obj, clusterClassDuration.String(), contract.ControlPlane().MachineTemplate().NodeVolumeDetachTimeout().Path()...)
 		assertNestedField(g, obj, clusterClassDuration.String(), contract.ControlPlane().MachineTemplate().NodeDeletionTimeout().Path()...)
+		assertNestedField(g, obj, map[string]interface{}{"t1": "cc", "t2": "cc"}, contract.ControlPlane().MachineTemplate().AdditionalTags().Path()...)
 	})
 	t.Run("Skips setting replicas if required", func(t *testing.T) {
 		g := NewWithT(t)
@@ -606,6 +733,112 @@ func TestComputeControlPlane(t *testing.T) {
 	})
 }
 
+func TestComputeTopologyVersion(t *testing.T) {
+	upgradePath := map[string]string{clusterv1.ClusterTopologyUpgradePathAnnotation: "v1.23.4,v1.24.2,v1.25.1"}
+
+	tests := []struct {
+		name               string
+		annotations        map[string]string
+		controlPlane       string
+		machineDeployments map[string]string
+		maxVersionSkew     map[string]int32
+		machinePools       map[string]string
+		want               string
+	}{
+		{
+			name:               "Use the topology version if there is no upgrade path",
+			controlPlane:       "v1.22.0",
+			machineDeployments: map[string]string{"md1": "v1.22.0"},
+			want:               "v1.26.0",
+		},
+		{
+			name:               "Use the next version of the upgrade path if everything runs the control plane version",
+			annotations:        upgradePath,
+			controlPlane:       "v1.22.0",
+			machineDeployments: map[string]string{"md1": "v1.22.0"},
+			machinePools:       map[string]string{"mp1": "v1.22.0"},
+			want:               "v1.23.4",
+		},
+		{
+			name:               "Use the topology version after the last version of the upgrade path",
+			annotations:        upgradePath,
+			controlPlane:       "v1.25.1",
+			machineDeployments: map[string]string{"md1": "v1.25.1"},
+			want:               "v1.26.0",
+		},
+		{
+			name:               "Use the control plane version until MachineDeployments are upgraded",
+			annotations:        upgradePath,
+			controlPlane:       "v1.23.4",
+			machineDeployments: map[string]string{"md1": "v1.23.4", "md2": "v1.22.0"},
+			want:               "v1.23.4",
+		},
+		{
+			name:               "Use the control plane version until MachinePools are upgraded",
+			annotations:        upgradePath,
+			controlPlane:       "v1.23.4",
+			machineDeployments: map[string]string{"md1": "v1.23.4"},
+			machinePools:       map[string]string{"mp1": "v1.22.0"},
+			want:               "v1.23.4",
+		},
+		{
+			name:               "Use the next version of the upgrade path if MachineDeployments lag behind within their max version skew",
+			annotations:        upgradePath,
+			controlPlane:       "v1.23.4",
+			machineDeployments: map[string]string{"md1": "v1.22.0"},
+			maxVersionSkew:     map[string]int32{"md1": 2},
+			want:               "v1.24.2",
+		},
+		{
+			name:               "Use the control plane version if the next version exceeds the max version skew of a MachineDeployment",
+			annotations:        upgradePath,
+			controlPlane:       "v1.24.2",
+			machineDeployments: map[string]string{"md1": "v1.22.0"},
+			maxVersionSkew:     map[string]int32{"md1": 2},
+			want:               "v1.24.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			topology := &clusterv1.Topology{
+				Version: "v1.26.0",
+				Workers: &clusterv1.WorkersTopology{},
+			}
+			s := scope.New(builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(topology).
+				Build())
+			s.Current.Cluster.Annotations = tt.annotations
+			s.Blueprint = &scope.ClusterBlueprint{Topology: topology}
+			s.Current.ControlPlane = &scope.ControlPlaneState{
+				Object: builder.ControlPlane(metav1.NamespaceDefault, "cp1").WithVersion(tt.controlPlane).Build(),
+			}
+			s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{}
+			for name, version := range tt.machineDeployments {
+				mdTopology := clusterv1.MachineDeploymentTopology{Name: name}
+				if skew, ok := tt.maxVersionSkew[name]; ok {
+					mdTopology.MaxVersionSkew = pointer.Int32(skew)
+				}
+				topology.Workers.MachineDeployments = append(topology.Workers.MachineDeployments, mdTopology)
+				s.Current.MachineDeployments[name] = &scope.MachineDeploymentState{
+					Object: builder.MachineDeployment(metav1.NamespaceDefault, name).WithVersion(version).Build(),
+				}
+			}
+			s.Current.MachinePools = map[string]*scope.MachinePoolState{}
+			for name, version := range tt.machinePools {
+				s.Current.MachinePools[name] = &scope.MachinePoolState{
+					Object: builder.MachinePool(metav1.NamespaceDefault, name).WithVersion(version).Build(),
+				}
+			}
+
+			got, err := computeTopologyVersion(s)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestComputeControlPlaneVersion(t *testing.T) {
 	t.Run("Compute control plane version under various circumstances", func(t *testing.T) {
 		defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.RuntimeSDK, true)()
@@ -1282,6 +1515,13 @@ func TestComputeCluster(t *testing.T) {
 
 	// aggregating current cluster objects into ClusterState (simulating getCurrentState)
 	scope := scope.New(cluster)
+	scope.Blueprint.ClusterClass = &clusterv1.ClusterClass{
+		Spec: clusterv1.ClusterClassSpec{
+			ClusterNetwork: &clusterv1.ClusterClassNetwork{
+				ServiceDomain: "cluster.local",
+			},
+		},
+	}
 
 	obj, err := computeCluster(ctx, scope, infrastructureCluster, controlPlane)
 	g.Expect(err).ToNot(HaveOccurred())
@@ -1300,6 +1540,99 @@ func TestComputeCluster(t *testing.T) {
 	// Spec
 	g.Expect(obj.Spec.InfrastructureRef).To(Equal(contract.ObjToRef(infrastructureCluster)))
 	g.Expect(obj.Spec.ControlPlaneRef).To(Equal(contract.ObjToRef(controlPlane)))
+	g.Expect(obj.Spec.ClusterNetwork).To(Equal(&clusterv1.ClusterNetwork{ServiceDomain: "cluster.local"}))
+}
+
+func TestComputeClusterProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	infrastructureCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infrastructureCluster1").
+		Build()
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "controlplane1").
+		Build()
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.21.2").WithProfile("prod").Build()).
+		Build()
+
+	s := scope.New(cluster)
+	s.Blueprint.ClusterClass = &clusterv1.ClusterClass{}
+
+	obj, err := computeCluster(ctx, s, infrastructureCluster, controlPlane)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterProfileLabelName, "prod"))
+
+	// The profile label is not set if the topology does not define a profile.
+	cluster.Spec.Topology.Profile = ""
+	obj, err = computeCluster(ctx, s, infrastructureCluster, controlPlane)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetLabels()).ToNot(HaveKey(clusterv1.ClusterProfileLabelName))
+}
+
+func TestComputeClusterNetwork(t *testing.T) {
+	defaults := &clusterv1.ClusterClassNetwork{
+		Services: &clusterv1.ClusterClassNetworkRanges{
+			CIDRBlocks:          []string{"10.128.0.0/12"},
+			PermittedCIDRBlocks: []string{"10.128.0.0/9"},
+		},
+		Pods: &clusterv1.ClusterClassNetworkRanges{
+			CIDRBlocks: []string{"192.168.0.0/16"},
+		},
+		ServiceDomain: "cluster.local",
+	}
+
+	tests := []struct {
+		name           string
+		clusterNetwork *clusterv1.ClusterNetwork
+		defaults       *clusterv1.ClusterClassNetwork
+		want           *clusterv1.ClusterNetwork
+	}{
+		{
+			name:           "No changes if the ClusterClass does not define defaults",
+			clusterNetwork: &clusterv1.ClusterNetwork{ServiceDomain: "foo.local"},
+			defaults:       nil,
+			want:           &clusterv1.ClusterNetwork{ServiceDomain: "foo.local"},
+		},
+		{
+			name:           "No cluster network is added if the ClusterClass defines only permitted ranges",
+			clusterNetwork: nil,
+			defaults: &clusterv1.ClusterClassNetwork{
+				Pods: &clusterv1.ClusterClassNetworkRanges{PermittedCIDRBlocks: []string{"192.168.0.0/16"}},
+			},
+			want: nil,
+		},
+		{
+			name:           "Defaults are applied if the Cluster does not define a cluster network",
+			clusterNetwork: nil,
+			defaults:       defaults,
+			want: &clusterv1.ClusterNetwork{
+				Services:      &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.128.0.0/12"}},
+				Pods:          &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				ServiceDomain: "cluster.local",
+			},
+		},
+		{
+			name: "Defaults are applied only to fields not set in the Cluster",
+			clusterNetwork: &clusterv1.ClusterNetwork{
+				APIServerPort: pointer.Int32(8443),
+				Services:      &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.129.0.0/16"}},
+				Pods:          &clusterv1.NetworkRanges{CIDRBlocks: []string{}},
+			},
+			defaults: defaults,
+			want: &clusterv1.ClusterNetwork{
+				APIServerPort: pointer.Int32(8443),
+				Services:      &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.129.0.0/16"}},
+				Pods:          &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				ServiceDomain: "cluster.local",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(computeClusterNetwork(tt.clusterNetwork, tt.defaults)).To(Equal(tt.want))
+		})
+	}
 }
 
 func TestComputeMachineDeployment(t *testing.T) {
@@ -1343,6 +1676,7 @@ func TestComputeMachineDeployment(t *testing.T) {
 		WithNodeDrainTimeout(&clusterClassDuration).
 		WithNodeVolumeDetachTimeout(&clusterClassDuration).
 		WithNodeDeletionTimeout(&clusterClassDuration).
+		WithAdditionalTags(map[string]string{"t1": "cc", "t2": "cc"}).
 		WithMinReadySeconds(&clusterClassMinReadySeconds).
 		WithStrategy(&clusterClassStrategy).
 		Build()
@@ -1402,6 +1736,7 @@ func TestComputeMachineDeployment(t *testing.T) {
 		NodeDrainTimeout:        &topologyDuration,
 		NodeVolumeDetachTimeout: &topologyDuration,
 		NodeDeletionTimeout:     &topologyDuration,
+		AdditionalTags:          map[string]string{"t2": "topology", "t3": "topology"},
 		MinReadySeconds:         &topologyMinReadySeconds,
 		Strategy:                &topologyStrategy,
 	}
@@ -1436,6 +1771,7 @@ func TestComputeMachineDeployment(t *testing.T) {
 		g.Expect(*actualMd.Spec.Template.Spec.NodeDrainTimeout).To(Equal(topologyDuration))
 		g.Expect(*actualMd.Spec.Template.Spec.NodeVolumeDetachTimeout).To(Equal(topologyDuration))
 		g.Expect(*actualMd.Spec.Template.Spec.NodeDeletionTimeout).To(Equal(topologyDuration))
+		g.Expect(actualMd.Spec.Template.Spec.AdditionalTags).To(Equal(map[string]string{"t1": "cc", "t2": "topology", "t3": "topology"}))
 		g.Expect(actualMd.Spec.ClusterName).To(Equal("cluster1"))
 		g.Expect(actualMd.Name).To(ContainSubstring("cluster1"))
 		g.Expect(actualMd.Name).To(ContainSubstring("big-pool-of-machines"))
@@ -1464,7 +1800,7 @@ func TestComputeMachineDeployment(t *testing.T) {
 			Class:    "linux-worker",
 			Name:     "big-pool-of-machines",
 			Replicas: &replicas,
-			// missing FailureDomain, NodeDrainTimeout, NodeVolumeDetachTimeout, NodeDeletionTimeout, MinReadySeconds, Strategy
+			// missing FailureDomain, NodeDrainTimeout, NodeVolumeDetachTimeout, NodeDeletionTimeout, AdditionalTags, MinReadySeconds, Strategy
 		}
 
 		actual, err := computeMachineDeployment(ctx, scope, nil, mdTopology)
@@ -1478,6 +1814,71 @@ func TestComputeMachineDeployment(t *testing.T) {
 		g.Expect(*actualMd.Spec.Template.Spec.NodeDrainTimeout).To(Equal(clusterClassDuration))
 		g.Expect(*actualMd.Spec.Template.Spec.NodeVolumeDetachTimeout).To(Equal(clusterClassDuration))
 		g.Expect(*actualMd.Spec.Template.Spec.NodeDeletionTimeout).To(Equal(clusterClassDuration))
+		g.Expect(actualMd.Spec.Template.Spec.AdditionalTags).To(Equal(map[string]string{"t1": "cc", "t2": "cc"}))
+	})
+
+	t.Run("Generates the machine deployment using the DeletePolicy from the MachineDeploymentTopology", func(t *testing.T) {
+		g := NewWithT(t)
+		scope := scope.New(cluster)
+		scope.Blueprint = blueprint
+
+		mdTopology := clusterv1.MachineDeploymentTopology{
+			Class:        "linux-worker",
+			Name:         "big-pool-of-machines",
+			Replicas:     &replicas,
+			DeletePolicy: pointer.String(string(clusterv1.OldestMachineSetDeletePolicy)),
+		}
+
+		actual, err := computeMachineDeployment(ctx, scope, nil, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		actualMd := actual.Object
+		g.Expect(actualMd.Spec.Strategy.Type).To(Equal(clusterClassStrategy.Type))
+		g.Expect(actualMd.Spec.Strategy.RollingUpdate).ToNot(BeNil())
+		g.Expect(actualMd.Spec.Strategy.RollingUpdate.DeletePolicy).To(Equal(pointer.String(string(clusterv1.OldestMachineSetDeletePolicy))))
+
+		// Ensure the strategy in the ClusterClass has not been modified.
+		g.Expect(md1.Strategy.RollingUpdate).To(BeNil())
+	})
+
+	t.Run("Triggers a rollout of the machine deployment when the RolloutAfter from the MachineDeploymentTopology is reached", func(t *testing.T) {
+		g := NewWithT(t)
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+
+		past := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		future := metav1.NewTime(time.Now().Add(time.Hour))
+
+		// No rollout is triggered while RolloutAfter is not reached.
+		mdTopology := clusterv1.MachineDeploymentTopology{
+			Class:        "linux-worker",
+			Name:         "big-pool-of-machines",
+			Replicas:     &replicas,
+			RolloutAfter: &future,
+		}
+		actual, err := computeMachineDeployment(ctx, s, nil, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(actual.Object.Spec.Template.Annotations).ToNot(HaveKey(clusterv1.ClusterTopologyMachineDeploymentRolloutAfterAnnotation))
+
+		// A rollout is triggered when RolloutAfter is reached.
+		mdTopology.RolloutAfter = &past
+		actual, err = computeMachineDeployment(ctx, s, nil, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(actual.Object.Spec.Template.Annotations).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachineDeploymentRolloutAfterAnnotation, past.UTC().Format(time.RFC3339)))
+
+		// The annotation of the current MachineDeployment is preserved when RolloutAfter is removed,
+		// so no further rollout is triggered.
+		s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{
+			"big-pool-of-machines": {
+				Object:                        actual.Object,
+				BootstrapTemplate:             workerBootstrapTemplate,
+				InfrastructureMachineTemplate: workerInfrastructureMachineTemplate,
+			},
+		}
+		mdTopology.RolloutAfter = nil
+		actual, err = computeMachineDeployment(ctx, s, nil, mdTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(actual.Object.Spec.Template.Annotations).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachineDeploymentRolloutAfterAnnotation, past.UTC().Format(time.RFC3339)))
 	})
 
 	t.Run("If there is already a machine deployment, it preserves the object name and the reference names", func(t *testing.T) {
@@ -1690,6 +2091,60 @@ func TestComputeMachineDeployment(t *testing.T) {
 	})
 }
 
+func TestNextMachineDeploymentRolloutAfter(t *testing.T) {
+	now := time.Now()
+	past := metav1.NewTime(now.Add(-time.Hour))
+	soon := metav1.NewTime(now.Add(time.Minute))
+	later := metav1.NewTime(now.Add(time.Hour))
+
+	tests := []struct {
+		name               string
+		machineDeployments []clusterv1.MachineDeploymentTopology
+		want               time.Duration
+	}{
+		{
+			name: "no MachineDeployments",
+			want: 0,
+		},
+		{
+			name: "no rollout requested",
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				{Name: "md1"},
+			},
+			want: 0,
+		},
+		{
+			name: "rollout already reached",
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				{Name: "md1", RolloutAfter: &past},
+			},
+			want: 0,
+		},
+		{
+			name: "earliest rollout not yet reached",
+			machineDeployments: []clusterv1.MachineDeploymentTopology{
+				{Name: "md1", RolloutAfter: &past},
+				{Name: "md2", RolloutAfter: &later},
+				{Name: "md3", RolloutAfter: &soon},
+			},
+			want: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(&clusterv1.Topology{
+					Workers: &clusterv1.WorkersTopology{MachineDeployments: tt.machineDeployments},
+				}).
+				Build()
+
+			g.Expect(nextMachineDeploymentRolloutAfter(cluster, now)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestComputeMachineDeploymentVersion(t *testing.T) {
 	controlPlaneStable122 := builder.ControlPlane("test1", "cp1").
 		WithSpecFields(map[string]interface{}{
@@ -1771,6 +2226,19 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 			ReadyReplicas:      1,
 		}).
 		Build()
+	// A machine deployment with all the replicas ready but not yet available, e.g. because
+	// MinReadySeconds are not yet elapsed, is still considered rolling out.
+	machineDeploymentNotAvailable := builder.MachineDeployment("test-namespace", "md-3").
+		WithGeneration(1).
+		WithReplicas(2).
+		WithStatus(clusterv1.MachineDeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           2,
+			UpdatedReplicas:    2,
+			AvailableReplicas:  1,
+			ReadyReplicas:      2,
+		}).
+		Build()
 
 	machineDeploymentsStateStable := scope.MachineDeploymentsStateMap{
 		"md1": &scope.MachineDeploymentState{Object: machineDeploymentStable},
@@ -1780,6 +2248,10 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 		"md1": &scope.MachineDeploymentState{Object: machineDeploymentStable},
 		"md2": &scope.MachineDeploymentState{Object: machineDeploymentRollingOut},
 	}
+	machineDeploymentsStateNotAvailable := scope.MachineDeploymentsStateMap{
+		"md1": &scope.MachineDeploymentState{Object: machineDeploymentStable},
+		"md3": &scope.MachineDeploymentState{Object: machineDeploymentNotAvailable},
+	}
 
 	tests := []struct {
 		name                          string
@@ -1788,6 +2260,7 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 		currentControlPlane           *unstructured.Unstructured
 		desiredControlPlane           *unstructured.Unstructured
 		topologyVersion               string
+		upgradePolicy                 clusterv1.MachineDeploymentsUpgradePolicy
 		expectedVersion               string
 	}{
 		{
@@ -1806,6 +2279,25 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 			topologyVersion:               "v1.2.3",
 			expectedVersion:               "v1.2.2",
 		},
+		{
+			name:                          "should return machine deployment's spec.template.spec.version if any one of the machine deployments has machines not yet available",
+			currentMachineDeploymentState: &scope.MachineDeploymentState{Object: builder.MachineDeployment("test1", "md-current").WithVersion("v1.2.2").Build()},
+			machineDeploymentsStateMap:    machineDeploymentsStateNotAvailable,
+			currentControlPlane:           controlPlaneStable123,
+			desiredControlPlane:           controlPlaneDesired,
+			topologyVersion:               "v1.2.3",
+			expectedVersion:               "v1.2.2",
+		},
+		{
+			name:                          "should return cluster.spec.topology.version if any one of the machine deployments is rolling out but machine deployments are upgraded in parallel",
+			currentMachineDeploymentState: &scope.MachineDeploymentState{Object: builder.MachineDeployment("test1", "md-current").WithVersion("v1.2.2").Build()},
+			machineDeploymentsStateMap:    machineDeploymentsStateRollingOut,
+			currentControlPlane:           controlPlaneStable123,
+			desiredControlPlane:           controlPlaneDesired,
+			topologyVersion:               "v1.2.3",
+			upgradePolicy:                 clusterv1.ParallelMachineDeploymentsUpgradePolicy,
+			expectedVersion:               "v1.2.3",
+		},
 		{
 			// Control plane is considered upgrading if the control plane's spec.version and status.version is not equal.
 			name:                          "should return machine deployment's spec.template.spec.version if control plane is upgrading",
@@ -1834,6 +2326,21 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 			topologyVersion:               "v1.2.3",
 			expectedVersion:               "v1.2.2",
 		},
+		{
+			name: "should return machine deployment's spec.template.spec.version if the machine deployment is blocked by an infrastructure quota",
+			currentMachineDeploymentState: &scope.MachineDeploymentState{Object: builder.MachineDeployment("test1", "md-current").WithVersion("v1.2.2").
+				WithStatus(clusterv1.MachineDeploymentStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.FalseCondition(clusterv1.MachinesCreatedCondition, clusterv1.InfrastructureQuotaExceededReason, clusterv1.ConditionSeverityWarning, ""),
+					},
+				}).
+				Build()},
+			machineDeploymentsStateMap: machineDeploymentsStateStable,
+			currentControlPlane:        controlPlaneStable123,
+			desiredControlPlane:        controlPlaneDesired,
+			topologyVersion:            "v1.2.3",
+			expectedVersion:            "v1.2.2",
+		},
 		{
 			name:                          "should return cluster.spec.topology.version if the control plane is not upgrading, not scaling, not ready to upgrade and none of the machine deployments are rolling out",
 			currentMachineDeploymentState: &scope.MachineDeploymentState{Object: builder.MachineDeployment("test1", "md-current").WithVersion("v1.2.2").Build()},
@@ -1855,6 +2362,9 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 					ControlPlane: clusterv1.ControlPlaneTopology{
 						Replicas: pointer.Int32(2),
 					},
+					Workers: &clusterv1.WorkersTopology{
+						MachineDeploymentsUpgradePolicy: tt.upgradePolicy,
+					},
 				}},
 				Current: &scope.ClusterState{
 					ControlPlane:       &scope.ControlPlaneState{Object: tt.currentControlPlane},
@@ -1870,6 +2380,152 @@ func TestComputeMachineDeploymentVersion(t *testing.T) {
 	}
 }
 
+func TestComputeMachinePool(t *testing.T) {
+	workerInfrastructureMachinePoolTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "linux-worker-inframachinepooltemplate").
+		Build()
+	workerBootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "linux-worker-bootstraptemplate").
+		Build()
+	labels := map[string]string{"fizz": "buzz", "foo": "bar"}
+	annotations := map[string]string{"annotation-1": "annotation-1-val"}
+
+	clusterClassDuration := metav1.Duration{Duration: 20 * time.Second}
+	var clusterClassMinReadySeconds int32 = 20
+	fakeClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+	fakeClass.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{
+		{
+			Class:            "linux-worker",
+			FailureDomains:   []string{"A"},
+			NodeDrainTimeout: &clusterClassDuration,
+			MinReadySeconds:  &clusterClassMinReadySeconds,
+		},
+	}
+
+	version := "v1.21.2"
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			Topology: &clusterv1.Topology{
+				Version: version,
+			},
+		},
+	}
+
+	blueprint := &scope.ClusterBlueprint{
+		Topology:     cluster.Spec.Topology,
+		ClusterClass: fakeClass,
+		MachinePools: map[string]*scope.MachinePoolBlueprint{
+			"linux-worker": {
+				Metadata: clusterv1.ObjectMeta{
+					Labels:      labels,
+					Annotations: annotations,
+				},
+				BootstrapTemplate:                 workerBootstrapTemplate,
+				InfrastructureMachinePoolTemplate: workerInfrastructureMachinePoolTemplate,
+			},
+		},
+	}
+
+	replicas := int32(5)
+	topologyDuration := metav1.Duration{Duration: 10 * time.Second}
+	mpTopology := clusterv1.MachinePoolTopology{
+		Metadata: clusterv1.ObjectMeta{
+			Labels: map[string]string{"foo": "baz"},
+		},
+		Class:            "linux-worker",
+		Name:             "big-pool-of-machines",
+		Replicas:         &replicas,
+		FailureDomains:   []string{"B", "C"},
+		NodeDrainTimeout: &topologyDuration,
+	}
+
+	t.Run("Generates the machine pool and the referenced objects", func(t *testing.T) {
+		g := NewWithT(t)
+		scope := scope.New(cluster)
+		scope.Blueprint = blueprint
+
+		actual, err := computeMachinePool(ctx, scope, nil, mpTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// Ensure the bootstrap config and the infrastructure machine pool are cloned from the templates.
+		g.Expect(actual.BootstrapObject.GetKind()).To(Equal(builder.GenericBootstrapConfigKind))
+		g.Expect(actual.BootstrapObject.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachinePoolNameLabel, "big-pool-of-machines"))
+		g.Expect(actual.BootstrapObject.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(actual.BootstrapObject.GetOwnerReferences()[0].Kind).To(Equal("Cluster"))
+		g.Expect(actual.InfrastructureMachinePoolObject.GetKind()).To(Equal(builder.GenericInfrastructureMachineKind))
+		g.Expect(actual.InfrastructureMachinePoolObject.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachinePoolNameLabel, "big-pool-of-machines"))
+		g.Expect(actual.InfrastructureMachinePoolObject.GetOwnerReferences()).To(HaveLen(1))
+		g.Expect(actual.InfrastructureMachinePoolObject.GetOwnerReferences()[0].Kind).To(Equal("Cluster"))
+
+		actualMp := actual.Object
+		g.Expect(*actualMp.Spec.Replicas).To(Equal(replicas))
+		g.Expect(*actualMp.Spec.MinReadySeconds).To(Equal(clusterClassMinReadySeconds))
+		g.Expect(actualMp.Spec.FailureDomains).To(Equal([]string{"B", "C"}))
+		g.Expect(*actualMp.Spec.Template.Spec.NodeDrainTimeout).To(Equal(topologyDuration))
+		g.Expect(*actualMp.Spec.Template.Spec.Version).To(Equal(version))
+		g.Expect(actualMp.Spec.ClusterName).To(Equal("cluster1"))
+		g.Expect(actualMp.Name).To(ContainSubstring("cluster1"))
+		g.Expect(actualMp.Name).To(ContainSubstring("big-pool-of-machines"))
+
+		g.Expect(actualMp.Labels).To(HaveKeyWithValue(clusterv1.ClusterTopologyMachinePoolNameLabel, "big-pool-of-machines"))
+		g.Expect(actualMp.Labels).To(HaveKey(clusterv1.ClusterTopologyOwnedLabel))
+
+		g.Expect(actualMp.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("foo", "baz"))
+		g.Expect(actualMp.Spec.Template.ObjectMeta.Labels).To(HaveKeyWithValue("fizz", "buzz"))
+		g.Expect(actualMp.Spec.Template.ObjectMeta.Annotations).To(HaveKeyWithValue("annotation-1", "annotation-1-val"))
+		g.Expect(actualMp.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal(actual.BootstrapObject.GetName()))
+		g.Expect(actualMp.Spec.Template.Spec.InfrastructureRef.Name).To(Equal(actual.InfrastructureMachinePoolObject.GetName()))
+	})
+
+	t.Run("If there is already a machine pool, it preserves the object name and the object references", func(t *testing.T) {
+		g := NewWithT(t)
+
+		currentBootstrapObject := &unstructured.Unstructured{}
+		currentBootstrapObject.SetGroupVersionKind(builder.BootstrapGroupVersion.WithKind(builder.GenericBootstrapConfigKind))
+		currentBootstrapObject.SetNamespace(metav1.NamespaceDefault)
+		currentBootstrapObject.SetName("current-bootstrap-config")
+		currentInfrastructureMachinePoolObject := &unstructured.Unstructured{}
+		currentInfrastructureMachinePoolObject.SetGroupVersionKind(builder.InfrastructureGroupVersion.WithKind(builder.GenericInfrastructureMachineKind))
+		currentInfrastructureMachinePoolObject.SetNamespace(metav1.NamespaceDefault)
+		currentInfrastructureMachinePoolObject.SetName("current-infra-machine-pool")
+		currentMp := builder.MachinePool(metav1.NamespaceDefault, "existing-pool-1").
+			WithVersion(version).
+			WithBootstrapTemplate(currentBootstrapObject).
+			WithInfrastructureTemplate(currentInfrastructureMachinePoolObject).
+			Build()
+
+		s := scope.New(cluster)
+		s.Blueprint = blueprint
+		s.Current.MachinePools = map[string]*scope.MachinePoolState{
+			"big-pool-of-machines": {
+				Object:                          currentMp,
+				BootstrapObject:                 currentBootstrapObject,
+				InfrastructureMachinePoolObject: currentInfrastructureMachinePoolObject,
+			},
+		}
+
+		actual, err := computeMachinePool(ctx, s, nil, mpTopology)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(actual.Object.Name).To(Equal("existing-pool-1"))
+		g.Expect(actual.BootstrapObject.GetName()).To(Equal("current-bootstrap-config"))
+		g.Expect(actual.InfrastructureMachinePoolObject.GetName()).To(Equal("current-infra-machine-pool"))
+		g.Expect(actual.Object.Spec.Template.Spec.Bootstrap.ConfigRef.Name).To(Equal("current-bootstrap-config"))
+		g.Expect(actual.Object.Spec.Template.Spec.InfrastructureRef.Name).To(Equal("current-infra-machine-pool"))
+	})
+
+	t.Run("Fails if the MachinePool class does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+		scope := scope.New(cluster)
+		scope.Blueprint = blueprint
+
+		_, err := computeMachinePool(ctx, scope, nil, clusterv1.MachinePoolTopology{Class: "does-not-exist", Name: "pool"})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
 func TestTemplateToObject(t *testing.T) {
 	template := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infrastructureClusterTemplate").
 		WithSpecFields(map[string]interface{}{"spec.template.spec.fakeSetting": true}).
@@ -1944,13 +2600,14 @@ func TestTemplateToTemplate(t *testing.T) {
 
 	t.Run("Generates a template from a template", func(t *testing.T) {
 		g := NewWithT(t)
-		obj := templateToTemplate(templateToInput{
+		obj, err := templateToTemplate(templateToInput{
 			template:              template,
 			templateClonedFromRef: fakeRef1,
 			cluster:               cluster,
 			namePrefix:            cluster.Name,
 			currentObjectRef:      nil,
 		})
+		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(obj).ToNot(BeNil())
 		assertTemplateToTemplate(g, assertTemplateInput{
 			cluster:     cluster,
@@ -1962,13 +2619,14 @@ func TestTemplateToTemplate(t *testing.T) {
 	})
 	t.Run("Overrides the generated name if there is already a reference", func(t *testing.T) {
 		g := NewWithT(t)
-		obj := templateToTemplate(templateToInput{
+		obj, err := templateToTemplate(templateToInput{
 			template:              template,
 			templateClonedFromRef: fakeRef1,
 			cluster:               cluster,
 			namePrefix:            cluster.Name,
 			currentObjectRef:      fakeRef2,
 		})
+		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(obj).ToNot(BeNil())
 		assertTemplateToTemplate(g, assertTemplateInput{
 			cluster:     cluster,
@@ -2003,6 +2661,9 @@ func assertTemplateToObject(g *WithT, in assertTemplateInput) {
 	g.Expect(in.obj.GetNamespace()).To(Equal(in.cluster.Namespace))
 	g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterLabelName, in.cluster.Name))
 	g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyOwnedLabel, ""))
+	if in.cluster.Spec.Topology != nil && in.cluster.Spec.Topology.Class != "" {
+		g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyClassNameLabel, in.cluster.Spec.Topology.Class))
+	}
 	for k, v := range in.labels {
 		g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(k, v))
 	}
@@ -2038,6 +2699,9 @@ func assertTemplateToTemplate(g *WithT, in assertTemplateInput) {
 	g.Expect(in.obj.GetNamespace()).To(Equal(in.cluster.Namespace))
 	g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterLabelName, in.cluster.Name))
 	g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyOwnedLabel, ""))
+	if in.cluster.Spec.Topology != nil && in.cluster.Spec.Topology.Class != "" {
+		g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(clusterv1.ClusterTopologyClassNameLabel, in.cluster.Spec.Topology.Class))
+	}
 	for k, v := range in.labels {
 		g.Expect(in.obj.GetLabels()).To(HaveKeyWithValue(k, v))
 	}
@@ -2106,6 +2770,120 @@ func TestMergeMap(t *testing.T) {
 	})
 }
 
+func TestMergeTopologyMetadataMap(t *testing.T) {
+	topology := map[string]string{"a": "a", "b": "b"}
+	clusterClass := map[string]string{"a": "ax", "c": "c"}
+
+	t.Run("Cluster topology takes precedence by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{}
+		g.Expect(mergeTopologyMetadataMap(cluster, topology, clusterClass)).To(Equal(map[string]string{"a": "a", "b": "b", "c": "c"}))
+	})
+	t.Run("ClusterClass takes precedence if required by the precedence annotation", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			clusterv1.ClusterTopologyMetadataPrecedenceAnnotation: clusterv1.ClusterTopologyMetadataPrecedenceClusterClass,
+		}}}
+		g.Expect(mergeTopologyMetadataMap(cluster, topology, clusterClass)).To(Equal(map[string]string{"a": "ax", "b": "b", "c": "c"}))
+	})
+	t.Run("Reports conflicting keys", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(metadataConflicts(topology, clusterClass)).To(Equal([]string{"a"}))
+		g.Expect(metadataConflicts(topology, map[string]string{"a": "a"})).To(BeEmpty())
+	})
+}
+
+func TestReportTopologyMetadataConflicts(t *testing.T) {
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithControlPlaneMetadata(map[string]string{"foo": "class"}, nil).
+		Build()
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		labels        map[string]string
+		wantEvent     bool
+		wantCondition *clusterv1.Condition
+	}{
+		{
+			name:          "Does not report anything if there are no conflicts",
+			labels:        map[string]string{"foo": "class"},
+			wantEvent:     false,
+			wantCondition: conditions.TrueCondition(clusterv1.TopologyMetadataConsistentCondition),
+		},
+		{
+			name:          "Reports conflicts",
+			labels:        map[string]string{"foo": "topology"},
+			wantEvent:     true,
+			wantCondition: conditions.FalseCondition(clusterv1.TopologyMetadataConsistentCondition, clusterv1.TopologyMetadataConflictReason, clusterv1.ConditionSeverityWarning, ""),
+		},
+		{
+			name:        "Does not report conflicts if the precedence is set explicitly",
+			annotations: map[string]string{clusterv1.ClusterTopologyMetadataPrecedenceAnnotation: clusterv1.ClusterTopologyMetadataPrecedenceCluster},
+			labels:      map[string]string{"foo": "topology"},
+			wantEvent:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: metav1.NamespaceDefault, Annotations: tt.annotations},
+				Spec: clusterv1.ClusterSpec{
+					Topology: &clusterv1.Topology{
+						ControlPlane: clusterv1.ControlPlaneTopology{
+							Metadata: clusterv1.ObjectMeta{Labels: tt.labels},
+						},
+					},
+				},
+			}
+			s := scope.New(cluster)
+			s.Blueprint = &scope.ClusterBlueprint{
+				Topology:     cluster.Spec.Topology,
+				ClusterClass: clusterClass,
+			}
+
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{recorder: recorder}
+			r.reportTopologyMetadataConflicts(s)
+
+			if tt.wantCondition == nil {
+				g.Expect(conditions.Has(cluster, clusterv1.TopologyMetadataConsistentCondition)).To(BeFalse())
+			} else {
+				c := conditions.Get(cluster, clusterv1.TopologyMetadataConsistentCondition)
+				g.Expect(c).ToNot(BeNil())
+				g.Expect(c.Status).To(Equal(tt.wantCondition.Status))
+				g.Expect(c.Reason).To(Equal(tt.wantCondition.Reason))
+			}
+
+			if !tt.wantEvent {
+				g.Expect(recorder.Events).To(BeEmpty())
+				return
+			}
+			g.Expect(recorder.Events).To(Receive(And(
+				ContainSubstring(metadataConflictEventReason),
+				ContainSubstring("controlPlane labels foo"),
+			)))
+			g.Expect(conditions.GetMessage(cluster, clusterv1.TopologyMetadataConsistentCondition)).To(ContainSubstring("controlPlane labels foo"))
+
+			// The event is not recorded again if the conflicts did not change.
+			r.reportTopologyMetadataConflicts(s)
+			g.Expect(recorder.Events).To(BeEmpty())
+
+			// The event is recorded again if the conflicts changed.
+			s.Blueprint.Topology.ControlPlane.Metadata.Annotations = map[string]string{"bar": "topology"}
+			s.Blueprint.ClusterClass = s.Blueprint.ClusterClass.DeepCopy()
+			s.Blueprint.ClusterClass.Spec.ControlPlane.Metadata.Annotations = map[string]string{"bar": "class"}
+			r.reportTopologyMetadataConflicts(s)
+			g.Expect(recorder.Events).To(Receive(ContainSubstring("controlPlane annotations bar")))
+		})
+	}
+}
+
 func Test_computeMachineHealthCheck(t *testing.T) {
 	maxUnhealthyValue := intstr.FromString("100%")
 	mhcSpec := &clusterv1.MachineHealthCheckClass{
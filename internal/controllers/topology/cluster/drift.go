@@ -0,0 +1,233 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// maxReportedFieldsPerManager is the maximum number of fields reported for each manager changing an object out-of-band.
+const maxReportedFieldsPerManager = 5
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(driftDetectedTotal)
+}
+
+// driftDetectedTotal reports the out-of-band changes detected in objects generated from a Cluster topology.
+var driftDetectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Subsystem: "capi_topology",
+	Name:      "drift_detected_total",
+	Help:      "Number of out-of-band changes detected in objects generated from a Cluster topology, partitioned by kind of the object.",
+}, []string{"kind"})
+
+// getDriftPolicy returns the drift policy defined on a Cluster; an empty string means that drift detection is disabled.
+func getDriftPolicy(cluster *clusterv1.Cluster) string {
+	switch policy := cluster.GetAnnotations()[clusterv1.ClusterTopologyDriftPolicyAnnotation]; policy {
+	case clusterv1.ClusterTopologyDriftPolicyReport, clusterv1.ClusterTopologyDriftPolicyEnforce:
+		return policy
+	default:
+		return ""
+	}
+}
+
+// setDesiredStateHash stores the hash of the desired state of an object generated from a Cluster topology
+// in the ClusterTopologyDesiredStateHashAnnotation, if drift detection is enabled for the Cluster.
+func setDesiredStateHash(cluster *clusterv1.Cluster, desired client.Object) error {
+	if getDriftPolicy(cluster) == "" {
+		return nil
+	}
+
+	hash, err := computeDesiredStateHash(desired)
+	if err != nil {
+		return errors.Wrapf(err, "failed to compute desired state hash for %s", tlog.KObj{Obj: desired})
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.ClusterTopologyDesiredStateHashAnnotation] = hash
+	desired.SetAnnotations(annotations)
+	return nil
+}
+
+// computeDesiredStateHash returns the hash of the desired state of an object, ignoring the desired state hash annotation.
+func computeDesiredStateHash(desired client.Object) (string, error) {
+	obj := desired.DeepCopyObject().(client.Object)
+	annotations := obj.GetAnnotations()
+	delete(annotations, clusterv1.ClusterTopologyDesiredStateHashAnnotation)
+	obj.SetAnnotations(annotations)
+
+	return computeHash(obj)
+}
+
+// reconcileDrift checks if an object generated from a Cluster topology has been changed out-of-band, i.e. if the
+// object has changes to be applied even if its desired state did not change since the last time it has been applied.
+// Out-of-band changes are recorded in the DriftTracker and reported with events and metrics; the func returns true
+// if the changes must not be reverted according to the drift policy of the Cluster.
+// NOTE: The desired object must have the desired state hash set by setDesiredStateHash.
+func (r *Reconciler) reconcileDrift(ctx context.Context, cluster *clusterv1.Cluster, driftTracker *scope.DriftTracker, current, desired client.Object, patchHelper structuredmerge.PatchHelper) bool {
+	log := tlog.LoggerFrom(ctx)
+
+	policy := getDriftPolicy(cluster)
+	if policy == "" || !patchHelper.HasChanges() {
+		return false
+	}
+	hash := desired.GetAnnotations()[clusterv1.ClusterTopologyDesiredStateHashAnnotation]
+	if hash == "" || current.GetAnnotations()[clusterv1.ClusterTopologyDesiredStateHashAnnotation] != hash {
+		return false
+	}
+
+	// NOTE: The kind is read from the desired object, given that TypeMeta could be empty in typed current objects.
+	kind := desired.GetObjectKind().GroupVersionKind().Kind
+	changes := outOfBandChanges(current)
+	driftTracker.Add(kind, current.GetName(), changes)
+
+	// Out-of-band changes already reported by a previous reconcile, e.g. changes which are not reverted because of
+	// the Report policy, are not reported again with events and metrics.
+	reported := isDriftReported(cluster, kind, current.GetName(), changes)
+	if !reported {
+		driftDetectedTotal.WithLabelValues(kind).Inc()
+	}
+
+	if policy == clusterv1.ClusterTopologyDriftPolicyReport {
+		if !reported {
+			log.Infof("Detected out-of-band changes to %s, not reverting them as required by the drift policy", tlog.KObj{Obj: current})
+			r.recorder.Eventf(cluster, corev1.EventTypeWarning, driftDetectedEventReason, "Detected out-of-band changes to %q, not reverting them", tlog.KObj{Obj: current})
+		}
+		return true
+	}
+	if !reported {
+		log.Infof("Detected out-of-band changes to %s, reverting them", tlog.KObj{Obj: current})
+		r.recorder.Eventf(cluster, corev1.EventTypeWarning, driftDetectedEventReason, "Detected out-of-band changes to %q, reverting them", tlog.KObj{Obj: current})
+	}
+	return false
+}
+
+// isDriftReported returns true if the out-of-band changes to an object are already reported in the TopologyInSync
+// condition of the Cluster, i.e. if they have been detected by a previous reconcile.
+// NOTE: The condition on the Cluster is the one set by the previous reconcile, given that conditions are
+// computed at the end of the reconcile.
+func isDriftReported(cluster *clusterv1.Cluster, kind, name string, changes []string) bool {
+	c := conditions.Get(cluster, clusterv1.TopologyInSyncCondition)
+	if c == nil || c.Status != corev1.ConditionFalse || c.Reason != clusterv1.TopologyDriftDetectedReason {
+		return false
+	}
+	// NOTE: Objects in the condition message are separated by ", ", so matching the separator after the drift
+	// message prevents matching objects whose name starts with the name of the given object.
+	msg := scope.DriftMessage(kind, name, changes)
+	return strings.HasSuffix(c.Message, msg) || strings.Contains(c.Message, msg+", ")
+}
+
+// outOfBandChanges returns a description of the changes applied to an object by managers other than the topology
+// controller after the topology controller last applied the object, as recorded in managedFields,
+// e.g. "kubectl-edit: spec.replicas".
+// NOTE: Changes to the status subresource are ignored, given that they are expected to be done by other controllers.
+func outOfBandChanges(obj client.Object) []string {
+	var lastApplied *metav1.Time
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == structuredmerge.TopologyManagerName && entry.Operation == metav1.ManagedFieldsOperationApply && entry.Subresource == "" {
+			lastApplied = entry.Time
+		}
+	}
+
+	changes := []string{}
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == structuredmerge.TopologyManagerName || entry.Subresource != "" {
+			continue
+		}
+		if lastApplied != nil && entry.Time != nil && entry.Time.Before(lastApplied) {
+			continue
+		}
+
+		fields := managedFieldPaths(entry.FieldsV1)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > maxReportedFieldsPerManager {
+			fields = append(fields[:maxReportedFieldsPerManager], fmt.Sprintf("and %d more", len(fields)-maxReportedFieldsPerManager))
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s", entry.Manager, strings.Join(fields, ", ")))
+	}
+	return changes
+}
+
+// managedFieldPaths returns the paths of the fields in a managedFields entry, e.g. spec.replicas.
+// NOTE: Items of lists are not reported individually, but only the path of the list is reported.
+func managedFieldPaths(fieldsV1 *metav1.FieldsV1) []string {
+	if fieldsV1 == nil {
+		return nil
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(fieldsV1.Raw, &fields); err != nil {
+		return nil
+	}
+
+	paths := sets.NewString()
+	collectManagedFieldPaths(paths, "", fields)
+	return paths.List()
+}
+
+func collectManagedFieldPaths(paths sets.String, prefix string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if k == "." {
+			continue
+		}
+		// Keys not starting with "f:" identify list items (e.g. "k:{...}", "v:..."), report the path of the list.
+		if !strings.HasPrefix(k, "f:") {
+			if prefix != "" {
+				paths.Insert(prefix)
+			}
+			continue
+		}
+
+		path := strings.TrimPrefix(k, "f:")
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		children, _ := fields[k].(map[string]interface{})
+		if len(children) == 0 || (len(children) == 1 && children["."] != nil) {
+			paths.Insert(path)
+			continue
+		}
+		collectManagedFieldPaths(paths, path, children)
+	}
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestSetDesiredStateHash(t *testing.T) {
+	clusterWithDriftPolicy := func(policy string) *clusterv1.Cluster {
+		cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+		if policy != "" {
+			cluster.Annotations = map[string]string{clusterv1.ClusterTopologyDriftPolicyAnnotation: policy}
+		}
+		return cluster
+	}
+
+	t.Run("The hash is not set if drift detection is disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		desired := builder.MachineDeployment(metav1.NamespaceDefault, "md1").Build()
+		g.Expect(setDesiredStateHash(clusterWithDriftPolicy(""), desired)).To(Succeed())
+		g.Expect(desired.Annotations).ToNot(HaveKey(clusterv1.ClusterTopologyDesiredStateHashAnnotation))
+
+		g.Expect(setDesiredStateHash(clusterWithDriftPolicy("invalid"), desired)).To(Succeed())
+		g.Expect(desired.Annotations).ToNot(HaveKey(clusterv1.ClusterTopologyDesiredStateHashAnnotation))
+	})
+	t.Run("The hash is set if drift detection is enabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		desired := builder.MachineDeployment(metav1.NamespaceDefault, "md1").Build()
+		g.Expect(setDesiredStateHash(clusterWithDriftPolicy(clusterv1.ClusterTopologyDriftPolicyReport), desired)).To(Succeed())
+		g.Expect(desired.Annotations).To(HaveKey(clusterv1.ClusterTopologyDesiredStateHashAnnotation))
+		hash := desired.Annotations[clusterv1.ClusterTopologyDesiredStateHashAnnotation]
+
+		// The hash does not depend on the hash annotation itself.
+		g.Expect(setDesiredStateHash(clusterWithDriftPolicy(clusterv1.ClusterTopologyDriftPolicyReport), desired)).To(Succeed())
+		g.Expect(desired.Annotations).To(HaveKeyWithValue(clusterv1.ClusterTopologyDesiredStateHashAnnotation, hash))
+
+		// The hash changes when the desired state changes.
+		desired.Spec.Replicas = pointer.Int32(3)
+		g.Expect(setDesiredStateHash(clusterWithDriftPolicy(clusterv1.ClusterTopologyDriftPolicyEnforce), desired)).To(Succeed())
+		g.Expect(desired.Annotations[clusterv1.ClusterTopologyDesiredStateHashAnnotation]).ToNot(Equal(hash))
+	})
+}
+
+func TestReconcileDrift(t *testing.T) {
+	desiredWithHash := func(hash string) *clusterv1.MachineDeployment {
+		md := builder.MachineDeployment(metav1.NamespaceDefault, "md1").Build()
+		md.Annotations = map[string]string{clusterv1.ClusterTopologyDesiredStateHashAnnotation: hash}
+		return md
+	}
+	currentWithHash := func(hash string) *clusterv1.MachineDeployment {
+		md := desiredWithHash(hash)
+		md.TypeMeta = metav1.TypeMeta{}
+		md.ManagedFields = []metav1.ManagedFieldsEntry{
+			{
+				Manager:   structuredmerge.TopologyManagerName,
+				Operation: metav1.ManagedFieldsOperationApply,
+				Time:      &metav1.Time{Time: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+				FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:version":{}}}}}`)},
+			},
+			{
+				Manager:   "kubectl-edit",
+				Operation: metav1.ManagedFieldsOperationUpdate,
+				Time:      &metav1.Time{Time: time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)},
+				FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+			},
+		}
+		return md
+	}
+
+	tests := []struct {
+		name        string
+		policy      string
+		current     *clusterv1.MachineDeployment
+		desired     *clusterv1.MachineDeployment
+		hasChanges  bool
+		wantSkip    bool
+		wantDrifted bool
+	}{
+		{
+			name:        "No drift if drift detection is disabled",
+			policy:      "",
+			current:     currentWithHash("a"),
+			desired:     desiredWithHash("a"),
+			hasChanges:  true,
+			wantSkip:    false,
+			wantDrifted: false,
+		},
+		{
+			name:        "No drift if there are no changes",
+			policy:      clusterv1.ClusterTopologyDriftPolicyReport,
+			current:     currentWithHash("a"),
+			desired:     desiredWithHash("a"),
+			hasChanges:  false,
+			wantSkip:    false,
+			wantDrifted: false,
+		},
+		{
+			name:        "No drift if the desired state changed",
+			policy:      clusterv1.ClusterTopologyDriftPolicyReport,
+			current:     currentWithHash("a"),
+			desired:     desiredWithHash("b"),
+			hasChanges:  true,
+			wantSkip:    false,
+			wantDrifted: false,
+		},
+		{
+			name:        "Drift is reported and changes are not reverted with the Report policy",
+			policy:      clusterv1.ClusterTopologyDriftPolicyReport,
+			current:     currentWithHash("a"),
+			desired:     desiredWithHash("a"),
+			hasChanges:  true,
+			wantSkip:    true,
+			wantDrifted: true,
+		},
+		{
+			name:        "Drift is reported and changes are reverted with the Enforce policy",
+			policy:      clusterv1.ClusterTopologyDriftPolicyEnforce,
+			current:     currentWithHash("a"),
+			desired:     desiredWithHash("a"),
+			hasChanges:  true,
+			wantSkip:    false,
+			wantDrifted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+			if tt.policy != "" {
+				cluster.Annotations = map[string]string{clusterv1.ClusterTopologyDriftPolicyAnnotation: tt.policy}
+			}
+			driftTracker := scope.NewDriftTracker()
+			r := &Reconciler{recorder: record.NewFakeRecorder(32)}
+
+			skip := r.reconcileDrift(ctx, cluster, driftTracker, tt.current, tt.desired, &fakePatchHelper{hasChanges: tt.hasChanges})
+			g.Expect(skip).To(Equal(tt.wantSkip))
+			g.Expect(driftTracker.IsDrifted()).To(Equal(tt.wantDrifted))
+			if tt.wantDrifted {
+				g.Expect(driftTracker.AggregateMessage()).To(Equal("MachineDeployment md1 (kubectl-edit: spec.replicas)"))
+			}
+		})
+	}
+}
+
+func TestReconcileDriftReportsNewDriftOnly(t *testing.T) {
+	g := NewWithT(t)
+
+	desired := builder.MachineDeployment(metav1.NamespaceDefault, "md1").Build()
+	desired.Annotations = map[string]string{clusterv1.ClusterTopologyDesiredStateHashAnnotation: "a"}
+	current := desired.DeepCopy()
+	current.TypeMeta = metav1.TypeMeta{}
+	current.ManagedFields = []metav1.ManagedFieldsEntry{
+		{
+			Manager:   "kubectl-edit",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		},
+	}
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+	cluster.Annotations = map[string]string{clusterv1.ClusterTopologyDriftPolicyAnnotation: clusterv1.ClusterTopologyDriftPolicyReport}
+	recorder := record.NewFakeRecorder(32)
+	r := &Reconciler{recorder: recorder}
+
+	// Reconcile the same drift twice, setting the TopologyInSync condition at the end of each reconcile.
+	for i := 0; i < 2; i++ {
+		s := &scope.Scope{DriftTracker: scope.NewDriftTracker()}
+		g.Expect(r.reconcileDrift(ctx, cluster, s.DriftTracker, current, desired, &fakePatchHelper{hasChanges: true})).To(BeTrue())
+		g.Expect(s.DriftTracker.IsDrifted()).To(BeTrue())
+		reconcileTopologyInSyncCondition(s, cluster, nil)
+	}
+	g.Expect(recorder.Events).To(HaveLen(1))
+
+	// A different drift of the same object is reported again.
+	current.ManagedFields[0].FieldsV1 = &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:paused":{}}}`)}
+	s := &scope.Scope{DriftTracker: scope.NewDriftTracker()}
+	g.Expect(r.reconcileDrift(ctx, cluster, s.DriftTracker, current, desired, &fakePatchHelper{hasChanges: true})).To(BeTrue())
+	g.Expect(recorder.Events).To(HaveLen(2))
+}
+
+func TestOutOfBandChanges(t *testing.T) {
+	lastApplied := time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		managedFields []metav1.ManagedFieldsEntry
+		want          []string
+	}{
+		{
+			name:          "No changes without managed fields",
+			managedFields: nil,
+			want:          []string{},
+		},
+		{
+			name: "Changes from other managers after the last apply are reported",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   structuredmerge.TopologyManagerName,
+					Operation: metav1.ManagedFieldsOperationApply,
+					Time:      &metav1.Time{Time: lastApplied},
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:version":{}}}`)},
+				},
+				{
+					Manager:   "before",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					Time:      &metav1.Time{Time: lastApplied.Add(-time.Hour)},
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:foo":{}}}`)},
+				},
+				{
+					Manager:     "status-manager",
+					Operation:   metav1.ManagedFieldsOperationUpdate,
+					Subresource: "status",
+					Time:        &metav1.Time{Time: lastApplied.Add(time.Hour)},
+					FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:ready":{}}}`)},
+				},
+				{
+					Manager:   "kubectl-edit",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					Time:      &metav1.Time{Time: lastApplied.Add(time.Hour)},
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{".":{},"f:foo":{}}},` +
+						`"f:spec":{"f:replicas":{},"f:template":{"f:spec":{"f:files":{"k:{\"path\":\"/tmp\"}":{".":{},"f:content":{}}}}}}}`)},
+				},
+			},
+			want: []string{"kubectl-edit: metadata.labels.foo, spec.replicas, spec.template.spec.files"},
+		},
+		{
+			name: "The number of reported fields is capped",
+			managedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   "kubectl-edit",
+					Operation: metav1.ManagedFieldsOperationUpdate,
+					FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:a":{},"f:b":{},"f:c":{},"f:d":{},"f:e":{},"f:f":{},"f:g":{}}}`)},
+				},
+			},
+			want: []string{"kubectl-edit: spec.a, spec.b, spec.c, spec.d, spec.e, and 2 more"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := builder.MachineDeployment(metav1.NamespaceDefault, "md1").Build()
+			md.ManagedFields = tt.managedFields
+			g.Expect(outOfBandChanges(md)).To(Equal(tt.want))
+		})
+	}
+}
+
+type fakePatchHelper struct {
+	hasChanges bool
+}
+
+func (h *fakePatchHelper) HasSpecChanges() bool {
+	return h.hasChanges
+}
+
+func (h *fakePatchHelper) HasChanges() bool {
+	return h.hasChanges
+}
+
+func (h *fakePatchHelper) Patch(_ context.Context) error {
+	return nil
+}
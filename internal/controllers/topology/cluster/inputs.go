@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+)
+
+// topologyInputs are the inputs used to compute the desired state of an object generated from a Cluster topology.
+type topologyInputs struct {
+	// ClusterClassGeneration is the generation of the ClusterClass.
+	ClusterClassGeneration int64 `json:"clusterClassGeneration"`
+
+	// Templates are the resourceVersions of the templates the object is generated from, indexed by kind and name.
+	Templates map[string]string `json:"templates,omitempty"`
+
+	// Topology is the topology of the Cluster.
+	Topology *clusterv1.Topology `json:"topology"`
+}
+
+// setInputsHashes stores the hash of the inputs used to compute the desired state of the InfrastructureCluster,
+// the ControlPlane, the MachineDeployments and the MachinePools in the ClusterTopologyInputsHashAnnotation.
+func setInputsHashes(s *scope.Scope, desired *scope.ClusterState) error {
+	if desired.InfrastructureCluster != nil {
+		if err := setInputsHash(s, desired.InfrastructureCluster, s.Blueprint.InfrastructureClusterTemplate); err != nil {
+			return err
+		}
+	}
+
+	if desired.ControlPlane != nil && desired.ControlPlane.Object != nil && s.Blueprint.ControlPlane != nil {
+		templates := []*unstructured.Unstructured{s.Blueprint.ControlPlane.Template, s.Blueprint.ControlPlane.InfrastructureMachineTemplate}
+		for _, failureDomain := range sortedFailureDomains(s.Blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates) {
+			templates = append(templates, s.Blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain])
+		}
+		if err := setInputsHash(s, desired.ControlPlane.Object, templates...); err != nil {
+			return err
+		}
+	}
+
+	if s.Blueprint.Topology == nil || s.Blueprint.Topology.Workers == nil {
+		return nil
+	}
+	for _, mdTopology := range s.Blueprint.Topology.Workers.MachineDeployments {
+		md, ok := desired.MachineDeployments[mdTopology.Name]
+		if !ok || md.Object == nil {
+			continue
+		}
+		mdBlueprint, ok := s.Blueprint.MachineDeployments[mdTopology.Class]
+		if !ok {
+			continue
+		}
+		if err := setInputsHash(s, md.Object, mdBlueprint.BootstrapTemplate, mdBlueprint.InfrastructureMachineTemplate); err != nil {
+			return err
+		}
+	}
+	for _, mpTopology := range s.Blueprint.Topology.Workers.MachinePools {
+		mp, ok := desired.MachinePools[mpTopology.Name]
+		if !ok || mp.Object == nil {
+			continue
+		}
+		mpBlueprint, ok := s.Blueprint.MachinePools[mpTopology.Class]
+		if !ok {
+			continue
+		}
+		if err := setInputsHash(s, mp.Object, mpBlueprint.BootstrapTemplate, mpBlueprint.InfrastructureMachinePoolTemplate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setInputsHash stores the hash of the inputs used to compute the desired state of an object in the ClusterTopologyInputsHashAnnotation.
+func setInputsHash(s *scope.Scope, desired client.Object, templates ...*unstructured.Unstructured) error {
+	inputs := topologyInputs{
+		ClusterClassGeneration: s.Blueprint.ClusterClass.GetGeneration(),
+		Templates:              map[string]string{},
+		Topology:               s.Blueprint.Topology,
+	}
+	for _, t := range templates {
+		if t == nil {
+			continue
+		}
+		inputs.Templates[fmt.Sprintf("%s/%s", t.GetKind(), t.GetName())] = t.GetResourceVersion()
+	}
+
+	hash, err := computeHash(inputs)
+	if err != nil {
+		return errors.Wrapf(err, "failed to compute inputs hash for %s", tlog.KObj{Obj: desired})
+	}
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.ClusterTopologyInputsHashAnnotation] = hash
+	desired.SetAnnotations(annotations)
+	return nil
+}
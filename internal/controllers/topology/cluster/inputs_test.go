@@ -0,0 +1,137 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestSetInputsHashes(t *testing.T) {
+	infrastructureClusterTemplate := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra-cluster-template").Build()
+	controlPlaneTemplate := builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp-template").Build()
+	bootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap-template").Build()
+	infrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-machine-template").Build()
+
+	newScope := func() *scope.Scope {
+		cluster := &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster1",
+				Namespace: metav1.NamespaceDefault,
+			},
+		}
+		s := scope.New(cluster)
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology: &clusterv1.Topology{
+				Version: "v1.22.0",
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{Class: "linux-worker", Name: "md1"},
+					},
+				},
+			},
+			ClusterClass:                  builder.ClusterClass(metav1.NamespaceDefault, "class1").Build(),
+			InfrastructureClusterTemplate: infrastructureClusterTemplate.DeepCopy(),
+			ControlPlane: &scope.ControlPlaneBlueprint{
+				Template: controlPlaneTemplate.DeepCopy(),
+			},
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+				"linux-worker": {
+					BootstrapTemplate:             bootstrapTemplate.DeepCopy(),
+					InfrastructureMachineTemplate: infrastructureMachineTemplate.DeepCopy(),
+				},
+			},
+		}
+		return s
+	}
+	newDesiredState := func() *scope.ClusterState {
+		return &scope.ClusterState{
+			InfrastructureCluster: builder.InfrastructureCluster(metav1.NamespaceDefault, "infra-cluster").Build(),
+			ControlPlane: &scope.ControlPlaneState{
+				Object: builder.ControlPlane(metav1.NamespaceDefault, "cp").Build(),
+			},
+			MachineDeployments: map[string]*scope.MachineDeploymentState{
+				"md1": {Object: builder.MachineDeployment(metav1.NamespaceDefault, "md1").Build()},
+			},
+		}
+	}
+	inputsHashes := func(desired *scope.ClusterState) []string {
+		return []string{
+			desired.InfrastructureCluster.GetAnnotations()[clusterv1.ClusterTopologyInputsHashAnnotation],
+			desired.ControlPlane.Object.GetAnnotations()[clusterv1.ClusterTopologyInputsHashAnnotation],
+			desired.MachineDeployments["md1"].Object.GetAnnotations()[clusterv1.ClusterTopologyInputsHashAnnotation],
+		}
+	}
+
+	g := NewWithT(t)
+
+	desired := newDesiredState()
+	g.Expect(setInputsHashes(newScope(), desired)).To(Succeed())
+	hashes := inputsHashes(desired)
+	for _, h := range hashes {
+		g.Expect(h).ToNot(BeEmpty())
+	}
+
+	// The hashes do not change if the inputs do not change.
+	desired = newDesiredState()
+	g.Expect(setInputsHashes(newScope(), desired)).To(Succeed())
+	g.Expect(inputsHashes(desired)).To(Equal(hashes))
+
+	// Only the hash of the MachineDeployment changes if its templates change.
+	s := newScope()
+	s.Blueprint.MachineDeployments["linux-worker"].BootstrapTemplate.SetResourceVersion("2")
+	desired = newDesiredState()
+	g.Expect(setInputsHashes(s, desired)).To(Succeed())
+	got := inputsHashes(desired)
+	g.Expect(got[0]).To(Equal(hashes[0]))
+	g.Expect(got[1]).To(Equal(hashes[1]))
+	g.Expect(got[2]).ToNot(Equal(hashes[2]))
+
+	// All the hashes change if the ClusterClass or the Cluster topology change.
+	for _, mutate := range []func(s *scope.Scope){
+		func(s *scope.Scope) { s.Blueprint.ClusterClass.SetGeneration(2) },
+		func(s *scope.Scope) { s.Blueprint.Topology.Version = "v1.23.0" },
+	} {
+		s := newScope()
+		mutate(s)
+		desired = newDesiredState()
+		g.Expect(setInputsHashes(s, desired)).To(Succeed())
+		got := inputsHashes(desired)
+		for i := range got {
+			g.Expect(got[i]).ToNot(Equal(hashes[i]))
+		}
+	}
+}
+
+func TestComputeHash(t *testing.T) {
+	g := NewWithT(t)
+
+	a := topologyInputs{Templates: map[string]string{"A/a": "1", "B/b": "2"}}
+	b := topologyInputs{Templates: map[string]string{"B/b": "2", "A/a": "1"}}
+
+	hashA, err := computeHash(a)
+	g.Expect(err).ToNot(HaveOccurred())
+	hashB, err := computeHash(b)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hashA).To(Equal(hashB))
+}
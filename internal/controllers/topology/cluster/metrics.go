@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+)
+
+const (
+	// machinesKindControlPlane is the value of the kind label for the Machines of the control plane.
+	machinesKindControlPlane = "ControlPlane"
+
+	// machinesKindMachineDeployment is the value of the kind label for the Machines of a MachineDeployment.
+	machinesKindMachineDeployment = "MachineDeployment"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(machinesTotal, machinesOutOfDate)
+}
+
+// machineLabels are the labels of the Machine metrics; name is the name of the MachineDeployment topology,
+// and it is empty for the control plane.
+var machineLabels = []string{"namespace", "cluster", "cluster_class", "kind", "name"}
+
+var (
+	// machinesTotal reports the number of Machines of a Cluster topology.
+	machinesTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "capi_topology",
+		Name:      "machines",
+		Help:      "Number of Machines of a Cluster topology, partitioned by Cluster, ClusterClass and control plane or MachineDeployment topology.",
+	}, machineLabels)
+
+	// machinesOutOfDate reports the number of Machines of a Cluster topology which do not match the desired
+	// version or template yet, e.g. while rolling out a change to the topology or to the ClusterClass.
+	machinesOutOfDate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "capi_topology",
+		Name:      "machines_out_of_date",
+		Help:      "Number of Machines of a Cluster topology not matching the desired version or template, partitioned by Cluster, ClusterClass and control plane or MachineDeployment topology.",
+	}, machineLabels)
+)
+
+// machineCount is the number of Machines, and the number of out of date Machines, of a control plane or MachineDeployment.
+type machineCount struct {
+	kind      string
+	name      string
+	total     int64
+	outOfDate int64
+}
+
+// reportMachineMetrics reports the Machine metrics for a Cluster topology, computed from the current state.
+func reportMachineMetrics(s *scope.Scope) {
+	cluster := s.Current.Cluster
+
+	// Delete the metrics previously reported for the Cluster, so series for MachineDeployments which have
+	// been removed from the topology or for the previous ClusterClass, if the class has been changed, go away.
+	deleteMachineMetrics(cluster.Namespace, cluster.Name)
+
+	for _, count := range computeMachineCounts(s) {
+		labels := prometheus.Labels{
+			"namespace":     cluster.Namespace,
+			"cluster":       cluster.Name,
+			"cluster_class": cluster.Spec.Topology.Class,
+			"kind":          count.kind,
+			"name":          count.name,
+		}
+		machinesTotal.With(labels).Set(float64(count.total))
+		machinesOutOfDate.With(labels).Set(float64(count.outOfDate))
+	}
+}
+
+// deleteMachineMetrics deletes the Machine metrics reported for a Cluster.
+func deleteMachineMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "cluster": name}
+	machinesTotal.DeletePartialMatch(labels)
+	machinesOutOfDate.DeletePartialMatch(labels)
+}
+
+// computeMachineCounts computes the number of Machines, and the number of out of date Machines, for the
+// control plane and the MachineDeployments of a Cluster topology.
+// NOTE: Machines are considered out of date if they are not yet updated to the current spec of the owning
+// control plane or MachineDeployment or, if the version of the owning object does not match yet the version
+// defined in the topology (e.g. the upgrade is pending), all the Machines are considered out of date.
+func computeMachineCounts(s *scope.Scope) []machineCount {
+	counts := []machineCount{}
+	version := s.Current.Cluster.Spec.Topology.Version
+
+	// Control plane Machines are counted only if the control plane is machine based.
+	if s.Current.ControlPlane != nil && s.Current.ControlPlane.Object != nil && s.Blueprint.HasControlPlaneInfrastructureMachine() {
+		cp := s.Current.ControlPlane.Object
+		count := machineCount{kind: machinesKindControlPlane}
+		if replicas, err := contract.ControlPlane().StatusReplicas().Get(cp); err == nil {
+			count.total = *replicas
+		}
+		var updatedReplicas int64
+		if replicas, err := contract.ControlPlane().UpdatedReplicas().Get(cp); err == nil {
+			updatedReplicas = *replicas
+		}
+		count.outOfDate = count.total - updatedReplicas
+		if cpVersion, err := contract.ControlPlane().Version().Get(cp); err != nil || *cpVersion != version {
+			count.outOfDate = count.total
+		}
+		counts = append(counts, sanitizeMachineCount(count))
+	}
+
+	for name, md := range s.Current.MachineDeployments {
+		if md == nil || md.Object == nil {
+			continue
+		}
+		count := machineCount{
+			kind:      machinesKindMachineDeployment,
+			name:      name,
+			total:     int64(md.Object.Status.Replicas),
+			outOfDate: int64(md.Object.Status.Replicas - md.Object.Status.UpdatedReplicas),
+		}
+		if md.Object.Spec.Template.Spec.Version == nil || *md.Object.Spec.Template.Spec.Version != version {
+			count.outOfDate = count.total
+		}
+		counts = append(counts, sanitizeMachineCount(count))
+	}
+	return counts
+}
+
+// sanitizeMachineCount ensures the number of out of date Machines is not negative, which could happen
+// e.g. when status is not yet updated after a scale down.
+func sanitizeMachineCount(count machineCount) machineCount {
+	if count.outOfDate < 0 {
+		count.outOfDate = 0
+	}
+	return count
+}
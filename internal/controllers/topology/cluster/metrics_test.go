@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestComputeMachineCounts(t *testing.T) {
+	infrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra1").Build()
+	clusterClassWithMachines := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithControlPlaneInfrastructureMachineTemplate(infrastructureMachineTemplate).
+		Build()
+	clusterClassWithoutMachines := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+
+	controlPlane := func(version string, replicas, updatedReplicas int64) *unstructured.Unstructured {
+		return builder.ControlPlane(metav1.NamespaceDefault, "cp1").
+			WithVersion(version).
+			WithStatusFields(map[string]interface{}{
+				"status.replicas":        replicas,
+				"status.updatedReplicas": updatedReplicas,
+			}).
+			Build()
+	}
+	machineDeployment := func(version string, replicas, updatedReplicas int32) *scope.MachineDeploymentState {
+		return &scope.MachineDeploymentState{
+			Object: builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+				WithVersion(version).
+				WithStatus(clusterv1.MachineDeploymentStatus{
+					Replicas:        replicas,
+					UpdatedReplicas: updatedReplicas,
+				}).
+				Build(),
+		}
+	}
+
+	tests := []struct {
+		name               string
+		clusterClass       *clusterv1.ClusterClass
+		controlPlane       *unstructured.Unstructured
+		machineDeployments scope.MachineDeploymentsStateMap
+		want               []machineCount
+	}{
+		{
+			name:         "Machines are up to date",
+			clusterClass: clusterClassWithMachines,
+			controlPlane: controlPlane("v1.21.2", 3, 3),
+			machineDeployments: scope.MachineDeploymentsStateMap{
+				"md-topology1": machineDeployment("v1.21.2", 2, 2),
+			},
+			want: []machineCount{
+				{kind: machinesKindControlPlane, total: 3, outOfDate: 0},
+				{kind: machinesKindMachineDeployment, name: "md-topology1", total: 2, outOfDate: 0},
+			},
+		},
+		{
+			name:         "Machines not updated yet are out of date",
+			clusterClass: clusterClassWithMachines,
+			controlPlane: controlPlane("v1.21.2", 3, 1),
+			machineDeployments: scope.MachineDeploymentsStateMap{
+				"md-topology1": machineDeployment("v1.21.2", 4, 3),
+			},
+			want: []machineCount{
+				{kind: machinesKindControlPlane, total: 3, outOfDate: 2},
+				{kind: machinesKindMachineDeployment, name: "md-topology1", total: 4, outOfDate: 1},
+			},
+		},
+		{
+			name:         "All the Machines are out of date if the version is not yet upgraded",
+			clusterClass: clusterClassWithMachines,
+			controlPlane: controlPlane("v1.21.1", 3, 3),
+			machineDeployments: scope.MachineDeploymentsStateMap{
+				"md-topology1": machineDeployment("v1.21.1", 2, 2),
+			},
+			want: []machineCount{
+				{kind: machinesKindControlPlane, total: 3, outOfDate: 3},
+				{kind: machinesKindMachineDeployment, name: "md-topology1", total: 2, outOfDate: 2},
+			},
+		},
+		{
+			name:         "Out of date Machines are never negative",
+			clusterClass: clusterClassWithMachines,
+			controlPlane: controlPlane("v1.21.2", 2, 3),
+			want: []machineCount{
+				{kind: machinesKindControlPlane, total: 2, outOfDate: 0},
+			},
+		},
+		{
+			name:         "Control plane Machines are not counted if the control plane is not machine based",
+			clusterClass: clusterClassWithoutMachines,
+			controlPlane: controlPlane("v1.21.2", 3, 3),
+			want:         []machineCount{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.21.2").Build()).
+				Build()
+			s := scope.New(cluster)
+			s.Blueprint.ClusterClass = tt.clusterClass
+			s.Current.ControlPlane = &scope.ControlPlaneState{Object: tt.controlPlane}
+			s.Current.MachineDeployments = tt.machineDeployments
+
+			g.Expect(computeMachineCounts(s)).To(ConsistOf(tt.want))
+		})
+	}
+}
+
+func TestReportMachineMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	md := builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+		WithVersion("v1.21.1").
+		WithStatus(clusterv1.MachineDeploymentStatus{Replicas: 2, UpdatedReplicas: 2}).
+		Build()
+	cluster := builder.Cluster(metav1.NamespaceDefault, "metrics-cluster").
+		WithTopology(builder.ClusterTopology().WithClass("class1").WithVersion("v1.21.2").Build()).
+		Build()
+	s := scope.New(cluster)
+	s.Blueprint.ClusterClass = builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+	s.Current.MachineDeployments = scope.MachineDeploymentsStateMap{
+		"md-topology1": &scope.MachineDeploymentState{Object: md},
+	}
+
+	reportMachineMetrics(s)
+	g.Expect(testutil.ToFloat64(machinesTotal.WithLabelValues(metav1.NamespaceDefault, "metrics-cluster", "class1", machinesKindMachineDeployment, "md-topology1"))).To(Equal(float64(2)))
+	g.Expect(testutil.ToFloat64(machinesOutOfDate.WithLabelValues(metav1.NamespaceDefault, "metrics-cluster", "class1", machinesKindMachineDeployment, "md-topology1"))).To(Equal(float64(2)))
+
+	// Series for the previous ClusterClass are dropped when the class is changed.
+	cluster.Spec.Topology.Class = "class2"
+	reportMachineMetrics(s)
+	g.Expect(testutil.CollectAndCount(machinesTotal)).To(Equal(1))
+	g.Expect(testutil.ToFloat64(machinesTotal.WithLabelValues(metav1.NamespaceDefault, "metrics-cluster", "class2", machinesKindMachineDeployment, "md-topology1"))).To(Equal(float64(2)))
+
+	// Series are dropped when the Cluster is deleted.
+	deleteMachineMetrics(metav1.NamespaceDefault, "metrics-cluster")
+	g.Expect(testutil.CollectAndCount(machinesTotal)).To(Equal(0))
+	g.Expect(testutil.CollectAndCount(machinesOutOfDate)).To(Equal(0))
+}
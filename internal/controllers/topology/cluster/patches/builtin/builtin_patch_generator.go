@@ -0,0 +1,483 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package builtin implements the generator for the builtin patches, which inject cluster-wide settings
+// defined in Cluster topology variables into the kubeadm bootstrap configuration of all the machines and
+// into the InfrastructureClusterTemplate, and the bootstrap overrides defined for MachineDeployments into
+// their kubeadm bootstrap configuration.
+package builtin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/api"
+	patchvariables "sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/variables"
+)
+
+const (
+	// containerdProxyFile is the systemd drop-in used to configure the proxy for containerd.
+	containerdProxyFile = "/etc/systemd/system/containerd.service.d/http-proxy.conf"
+
+	// containerdCertsDir is the directory where containerd looks for registry host configurations,
+	// see https://github.com/containerd/containerd/blob/main/docs/hosts.md.
+	containerdCertsDir = "/etc/containerd/certs.d"
+)
+
+// kubeadmConfigSpecPaths are the paths of the KubeadmConfigSpec in the templates supported by the builtin patches.
+var kubeadmConfigSpecPaths = map[string][]string{
+	"controlplane.cluster.x-k8s.io/KubeadmControlPlaneTemplate": {"spec", "template", "spec", "kubeadmConfigSpec"},
+	"bootstrap.cluster.x-k8s.io/KubeadmConfigTemplate":          {"spec", "template", "spec"},
+}
+
+// Proxy is the value of the proxy variable.
+type Proxy struct {
+	// HTTPProxy is the proxy used for HTTP connections.
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the proxy used for HTTPS connections.
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is the list of hosts, domains and CIDRs which should be reached without using the proxy;
+	// localhost and the service and pod CIDRs of the Cluster are always added.
+	NoProxy []string `json:"noProxy,omitempty"`
+}
+
+// RegistryMirror is an item of the registryMirrors variable.
+type RegistryMirror struct {
+	// Registry is the registry to be mirrored, e.g. docker.io.
+	Registry string `json:"registry"`
+
+	// Endpoints are the URLs of the mirrors, in order of preference, e.g. https://mirror.example.com.
+	Endpoints []string `json:"endpoints"`
+}
+
+// file is a file in a KubeadmConfigSpec.
+type file struct {
+	Path        string `json:"path"`
+	Owner       string `json:"owner,omitempty"`
+	Permissions string `json:"permissions,omitempty"`
+	Content     string `json:"content"`
+}
+
+// builtinPatchGenerator generates JSON patches for a GeneratePatchesRequest based on the builtin patches
+// enabled in a ClusterClass.
+type builtinPatchGenerator struct {
+	patches []string
+
+	// infrastructureClusterTemplateCRDAnnotations are the annotations of the CustomResourceDefinition of the
+	// InfrastructureClusterTemplate, declaring the fields set by the controlPlaneLoadBalancer builtin patch.
+	infrastructureClusterTemplateCRDAnnotations map[string]string
+}
+
+// NewGenerator returns a new builtin Generator for the given builtin patches and the annotations of the
+// CustomResourceDefinition of the InfrastructureClusterTemplate.
+func NewGenerator(patches []string, infrastructureClusterTemplateCRDAnnotations map[string]string) api.Generator {
+	return &builtinPatchGenerator{
+		patches: patches,
+		infrastructureClusterTemplateCRDAnnotations: infrastructureClusterTemplateCRDAnnotations,
+	}
+}
+
+// Generate generates JSON patches for the KubeadmControlPlaneTemplate and the KubeadmConfigTemplates in the given
+// GeneratePatchesRequest, adding files and commands derived from the variables of the enabled builtin patches.
+// The settings of the control plane load balancer are applied to the InfrastructureClusterTemplate.
+// Builtin patches for variables which are not set in the Cluster topology are skipped.
+// The bootstrap overrides of MachineDeployments are always applied to the corresponding KubeadmConfigTemplates.
+func (g *builtinPatchGenerator) Generate(_ context.Context, _ client.Object, req *runtimehooksv1.GeneratePatchesRequest) (*runtimehooksv1.GeneratePatchesResponse, error) {
+	resp := &runtimehooksv1.GeneratePatchesResponse{}
+
+	variables := patchvariables.ToMap(req.Variables)
+	files, commands, err := g.computeFilesAndCommands(variables)
+	if err != nil {
+		return nil, err
+	}
+	loadBalancer, err := g.getControlPlaneLoadBalancer(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := []error{}
+	for i := range req.Items {
+		item := &req.Items[i]
+
+		if isInfrastructureClusterTemplate(item) {
+			if loadBalancer == nil {
+				continue
+			}
+			template := &unstructured.Unstructured{}
+			if err := template.UnmarshalJSON(item.Object.Raw); err != nil {
+				errs = append(errs, errors.Wrapf(err, "failed to unmarshal template for item with uid %q", item.UID))
+				continue
+			}
+			jsonPatches, err := generateLoadBalancerJSONPatches(template, g.infrastructureClusterTemplateCRDAnnotations, loadBalancer)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "failed to generate JSON patches for item with uid %q", item.UID))
+				continue
+			}
+			resp.Items = append(resp.Items, runtimehooksv1.GeneratePatchesResponseItem{
+				UID:       item.UID,
+				Patch:     jsonPatches,
+				PatchType: runtimehooksv1.JSONPatchType,
+			})
+			continue
+		}
+
+		overrides, err := getBootstrapOverrides(patchvariables.ToMap(item.Variables))
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to get bootstrap overrides for item with uid %q", item.UID))
+			continue
+		}
+		if len(files) == 0 && len(commands) == 0 && overrides == nil {
+			continue
+		}
+
+		template := &unstructured.Unstructured{}
+		if err := template.UnmarshalJSON(item.Object.Raw); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to unmarshal template for item with uid %q", item.UID))
+			continue
+		}
+		gvk := template.GroupVersionKind()
+		path, ok := kubeadmConfigSpecPaths[fmt.Sprintf("%s/%s", gvk.Group, gvk.Kind)]
+		if !ok {
+			continue
+		}
+
+		jsonPatches, err := generateJSONPatches(template, path, files, commands, overrides)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to generate JSON patches for item with uid %q", item.UID))
+			continue
+		}
+		resp.Items = append(resp.Items, runtimehooksv1.GeneratePatchesResponseItem{
+			UID:       item.UID,
+			Patch:     jsonPatches,
+			PatchType: runtimehooksv1.JSONPatchType,
+		})
+	}
+
+	if err := kerrors.NewAggregate(errs); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// computeFilesAndCommands computes the files and the commands to be added to the KubeadmConfigSpecs.
+func (g *builtinPatchGenerator) computeFilesAndCommands(variables map[string]apiextensionsv1.JSON) ([]file, []string, error) {
+	files := []file{}
+	commands := []string{}
+	for _, patch := range g.patches {
+		value, ok := variables[patch]
+		if !ok {
+			continue
+		}
+
+		switch patch {
+		case clusterv1.ClusterTopologyBuiltinPatchProxy:
+			proxy := &Proxy{}
+			if err := json.Unmarshal(value.Raw, proxy); err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to unmarshal variable %q", patch)
+			}
+			builtins, err := getBuiltins(variables)
+			if err != nil {
+				return nil, nil, err
+			}
+			files = append(files, proxyFile(proxy, builtins))
+			commands = append(commands, "systemctl daemon-reload", "systemctl restart containerd")
+		case clusterv1.ClusterTopologyBuiltinPatchRegistryMirrors:
+			mirrors := []RegistryMirror{}
+			if err := json.Unmarshal(value.Raw, &mirrors); err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to unmarshal variable %q", patch)
+			}
+			files = append(files, registryMirrorFiles(mirrors)...)
+		case clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer:
+			// NOTE: The control plane load balancer is configured in the InfrastructureClusterTemplate, see getControlPlaneLoadBalancer.
+		default:
+			return nil, nil, errors.Errorf("unknown builtin patch %q", patch)
+		}
+	}
+	return files, commands, nil
+}
+
+// getBuiltins returns the value of the builtin variable.
+func getBuiltins(variables map[string]apiextensionsv1.JSON) (*patchvariables.Builtins, error) {
+	builtins := &patchvariables.Builtins{}
+	value, ok := variables[patchvariables.BuiltinsName]
+	if !ok {
+		return builtins, nil
+	}
+	if err := json.Unmarshal(value.Raw, builtins); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal variable %q", patchvariables.BuiltinsName)
+	}
+	return builtins, nil
+}
+
+// getBootstrapOverrides returns the bootstrap overrides of the MachineDeployment from the builtin variable, if any.
+func getBootstrapOverrides(variables map[string]apiextensionsv1.JSON) (*clusterv1.MachineDeploymentBootstrapOverrides, error) {
+	builtins, err := getBuiltins(variables)
+	if err != nil {
+		return nil, err
+	}
+	if builtins.MachineDeployment == nil || builtins.MachineDeployment.Bootstrap == nil {
+		return nil, nil
+	}
+	return builtins.MachineDeployment.Bootstrap.Overrides, nil
+}
+
+// proxyFile returns the systemd drop-in configuring the proxy for containerd.
+func proxyFile(proxy *Proxy, builtins *patchvariables.Builtins) file {
+	noProxy := []string{"localhost", "127.0.0.1"}
+	if builtins.Cluster != nil && builtins.Cluster.Network != nil {
+		noProxy = append(noProxy, builtins.Cluster.Network.Services...)
+		noProxy = append(noProxy, builtins.Cluster.Network.Pods...)
+		if builtins.Cluster.Network.ServiceDomain != nil && *builtins.Cluster.Network.ServiceDomain != "" {
+			noProxy = append(noProxy, "."+*builtins.Cluster.Network.ServiceDomain)
+		}
+	}
+	noProxy = append(noProxy, proxy.NoProxy...)
+
+	content := &strings.Builder{}
+	content.WriteString("[Service]\n")
+	if proxy.HTTPProxy != "" {
+		fmt.Fprintf(content, "Environment=\"HTTP_PROXY=%s\"\n", proxy.HTTPProxy)
+	}
+	if proxy.HTTPSProxy != "" {
+		fmt.Fprintf(content, "Environment=\"HTTPS_PROXY=%s\"\n", proxy.HTTPSProxy)
+	}
+	fmt.Fprintf(content, "Environment=\"NO_PROXY=%s\"\n", strings.Join(uniqueStrings(noProxy), ","))
+
+	return file{
+		Path:        containerdProxyFile,
+		Owner:       "root:root",
+		Permissions: "0644",
+		Content:     content.String(),
+	}
+}
+
+// registryMirrorFiles returns the containerd host configurations for the registry mirrors.
+func registryMirrorFiles(mirrors []RegistryMirror) []file {
+	// Sort the mirrors to always generate files in the same order.
+	sort.SliceStable(mirrors, func(i, j int) bool {
+		return mirrors[i].Registry < mirrors[j].Registry
+	})
+
+	files := []file{}
+	for _, mirror := range mirrors {
+		server := "https://" + mirror.Registry
+		if mirror.Registry == "docker.io" {
+			server = "https://registry-1.docker.io"
+		}
+
+		content := &strings.Builder{}
+		fmt.Fprintf(content, "server = %q\n", server)
+		for _, endpoint := range mirror.Endpoints {
+			fmt.Fprintf(content, "\n[host.%q]\n  capabilities = [\"pull\", \"resolve\"]\n", endpoint)
+		}
+
+		files = append(files, file{
+			Path:        fmt.Sprintf("%s/%s/hosts.toml", containerdCertsDir, mirror.Registry),
+			Owner:       "root:root",
+			Permissions: "0644",
+			Content:     content.String(),
+		})
+	}
+	return files
+}
+
+// generateJSONPatches generates the JSON patches adding files and commands to the KubeadmConfigSpec at the given path
+// of a template. Files already defined in the template with the same path are replaced, while the commands are added
+// before the preKubeadmCommands defined in the template.
+// If not nil, the bootstrap overrides are applied to the node registration options of the join configuration.
+func generateJSONPatches(template *unstructured.Unstructured, path []string, files []file, commands []string, overrides *clusterv1.MachineDeploymentBootstrapOverrides) ([]byte, error) {
+	currentFiles, _, err := unstructured.NestedSlice(template.Object, append(path, "files")...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get files from template")
+	}
+	currentCommands, _, err := unstructured.NestedStringSlice(template.Object, append(path, "preKubeadmCommands")...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get preKubeadmCommands from template")
+	}
+
+	paths := map[string]bool{}
+	newFiles := []interface{}{}
+	for _, f := range files {
+		paths[f.Path] = true
+		newFiles = append(newFiles, f)
+	}
+	for _, f := range currentFiles {
+		if m, ok := f.(map[string]interface{}); ok && paths[fmt.Sprintf("%v", m["path"])] {
+			continue
+		}
+		newFiles = append(newFiles, f)
+	}
+
+	jsonPatches := []map[string]interface{}{}
+	// NOTE: The KubeadmConfigSpec is always set in KubeadmControlPlaneTemplates, while it could be empty in KubeadmConfigTemplates.
+	jsonPatches = appendAddIfMissing(jsonPatches, template, path)
+	if len(files) > 0 {
+		jsonPatches = append(jsonPatches, map[string]interface{}{
+			"op":    "add",
+			"path":  "/" + strings.Join(append(path, "files"), "/"),
+			"value": newFiles,
+		})
+	}
+	if len(commands) > 0 {
+		jsonPatches = append(jsonPatches, map[string]interface{}{
+			"op":    "add",
+			"path":  "/" + strings.Join(append(path, "preKubeadmCommands"), "/"),
+			"value": append(append([]string{}, commands...), currentCommands...),
+		})
+	}
+	if overrides != nil {
+		overridesPatches, err := generateBootstrapOverridesJSONPatches(template, path, overrides)
+		if err != nil {
+			return nil, err
+		}
+		jsonPatches = append(jsonPatches, overridesPatches...)
+	}
+
+	patch, err := json.Marshal(jsonPatches)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal JSON patches")
+	}
+	return patch, nil
+}
+
+// generateBootstrapOverridesJSONPatches generates the JSON patches applying the bootstrap overrides to the node
+// registration options of the join configuration in the KubeadmConfigSpec at the given path of a template.
+// Kubelet extra args and node labels are merged with the ones defined in the template, taking precedence in case of
+// conflicts, while taints with the same key and effect of the ones defined in the template replace them.
+func generateBootstrapOverridesJSONPatches(template *unstructured.Unstructured, path []string, overrides *clusterv1.MachineDeploymentBootstrapOverrides) ([]map[string]interface{}, error) {
+	nodeRegistrationPath := append(append([]string{}, path...), "joinConfiguration", "nodeRegistration")
+
+	jsonPatches := []map[string]interface{}{}
+	jsonPatches = appendAddIfMissing(jsonPatches, template, nodeRegistrationPath[:len(nodeRegistrationPath)-1])
+	jsonPatches = appendAddIfMissing(jsonPatches, template, nodeRegistrationPath)
+
+	if len(overrides.KubeletExtraArgs) > 0 || len(overrides.NodeLabels) > 0 {
+		kubeletExtraArgs, _, err := unstructured.NestedStringMap(template.Object, append(nodeRegistrationPath, "kubeletExtraArgs")...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get kubeletExtraArgs from template")
+		}
+		if kubeletExtraArgs == nil {
+			kubeletExtraArgs = map[string]string{}
+		}
+		for k, v := range overrides.KubeletExtraArgs {
+			kubeletExtraArgs[k] = v
+		}
+		if len(overrides.NodeLabels) > 0 {
+			kubeletExtraArgs["node-labels"] = mergeNodeLabels(kubeletExtraArgs["node-labels"], overrides.NodeLabels)
+		}
+		jsonPatches = append(jsonPatches, map[string]interface{}{
+			"op":    "add",
+			"path":  "/" + strings.Join(append(nodeRegistrationPath, "kubeletExtraArgs"), "/"),
+			"value": kubeletExtraArgs,
+		})
+	}
+
+	if len(overrides.Taints) > 0 {
+		currentTaints, _, err := unstructured.NestedSlice(template.Object, append(nodeRegistrationPath, "taints")...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get taints from template")
+		}
+		overridden := map[string]bool{}
+		for _, taint := range overrides.Taints {
+			overridden[fmt.Sprintf("%s:%s", taint.Key, taint.Effect)] = true
+		}
+		taints := []interface{}{}
+		for _, taint := range currentTaints {
+			if m, ok := taint.(map[string]interface{}); ok && overridden[fmt.Sprintf("%v:%v", m["key"], m["effect"])] {
+				continue
+			}
+			taints = append(taints, taint)
+		}
+		for _, taint := range overrides.Taints {
+			taints = append(taints, taint)
+		}
+		jsonPatches = append(jsonPatches, map[string]interface{}{
+			"op":    "add",
+			"path":  "/" + strings.Join(append(nodeRegistrationPath, "taints"), "/"),
+			"value": taints,
+		})
+	}
+	return jsonPatches, nil
+}
+
+// appendAddIfMissing appends a JSON patch adding an empty object at the given path of a template, if not already set.
+func appendAddIfMissing(jsonPatches []map[string]interface{}, template *unstructured.Unstructured, path []string) []map[string]interface{} {
+	if _, ok, _ := unstructured.NestedFieldNoCopy(template.Object, path...); ok {
+		return jsonPatches
+	}
+	return append(jsonPatches, map[string]interface{}{
+		"op":    "add",
+		"path":  "/" + strings.Join(path, "/"),
+		"value": map[string]interface{}{},
+	})
+}
+
+// mergeNodeLabels merges labels into the value of the node-labels kubelet argument, i.e. a comma separated list
+// of key=value pairs, sorting the result by key.
+func mergeNodeLabels(nodeLabels string, labels map[string]string) string {
+	merged := map[string]string{}
+	for _, label := range strings.Split(nodeLabels, ",") {
+		if label = strings.TrimSpace(label); label == "" {
+			continue
+		}
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) == 1 {
+			kv = append(kv, "")
+		}
+		merged[kv[0]] = kv[1]
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, merged[k]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// uniqueStrings returns the given strings without duplicates, preserving the order.
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
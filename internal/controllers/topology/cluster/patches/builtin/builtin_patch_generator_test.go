@@ -0,0 +1,246 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+)
+
+func TestGenerate(t *testing.T) {
+	kcpTemplate := []byte(`{"apiVersion":"controlplane.cluster.x-k8s.io/v1beta1","kind":"KubeadmControlPlaneTemplate",` +
+		`"spec":{"template":{"spec":{"kubeadmConfigSpec":{` +
+		`"files":[{"path":"/etc/foo","content":"foo"}],"preKubeadmCommands":["echo foo"]}}}}}`)
+	kubeadmConfigTemplate := []byte(`{"apiVersion":"bootstrap.cluster.x-k8s.io/v1beta1","kind":"KubeadmConfigTemplate",` +
+		`"spec":{"template":{"spec":{` +
+		`"files":[{"path":"/etc/systemd/system/containerd.service.d/http-proxy.conf","content":"old"}]}}}}`)
+	infrastructureTemplate := []byte(`{"apiVersion":"infrastructure.cluster.x-k8s.io/v1beta1","kind":"DockerMachineTemplate",` +
+		`"spec":{"template":{"spec":{}}}}`)
+
+	proxyVariable := runtimehooksv1.Variable{
+		Name:  clusterv1.ClusterTopologyBuiltinPatchProxy,
+		Value: apiextensionsv1.JSON{Raw: []byte(`{"httpProxy":"http://proxy:3128","httpsProxy":"http://proxy:3128","noProxy":["example.com"]}`)},
+	}
+	registryMirrorsVariable := runtimehooksv1.Variable{
+		Name:  clusterv1.ClusterTopologyBuiltinPatchRegistryMirrors,
+		Value: apiextensionsv1.JSON{Raw: []byte(`[{"registry":"docker.io","endpoints":["https://mirror.example.com"]}]`)},
+	}
+	builtinVariable := runtimehooksv1.Variable{
+		Name:  "builtin",
+		Value: apiextensionsv1.JSON{Raw: []byte(`{"cluster":{"network":{"services":["10.128.0.0/12"],"pods":["192.168.0.0/16"],"serviceDomain":"cluster.local"}}}`)},
+	}
+
+	wantProxyFile := map[string]interface{}{
+		"path":        "/etc/systemd/system/containerd.service.d/http-proxy.conf",
+		"owner":       "root:root",
+		"permissions": "0644",
+		"content": "[Service]\n" +
+			"Environment=\"HTTP_PROXY=http://proxy:3128\"\n" +
+			"Environment=\"HTTPS_PROXY=http://proxy:3128\"\n" +
+			"Environment=\"NO_PROXY=localhost,127.0.0.1,10.128.0.0/12,192.168.0.0/16,.cluster.local,example.com\"\n",
+	}
+	wantRegistryMirrorFile := map[string]interface{}{
+		"path":        "/etc/containerd/certs.d/docker.io/hosts.toml",
+		"owner":       "root:root",
+		"permissions": "0644",
+		"content": "server = \"https://registry-1.docker.io\"\n" +
+			"\n[host.\"https://mirror.example.com\"]\n  capabilities = [\"pull\", \"resolve\"]\n",
+	}
+
+	tests := []struct {
+		name        string
+		patches     []string
+		variables   []runtimehooksv1.Variable
+		wantKCP     map[string]interface{}
+		wantKubeadm map[string]interface{}
+	}{
+		{
+			name:      "Add proxy and registry mirrors settings",
+			patches:   []string{clusterv1.ClusterTopologyBuiltinPatchProxy, clusterv1.ClusterTopologyBuiltinPatchRegistryMirrors},
+			variables: []runtimehooksv1.Variable{builtinVariable, proxyVariable, registryMirrorsVariable},
+			wantKCP: map[string]interface{}{
+				"files": []interface{}{
+					wantProxyFile,
+					wantRegistryMirrorFile,
+					map[string]interface{}{"path": "/etc/foo", "content": "foo"},
+				},
+				"preKubeadmCommands": []interface{}{"systemctl daemon-reload", "systemctl restart containerd", "echo foo"},
+			},
+			wantKubeadm: map[string]interface{}{
+				"files":              []interface{}{wantProxyFile, wantRegistryMirrorFile},
+				"preKubeadmCommands": []interface{}{"systemctl daemon-reload", "systemctl restart containerd"},
+			},
+		},
+		{
+			name:      "Skip builtin patches if the variables are not set",
+			patches:   []string{clusterv1.ClusterTopologyBuiltinPatchProxy},
+			variables: []runtimehooksv1.Variable{builtinVariable, registryMirrorsVariable},
+		},
+		{
+			name:      "Skip variables if the builtin patches are not enabled",
+			patches:   []string{clusterv1.ClusterTopologyBuiltinPatchRegistryMirrors},
+			variables: []runtimehooksv1.Variable{builtinVariable, proxyVariable, registryMirrorsVariable},
+			wantKCP: map[string]interface{}{
+				"files": []interface{}{
+					wantRegistryMirrorFile,
+					map[string]interface{}{"path": "/etc/foo", "content": "foo"},
+				},
+				"preKubeadmCommands": []interface{}{"echo foo"},
+			},
+			wantKubeadm: map[string]interface{}{
+				"files": []interface{}{
+					wantRegistryMirrorFile,
+					map[string]interface{}{"path": "/etc/systemd/system/containerd.service.d/http-proxy.conf", "content": "old"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			req := &runtimehooksv1.GeneratePatchesRequest{
+				Variables: tt.variables,
+				Items: []runtimehooksv1.GeneratePatchesRequestItem{
+					{UID: "kcp", Object: runtime.RawExtension{Raw: kcpTemplate}},
+					{UID: "kubeadm", Object: runtime.RawExtension{Raw: kubeadmConfigTemplate}},
+					{UID: "infrastructure", Object: runtime.RawExtension{Raw: infrastructureTemplate}},
+				},
+			}
+
+			resp, err := NewGenerator(tt.patches, nil).Generate(context.Background(), nil, req)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			if tt.wantKCP == nil {
+				g.Expect(resp.Items).To(BeEmpty())
+				return
+			}
+			g.Expect(resp.Items).To(HaveLen(2))
+			for _, item := range resp.Items {
+				g.Expect(item.PatchType).To(Equal(runtimehooksv1.JSONPatchType))
+
+				raw, want, path := kcpTemplate, tt.wantKCP, []string{"spec", "template", "spec", "kubeadmConfigSpec"}
+				if item.UID == "kubeadm" {
+					raw, want, path = kubeadmConfigTemplate, tt.wantKubeadm, []string{"spec", "template", "spec"}
+				}
+				patch, err := jsonpatch.DecodePatch(item.Patch)
+				g.Expect(err).NotTo(HaveOccurred())
+				patched, err := patch.Apply(raw)
+				g.Expect(err).NotTo(HaveOccurred())
+
+				obj := &unstructured.Unstructured{}
+				g.Expect(obj.UnmarshalJSON(patched)).To(Succeed())
+				spec, _, err := unstructured.NestedMap(obj.Object, path...)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(spec).To(Equal(want))
+			}
+		})
+	}
+}
+
+func TestGenerateBootstrapOverrides(t *testing.T) {
+	kubeadmConfigTemplate := []byte(`{"apiVersion":"bootstrap.cluster.x-k8s.io/v1beta1","kind":"KubeadmConfigTemplate",` +
+		`"spec":{"template":{"spec":{"joinConfiguration":{"nodeRegistration":{` +
+		`"kubeletExtraArgs":{"max-pods":"110","node-labels":"tier=web,zone=a"},` +
+		`"taints":[{"key":"gpu","effect":"NoSchedule"},{"key":"dedicated","value":"web","effect":"NoSchedule"}]}}}}}}`)
+	emptyKubeadmConfigTemplate := []byte(`{"apiVersion":"bootstrap.cluster.x-k8s.io/v1beta1","kind":"KubeadmConfigTemplate",` +
+		`"spec":{"template":{}}}`)
+	overridesVariable := runtimehooksv1.Variable{
+		Name: "builtin",
+		Value: apiextensionsv1.JSON{Raw: []byte(`{"machineDeployment":{"bootstrap":{"overrides":{` +
+			`"kubeletExtraArgs":{"max-pods":"200"},"nodeLabels":{"tier":"gpu"},` +
+			`"taints":[{"key":"gpu","value":"true","effect":"NoSchedule"}]}}}}`)},
+	}
+
+	tests := []struct {
+		name     string
+		template []byte
+		want     map[string]interface{}
+	}{
+		{
+			name:     "Merge overrides with the node registration options of the template",
+			template: kubeadmConfigTemplate,
+			want: map[string]interface{}{
+				"kubeletExtraArgs": map[string]interface{}{"max-pods": "200", "node-labels": "tier=gpu,zone=a"},
+				"taints": []interface{}{
+					map[string]interface{}{"key": "dedicated", "value": "web", "effect": "NoSchedule"},
+					map[string]interface{}{"key": "gpu", "value": "true", "effect": "NoSchedule"},
+				},
+			},
+		},
+		{
+			name:     "Add overrides to templates without a KubeadmConfigSpec",
+			template: emptyKubeadmConfigTemplate,
+			want: map[string]interface{}{
+				"kubeletExtraArgs": map[string]interface{}{"max-pods": "200", "node-labels": "tier=gpu"},
+				"taints": []interface{}{
+					map[string]interface{}{"key": "gpu", "value": "true", "effect": "NoSchedule"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			req := &runtimehooksv1.GeneratePatchesRequest{
+				Items: []runtimehooksv1.GeneratePatchesRequestItem{
+					{UID: "kubeadm", Object: runtime.RawExtension{Raw: tt.template}, Variables: []runtimehooksv1.Variable{overridesVariable}},
+					{UID: "other-kubeadm", Object: runtime.RawExtension{Raw: tt.template}},
+				},
+			}
+
+			resp, err := NewGenerator(nil, nil).Generate(context.Background(), nil, req)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resp.Items).To(HaveLen(1))
+			g.Expect(resp.Items[0].UID).To(BeEquivalentTo("kubeadm"))
+
+			patch, err := jsonpatch.DecodePatch(resp.Items[0].Patch)
+			g.Expect(err).NotTo(HaveOccurred())
+			patched, err := patch.Apply(tt.template)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			obj := &unstructured.Unstructured{}
+			g.Expect(obj.UnmarshalJSON(patched)).To(Succeed())
+			nodeRegistration, _, err := unstructured.NestedMap(obj.Object, "spec", "template", "spec", "joinConfiguration", "nodeRegistration")
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(nodeRegistration).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGenerateFailsForInvalidVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	req := &runtimehooksv1.GeneratePatchesRequest{
+		Variables: []runtimehooksv1.Variable{{
+			Name:  clusterv1.ClusterTopologyBuiltinPatchRegistryMirrors,
+			Value: apiextensionsv1.JSON{Raw: []byte(`"docker.io"`)},
+		}},
+	}
+	_, err := NewGenerator([]string{clusterv1.ClusterTopologyBuiltinPatchRegistryMirrors}, nil).Generate(context.Background(), nil, req)
+	g.Expect(err).To(HaveOccurred())
+}
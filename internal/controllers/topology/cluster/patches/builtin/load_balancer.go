@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builtin
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/internal/contract"
+)
+
+const (
+	// LoadBalancerSchemeInternal is the scheme of load balancers reachable only from the network of the Cluster.
+	LoadBalancerSchemeInternal = "internal"
+
+	// LoadBalancerSchemeExternal is the scheme of load balancers reachable from outside the network of the Cluster.
+	LoadBalancerSchemeExternal = "external"
+)
+
+// ControlPlaneLoadBalancer is the value of the controlPlaneLoadBalancer variable.
+type ControlPlaneLoadBalancer struct {
+	// Scheme is the scheme of the control plane load balancer, internal or external.
+	Scheme string `json:"scheme,omitempty"`
+
+	// AllowedCIDRs are the CIDRs allowed to reach the control plane load balancer.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// loadBalancerFields are the fields of an InfrastructureClusterTemplate storing the settings of the control plane
+// load balancer, as declared by the annotations of the CustomResourceDefinition of the InfrastructureClusterTemplate.
+type loadBalancerFields struct {
+	// schemePath is the path of the scheme of the load balancer; nil if not supported. schemes maps the internal
+	// and external schemes to the values of the field.
+	schemePath contract.Path
+	schemes    map[string]string
+
+	// allowedCIDRsPath is the path of the list of CIDRs allowed to reach the load balancer; nil if not supported.
+	allowedCIDRsPath contract.Path
+}
+
+// getLoadBalancerFields returns the fields for the control plane load balancer declared by the annotations of the
+// CustomResourceDefinition of an InfrastructureClusterTemplate; an error is returned if no field is declared.
+func getLoadBalancerFields(kind string, crdAnnotations map[string]string) (*loadBalancerFields, error) {
+	fields := &loadBalancerFields{
+		schemes: map[string]string{
+			LoadBalancerSchemeInternal: LoadBalancerSchemeInternal,
+			LoadBalancerSchemeExternal: LoadBalancerSchemeExternal,
+		},
+	}
+
+	var err error
+	if fields.schemePath, err = parseLoadBalancerFieldPath(crdAnnotations, clusterv1.ControlPlaneLoadBalancerSchemePathAnnotation); err != nil {
+		return nil, err
+	}
+	if fields.allowedCIDRsPath, err = parseLoadBalancerFieldPath(crdAnnotations, clusterv1.ControlPlaneLoadBalancerAllowedCIDRsPathAnnotation); err != nil {
+		return nil, err
+	}
+	if fields.schemePath == nil && fields.allowedCIDRsPath == nil {
+		return nil, errors.Errorf("%s does not support the %q builtin patch: its CustomResourceDefinition must declare the fields "+
+			"of the control plane load balancer with the %s and %s annotations", kind, clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer,
+			clusterv1.ControlPlaneLoadBalancerSchemePathAnnotation, clusterv1.ControlPlaneLoadBalancerAllowedCIDRsPathAnnotation)
+	}
+
+	if value, ok := crdAnnotations[clusterv1.ControlPlaneLoadBalancerSchemesAnnotation]; ok {
+		for _, mapping := range strings.Split(value, ",") {
+			scheme, schemeValue, ok := strings.Cut(strings.TrimSpace(mapping), "=")
+			if !ok || schemeValue == "" || (scheme != LoadBalancerSchemeInternal && scheme != LoadBalancerSchemeExternal) {
+				return nil, errors.Errorf("invalid value %q of the %s annotation: must be a comma separated list of %s=<value> or %s=<value>",
+					value, clusterv1.ControlPlaneLoadBalancerSchemesAnnotation, LoadBalancerSchemeInternal, LoadBalancerSchemeExternal)
+			}
+			fields.schemes[scheme] = schemeValue
+		}
+	}
+	return fields, nil
+}
+
+// parseLoadBalancerFieldPath returns the path of a field for the control plane load balancer declared by an annotation
+// of the CustomResourceDefinition of an InfrastructureClusterTemplate, if any.
+func parseLoadBalancerFieldPath(crdAnnotations map[string]string, annotation string) (contract.Path, error) {
+	value, ok := crdAnnotations[annotation]
+	if !ok {
+		return nil, nil
+	}
+	paths, err := contract.ParsePaths(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value of the %s annotation", annotation)
+	}
+	if len(paths) != 1 {
+		return nil, errors.Errorf("invalid value %q of the %s annotation: must be a single path", value, annotation)
+	}
+	return paths[0], nil
+}
+
+// getControlPlaneLoadBalancer returns the value of the controlPlaneLoadBalancer variable, if the builtin patch
+// is enabled and the variable is set.
+func (g *builtinPatchGenerator) getControlPlaneLoadBalancer(variables map[string]apiextensionsv1.JSON) (*ControlPlaneLoadBalancer, error) {
+	enabled := false
+	for _, patch := range g.patches {
+		if patch == clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer {
+			enabled = true
+		}
+	}
+	value, ok := variables[clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer]
+	if !enabled || !ok {
+		return nil, nil
+	}
+
+	loadBalancer := &ControlPlaneLoadBalancer{}
+	if err := json.Unmarshal(value.Raw, loadBalancer); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal variable %q", clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer)
+	}
+	if loadBalancer.Scheme != "" && loadBalancer.Scheme != LoadBalancerSchemeInternal && loadBalancer.Scheme != LoadBalancerSchemeExternal {
+		return nil, errors.Errorf("invalid scheme %q in variable %q: must be %s or %s",
+			loadBalancer.Scheme, clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer, LoadBalancerSchemeInternal, LoadBalancerSchemeExternal)
+	}
+	for _, cidr := range loadBalancer.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return nil, errors.Wrapf(err, "invalid CIDR %q in variable %q", cidr, clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer)
+		}
+	}
+	return loadBalancer, nil
+}
+
+// isInfrastructureClusterTemplate returns true if the item of a GeneratePatchesRequest is the InfrastructureClusterTemplate.
+func isInfrastructureClusterTemplate(item *runtimehooksv1.GeneratePatchesRequestItem) bool {
+	return item.HolderReference.Kind == "Cluster" && item.HolderReference.FieldPath == "spec.infrastructureRef"
+}
+
+// generateLoadBalancerJSONPatches generates the JSON patches setting the control plane load balancer fields of an
+// InfrastructureClusterTemplate; an error is returned if a setting is not supported by the InfrastructureClusterTemplate,
+// as declared by the annotations of its CustomResourceDefinition.
+func generateLoadBalancerJSONPatches(template *unstructured.Unstructured, crdAnnotations map[string]string, loadBalancer *ControlPlaneLoadBalancer) ([]byte, error) {
+	fields, err := getLoadBalancerFields(template.GetKind(), crdAnnotations)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonPatches := []map[string]interface{}{}
+	added := map[string]bool{}
+	addField := func(path []string, value interface{}) {
+		// NOTE: The parents of the field must exist for the add operation to succeed; parents added for
+		// a previous field must not be added again, because this would drop the previous field.
+		for i := 1; i < len(path); i++ {
+			parent := strings.Join(path[:i], "/")
+			if added[parent] {
+				continue
+			}
+			added[parent] = true
+			jsonPatches = appendAddIfMissing(jsonPatches, template, path[:i])
+		}
+		jsonPatches = append(jsonPatches, map[string]interface{}{
+			"op":    "add",
+			"path":  "/" + strings.Join(path, "/"),
+			"value": value,
+		})
+	}
+
+	if loadBalancer.Scheme != "" {
+		if fields.schemePath == nil {
+			return nil, errors.Errorf("scheme in variable %q is not supported for %s",
+				clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer, template.GetKind())
+		}
+		addField(fields.schemePath, fields.schemes[loadBalancer.Scheme])
+	}
+	if len(loadBalancer.AllowedCIDRs) > 0 {
+		if fields.allowedCIDRsPath == nil {
+			return nil, errors.Errorf("allowedCIDRs in variable %q are not supported for %s",
+				clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer, template.GetKind())
+		}
+		addField(fields.allowedCIDRsPath, loadBalancer.AllowedCIDRs)
+	}
+
+	patch, err := json.Marshal(jsonPatches)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal JSON patches")
+	}
+	return patch, nil
+}
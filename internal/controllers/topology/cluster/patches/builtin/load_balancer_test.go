@@ -0,0 +1,189 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
+)
+
+func TestGenerateControlPlaneLoadBalancer(t *testing.T) {
+	dockerClusterTemplate := []byte(`{"apiVersion":"infrastructure.cluster.x-k8s.io/v1beta1","kind":"DockerClusterTemplate",` +
+		`"spec":{"template":{"spec":{}}}}`)
+	awsClusterTemplate := []byte(`{"apiVersion":"infrastructure.cluster.x-k8s.io/v1beta2","kind":"AWSClusterTemplate",` +
+		`"spec":{"template":{"spec":{"region":"us-east-1"}}}}`)
+	azureClusterTemplate := []byte(`{"apiVersion":"infrastructure.cluster.x-k8s.io/v1beta1","kind":"AzureClusterTemplate",` +
+		`"spec":{"template":{"spec":{"networkSpec":{"vnet":{"name":"vnet"}}}}}}`)
+
+	dockerCRDAnnotations := map[string]string{
+		clusterv1.ControlPlaneLoadBalancerSchemePathAnnotation:       "spec.template.spec.controlPlaneLoadBalancer.scheme",
+		clusterv1.ControlPlaneLoadBalancerAllowedCIDRsPathAnnotation: "spec.template.spec.controlPlaneLoadBalancer.allowedCIDRs",
+	}
+	awsCRDAnnotations := map[string]string{
+		clusterv1.ControlPlaneLoadBalancerSchemePathAnnotation: "spec.template.spec.controlPlaneLoadBalancer.scheme",
+		clusterv1.ControlPlaneLoadBalancerSchemesAnnotation:    "external=internet-facing",
+	}
+	azureCRDAnnotations := map[string]string{
+		clusterv1.ControlPlaneLoadBalancerSchemePathAnnotation: "spec.template.spec.networkSpec.apiServerLB.type",
+		clusterv1.ControlPlaneLoadBalancerSchemesAnnotation:    "internal=Internal, external=Public",
+	}
+
+	tests := []struct {
+		name           string
+		patches        []string
+		template       []byte
+		crdAnnotations map[string]string
+		variable       string
+		wantPath       []string
+		wantValue      interface{}
+		wantErr        bool
+	}{
+		{
+			name:           "Set the scheme and the allowed CIDRs",
+			patches:        []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template:       dockerClusterTemplate,
+			crdAnnotations: dockerCRDAnnotations,
+			variable:       `{"scheme":"internal","allowedCIDRs":["10.0.0.0/8"]}`,
+			wantPath:       []string{"spec", "template", "spec", "controlPlaneLoadBalancer"},
+			wantValue:      map[string]interface{}{"scheme": "internal", "allowedCIDRs": []interface{}{"10.0.0.0/8"}},
+		},
+		{
+			name:           "Set the scheme with the value declared for the external scheme",
+			patches:        []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template:       awsClusterTemplate,
+			crdAnnotations: awsCRDAnnotations,
+			variable:       `{"scheme":"external"}`,
+			wantPath:       []string{"spec", "template", "spec", "controlPlaneLoadBalancer", "scheme"},
+			wantValue:      "internet-facing",
+		},
+		{
+			name:           "Set the scheme with the default value for schemes not declared",
+			patches:        []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template:       awsClusterTemplate,
+			crdAnnotations: awsCRDAnnotations,
+			variable:       `{"scheme":"internal"}`,
+			wantPath:       []string{"spec", "template", "spec", "controlPlaneLoadBalancer", "scheme"},
+			wantValue:      "internal",
+		},
+		{
+			name:           "Set the scheme at the declared path",
+			patches:        []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template:       azureClusterTemplate,
+			crdAnnotations: azureCRDAnnotations,
+			variable:       `{"scheme":"internal"}`,
+			wantPath:       []string{"spec", "template", "spec", "networkSpec", "apiServerLB", "type"},
+			wantValue:      "Internal",
+		},
+		{
+			name:           "Fail for an invalid scheme",
+			patches:        []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template:       dockerClusterTemplate,
+			crdAnnotations: dockerCRDAnnotations,
+			variable:       `{"scheme":"private"}`,
+			wantErr:        true,
+		},
+		{
+			name:           "Fail for an invalid CIDR",
+			patches:        []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template:       dockerClusterTemplate,
+			crdAnnotations: dockerCRDAnnotations,
+			variable:       `{"allowedCIDRs":["10.0.0.0"]}`,
+			wantErr:        true,
+		},
+		{
+			name:           "Fail for allowed CIDRs not declared by the CustomResourceDefinition",
+			patches:        []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template:       azureClusterTemplate,
+			crdAnnotations: azureCRDAnnotations,
+			variable:       `{"scheme":"internal","allowedCIDRs":["10.0.0.0/8"]}`,
+			wantErr:        true,
+		},
+		{
+			name:     "Fail for InfrastructureClusterTemplates whose CustomResourceDefinition does not declare any field",
+			patches:  []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template: dockerClusterTemplate,
+			variable: `{"scheme":"internal"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "Fail for invalid schemes declared by the CustomResourceDefinition",
+			patches:  []string{clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer},
+			template: awsClusterTemplate,
+			crdAnnotations: map[string]string{
+				clusterv1.ControlPlaneLoadBalancerSchemePathAnnotation: "spec.template.spec.controlPlaneLoadBalancer.scheme",
+				clusterv1.ControlPlaneLoadBalancerSchemesAnnotation:    "private=internal",
+			},
+			variable: `{"scheme":"internal"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "Skip if the builtin patch is not enabled",
+			template: dockerClusterTemplate,
+			variable: `{"scheme":"internal"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			req := &runtimehooksv1.GeneratePatchesRequest{
+				Variables: []runtimehooksv1.Variable{{
+					Name:  clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer,
+					Value: apiextensionsv1.JSON{Raw: []byte(tt.variable)},
+				}},
+				Items: []runtimehooksv1.GeneratePatchesRequestItem{{
+					UID:             "infrastructure-cluster",
+					HolderReference: runtimehooksv1.HolderReference{Kind: "Cluster", FieldPath: "spec.infrastructureRef"},
+					Object:          runtime.RawExtension{Raw: tt.template},
+				}},
+			}
+
+			resp, err := NewGenerator(tt.patches, tt.crdAnnotations).Generate(context.Background(), nil, req)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			if tt.wantPath == nil {
+				g.Expect(resp.Items).To(BeEmpty())
+				return
+			}
+			g.Expect(resp.Items).To(HaveLen(1))
+
+			patch, err := jsonpatch.DecodePatch(resp.Items[0].Patch)
+			g.Expect(err).NotTo(HaveOccurred())
+			patched, err := patch.Apply(tt.template)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			obj := &unstructured.Unstructured{}
+			g.Expect(obj.UnmarshalJSON(patched)).To(Succeed())
+			value, ok, err := unstructured.NestedFieldCopy(obj.Object, tt.wantPath...)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(ok).To(BeTrue())
+			g.Expect(value).To(Equal(tt.wantValue))
+		})
+	}
+}
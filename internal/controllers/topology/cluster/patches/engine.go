@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/api"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/builtin"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/external"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/inline"
 	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/patches/variables"
@@ -61,8 +62,11 @@ type engine struct {
 //     and successively applied to the templates in the GeneratePatchesRequest.
 //   - Eventually the patched templates are used to update the specs of the desired objects.
 func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) error {
+	builtinPatches := blueprint.BuiltinPatches()
+	hasBootstrapOverrides := blueprint.HasMachineDeploymentBootstrapOverrides()
+
 	// Return if there are no patches.
-	if len(blueprint.ClusterClass.Spec.Patches) == 0 {
+	if len(blueprint.ClusterClass.Spec.Patches) == 0 && len(builtinPatches) == 0 && !hasBootstrapOverrides {
 		return nil
 	}
 
@@ -74,6 +78,20 @@ func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, d
 		return errors.Wrapf(err, "failed to generate patch request")
 	}
 
+	// Apply the builtin patches and the MachineDeployments bootstrap overrides first, so the patches in ClusterClass
+	// can further modify the templates.
+	if len(builtinPatches) > 0 || hasBootstrapOverrides {
+		log.V(5).Infof("Applying builtin patches %s to templates", strings.Join(builtinPatches, ","))
+
+		resp, err := builtin.NewGenerator(builtinPatches, blueprint.InfrastructureClusterTemplateCRDAnnotations).Generate(ctx, desired.Cluster, req)
+		if err != nil {
+			return errors.Wrapf(err, "failed to generate builtin patches")
+		}
+		if err := applyPatchesToRequest(ctx, req, resp); err != nil {
+			return err
+		}
+	}
+
 	// Loop over patches in ClusterClass, generate patches and apply them to the request,
 	// respecting the order in which they are defined.
 	for i := range blueprint.ClusterClass.Spec.Patches {
@@ -139,9 +157,9 @@ func (e *engine) Apply(ctx context.Context, blueprint *scope.ClusterBlueprint, d
 // createRequest creates a GeneratePatchesRequest based on the ClusterBlueprint and the desired state.
 // ClusterBlueprint supplies the templates. Desired state is used to calculate variables which are later used
 // as input for the patch generation.
-// NOTE: GenerateRequestTemplates are created for the templates of each individual MachineDeployment in the desired
-// state. This is necessary because some builtin variables are MachineDeployment specific. For example version and
-// replicas of a MachineDeployment.
+// NOTE: GenerateRequestTemplates are created for the templates of each individual MachineDeployment and MachinePool
+// in the desired state. This is necessary because some builtin variables are MachineDeployment or MachinePool specific.
+// For example version and replicas of a MachineDeployment.
 // NOTE: A single GeneratePatchesRequest object is used to carry templates state across subsequent Generate calls.
 func createRequest(blueprint *scope.ClusterBlueprint, desired *scope.ClusterState) (*runtimehooksv1.GeneratePatchesRequest, error) {
 	req := &runtimehooksv1.GeneratePatchesRequest{}
@@ -242,9 +260,65 @@ func createRequest(blueprint *scope.ClusterBlueprint, desired *scope.ClusterStat
 		req.Items = append(req.Items, *t)
 	}
 
+	// Add BootstrapConfigTemplate and InfrastructureMachinePoolTemplate for all MachinePoolTopologies
+	// in the Cluster.
+	// NOTE: As for MachineDeployments, we iterate over the MachinePools in the Cluster because each MachinePool
+	// has its own state used to calculate builtin variables.
+	for mpTopologyName, mp := range desired.MachinePools {
+		// Lookup MachinePoolTopology definition from cluster.spec.topology.
+		mpTopology, err := lookupMPTopology(blueprint.Topology, mpTopologyName)
+		if err != nil {
+			return nil, err
+		}
+
+		// Get corresponding MachinePoolClass from the ClusterClass.
+		mpClass, ok := blueprint.MachinePools[mpTopology.Class]
+		if !ok {
+			return nil, errors.Errorf("failed to lookup MachinePool class %q in ClusterClass", mpTopology.Class)
+		}
+
+		// Calculate MachinePool variables.
+		mpVariables, err := variables.MachinePool(mpTopology, mp.Object, mp.BootstrapObject, mp.InfrastructureMachinePoolObject)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to calculate variables for %s", tlog.KObj{Obj: mp.Object})
+		}
+
+		// Add the BootstrapTemplate.
+		t, err := newRequestItemBuilder(mpClass.BootstrapTemplate).
+			WithHolder(mp.Object, "spec.template.spec.bootstrap.configRef").
+			Build()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to prepare BootstrapConfig template %s for MachinePool topology %s for patching",
+				tlog.KObj{Obj: mpClass.BootstrapTemplate}, mpTopologyName)
+		}
+		t.Variables = mpVariables
+		req.Items = append(req.Items, *t)
+
+		// Add the InfrastructureMachinePoolTemplate.
+		t, err = newRequestItemBuilder(mpClass.InfrastructureMachinePoolTemplate).
+			WithHolder(mp.Object, "spec.template.spec.infrastructureRef").
+			Build()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to prepare InfrastructureMachinePool template %s for MachinePool topology %s for patching",
+				tlog.KObj{Obj: mpClass.InfrastructureMachinePoolTemplate}, mpTopologyName)
+		}
+		t.Variables = mpVariables
+		req.Items = append(req.Items, *t)
+	}
+
 	return req, nil
 }
 
+// lookupMPTopology looks up the MachinePoolTopology based on a mpTopologyName in a topology.
+func lookupMPTopology(topology *clusterv1.Topology, mpTopologyName string) (*clusterv1.MachinePoolTopology, error) {
+	for _, mpTopology := range topology.Workers.MachinePools {
+		if mpTopology.Name == mpTopologyName {
+			return &mpTopology, nil
+		}
+	}
+	return nil, errors.Errorf("failed to lookup MachinePool topology %q in Cluster.spec.topology.workers.machinePools", mpTopologyName)
+}
+
 // lookupMDTopology looks up the MachineDeploymentTopology based on a mdTopologyName in a topology.
 func lookupMDTopology(topology *clusterv1.Topology, mdTopologyName string) (*clusterv1.MachineDeploymentTopology, error) {
 	for _, mdTopology := range topology.Workers.MachineDeployments {
@@ -371,6 +445,7 @@ func updateDesiredState(ctx context.Context, req *runtimehooksv1.GeneratePatches
 	if err := patchObject(ctx, desired.ControlPlane.Object, controlPlaneTemplate, PreserveFields{
 		contract.ControlPlane().MachineTemplate().Metadata().Path(),
 		contract.ControlPlane().MachineTemplate().InfrastructureRef().Path(),
+		contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Path(),
 		contract.ControlPlane().MachineTemplate().NodeDrainTimeout().Path(),
 		contract.ControlPlane().MachineTemplate().NodeVolumeDetachTimeout().Path(),
 		contract.ControlPlane().MachineTemplate().NodeDeletionTimeout().Path(),
@@ -413,5 +488,28 @@ func updateDesiredState(ctx context.Context, req *runtimehooksv1.GeneratePatches
 		}
 	}
 
+	// Update the objects for all MachinePools.
+	// NOTE: MachinePools reference objects cloned from the templates, so the spec of the patched templates
+	// is applied to the spec of the objects.
+	for mpTopologyName, mp := range desired.MachinePools {
+		// Update the BootstrapConfig.
+		bootstrapTemplate, err := getTemplateAsUnstructured(req, "MachinePool", "spec.template.spec.bootstrap.configRef", mpTopologyName)
+		if err != nil {
+			return err
+		}
+		if err := patchObject(ctx, mp.BootstrapObject, bootstrapTemplate); err != nil {
+			return err
+		}
+
+		// Update the InfrastructureMachinePool.
+		infrastructureMachinePoolTemplate, err := getTemplateAsUnstructured(req, "MachinePool", "spec.template.spec.infrastructureRef", mpTopologyName)
+		if err != nil {
+			return err
+		}
+		if err := patchObject(ctx, mp.InfrastructureMachinePoolObject, infrastructureMachinePoolTemplate); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
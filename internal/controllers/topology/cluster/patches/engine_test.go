@@ -49,6 +49,8 @@ func TestApply(t *testing.T) {
 		controlPlaneInfrastructureMachineTemplate      map[string]interface{}
 		machineDeploymentBootstrapTemplate             map[string]map[string]interface{}
 		machineDeploymentInfrastructureMachineTemplate map[string]map[string]interface{}
+		machinePoolBootstrapObject                     map[string]map[string]interface{}
+		machinePoolInfrastructureMachinePoolObject     map[string]map[string]interface{}
 	}
 
 	tests := []struct {
@@ -188,6 +190,60 @@ func TestApply(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Should apply JSON patches to MachinePool objects",
+			patches: []clusterv1.ClusterClassPatch{
+				{
+					Name: "fake-patch1",
+					Definitions: []clusterv1.PatchDefinition{
+						{
+							Selector: clusterv1.PatchSelector{
+								APIVersion: builder.InfrastructureGroupVersion.String(),
+								Kind:       builder.GenericInfrastructureMachineTemplateKind,
+								MatchResources: clusterv1.PatchSelectorMatch{
+									MachinePoolClass: &clusterv1.PatchSelectorMatchMachinePoolClass{
+										Names: []string{"default-mp-worker"},
+									},
+								},
+							},
+							JSONPatches: []clusterv1.JSONPatch{
+								{
+									Op:    "add",
+									Path:  "/spec/template/spec/resource",
+									Value: &apiextensionsv1.JSON{Raw: []byte(`"default-mp-worker-infra"`)},
+								},
+							},
+						},
+						{
+							Selector: clusterv1.PatchSelector{
+								APIVersion: builder.BootstrapGroupVersion.String(),
+								Kind:       builder.GenericBootstrapConfigTemplateKind,
+								MatchResources: clusterv1.PatchSelectorMatch{
+									MachinePoolClass: &clusterv1.PatchSelectorMatchMachinePoolClass{
+										Names: []string{"default-mp-worker"},
+									},
+								},
+							},
+							JSONPatches: []clusterv1.JSONPatch{
+								{
+									Op:        "add",
+									Path:      "/spec/template/spec/resource",
+									ValueFrom: &clusterv1.JSONPatchValue{Variable: pointer.String("builtin.machinePool.name")},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedFields: expectedFields{
+				machinePoolBootstrapObject: map[string]map[string]interface{}{
+					"default-mp-worker-topo1": {"spec.resource": "mp1"},
+				},
+				machinePoolInfrastructureMachinePoolObject: map[string]map[string]interface{}{
+					"default-mp-worker-topo1": {"spec.resource": "default-mp-worker-infra"},
+				},
+			},
+		},
 		{
 			name: "Should apply JSON patches in the correct order",
 			patches: []clusterv1.ClusterClassPatch{
@@ -489,10 +545,12 @@ func TestApply(t *testing.T) {
 			//   * A ClusterClass with its corresponding templates:
 			//     * ControlPlaneTemplate with a corresponding ControlPlane InfrastructureMachineTemplate.
 			//     * MachineDeploymentClass "default-worker" with corresponding BootstrapTemplate and InfrastructureMachineTemplate.
+			//     * MachinePoolClass "default-mp-worker" with corresponding BootstrapTemplate and InfrastructureMachineTemplate.
 			//   * The corresponding Cluster.spec.topology:
 			//     * with 3 ControlPlane replicas
 			//     * with a "default-worker-topo1" MachineDeploymentTopology without replicas (based on "default-worker")
 			//     * with a "default-worker-topo2" MachineDeploymentTopology with 3 replicas (based on "default-worker")
+			//     * with a "default-mp-worker-topo1" MachinePoolTopology with 2 replicas (based on "default-mp-worker")
 			// * desired: essentially the corresponding desired objects.
 			blueprint, desired := setupTestObjects()
 
@@ -533,6 +591,12 @@ func TestApply(t *testing.T) {
 				expectedBootstrapTemplates[mdTopology] = md.BootstrapTemplate.DeepCopy()
 				expectedInfrastructureMachineTemplate[mdTopology] = md.InfrastructureMachineTemplate.DeepCopy()
 			}
+			expectedBootstrapObjects := map[string]*unstructured.Unstructured{}
+			expectedInfrastructureMachinePoolObjects := map[string]*unstructured.Unstructured{}
+			for mpTopology, mp := range desired.MachinePools {
+				expectedBootstrapObjects[mpTopology] = mp.BootstrapObject.DeepCopy()
+				expectedInfrastructureMachinePoolObjects[mpTopology] = mp.InfrastructureMachinePoolObject.DeepCopy()
+			}
 
 			// Set expected fields on the copy of the objects, so they can be used for comparison with the result of Apply.
 			if tt.expectedFields.infrastructureCluster != nil {
@@ -550,6 +614,12 @@ func TestApply(t *testing.T) {
 			for mdTopology, expectedFields := range tt.expectedFields.machineDeploymentInfrastructureMachineTemplate {
 				setSpecFields(expectedInfrastructureMachineTemplate[mdTopology], expectedFields)
 			}
+			for mpTopology, expectedFields := range tt.expectedFields.machinePoolBootstrapObject {
+				setSpecFields(expectedBootstrapObjects[mpTopology], expectedFields)
+			}
+			for mpTopology, expectedFields := range tt.expectedFields.machinePoolInfrastructureMachinePoolObject {
+				setSpecFields(expectedInfrastructureMachinePoolObjects[mpTopology], expectedFields)
+			}
 
 			// Apply patches.
 			if err := patchEngine.Apply(context.Background(), blueprint, desired); err != nil {
@@ -570,6 +640,12 @@ func TestApply(t *testing.T) {
 			for mdTopology, infrastructureMachineTemplate := range expectedInfrastructureMachineTemplate {
 				g.Expect(desired.MachineDeployments[mdTopology].InfrastructureMachineTemplate).To(EqualObject(infrastructureMachineTemplate))
 			}
+			for mpTopology, bootstrapObject := range expectedBootstrapObjects {
+				g.Expect(desired.MachinePools[mpTopology].BootstrapObject).To(EqualObject(bootstrapObject))
+			}
+			for mpTopology, infrastructureMachinePoolObject := range expectedInfrastructureMachinePoolObjects {
+				g.Expect(desired.MachinePools[mpTopology].InfrastructureMachinePoolObject).To(EqualObject(infrastructureMachinePoolObject))
+			}
 		})
 	}
 }
@@ -588,6 +664,10 @@ func setupTestObjects() (*scope.ClusterBlueprint, *scope.ClusterState) {
 		Build()
 	workerBootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "linux-worker-bootstraptemplate").
 		Build()
+	mpWorkerInfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "linux-mp-worker-inframachinetemplate").
+		Build()
+	mpWorkerBootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "linux-mp-worker-bootstraptemplate").
+		Build()
 	mdClass1 := builder.MachineDeploymentClass("default-worker").
 		WithInfrastructureTemplate(workerInfrastructureMachineTemplate).
 		WithBootstrapTemplate(workerBootstrapTemplate).
@@ -645,6 +725,13 @@ func setupTestObjects() (*scope.ClusterBlueprint, *scope.ClusterState) {
 							Replicas: pointer.Int32(5),
 						},
 					},
+					MachinePools: []clusterv1.MachinePoolTopology{
+						{
+							Class:    "default-mp-worker",
+							Name:     "default-mp-worker-topo1",
+							Replicas: pointer.Int32(2),
+						},
+					},
 				},
 			},
 		},
@@ -665,6 +752,12 @@ func setupTestObjects() (*scope.ClusterBlueprint, *scope.ClusterState) {
 				BootstrapTemplate:             workerBootstrapTemplate,
 			},
 		},
+		MachinePools: map[string]*scope.MachinePoolBlueprint{
+			"default-mp-worker": {
+				InfrastructureMachinePoolTemplate: mpWorkerInfrastructureMachineTemplate,
+				BootstrapTemplate:                 mpWorkerBootstrapTemplate,
+			},
+		},
 	}
 
 	// Create a Cluster using the ClusterClass from above with multiple MachineDeployments
@@ -713,6 +806,27 @@ func setupTestObjects() (*scope.ClusterBlueprint, *scope.ClusterState) {
 				BootstrapTemplate:             workerBootstrapTemplate.DeepCopy(),
 			},
 		},
+		MachinePools: map[string]*scope.MachinePoolState{
+			"default-mp-worker-topo1": {
+				Object: builder.MachinePool(metav1.NamespaceDefault, "mp1").
+					WithVersion("v1.21.2").
+					WithReplicas(2).
+					Build(),
+				// The objects referenced by a MachinePool are cloned from the templates.
+				BootstrapObject: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": builder.BootstrapGroupVersion.String(),
+					"kind":       builder.GenericBootstrapConfigKind,
+					"metadata":   map[string]interface{}{"name": "mp1-bootstrap", "namespace": metav1.NamespaceDefault},
+					"spec":       map[string]interface{}{},
+				}},
+				InfrastructureMachinePoolObject: &unstructured.Unstructured{Object: map[string]interface{}{
+					"apiVersion": builder.InfrastructureGroupVersion.String(),
+					"kind":       builder.GenericInfrastructureMachineKind,
+					"metadata":   map[string]interface{}{"name": "mp1-infra", "namespace": metav1.NamespaceDefault},
+					"spec":       map[string]interface{}{},
+				}},
+			},
+		},
 	}
 	return blueprint, desired
 }
@@ -85,13 +85,14 @@ func (t *requestItemBuilder) Build() (*runtimehooksv1.GeneratePatchesRequestItem
 }
 
 // getTemplateAsUnstructured is a utility func that returns a template matching the holderKind, holderFieldPath
-// and mdTopologyName from a GeneratePatchesRequest.
-func getTemplateAsUnstructured(req *runtimehooksv1.GeneratePatchesRequest, holderKind, holderFieldPath, mdTopologyName string) (*unstructured.Unstructured, error) {
+// and topologyName from a GeneratePatchesRequest; topologyName is the name of the MachineDeployment or MachinePool
+// topology, depending on holderKind.
+func getTemplateAsUnstructured(req *runtimehooksv1.GeneratePatchesRequest, holderKind, holderFieldPath, topologyName string) (*unstructured.Unstructured, error) {
 	// Find the requestItem.
-	requestItem := getRequestItem(req, holderKind, holderFieldPath, mdTopologyName)
+	requestItem := getRequestItem(req, holderKind, holderFieldPath, topologyName)
 
 	if requestItem == nil {
-		return nil, errors.Errorf("failed to get request item with holder kind %q, holder field path %q and MD topology name %q", holderKind, holderFieldPath, mdTopologyName)
+		return nil, errors.Errorf("failed to get request item with holder kind %q, holder field path %q and topology name %q", holderKind, holderFieldPath, topologyName)
 	}
 
 	// Unmarshal the template.
@@ -113,8 +114,13 @@ func getRequestItemByUID(req *runtimehooksv1.GeneratePatchesRequest, uid types.U
 	return nil
 }
 
-// getRequestItem is a utility func that returns a template matching the holderKind, holderFiledPath and mdTopologyName from a GeneratePatchesRequest.
-func getRequestItem(req *runtimehooksv1.GeneratePatchesRequest, holderKind, holderFieldPath, mdTopologyName string) *runtimehooksv1.GeneratePatchesRequestItem {
+// getRequestItem is a utility func that returns a template matching the holderKind, holderFiledPath and topologyName from a GeneratePatchesRequest.
+func getRequestItem(req *runtimehooksv1.GeneratePatchesRequest, holderKind, holderFieldPath, topologyName string) *runtimehooksv1.GeneratePatchesRequestItem {
+	topologyNameVariable := "builtin.machineDeployment.topologyName"
+	if holderKind == "MachinePool" {
+		topologyNameVariable = "builtin.machinePool.topologyName"
+	}
+
 	for _, template := range req.Items {
 		if holderKind != "" && template.HolderReference.Kind != holderKind {
 			continue
@@ -122,11 +128,11 @@ func getRequestItem(req *runtimehooksv1.GeneratePatchesRequest, holderKind, hold
 		if holderFieldPath != "" && template.HolderReference.FieldPath != holderFieldPath {
 			continue
 		}
-		if mdTopologyName != "" {
+		if topologyName != "" {
 			templateVariables := toMap(template.Variables)
 
-			v, err := variables.GetVariableValue(templateVariables, "builtin.machineDeployment.topologyName")
-			if err != nil || string(v.Raw) != strconv.Quote(mdTopologyName) {
+			v, err := variables.GetVariableValue(templateVariables, topologyNameVariable)
+			if err != nil || string(v.Raw) != strconv.Quote(topologyName) {
 				continue
 			}
 		}
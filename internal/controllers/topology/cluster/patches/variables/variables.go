@@ -26,6 +26,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/internal/contract"
 )
@@ -40,6 +41,7 @@ type Builtins struct {
 	Cluster           *ClusterBuiltins           `json:"cluster,omitempty"`
 	ControlPlane      *ControlPlaneBuiltins      `json:"controlPlane,omitempty"`
 	MachineDeployment *MachineDeploymentBuiltins `json:"machineDeployment,omitempty"`
+	MachinePool       *MachinePoolBuiltins       `json:"machinePool,omitempty"`
 }
 
 // ClusterBuiltins represents builtin cluster variables.
@@ -153,6 +155,9 @@ type MachineDeploymentBuiltins struct {
 type MachineDeploymentBootstrapBuiltins struct {
 	// ConfigRef is the value of the .spec.template.spec.bootstrap.configRef field of the MachineDeployment.
 	ConfigRef *MachineDeploymentBootstrapConfigRefBuiltins `json:"configRef,omitempty"`
+
+	// Overrides is the value of the bootstrapOverrides field of the MachineDeployment topology.
+	Overrides *clusterv1.MachineDeploymentBootstrapOverrides `json:"overrides,omitempty"`
 }
 
 // MachineDeploymentBootstrapConfigRefBuiltins is the value of the .spec.template.spec.bootstrap.configRef
@@ -169,6 +174,60 @@ type MachineDeploymentInfrastructureRefBuiltins struct {
 	Name string `json:"name,omitempty"`
 }
 
+// MachinePoolBuiltins represents builtin MachinePool variables.
+// NOTE: These variables are only set for templates belonging to a MachinePool.
+type MachinePoolBuiltins struct {
+	// Version is the Kubernetes version of the MachinePool,
+	// to which the current template belongs to.
+	// NOTE: Please note that this version is the version we are currently reconciling towards.
+	// It can differ from the current version of the MachinePool machines while an upgrade process is
+	// being orchestrated.
+	Version string `json:"version,omitempty"`
+
+	// Class is the class name of the MachinePool,
+	// to which the current template belongs to.
+	Class string `json:"class,omitempty"`
+
+	// Name is the name of the MachinePool,
+	// to which the current template belongs to.
+	Name string `json:"name,omitempty"`
+
+	// TopologyName is the topology name of the MachinePool,
+	// to which the current template belongs to.
+	TopologyName string `json:"topologyName,omitempty"`
+
+	// Replicas is the value of the replicas field of the MachinePool,
+	// to which the current template belongs to.
+	Replicas *int64 `json:"replicas,omitempty"`
+
+	// Bootstrap is the value of the .spec.template.spec.bootstrap field of the MachinePool.
+	Bootstrap *MachinePoolBootstrapBuiltins `json:"bootstrap,omitempty"`
+
+	// InfrastructureRef is the value of the .spec.template.spec.infrastructureRef field of the MachinePool.
+	InfrastructureRef *MachinePoolInfrastructureRefBuiltins `json:"infrastructureRef,omitempty"`
+}
+
+// MachinePoolBootstrapBuiltins is the value of the .spec.template.spec.bootstrap field
+// of the MachinePool.
+type MachinePoolBootstrapBuiltins struct {
+	// ConfigRef is the value of the .spec.template.spec.bootstrap.configRef field of the MachinePool.
+	ConfigRef *MachinePoolBootstrapConfigRefBuiltins `json:"configRef,omitempty"`
+}
+
+// MachinePoolBootstrapConfigRefBuiltins is the value of the .spec.template.spec.bootstrap.configRef
+// field of the MachinePool.
+type MachinePoolBootstrapConfigRefBuiltins struct {
+	// Name of the bootstrap.configRef.
+	Name string `json:"name,omitempty"`
+}
+
+// MachinePoolInfrastructureRefBuiltins is the value of the .spec.template.spec.infrastructureRef field
+// of the MachinePool.
+type MachinePoolInfrastructureRefBuiltins struct {
+	// Name of the infrastructureRef.
+	Name string `json:"name,omitempty"`
+}
+
 // Global returns variables that apply to all the templates, including user provided variables
 // and builtin variables for the Cluster object.
 func Global(clusterTopology *clusterv1.Topology, cluster *clusterv1.Cluster) ([]runtimehooksv1.Variable, error) {
@@ -305,6 +364,13 @@ func MachineDeployment(mdTopology *clusterv1.MachineDeploymentTopology, md *clus
 		}
 	}
 
+	if mdTopology.BootstrapOverrides != nil {
+		if builtin.MachineDeployment.Bootstrap == nil {
+			builtin.MachineDeployment.Bootstrap = &MachineDeploymentBootstrapBuiltins{}
+		}
+		builtin.MachineDeployment.Bootstrap.Overrides = mdTopology.BootstrapOverrides
+	}
+
 	if mdInfrastructureMachineTemplate != nil {
 		builtin.MachineDeployment.InfrastructureRef = &MachineDeploymentInfrastructureRefBuiltins{
 			Name: mdInfrastructureMachineTemplate.GetName(),
@@ -320,6 +386,46 @@ func MachineDeployment(mdTopology *clusterv1.MachineDeploymentTopology, md *clus
 	return variables, nil
 }
 
+// MachinePool returns variables that apply to templates belonging to a MachinePool.
+func MachinePool(mpTopology *clusterv1.MachinePoolTopology, mp *expv1.MachinePool, mpBootstrapObject, mpInfrastructureMachinePoolObject *unstructured.Unstructured) ([]runtimehooksv1.Variable, error) {
+	variables := []runtimehooksv1.Variable{}
+
+	// Construct builtin variable.
+	builtin := Builtins{
+		MachinePool: &MachinePoolBuiltins{
+			Version:      *mp.Spec.Template.Spec.Version,
+			Class:        mpTopology.Class,
+			Name:         mp.Name,
+			TopologyName: mpTopology.Name,
+		},
+	}
+	if mp.Spec.Replicas != nil {
+		builtin.MachinePool.Replicas = pointer.Int64(int64(*mp.Spec.Replicas))
+	}
+
+	if mpBootstrapObject != nil {
+		builtin.MachinePool.Bootstrap = &MachinePoolBootstrapBuiltins{
+			ConfigRef: &MachinePoolBootstrapConfigRefBuiltins{
+				Name: mpBootstrapObject.GetName(),
+			},
+		}
+	}
+
+	if mpInfrastructureMachinePoolObject != nil {
+		builtin.MachinePool.InfrastructureRef = &MachinePoolInfrastructureRefBuiltins{
+			Name: mpInfrastructureMachinePoolObject.GetName(),
+		}
+	}
+
+	variable, err := toVariable(BuiltinsName, builtin)
+	if err != nil {
+		return nil, err
+	}
+	variables = append(variables, *variable)
+
+	return variables, nil
+}
+
 // toVariable converts name and value to a variable.
 func toVariable(name string, value interface{}) (*runtimehooksv1.Variable, error) {
 	marshalledValue, err := json.Marshal(value)
@@ -22,12 +22,14 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 )
@@ -671,6 +673,49 @@ func TestMachineDeployment(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Should calculate MachineDeployment variables with bootstrap overrides",
+			mdTopology: &clusterv1.MachineDeploymentTopology{
+				Replicas: pointer.Int32(3),
+				Name:     "md-topology",
+				Class:    "md-class",
+				BootstrapOverrides: &clusterv1.MachineDeploymentBootstrapOverrides{
+					KubeletExtraArgs: map[string]string{"max-pods": "200"},
+					NodeLabels:       map[string]string{"gpu": "true"},
+					Taints: []corev1.Taint{
+						{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+			md: builder.MachineDeployment(metav1.NamespaceDefault, "md1").
+				WithReplicas(3).
+				WithVersion("v1.21.1").
+				Build(),
+			mdBootstrapTemplate: builder.BootstrapTemplate(metav1.NamespaceDefault, "mdBT1").Build(),
+			want: []runtimehooksv1.Variable{
+				{
+					Name: BuiltinsName,
+					Value: toJSONCompact(`{
+					"machineDeployment":{
+						"version": "v1.21.1",
+						"class": "md-class",
+						"name": "md1",
+						"topologyName": "md-topology",
+						"replicas":3,
+						"bootstrap":{
+							"configRef":{
+								"name": "mdBT1"
+							},
+							"overrides":{
+								"kubeletExtraArgs":{"max-pods":"200"},
+								"nodeLabels":{"gpu":"true"},
+								"taints":[{"key":"gpu","value":"true","effect":"NoSchedule"}]
+							}
+						}
+					}}`),
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -683,6 +728,109 @@ func TestMachineDeployment(t *testing.T) {
 	}
 }
 
+func TestMachinePool(t *testing.T) {
+	tests := []struct {
+		name                              string
+		mpTopology                        *clusterv1.MachinePoolTopology
+		mp                                *expv1.MachinePool
+		mpBootstrapObject                 *unstructured.Unstructured
+		mpInfrastructureMachinePoolObject *unstructured.Unstructured
+		want                              []runtimehooksv1.Variable
+	}{
+		{
+			name: "Should calculate MachinePool variables",
+			mpTopology: &clusterv1.MachinePoolTopology{
+				Replicas: pointer.Int32(3),
+				Name:     "mp-topology",
+				Class:    "mp-class",
+			},
+			mp: builder.MachinePool(metav1.NamespaceDefault, "mp1").
+				WithReplicas(3).
+				WithVersion("v1.21.1").
+				Build(),
+			want: []runtimehooksv1.Variable{
+				{
+					Name: BuiltinsName,
+					Value: toJSONCompact(`{
+					"machinePool":{
+						"version": "v1.21.1",
+						"class": "mp-class",
+						"name": "mp1",
+						"topologyName": "mp-topology",
+						"replicas":3
+					}}`),
+				},
+			},
+		},
+		{
+			name: "Should calculate MachinePool variables, replicas not set",
+			mpTopology: &clusterv1.MachinePoolTopology{
+				Name:  "mp-topology",
+				Class: "mp-class",
+			},
+			mp: builder.MachinePool(metav1.NamespaceDefault, "mp1").
+				WithVersion("v1.21.1").
+				Build(),
+			want: []runtimehooksv1.Variable{
+				{
+					Name: BuiltinsName,
+					Value: toJSONCompact(`{
+					"machinePool":{
+						"version": "v1.21.1",
+						"class": "mp-class",
+						"name": "mp1",
+						"topologyName": "mp-topology"
+					}}`),
+				},
+			},
+		},
+		{
+			name: "Should calculate MachinePool variables with bootstrap config and infrastructure machine pool",
+			mpTopology: &clusterv1.MachinePoolTopology{
+				Replicas: pointer.Int32(3),
+				Name:     "mp-topology",
+				Class:    "mp-class",
+			},
+			mp: builder.MachinePool(metav1.NamespaceDefault, "mp1").
+				WithReplicas(3).
+				WithVersion("v1.21.1").
+				Build(),
+			mpBootstrapObject:                 builder.BootstrapTemplate(metav1.NamespaceDefault, "mp1-bs").Build(),
+			mpInfrastructureMachinePoolObject: builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "mp1-infra").Build(),
+			want: []runtimehooksv1.Variable{
+				{
+					Name: BuiltinsName,
+					Value: toJSONCompact(`{
+					"machinePool":{
+						"version": "v1.21.1",
+						"class": "mp-class",
+						"name": "mp1",
+						"topologyName": "mp-topology",
+						"replicas":3,
+						"bootstrap":{
+							"configRef":{
+								"name": "mp1-bs"
+							}
+						},
+						"infrastructureRef":{
+							"name": "mp1-infra"
+						}
+					}}`),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := MachinePool(tt.mpTopology, tt.mp, tt.mpBootstrapObject, tt.mpInfrastructureMachinePoolObject)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
 func toJSON(value string) apiextensionsv1.JSON {
 	return apiextensionsv1.JSON{Raw: []byte(value)}
 }
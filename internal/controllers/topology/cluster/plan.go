@@ -0,0 +1,239 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/structuredmerge"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const (
+	planEventReason = "TopologyPlan"
+
+	planOperationCreate = "create"
+	planOperationUpdate = "update"
+	planOperationRotate = "rotate"
+	planOperationDelete = "delete"
+)
+
+// isTopologyPlan returns true if the Cluster topology is in plan mode, i.e. if the desired state of the Cluster
+// topology must be computed, but not applied.
+func isTopologyPlan(cluster *clusterv1.Cluster) bool {
+	_, ok := cluster.GetAnnotations()[clusterv1.ClusterTopologyPlanAnnotation]
+	return ok
+}
+
+// reconcilePlan computes the desired state of a Cluster topology in plan mode, and records the changes which would
+// be applied to the objects generated from the Cluster topology in the PlanTracker, without applying them;
+// each change is reported with an event including the patch which would be applied to the object.
+// NOTE: Changes are computed with a two-ways merge patch, like in clusterctl alpha topology plan, so they could
+// slightly differ from the changes applied with server side apply, e.g. for fields set by other controllers.
+// NOTE: Lifecycle hooks are not called in plan mode, so the plan assumes they are not blocking.
+func (r *Reconciler) reconcilePlan(ctx context.Context, s *scope.Scope) error {
+	log := tlog.LoggerFrom(ctx)
+
+	// Setup watches, so changes to the ClusterClass templates or to the objects of the Cluster topology update the plan.
+	if err := r.setupDynamicWatches(ctx, s); err != nil {
+		return errors.Wrap(err, "error creating dynamic watch")
+	}
+
+	var err error
+	s.Desired, err = r.computeDesiredState(ctx, s)
+	if err != nil {
+		return errors.Wrap(err, "error computing the desired state of the Cluster topology")
+	}
+
+	if err := computePlan(s); err != nil {
+		return errors.Wrap(err, "error computing the plan of the Cluster topology")
+	}
+
+	for _, c := range s.PlanTracker.Changes() {
+		log.Infof("Cluster topology is in plan mode, not applying %s of %s %s", c.Operation, c.Kind, c.Name)
+		if c.Diff == "" {
+			r.recorder.Eventf(s.Current.Cluster, corev1.EventTypeNormal, planEventReason, "Would %s %s %q", c.Operation, c.Kind, c.Name)
+			continue
+		}
+		r.recorder.Eventf(s.Current.Cluster, corev1.EventTypeNormal, planEventReason, "Would %s %s %q: %s", c.Operation, c.Kind, c.Name, c.Diff)
+	}
+	return nil
+}
+
+// computePlan compares the current and the desired state of a Cluster topology and records the changes which would
+// be applied to the objects generated from the Cluster topology in the PlanTracker.
+func computePlan(s *scope.Scope) error {
+	ignorePaths, err := s.Blueprint.IgnorePaths()
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate ignore paths from %s", tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+	infrastructureClusterIgnorePaths, err := contract.InfrastructureCluster().IgnorePaths(s.Desired.InfrastructureCluster)
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate ignore paths for %s", tlog.KObj{Obj: s.Desired.InfrastructureCluster})
+	}
+
+	plan := func(in planChangeInput) error {
+		return planChange(s.PlanTracker, in)
+	}
+
+	if err := plan(planChangeInput{current: s.Current.InfrastructureCluster, desired: s.Desired.InfrastructureCluster, ignorePaths: append(infrastructureClusterIgnorePaths, ignorePaths...)}); err != nil {
+		return err
+	}
+
+	// Control plane.
+	if s.Blueprint.HasControlPlaneInfrastructureMachine() {
+		if err := plan(planChangeInput{current: s.Current.ControlPlane.InfrastructureMachineTemplate, desired: s.Desired.ControlPlane.InfrastructureMachineTemplate, ignorePaths: ignorePaths, template: true}); err != nil {
+			return err
+		}
+	}
+	for _, failureDomain := range sortedFailureDomains(s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates) {
+		if err := plan(planChangeInput{current: s.Current.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain], desired: s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain], ignorePaths: ignorePaths, template: true}); err != nil {
+			return err
+		}
+	}
+	for _, failureDomain := range sortedFailureDomains(s.Current.ControlPlane.FailureDomainInfrastructureMachineTemplates) {
+		if _, ok := s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain]; !ok {
+			if err := plan(planChangeInput{current: s.Current.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain]}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := plan(planChangeInput{current: s.Current.ControlPlane.Object, desired: s.Desired.ControlPlane.Object, ignorePaths: ignorePaths}); err != nil {
+		return err
+	}
+	if err := plan(planChangeInput{kind: "MachineHealthCheck", current: s.Current.ControlPlane.MachineHealthCheck, desired: s.Desired.ControlPlane.MachineHealthCheck}); err != nil {
+		return err
+	}
+
+	if err := plan(planChangeInput{kind: "Cluster", current: s.Current.Cluster, desired: s.Desired.Cluster}); err != nil {
+		return err
+	}
+
+	// MachineDeployments.
+	mdDiff := calculateMachineDeploymentDiff(s.Current.MachineDeployments, s.Desired.MachineDeployments)
+	toPlan := append(append(mdDiff.toCreate, mdDiff.toUpdate...), mdDiff.toDelete...)
+	sort.Strings(toPlan)
+	for _, mdTopologyName := range toPlan {
+		current, desired := s.Current.MachineDeployments[mdTopologyName], s.Desired.MachineDeployments[mdTopologyName]
+		if desired == nil {
+			// NOTE: The templates and the MachineHealthCheck are deleted together with the MachineDeployment.
+			if err := plan(planChangeInput{kind: "MachineDeployment", current: current.Object}); err != nil {
+				return err
+			}
+			continue
+		}
+		if current == nil {
+			current = &scope.MachineDeploymentState{}
+		}
+		for _, in := range []planChangeInput{
+			{current: current.BootstrapTemplate, desired: desired.BootstrapTemplate, ignorePaths: ignorePaths, template: true},
+			{current: current.InfrastructureMachineTemplate, desired: desired.InfrastructureMachineTemplate, ignorePaths: ignorePaths, template: true},
+			{kind: "MachineDeployment", current: current.Object, desired: desired.Object},
+			{kind: "MachineHealthCheck", current: current.MachineHealthCheck, desired: desired.MachineHealthCheck},
+		} {
+			if err := plan(in); err != nil {
+				return err
+			}
+		}
+	}
+
+	// MachinePools.
+	mpDiff := calculateMachinePoolDiff(s.Current.MachinePools, s.Desired.MachinePools)
+	toPlan = append(append(mpDiff.toCreate, mpDiff.toUpdate...), mpDiff.toDelete...)
+	sort.Strings(toPlan)
+	for _, mpTopologyName := range toPlan {
+		current, desired := s.Current.MachinePools[mpTopologyName], s.Desired.MachinePools[mpTopologyName]
+		if desired == nil {
+			if err := plan(planChangeInput{kind: "MachinePool", current: current.Object}); err != nil {
+				return err
+			}
+			continue
+		}
+		if current == nil {
+			current = &scope.MachinePoolState{}
+		}
+		for _, in := range []planChangeInput{
+			{current: current.BootstrapObject, desired: desired.BootstrapObject, ignorePaths: ignorePaths},
+			{current: current.InfrastructureMachinePoolObject, desired: desired.InfrastructureMachinePoolObject, ignorePaths: ignorePaths},
+			{kind: "MachinePool", current: current.Object, desired: desired.Object},
+		} {
+			if err := plan(in); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type planChangeInput struct {
+	// kind is the kind of the object; if empty, the kind is read from the objects.
+	// NOTE: The kind must be set for typed objects, given that TypeMeta could be empty in typed current objects.
+	kind        string
+	current     client.Object
+	desired     client.Object
+	ignorePaths []contract.Path
+	// template is true if the object is a template, which is rotated instead of being updated when the spec changes.
+	template bool
+}
+
+// planChange records the change which would be applied to an object generated from a Cluster topology in the PlanTracker.
+func planChange(planTracker *scope.PlanTracker, in planChangeInput) error {
+	currentIsNil, desiredIsNil := util.IsNil(in.current), util.IsNil(in.desired)
+	kind := in.kind
+	switch {
+	case kind != "":
+	case !desiredIsNil:
+		kind = in.desired.GetObjectKind().GroupVersionKind().Kind
+	case !currentIsNil:
+		kind = in.current.GetObjectKind().GroupVersionKind().Kind
+	}
+
+	switch {
+	case currentIsNil && desiredIsNil:
+		return nil
+	case desiredIsNil:
+		planTracker.Add(planOperationDelete, kind, in.current.GetName(), "")
+		return nil
+	case currentIsNil:
+		planTracker.Add(planOperationCreate, kind, in.desired.GetName(), "")
+		return nil
+	}
+
+	patchHelper, err := structuredmerge.NewTwoWaysPatchHelper(in.current, in.desired, nil, structuredmerge.IgnorePaths(in.ignorePaths))
+	if err != nil {
+		return errors.Wrapf(err, "failed to compute changes to %s %s", kind, in.current.GetName())
+	}
+	if !patchHelper.HasChanges() {
+		return nil
+	}
+	// NOTE: Templates are rotated if the spec changes, while changes to the metadata only are applied in place.
+	operation := planOperationUpdate
+	if in.template && patchHelper.HasSpecChanges() {
+		operation = planOperationRotate
+	}
+	planTracker.Add(operation, kind, in.current.GetName(), string(patchHelper.Changes()))
+	return nil
+}
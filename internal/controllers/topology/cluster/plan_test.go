@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestComputePlan(t *testing.T) {
+	g := NewWithT(t)
+
+	// NOTE: Current objects are read from the API server, so they have a creationTimestamp.
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+	cluster.CreationTimestamp = metav1.Now()
+	infrastructureCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infra1").
+		WithSpecFields(map[string]interface{}{"spec.foo": "bar"}).
+		Build()
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp1").Build()
+	bootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()
+	infrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-machine1").Build()
+	newMachineDeployment := func(name string, replicas int32) *scope.MachineDeploymentState {
+		return &scope.MachineDeploymentState{
+			Object: builder.MachineDeployment(metav1.NamespaceDefault, name).
+				WithBootstrapTemplate(bootstrapTemplate).
+				WithInfrastructureTemplate(infrastructureMachineTemplate).
+				WithReplicas(replicas).
+				Build(),
+			BootstrapTemplate:             bootstrapTemplate.DeepCopy(),
+			InfrastructureMachineTemplate: infrastructureMachineTemplate.DeepCopy(),
+		}
+	}
+
+	s := scope.New(cluster)
+	s.Blueprint = &scope.ClusterBlueprint{
+		ClusterClass: builder.ClusterClass(metav1.NamespaceDefault, "class1").Build(),
+	}
+	s.Current.InfrastructureCluster = infrastructureCluster
+	s.Current.ControlPlane = &scope.ControlPlaneState{Object: controlPlane}
+	s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{
+		"md-unchanged": newMachineDeployment("md-unchanged", 1),
+		"md-scaled":    newMachineDeployment("md-scaled", 1),
+		"md-rotated":   newMachineDeployment("md-rotated", 1),
+		"md-deleted":   newMachineDeployment("md-deleted", 1),
+	}
+	for _, md := range s.Current.MachineDeployments {
+		md.Object.CreationTimestamp = cluster.CreationTimestamp
+	}
+
+	s.Desired = &scope.ClusterState{
+		Cluster: cluster.DeepCopy(),
+		InfrastructureCluster: builder.InfrastructureCluster(metav1.NamespaceDefault, "infra1").
+			WithSpecFields(map[string]interface{}{"spec.foo": "baz"}).
+			Build(),
+		ControlPlane: &scope.ControlPlaneState{Object: controlPlane.DeepCopy()},
+		MachineDeployments: map[string]*scope.MachineDeploymentState{
+			"md-unchanged": newMachineDeployment("md-unchanged", 1),
+			"md-scaled":    newMachineDeployment("md-scaled", 3),
+			"md-rotated": func() *scope.MachineDeploymentState {
+				md := newMachineDeployment("md-rotated", 1)
+				g.Expect(unstructured.SetNestedField(md.BootstrapTemplate.Object, "bar", "spec", "template", "spec", "foo")).To(Succeed())
+				return md
+			}(),
+			"md-created": newMachineDeployment("md-created", 1),
+		},
+	}
+
+	g.Expect(computePlan(s)).To(Succeed())
+
+	changes := []string{}
+	for _, c := range s.PlanTracker.Changes() {
+		changes = append(changes, c.Operation+" "+c.Kind+" "+c.Name+" "+c.Diff)
+	}
+	g.Expect(changes).To(Equal([]string{
+		`update GenericInfrastructureCluster infra1 {"spec":{"foo":"baz"}}`,
+		"create GenericBootstrapConfigTemplate bootstrap1 ",
+		"create GenericInfrastructureMachineTemplate infra-machine1 ",
+		"create MachineDeployment md-created ",
+		"delete MachineDeployment md-deleted ",
+		`rotate GenericBootstrapConfigTemplate bootstrap1 {"spec":{"template":{"spec":{"foo":"bar"}}}}`,
+		`update MachineDeployment md-scaled {"spec":{"replicas":3}}`,
+	}))
+	g.Expect(s.Current.Cluster.Spec).To(Equal(cluster.Spec))
+}
+
+func TestIsTopologyPlan(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+	g.Expect(isTopologyPlan(cluster)).To(BeFalse())
+
+	cluster.Annotations = map[string]string{clusterv1.ClusterTopologyPlanAnnotation: ""}
+	g.Expect(isTopologyPlan(cluster)).To(BeTrue())
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+)
+
+// reconcileCache keeps track of the hash of the state of each Cluster topology which was last reconciled
+// successfully into a steady state, i.e. without upgrades, rollouts or blocking hooks in progress; this allows
+// to skip computing and applying the desired state of Cluster topologies which have not changed since then.
+// The reconcileCache also keeps track, for each Cluster topology, of the objects last found without changes
+// to be applied; this allows to skip computing the patch for objects whose current and desired state have
+// not changed since then, e.g. when the reconciliation is triggered by changes to other objects.
+type reconcileCache struct {
+	lock    sync.Mutex
+	hashes  map[types.NamespacedName]string
+	objects map[types.NamespacedName]map[types.UID]string
+}
+
+func newReconcileCache() *reconcileCache {
+	return &reconcileCache{
+		hashes:  map[types.NamespacedName]string{},
+		objects: map[types.NamespacedName]map[types.UID]string{},
+	}
+}
+
+// Has returns true if the given hash is the hash of the state last reconciled into a steady state for a Cluster.
+func (c *reconcileCache) Has(key types.NamespacedName, hash string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h, ok := c.hashes[key]
+	return ok && h == hash
+}
+
+// Set stores the hash of the state last reconciled into a steady state for a Cluster.
+func (c *reconcileCache) Set(key types.NamespacedName, hash string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.hashes[key] = hash
+}
+
+// Delete removes the hash stored for a Cluster.
+// NOTE: The hashes stored for the objects of the Cluster topology are not removed, see DeleteObjects.
+func (c *reconcileCache) Delete(key types.NamespacedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.hashes, key)
+}
+
+// HasObject returns true if the given hash is the hash of the state of an object of a Cluster topology
+// last found without changes to be applied.
+func (c *reconcileCache) HasObject(key types.NamespacedName, uid types.UID, hash string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	h, ok := c.objects[key][uid]
+	return ok && h == hash
+}
+
+// SetObject stores the hash of the state of an object of a Cluster topology last found without changes to be applied.
+func (c *reconcileCache) SetObject(key types.NamespacedName, uid types.UID, hash string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.objects[key] == nil {
+		c.objects[key] = map[types.UID]string{}
+	}
+	c.objects[key][uid] = hash
+}
+
+// DeleteObjects removes the hashes stored for the objects of a Cluster topology.
+func (c *reconcileCache) DeleteObjects(key types.NamespacedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.objects, key)
+}
+
+// unchangedObjectInputs are the inputs determining the changes to be applied to an object of a Cluster topology.
+type unchangedObjectInputs struct {
+	// DesiredStateHash is the hash of the desired state of the object, which includes the hash of the inputs
+	// used to compute it stored in the ClusterTopologyInputsHashAnnotation.
+	DesiredStateHash string `json:"desiredStateHash"`
+
+	// ResourceVersion is the resourceVersion of the current object.
+	ResourceVersion string `json:"resourceVersion"`
+
+	// IgnorePaths are the paths ignored when computing the changes to be applied.
+	IgnorePaths []contract.Path `json:"ignorePaths,omitempty"`
+}
+
+// computeObjectHash returns the hash of the inputs determining the changes to be applied to an object of a
+// Cluster topology, i.e. its desired state, the resourceVersion of the current object and the ignored paths.
+// NOTE: As long as the hash does not change, the changes to be applied do not change too.
+func computeObjectHash(current, desired client.Object, ignorePaths ...[]contract.Path) (string, error) {
+	desiredStateHash, err := computeDesiredStateHash(desired)
+	if err != nil {
+		return "", err
+	}
+	inputs := unchangedObjectInputs{
+		DesiredStateHash: desiredStateHash,
+		ResourceVersion:  current.GetResourceVersion(),
+	}
+	for _, paths := range ignorePaths {
+		inputs.IgnorePaths = append(inputs.IgnorePaths, paths...)
+	}
+	return computeHash(inputs)
+}
+
+// reconcileInputs are the inputs of the reconciliation of a Cluster topology.
+type reconcileInputs struct {
+	// ClusterClassGeneration is the generation of the ClusterClass.
+	ClusterClassGeneration int64 `json:"clusterClassGeneration"`
+
+	// ResourceVersions are the resourceVersions of the Cluster, of the templates referenced by the ClusterClass
+	// and of the objects generated from the Cluster topology.
+	ResourceVersions map[string]string `json:"resourceVersions"`
+
+	// RolloutsReached are the names of the MachineDeployment topologies with a rolloutAfter which is reached.
+	RolloutsReached []string `json:"rolloutsReached,omitempty"`
+}
+
+// computeReconcileHash returns the hash of the inputs of the reconciliation of a Cluster topology, i.e. the generation
+// of the ClusterClass, the resourceVersion of the Cluster, of the templates and of the objects generated from the
+// Cluster topology; false is returned if the reconciliation cannot be skipped even if the inputs have not changed,
+// i.e. if the Cluster has a drift policy, if the ClusterClass has external patches or if there are pending hooks.
+func computeReconcileHash(s *scope.Scope, now time.Time) (string, bool, error) {
+	if getDriftPolicy(s.Current.Cluster) != "" || s.Current.Cluster.GetAnnotations()[runtimev1.PendingHooksAnnotation] != "" {
+		return "", false, nil
+	}
+	for _, patch := range s.Blueprint.ClusterClass.Spec.Patches {
+		if patch.External != nil {
+			return "", false, nil
+		}
+	}
+
+	inputs := reconcileInputs{
+		ClusterClassGeneration: s.Blueprint.ClusterClass.GetGeneration(),
+		ResourceVersions:       map[string]string{},
+	}
+	addResourceVersion := func(key string, obj client.Object) {
+		inputs.ResourceVersions[key] = obj.GetResourceVersion()
+	}
+	addTemplateResourceVersion := func(prefix string, t *unstructured.Unstructured) {
+		if t != nil {
+			addResourceVersion(fmt.Sprintf("%s/%s/%s", prefix, t.GetKind(), t.GetName()), t)
+		}
+	}
+
+	addResourceVersion("Cluster", s.Current.Cluster)
+
+	addTemplateResourceVersion("blueprint", s.Blueprint.InfrastructureClusterTemplate)
+	if s.Blueprint.ControlPlane != nil {
+		addTemplateResourceVersion("blueprint", s.Blueprint.ControlPlane.Template)
+		addTemplateResourceVersion("blueprint", s.Blueprint.ControlPlane.InfrastructureMachineTemplate)
+		for _, t := range s.Blueprint.ControlPlane.FailureDomainInfrastructureMachineTemplates {
+			addTemplateResourceVersion("blueprint", t)
+		}
+	}
+	for _, md := range s.Blueprint.MachineDeployments {
+		addTemplateResourceVersion("blueprint", md.BootstrapTemplate)
+		addTemplateResourceVersion("blueprint", md.InfrastructureMachineTemplate)
+	}
+	for _, mp := range s.Blueprint.MachinePools {
+		addTemplateResourceVersion("blueprint", mp.BootstrapTemplate)
+		addTemplateResourceVersion("blueprint", mp.InfrastructureMachinePoolTemplate)
+	}
+
+	addTemplateResourceVersion("current", s.Current.InfrastructureCluster)
+	if s.Current.ControlPlane != nil {
+		addTemplateResourceVersion("current", s.Current.ControlPlane.Object)
+		addTemplateResourceVersion("current", s.Current.ControlPlane.InfrastructureMachineTemplate)
+		for _, t := range s.Current.ControlPlane.FailureDomainInfrastructureMachineTemplates {
+			addTemplateResourceVersion("current", t)
+		}
+		if s.Current.ControlPlane.MachineHealthCheck != nil {
+			addResourceVersion("current/MachineHealthCheck/"+s.Current.ControlPlane.MachineHealthCheck.Name, s.Current.ControlPlane.MachineHealthCheck)
+		}
+	}
+	for _, md := range s.Current.MachineDeployments {
+		if md.Object != nil {
+			addResourceVersion("current/MachineDeployment/"+md.Object.Name, md.Object)
+		}
+		addTemplateResourceVersion("current", md.BootstrapTemplate)
+		addTemplateResourceVersion("current", md.InfrastructureMachineTemplate)
+		if md.MachineHealthCheck != nil {
+			addResourceVersion("current/MachineHealthCheck/"+md.MachineHealthCheck.Name, md.MachineHealthCheck)
+		}
+	}
+	for _, mp := range s.Current.MachinePools {
+		if mp.Object != nil {
+			addResourceVersion("current/MachinePool/"+mp.Object.Name, mp.Object)
+		}
+		addTemplateResourceVersion("current", mp.BootstrapObject)
+		addTemplateResourceVersion("current", mp.InfrastructureMachinePoolObject)
+	}
+
+	// NOTE: rolloutAfter depends on the current time, so reaching it must invalidate the hash.
+	if s.Current.Cluster.Spec.Topology.Workers != nil {
+		for _, md := range s.Current.Cluster.Spec.Topology.Workers.MachineDeployments {
+			if md.RolloutAfter != nil && !md.RolloutAfter.After(now) {
+				inputs.RolloutsReached = append(inputs.RolloutsReached, md.Name)
+			}
+		}
+	}
+
+	hash, err := computeHash(inputs)
+	if err != nil {
+		return "", false, err
+	}
+	return hash, true, nil
+}
+
+// isSteadyState returns true if the reconciliation of a Cluster topology did not find any upgrade, rollout
+// or blocking hook in progress.
+func isSteadyState(s *scope.Scope) bool {
+	return s.HookResponseTracker.AggregateRetryAfter() == 0 &&
+		!s.UpgradeTracker.ControlPlane.PendingUpgrade &&
+		!s.UpgradeTracker.ControlPlane.IsProvisioning &&
+		!s.UpgradeTracker.ControlPlane.IsUpgrading &&
+		!s.UpgradeTracker.ControlPlane.IsScaling &&
+		!s.UpgradeTracker.MachineDeployments.PendingUpgrade() &&
+		len(s.UpgradeTracker.MachineDeployments.RolloutNames()) == 0 &&
+		!s.Current.MachineDeployments.IsAnyRollingOut() &&
+		!s.UpgradeTracker.MachinePools.PendingUpgrade() &&
+		len(s.UpgradeTracker.MachinePools.UpgradingNames()) == 0 &&
+		!s.Current.MachinePools.IsAnyRollingOut()
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
+	"sigs.k8s.io/cluster-api/internal/controllers/topology/cluster/scope"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestComputeReconcileHash(t *testing.T) {
+	now := time.Now()
+	infrastructureClusterTemplate := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra-cluster-template").Build()
+	bootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap-template").Build()
+	infrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-machine-template").Build()
+
+	newScope := func() *scope.Scope {
+		cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+			WithTopology(&clusterv1.Topology{
+				Version: "v1.22.0",
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{Class: "linux-worker", Name: "md1"},
+					},
+				},
+			}).
+			Build()
+		cluster.SetResourceVersion("1")
+		s := scope.New(cluster)
+		s.Blueprint = &scope.ClusterBlueprint{
+			Topology:                      cluster.Spec.Topology,
+			ClusterClass:                  builder.ClusterClass(metav1.NamespaceDefault, "class1").Build(),
+			InfrastructureClusterTemplate: infrastructureClusterTemplate.DeepCopy(),
+			MachineDeployments: map[string]*scope.MachineDeploymentBlueprint{
+				"linux-worker": {
+					BootstrapTemplate:             bootstrapTemplate.DeepCopy(),
+					InfrastructureMachineTemplate: infrastructureMachineTemplate.DeepCopy(),
+				},
+			},
+		}
+		s.Current.InfrastructureCluster = builder.InfrastructureCluster(metav1.NamespaceDefault, "infra-cluster").Build()
+		s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{
+			"md1": {
+				Object:             builder.MachineDeployment(metav1.NamespaceDefault, "md1").Build(),
+				MachineHealthCheck: builder.MachineHealthCheck(metav1.NamespaceDefault, "md1").Build(),
+			},
+		}
+		return s
+	}
+
+	g := NewWithT(t)
+
+	hash, cacheable, err := computeReconcileHash(newScope(), now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cacheable).To(BeTrue())
+	g.Expect(hash).ToNot(BeEmpty())
+
+	// The hash does not change if nothing changes.
+	got, _, err := computeReconcileHash(newScope(), now)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(hash))
+
+	// The hash changes if any of the inputs change.
+	for name, mutate := range map[string]func(s *scope.Scope){
+		"ClusterClass": func(s *scope.Scope) { s.Blueprint.ClusterClass.SetGeneration(2) },
+		"Cluster":      func(s *scope.Scope) { s.Current.Cluster.SetResourceVersion("2") },
+		"template": func(s *scope.Scope) {
+			s.Blueprint.MachineDeployments["linux-worker"].BootstrapTemplate.SetResourceVersion("2")
+		},
+		"current object":     func(s *scope.Scope) { s.Current.InfrastructureCluster.SetResourceVersion("2") },
+		"MachineHealthCheck": func(s *scope.Scope) { s.Current.MachineDeployments["md1"].MachineHealthCheck.SetResourceVersion("2") },
+		"MachinePool": func(s *scope.Scope) {
+			s.Current.MachinePools = map[string]*scope.MachinePoolState{
+				"mp1": {Object: builder.MachinePool(metav1.NamespaceDefault, "mp1").Build()},
+			}
+		},
+		"rolloutAfter reached": func(s *scope.Scope) {
+			rolloutAfter := metav1.NewTime(now.Add(-time.Minute))
+			s.Current.Cluster.Spec.Topology.Workers.MachineDeployments[0].RolloutAfter = &rolloutAfter
+		},
+	} {
+		s := newScope()
+		mutate(s)
+		got, cacheable, err := computeReconcileHash(s, now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cacheable).To(BeTrue())
+		g.Expect(got).ToNot(Equal(hash), "hash did not change when changing the %s", name)
+	}
+
+	// The reconcile cannot be skipped for Clusters with a drift policy, with pending hooks or with external patches.
+	for name, mutate := range map[string]func(s *scope.Scope){
+		"drift policy": func(s *scope.Scope) {
+			s.Current.Cluster.SetAnnotations(map[string]string{clusterv1.ClusterTopologyDriftPolicyAnnotation: clusterv1.ClusterTopologyDriftPolicyReport})
+		},
+		"pending hooks": func(s *scope.Scope) {
+			s.Current.Cluster.SetAnnotations(map[string]string{runtimev1.PendingHooksAnnotation: "AfterClusterUpgrade"})
+		},
+		"external patches": func(s *scope.Scope) {
+			s.Blueprint.ClusterClass.Spec.Patches = []clusterv1.ClusterClassPatch{{Name: "patch1", External: &clusterv1.ExternalPatchDefinition{}}}
+		},
+	} {
+		s := newScope()
+		mutate(s)
+		_, cacheable, err := computeReconcileHash(s, now)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cacheable).To(BeFalse(), "reconcile can be skipped with %s", name)
+	}
+}
+
+func TestReconcileCache(t *testing.T) {
+	g := NewWithT(t)
+
+	key := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "cluster1"}
+	c := newReconcileCache()
+	g.Expect(c.Has(key, "hash1")).To(BeFalse())
+
+	c.Set(key, "hash1")
+	g.Expect(c.Has(key, "hash1")).To(BeTrue())
+	g.Expect(c.Has(key, "hash2")).To(BeFalse())
+
+	c.Delete(key)
+	g.Expect(c.Has(key, "hash1")).To(BeFalse())
+}
+
+func TestIsSteadyState(t *testing.T) {
+	newScope := func() *scope.Scope {
+		s := scope.New(builder.Cluster(metav1.NamespaceDefault, "cluster1").Build())
+		s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{}
+		return s
+	}
+
+	g := NewWithT(t)
+	g.Expect(isSteadyState(newScope())).To(BeTrue())
+
+	for name, mutate := range map[string]func(s *scope.Scope){
+		"control plane pending upgrade":     func(s *scope.Scope) { s.UpgradeTracker.ControlPlane.PendingUpgrade = true },
+		"control plane upgrading":           func(s *scope.Scope) { s.UpgradeTracker.ControlPlane.IsUpgrading = true },
+		"control plane scaling":             func(s *scope.Scope) { s.UpgradeTracker.ControlPlane.IsScaling = true },
+		"MachineDeployment pending upgrade": func(s *scope.Scope) { s.UpgradeTracker.MachineDeployments.MarkPendingUpgrade("md1") },
+		"MachineDeployment rolling out":     func(s *scope.Scope) { s.UpgradeTracker.MachineDeployments.MarkRollingOut("md1") },
+		"control plane being provisioned":   func(s *scope.Scope) { s.UpgradeTracker.ControlPlane.IsProvisioning = true },
+		"MachinePool pending upgrade":       func(s *scope.Scope) { s.UpgradeTracker.MachinePools.MarkPendingUpgrade("mp1") },
+		"MachinePool upgrading":             func(s *scope.Scope) { s.UpgradeTracker.MachinePools.MarkUpgrading("mp1") },
+		"MachinePool rolling out": func(s *scope.Scope) {
+			s.Current.MachinePools = scope.MachinePoolsStateMap{
+				"mp1": {Object: builder.MachinePool(metav1.NamespaceDefault, "mp1").WithReplicas(2).Build()},
+			}
+		},
+	} {
+		s := newScope()
+		mutate(s)
+		g.Expect(isSteadyState(s)).To(BeFalse(), "steady state with %s", name)
+	}
+}
@@ -19,6 +19,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -33,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	runtimehooksv1 "sigs.k8s.io/cluster-api/exp/runtime/hooks/api/v1alpha1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/contract"
@@ -47,6 +49,9 @@ const (
 	createEventReason = "TopologyCreate"
 	updateEventReason = "TopologyUpdate"
 	deleteEventReason = "TopologyDelete"
+
+	metadataConflictEventReason = "TopologyMetadataConflict"
+	driftDetectedEventReason    = "TopologyDriftDetected"
 )
 
 // reconcileState reconciles the current and desired state of the managed Cluster topology.
@@ -70,23 +75,27 @@ func (r *Reconciler) reconcileState(ctx context.Context, s *scope.Scope) error {
 		}
 	}
 
-	// Reconcile desired state of the InfrastructureCluster object.
-	if err := r.reconcileInfrastructureCluster(ctx, s); err != nil {
+	// Reconcile the desired state of the objects generated from the Cluster topology.
+	// NOTE: The InfrastructureCluster, the ControlPlane, the MachineDeployments and the MachinePools are reconciled concurrently,
+	// while the Cluster is reconciled only after the InfrastructureCluster and the ControlPlane it references.
+	// Templates are always reconciled before the objects referencing them.
+	g := newApplyGraph()
+	g.Add("InfrastructureCluster", func(ctx context.Context) error {
+		return r.reconcileInfrastructureCluster(ctx, s)
+	})
+	g.Add("ControlPlane", func(ctx context.Context) error {
+		return r.reconcileControlPlane(ctx, s)
+	})
+	g.Add("Cluster", func(ctx context.Context) error {
+		return r.reconcileCluster(ctx, s)
+	}, "InfrastructureCluster", "ControlPlane")
+	if err := r.addMachineDeploymentsToApplyGraph(s, g); err != nil {
 		return err
 	}
-
-	// Reconcile desired state of the ControlPlane object.
-	if err := r.reconcileControlPlane(ctx, s); err != nil {
+	if err := r.addMachinePoolsToApplyGraph(s, g); err != nil {
 		return err
 	}
-
-	// Reconcile desired state of the Cluster object.
-	if err := r.reconcileCluster(ctx, s); err != nil {
-		return err
-	}
-
-	// Reconcile desired state of the MachineDeployment objects.
-	return r.reconcileMachineDeployments(ctx, s)
+	return g.Run(ctx, r.MaxConcurrentApplies)
 }
 
 // Reconcile the Cluster shim, a temporary object used a mean to collect objects/templates
@@ -186,6 +195,10 @@ func getOwnerReferenceFrom(obj, owner client.Object) *metav1.OwnerReference {
 }
 
 func (r *Reconciler) callAfterHooks(ctx context.Context, s *scope.Scope) error {
+	if err := r.callAfterControlPlaneEndpointSet(ctx, s); err != nil {
+		return err
+	}
+
 	if err := r.callAfterControlPlaneInitialized(ctx, s); err != nil {
 		return err
 	}
@@ -197,6 +210,37 @@ func (r *Reconciler) callAfterHooks(ctx context.Context, s *scope.Scope) error {
 	return nil
 }
 
+func (r *Reconciler) callAfterControlPlaneEndpointSet(ctx context.Context, s *scope.Scope) error {
+	// If the cluster topology is being created then track to intent to call the AfterControlPlaneEndpointSet hook so that we can call it later.
+	if s.Current.Cluster.Spec.InfrastructureRef == nil && s.Current.Cluster.Spec.ControlPlaneRef == nil {
+		if err := hooks.MarkAsPending(ctx, r.Client, s.Current.Cluster, runtimehooksv1.AfterControlPlaneEndpointSet); err != nil {
+			return err
+		}
+	}
+
+	// Call the hook only if we are tracking the intent to do so. If it is not tracked it means we don't need to call the
+	// hook because we already called the hook after the control plane endpoint has been set.
+	if hooks.IsPending(runtimehooksv1.AfterControlPlaneEndpointSet, s.Current.Cluster) {
+		if s.Current.Cluster.Spec.ControlPlaneEndpoint.IsValid() {
+			// The control plane endpoint is set for the first time. Call all the registered extensions for the hook.
+			hookRequest := &runtimehooksv1.AfterControlPlaneEndpointSetRequest{
+				Cluster:              *s.Current.Cluster,
+				ControlPlaneEndpoint: s.Current.Cluster.Spec.ControlPlaneEndpoint,
+			}
+			hookResponse := &runtimehooksv1.AfterControlPlaneEndpointSetResponse{}
+			if err := r.RuntimeClient.CallAllExtensions(ctx, runtimehooksv1.AfterControlPlaneEndpointSet, s.Current.Cluster, hookRequest, hookResponse); err != nil {
+				return err
+			}
+			s.HookResponseTracker.Add(runtimehooksv1.AfterControlPlaneEndpointSet, hookResponse)
+			if err := hooks.MarkAsDone(ctx, r.Client, s.Current.Cluster, runtimehooksv1.AfterControlPlaneEndpointSet); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (r *Reconciler) callAfterControlPlaneInitialized(ctx context.Context, s *scope.Scope) error {
 	// If the cluster topology is being created then track to intent to call the AfterControlPlaneInitialized hook so that we can call it later.
 	if s.Current.Cluster.Spec.InfrastructureRef == nil && s.Current.Cluster.Spec.ControlPlaneRef == nil {
@@ -250,6 +294,9 @@ func (r *Reconciler) callAfterClusterUpgrade(ctx context.Context, s *scope.Scope
 		// - MachineDeployments are not currently rolling out
 		// - MAchineDeployments are not about to roll out
 		// - MachineDeployments are not pending an upgrade
+		// - MachinePools are not currently rolling out
+		// - MachinePools are not about to pick up the new version
+		// - MachinePools are not pending an upgrade
 
 		// Check if the control plane is upgrading.
 		cpUpgrading, err := contract.ControlPlane().IsUpgrading(s.Current.ControlPlane.Object)
@@ -269,7 +316,10 @@ func (r *Reconciler) callAfterClusterUpgrade(ctx context.Context, s *scope.Scope
 
 		if !cpUpgrading && !cpScaling && !s.UpgradeTracker.ControlPlane.PendingUpgrade && // Control Plane checks
 			len(s.UpgradeTracker.MachineDeployments.RolloutNames()) == 0 && // Machine deployments are not rollout out or not about to roll out
-			!s.UpgradeTracker.MachineDeployments.PendingUpgrade() { // Machine Deployments are not pending an upgrade
+			!s.UpgradeTracker.MachineDeployments.PendingUpgrade() && // Machine Deployments are not pending an upgrade
+			!s.Current.MachinePools.IsAnyRollingOut() && // Machine pools are not rolling out
+			len(s.UpgradeTracker.MachinePools.UpgradingNames()) == 0 && // Machine pools are not about to pick up the new version
+			!s.UpgradeTracker.MachinePools.PendingUpgrade() { // Machine pools are not pending an upgrade
 			// Everything is stable and the cluster can be considered fully upgraded.
 			hookRequest := &runtimehooksv1.AfterClusterUpgradeRequest{
 				Cluster:           *s.Current.Cluster,
@@ -298,18 +348,29 @@ func (r *Reconciler) reconcileInfrastructureCluster(ctx context.Context, s *scop
 	if err != nil {
 		return errors.Wrap(err, "failed to calculate ignore paths")
 	}
+	clusterClassIgnorePaths, err := s.Blueprint.IgnorePaths()
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate ignore paths from %s", tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
 
 	return r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{
-		cluster:     s.Current.Cluster,
-		current:     s.Current.InfrastructureCluster,
-		desired:     s.Desired.InfrastructureCluster,
-		ignorePaths: ignorePaths,
+		cluster:             s.Current.Cluster,
+		current:             s.Current.InfrastructureCluster,
+		desired:             s.Desired.InfrastructureCluster,
+		ignorePaths:         ignorePaths,
+		ignorePathsOnUpdate: clusterClassIgnorePaths,
+		driftTracker:        s.DriftTracker,
 	})
 }
 
 // reconcileControlPlane works to bring the current state of a managed topology in line with the desired state. This involves
 // updating the cluster where needed.
 func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope) error {
+	ignorePaths, err := s.Blueprint.IgnorePaths()
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate ignore paths from %s", tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+
 	// If the clusterClass mandates the controlPlane has infrastructureMachines, reconcile it.
 	if s.Blueprint.HasControlPlaneInfrastructureMachine() {
 		ctx, _ := tlog.LoggerFrom(ctx).WithObject(s.Desired.ControlPlane.InfrastructureMachineTemplate).Into(ctx)
@@ -327,6 +388,7 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope)
 			desired:              s.Desired.ControlPlane.InfrastructureMachineTemplate,
 			compatibilityChecker: check.ObjectsAreCompatible,
 			templateNamePrefix:   controlPlaneInfrastructureMachineTemplateNamePrefix(s.Current.Cluster.Name),
+			ignorePathsOnUpdate:  ignorePaths,
 		},
 		); err != nil {
 			return err
@@ -339,13 +401,44 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope)
 		}
 	}
 
+	// If the clusterClass defines infrastructureMachines for the controlPlane in specific failure domains, reconcile them.
+	if len(s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates) > 0 {
+		for _, failureDomain := range sortedFailureDomains(s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates) {
+			desiredTemplate := s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain]
+			ctx, _ := tlog.LoggerFrom(ctx).WithObject(desiredTemplate).Into(ctx)
+
+			// Create or update the MachineInfrastructureTemplate of the control plane for the failure domain.
+			if err := r.reconcileReferencedTemplate(ctx, reconcileReferencedTemplateInput{
+				cluster:                   s.Current.Cluster,
+				ref:                       contract.ObjToRef(desiredTemplate),
+				current:                   s.Current.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain],
+				desired:                   desiredTemplate,
+				compatibilityChecker:      check.ObjectsAreCompatible,
+				templateNamePrefix:        controlPlaneInfrastructureMachineTemplateNamePrefix(s.Current.Cluster.Name),
+				templateNameDiscriminator: controlPlaneFailureDomainInfrastructureMachineTemplateNameDiscriminator(failureDomain),
+				ignorePathsOnUpdate:       ignorePaths,
+			},
+			); err != nil {
+				return err
+			}
+		}
+
+		// The controlPlaneObject.Spec.machineTemplate.failureDomainInfrastructureRefs has to be updated in the desired object,
+		// given that the templates could have been rotated.
+		if err := contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Set(s.Desired.ControlPlane.Object, s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates); err != nil {
+			return errors.Wrapf(err, "failed to reconcile %s", tlog.KObj{Obj: s.Desired.ControlPlane.Object})
+		}
+	}
+
 	// Create or update the ControlPlaneObject for the ControlPlaneState.
 	ctx, _ = tlog.LoggerFrom(ctx).WithObject(s.Desired.ControlPlane.Object).Into(ctx)
 	if err := r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{
-		cluster:       s.Current.Cluster,
-		current:       s.Current.ControlPlane.Object,
-		desired:       s.Desired.ControlPlane.Object,
-		versionGetter: contract.ControlPlane().Version().Get,
+		cluster:             s.Current.Cluster,
+		current:             s.Current.ControlPlane.Object,
+		desired:             s.Desired.ControlPlane.Object,
+		versionGetter:       contract.ControlPlane().Version().Get,
+		ignorePathsOnUpdate: ignorePaths,
+		driftTracker:        s.DriftTracker,
 	}); err != nil {
 		return err
 	}
@@ -364,6 +457,23 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope)
 		}
 	}
 
+	// If the InfrastructureMachineTemplates for specific failure domains have changed on this reconcile or are not
+	// used anymore, delete the old templates.
+	// This is a best effort deletion only and may leak templates if an error occurs during reconciliation.
+	for _, failureDomain := range sortedFailureDomains(s.Current.ControlPlane.FailureDomainInfrastructureMachineTemplates) {
+		currentTemplate := s.Current.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain]
+		if desiredTemplate, ok := s.Desired.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain]; ok && desiredTemplate.GetName() == currentTemplate.GetName() {
+			continue
+		}
+		if err := r.Client.Delete(ctx, currentTemplate); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete old infrastructure machine template %s for failure domain %q of control plane %s",
+				tlog.KObj{Obj: currentTemplate},
+				failureDomain,
+				tlog.KObj{Obj: s.Current.ControlPlane.Object},
+			)
+		}
+	}
+
 	// If the ControlPlane has defined a current or desired MachineHealthCheck attempt to reconcile it.
 	if s.Desired.ControlPlane.MachineHealthCheck != nil || s.Current.ControlPlane.MachineHealthCheck != nil {
 		// Reconcile the current and desired state of the MachineHealthCheck.
@@ -374,6 +484,16 @@ func (r *Reconciler) reconcileControlPlane(ctx context.Context, s *scope.Scope)
 	return nil
 }
 
+// sortedFailureDomains returns the failure domains of a map of templates indexed by failure domain, sorted alphabetically.
+func sortedFailureDomains(templates map[string]*unstructured.Unstructured) []string {
+	failureDomains := make([]string, 0, len(templates))
+	for failureDomain := range templates {
+		failureDomains = append(failureDomains, failureDomain)
+	}
+	sort.Strings(failureDomains)
+	return failureDomains
+}
+
 // reconcileMachineHealthCheck creates, updates, deletes or leaves untouched a MachineHealthCheck depending on the difference between the
 // current state and the desired state.
 func (r *Reconciler) reconcileMachineHealthCheck(ctx context.Context, current, desired *clusterv1.MachineHealthCheck) error {
@@ -453,58 +573,80 @@ func (r *Reconciler) reconcileCluster(ctx context.Context, s *scope.Scope) error
 	return nil
 }
 
-// reconcileMachineDeployments reconciles the desired state of the MachineDeployment objects.
-func (r *Reconciler) reconcileMachineDeployments(ctx context.Context, s *scope.Scope) error {
+// addMachineDeploymentsToApplyGraph adds to the apply graph the steps reconciling the desired state of the MachineDeployment objects.
+// NOTE: Each MachineDeployment is created, updated or deleted independently of the others.
+func (r *Reconciler) addMachineDeploymentsToApplyGraph(s *scope.Scope, g *applyGraph) error {
 	diff := calculateMachineDeploymentDiff(s.Current.MachineDeployments, s.Desired.MachineDeployments)
 
+	ignorePaths, err := s.Blueprint.IgnorePaths()
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate ignore paths from %s", tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+
 	// Create MachineDeployments.
 	for _, mdTopologyName := range diff.toCreate {
+		mdTopologyName := mdTopologyName
 		md := s.Desired.MachineDeployments[mdTopologyName]
-		if err := r.createMachineDeployment(ctx, s.Current.Cluster, md); err != nil {
-			return err
-		}
+		g.Add(machineDeploymentApplyStep(mdTopologyName), func(ctx context.Context) error {
+			return r.createMachineDeployment(ctx, s.Current.Cluster, mdTopologyName, md, ignorePaths)
+		})
 	}
 
 	// Update MachineDeployments.
 	for _, mdTopologyName := range diff.toUpdate {
+		mdTopologyName := mdTopologyName
 		currentMD := s.Current.MachineDeployments[mdTopologyName]
 		desiredMD := s.Desired.MachineDeployments[mdTopologyName]
-		if err := r.updateMachineDeployment(ctx, s.Current.Cluster, mdTopologyName, currentMD, desiredMD); err != nil {
-			return err
-		}
+		g.Add(machineDeploymentApplyStep(mdTopologyName), func(ctx context.Context) error {
+			return r.updateMachineDeployment(ctx, s, mdTopologyName, currentMD, desiredMD, ignorePaths)
+		})
 	}
 
 	// Delete MachineDeployments.
 	for _, mdTopologyName := range diff.toDelete {
 		md := s.Current.MachineDeployments[mdTopologyName]
-		if err := r.deleteMachineDeployment(ctx, s.Current.Cluster, md); err != nil {
-			return err
-		}
+		g.Add(machineDeploymentApplyStep(mdTopologyName), func(ctx context.Context) error {
+			return r.deleteMachineDeployment(ctx, s.Current.Cluster, md)
+		})
 	}
 	return nil
 }
 
+// machineDeploymentApplyStep returns the name of the apply step reconciling a MachineDeployment.
+func machineDeploymentApplyStep(mdTopologyName string) string {
+	return fmt.Sprintf("MachineDeployment/%s", mdTopologyName)
+}
+
 // createMachineDeployment creates a MachineDeployment and the corresponding Templates.
-func (r *Reconciler) createMachineDeployment(ctx context.Context, cluster *clusterv1.Cluster, md *scope.MachineDeploymentState) error {
+func (r *Reconciler) createMachineDeployment(ctx context.Context, cluster *clusterv1.Cluster, mdTopologyName string, md *scope.MachineDeploymentState, ignorePaths []contract.Path) error {
 	log := tlog.LoggerFrom(ctx).WithMachineDeployment(md.Object)
 
 	infraCtx, _ := log.WithObject(md.InfrastructureMachineTemplate).Into(ctx)
 	if err := r.reconcileReferencedTemplate(infraCtx, reconcileReferencedTemplateInput{
-		cluster: cluster,
-		desired: md.InfrastructureMachineTemplate,
+		cluster:             cluster,
+		ref:                 &md.Object.Spec.Template.Spec.InfrastructureRef,
+		desired:             md.InfrastructureMachineTemplate,
+		templateNamePrefix:  infrastructureMachineTemplateNamePrefix(cluster.Name, mdTopologyName),
+		ignorePathsOnUpdate: ignorePaths,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to create %s", md.Object.Kind)
 	}
 
 	bootstrapCtx, _ := log.WithObject(md.BootstrapTemplate).Into(ctx)
 	if err := r.reconcileReferencedTemplate(bootstrapCtx, reconcileReferencedTemplateInput{
-		cluster: cluster,
-		desired: md.BootstrapTemplate,
+		cluster:             cluster,
+		ref:                 md.Object.Spec.Template.Spec.Bootstrap.ConfigRef,
+		desired:             md.BootstrapTemplate,
+		templateNamePrefix:  bootstrapTemplateNamePrefix(cluster.Name, mdTopologyName),
+		ignorePathsOnUpdate: ignorePaths,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to create %s", md.Object.Kind)
 	}
 
 	log = log.WithObject(md.Object)
+	if err := setDesiredStateHash(cluster, md.Object); err != nil {
+		return err
+	}
 	log.Infof(fmt.Sprintf("Creating %s", tlog.KObj{Obj: md.Object}))
 	helper, err := r.patchHelperFactory(ctx, nil, md.Object)
 	if err != nil {
@@ -525,8 +667,9 @@ func (r *Reconciler) createMachineDeployment(ctx context.Context, cluster *clust
 }
 
 // updateMachineDeployment updates a MachineDeployment. Also rotates the corresponding Templates if necessary.
-func (r *Reconciler) updateMachineDeployment(ctx context.Context, cluster *clusterv1.Cluster, mdTopologyName string, currentMD, desiredMD *scope.MachineDeploymentState) error {
+func (r *Reconciler) updateMachineDeployment(ctx context.Context, s *scope.Scope, mdTopologyName string, currentMD, desiredMD *scope.MachineDeploymentState, ignorePaths []contract.Path) error {
 	log := tlog.LoggerFrom(ctx).WithMachineDeployment(desiredMD.Object)
+	cluster := s.Current.Cluster
 
 	infraCtx, _ := log.WithObject(desiredMD.InfrastructureMachineTemplate).Into(ctx)
 	if err := r.reconcileReferencedTemplate(infraCtx, reconcileReferencedTemplateInput{
@@ -536,6 +679,7 @@ func (r *Reconciler) updateMachineDeployment(ctx context.Context, cluster *clust
 		desired:              desiredMD.InfrastructureMachineTemplate,
 		templateNamePrefix:   infrastructureMachineTemplateNamePrefix(cluster.Name, mdTopologyName),
 		compatibilityChecker: check.ObjectsAreCompatible,
+		ignorePathsOnUpdate:  ignorePaths,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to reconcile %s", tlog.KObj{Obj: currentMD.Object})
 	}
@@ -548,6 +692,7 @@ func (r *Reconciler) updateMachineDeployment(ctx context.Context, cluster *clust
 		desired:              desiredMD.BootstrapTemplate,
 		templateNamePrefix:   bootstrapTemplateNamePrefix(cluster.Name, mdTopologyName),
 		compatibilityChecker: check.ObjectsAreInTheSameNamespace,
+		ignorePathsOnUpdate:  ignorePaths,
 	}); err != nil {
 		return errors.Wrapf(err, "failed to reconcile %s", tlog.KObj{Obj: currentMD.Object})
 	}
@@ -561,6 +706,9 @@ func (r *Reconciler) updateMachineDeployment(ctx context.Context, cluster *clust
 
 	// Check differences between current and desired MachineDeployment, and eventually patch the current object.
 	log = log.WithObject(desiredMD.Object)
+	if err := setDesiredStateHash(cluster, desiredMD.Object); err != nil {
+		return err
+	}
 	patchHelper, err := r.patchHelperFactory(ctx, currentMD.Object, desiredMD.Object)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: currentMD.Object})
@@ -569,6 +717,9 @@ func (r *Reconciler) updateMachineDeployment(ctx context.Context, cluster *clust
 		log.V(3).Infof("No changes for %s", tlog.KObj{Obj: currentMD.Object})
 		return nil
 	}
+	if r.reconcileDrift(ctx, cluster, s.DriftTracker, currentMD.Object, desiredMD.Object, patchHelper) {
+		return nil
+	}
 
 	log.Infof("Patching %s", tlog.KObj{Obj: currentMD.Object})
 	if err := patchHelper.Patch(ctx); err != nil {
@@ -635,6 +786,188 @@ func calculateMachineDeploymentDiff(current, desired map[string]*scope.MachineDe
 	return diff
 }
 
+// addMachinePoolsToApplyGraph adds to the apply graph the steps reconciling the desired state of the MachinePool objects.
+// NOTE: Each MachinePool is created, updated or deleted independently of the others.
+func (r *Reconciler) addMachinePoolsToApplyGraph(s *scope.Scope, g *applyGraph) error {
+	diff := calculateMachinePoolDiff(s.Current.MachinePools, s.Desired.MachinePools)
+
+	ignorePaths, err := s.Blueprint.IgnorePaths()
+	if err != nil {
+		return errors.Wrapf(err, "failed to calculate ignore paths from %s", tlog.KObj{Obj: s.Blueprint.ClusterClass})
+	}
+
+	// Create MachinePools.
+	for _, mpTopologyName := range diff.toCreate {
+		mp := s.Desired.MachinePools[mpTopologyName]
+		g.Add(machinePoolApplyStep(mpTopologyName), func(ctx context.Context) error {
+			return r.createMachinePool(ctx, s.Current.Cluster, mp, ignorePaths)
+		})
+	}
+
+	// Update MachinePools.
+	for _, mpTopologyName := range diff.toUpdate {
+		currentMP := s.Current.MachinePools[mpTopologyName]
+		desiredMP := s.Desired.MachinePools[mpTopologyName]
+		g.Add(machinePoolApplyStep(mpTopologyName), func(ctx context.Context) error {
+			return r.updateMachinePool(ctx, s, currentMP, desiredMP, ignorePaths)
+		})
+	}
+
+	// Delete MachinePools.
+	for _, mpTopologyName := range diff.toDelete {
+		mp := s.Current.MachinePools[mpTopologyName]
+		g.Add(machinePoolApplyStep(mpTopologyName), func(ctx context.Context) error {
+			return r.deleteMachinePool(ctx, s.Current.Cluster, mp)
+		})
+	}
+	return nil
+}
+
+// machinePoolApplyStep returns the name of the apply step reconciling a MachinePool.
+func machinePoolApplyStep(mpTopologyName string) string {
+	return fmt.Sprintf("MachinePool/%s", mpTopologyName)
+}
+
+// createMachinePool creates a MachinePool and the corresponding bootstrap config and infrastructure machine pool.
+func (r *Reconciler) createMachinePool(ctx context.Context, cluster *clusterv1.Cluster, mp *scope.MachinePoolState, ignorePaths []contract.Path) error {
+	log := tlog.LoggerFrom(ctx).WithMachinePool(mp.Object)
+
+	infraCtx, _ := log.WithObject(mp.InfrastructureMachinePoolObject).Into(ctx)
+	if err := r.reconcileReferencedObject(infraCtx, reconcileReferencedObjectInput{
+		cluster:             cluster,
+		desired:             mp.InfrastructureMachinePoolObject,
+		ignorePathsOnUpdate: ignorePaths,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create %s", mp.Object.Kind)
+	}
+
+	bootstrapCtx, _ := log.WithObject(mp.BootstrapObject).Into(ctx)
+	if err := r.reconcileReferencedObject(bootstrapCtx, reconcileReferencedObjectInput{
+		cluster:             cluster,
+		desired:             mp.BootstrapObject,
+		ignorePathsOnUpdate: ignorePaths,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to create %s", mp.Object.Kind)
+	}
+
+	log = log.WithObject(mp.Object)
+	if err := setDesiredStateHash(cluster, mp.Object); err != nil {
+		return err
+	}
+	log.Infof(fmt.Sprintf("Creating %s", tlog.KObj{Obj: mp.Object}))
+	helper, err := r.patchHelperFactory(ctx, nil, mp.Object)
+	if err != nil {
+		return createErrorWithoutObjectName(ctx, err, mp.Object)
+	}
+	if err := helper.Patch(ctx); err != nil {
+		return createErrorWithoutObjectName(ctx, err, mp.Object)
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, createEventReason, "Created %q", tlog.KObj{Obj: mp.Object})
+	return nil
+}
+
+// updateMachinePool updates a MachinePool and patches in place the corresponding bootstrap config and
+// infrastructure machine pool if necessary.
+func (r *Reconciler) updateMachinePool(ctx context.Context, s *scope.Scope, currentMP, desiredMP *scope.MachinePoolState, ignorePaths []contract.Path) error {
+	log := tlog.LoggerFrom(ctx).WithMachinePool(desiredMP.Object)
+	cluster := s.Current.Cluster
+
+	infraCtx, _ := log.WithObject(desiredMP.InfrastructureMachinePoolObject).Into(ctx)
+	if err := r.reconcileReferencedObject(infraCtx, reconcileReferencedObjectInput{
+		cluster:             cluster,
+		current:             currentMP.InfrastructureMachinePoolObject,
+		desired:             desiredMP.InfrastructureMachinePoolObject,
+		ignorePathsOnUpdate: ignorePaths,
+		driftTracker:        s.DriftTracker,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to reconcile %s", tlog.KObj{Obj: currentMP.Object})
+	}
+
+	bootstrapCtx, _ := log.WithObject(desiredMP.BootstrapObject).Into(ctx)
+	if err := r.reconcileReferencedObject(bootstrapCtx, reconcileReferencedObjectInput{
+		cluster:             cluster,
+		current:             currentMP.BootstrapObject,
+		desired:             desiredMP.BootstrapObject,
+		ignorePathsOnUpdate: ignorePaths,
+		driftTracker:        s.DriftTracker,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to reconcile %s", tlog.KObj{Obj: currentMP.Object})
+	}
+
+	// Check differences between current and desired MachinePool, and eventually patch the current object.
+	log = log.WithObject(desiredMP.Object)
+	if err := setDesiredStateHash(cluster, desiredMP.Object); err != nil {
+		return err
+	}
+	patchHelper, err := r.patchHelperFactory(ctx, currentMP.Object, desiredMP.Object)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: currentMP.Object})
+	}
+	if !patchHelper.HasChanges() {
+		log.V(3).Infof("No changes for %s", tlog.KObj{Obj: currentMP.Object})
+		return nil
+	}
+	if r.reconcileDrift(ctx, cluster, s.DriftTracker, currentMP.Object, desiredMP.Object, patchHelper) {
+		return nil
+	}
+
+	log.Infof("Patching %s", tlog.KObj{Obj: currentMP.Object})
+	if err := patchHelper.Patch(ctx); err != nil {
+		return errors.Wrapf(err, "failed to patch %s", tlog.KObj{Obj: currentMP.Object})
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, updateEventReason, "Updated %q%s", tlog.KObj{Obj: currentMP.Object}, logMachinePoolVersionChange(currentMP.Object, desiredMP.Object))
+	return nil
+}
+
+func logMachinePoolVersionChange(current, desired *expv1.MachinePool) string {
+	if current.Spec.Template.Spec.Version == nil || desired.Spec.Template.Spec.Version == nil {
+		return ""
+	}
+
+	if *current.Spec.Template.Spec.Version != *desired.Spec.Template.Spec.Version {
+		return fmt.Sprintf(" with version change from %s to %s", *current.Spec.Template.Spec.Version, *desired.Spec.Template.Spec.Version)
+	}
+	return ""
+}
+
+// deleteMachinePool deletes a MachinePool.
+// NOTE: The bootstrap config and the infrastructure machine pool are deleted by the MachinePool controller.
+func (r *Reconciler) deleteMachinePool(ctx context.Context, cluster *clusterv1.Cluster, mp *scope.MachinePoolState) error {
+	log := tlog.LoggerFrom(ctx).WithMachinePool(mp.Object).WithObject(mp.Object)
+	log.Infof("Deleting %s", tlog.KObj{Obj: mp.Object})
+	if err := r.Client.Delete(ctx, mp.Object); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete %s", tlog.KObj{Obj: mp.Object})
+	}
+	r.recorder.Eventf(cluster, corev1.EventTypeNormal, deleteEventReason, "Deleted %q", tlog.KObj{Obj: mp.Object})
+	return nil
+}
+
+type machinePoolDiff struct {
+	toCreate, toUpdate, toDelete []string
+}
+
+// calculateMachinePoolDiff compares two maps of MachinePoolState and calculates which
+// MachinePools should be created, updated or deleted.
+func calculateMachinePoolDiff(current, desired map[string]*scope.MachinePoolState) machinePoolDiff {
+	var diff machinePoolDiff
+
+	for mp := range desired {
+		if _, ok := current[mp]; ok {
+			diff.toUpdate = append(diff.toUpdate, mp)
+		} else {
+			diff.toCreate = append(diff.toCreate, mp)
+		}
+	}
+
+	for mp := range current {
+		if _, ok := desired[mp]; !ok {
+			diff.toDelete = append(diff.toDelete, mp)
+		}
+	}
+
+	return diff
+}
+
 type unstructuredVersionGetter func(obj *unstructured.Unstructured) (*string, error)
 
 type reconcileReferencedObjectInput struct {
@@ -643,6 +976,10 @@ type reconcileReferencedObjectInput struct {
 	desired       *unstructured.Unstructured
 	versionGetter unstructuredVersionGetter
 	ignorePaths   []contract.Path
+	// ignorePathsOnUpdate are ignored only when patching an existing object, e.g. the paths
+	// defined in the ClusterClass.
+	ignorePathsOnUpdate []contract.Path
+	driftTracker        *scope.DriftTracker
 }
 
 // reconcileReferencedObject reconciles the desired state of the referenced object.
@@ -651,10 +988,14 @@ type reconcileReferencedObjectInput struct {
 func (r *Reconciler) reconcileReferencedObject(ctx context.Context, in reconcileReferencedObjectInput) error {
 	log := tlog.LoggerFrom(ctx)
 
+	if err := setDesiredStateHash(in.cluster, in.desired); err != nil {
+		return err
+	}
+
 	// If there is no current object, create it.
 	if in.current == nil {
 		log.Infof("Creating %s", tlog.KObj{Obj: in.desired})
-		helper, err := r.patchHelperFactory(ctx, nil, in.desired, structuredmerge.IgnorePaths(in.ignorePaths))
+		helper, err := r.patchHelperFactory(ctx, nil, in.desired, structuredmerge.IgnorePaths(in.ignorePaths), structuredmerge.IgnorePathsOnUpdate(in.ignorePathsOnUpdate))
 		if err != nil {
 			return errors.Wrap(createErrorWithoutObjectName(ctx, err, in.desired), "failed to create patch helper")
 		}
@@ -670,13 +1011,35 @@ func (r *Reconciler) reconcileReferencedObject(ctx context.Context, in reconcile
 		return allErrs.ToAggregate()
 	}
 
+	// Skip computing the changes if neither the current nor the desired object changed since the object
+	// was last found without changes to be applied.
+	clusterKey := client.ObjectKeyFromObject(in.cluster)
+	var objectHash string
+	if r.reconcileCache != nil {
+		hash, err := computeObjectHash(in.current, in.desired, in.ignorePaths, in.ignorePathsOnUpdate)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compute hash for %s", tlog.KObj{Obj: in.current})
+		}
+		if r.reconcileCache.HasObject(clusterKey, in.current.GetUID(), hash) {
+			log.V(3).Infof("No changes for %s since it was last reconciled", tlog.KObj{Obj: in.desired})
+			return nil
+		}
+		objectHash = hash
+	}
+
 	// Check differences between current and desired state, and eventually patch the current object.
-	patchHelper, err := r.patchHelperFactory(ctx, in.current, in.desired, structuredmerge.IgnorePaths(in.ignorePaths))
+	patchHelper, err := r.patchHelperFactory(ctx, in.current, in.desired, structuredmerge.IgnorePaths(in.ignorePaths), structuredmerge.IgnorePathsOnUpdate(in.ignorePathsOnUpdate))
 	if err != nil {
 		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: in.current})
 	}
 	if !patchHelper.HasChanges() {
 		log.V(3).Infof("No changes for %s", tlog.KObj{Obj: in.desired})
+		if objectHash != "" {
+			r.reconcileCache.SetObject(clusterKey, in.current.GetUID(), objectHash)
+		}
+		return nil
+	}
+	if in.driftTracker != nil && r.reconcileDrift(ctx, in.cluster, in.driftTracker, in.current, in.desired, patchHelper) {
 		return nil
 	}
 
@@ -709,12 +1072,17 @@ func logUnstructuredVersionChange(current, desired *unstructured.Unstructured, v
 }
 
 type reconcileReferencedTemplateInput struct {
-	cluster              *clusterv1.Cluster
-	ref                  *corev1.ObjectReference
-	current              *unstructured.Unstructured
-	desired              *unstructured.Unstructured
-	templateNamePrefix   string
-	compatibilityChecker func(current, desired client.Object) field.ErrorList
+	cluster            *clusterv1.Cluster
+	ref                *corev1.ObjectReference
+	current            *unstructured.Unstructured
+	desired            *unstructured.Unstructured
+	templateNamePrefix string
+	// templateNameDiscriminator is an optional discriminator for the name of the template, see templateNameWithHash.
+	templateNameDiscriminator string
+	compatibilityChecker      func(current, desired client.Object) field.ErrorList
+	// ignorePathsOnUpdate are ignored only when checking differences between current and desired objects,
+	// so templates (including the ones created by a template rotation) are always created with all the fields.
+	ignorePathsOnUpdate []contract.Path
 }
 
 // reconcileReferencedTemplate reconciles the desired state of a referenced Template.
@@ -730,8 +1098,21 @@ func (r *Reconciler) reconcileReferencedTemplate(ctx context.Context, in reconci
 
 	// If there is no current object, create the desired object.
 	if in.current == nil {
+		// Ensure the name of the new template includes a hash of its final content, i.e. after patches have been applied.
+		// NOTE: Updating the object hosting the reference to the template is executed outside this func.
+		if in.templateNamePrefix != "" {
+			name, err := templateNameWithHash(in.templateNamePrefix, in.templateNameDiscriminator, in.desired)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s", tlog.KObj{Obj: in.desired})
+			}
+			in.desired.SetName(name)
+			if in.ref != nil {
+				in.ref.Name = name
+			}
+		}
+
 		log.Infof("Creating %s", tlog.KObj{Obj: in.desired})
-		helper, err := r.patchHelperFactory(ctx, nil, in.desired)
+		helper, err := r.patchHelperFactory(ctx, nil, in.desired, structuredmerge.IgnorePathsOnUpdate(in.ignorePathsOnUpdate))
 		if err != nil {
 			return errors.Wrap(createErrorWithoutObjectName(ctx, err, in.desired), "failed to create patch helper")
 		}
@@ -752,7 +1133,7 @@ func (r *Reconciler) reconcileReferencedTemplate(ctx context.Context, in reconci
 	}
 
 	// Check differences between current and desired objects, and if there are changes eventually start the template rotation.
-	patchHelper, err := r.patchHelperFactory(ctx, in.current, in.desired)
+	patchHelper, err := r.patchHelperFactory(ctx, in.current, in.desired, structuredmerge.IgnorePathsOnUpdate(in.ignorePathsOnUpdate))
 	if err != nil {
 		return errors.Wrapf(err, "failed to create patch helper for %s", tlog.KObj{Obj: in.current})
 	}
@@ -777,13 +1158,22 @@ func (r *Reconciler) reconcileReferencedTemplate(ctx context.Context, in reconci
 	// Create the new template.
 
 	// NOTE: it is required to assign a new name, because during compute the desired object name is enforced to be equal to the current one.
+	// The new name includes a hash of the content of the desired template; if it is equal to the current name, the
+	// current template has been changed after creation (the dry-run above detected spec changes), so a random suffix
+	// is used instead to create a new template.
 	// TODO: find a way to make side effect more explicit
-	newName := names.SimpleNameGenerator.GenerateName(in.templateNamePrefix)
+	newName, err := templateNameWithHash(in.templateNamePrefix, in.templateNameDiscriminator, in.desired)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rotate %s", tlog.KObj{Obj: in.current})
+	}
+	if newName == in.current.GetName() {
+		newName = names.SimpleNameGenerator.GenerateName(in.templateNamePrefix)
+	}
 	in.desired.SetName(newName)
 
 	log.Infof("Rotating %s, new name %s", tlog.KObj{Obj: in.current}, newName)
 	log.Infof("Creating %s", tlog.KObj{Obj: in.desired})
-	helper, err := r.patchHelperFactory(ctx, nil, in.desired)
+	helper, err := r.patchHelperFactory(ctx, nil, in.desired, structuredmerge.IgnorePathsOnUpdate(in.ignorePathsOnUpdate))
 	if err != nil {
 		return errors.Wrap(createErrorWithoutObjectName(ctx, err, in.desired), "failed to create patch helper")
 	}
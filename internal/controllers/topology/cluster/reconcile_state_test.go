@@ -17,6 +17,7 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -32,6 +33,8 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -281,6 +284,165 @@ func TestReconcileShim(t *testing.T) {
 	})
 }
 
+func TestReconcile_callAfterControlPlaneEndpointSet(t *testing.T) {
+	catalog := runtimecatalog.New()
+	_ = runtimehooksv1.AddToCatalog(catalog)
+
+	afterControlPlaneEndpointSetGVH, err := catalog.GroupVersionHook(runtimehooksv1.AfterControlPlaneEndpointSet)
+	if err != nil {
+		panic(err)
+	}
+
+	successResponse := &runtimehooksv1.AfterControlPlaneEndpointSetResponse{
+		CommonResponse: runtimehooksv1.CommonResponse{
+			Status: runtimehooksv1.ResponseStatusSuccess,
+		},
+	}
+	failureResponse := &runtimehooksv1.AfterControlPlaneEndpointSetResponse{
+		CommonResponse: runtimehooksv1.CommonResponse{
+			Status: runtimehooksv1.ResponseStatusFailure,
+		},
+	}
+	endpoint := clusterv1.APIEndpoint{Host: "api.example.com", Port: 6443}
+
+	tests := []struct {
+		name               string
+		cluster            *clusterv1.Cluster
+		hookResponse       *runtimehooksv1.AfterControlPlaneEndpointSetResponse
+		wantMarked         bool
+		wantHookToBeCalled bool
+		wantError          bool
+	}{
+		{
+			name: "hook should be marked if the cluster is about to be created",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-ns",
+				},
+				Spec: clusterv1.ClusterSpec{},
+			},
+			hookResponse:       successResponse,
+			wantMarked:         true,
+			wantHookToBeCalled: false,
+			wantError:          false,
+		},
+		{
+			name: "hook should be called if it is marked and the control plane endpoint is set - the hook should become unmarked for a success response",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-ns",
+					Annotations: map[string]string{
+						runtimev1.PendingHooksAnnotation: "AfterControlPlaneEndpointSet",
+					},
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef:      &corev1.ObjectReference{},
+					InfrastructureRef:    &corev1.ObjectReference{},
+					ControlPlaneEndpoint: endpoint,
+				},
+			},
+			hookResponse:       successResponse,
+			wantMarked:         false,
+			wantHookToBeCalled: true,
+			wantError:          false,
+		},
+		{
+			name: "hook should be called if it is marked and the control plane endpoint is set - the hook should remain marked for a failure response",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-ns",
+					Annotations: map[string]string{
+						runtimev1.PendingHooksAnnotation: "AfterControlPlaneEndpointSet",
+					},
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef:      &corev1.ObjectReference{},
+					InfrastructureRef:    &corev1.ObjectReference{},
+					ControlPlaneEndpoint: endpoint,
+				},
+			},
+			hookResponse:       failureResponse,
+			wantMarked:         true,
+			wantHookToBeCalled: true,
+			wantError:          true,
+		},
+		{
+			name: "hook should not be called if it is marked and the control plane endpoint is not set - the hook should remain marked",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-ns",
+					Annotations: map[string]string{
+						runtimev1.PendingHooksAnnotation: "AfterControlPlaneEndpointSet",
+					},
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef:   &corev1.ObjectReference{},
+					InfrastructureRef: &corev1.ObjectReference{},
+				},
+			},
+			hookResponse:       successResponse,
+			wantMarked:         true,
+			wantHookToBeCalled: false,
+			wantError:          false,
+		},
+		{
+			name: "hook should not be called if it is not marked",
+			cluster: &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cluster",
+					Namespace: "test-ns",
+				},
+				Spec: clusterv1.ClusterSpec{
+					ControlPlaneRef:      &corev1.ObjectReference{},
+					InfrastructureRef:    &corev1.ObjectReference{},
+					ControlPlaneEndpoint: endpoint,
+				},
+			},
+			hookResponse:       successResponse,
+			wantMarked:         false,
+			wantHookToBeCalled: false,
+			wantError:          false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			s := &scope.Scope{
+				Current: &scope.ClusterState{
+					Cluster: tt.cluster,
+				},
+				HookResponseTracker: scope.NewHookResponseTracker(),
+			}
+
+			fakeRuntimeClient := fakeruntimeclient.NewRuntimeClientBuilder().
+				WithCallAllExtensionResponses(map[runtimecatalog.GroupVersionHook]runtimehooksv1.ResponseObject{
+					afterControlPlaneEndpointSetGVH: tt.hookResponse,
+				}).
+				WithCatalog(catalog).
+				Build()
+
+			fakeClient := fake.NewClientBuilder().WithObjects(tt.cluster).Build()
+
+			r := &Reconciler{
+				Client:        fakeClient,
+				APIReader:     fakeClient,
+				RuntimeClient: fakeRuntimeClient,
+			}
+
+			err := r.callAfterControlPlaneEndpointSet(ctx, s)
+			g.Expect(fakeRuntimeClient.CallAllCount(runtimehooksv1.AfterControlPlaneEndpointSet) == 1).To(Equal(tt.wantHookToBeCalled))
+			g.Expect(hooks.IsPending(runtimehooksv1.AfterControlPlaneEndpointSet, tt.cluster)).To(Equal(tt.wantMarked))
+			g.Expect(err != nil).To(Equal(tt.wantError))
+		})
+	}
+}
+
 func TestReconcile_callAfterControlPlaneInitialized(t *testing.T) {
 	catalog := runtimecatalog.New()
 	_ = runtimehooksv1.AddToCatalog(catalog)
@@ -1811,7 +1973,9 @@ func TestReconcileMachineDeployments(t *testing.T) {
 				patchHelperFactory: serverSideApplyPatchHelperFactory(env),
 				recorder:           env.GetEventRecorderFor("test"),
 			}
-			err = r.reconcileMachineDeployments(ctx, s)
+			graph := newApplyGraph()
+			g.Expect(r.addMachineDeploymentsToApplyGraph(s, graph)).To(Succeed())
+			err = graph.Run(ctx, 1)
 			if tt.wantErr {
 				g.Expect(err).To(HaveOccurred())
 				return
@@ -1898,6 +2062,55 @@ func TestReconcileMachineDeployments(t *testing.T) {
 // for a control-plane object to verify that the objects are reconciled as expected by tracking managed fields correctly.
 // NOTE: by Extension this tests validates managed field handling in mergePatches, and thus its usage in other parts of the
 // codebase.
+// fakeClientWithDeleteErr is a client failing to delete the objects with a given name.
+type fakeClientWithDeleteErr struct {
+	client.Client
+	name string
+}
+
+func (fc fakeClientWithDeleteErr) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if obj.GetName() == fc.name {
+		return apierrors.NewInternalError(errors.New("fake error"))
+	}
+	return fc.Client.Delete(ctx, obj, opts...)
+}
+
+func TestReconcileMachineDeploymentsPartialFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	md1 := builder.MachineDeployment(metav1.NamespaceDefault, "md-1").Build()
+	md2 := builder.MachineDeployment(metav1.NamespaceDefault, "md-2").Build()
+	md3 := builder.MachineDeployment(metav1.NamespaceDefault, "md-3").Build()
+
+	s := scope.New(builder.Cluster(metav1.NamespaceDefault, "cluster-1").Build())
+	s.Current.MachineDeployments = map[string]*scope.MachineDeploymentState{
+		"md-1": {Object: md1},
+		"md-2": {Object: md2},
+		"md-3": {Object: md3},
+	}
+	s.Desired = &scope.ClusterState{MachineDeployments: map[string]*scope.MachineDeploymentState{}}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(md1, md2, md3).Build()
+	r := Reconciler{
+		Client:   fakeClientWithDeleteErr{Client: fakeClient, name: "md-2"},
+		recorder: record.NewFakeRecorder(32),
+	}
+
+	graph := newApplyGraph()
+	g.Expect(r.addMachineDeploymentsToApplyGraph(s, graph)).To(Succeed())
+	err := graph.Run(ctx, 3)
+
+	// The error of the MachineDeployment which failed is returned as is.
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("failed to delete MachineDeployment/md-2"))
+	g.Expect(apierrors.IsInternalError(errors.Cause(err))).To(BeTrue())
+
+	// The other MachineDeployments are reconciled nonetheless.
+	g.Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(md1), &clusterv1.MachineDeployment{}))).To(BeTrue())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(md2), &clusterv1.MachineDeployment{})).To(Succeed())
+	g.Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(md3), &clusterv1.MachineDeployment{}))).To(BeTrue())
+}
+
 func TestReconcileReferencedObjectSequences(t *testing.T) {
 	// g := NewWithT(t)
 	// Write the config file to access the test env for debugging.
@@ -2557,8 +2770,9 @@ func TestReconcileMachineDeploymentMachineHealthCheck(t *testing.T) {
 				recorder:           env.GetEventRecorderFor("test"),
 			}
 
-			err = r.reconcileMachineDeployments(ctx, s)
-			g.Expect(err).ToNot(HaveOccurred())
+			graph := newApplyGraph()
+			g.Expect(r.addMachineDeploymentsToApplyGraph(s, graph)).To(Succeed())
+			g.Expect(graph.Run(ctx, 1)).To(Succeed())
 
 			var gotMachineHealthCheckList clusterv1.MachineHealthCheckList
 			g.Expect(env.GetAPIReader().List(ctx, &gotMachineHealthCheckList, &client.ListOptions{Namespace: namespace.GetName()})).To(Succeed())
@@ -2985,3 +3199,152 @@ func Test_createErrorWithoutObjectName(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileReferencedTemplateRotatesTemplatesChangedAfterCreation(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+	namePrefix := infrastructureMachineTemplateNamePrefix(cluster.Name, "md1")
+
+	desired := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.foo": "bar"}).
+		Build()
+	hashName, err := templateNameWithHash(namePrefix, "", desired)
+	g.Expect(err).ToNot(HaveOccurred())
+	desired.SetName(hashName)
+
+	// The current template has the name computed from the desired content, but its spec has been changed after creation.
+	current := desired.DeepCopy()
+	g.Expect(unstructured.SetNestedField(current.Object, "changed", "spec", "template", "spec", "foo")).To(Succeed())
+
+	fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(current).Build()
+	r := Reconciler{
+		Client:             fakeClient,
+		patchHelperFactory: dryRunPatchHelperFactory(fakeClient),
+		recorder:           record.NewFakeRecorder(32),
+	}
+
+	ref := contract.ObjToRef(current)
+	g.Expect(r.reconcileReferencedTemplate(ctx, reconcileReferencedTemplateInput{
+		cluster:              cluster,
+		ref:                  ref,
+		current:              current,
+		desired:              desired,
+		templateNamePrefix:   namePrefix,
+		compatibilityChecker: func(current, desired client.Object) field.ErrorList { return nil },
+	})).To(Succeed())
+
+	// The template is rotated, even if the name computed from the desired content is equal to the current name.
+	g.Expect(ref.Name).ToNot(Equal(hashName))
+	g.Expect(ref.Name).To(HavePrefix(namePrefix))
+
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(desired.GroupVersionKind())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: ref.Name}, got)).To(Succeed())
+	g.Expect(got.Object["spec"]).To(Equal(desired.Object["spec"]))
+}
+
+func TestReconcileReferencedTemplateIgnorePathsOnUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+	namePrefix := infrastructureMachineTemplateNamePrefix(cluster.Name, "md1")
+	ignorePaths := []contract.Path{{"spec", "template", "spec", "foo"}}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).Build()
+	r := Reconciler{
+		Client:             fakeClient,
+		patchHelperFactory: dryRunPatchHelperFactory(fakeClient),
+		recorder:           record.NewFakeRecorder(32),
+	}
+
+	// Create the template; ignore paths are not considered, so the template is created with all the fields.
+	desired := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.foo": "bar"}).
+		Build()
+	ref := &corev1.ObjectReference{}
+	g.Expect(r.reconcileReferencedTemplate(ctx, reconcileReferencedTemplateInput{
+		cluster:             cluster,
+		ref:                 ref,
+		desired:             desired,
+		templateNamePrefix:  namePrefix,
+		ignorePathsOnUpdate: ignorePaths,
+	})).To(Succeed())
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(desired.GroupVersionKind())
+	g.Expect(fakeClient.Get(ctx, client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: ref.Name}, current)).To(Succeed())
+	foo, _, err := unstructured.NestedString(current.Object, "spec", "template", "spec", "foo")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(foo).To(Equal("bar"))
+
+	// Change the ignored field in the desired template; ignore paths are considered for the existing template, so it is not rotated.
+	desired = current.DeepCopy()
+	g.Expect(unstructured.SetNestedField(desired.Object, "changed", "spec", "template", "spec", "foo")).To(Succeed())
+	currentName := ref.Name
+	g.Expect(r.reconcileReferencedTemplate(ctx, reconcileReferencedTemplateInput{
+		cluster:              cluster,
+		ref:                  ref,
+		current:              current,
+		desired:              desired,
+		templateNamePrefix:   namePrefix,
+		compatibilityChecker: func(current, desired client.Object) field.ErrorList { return nil },
+		ignorePathsOnUpdate:  ignorePaths,
+	})).To(Succeed())
+	g.Expect(ref.Name).To(Equal(currentName))
+}
+
+func TestReconcileReferencedObjectSkipsUnchangedObjects(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").Build()
+	infrastructureCluster := builder.InfrastructureCluster(metav1.NamespaceDefault, "infra-cluster1").
+		WithSpecFields(map[string]interface{}{"spec.foo": "bar"}).
+		Build()
+
+	fakeClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(infrastructureCluster).Build()
+	patchHelpers := 0
+	r := Reconciler{
+		Client: fakeClient,
+		patchHelperFactory: func(ctx context.Context, original, modified client.Object, opts ...structuredmerge.HelperOption) (structuredmerge.PatchHelper, error) {
+			patchHelpers++
+			return dryRunPatchHelperFactory(fakeClient)(ctx, original, modified, opts...)
+		},
+		recorder:       record.NewFakeRecorder(32),
+		reconcileCache: newReconcileCache(),
+	}
+
+	getCurrent := func() *unstructured.Unstructured {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(infrastructureCluster.GroupVersionKind())
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(infrastructureCluster), current)).To(Succeed())
+		return current
+	}
+
+	// The first time the changes are computed, and no changes are found.
+	current := getCurrent()
+	g.Expect(r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{cluster: cluster, current: current, desired: current.DeepCopy()})).To(Succeed())
+	g.Expect(patchHelpers).To(Equal(1))
+
+	// If neither the current nor the desired object changed, computing the changes is skipped.
+	g.Expect(r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{cluster: cluster, current: current, desired: current.DeepCopy()})).To(Succeed())
+	g.Expect(patchHelpers).To(Equal(1))
+
+	// If the desired object changes, the changes are computed and applied.
+	desired := current.DeepCopy()
+	g.Expect(unstructured.SetNestedField(desired.Object, "changed", "spec", "foo")).To(Succeed())
+	g.Expect(r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{cluster: cluster, current: current, desired: desired})).To(Succeed())
+	g.Expect(patchHelpers).To(Equal(2))
+
+	// If the current object changes, e.g. because it has been patched, the changes are computed again.
+	current = getCurrent()
+	g.Expect(r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{cluster: cluster, current: current, desired: desired})).To(Succeed())
+	g.Expect(patchHelpers).To(Equal(3))
+	g.Expect(r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{cluster: cluster, current: current, desired: desired})).To(Succeed())
+	g.Expect(patchHelpers).To(Equal(3))
+
+	// If the state of the Cluster topology is forgotten, the changes are computed again.
+	r.forgetReconciledState(client.ObjectKeyFromObject(cluster))
+	g.Expect(r.reconcileReferencedObject(ctx, reconcileReferencedObjectInput{cluster: cluster, current: current, desired: desired})).To(Succeed())
+	g.Expect(patchHelpers).To(Equal(4))
+}
@@ -17,9 +17,13 @@ limitations under the License.
 package scope
 
 import (
+	"sort"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
 )
 
 // ClusterBlueprint holds all the objects required for computing the desired state of a managed Cluster topology,
@@ -34,11 +38,18 @@ type ClusterBlueprint struct {
 	// InfrastructureClusterTemplate holds the InfrastructureClusterTemplate referenced from ClusterClass.
 	InfrastructureClusterTemplate *unstructured.Unstructured
 
+	// InfrastructureClusterTemplateCRDAnnotations holds the annotations of the CustomResourceDefinition of the
+	// InfrastructureClusterTemplate; they are read only if the controlPlaneLoadBalancer builtin patch is enabled.
+	InfrastructureClusterTemplateCRDAnnotations map[string]string
+
 	// ControlPlane holds the ControlPlaneBlueprint derived from ClusterClass.
 	ControlPlane *ControlPlaneBlueprint
 
 	// MachineDeployments holds the MachineDeploymentBlueprints derived from ClusterClass.
 	MachineDeployments map[string]*MachineDeploymentBlueprint
+
+	// MachinePools holds the MachinePoolBlueprints derived from ClusterClass.
+	MachinePools map[string]*MachinePoolBlueprint
 }
 
 // ControlPlaneBlueprint holds the templates required for computing the desired state of a managed control plane.
@@ -49,6 +60,10 @@ type ControlPlaneBlueprint struct {
 	// InfrastructureMachineTemplate holds the infrastructure machine template for the control plane, if defined in the ClusterClass.
 	InfrastructureMachineTemplate *unstructured.Unstructured
 
+	// FailureDomainInfrastructureMachineTemplates holds the infrastructure machine templates for the control plane
+	// in specific failure domains, if defined in the ClusterClass, indexed by failure domain.
+	FailureDomainInfrastructureMachineTemplates map[string]*unstructured.Unstructured
+
 	// MachineHealthCheck holds the MachineHealthCheckClass for this ControlPlane.
 	// +optional
 	MachineHealthCheck *clusterv1.MachineHealthCheckClass
@@ -73,11 +88,32 @@ type MachineDeploymentBlueprint struct {
 	MachineHealthCheck *clusterv1.MachineHealthCheckClass
 }
 
+// MachinePoolBlueprint holds the templates required for computing the desired state of a managed MachinePool;
+// it also holds a copy of the MachinePool metadata from the ClusterClass, thus providing all the required info
+// in a single place.
+type MachinePoolBlueprint struct {
+	// Metadata holds the metadata for a MachinePool.
+	// NOTE: This is a convenience copy of the metadata field from ClusterClass.Spec.Workers.MachinePools[x].Template.
+	Metadata clusterv1.ObjectMeta
+
+	// BootstrapTemplate holds the bootstrap template for a MachinePool referenced from ClusterClass.
+	BootstrapTemplate *unstructured.Unstructured
+
+	// InfrastructureMachinePoolTemplate holds the infrastructure machine pool template for a MachinePool referenced from ClusterClass.
+	InfrastructureMachinePoolTemplate *unstructured.Unstructured
+}
+
 // HasControlPlaneInfrastructureMachine checks whether the clusterClass mandates the controlPlane has infrastructureMachines.
 func (b *ClusterBlueprint) HasControlPlaneInfrastructureMachine() bool {
 	return b.ClusterClass.Spec.ControlPlane.MachineInfrastructure != nil && b.ClusterClass.Spec.ControlPlane.MachineInfrastructure.Ref != nil
 }
 
+// HasControlPlaneFailureDomainInfrastructureMachines checks whether the clusterClass defines infrastructureMachines
+// for the controlPlane in specific failure domains.
+func (b *ClusterBlueprint) HasControlPlaneFailureDomainInfrastructureMachines() bool {
+	return b.HasControlPlaneInfrastructureMachine() && len(b.ClusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure) > 0
+}
+
 // IsControlPlaneMachineHealthCheckEnabled returns true if a MachineHealthCheck should be created for the control plane.
 // Returns false otherwise.
 func (b *ClusterBlueprint) IsControlPlaneMachineHealthCheckEnabled() bool {
@@ -137,3 +173,96 @@ func (b *ClusterBlueprint) MachineDeploymentMachineHealthCheckClass(md *clusterv
 func (b *ClusterBlueprint) HasMachineDeployments() bool {
 	return b.Topology.Workers != nil && len(b.Topology.Workers.MachineDeployments) > 0
 }
+
+// HasMachinePools checks whether the topology has MachinePools.
+func (b *ClusterBlueprint) HasMachinePools() bool {
+	return b.Topology.Workers != nil && len(b.Topology.Workers.MachinePools) > 0
+}
+
+// IgnorePaths returns the paths the topology controller should ignore when reconciling the objects
+// generated from the ClusterClass, as defined by the ClusterTopologyIgnorePathsAnnotation on the ClusterClass.
+// NOTE: No paths are ignored if the blueprint has no ClusterClass, e.g. when reconciling a subset of the topology.
+func (b *ClusterBlueprint) IgnorePaths() ([]contract.Path, error) {
+	if b.ClusterClass == nil {
+		return nil, nil
+	}
+	value, ok := b.ClusterClass.GetAnnotations()[clusterv1.ClusterTopologyIgnorePathsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	return contract.ParsePaths(value)
+}
+
+// BuiltinPatches returns the builtin patches enabled for the ClusterClass, as defined by the
+// ClusterTopologyBuiltinPatchesAnnotation on the ClusterClass.
+func (b *ClusterBlueprint) BuiltinPatches() []string {
+	patches := []string{}
+	for _, patch := range strings.Split(b.ClusterClass.GetAnnotations()[clusterv1.ClusterTopologyBuiltinPatchesAnnotation], ",") {
+		if patch = strings.TrimSpace(patch); patch != "" {
+			patches = append(patches, patch)
+		}
+	}
+	return patches
+}
+
+// HasMachineDeploymentBootstrapOverrides checks if any MachineDeployment in the topology defines bootstrap overrides.
+func (b *ClusterBlueprint) HasMachineDeploymentBootstrapOverrides() bool {
+	if !b.HasMachineDeployments() {
+		return false
+	}
+	for _, md := range b.Topology.Workers.MachineDeployments {
+		if md.BootstrapOverrides != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Templates returns all the templates referenced by the ClusterClass.
+func (b *ClusterBlueprint) Templates() []*unstructured.Unstructured {
+	templates := []*unstructured.Unstructured{}
+	add := func(t *unstructured.Unstructured) {
+		if t != nil {
+			templates = append(templates, t)
+		}
+	}
+
+	add(b.InfrastructureClusterTemplate)
+	if b.ControlPlane != nil {
+		add(b.ControlPlane.Template)
+		add(b.ControlPlane.InfrastructureMachineTemplate)
+
+		// Sort the failure domains to always return the templates in the same order.
+		failureDomains := make([]string, 0, len(b.ControlPlane.FailureDomainInfrastructureMachineTemplates))
+		for failureDomain := range b.ControlPlane.FailureDomainInfrastructureMachineTemplates {
+			failureDomains = append(failureDomains, failureDomain)
+		}
+		sort.Strings(failureDomains)
+		for _, failureDomain := range failureDomains {
+			add(b.ControlPlane.FailureDomainInfrastructureMachineTemplates[failureDomain])
+		}
+	}
+
+	// Sort the MachineDeployment classes to always return the templates in the same order.
+	classes := make([]string, 0, len(b.MachineDeployments))
+	for class := range b.MachineDeployments {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		add(b.MachineDeployments[class].BootstrapTemplate)
+		add(b.MachineDeployments[class].InfrastructureMachineTemplate)
+	}
+
+	// Sort the MachinePool classes to always return the templates in the same order.
+	classes = make([]string, 0, len(b.MachinePools))
+	for class := range b.MachinePools {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		add(b.MachinePools[class].BootstrapTemplate)
+		add(b.MachinePools[class].InfrastructureMachinePoolTemplate)
+	}
+	return templates
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
 )
 
@@ -465,3 +466,124 @@ func TestMachineDeploymentMachineHealthCheckClass(t *testing.T) {
 		})
 	}
 }
+
+func TestIgnorePaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        []contract.Path
+		wantErr     bool
+	}{
+		{
+			name:        "should return no paths if the ClusterClass does not have the ignore paths annotation",
+			annotations: nil,
+			want:        nil,
+		},
+		{
+			name:        "should return the paths from the ignore paths annotation",
+			annotations: map[string]string{clusterv1.ClusterTopologyIgnorePathsAnnotation: "spec.foo,spec.template.spec.bar"},
+			want:        []contract.Path{{"spec", "foo"}, {"spec", "template", "spec", "bar"}},
+		},
+		{
+			name:        "should fail if the ignore paths annotation is not valid",
+			annotations: map[string]string{clusterv1.ClusterTopologyIgnorePathsAnnotation: "status.foo"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			blueprint := &ClusterBlueprint{
+				ClusterClass: &clusterv1.ClusterClass{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}},
+			}
+			got, err := blueprint.IgnorePaths()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+
+	t.Run("should return no paths if the blueprint has no ClusterClass", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got, err := (&ClusterBlueprint{}).IgnorePaths()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).To(BeNil())
+	})
+}
+
+func TestTemplates(t *testing.T) {
+	infrastructureClusterTemplate := builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra-cluster-template").Build()
+	controlPlaneTemplate := builder.ControlPlaneTemplate(metav1.NamespaceDefault, "control-plane-template").Build()
+	controlPlaneInfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "control-plane-infra-machine-template").Build()
+	md1BootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "md1-bootstrap-template").Build()
+	md1InfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "md1-infra-machine-template").Build()
+	md2BootstrapTemplate := builder.BootstrapTemplate(metav1.NamespaceDefault, "md2-bootstrap-template").Build()
+	md2InfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "md2-infra-machine-template").Build()
+
+	tests := []struct {
+		name      string
+		blueprint *ClusterBlueprint
+		want      []*unstructured.Unstructured
+	}{
+		{
+			name:      "Return no templates for an empty blueprint",
+			blueprint: &ClusterBlueprint{},
+			want:      []*unstructured.Unstructured{},
+		},
+		{
+			name: "Return all the templates",
+			blueprint: &ClusterBlueprint{
+				InfrastructureClusterTemplate: infrastructureClusterTemplate,
+				ControlPlane: &ControlPlaneBlueprint{
+					Template:                      controlPlaneTemplate,
+					InfrastructureMachineTemplate: controlPlaneInfrastructureMachineTemplate,
+				},
+				MachineDeployments: map[string]*MachineDeploymentBlueprint{
+					"md2": {
+						BootstrapTemplate:             md2BootstrapTemplate,
+						InfrastructureMachineTemplate: md2InfrastructureMachineTemplate,
+					},
+					"md1": {
+						BootstrapTemplate:             md1BootstrapTemplate,
+						InfrastructureMachineTemplate: md1InfrastructureMachineTemplate,
+					},
+				},
+			},
+			want: []*unstructured.Unstructured{
+				infrastructureClusterTemplate,
+				controlPlaneTemplate,
+				controlPlaneInfrastructureMachineTemplate,
+				md1BootstrapTemplate,
+				md1InfrastructureMachineTemplate,
+				md2BootstrapTemplate,
+				md2InfrastructureMachineTemplate,
+			},
+		},
+		{
+			name: "Skip templates which are not defined",
+			blueprint: &ClusterBlueprint{
+				InfrastructureClusterTemplate: infrastructureClusterTemplate,
+				ControlPlane: &ControlPlaneBlueprint{
+					Template: controlPlaneTemplate,
+				},
+			},
+			want: []*unstructured.Unstructured{
+				infrastructureClusterTemplate,
+				controlPlaneTemplate,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(tt.blueprint.Templates()).To(Equal(tt.want))
+		})
+	}
+}
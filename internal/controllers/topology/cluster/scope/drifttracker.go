@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DriftTracker is a helper to capture the objects generated from a Cluster topology which have been changed out-of-band.
+// NOTE: DriftTracker is safe for concurrent use, given that the objects generated from a Cluster topology are reconciled concurrently.
+type DriftTracker struct {
+	lock    sync.RWMutex
+	objects []driftedObject
+}
+
+// driftedObject describes an object which has been changed out-of-band.
+type driftedObject struct {
+	kind    string
+	name    string
+	changes []string
+}
+
+// NewDriftTracker returns a new DriftTracker.
+func NewDriftTracker() *DriftTracker {
+	return &DriftTracker{}
+}
+
+// Add adds an object which has been changed out-of-band to the tracker, together with a description
+// of the changes, e.g. "kubectl-edit: spec.replicas".
+func (d *DriftTracker) Add(kind, name string, changes []string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.objects = append(d.objects, driftedObject{kind: kind, name: name, changes: changes})
+}
+
+// IsDrifted returns true if any object has been changed out-of-band.
+func (d *DriftTracker) IsDrifted() bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return len(d.objects) > 0
+}
+
+// AggregateMessage returns a human friendly message about the objects which have been changed out-of-band.
+func (d *DriftTracker) AggregateMessage() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	objectsAndChanges := []string{}
+	for _, o := range d.objects {
+		objectsAndChanges = append(objectsAndChanges, DriftMessage(o.kind, o.name, o.changes))
+	}
+	return strings.Join(objectsAndChanges, ", ")
+}
+
+// DriftMessage returns a human friendly message about an object which has been changed out-of-band,
+// as reported in the message returned by AggregateMessage.
+func DriftMessage(kind, name string, changes []string) string {
+	if len(changes) == 0 {
+		return fmt.Sprintf("%s %s", kind, name)
+	}
+	return fmt.Sprintf("%s %s (%s)", kind, name, strings.Join(changes, "; "))
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDriftTracker(t *testing.T) {
+	t.Run("IsDrifted should return false if there are no drifted objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dt := NewDriftTracker()
+		g.Expect(dt.IsDrifted()).To(BeFalse())
+		g.Expect(dt.AggregateMessage()).To(BeEmpty())
+	})
+	t.Run("AggregateMessage should return a message for all the drifted objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		dt := NewDriftTracker()
+		dt.Add("KubeadmControlPlane", "cp1", nil)
+		dt.Add("MachineDeployment", "md1", []string{"kubectl-edit: spec.replicas", "manager: spec.paused"})
+		g.Expect(dt.IsDrifted()).To(BeTrue())
+		g.Expect(dt.AggregateMessage()).To(Equal("KubeadmControlPlane cp1, MachineDeployment md1 (kubectl-edit: spec.replicas; manager: spec.paused)"))
+	})
+}
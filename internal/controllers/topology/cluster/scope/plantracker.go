@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// PlanTracker is a helper to capture the changes which would be applied to the objects generated from a Cluster
+// topology when the Cluster topology is in plan mode.
+// NOTE: PlanTracker is safe for concurrent use, in line with the other trackers of the scope.
+type PlanTracker struct {
+	lock    sync.RWMutex
+	changes []PlannedChange
+}
+
+// PlannedChange describes a change which would be applied to an object generated from a Cluster topology.
+type PlannedChange struct {
+	// Operation is the operation which would be applied to the object, e.g. create, update or delete.
+	Operation string
+
+	// Kind is the kind of the object.
+	Kind string
+
+	// Name is the name of the object.
+	Name string
+
+	// Diff is the patch which would be applied to the object, if any.
+	Diff string
+}
+
+// NewPlanTracker returns a new PlanTracker.
+func NewPlanTracker() *PlanTracker {
+	return &PlanTracker{}
+}
+
+// Add adds a change which would be applied to an object to the tracker.
+func (p *PlanTracker) Add(operation, kind, name, diff string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.changes = append(p.changes, PlannedChange{Operation: operation, Kind: kind, Name: name, Diff: diff})
+}
+
+// Changes returns the changes which would be applied to the objects.
+func (p *PlanTracker) Changes() []PlannedChange {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return append([]PlannedChange{}, p.changes...)
+}
+
+// HasChanges returns true if any change would be applied to the objects.
+func (p *PlanTracker) HasChanges() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return len(p.changes) > 0
+}
+
+// AggregateMessage returns a human friendly message about the changes which would be applied to the objects.
+func (p *PlanTracker) AggregateMessage() string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	changes := []string{}
+	for _, c := range p.changes {
+		changes = append(changes, fmt.Sprintf("%s %s %s", c.Operation, c.Kind, c.Name))
+	}
+	return strings.Join(changes, ", ")
+}
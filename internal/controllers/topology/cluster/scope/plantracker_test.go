@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPlanTracker(t *testing.T) {
+	t.Run("HasChanges should return false if there are no changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pt := NewPlanTracker()
+		g.Expect(pt.HasChanges()).To(BeFalse())
+		g.Expect(pt.Changes()).To(BeEmpty())
+		g.Expect(pt.AggregateMessage()).To(BeEmpty())
+	})
+	t.Run("AggregateMessage should return a message for all the changes", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pt := NewPlanTracker()
+		pt.Add("update", "KubeadmControlPlane", "cp1", `{"spec":{"version":"v1.24.0"}}`)
+		pt.Add("create", "MachineDeployment", "md1", "")
+		g.Expect(pt.HasChanges()).To(BeTrue())
+		g.Expect(pt.Changes()).To(HaveLen(2))
+		g.Expect(pt.AggregateMessage()).To(Equal("update KubeadmControlPlane cp1, create MachineDeployment md1"))
+	})
+}
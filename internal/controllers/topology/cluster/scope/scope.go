@@ -37,6 +37,12 @@ type Scope struct {
 	// HookResponseTracker holds the hook responses that will be used to
 	// calculate a combined reconcile result.
 	HookResponseTracker *HookResponseTracker
+
+	// DriftTracker holds the objects generated from the managed topology which have been changed out-of-band.
+	DriftTracker *DriftTracker
+
+	// PlanTracker holds the changes which would be applied to the managed topology when the Cluster topology is in plan mode.
+	PlanTracker *PlanTracker
 }
 
 // New returns a new Scope with only the cluster; while processing a request in the topology/ClusterReconciler controller
@@ -50,7 +56,19 @@ func New(cluster *clusterv1.Cluster) *Scope {
 		Current: &ClusterState{
 			Cluster: cluster,
 		},
-		UpgradeTracker:      NewUpgradeTracker(),
+		UpgradeTracker:      NewUpgradeTracker(upgradeTrackerOptions(cluster)...),
 		HookResponseTracker: NewHookResponseTracker(),
+		DriftTracker:        NewDriftTracker(),
+		PlanTracker:         NewPlanTracker(),
+	}
+}
+
+// upgradeTrackerOptions returns the options for the UpgradeTracker of a Cluster, e.g. allowing
+// all the MachineDeployments to be upgraded at the same time if the Cluster uses the Parallel upgrade policy.
+func upgradeTrackerOptions(cluster *clusterv1.Cluster) []UpgradeTrackerOption {
+	if cluster.Spec.Topology == nil || cluster.Spec.Topology.Workers == nil ||
+		cluster.Spec.Topology.Workers.MachineDeploymentsUpgradePolicy != clusterv1.ParallelMachineDeploymentsUpgradePolicy {
+		return nil
 	}
+	return []UpgradeTrackerOption{MaxMachineDeploymentUpgradeConcurrency(len(cluster.Spec.Topology.Workers.MachineDeployments))}
 }
@@ -17,9 +17,12 @@ limitations under the License.
 package scope
 
 import (
+	"sort"
+
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/controllers/machinedeployment/mdutil"
 )
 
@@ -38,6 +41,9 @@ type ClusterState struct {
 
 	// MachineDeployments holds the machine deployments in the Cluster.
 	MachineDeployments MachineDeploymentsStateMap
+
+	// MachinePools holds the machine pools in the Cluster.
+	MachinePools MachinePoolsStateMap
 }
 
 // ControlPlaneState holds all the objects representing the state of a managed control plane.
@@ -48,6 +54,10 @@ type ControlPlaneState struct {
 	// InfrastructureMachineTemplate holds the infrastructure template referenced by the ControlPlane object.
 	InfrastructureMachineTemplate *unstructured.Unstructured
 
+	// FailureDomainInfrastructureMachineTemplates holds the infrastructure templates for specific failure domains
+	// referenced by the ControlPlane object, indexed by failure domain.
+	FailureDomainInfrastructureMachineTemplates map[string]*unstructured.Unstructured
+
 	// MachineHealthCheckClass holds the MachineHealthCheck for this ControlPlane.
 	// +optional
 	MachineHealthCheck *clusterv1.MachineHealthCheck
@@ -96,3 +106,48 @@ type MachineDeploymentState struct {
 func (md *MachineDeploymentState) IsRollingOut() bool {
 	return !mdutil.DeploymentComplete(md.Object, &md.Object.Status) || *md.Object.Spec.Replicas != md.Object.Status.ReadyReplicas
 }
+
+// MachinePoolsStateMap holds a collection of MachinePool states.
+type MachinePoolsStateMap map[string]*MachinePoolState
+
+// MachinePoolState holds all the objects representing the state of a managed pool.
+type MachinePoolState struct {
+	// Object holds the MachinePool object.
+	Object *expv1.MachinePool
+
+	// BootstrapObject holds the bootstrap config object referenced by the MachinePool object.
+	BootstrapObject *unstructured.Unstructured
+
+	// InfrastructureMachinePoolObject holds the infrastructure machine pool object referenced by the MachinePool object.
+	InfrastructureMachinePoolObject *unstructured.Unstructured
+}
+
+// RollingOut returns the sorted list of the machine pools
+// that are rolling out.
+func (mps MachinePoolsStateMap) RollingOut() []string {
+	names := []string{}
+	for _, mp := range mps {
+		if mp.IsRollingOut() {
+			names = append(names, mp.Object.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsAnyRollingOut returns true if at least one of the
+// machine pools is rolling out. False, otherwise.
+func (mps MachinePoolsStateMap) IsAnyRollingOut() bool {
+	return len(mps.RollingOut()) != 0
+}
+
+// IsRollingOut determines if the machine pool is upgrading.
+// A machine pool is considered upgrading if:
+// - the MachinePool controller has not yet observed the latest generation of the machine pool.
+// - if any of the replicas of the machine pool is not ready.
+func (mp *MachinePoolState) IsRollingOut() bool {
+	if mp.Object.Status.ObservedGeneration < mp.Object.Generation {
+		return true
+	}
+	return mp.Object.Spec.Replicas != nil && *mp.Object.Spec.Replicas != mp.Object.Status.ReadyReplicas
+}
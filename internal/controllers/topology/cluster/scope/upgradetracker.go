@@ -18,12 +18,13 @@ package scope
 
 import "k8s.io/apimachinery/pkg/util/sets"
 
-const maxMachineDeploymentUpgradeConcurrency = 1
+const defaultMaxMachineDeploymentUpgradeConcurrency = 1
 
 // UpgradeTracker is a helper to capture the upgrade status and make upgrade decisions.
 type UpgradeTracker struct {
 	ControlPlane       ControlPlaneUpgradeTracker
 	MachineDeployments MachineDeploymentUpgradeTracker
+	MachinePools       MachinePoolUpgradeTracker
 }
 
 // ControlPlaneUpgradeTracker holds the current upgrade status of the Control Plane.
@@ -46,19 +47,48 @@ type ControlPlaneUpgradeTracker struct {
 // MachineDeploymentUpgradeTracker holds the current upgrade status and makes upgrade
 // decisions for MachineDeployments.
 type MachineDeploymentUpgradeTracker struct {
-	pendingNames    sets.String
-	rollingOutNames sets.String
-	holdUpgrades    bool
+	pendingNames          sets.String
+	rollingOutNames       sets.String
+	holdUpgrades          bool
+	maxUpgradeConcurrency int
+}
+
+// MachinePoolUpgradeTracker holds the current upgrade status of MachinePools.
+type MachinePoolUpgradeTracker struct {
+	pendingNames   sets.String
+	upgradingNames sets.String
+}
+
+// UpgradeTrackerOption is an option for the UpgradeTracker.
+type UpgradeTrackerOption func(*UpgradeTracker)
+
+// MaxMachineDeploymentUpgradeConcurrency sets the maximum number of MachineDeployments
+// that can be upgraded at the same time; values lower than 1 are ignored.
+func MaxMachineDeploymentUpgradeConcurrency(n int) UpgradeTrackerOption {
+	return func(u *UpgradeTracker) {
+		if n > 0 {
+			u.MachineDeployments.maxUpgradeConcurrency = n
+		}
+	}
 }
 
 // NewUpgradeTracker returns an upgrade tracker with empty tracking information.
-func NewUpgradeTracker() *UpgradeTracker {
-	return &UpgradeTracker{
+func NewUpgradeTracker(opts ...UpgradeTrackerOption) *UpgradeTracker {
+	u := &UpgradeTracker{
 		MachineDeployments: MachineDeploymentUpgradeTracker{
-			pendingNames:    sets.NewString(),
-			rollingOutNames: sets.NewString(),
+			pendingNames:          sets.NewString(),
+			rollingOutNames:       sets.NewString(),
+			maxUpgradeConcurrency: defaultMaxMachineDeploymentUpgradeConcurrency,
+		},
+		MachinePools: MachinePoolUpgradeTracker{
+			pendingNames:   sets.NewString(),
+			upgradingNames: sets.NewString(),
 		},
 	}
+	for _, opt := range opts {
+		opt(u)
+	}
+	return u
 }
 
 // MarkRollingOut marks a MachineDeployment as currently rolling out or
@@ -94,7 +124,7 @@ func (m *MachineDeploymentUpgradeTracker) AllowUpgrade() bool {
 	if m.holdUpgrades {
 		return false
 	}
-	return m.rollingOutNames.Len() < maxMachineDeploymentUpgradeConcurrency
+	return m.rollingOutNames.Len() < m.maxUpgradeConcurrency
 }
 
 // MarkPendingUpgrade marks a machine deployment as in need of an upgrade.
@@ -115,3 +145,36 @@ func (m *MachineDeploymentUpgradeTracker) PendingUpgradeNames() []string {
 func (m *MachineDeploymentUpgradeTracker) PendingUpgrade() bool {
 	return len(m.pendingNames) != 0
 }
+
+// MarkUpgrading marks a MachinePool as picking up the topology version
+// in the current reconcile.
+func (m *MachinePoolUpgradeTracker) MarkUpgrading(names ...string) {
+	for _, name := range names {
+		m.upgradingNames.Insert(name)
+	}
+}
+
+// UpgradingNames returns the list of machine pools that are picking up
+// the topology version in the current reconcile.
+func (m *MachinePoolUpgradeTracker) UpgradingNames() []string {
+	return m.upgradingNames.List()
+}
+
+// MarkPendingUpgrade marks a machine pool as in need of an upgrade.
+// This is generally used to capture machine pools that have not yet
+// picked up the topology version.
+func (m *MachinePoolUpgradeTracker) MarkPendingUpgrade(name string) {
+	m.pendingNames.Insert(name)
+}
+
+// PendingUpgradeNames returns the list of machine pool names that
+// are pending an upgrade.
+func (m *MachinePoolUpgradeTracker) PendingUpgradeNames() []string {
+	return m.pendingNames.List()
+}
+
+// PendingUpgrade returns true if any of the machine pools are pending
+// an upgrade. Returns false, otherwise.
+func (m *MachinePoolUpgradeTracker) PendingUpgrade() bool {
+	return len(m.pendingNames) != 0
+}
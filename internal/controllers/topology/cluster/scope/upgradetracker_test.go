@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scope
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestMachineDeploymentUpgradeTrackerAllowUpgrade(t *testing.T) {
+	t.Run("MachineDeployments are upgraded one at a time by default", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := New(&clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Topology: &clusterv1.Topology{
+			Workers: &clusterv1.WorkersTopology{
+				MachineDeployments: []clusterv1.MachineDeploymentTopology{{Name: "md1"}, {Name: "md2"}},
+			},
+		}}})
+		g.Expect(s.UpgradeTracker.MachineDeployments.AllowUpgrade()).To(BeTrue())
+		s.UpgradeTracker.MachineDeployments.MarkRollingOut("md1")
+		g.Expect(s.UpgradeTracker.MachineDeployments.AllowUpgrade()).To(BeFalse())
+	})
+
+	t.Run("All the MachineDeployments are upgraded at the same time with the Parallel upgrade policy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := New(&clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Topology: &clusterv1.Topology{
+			Workers: &clusterv1.WorkersTopology{
+				MachineDeployments:              []clusterv1.MachineDeploymentTopology{{Name: "md1"}, {Name: "md2"}},
+				MachineDeploymentsUpgradePolicy: clusterv1.ParallelMachineDeploymentsUpgradePolicy,
+			},
+		}}})
+		s.UpgradeTracker.MachineDeployments.MarkRollingOut("md1")
+		g.Expect(s.UpgradeTracker.MachineDeployments.AllowUpgrade()).To(BeTrue())
+		s.UpgradeTracker.MachineDeployments.MarkRollingOut("md2")
+		g.Expect(s.UpgradeTracker.MachineDeployments.AllowUpgrade()).To(BeFalse())
+
+		u := NewUpgradeTracker(MaxMachineDeploymentUpgradeConcurrency(2))
+		g.Expect(u.MachineDeployments.AllowUpgrade()).To(BeTrue())
+		u.MachineDeployments.HoldUpgrades(true)
+		g.Expect(u.MachineDeployments.AllowUpgrade()).To(BeFalse())
+	})
+}
+
+func TestMachinePoolUpgradeTracker(t *testing.T) {
+	g := NewWithT(t)
+
+	u := NewUpgradeTracker()
+	g.Expect(u.MachinePools.PendingUpgrade()).To(BeFalse())
+	g.Expect(u.MachinePools.UpgradingNames()).To(BeEmpty())
+
+	u.MachinePools.MarkPendingUpgrade("mp2")
+	u.MachinePools.MarkPendingUpgrade("mp1")
+	g.Expect(u.MachinePools.PendingUpgrade()).To(BeTrue())
+	g.Expect(u.MachinePools.PendingUpgradeNames()).To(Equal([]string{"mp1", "mp2"}))
+
+	u.MachinePools.MarkUpgrading("mp3")
+	g.Expect(u.MachinePools.UpgradingNames()).To(Equal([]string{"mp3"}))
+}
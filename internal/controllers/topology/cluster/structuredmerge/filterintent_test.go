@@ -21,6 +21,7 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/contract"
 )
 
@@ -87,6 +88,33 @@ func Test_filterNotAllowedPaths(t *testing.T) {
 				// status filtered out
 			},
 		},
+		{
+			name: "Keeps the labels the topology controller has an opinion on for Clusters",
+			ctx: &filterIntentInput{
+				path: contract.Path{},
+				value: map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{
+							clusterv1.ClusterLabelName:          "foo",
+							clusterv1.ClusterTopologyOwnedLabel: "",
+							clusterv1.ClusterProfileLabelName:   "prod",
+							"foo":                               "123",
+						},
+					},
+				},
+				shouldFilter: isNotAllowedPath(allowedPathsCluster),
+			},
+			wantValue: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						clusterv1.ClusterLabelName:          "foo",
+						clusterv1.ClusterTopologyOwnedLabel: "",
+						clusterv1.ClusterProfileLabelName:   "prod",
+						// foo filtered out
+					},
+				},
+			},
+		},
 		{
 			name: "Cleanup empty maps",
 			ctx: &filterIntentInput{
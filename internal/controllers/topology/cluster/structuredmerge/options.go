@@ -21,6 +21,7 @@ import (
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util"
 )
 
 var (
@@ -53,12 +54,17 @@ var (
 		{"metadata", "namespace"},
 		// uid is optional for a server side apply intent but sets the expectation of an object getting created or a specific one updated.
 		{"metadata", "uid"},
-		// the topology controller controls/has an opinion for the labels ClusterLabelName
-		// and ClusterTopologyOwnedLabel as well as infrastructureRef and controlPlaneRef in spec.
+		// the topology controller controls/has an opinion for the labels ClusterLabelName,
+		// ClusterTopologyOwnedLabel and ClusterProfileLabelName as well as infrastructureRef and controlPlaneRef in spec.
 		{"metadata", "labels", clusterv1.ClusterLabelName},
 		{"metadata", "labels", clusterv1.ClusterTopologyOwnedLabel},
+		{"metadata", "labels", clusterv1.ClusterProfileLabelName},
 		{"spec", "infrastructureRef"},
 		{"spec", "controlPlaneRef"},
+		// the topology controller also has an opinion on the cluster network fields which can be defaulted by the ClusterClass.
+		{"spec", "clusterNetwork", "services"},
+		{"spec", "clusterNetwork", "pods"},
+		{"spec", "clusterNetwork", "serviceDomain"},
 	}
 )
 
@@ -82,6 +88,10 @@ type HelperOptions struct {
 	// spec.ControlPlaneEndpoint.
 	// NOTE: ignore paths which point to an array are not supported by the current implementation.
 	ignorePaths []contract.Path
+
+	// ignorePathsOnUpdate instruct the Helper to ignore given paths when computing a patch for an existing object;
+	// when creating an object those paths are handled like all the other paths.
+	ignorePathsOnUpdate []contract.Path
 }
 
 // newHelperOptions returns initialized HelperOptions.
@@ -98,6 +108,16 @@ func newHelperOptions(target client.Object, opts ...HelperOption) *HelperOptions
 	return helperOptions
 }
 
+// ignorePathsFor returns the paths to be ignored when computing a patch for the given original object.
+func (o *HelperOptions) ignorePathsFor(original client.Object) []contract.Path {
+	if util.IsNil(original) || len(o.ignorePathsOnUpdate) == 0 {
+		return o.ignorePaths
+	}
+	ignorePaths := make([]contract.Path, 0, len(o.ignorePaths)+len(o.ignorePathsOnUpdate))
+	ignorePaths = append(ignorePaths, o.ignorePaths...)
+	return append(ignorePaths, o.ignorePathsOnUpdate...)
+}
+
 // ApplyOptions applies the given patch options on these options,
 // and then returns itself (for convenient chaining).
 func (o *HelperOptions) ApplyOptions(opts []HelperOption) *HelperOptions {
@@ -116,3 +136,13 @@ type IgnorePaths []contract.Path
 func (i IgnorePaths) ApplyToHelper(opts *HelperOptions) {
 	opts.ignorePaths = i
 }
+
+// IgnorePathsOnUpdate instruct the Helper to ignore given paths when computing a patch for an existing object.
+// NOTE: differently from IgnorePaths, those paths are set when creating an object; this allows e.g. to
+// create an object with a value for a field that gets then owned by something else.
+type IgnorePathsOnUpdate []contract.Path
+
+// ApplyToHelper applies this configuration to the given helper options.
+func (i IgnorePathsOnUpdate) ApplyToHelper(opts *HelperOptions) {
+	opts.ignorePathsOnUpdate = i
+}
@@ -538,6 +538,48 @@ func TestServerSideApply_CleanupLegacyManagedFields(t *testing.T) {
 	})
 }
 
+// NOTE: This test ensures the ServerSideApply sets the labels the topology controller has an opinion on for Clusters.
+func TestServerSideApply_ClusterProfileLabel(t *testing.T) {
+	g := NewWithT(t)
+
+	// Create a namespace for running the test
+	ns, err := env.CreateNamespace(ctx, "ssa-cluster")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Create a Cluster with a label not managed by the topology controller.
+	cluster := builder.Cluster(ns.Name, "cluster1").WithLabels(map[string]string{"foo": "bar"}).Build()
+	g.Expect(env.Create(ctx, cluster)).To(Succeed())
+
+	t.Run("Server side apply sets the profile label on a Cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		original := &clusterv1.Cluster{}
+		g.Expect(env.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(cluster), original)).To(Succeed())
+
+		modified := original.DeepCopy()
+		modified.Labels[clusterv1.ClusterProfileLabelName] = "prod"
+
+		p0, err := NewServerSidePatchHelper(ctx, original, modified, env.GetClient())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(p0.HasChanges()).To(BeTrue())
+		g.Expect(p0.HasSpecChanges()).To(BeFalse())
+		g.Expect(p0.Patch(ctx)).To(Succeed())
+
+		// Check the label is set and the topology controller is tracked as its manager.
+		got := &clusterv1.Cluster{}
+		g.Expect(env.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(cluster), got)).To(Succeed())
+		g.Expect(got.Labels).To(HaveKeyWithValue(clusterv1.ClusterProfileLabelName, "prod"))
+		g.Expect(got.Labels).To(HaveKeyWithValue("foo", "bar"))
+
+		got.SetGroupVersionKind(clusterv1.GroupVersion.WithKind("Cluster"))
+		fieldV1 := getTopologyManagedFields(got)
+		g.Expect(fieldV1).To(HaveKey("f:metadata"))
+		labelsFieldV1 := fieldV1["f:metadata"].(map[string]interface{})["f:labels"].(map[string]interface{})
+		g.Expect(labelsFieldV1).To(HaveKey("f:" + clusterv1.ClusterProfileLabelName))
+		g.Expect(labelsFieldV1).ToNot(HaveKey("f:foo")) // topology controller should not express opinions on other labels.
+	})
+}
+
 // getTopologyManagedFields returns metadata.managedFields entry tracking
 // server side apply operations for the topology controller.
 func getTopologyManagedFields(original client.Object) map[string]interface{} {
@@ -62,10 +62,11 @@ type TwoWaysPatchHelper struct {
 //   - TwoWaysPatch doesn't generate metadata.managedFields as server side apply does.
 //
 // NOTE: NewTwoWaysPatchHelper consider changes only in metadata.labels, metadata.annotation and spec; it also respects
-// the ignorePath option (same as the server side apply helper).
+// the IgnorePaths and IgnorePathsOnUpdate options (same as the server side apply helper).
 func NewTwoWaysPatchHelper(original, modified client.Object, c client.Client, opts ...HelperOption) (*TwoWaysPatchHelper, error) {
 	helperOptions := &HelperOptions{}
 	helperOptions = helperOptions.ApplyOptions(opts)
+	helperOptions.ignorePaths = helperOptions.ignorePathsFor(original)
 	helperOptions.allowedPaths = []contract.Path{
 		{"metadata", "labels"},
 		{"metadata", "annotations"},
@@ -204,6 +205,11 @@ func (h *TwoWaysPatchHelper) HasChanges() bool {
 	return !bytes.Equal(h.patch, []byte("{}"))
 }
 
+// Changes returns the two ways merge patch in json format, e.g. to preview the changes without applying them.
+func (h *TwoWaysPatchHelper) Changes() []byte {
+	return h.patch
+}
+
 // Patch will attempt to apply the twoWaysPatch to the original object.
 func (h *TwoWaysPatchHelper) Patch(ctx context.Context) error {
 	if !h.HasChanges() {
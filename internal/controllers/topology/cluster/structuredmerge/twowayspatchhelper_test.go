@@ -84,6 +84,51 @@ func TestNewHelper(t *testing.T) {
 			wantHasSpecChanges: false,
 			wantPatch:          []byte("{}"),
 		},
+		{
+			name: "Ignore on update fields are removed from the patch",
+			original: &unstructured.Unstructured{ // current
+				Object: map[string]interface{}{},
+			},
+			modified: &unstructured.Unstructured{ // desired
+				Object: map[string]interface{}{
+					"spec": map[string]interface{}{
+						"foo": "foo",
+					},
+				},
+			},
+			options:            []HelperOption{IgnorePathsOnUpdate{contract.Path{"spec", "foo"}}},
+			wantHasChanges:     false,
+			wantHasSpecChanges: false,
+			wantPatch:          []byte("{}"),
+		},
+		{
+			name:     "Ignore on update fields are preserved when creating",
+			original: nil,
+			modified: &unstructured.Unstructured{ // desired
+				Object: map[string]interface{}{
+					"apiVersion": builder.BootstrapGroupVersion.String(),
+					"kind":       builder.GenericBootstrapConfigKind,
+					"metadata": map[string]interface{}{
+						"namespace": metav1.NamespaceDefault,
+						"name":      "foo",
+					},
+					"spec": map[string]interface{}{
+						"foo": "foo",
+						"controlPlaneEndpoint": map[string]interface{}{
+							"host": "",
+							"port": int64(0),
+						},
+					},
+				},
+			},
+			options: []HelperOption{
+				IgnorePaths{contract.Path{"spec", "controlPlaneEndpoint"}},
+				IgnorePathsOnUpdate{contract.Path{"spec", "foo"}},
+			},
+			wantHasChanges:     true,
+			wantHasSpecChanges: true,
+			wantPatch:          []byte(fmt.Sprintf("{\"apiVersion\":%q,\"kind\":%q,\"metadata\":{\"name\":\"foo\",\"namespace\":%q},\"spec\":{\"foo\":\"foo\"}}", builder.BootstrapGroupVersion.String(), builder.GenericBootstrapConfigKind, metav1.NamespaceDefault)),
+		},
 
 		// Allowed Path fields
 
@@ -444,7 +489,7 @@ func TestNewHelper(t *testing.T) {
 			patch, err := NewTwoWaysPatchHelper(tt.original, tt.modified, env.GetClient(), tt.options...)
 			g.Expect(err).ToNot(HaveOccurred())
 
-			g.Expect(patch.patch).To(Equal(tt.wantPatch))
+			g.Expect(patch.Changes()).To(Equal(tt.wantPatch))
 			g.Expect(patch.HasChanges()).To(Equal(tt.wantHasChanges))
 			g.Expect(patch.HasSpecChanges()).To(Equal(tt.wantHasSpecChanges))
 		})
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// templateReaderFunc returns the reader to be used for reading the templates referenced by the ClusterClass
+// of the Clusters in a namespace.
+type templateReaderFunc func(namespace string) (client.Reader, error)
+
+// impersonatingTemplateReader returns a templateReaderFunc reading templates impersonating the ServiceAccount
+// with the given name in the namespace of the Cluster, thus ensuring the ClusterClass of a Cluster can only
+// reference templates the ServiceAccount is allowed to read.
+// NOTE: Readers are created once per namespace and then reused; they read directly from the API server, because
+// it is not possible to share the cache of the controller across different identities.
+func impersonatingTemplateReader(config *rest.Config, scheme *runtime.Scheme, mapper meta.RESTMapper, serviceAccount string) templateReaderFunc {
+	var lock sync.Mutex
+	readers := map[string]client.Reader{}
+
+	return func(namespace string) (client.Reader, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if reader, ok := readers[namespace]; ok {
+			return reader, nil
+		}
+
+		impersonatingConfig := rest.CopyConfig(config)
+		impersonatingConfig.Impersonate = rest.ImpersonationConfig{
+			UserName: serviceaccount.MakeUsername(namespace, serviceAccount),
+		}
+		reader, err := client.New(impersonatingConfig, client.Options{Scheme: scheme, Mapper: mapper})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create a client impersonating ServiceAccount %s/%s", namespace, serviceAccount)
+		}
+		readers[namespace] = reader
+		return reader, nil
+	}
+}
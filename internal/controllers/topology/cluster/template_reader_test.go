@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestImpersonatingTemplateReader(t *testing.T) {
+	g := NewWithT(t)
+
+	// The API server records the impersonated users and answers NotFound to every request.
+	var lock sync.Mutex
+	var users []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		users = append(users, r.Header.Get("Impersonate-User"))
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+	}))
+	defer server.Close()
+
+	gvk := builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp-template").Build().GroupVersionKind()
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(gvk, meta.RESTScopeNamespace)
+
+	readerFor := impersonatingTemplateReader(&rest.Config{Host: server.URL}, runtime.NewScheme(), mapper, "template-reader")
+
+	reader, err := readerFor("ns1")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Readers are reused for the same namespace.
+	sameReader, err := readerFor("ns1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(sameReader).To(BeIdenticalTo(reader))
+
+	otherReader, err := readerFor("ns2")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for _, r := range []client.Reader{reader, otherReader} {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		err := r.Get(ctx, client.ObjectKey{Namespace: "any", Name: "cp-template"}, obj)
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	}
+	g.Expect(users).To(Equal([]string{
+		"system:serviceaccount:ns1:template-reader",
+		"system:serviceaccount:ns2:template-reader",
+	}))
+}
+
+func TestGetTemplateReader(t *testing.T) {
+	g := NewWithT(t)
+
+	// Templates are read with the controller client if no template reader is configured.
+	cachingClient := fake.NewClientBuilder().Build()
+	r := &Reconciler{UnstructuredCachingClient: cachingClient}
+	reader, err := r.getTemplateReader(metav1.NamespaceDefault)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reader).To(BeIdenticalTo(cachingClient))
+
+	// Otherwise the template reader for the namespace is used.
+	namespaceReader := fake.NewClientBuilder().Build()
+	r.templateReader = func(namespace string) (client.Reader, error) {
+		g.Expect(namespace).To(Equal(metav1.NamespaceDefault))
+		return namespaceReader, nil
+	}
+	reader, err = r.getTemplateReader(metav1.NamespaceDefault)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reader).To(BeIdenticalTo(namespaceReader))
+}
@@ -18,11 +18,14 @@ package cluster
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"sigs.k8s.io/cluster-api/controllers/external"
 )
@@ -37,24 +40,97 @@ func infrastructureMachineTemplateNamePrefix(clusterName, machineDeploymentTopol
 	return fmt.Sprintf("%s-%s-infra-", clusterName, machineDeploymentTopologyName)
 }
 
+// bootstrapConfigNamePrefix calculates the name prefix for a BootstrapConfig.
+func bootstrapConfigNamePrefix(clusterName, machinePoolTopologyName string) string {
+	return fmt.Sprintf("%s-%s-bootstrap-", clusterName, machinePoolTopologyName)
+}
+
+// infrastructureMachinePoolNamePrefix calculates the name prefix for a InfrastructureMachinePool.
+func infrastructureMachinePoolNamePrefix(clusterName, machinePoolTopologyName string) string {
+	return fmt.Sprintf("%s-%s-infra-", clusterName, machinePoolTopologyName)
+}
+
 // infrastructureMachineTemplateNamePrefix calculates the name prefix for a InfrastructureMachineTemplate.
 func controlPlaneInfrastructureMachineTemplateNamePrefix(clusterName string) string {
 	return fmt.Sprintf("%s-control-plane-", clusterName)
 }
 
+// controlPlaneFailureDomainInfrastructureMachineTemplateNameDiscriminator returns the discriminator for the names of the
+// InfrastructureMachineTemplates of the control plane for a failure domain, so they get different names even if they
+// have the same content.
+func controlPlaneFailureDomainInfrastructureMachineTemplateNameDiscriminator(failureDomain string) string {
+	return "failure-domain/" + failureDomain
+}
+
+// maxTemplateNameLength is the maximum length of the names of the templates generated by the topology controller.
+const maxTemplateNameLength = 63
+
+// templateNameWithHash calculates the name of a template by appending to the name prefix a hash of the content
+// of the template, i.e. of its kind and spec; this way the name of a template changes only if its content changes,
+// and the topology controller can detect if a template rotation is actually required.
+// NOTE: The full name prefix, which includes the name of the Cluster and of the MachineDeployment or MachinePool topology,
+// is included in the hash so templates of different topologies with the same content get different names even when
+// the name prefix is truncated.
+// NOTE: The discriminator, if any, is included in the hash so templates with the same name prefix and content, e.g. the
+// InfrastructureMachineTemplates of the control plane for different failure domains, get different names.
+func templateNameWithHash(namePrefix, discriminator string, template *unstructured.Unstructured) (string, error) {
+	spec, err := json.Marshal(template.Object["spec"])
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to compute name hash for %s", template.GetKind())
+	}
+
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(namePrefix))
+	_, _ = hasher.Write([]byte(template.GroupVersionKind().GroupKind().String()))
+	_, _ = hasher.Write([]byte(discriminator))
+	_, _ = hasher.Write(spec)
+	hash := fmt.Sprintf("%08x", hasher.Sum32())
+
+	if len(namePrefix) > maxTemplateNameLength-len(hash) {
+		namePrefix = namePrefix[:maxTemplateNameLength-len(hash)]
+	}
+	return namePrefix + hash, nil
+}
+
+// computeHash returns the hash of the JSON representation of an object.
+// NOTE: Maps are marshalled with sorted keys, thus the hash does not depend on the order keys are added.
+func computeHash(obj interface{}) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(data)
+	return fmt.Sprintf("%x", hasher.Sum64()), nil
+}
+
 // getReference gets the object referenced in ref.
 func (r *Reconciler) getReference(ctx context.Context, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
+	return getReferenceFrom(ctx, r.UnstructuredCachingClient, ref)
+}
+
+// getReferenceFrom gets the object referenced in ref using the given reader.
+func getReferenceFrom(ctx context.Context, reader client.Reader, ref *corev1.ObjectReference) (*unstructured.Unstructured, error) {
 	if ref == nil {
 		return nil, errors.New("reference is not set")
 	}
 
-	obj, err := external.Get(ctx, r.UnstructuredCachingClient, ref, ref.Namespace)
+	obj, err := external.Get(ctx, reader, ref, ref.Namespace)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to retrieve %s %q in namespace %q", ref.Kind, ref.Name, ref.Namespace)
 	}
 	return obj, nil
 }
 
+// getTemplateReader returns the reader to be used for reading the templates referenced by the ClusterClass
+// of the Clusters in a namespace.
+func (r *Reconciler) getTemplateReader(namespace string) (client.Reader, error) {
+	if r.templateReader == nil {
+		return r.UnstructuredCachingClient, nil
+	}
+	return r.templateReader(namespace)
+}
+
 // refToUnstructured returns an unstructured object with details from an ObjectReference.
 func refToUnstructured(ref *corev1.ObjectReference) *unstructured.Unstructured {
 	uns := &unstructured.Unstructured{}
@@ -17,6 +17,7 @@ limitations under the License.
 package cluster
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -114,3 +115,49 @@ func TestGetReference(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateNameWithHash(t *testing.T) {
+	g := NewWithT(t)
+
+	template := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "infra-template").
+		WithSpecFields(map[string]interface{}{"spec.template.spec.foo": "bar"}).
+		Build()
+
+	name, err := templateNameWithHash("cluster1-md1-infra-", "", template)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(name).To(HavePrefix("cluster1-md1-infra-"))
+	g.Expect(name).To(HaveLen(len("cluster1-md1-infra-") + 8))
+
+	// The name does not change if the content of the template does not change, e.g. if only metadata changes.
+	sameContent := template.DeepCopy()
+	sameContent.SetName("another-name")
+	sameContent.SetLabels(map[string]string{"foo": "bar"})
+	got, err := templateNameWithHash("cluster1-md1-infra-", "", sameContent)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(name))
+
+	// The name changes if the spec of the template changes.
+	changedSpec := template.DeepCopy()
+	g.Expect(unstructured.SetNestedField(changedSpec.Object, "baz", "spec", "template", "spec", "foo")).To(Succeed())
+	got, err = templateNameWithHash("cluster1-md1-infra-", "", changedSpec)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).ToNot(Equal(name))
+
+	// The name changes with the discriminator.
+	got, err = templateNameWithHash("cluster1-md1-infra-", controlPlaneFailureDomainInfrastructureMachineTemplateNameDiscriminator("fd1"), template)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).ToNot(Equal(name))
+
+	// Long prefixes are truncated.
+	got, err = templateNameWithHash(strings.Repeat("a", 100), "", template)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(HaveLen(maxTemplateNameLength))
+
+	// Templates of different topologies get different names even if their name prefixes are the same after truncation.
+	longClusterName := strings.Repeat("a", 60)
+	md1Name, err := templateNameWithHash(infrastructureMachineTemplateNamePrefix(longClusterName, "md1"), "", template)
+	g.Expect(err).ToNot(HaveOccurred())
+	md2Name, err := templateNameWithHash(infrastructureMachineTemplateNamePrefix(longClusterName, "md2"), "", template)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(md1Name).ToNot(Equal(md2Name))
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/topology/variables"
+)
+
+// resolveTopologyVariables returns a copy of the Cluster topology where all the variables with a valueFrom
+// are replaced by variables with the value read from the referenced Secret or ConfigMap.
+// NOTE: The Cluster topology is returned as is if none of its variables has a valueFrom; the returned
+// copy must never be written back into the Cluster object, so resolved values are not leaked into the Cluster spec.
+func (r *Reconciler) resolveTopologyVariables(ctx context.Context, cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) (*clusterv1.Topology, error) {
+	if !hasVariablesValueFrom(cluster.Spec.Topology) {
+		return cluster.Spec.Topology, nil
+	}
+
+	topology := cluster.Spec.Topology.DeepCopy()
+	clusterClassVariables := map[string]*clusterv1.ClusterClassVariable{}
+	for i := range clusterClass.Spec.Variables {
+		clusterClassVariables[clusterClass.Spec.Variables[i].Name] = &clusterClass.Spec.Variables[i]
+	}
+
+	var err error
+	topology.Variables, err = r.resolveVariables(ctx, cluster.Namespace, topology.Variables, clusterClassVariables, field.NewPath("spec", "topology", "variables"))
+	if err != nil {
+		return nil, err
+	}
+
+	if topology.Workers != nil {
+		for i := range topology.Workers.MachineDeployments {
+			md := &topology.Workers.MachineDeployments[i]
+			if md.Variables == nil {
+				continue
+			}
+			md.Variables.Overrides, err = r.resolveVariables(ctx, cluster.Namespace, md.Variables.Overrides, clusterClassVariables,
+				field.NewPath("spec", "topology", "workers", "machineDeployments").Index(i).Child("variables", "overrides"))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to resolve variables for MachineDeployment topology %q", md.Name)
+			}
+		}
+	}
+
+	return topology, nil
+}
+
+// resolveVariables resolves the values of the variables with a valueFrom and validates them against the schema of
+// the corresponding ClusterClass variable.
+// NOTE: Variables with an optional valueFrom pointing to a missing Secret, ConfigMap or key are dropped.
+func (r *Reconciler) resolveVariables(ctx context.Context, namespace string, clusterVariables []clusterv1.ClusterVariable, clusterClassVariables map[string]*clusterv1.ClusterClassVariable, fldPath *field.Path) ([]clusterv1.ClusterVariable, error) {
+	resolved := make([]clusterv1.ClusterVariable, 0, len(clusterVariables))
+	var allErrs field.ErrorList
+	for i := range clusterVariables {
+		clusterVariable := clusterVariables[i]
+		if clusterVariable.ValueFrom == nil {
+			resolved = append(resolved, clusterVariable)
+			continue
+		}
+
+		clusterClassVariable, ok := clusterClassVariables[clusterVariable.Name]
+		if !ok {
+			return nil, errors.Errorf("variable %q is not defined in the ClusterClass", clusterVariable.Name)
+		}
+
+		data, found, err := r.getVariableSourceData(ctx, namespace, clusterVariable.ValueFrom)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve value of variable %q", clusterVariable.Name)
+		}
+		if !found {
+			continue
+		}
+
+		value, err := variableValueFromData(data, clusterClassVariable)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve value of variable %q", clusterVariable.Name)
+		}
+
+		resolvedVariable := clusterv1.ClusterVariable{
+			Name:  clusterVariable.Name,
+			Value: value,
+		}
+		allErrs = append(allErrs, variables.ValidateClusterVariable(&resolvedVariable, clusterClassVariable, fldPath.Index(i))...)
+		resolved = append(resolved, resolvedVariable)
+	}
+
+	if len(allErrs) > 0 {
+		// NOTE: The error does not include the invalid values, given that they can contain sensitive data.
+		errs := make([]error, 0, len(allErrs))
+		for _, err := range allErrs {
+			errs = append(errs, errors.Errorf("%s: %s", err.Field, err.Detail))
+		}
+		return nil, errors.Wrap(kerrors.NewAggregate(errs), "failed to validate resolved variables")
+	}
+
+	return resolved, nil
+}
+
+// getVariableSourceData returns the data of the Secret or ConfigMap key referenced by a variable valueFrom.
+// If the Secret, the ConfigMap or the key do not exist and the reference is optional, found is false.
+func (r *Reconciler) getVariableSourceData(ctx context.Context, namespace string, valueFrom *clusterv1.ClusterVariableValueSource) (data []byte, found bool, err error) {
+	switch {
+	case valueFrom.SecretKeyRef != nil:
+		ref := valueFrom.SecretKeyRef
+		optional := ref.Optional != nil && *ref.Optional
+
+		secret := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) && optional {
+				return nil, false, nil
+			}
+			return nil, false, errors.Wrapf(err, "failed to get Secret %s/%s", namespace, ref.Name)
+		}
+		data, ok := secret.Data[ref.Key]
+		if !ok {
+			if optional {
+				return nil, false, nil
+			}
+			return nil, false, errors.Errorf("key %q not found in Secret %s/%s", ref.Key, namespace, ref.Name)
+		}
+		return data, true, nil
+	case valueFrom.ConfigMapKeyRef != nil:
+		ref := valueFrom.ConfigMapKeyRef
+		optional := ref.Optional != nil && *ref.Optional
+
+		configMap := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, configMap); err != nil {
+			if apierrors.IsNotFound(err) && optional {
+				return nil, false, nil
+			}
+			return nil, false, errors.Wrapf(err, "failed to get ConfigMap %s/%s", namespace, ref.Name)
+		}
+		if data, ok := configMap.Data[ref.Key]; ok {
+			return []byte(data), true, nil
+		}
+		if data, ok := configMap.BinaryData[ref.Key]; ok {
+			return data, true, nil
+		}
+		if optional {
+			return nil, false, nil
+		}
+		return nil, false, errors.Errorf("key %q not found in ConfigMap %s/%s", ref.Key, namespace, ref.Name)
+	default:
+		return nil, false, errors.New("either secretKeyRef or configMapKeyRef must be set")
+	}
+}
+
+// variableValueFromData converts the data read from a Secret or ConfigMap key into a variable value.
+// If the ClusterClass variable is of type string the data is used as is, otherwise the data must be a valid JSON value.
+func variableValueFromData(data []byte, clusterClassVariable *clusterv1.ClusterClassVariable) (apiextensionsv1.JSON, error) {
+	if clusterClassVariable.Schema.OpenAPIV3Schema.Type == "string" {
+		raw, err := json.Marshal(string(data))
+		if err != nil {
+			return apiextensionsv1.JSON{}, errors.Wrap(err, "failed to marshal value")
+		}
+		return apiextensionsv1.JSON{Raw: raw}, nil
+	}
+
+	if !json.Valid(data) {
+		// NOTE: The error does not include the value, given that it can contain sensitive data.
+		return apiextensionsv1.JSON{}, errors.Errorf("value must be valid JSON for variables of type %q", clusterClassVariable.Schema.OpenAPIV3Schema.Type)
+	}
+	return apiextensionsv1.JSON{Raw: data}, nil
+}
+
+// hasVariablesValueFrom returns true if any of the variables in the Cluster topology has a valueFrom.
+func hasVariablesValueFrom(topology *clusterv1.Topology) bool {
+	for _, v := range topology.Variables {
+		if v.ValueFrom != nil {
+			return true
+		}
+	}
+	if topology.Workers != nil {
+		for _, md := range topology.Workers.MachineDeployments {
+			if md.Variables == nil {
+				continue
+			}
+			for _, v := range md.Variables.Overrides {
+				if v.ValueFrom != nil {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestResolveTopologyVariables(t *testing.T) {
+	clusterClass := &clusterv1.ClusterClass{
+		Spec: clusterv1.ClusterClassSpec{
+			Variables: []clusterv1.ClusterClassVariable{
+				{
+					Name: "registryPassword",
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "string"},
+					},
+				},
+				{
+					Name: "registry",
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]clusterv1.JSONSchemaProps{
+								"mirror": {Type: "string"},
+							},
+						},
+					},
+				},
+				{
+					Name: "replicas",
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "integer"},
+					},
+				},
+			},
+		},
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "registry-credentials"},
+		Data: map[string][]byte{
+			"password": []byte("s3cr3t"),
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "registry"},
+		Data: map[string]string{
+			"registry": `{"mirror":"https://mirror.example.com"}`,
+			"invalid":  `{"mirror":1}`,
+			"replicas": "3",
+		},
+	}
+
+	secretKeyRef := func(name, key string, optional bool) *clusterv1.ClusterVariableValueSource {
+		return &clusterv1.ClusterVariableValueSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+				Optional:             pointer.Bool(optional),
+			},
+		}
+	}
+	configMapKeyRef := func(name, key string) *clusterv1.ClusterVariableValueSource {
+		return &clusterv1.ClusterVariableValueSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		topology *clusterv1.Topology
+		want     *clusterv1.Topology
+		wantErr  bool
+	}{
+		{
+			name: "Variables without valueFrom are returned as is",
+			topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+				},
+			},
+			want: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+				},
+			},
+		},
+		{
+			name: "Variables with valueFrom are resolved",
+			topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "registryPassword", ValueFrom: secretKeyRef("registry-credentials", "password", false)},
+					{Name: "registry", ValueFrom: configMapKeyRef("registry", "registry")},
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+				},
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{
+							Name: "md1",
+							Variables: &clusterv1.MachineDeploymentVariables{
+								Overrides: []clusterv1.ClusterVariable{
+									{Name: "replicas", ValueFrom: configMapKeyRef("registry", "replicas")},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "registryPassword", Value: apiextensionsv1.JSON{Raw: []byte(`"s3cr3t"`)}},
+					{Name: "registry", Value: apiextensionsv1.JSON{Raw: []byte(`{"mirror":"https://mirror.example.com"}`)}},
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`1`)}},
+				},
+				Workers: &clusterv1.WorkersTopology{
+					MachineDeployments: []clusterv1.MachineDeploymentTopology{
+						{
+							Name: "md1",
+							Variables: &clusterv1.MachineDeploymentVariables{
+								Overrides: []clusterv1.ClusterVariable{
+									{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`3`)}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Variables with an optional valueFrom pointing to a missing key are dropped",
+			topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "registryPassword", ValueFrom: secretKeyRef("registry-credentials", "does-not-exist", true)},
+				},
+			},
+			want: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{},
+			},
+		},
+		{
+			name: "Error if the Secret does not exist",
+			topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "registryPassword", ValueFrom: secretKeyRef("does-not-exist", "password", false)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error if the resolved value is not valid JSON",
+			topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "registry", ValueFrom: secretKeyRef("registry-credentials", "password", false)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error if the resolved value does not match the schema",
+			topology: &clusterv1.Topology{
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "registry", ValueFrom: configMapKeyRef("registry", "invalid")},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1"},
+				Spec: clusterv1.ClusterSpec{
+					Topology: tt.topology,
+				},
+			}
+			original := cluster.DeepCopy()
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(secret, configMap).
+				Build()
+			r := &Reconciler{
+				Client: fakeClient,
+			}
+
+			got, err := r.resolveTopologyVariables(ctx, cluster, clusterClass)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+
+			// Resolved values must never be written into the Cluster.
+			g.Expect(cluster).To(Equal(original))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// webhookUnavailableRequeueAfter is the base delay before reconciling again a Cluster topology blocked by a webhook
+	// which is not available.
+	webhookUnavailableRequeueAfter = 15 * time.Second
+
+	// webhookUnavailableRequeueJitter is the jitter factor applied to webhookUnavailableRequeueAfter, so Clusters
+	// blocked by the same webhook do not retry all at the same time when the webhook becomes available again.
+	webhookUnavailableRequeueJitter = 1.0
+)
+
+var (
+	// webhookNameRegexp matches the name of a failing admission webhook in an error returned by the API server.
+	webhookNameRegexp = regexp.MustCompile(`failed calling webhook "([^"]+)"`)
+
+	// conversionWebhookRegexp matches the resource of a failing conversion webhook in an error returned by the API server.
+	conversionWebhookRegexp = regexp.MustCompile(`conversion webhook for (.+?) failed`)
+
+	// webhookServiceURLRegexp matches the name and the namespace of the service of a webhook in the URL of the webhook.
+	webhookServiceURLRegexp = regexp.MustCompile(`https://([a-z0-9-]+)\.([a-z0-9-]+)\.svc`)
+
+	// webhookServiceNoEndpointsRegexp matches the name of the service of a webhook without endpoints.
+	webhookServiceNoEndpointsRegexp = regexp.MustCompile(`no endpoints available for service \\?"([^"\\]+)\\?"`)
+
+	// webhookUnavailableMessages are the messages in errors returned by the API server when a webhook is not reachable.
+	webhookUnavailableMessages = []string{
+		"connection refused",
+		"connection reset by peer",
+		"no endpoints available for service",
+		"no such host",
+		"i/o timeout",
+		"context deadline exceeded",
+		"service unavailable",
+	}
+)
+
+// unavailableWebhook provides details about a webhook which is not available.
+type unavailableWebhook struct {
+	// Webhook is the name of the admission webhook or, for conversion webhooks, the resource being converted
+	// followed by "conversion".
+	Webhook string
+
+	// Service is the service of the webhook, if known, in the namespace/name format.
+	Service string
+}
+
+// Message returns a message describing the webhook which is not available.
+func (e *unavailableWebhook) Message() string {
+	service := e.Service
+	if service == "" {
+		service = "unknown"
+	}
+	return fmt.Sprintf("Webhook %q is not available (service: %s)", e.Webhook, service)
+}
+
+// getUnavailableWebhook returns details about the webhook which is not available if the error, which can be
+// an error wrapping the errors returned by the API server, is caused by a webhook not reachable; nil otherwise.
+// NOTE: If the error aggregates the errors of steps applied concurrently, all of them must be caused by a webhook
+// not reachable, so other errors are not hidden by the backoff.
+func getUnavailableWebhook(err error) *unavailableWebhook {
+	if err == nil {
+		return nil
+	}
+
+	var aggregate kerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		var webhook *unavailableWebhook
+		for _, e := range aggregate.Errors() {
+			w := getUnavailableWebhook(e)
+			if w == nil {
+				return nil
+			}
+			if webhook == nil {
+				webhook = w
+			}
+		}
+		return webhook
+	}
+	msg := err.Error()
+
+	unavailable := false
+	for _, m := range webhookUnavailableMessages {
+		if strings.Contains(msg, m) {
+			unavailable = true
+			break
+		}
+	}
+	if !unavailable {
+		return nil
+	}
+
+	webhook := &unavailableWebhook{}
+	if match := webhookNameRegexp.FindStringSubmatch(msg); match != nil {
+		webhook.Webhook = match[1]
+	} else if match := conversionWebhookRegexp.FindStringSubmatch(msg); match != nil {
+		webhook.Webhook = fmt.Sprintf("%s conversion", match[1])
+	} else {
+		return nil
+	}
+
+	if match := webhookServiceURLRegexp.FindStringSubmatch(msg); match != nil {
+		webhook.Service = fmt.Sprintf("%s/%s", match[2], match[1])
+	} else if match := webhookServiceNoEndpointsRegexp.FindStringSubmatch(msg); match != nil {
+		webhook.Service = match[1]
+	}
+	return webhook
+}
+
+// webhookUnavailableBackoff returns the delay before reconciling again a Cluster topology blocked by a webhook.
+func webhookUnavailableBackoff() time.Duration {
+	return wait.Jitter(webhookUnavailableRequeueAfter, webhookUnavailableRequeueJitter)
+}
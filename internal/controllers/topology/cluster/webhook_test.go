@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+func TestGetUnavailableWebhook(t *testing.T) {
+	noEndpointsErr := errors.New(`Internal error occurred: failed calling webhook "validation.kubeadmcontrolplane.controlplane.cluster.x-k8s.io": ` +
+		`failed to call webhook: Post "https://capi-kubeadm-control-plane-webhook-service.capi-kubeadm-control-plane-system.svc:443/validate?timeout=10s": ` +
+		`no endpoints available for service "capi-kubeadm-control-plane-webhook-service"`)
+	timeoutErr := errors.New(`conversion webhook for infrastructure.cluster.x-k8s.io/v1beta1, Kind=DockerMachineTemplate failed: ` +
+		`Post "https://capd-webhook-service.capd-system.svc:443/convert?timeout=30s": context deadline exceeded`)
+
+	tests := []struct {
+		name string
+		err  error
+		want *unavailableWebhook
+	}{
+		{
+			name: "No error",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "Error not related to webhooks",
+			err:  errors.New("failed to create DockerCluster: connection refused"),
+			want: nil,
+		},
+		{
+			name: "Error from a webhook rejecting the object",
+			err: errors.New(`admission webhook "validation.dockercluster.infrastructure.cluster.x-k8s.io" denied the request: ` +
+				`spec.foo: Invalid value`),
+			want: nil,
+		},
+		{
+			name: "Admission webhook with connection refused",
+			err: errors.Wrap(errors.New(`Internal error occurred: failed calling webhook "default.dockercluster.infrastructure.cluster.x-k8s.io": `+
+				`failed to call webhook: Post "https://capd-webhook-service.capd-system.svc:443/mutate-infrastructure-cluster-x-k8s-io-v1beta1-dockercluster?timeout=10s": `+
+				`dial tcp 10.96.10.10:443: connect: connection refused`), "failed to create DockerCluster"),
+			want: &unavailableWebhook{
+				Webhook: "default.dockercluster.infrastructure.cluster.x-k8s.io",
+				Service: "capd-system/capd-webhook-service",
+			},
+		},
+		{
+			name: "Admission webhook without endpoints",
+			err:  noEndpointsErr,
+			want: &unavailableWebhook{
+				Webhook: "validation.kubeadmcontrolplane.controlplane.cluster.x-k8s.io",
+				Service: "capi-kubeadm-control-plane-system/capi-kubeadm-control-plane-webhook-service",
+			},
+		},
+		{
+			name: "Conversion webhook with timeout",
+			err:  timeoutErr,
+			want: &unavailableWebhook{
+				Webhook: "infrastructure.cluster.x-k8s.io/v1beta1, Kind=DockerMachineTemplate conversion",
+				Service: "capd-system/capd-webhook-service",
+			},
+		},
+		{
+			name: "Errors of concurrent steps all caused by webhooks not available",
+			err:  errors.Wrap(kerrors.NewAggregate([]error{noEndpointsErr, timeoutErr}), "error reconciling the Cluster topology"),
+			want: &unavailableWebhook{
+				Webhook: "validation.kubeadmcontrolplane.controlplane.cluster.x-k8s.io",
+				Service: "capi-kubeadm-control-plane-system/capi-kubeadm-control-plane-webhook-service",
+			},
+		},
+		{
+			name: "Errors of concurrent steps not all caused by webhooks not available",
+			err:  errors.Wrap(kerrors.NewAggregate([]error{noEndpointsErr, errors.New("failed to create DockerCluster: invalid")}), "error reconciling the Cluster topology"),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(getUnavailableWebhook(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestWebhookUnavailableBackoff(t *testing.T) {
+	g := NewWithT(t)
+
+	for i := 0; i < 10; i++ {
+		backoff := webhookUnavailableBackoff()
+		g.Expect(backoff).To(BeNumerically(">=", webhookUnavailableRequeueAfter))
+		g.Expect(backoff).To(BeNumerically("<=", 2*webhookUnavailableRequeueAfter))
+	}
+}
@@ -0,0 +1,348 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/api/v1beta1/index"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	tlog "sigs.k8s.io/cluster-api/internal/log"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io;bootstrap.cluster.x-k8s.io;controlplane.cluster.x-k8s.io,resources=*,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusterclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments;machinesets,verbs=get;list;watch
+
+// Reconciler deletes orphaned objects generated from a Cluster topology, i.e. objects with the topology owned label
+// for a Cluster which are no longer referenced by the Cluster, its ControlPlane, MachineDeployments or MachineSets,
+// e.g. templates left behind by failed template rotations or by interrupted reconciles.
+// Clusters are checked periodically and when their ClusterClass changes, not on every change of the Cluster.
+// NOTE: Orphaned objects are deleted only after the GracePeriod is expired, so objects being created by the topology
+// controller, which are going to be referenced by the next patch of the Cluster topology, are not deleted; this also
+// allows to read objects from the cache, given that references to objects just created may not be in the cache yet.
+type Reconciler struct {
+	Client client.Client
+	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
+	// thus allowing to optimize reads for templates or provider specific objects.
+	UnstructuredCachingClient client.Client
+	WatchFilterValue          string
+
+	// Interval is the interval between periodic checks for orphaned objects.
+	Interval time.Duration
+
+	// GracePeriod is the minimum age of an orphaned object before it is deleted.
+	GracePeriod time.Duration
+}
+
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&clusterv1.Cluster{}, builder.WithPredicates(
+			// Only check Clusters with topology.
+			predicates.ClusterHasTopology(ctrl.LoggerFrom(ctx)),
+			// Clusters are checked periodically, so changes of the Cluster do not trigger a check.
+			predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool { return false }},
+		)).
+		Named("topology/garbagecollector").
+		Watches(
+			&source.Kind{Type: &clusterv1.ClusterClass{}},
+			handler.EnqueueRequestsFromMapFunc(r.clusterClassToCluster),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
+
+// Reconcile deletes orphaned objects generated from the topology of a Cluster, and requeues the Cluster
+// after the Interval for the next check.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Fetch the Cluster instance.
+	cluster := &clusterv1.Cluster{}
+	if err := r.Client.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			deleteOrphanMetrics(req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	// Return early if the Cluster does not use a managed topology or if it is deleted; objects
+	// generated from the Cluster topology are deleted together with the Cluster.
+	if cluster.Spec.Topology == nil || !cluster.DeletionTimestamp.IsZero() {
+		deleteOrphanMetrics(cluster.Namespace, cluster.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// Return early if the Cluster or its topology is paused.
+	if annotations.IsPaused(cluster, cluster) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+	if _, ok := cluster.Annotations[clusterv1.ClusterTopologyPausedAnnotation]; ok {
+		log.Info("Reconciliation of the Cluster topology is paused")
+		return ctrl.Result{}, nil
+	}
+
+	ctx = ctrl.LoggerInto(ctx, log.WithValues("ClusterClass", klog.KRef(cluster.Namespace, cluster.Spec.Topology.Class)))
+
+	requeueAfter, err := r.reconcile(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if r.Interval > 0 && (requeueAfter == 0 || r.Interval < requeueAfter) {
+		requeueAfter = r.Interval
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// clusterClassToCluster is a handler.ToRequestsFunc to be used to enqueue requests for the Clusters using
+// a ClusterClass when it gets updated, given that changes of the ClusterClass lead to template rotations.
+func (r *Reconciler) clusterClassToCluster(o client.Object) []ctrl.Request {
+	clusterClass, ok := o.(*clusterv1.ClusterClass)
+	if !ok {
+		panic(fmt.Sprintf("Expected a ClusterClass but got a %T", o))
+	}
+
+	clusterList := &clusterv1.ClusterList{}
+	if err := r.Client.List(
+		context.TODO(),
+		clusterList,
+		client.MatchingFields{index.ClusterClassNameField: clusterClass.Name},
+		client.InNamespace(clusterClass.Namespace),
+	); err != nil {
+		return nil
+	}
+
+	requests := []ctrl.Request{}
+	for i := range clusterList.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&clusterList.Items[i])})
+	}
+	return requests
+}
+
+// reconcile deletes the orphaned objects of a Cluster which are older than the grace period; it returns
+// the time after which the remaining orphaned objects are going to be older than the grace period, if any.
+func (r *Reconciler) reconcile(ctx context.Context, cluster *clusterv1.Cluster) (time.Duration, error) {
+	log := tlog.LoggerFrom(ctx)
+
+	clusterClass := &clusterv1.ClusterClass{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.Topology.Class}, clusterClass); err != nil {
+		return 0, errors.Wrapf(err, "failed to get ClusterClass %s", cluster.Spec.Topology.Class)
+	}
+
+	inUse, err := r.getObjectsInUse(ctx, cluster)
+	if err != nil {
+		return 0, err
+	}
+
+	orphans, err := r.getOrphans(ctx, cluster, clusterClass, inUse)
+	if err != nil {
+		return 0, err
+	}
+	reportOrphanMetrics(cluster, orphans)
+
+	var requeueAfter time.Duration
+	var errs []error
+	for _, orphan := range orphans {
+		age := time.Since(orphan.GetCreationTimestamp().Time)
+		if age < r.GracePeriod {
+			if remaining := r.GracePeriod - age; requeueAfter == 0 || remaining < requeueAfter {
+				requeueAfter = remaining
+			}
+			continue
+		}
+
+		log.Infof("Deleting orphaned %s", tlog.KObj{Obj: orphan})
+		if err := r.Client.Delete(ctx, orphan); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete orphaned %s", tlog.KObj{Obj: orphan}))
+			continue
+		}
+		orphansDeletedTotal.WithLabelValues(orphan.GetKind()).Inc()
+	}
+	if len(errs) > 0 {
+		return 0, kerrors.NewAggregate(errs)
+	}
+	return requeueAfter, nil
+}
+
+// getObjectsInUse returns the IDs of the objects referenced by the Cluster, its ControlPlane, MachineDeployments
+// and MachineSets, including the ones being deleted, whose templates are deleted by the topology controllers
+// for MachineDeployments and MachineSets.
+func (r *Reconciler) getObjectsInUse(ctx context.Context, cluster *clusterv1.Cluster) (map[string]bool, error) {
+	inUse := map[string]bool{}
+	if err := addObjectRefs(inUse, cluster.Spec.InfrastructureRef, cluster.Spec.ControlPlaneRef); err != nil {
+		return nil, errors.Wrapf(err, "failed to add objects referenced by %s", tlog.KObj{Obj: cluster})
+	}
+
+	if cluster.Spec.ControlPlaneRef != nil {
+		controlPlane, err := external.Get(ctx, r.UnstructuredCachingClient, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get ControlPlane for %s", tlog.KObj{Obj: cluster})
+		}
+		// NOTE: The infrastructure machine templates are optional, e.g. for managed control planes.
+		if ref, err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Get(controlPlane); err == nil {
+			if err := addObjectRefs(inUse, ref); err != nil {
+				return nil, errors.Wrapf(err, "failed to add objects referenced by %s", tlog.KObj{Obj: controlPlane})
+			}
+		}
+		failureDomainRefs, err := contract.ControlPlane().MachineTemplate().FailureDomainInfrastructureRefs().Get(controlPlane)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get failure domain InfrastructureMachineTemplate references for %s", tlog.KObj{Obj: controlPlane})
+		}
+		for _, ref := range failureDomainRefs {
+			if err := addObjectRefs(inUse, ref); err != nil {
+				return nil, errors.Wrapf(err, "failed to add objects referenced by %s", tlog.KObj{Obj: controlPlane})
+			}
+		}
+	}
+
+	mdList := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, mdList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineDeployments for %s", tlog.KObj{Obj: cluster})
+	}
+	for i := range mdList.Items {
+		md := &mdList.Items[i]
+		if err := addObjectRefs(inUse, md.Spec.Template.Spec.Bootstrap.ConfigRef, &md.Spec.Template.Spec.InfrastructureRef); err != nil {
+			return nil, errors.Wrapf(err, "failed to add objects referenced by %s", tlog.KObj{Obj: md})
+		}
+	}
+
+	msList := &clusterv1.MachineSetList{}
+	if err := r.Client.List(ctx, msList, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list MachineSets for %s", tlog.KObj{Obj: cluster})
+	}
+	for i := range msList.Items {
+		ms := &msList.Items[i]
+		if err := addObjectRefs(inUse, ms.Spec.Template.Spec.Bootstrap.ConfigRef, &ms.Spec.Template.Spec.InfrastructureRef); err != nil {
+			return nil, errors.Wrapf(err, "failed to add objects referenced by %s", tlog.KObj{Obj: ms})
+		}
+	}
+
+	return inUse, nil
+}
+
+// getOrphans returns the objects generated from the Cluster topology which are not in use; objects are looked up
+// for all the kinds of objects generated from the templates of the ClusterClass.
+func (r *Reconciler) getOrphans(ctx context.Context, cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass, inUse map[string]bool) ([]*unstructured.Unstructured, error) {
+	orphans := []*unstructured.Unstructured{}
+	for _, gvk := range generatedKinds(clusterClass) {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.UnstructuredCachingClient.List(ctx, list, client.InNamespace(cluster.Namespace), client.MatchingLabels{
+			clusterv1.ClusterLabelName:          cluster.Name,
+			clusterv1.ClusterTopologyOwnedLabel: "",
+		}); err != nil {
+			return nil, errors.Wrapf(err, "failed to list %s objects for %s", gvk.Kind, tlog.KObj{Obj: cluster})
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !obj.GetDeletionTimestamp().IsZero() || inUse[objectID(gvk.Group, gvk.Kind, obj.GetName())] {
+				continue
+			}
+			orphans = append(orphans, obj)
+		}
+	}
+	return orphans, nil
+}
+
+// generatedKinds returns the kinds of the objects generated from the templates of a ClusterClass, i.e. the
+// InfrastructureCluster, the ControlPlane and the templates cloned for the control plane and the MachineDeployments.
+func generatedKinds(clusterClass *clusterv1.ClusterClass) []schema.GroupVersionKind {
+	kinds := []schema.GroupVersionKind{}
+	seen := map[schema.GroupKind]bool{}
+	add := func(ref *corev1.ObjectReference, trimTemplateSuffix bool) {
+		if ref == nil {
+			return
+		}
+		gvk := ref.GroupVersionKind()
+		if trimTemplateSuffix {
+			gvk.Kind = strings.TrimSuffix(gvk.Kind, clusterv1.TemplateSuffix)
+		}
+		if seen[gvk.GroupKind()] {
+			return
+		}
+		seen[gvk.GroupKind()] = true
+		kinds = append(kinds, gvk)
+	}
+
+	add(clusterClass.Spec.Infrastructure.Ref, true)
+	add(clusterClass.Spec.ControlPlane.Ref, true)
+	if clusterClass.Spec.ControlPlane.MachineInfrastructure != nil {
+		add(clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref, false)
+	}
+	for _, fd := range clusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure {
+		add(fd.Ref, false)
+	}
+	for _, mdClass := range clusterClass.Spec.Workers.MachineDeployments {
+		add(mdClass.Template.Bootstrap.Ref, false)
+		add(mdClass.Template.Infrastructure.Ref, false)
+	}
+	return kinds
+}
+
+// addObjectRefs adds the refs to the refMap with the objectID as key.
+func addObjectRefs(refMap map[string]bool, refs ...*corev1.ObjectReference) error {
+	for _, ref := range refs {
+		if ref == nil {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse apiVersion %q", ref.APIVersion)
+		}
+		refMap[objectID(gv.Group, ref.Kind, ref.Name)] = true
+	}
+	return nil
+}
+
+// objectID returns the ID of an object in the format: g/k/name.
+// Note: We don't include the version as references with different versions should be treated as equal.
+func objectID(group, kind, name string) string {
+	return fmt.Sprintf("%s/%s/%s", group, kind, name)
+}
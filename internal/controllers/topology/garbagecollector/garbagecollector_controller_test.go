@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+)
+
+func TestReconcile(t *testing.T) {
+	gracePeriod := 10 * time.Minute
+	interval := 30 * time.Minute
+	oldEnough := metav1.NewTime(time.Now().Add(-2 * gracePeriod))
+	recent := metav1.NewTime(time.Now().Add(-gracePeriod / 2))
+
+	// generated returns an object generated from the topology of cluster1, created at the given time.
+	generated := func(obj *unstructured.Unstructured, creationTimestamp metav1.Time) *unstructured.Unstructured {
+		obj.SetLabels(map[string]string{
+			clusterv1.ClusterLabelName:          "cluster1",
+			clusterv1.ClusterTopologyOwnedLabel: "",
+		})
+		obj.SetCreationTimestamp(creationTimestamp)
+		return obj
+	}
+
+	infrastructureCluster := generated(builder.InfrastructureCluster(metav1.NamespaceDefault, "infra-cluster").Build(), oldEnough)
+	controlPlaneInfrastructureMachineTemplate := generated(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cp-infra").Build(), oldEnough)
+	controlPlane := generated(builder.ControlPlane(metav1.NamespaceDefault, "cp").
+		WithInfrastructureMachineTemplate(controlPlaneInfrastructureMachineTemplate).Build(), oldEnough)
+	mdBootstrapTemplate := generated(builder.BootstrapTemplate(metav1.NamespaceDefault, "md-bootstrap").Build(), oldEnough)
+	mdInfrastructureMachineTemplate := generated(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "md-infra").Build(), oldEnough)
+	msInfrastructureMachineTemplate := generated(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "ms-infra").Build(), oldEnough)
+
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra-cluster-template").Build()).
+		WithControlPlaneTemplate(builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp-template").Build()).
+		WithControlPlaneInfrastructureMachineTemplate(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cp-infra-template").Build()).
+		WithWorkerMachineDeploymentClasses(*builder.MachineDeploymentClass("worker").
+			WithBootstrapTemplate(builder.BootstrapTemplate(metav1.NamespaceDefault, "md-bootstrap-template").Build()).
+			WithInfrastructureTemplate(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "md-infra-template").Build()).
+			Build()).
+		Build()
+
+	cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+		WithInfrastructureCluster(infrastructureCluster).
+		WithControlPlane(controlPlane).
+		WithTopology(builder.ClusterTopology().WithClass(clusterClass.Name).Build()).
+		Build()
+
+	md := builder.MachineDeployment(metav1.NamespaceDefault, "md").
+		WithClusterName(cluster.Name).
+		WithLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name}).
+		WithBootstrapTemplate(mdBootstrapTemplate).
+		WithInfrastructureTemplate(mdInfrastructureMachineTemplate).
+		Build()
+	ms := builder.MachineSet(metav1.NamespaceDefault, "ms").
+		WithClusterName(cluster.Name).
+		WithLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name}).
+		WithBootstrapTemplate(mdBootstrapTemplate).
+		WithInfrastructureTemplate(msInfrastructureMachineTemplate).
+		Build()
+
+	orphanedInfrastructureCluster := generated(builder.InfrastructureCluster(metav1.NamespaceDefault, "orphaned-infra-cluster").Build(), oldEnough)
+	orphanedInfrastructureMachineTemplate := generated(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "orphaned-infra").Build(), oldEnough)
+	orphanedBootstrapTemplate := generated(builder.BootstrapTemplate(metav1.NamespaceDefault, "orphaned-bootstrap").Build(), oldEnough)
+	recentOrphanedInfrastructureMachineTemplate := generated(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "recent-orphaned-infra").Build(), recent)
+	notOwnedInfrastructureMachineTemplate := builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "not-owned-infra").Build()
+	notOwnedInfrastructureMachineTemplate.SetLabels(map[string]string{clusterv1.ClusterLabelName: cluster.Name})
+	otherClusterInfrastructureMachineTemplate := generated(builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "other-cluster-infra").Build(), oldEnough)
+	otherClusterInfrastructureMachineTemplate.SetLabels(map[string]string{clusterv1.ClusterLabelName: "cluster2", clusterv1.ClusterTopologyOwnedLabel: ""})
+
+	t.Run("Deletes orphaned objects older than the grace period", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeScheme).
+			WithObjects(clusterClass, cluster, md, ms,
+				infrastructureCluster, controlPlane, controlPlaneInfrastructureMachineTemplate,
+				mdBootstrapTemplate, mdInfrastructureMachineTemplate, msInfrastructureMachineTemplate,
+				orphanedInfrastructureCluster, orphanedInfrastructureMachineTemplate, orphanedBootstrapTemplate,
+				recentOrphanedInfrastructureMachineTemplate, notOwnedInfrastructureMachineTemplate, otherClusterInfrastructureMachineTemplate).
+			Build()
+
+		r := &Reconciler{
+			Client:                    fakeClient,
+			UnstructuredCachingClient: fakeClient,
+			Interval:                  interval,
+			GracePeriod:               gracePeriod,
+		}
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		g.Expect(err).ToNot(HaveOccurred())
+		// The Cluster is requeued when the recent orphaned object is going to be older than the grace period.
+		g.Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		g.Expect(result.RequeueAfter).To(BeNumerically("<=", gracePeriod/2))
+
+		for _, obj := range []*unstructured.Unstructured{orphanedInfrastructureCluster, orphanedInfrastructureMachineTemplate, orphanedBootstrapTemplate} {
+			g.Expect(apierrors.IsNotFound(fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopy()))).To(BeTrue(), "%s should be deleted", obj.GetName())
+		}
+		for _, obj := range []*unstructured.Unstructured{
+			infrastructureCluster, controlPlane, controlPlaneInfrastructureMachineTemplate,
+			mdBootstrapTemplate, mdInfrastructureMachineTemplate, msInfrastructureMachineTemplate,
+			recentOrphanedInfrastructureMachineTemplate, notOwnedInfrastructureMachineTemplate, otherClusterInfrastructureMachineTemplate,
+		} {
+			g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopy())).To(Succeed(), "%s should not be deleted", obj.GetName())
+		}
+	})
+
+	t.Run("Requeues after the interval if there are no orphaned objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeScheme).
+			WithObjects(clusterClass, cluster, md, ms,
+				infrastructureCluster, controlPlane, controlPlaneInfrastructureMachineTemplate,
+				mdBootstrapTemplate, mdInfrastructureMachineTemplate, msInfrastructureMachineTemplate).
+			Build()
+
+		r := &Reconciler{
+			Client:                    fakeClient,
+			UnstructuredCachingClient: fakeClient,
+			Interval:                  interval,
+			GracePeriod:               gracePeriod,
+		}
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.RequeueAfter).To(Equal(interval))
+	})
+
+	t.Run("Does not delete objects if the Cluster topology is paused", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pausedCluster := cluster.DeepCopy()
+		pausedCluster.Annotations = map[string]string{clusterv1.ClusterTopologyPausedAnnotation: ""}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeScheme).
+			WithObjects(clusterClass, pausedCluster, orphanedInfrastructureMachineTemplate).
+			Build()
+
+		r := &Reconciler{
+			Client:                    fakeClient,
+			UnstructuredCachingClient: fakeClient,
+			Interval:                  interval,
+			GracePeriod:               gracePeriod,
+		}
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(orphanedInfrastructureMachineTemplate), orphanedInfrastructureMachineTemplate.DeepCopy())).To(Succeed())
+	})
+}
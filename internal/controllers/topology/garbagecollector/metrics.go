@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package garbagecollector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(orphansTotal, orphansDeletedTotal)
+}
+
+var (
+	// orphansTotal reports the number of orphaned objects found for a Cluster topology, including
+	// the ones not yet deleted because they are not older than the grace period.
+	orphansTotal = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "capi_topology",
+		Name:      "orphaned_objects",
+		Help:      "Number of orphaned objects generated from a Cluster topology, partitioned by Cluster and kind of the object.",
+	}, []string{"namespace", "cluster", "kind"})
+
+	// orphansDeletedTotal reports the number of orphaned objects deleted.
+	orphansDeletedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "capi_topology",
+		Name:      "orphaned_objects_deleted_total",
+		Help:      "Number of orphaned objects generated from a Cluster topology which have been deleted, partitioned by kind of the object.",
+	}, []string{"kind"})
+)
+
+// reportOrphanMetrics reports the number of orphaned objects found for a Cluster.
+func reportOrphanMetrics(cluster *clusterv1.Cluster, orphans []*unstructured.Unstructured) {
+	// Delete the metrics previously reported for the Cluster, so series for kinds without orphans go away.
+	deleteOrphanMetrics(cluster.Namespace, cluster.Name)
+
+	counts := map[string]float64{}
+	for _, orphan := range orphans {
+		counts[orphan.GetKind()]++
+	}
+	for kind, count := range counts {
+		orphansTotal.WithLabelValues(cluster.Namespace, cluster.Name, kind).Set(count)
+	}
+}
+
+// deleteOrphanMetrics deletes the metrics reported for a Cluster.
+func deleteOrphanMetrics(namespace, name string) {
+	orphansTotal.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "cluster": name})
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deletioncause has helper functions for recording why Machines are deleted.
+package deletioncause
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// DeleteMachine records on the Machine why it is being deleted and by which controller, and then deletes it.
+// The deletion cause is not overridden if already set on the Machine, e.g. by a previous attempt to delete it.
+// NOTE: Callers patching the Machine with a patch helper after the deletion should use SetCause instead, and
+// persist the deletion cause with the patch helper before deleting the Machine; an additional patch would
+// otherwise change the resourceVersion of the Machine, thus making the patch helper fail with a conflict.
+func DeleteMachine(ctx context.Context, c client.Client, machine *clusterv1.Machine, cause, deletedBy string) error {
+	patch := client.MergeFrom(machine.DeepCopy())
+	if SetCause(machine, cause, deletedBy) {
+		if err := c.Patch(ctx, machine, patch); err != nil {
+			return errors.Wrapf(err, "failed to record the deletion cause on Machine %s", machine.Name)
+		}
+	}
+	return c.Delete(ctx, machine)
+}
+
+// SetCause sets on the Machine why it is being deleted and by which controller, without persisting the change,
+// and returns true if the Machine has been changed.
+// The deletion cause is not overridden if already set on the Machine, e.g. by a previous attempt to delete it.
+func SetCause(machine *clusterv1.Machine, cause, deletedBy string) bool {
+	if _, ok := machine.GetAnnotations()[clusterv1.MachineDeletionCauseAnnotation]; ok {
+		return false
+	}
+	annotations := machine.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[clusterv1.MachineDeletionCauseAnnotation] = cause
+	annotations[clusterv1.MachineDeletedByAnnotation] = deletedBy
+	machine.SetAnnotations(annotations)
+	return true
+}
+
+// Cause returns why a Machine has been deleted and by which controller.
+// Machines deleted without the MachineDeletionCauseAnnotation are considered remediated if a remediation was
+// triggered for them, as reported by the MachineOwnerRemediatedCondition, and deleted by the user otherwise.
+func Cause(machine *clusterv1.Machine) (cause, deletedBy string) {
+	if cause, ok := machine.GetAnnotations()[clusterv1.MachineDeletionCauseAnnotation]; ok {
+		return cause, machine.GetAnnotations()[clusterv1.MachineDeletedByAnnotation]
+	}
+	if conditions.Has(machine, clusterv1.MachineOwnerRemediatedCondition) {
+		return clusterv1.MachineDeletionCauseRemediation, ""
+	}
+	return clusterv1.MachineDeletionCauseUser, ""
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletioncause
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestDeleteMachine(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	t.Run("Records the deletion cause before deleting the Machine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "machine",
+				Namespace:  metav1.NamespaceDefault,
+				Finalizers: []string{clusterv1.MachineFinalizer},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine).Build()
+
+		g.Expect(DeleteMachine(context.Background(), c, machine, clusterv1.MachineDeletionCauseScaleDown, "machineset-controller")).To(Succeed())
+
+		got := &clusterv1.Machine{}
+		g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(machine), got)).To(Succeed())
+		g.Expect(got.DeletionTimestamp.IsZero()).To(BeFalse())
+		g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletionCauseAnnotation, clusterv1.MachineDeletionCauseScaleDown))
+		g.Expect(got.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletedByAnnotation, "machineset-controller"))
+	})
+
+	t.Run("Does not override the deletion cause already set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machine := &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine",
+				Namespace: metav1.NamespaceDefault,
+				Annotations: map[string]string{
+					clusterv1.MachineDeletionCauseAnnotation: clusterv1.MachineDeletionCauseRemediation,
+					clusterv1.MachineDeletedByAnnotation:     "machineset-controller",
+				},
+			},
+		}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine).Build()
+
+		g.Expect(DeleteMachine(context.Background(), c, machine, clusterv1.MachineDeletionCauseScaleDown, "kubeadm-control-plane-controller")).To(Succeed())
+		g.Expect(machine.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletionCauseAnnotation, clusterv1.MachineDeletionCauseRemediation))
+		g.Expect(machine.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletedByAnnotation, "machineset-controller"))
+
+		err := c.Get(context.Background(), client.ObjectKeyFromObject(machine), &clusterv1.Machine{})
+		g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+}
+
+func TestSetCause(t *testing.T) {
+	g := NewWithT(t)
+
+	machine := &clusterv1.Machine{}
+	g.Expect(SetCause(machine, clusterv1.MachineDeletionCauseRemediation, "kubeadm-control-plane-controller")).To(BeTrue())
+	g.Expect(machine.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletionCauseAnnotation, clusterv1.MachineDeletionCauseRemediation))
+	g.Expect(machine.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletedByAnnotation, "kubeadm-control-plane-controller"))
+
+	// The deletion cause already set is not overridden.
+	g.Expect(SetCause(machine, clusterv1.MachineDeletionCauseScaleDown, "machineset-controller")).To(BeFalse())
+	g.Expect(machine.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletionCauseAnnotation, clusterv1.MachineDeletionCauseRemediation))
+	g.Expect(machine.Annotations).To(HaveKeyWithValue(clusterv1.MachineDeletedByAnnotation, "kubeadm-control-plane-controller"))
+}
+
+func TestCause(t *testing.T) {
+	tests := []struct {
+		name          string
+		machine       *clusterv1.Machine
+		wantCause     string
+		wantDeletedBy string
+	}{
+		{
+			name: "Cause from annotations",
+			machine: &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						clusterv1.MachineDeletionCauseAnnotation: clusterv1.MachineDeletionCauseRollout,
+						clusterv1.MachineDeletedByAnnotation:     "machineset-controller",
+					},
+				},
+			},
+			wantCause:     clusterv1.MachineDeletionCauseRollout,
+			wantDeletedBy: "machineset-controller",
+		},
+		{
+			name: "Remediation if the MachineOwnerRemediatedCondition is set",
+			machine: &clusterv1.Machine{
+				Status: clusterv1.MachineStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.FalseCondition(clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, ""),
+					},
+				},
+			},
+			wantCause: clusterv1.MachineDeletionCauseRemediation,
+		},
+		{
+			name:      "User otherwise",
+			machine:   &clusterv1.Machine{},
+			wantCause: clusterv1.MachineDeletionCauseUser,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cause, deletedBy := Cause(tt.machine)
+			g.Expect(cause).To(Equal(tt.wantCause))
+			g.Expect(deletedBy).To(Equal(tt.wantDeletedBy))
+		})
+	}
+}
@@ -18,6 +18,7 @@ package builder
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -121,6 +122,7 @@ type ClusterTopologyBuilder struct {
 	controlPlaneReplicas int32
 	controlPlaneMHC      *clusterv1.MachineHealthCheckTopology
 	variables            []clusterv1.ClusterVariable
+	profile              string
 }
 
 // ClusterTopology returns a ClusterTopologyBuilder.
@@ -142,6 +144,12 @@ func (c *ClusterTopologyBuilder) WithVersion(version string) *ClusterTopologyBui
 	return c
 }
 
+// WithProfile adds the passed profile to the ClusterTopologyBuilder.
+func (c *ClusterTopologyBuilder) WithProfile(profile string) *ClusterTopologyBuilder {
+	c.profile = profile
+	return c
+}
+
 // WithControlPlaneReplicas adds the passed replicas value to the ClusterTopologyBuilder.
 func (c *ClusterTopologyBuilder) WithControlPlaneReplicas(replicas int32) *ClusterTopologyBuilder {
 	c.controlPlaneReplicas = replicas
@@ -172,6 +180,7 @@ func (c *ClusterTopologyBuilder) Build() *clusterv1.Topology {
 		Class:   c.class,
 		Workers: c.workers,
 		Version: c.version,
+		Profile: c.profile,
 		ControlPlane: clusterv1.ControlPlaneTopology{
 			Replicas:           &c.controlPlaneReplicas,
 			MachineHealthCheck: c.controlPlaneMHC,
@@ -240,19 +249,22 @@ func (m *MachineDeploymentTopologyBuilder) Build() clusterv1.MachineDeploymentTo
 
 // ClusterClassBuilder holds the variables and objects required to build a clusterv1.ClusterClass.
 type ClusterClassBuilder struct {
-	namespace                                 string
-	name                                      string
-	infrastructureClusterTemplate             *unstructured.Unstructured
-	controlPlaneMetadata                      *clusterv1.ObjectMeta
-	controlPlaneTemplate                      *unstructured.Unstructured
-	controlPlaneInfrastructureMachineTemplate *unstructured.Unstructured
-	controlPlaneMHC                           *clusterv1.MachineHealthCheckClass
-	controlPlaneNodeDrainTimeout              *metav1.Duration
-	controlPlaneNodeVolumeDetachTimeout       *metav1.Duration
-	controlPlaneNodeDeletionTimeout           *metav1.Duration
-	machineDeploymentClasses                  []clusterv1.MachineDeploymentClass
-	variables                                 []clusterv1.ClusterClassVariable
-	patches                                   []clusterv1.ClusterClassPatch
+	namespace                                               string
+	name                                                    string
+	infrastructureClusterTemplate                           *unstructured.Unstructured
+	controlPlaneMetadata                                    *clusterv1.ObjectMeta
+	controlPlaneTemplate                                    *unstructured.Unstructured
+	controlPlaneInfrastructureMachineTemplate               *unstructured.Unstructured
+	controlPlaneFailureDomainInfrastructureMachineTemplates map[string]*unstructured.Unstructured
+	controlPlaneMHC                                         *clusterv1.MachineHealthCheckClass
+	controlPlaneNodeDrainTimeout                            *metav1.Duration
+	controlPlaneNodeVolumeDetachTimeout                     *metav1.Duration
+	controlPlaneNodeDeletionTimeout                         *metav1.Duration
+	controlPlaneAdditionalTags                              map[string]string
+	machineDeploymentClasses                                []clusterv1.MachineDeploymentClass
+	clusterNetwork                                          *clusterv1.ClusterClassNetwork
+	variables                                               []clusterv1.ClusterClassVariable
+	patches                                                 []clusterv1.ClusterClassPatch
 }
 
 // ClusterClass returns a ClusterClassBuilder with the given name and namespace.
@@ -290,6 +302,16 @@ func (c *ClusterClassBuilder) WithControlPlaneInfrastructureMachineTemplate(t *u
 	return c
 }
 
+// WithControlPlaneFailureDomainInfrastructureMachineTemplate adds an InfrastructureMachineTemplate to be used for
+// ControlPlane Machines in the given failure domain to the ClusterClassBuilder.
+func (c *ClusterClassBuilder) WithControlPlaneFailureDomainInfrastructureMachineTemplate(failureDomain string, t *unstructured.Unstructured) *ClusterClassBuilder {
+	if c.controlPlaneFailureDomainInfrastructureMachineTemplates == nil {
+		c.controlPlaneFailureDomainInfrastructureMachineTemplates = map[string]*unstructured.Unstructured{}
+	}
+	c.controlPlaneFailureDomainInfrastructureMachineTemplates[failureDomain] = t
+	return c
+}
+
 // WithControlPlaneMachineHealthCheck adds a MachineHealthCheck for the ControlPlane to the ClusterClassBuilder.
 func (c *ClusterClassBuilder) WithControlPlaneMachineHealthCheck(mhc *clusterv1.MachineHealthCheckClass) *ClusterClassBuilder {
 	c.controlPlaneMHC = mhc
@@ -314,6 +336,18 @@ func (c *ClusterClassBuilder) WithControlPlaneNodeDeletionTimeout(t *metav1.Dura
 	return c
 }
 
+// WithControlPlaneAdditionalTags adds AdditionalTags for the ControlPlane to the ClusterClassBuilder.
+func (c *ClusterClassBuilder) WithControlPlaneAdditionalTags(tags map[string]string) *ClusterClassBuilder {
+	c.controlPlaneAdditionalTags = tags
+	return c
+}
+
+// WithClusterNetwork adds the cluster network defaults to the ClusterClassBuilder.
+func (c *ClusterClassBuilder) WithClusterNetwork(clusterNetwork *clusterv1.ClusterClassNetwork) *ClusterClassBuilder {
+	c.clusterNetwork = clusterNetwork
+	return c
+}
+
 // WithVariables adds the Variables the ClusterClassBuilder.
 func (c *ClusterClassBuilder) WithVariables(vars ...clusterv1.ClusterClassVariable) *ClusterClassBuilder {
 	c.variables = vars
@@ -347,8 +381,9 @@ func (c *ClusterClassBuilder) Build() *clusterv1.ClusterClass {
 			Namespace: c.namespace,
 		},
 		Spec: clusterv1.ClusterClassSpec{
-			Variables: c.variables,
-			Patches:   c.patches,
+			ClusterNetwork: c.clusterNetwork,
+			Variables:      c.variables,
+			Patches:        c.patches,
 		},
 	}
 	if c.infrastructureClusterTemplate != nil {
@@ -376,11 +411,27 @@ func (c *ClusterClassBuilder) Build() *clusterv1.ClusterClass {
 	if c.controlPlaneNodeDeletionTimeout != nil {
 		obj.Spec.ControlPlane.NodeDeletionTimeout = c.controlPlaneNodeDeletionTimeout
 	}
+	if c.controlPlaneAdditionalTags != nil {
+		obj.Spec.ControlPlane.AdditionalTags = c.controlPlaneAdditionalTags
+	}
 	if c.controlPlaneInfrastructureMachineTemplate != nil {
 		obj.Spec.ControlPlane.MachineInfrastructure = &clusterv1.LocalObjectTemplate{
 			Ref: objToRef(c.controlPlaneInfrastructureMachineTemplate),
 		}
 	}
+	failureDomains := make([]string, 0, len(c.controlPlaneFailureDomainInfrastructureMachineTemplates))
+	for failureDomain := range c.controlPlaneFailureDomainInfrastructureMachineTemplates {
+		failureDomains = append(failureDomains, failureDomain)
+	}
+	sort.Strings(failureDomains)
+	for _, failureDomain := range failureDomains {
+		obj.Spec.ControlPlane.FailureDomainMachineInfrastructure = append(obj.Spec.ControlPlane.FailureDomainMachineInfrastructure, clusterv1.FailureDomainMachineInfrastructure{
+			FailureDomain: failureDomain,
+			LocalObjectTemplate: clusterv1.LocalObjectTemplate{
+				Ref: objToRef(c.controlPlaneFailureDomainInfrastructureMachineTemplates[failureDomain]),
+			},
+		})
+	}
 
 	obj.Spec.Workers.MachineDeployments = c.machineDeploymentClasses
 	return obj
@@ -398,6 +449,7 @@ type MachineDeploymentClassBuilder struct {
 	nodeDrainTimeout              *metav1.Duration
 	nodeVolumeDetachTimeout       *metav1.Duration
 	nodeDeletionTimeout           *metav1.Duration
+	additionalTags                map[string]string
 	minReadySeconds               *int32
 	strategy                      *clusterv1.MachineDeploymentStrategy
 }
@@ -463,6 +515,12 @@ func (m *MachineDeploymentClassBuilder) WithNodeDeletionTimeout(t *metav1.Durati
 	return m
 }
 
+// WithAdditionalTags sets the AdditionalTags for the MachineDeploymentClassBuilder.
+func (m *MachineDeploymentClassBuilder) WithAdditionalTags(tags map[string]string) *MachineDeploymentClassBuilder {
+	m.additionalTags = tags
+	return m
+}
+
 // WithMinReadySeconds sets the MinReadySeconds for the MachineDeploymentClassBuilder.
 func (m *MachineDeploymentClassBuilder) WithMinReadySeconds(t *int32) *MachineDeploymentClassBuilder {
 	m.minReadySeconds = t
@@ -507,6 +565,9 @@ func (m *MachineDeploymentClassBuilder) Build() *clusterv1.MachineDeploymentClas
 	if m.nodeDeletionTimeout != nil {
 		obj.NodeDeletionTimeout = m.nodeDeletionTimeout
 	}
+	if m.additionalTags != nil {
+		obj.AdditionalTags = m.additionalTags
+	}
 	if m.minReadySeconds != nil {
 		obj.MinReadySeconds = m.minReadySeconds
 	}
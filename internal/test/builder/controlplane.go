@@ -120,6 +120,12 @@ var (
 					"infrastructureRef":   refSchema,
 					"nodeDeletionTimeout": {Type: "string"},
 					"nodeDrainTimeout":    {Type: "string"},
+					"additionalTags": {
+						Type: "object",
+						AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+							Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+						},
+					},
 				},
 			},
 			// General purpose fields to be used in different test scenario.
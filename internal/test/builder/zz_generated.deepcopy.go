@@ -23,6 +23,7 @@ package builder
 
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 	apiv1beta1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -114,6 +115,21 @@ func (in *ClusterClassBuilder) DeepCopyInto(out *ClusterClassBuilder) {
 		in, out := &in.controlPlaneInfrastructureMachineTemplate, &out.controlPlaneInfrastructureMachineTemplate
 		*out = (*in).DeepCopy()
 	}
+	if in.controlPlaneFailureDomainInfrastructureMachineTemplates != nil {
+		in, out := &in.controlPlaneFailureDomainInfrastructureMachineTemplates, &out.controlPlaneFailureDomainInfrastructureMachineTemplates
+		*out = make(map[string]*unstructured.Unstructured, len(*in))
+		for key, val := range *in {
+			var outVal *unstructured.Unstructured
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = (*in).DeepCopy()
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.controlPlaneMHC != nil {
 		in, out := &in.controlPlaneMHC, &out.controlPlaneMHC
 		*out = new(v1beta1.MachineHealthCheckClass)
@@ -134,6 +150,13 @@ func (in *ClusterClassBuilder) DeepCopyInto(out *ClusterClassBuilder) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.controlPlaneAdditionalTags != nil {
+		in, out := &in.controlPlaneAdditionalTags, &out.controlPlaneAdditionalTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.machineDeploymentClasses != nil {
 		in, out := &in.machineDeploymentClasses, &out.machineDeploymentClasses
 		*out = make([]v1beta1.MachineDeploymentClass, len(*in))
@@ -141,6 +164,11 @@ func (in *ClusterClassBuilder) DeepCopyInto(out *ClusterClassBuilder) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.clusterNetwork != nil {
+		in, out := &in.clusterNetwork, &out.clusterNetwork
+		*out = new(v1beta1.ClusterClassNetwork)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.variables != nil {
 		in, out := &in.variables, &out.variables
 		*out = make([]v1beta1.ClusterClassVariable, len(*in))
@@ -425,6 +453,13 @@ func (in *MachineDeploymentClassBuilder) DeepCopyInto(out *MachineDeploymentClas
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.additionalTags != nil {
+		in, out := &in.additionalTags, &out.additionalTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.minReadySeconds != nil {
 		in, out := &in.minReadySeconds, &out.minReadySeconds
 		*out = new(int32)
@@ -1006,6 +1006,57 @@ func TestMachineDeploymentTopologiesAreUniqueAndDefinedInClusterClass(t *testing
 	}
 }
 
+func TestMachinePoolTopologiesAreValidAndDefinedInClusterClass(t *testing.T) {
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+	clusterClass.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{{Class: "aa"}, {Class: "bb"}}
+
+	tests := []struct {
+		name         string
+		machinePools []clusterv1.MachinePoolTopology
+		wantErr      bool
+	}{
+		{
+			name:         "pass if MachinePoolTopologies are unique and defined in ClusterClass",
+			machinePools: []clusterv1.MachinePoolTopology{{Class: "aa", Name: "pool1"}, {Class: "bb", Name: "pool2"}},
+			wantErr:      false,
+		},
+		{
+			name:         "fail if MachinePoolTopologies name is empty",
+			machinePools: []clusterv1.MachinePoolTopology{{Class: "aa", Name: ""}},
+			wantErr:      true,
+		},
+		{
+			name:         "fail if MachinePoolTopologies names are not unique",
+			machinePools: []clusterv1.MachinePoolTopology{{Class: "aa", Name: "pool1"}, {Class: "bb", Name: "pool1"}},
+			wantErr:      true,
+		},
+		{
+			name:         "fail if MachinePoolTopologies class is not defined in ClusterClass",
+			machinePools: []clusterv1.MachinePoolTopology{{Class: "cc", Name: "pool1"}},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("class1").
+					WithVersion("v1.19.1").
+					Build()).
+				Build()
+			cluster.Spec.Topology.Workers = &clusterv1.WorkersTopology{MachinePools: tt.machinePools}
+
+			allErrs := MachinePoolTopologiesAreValidAndDefinedInClusterClass(cluster, clusterClass)
+			if tt.wantErr {
+				g.Expect(allErrs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(allErrs).To(BeEmpty())
+		})
+	}
+}
+
 func TestClusterClassReferencesAreValid(t *testing.T) {
 	ref := &corev1.ObjectReference{
 		APIVersion: "group.test.io/foo",
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterclass implements helpers for walking the templates referenced by ClusterClasses.
+package clusterclass
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// TemplateRef is a template reference of a ClusterClass, with the path of the LocalObjectTemplate it is defined in.
+type TemplateRef struct {
+	Path *field.Path
+	Ref  *corev1.ObjectReference
+}
+
+// TemplateRefs returns all the template references of a ClusterClass which are set.
+// NOTE: The references point to the ClusterClass, so changes to them are applied to the ClusterClass.
+func TemplateRefs(clusterClass *clusterv1.ClusterClass) []TemplateRef {
+	refs := []TemplateRef{}
+	add := func(template *clusterv1.LocalObjectTemplate, path *field.Path) {
+		if template != nil && template.Ref != nil {
+			refs = append(refs, TemplateRef{Path: path, Ref: template.Ref})
+		}
+	}
+
+	add(&clusterClass.Spec.Infrastructure, field.NewPath("spec", "infrastructure"))
+	add(&clusterClass.Spec.ControlPlane.LocalObjectTemplate, field.NewPath("spec", "controlPlane"))
+	add(clusterClass.Spec.ControlPlane.MachineInfrastructure, field.NewPath("spec", "controlPlane", "machineInfrastructure"))
+	for i := range clusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure {
+		add(&clusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure[i].LocalObjectTemplate,
+			field.NewPath("spec", "controlPlane", "failureDomainMachineInfrastructure").Index(i))
+	}
+	for i := range clusterClass.Spec.Workers.MachineDeployments {
+		path := field.NewPath("spec", "workers", "machineDeployments").Index(i).Child("template")
+		add(&clusterClass.Spec.Workers.MachineDeployments[i].Template.Bootstrap, path.Child("bootstrap"))
+		add(&clusterClass.Spec.Workers.MachineDeployments[i].Template.Infrastructure, path.Child("infrastructure"))
+	}
+	for i := range clusterClass.Spec.Workers.MachinePools {
+		path := field.NewPath("spec", "workers", "machinePools").Index(i).Child("template")
+		add(&clusterClass.Spec.Workers.MachinePools[i].Template.Bootstrap, path.Child("bootstrap"))
+		add(&clusterClass.Spec.Workers.MachinePools[i].Template.Infrastructure, path.Child("infrastructure"))
+	}
+	return refs
+}
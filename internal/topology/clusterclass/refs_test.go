@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterclass
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestTemplateRefs(t *testing.T) {
+	ref := func(name string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{APIVersion: "foo/v1", Kind: "FooTemplate", Name: name}
+	}
+	template := func(name string) clusterv1.LocalObjectTemplate {
+		return clusterv1.LocalObjectTemplate{Ref: ref(name)}
+	}
+
+	t.Run("returns all the template references which are set, with their path", func(t *testing.T) {
+		g := NewWithT(t)
+
+		clusterClass := &clusterv1.ClusterClass{
+			Spec: clusterv1.ClusterClassSpec{
+				Infrastructure: template("infra"),
+				ControlPlane: clusterv1.ControlPlaneClass{
+					LocalObjectTemplate: template("cp"),
+					FailureDomainMachineInfrastructure: []clusterv1.FailureDomainMachineInfrastructure{
+						{FailureDomain: "fd1", LocalObjectTemplate: template("cp-fd")},
+					},
+				},
+				Workers: clusterv1.WorkersClass{
+					MachineDeployments: []clusterv1.MachineDeploymentClass{
+						{
+							Class: "md",
+							Template: clusterv1.MachineDeploymentClassTemplate{
+								Bootstrap:      template("md-bootstrap"),
+								Infrastructure: template("md-infra"),
+							},
+						},
+					},
+					MachinePools: []clusterv1.MachinePoolClass{
+						{
+							Class: "mp",
+							Template: clusterv1.MachinePoolClassTemplate{
+								Bootstrap:      template("mp-bootstrap"),
+								Infrastructure: template("mp-infra"),
+							},
+						},
+					},
+				},
+			},
+		}
+
+		got := map[string]string{}
+		for _, t := range TemplateRefs(clusterClass) {
+			got[t.Path.String()] = t.Ref.Name
+		}
+		g.Expect(got).To(Equal(map[string]string{
+			"spec.infrastructure": "infra",
+			"spec.controlPlane":   "cp",
+			"spec.controlPlane.failureDomainMachineInfrastructure[0]":    "cp-fd",
+			"spec.workers.machineDeployments[0].template.bootstrap":      "md-bootstrap",
+			"spec.workers.machineDeployments[0].template.infrastructure": "md-infra",
+			"spec.workers.machinePools[0].template.bootstrap":            "mp-bootstrap",
+			"spec.workers.machinePools[0].template.infrastructure":       "mp-infra",
+		}))
+	})
+
+	t.Run("returns references which can be used to change the ClusterClass", func(t *testing.T) {
+		g := NewWithT(t)
+
+		machineInfrastructure := template("cp-machine-infra")
+		clusterClass := &clusterv1.ClusterClass{
+			Spec: clusterv1.ClusterClassSpec{
+				Infrastructure: template("infra"),
+				ControlPlane: clusterv1.ControlPlaneClass{
+					MachineInfrastructure: &machineInfrastructure,
+				},
+			},
+		}
+
+		refs := TemplateRefs(clusterClass)
+		g.Expect(refs).To(HaveLen(2))
+		for _, t := range refs {
+			t.Ref.Namespace = "ns1"
+		}
+		g.Expect(clusterClass.Spec.Infrastructure.Ref.Namespace).To(Equal("ns1"))
+		g.Expect(clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref.Namespace).To(Equal("ns1"))
+		g.Expect(clusterClass.Spec.ControlPlane.Ref).To(BeNil())
+	})
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade implements helpers for upgrading managed topologies through intermediate versions.
+package upgrade
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/version"
+)
+
+// Path returns the versions a Cluster topology has to be upgraded through, starting from the given version: the
+// intermediate versions defined in the ClusterTopologyUpgradePathAnnotation which are greater than from and lower than
+// the version defined in the topology, followed by the version defined in the topology.
+func Path(cluster *clusterv1.Cluster, from string) ([]string, error) {
+	fromVersion, err := semver.ParseTolerant(from)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse version %q", from)
+	}
+	toVersion, err := semver.ParseTolerant(cluster.Spec.Topology.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse version %q", cluster.Spec.Topology.Version)
+	}
+
+	path := []string{}
+	for _, v := range IntermediateVersions(cluster) {
+		if !version.KubeSemver.MatchString(v) {
+			return nil, errors.Errorf("version %q from the %s annotation must be a valid semantic version", v, clusterv1.ClusterTopologyUpgradePathAnnotation)
+		}
+		intermediateVersion, err := semver.ParseTolerant(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse version %q from the %s annotation", v, clusterv1.ClusterTopologyUpgradePathAnnotation)
+		}
+		if version.Compare(intermediateVersion, fromVersion, version.WithBuildTags()) <= 0 ||
+			version.Compare(intermediateVersion, toVersion, version.WithBuildTags()) >= 0 {
+			continue
+		}
+		path = append(path, v)
+	}
+	return append(path, cluster.Spec.Topology.Version), nil
+}
+
+// IntermediateVersions returns the intermediate versions defined in the ClusterTopologyUpgradePathAnnotation
+// of a Cluster, adding the "v" prefix if missing.
+func IntermediateVersions(cluster *clusterv1.Cluster) []string {
+	value, ok := cluster.GetAnnotations()[clusterv1.ClusterTopologyUpgradePathAnnotation]
+	if !ok {
+		return nil
+	}
+
+	versions := []string{}
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !strings.HasPrefix(v, "v") {
+			v = "v" + v
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// ValidatePath checks that each version of the path is greater than the previous one, starting from the given
+// version, and that each upgrade does not skip a minor version.
+func ValidatePath(from string, path []string) error {
+	previous, err := semver.ParseTolerant(from)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse version %q", from)
+	}
+	for _, v := range path {
+		next, err := semver.ParseTolerant(v)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse version %q", v)
+		}
+		if version.Compare(next, previous, version.WithBuildTags()) <= 0 {
+			return errors.Errorf("version %q must be greater than %q", v, previous)
+		}
+		ceilVersion := semver.Version{
+			Major: previous.Major,
+			Minor: previous.Minor + 2,
+		}
+		if next.GTE(ceilVersion) {
+			return errors.Errorf("version cannot be increased from %q to %q, skipping a minor version", previous, next)
+		}
+		previous = next
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		from        string
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name: "Without intermediate versions",
+			from: "v1.22.3",
+			want: []string{"v1.25.0"},
+		},
+		{
+			name:        "With intermediate versions",
+			annotations: map[string]string{clusterv1.ClusterTopologyUpgradePathAnnotation: "v1.23.4, 1.24.1"},
+			from:        "v1.22.3",
+			want:        []string{"v1.23.4", "v1.24.1", "v1.25.0"},
+		},
+		{
+			name:        "Intermediate versions already upgraded to or greater than the topology version are ignored",
+			annotations: map[string]string{clusterv1.ClusterTopologyUpgradePathAnnotation: "v1.22.1,v1.23.4,v1.24.1,v1.25.0,v1.26.0"},
+			from:        "v1.23.4",
+			want:        []string{"v1.24.1", "v1.25.0"},
+		},
+		{
+			name:        "Invalid intermediate versions",
+			annotations: map[string]string{clusterv1.ClusterTopologyUpgradePathAnnotation: "v1.23"},
+			from:        "v1.22.3",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec: clusterv1.ClusterSpec{
+					Topology: &clusterv1.Topology{Version: "v1.25.0"},
+				},
+			}
+			got, err := Path(cluster, tt.from)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestValidatePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		path    []string
+		wantErr bool
+	}{
+		{
+			name: "Upgrade to the next minor version",
+			from: "v1.22.3",
+			path: []string{"v1.23.0"},
+		},
+		{
+			name: "Upgrade through intermediate versions",
+			from: "v1.22.3",
+			path: []string{"v1.22.5", "v1.23.4", "v1.24.1"},
+		},
+		{
+			name:    "Upgrade skipping a minor version",
+			from:    "v1.22.3",
+			path:    []string{"v1.23.4", "v1.25.0"},
+			wantErr: true,
+		},
+		{
+			name:    "Versions not increasing",
+			from:    "v1.22.3",
+			path:    []string{"v1.23.4", "v1.23.4"},
+			wantErr: true,
+		},
+		{
+			name:    "Upgrade to the next major version",
+			from:    "v1.22.3",
+			path:    []string{"v2.0.0"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := ValidatePath(tt.from, tt.path)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
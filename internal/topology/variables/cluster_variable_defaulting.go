@@ -102,6 +102,12 @@ func defaultClusterVariables(clusterVariables []clusterv1.ClusterVariable, clust
 
 // defaultClusterVariable defaults a clusterVariable based on the default value in the clusterClassVariable.
 func defaultClusterVariable(clusterVariable *clusterv1.ClusterVariable, clusterClassVariable *clusterv1.ClusterClassVariable, fldPath *field.Path, createVariable bool) (*clusterv1.ClusterVariable, field.ErrorList) {
+	// Return the variable as is if its value is read from a Secret or a ConfigMap.
+	// NOTE: The value is resolved when computing the desired state of the Cluster topology.
+	if clusterVariable != nil && clusterVariable.ValueFrom != nil {
+		return clusterVariable.DeepCopy(), nil
+	}
+
 	if clusterVariable == nil {
 		// Return if the variable does not exist yet and createVariable is false.
 		if !createVariable {
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -272,6 +273,38 @@ func Test_DefaultClusterVariable(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Don't default variable with valueFrom",
+			clusterClassVariable: &clusterv1.ClusterClassVariable{
+				Name:     "registryPassword",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type:    "string",
+						Default: &apiextensionsv1.JSON{Raw: []byte(`"password"`)},
+					},
+				},
+			},
+			clusterVariable: &clusterv1.ClusterVariable{
+				Name: "registryPassword",
+				ValueFrom: &clusterv1.ClusterVariableValueSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"},
+						Key:                  "password",
+					},
+				},
+			},
+			createVariable: true,
+			want: &clusterv1.ClusterVariable{
+				Name: "registryPassword",
+				ValueFrom: &clusterv1.ClusterVariableValueSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"},
+						Key:                  "password",
+					},
+				},
+			},
+		},
 		{
 			name: "Don't default new integer variable if variable creation is disabled",
 			clusterClassVariable: &clusterv1.ClusterClassVariable{
@@ -106,7 +106,13 @@ func validateClusterVariablesDefined(clusterVariables []clusterv1.ClusterVariabl
 }
 
 // ValidateClusterVariable validates a clusterVariable.
+// NOTE: If the value of the clusterVariable is read from a Secret or a ConfigMap, only the reference is validated;
+// the value is validated against the schema when it is resolved.
 func ValidateClusterVariable(clusterVariable *clusterv1.ClusterVariable, clusterClassVariable *clusterv1.ClusterClassVariable, fldPath *field.Path) field.ErrorList {
+	if clusterVariable.ValueFrom != nil {
+		return validateClusterVariableValueFrom(clusterVariable, fldPath)
+	}
+
 	// Parse JSON value.
 	var variableValue interface{}
 	// Only try to unmarshal the clusterVariable if it is not nil, otherwise the variableValue is nil.
@@ -143,6 +149,47 @@ func ValidateClusterVariable(clusterVariable *clusterv1.ClusterVariable, cluster
 	return validateUnknownFields(fldPath, clusterVariable, variableValue, apiExtensionsSchema)
 }
 
+// validateClusterVariableValueFrom validates the valueFrom field of a clusterVariable.
+func validateClusterVariableValueFrom(clusterVariable *clusterv1.ClusterVariable, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(clusterVariable.Value.Raw) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), string(clusterVariable.Value.Raw),
+			fmt.Sprintf("variable %q cannot have both value and valueFrom set", clusterVariable.Name)))
+	}
+
+	valueFrom := clusterVariable.ValueFrom
+	valueFromPath := fldPath.Child("valueFrom")
+	switch {
+	case valueFrom.SecretKeyRef != nil && valueFrom.ConfigMapKeyRef != nil:
+		allErrs = append(allErrs, field.Invalid(valueFromPath, "",
+			fmt.Sprintf("variable %q cannot have both secretKeyRef and configMapKeyRef set", clusterVariable.Name)))
+	case valueFrom.SecretKeyRef != nil:
+		allErrs = append(allErrs, validateKeyRef(valueFrom.SecretKeyRef.Name, valueFrom.SecretKeyRef.Key, valueFromPath.Child("secretKeyRef"))...)
+	case valueFrom.ConfigMapKeyRef != nil:
+		allErrs = append(allErrs, validateKeyRef(valueFrom.ConfigMapKeyRef.Name, valueFrom.ConfigMapKeyRef.Key, valueFromPath.Child("configMapKeyRef"))...)
+	default:
+		allErrs = append(allErrs, field.Required(valueFromPath,
+			fmt.Sprintf("variable %q must have either secretKeyRef or configMapKeyRef set", clusterVariable.Name)))
+	}
+
+	return allErrs
+}
+
+// validateKeyRef validates the name and the key of a reference to a Secret or a ConfigMap key.
+func validateKeyRef(name, key string, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "name must be set"))
+	}
+	if key == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), "key must be set"))
+	}
+
+	return allErrs
+}
+
 // validateUnknownFields validates the given variableValue for unknown fields.
 // This func returns an error if there are variable fields in variableValue that are not defined in
 // variableSchema and if x-kubernetes-preserve-unknown-fields is not set.
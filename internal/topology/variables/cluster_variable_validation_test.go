@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
@@ -1117,6 +1118,138 @@ func Test_ValidateClusterVariable(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Valid valueFrom with secretKeyRef",
+			clusterClassVariable: &clusterv1.ClusterClassVariable{
+				Name:     "registryPassword",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type:      "string",
+						MinLength: pointer.Int64(1),
+					},
+				},
+			},
+			clusterVariable: &clusterv1.ClusterVariable{
+				Name: "registryPassword",
+				ValueFrom: &clusterv1.ClusterVariableValueSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"},
+						Key:                  "password",
+					},
+				},
+			},
+		},
+		{
+			name: "Valid valueFrom with configMapKeyRef",
+			clusterClassVariable: &clusterv1.ClusterClassVariable{
+				Name:     "registryMirror",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+			clusterVariable: &clusterv1.ClusterVariable{
+				Name: "registryMirror",
+				ValueFrom: &clusterv1.ClusterVariableValueSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+						Key:                  "mirror",
+					},
+				},
+			},
+		},
+		{
+			name: "Error if both value and valueFrom are set",
+			clusterClassVariable: &clusterv1.ClusterClassVariable{
+				Name:     "registryPassword",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+			clusterVariable: &clusterv1.ClusterVariable{
+				Name: "registryPassword",
+				Value: apiextensionsv1.JSON{
+					Raw: []byte(`"password"`),
+				},
+				ValueFrom: &clusterv1.ClusterVariableValueSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"},
+						Key:                  "password",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error if both secretKeyRef and configMapKeyRef are set",
+			clusterClassVariable: &clusterv1.ClusterClassVariable{
+				Name:     "registryPassword",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+			clusterVariable: &clusterv1.ClusterVariable{
+				Name: "registryPassword",
+				ValueFrom: &clusterv1.ClusterVariableValueSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"},
+						Key:                  "password",
+					},
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+						Key:                  "password",
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error if valueFrom is empty",
+			clusterClassVariable: &clusterv1.ClusterClassVariable{
+				Name:     "registryPassword",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+			clusterVariable: &clusterv1.ClusterVariable{
+				Name:      "registryPassword",
+				ValueFrom: &clusterv1.ClusterVariableValueSource{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Error if the key of secretKeyRef is not set",
+			clusterClassVariable: &clusterv1.ClusterClassVariable{
+				Name:     "registryPassword",
+				Required: true,
+				Schema: clusterv1.VariableSchema{
+					OpenAPIV3Schema: clusterv1.JSONSchemaProps{
+						Type: "string",
+					},
+				},
+			},
+			clusterVariable: &clusterv1.ClusterVariable{
+				Name: "registryPassword",
+				ValueFrom: &clusterv1.ClusterVariableValueSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "registry-credentials"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
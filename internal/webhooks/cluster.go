@@ -20,12 +20,18 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,8 +40,10 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/topology/check"
+	"sigs.k8s.io/cluster-api/internal/topology/upgrade"
 	"sigs.k8s.io/cluster-api/internal/topology/variables"
 	"sigs.k8s.io/cluster-api/util/version"
+	capiversion "sigs.k8s.io/cluster-api/version"
 )
 
 // SetupWebhookWithManager sets up Cluster webhooks.
@@ -143,8 +151,12 @@ func (webhook *Cluster) ValidateUpdate(ctx context.Context, oldObj, newObj runti
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type.
-func (webhook *Cluster) ValidateDelete(_ context.Context, _ runtime.Object) error {
-	return nil
+func (webhook *Cluster) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	cluster, ok := obj.(*clusterv1.Cluster)
+	if !ok {
+		return apierrors.NewBadRequest(fmt.Sprintf("expected a Cluster but got a %T", obj))
+	}
+	return webhook.validateDeletionProtection(ctx, cluster)
 }
 
 func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *clusterv1.Cluster) error {
@@ -184,6 +196,22 @@ func (webhook *Cluster) validate(ctx context.Context, oldCluster, newCluster *cl
 		}
 	}
 
+	// Ensure that the metadata precedence annotation, if set, has a valid value.
+	if precedence, ok := newCluster.Annotations[clusterv1.ClusterTopologyMetadataPrecedenceAnnotation]; ok &&
+		precedence != clusterv1.ClusterTopologyMetadataPrecedenceCluster && precedence != clusterv1.ClusterTopologyMetadataPrecedenceClusterClass {
+		allErrs = append(allErrs, field.NotSupported(
+			field.NewPath("metadata", "annotations").Key(clusterv1.ClusterTopologyMetadataPrecedenceAnnotation),
+			precedence,
+			[]string{clusterv1.ClusterTopologyMetadataPrecedenceCluster, clusterv1.ClusterTopologyMetadataPrecedenceClusterClass},
+		))
+	}
+
+	// Ensure that the bootstrap settings annotations, if set, have a valid value.
+	allErrs = append(allErrs, validateBootstrapAnnotations(newCluster)...)
+
+	// Ensure that the deletion protection annotations, if set, have a valid value.
+	allErrs = append(allErrs, validateDeletionProtectionAnnotations(newCluster)...)
+
 	topologyPath := specPath.Child("topology")
 
 	// Validate the managed topology, if defined.
@@ -243,8 +271,65 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 				"version must be a valid semantic version",
 			),
 		)
+	} else if oldCluster == nil || oldCluster.Spec.Topology == nil || oldCluster.Spec.Topology.Version != newCluster.Spec.Topology.Version {
+		// version should not be newer than the versions supported by Cluster API; this check is performed only
+		// when the version is set or changed, so other changes to existing Clusters are not blocked.
+		// NOTE: Versions older than the supported ones are reported by the KubernetesVersionSupported condition only,
+		// so it is still possible to upgrade out of them.
+		if compare, err := capiversion.SupportedWorkloadKubernetesVersions.Compare(newCluster.Spec.Topology.Version); err == nil && compare == 1 {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(
+					fldPath.Child("version"),
+					fmt.Sprintf("version %q is not supported by Cluster API, supported versions are %s",
+						newCluster.Spec.Topology.Version, capiversion.SupportedWorkloadKubernetesVersions),
+				),
+			)
+		}
+	}
+
+	// MachinePools can be used only if the MachinePool feature flag is enabled.
+	if newCluster.Spec.Topology.Workers != nil && len(newCluster.Spec.Topology.Workers.MachinePools) > 0 && !feature.Gates.Enabled(feature.MachinePool) {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				fldPath.Child("workers", "machinePools"),
+				"can be set only if the MachinePool feature flag is enabled",
+			),
+		)
 	}
 
+	// intermediate versions of the upgrade path should be valid.
+	for _, v := range upgrade.IntermediateVersions(newCluster) {
+		if !version.KubeSemver.MatchString(v) {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					field.NewPath("metadata", "annotations", clusterv1.ClusterTopologyUpgradePathAnnotation),
+					newCluster.Annotations[clusterv1.ClusterTopologyUpgradePathAnnotation],
+					fmt.Sprintf("version %q must be a valid semantic version", v),
+				),
+			)
+		}
+	}
+
+	// profile should be a valid label value, given that it is set on the Cluster as a label.
+	if newCluster.Spec.Topology.Profile != "" {
+		for _, msg := range validation.IsValidLabelValue(newCluster.Spec.Topology.Profile) {
+			allErrs = append(
+				allErrs,
+				field.Invalid(
+					fldPath.Child("profile"),
+					newCluster.Spec.Topology.Profile,
+					msg,
+				),
+			)
+		}
+	}
+
+	// replicas should not be negative.
+	allErrs = append(allErrs, validateTopologyReplicas(newCluster.Spec.Topology, fldPath)...)
+
 	// clusterClass must exist.
 	clusterClass := &clusterv1.ClusterClass{}
 	// Check to see if the ClusterClass referenced in the Cluster currently exists.
@@ -257,7 +342,14 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 		return allErrs
 	}
 
+	// The ClusterClass must be allowed in the namespace of the Cluster; this is checked only on create or when the
+	// class changes, so that changing the namespace policy does not block updates to existing Clusters.
+	if oldCluster == nil || oldCluster.Spec.Topology == nil || oldCluster.Spec.Topology.Class != newCluster.Spec.Topology.Class {
+		allErrs = append(allErrs, webhook.validateClusterClassAllowedInNamespace(ctx, newCluster, clusterClass, fldPath.Child("class"))...)
+	}
+
 	allErrs = append(allErrs, check.MachineDeploymentTopologiesAreValidAndDefinedInClusterClass(newCluster, clusterClass)...)
+	allErrs = append(allErrs, check.MachinePoolTopologiesAreValidAndDefinedInClusterClass(newCluster, clusterClass)...)
 
 	// Check if the variables defined in the ClusterClass are valid.
 	allErrs = append(allErrs, variables.ValidateClusterVariables(newCluster.Spec.Topology.Variables, clusterClass.Spec.Variables,
@@ -266,8 +358,36 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 	// validate the MachineHealthChecks defined in the cluster topology
 	allErrs = append(allErrs, validateMachineHealthChecks(newCluster, clusterClass)...)
 
+	// The cluster network must be within the ranges permitted by the ClusterClass; this is checked only on create or
+	// when the cluster network or the class changes, so that changing the permitted ranges does not block updates to existing Clusters.
+	if oldCluster == nil || oldCluster.Spec.Topology == nil || oldCluster.Spec.Topology.Class != newCluster.Spec.Topology.Class ||
+		!reflect.DeepEqual(oldCluster.Spec.ClusterNetwork, newCluster.Spec.ClusterNetwork) {
+		allErrs = append(allErrs, validateClusterNetworkIsPermitted(newCluster, clusterClass)...)
+	}
+
+	// The operating systems used in the topology must be a supported combination; this is checked only on create or
+	// when the topology or the class changes, so that changing the ClusterClass does not block updates to existing Clusters.
+	if oldCluster == nil || oldCluster.Spec.Topology == nil || oldCluster.Spec.Topology.Class != newCluster.Spec.Topology.Class ||
+		!reflect.DeepEqual(oldCluster.Spec.Topology.ControlPlane.Metadata, newCluster.Spec.Topology.ControlPlane.Metadata) ||
+		!reflect.DeepEqual(oldCluster.Spec.Topology.Workers, newCluster.Spec.Topology.Workers) {
+		allErrs = append(allErrs, validateTopologyOperatingSystems(newCluster, clusterClass)...)
+	}
+
+	// Bootstrap overrides must be supported by the bootstrap configuration templates of the MachineDeployments; this is
+	// checked only on create or when the workers or the class change, so that changing the ClusterClass does not block
+	// updates to existing Clusters.
+	if oldCluster == nil || oldCluster.Spec.Topology == nil || oldCluster.Spec.Topology.Class != newCluster.Spec.Topology.Class ||
+		!reflect.DeepEqual(oldCluster.Spec.Topology.Workers, newCluster.Spec.Topology.Workers) {
+		allErrs = append(allErrs, validateBootstrapOverridesAreSupported(newCluster, clusterClass)...)
+	}
+
 	if newCluster.Spec.Topology.Workers != nil {
 		for i, md := range newCluster.Spec.Topology.Workers.MachineDeployments {
+			if md.BootstrapOverrides != nil {
+				allErrs = append(allErrs, validateBootstrapOverrides(md.BootstrapOverrides,
+					fldPath.Child("workers", "machineDeployments").Index(i).Child("bootstrapOverrides"))...)
+			}
+
 			// Continue if there are no variable overrides.
 			if md.Variables == nil || len(md.Variables.Overrides) == 0 {
 				continue
@@ -330,20 +450,35 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 				),
 			)
 		}
-		// A +2 minor version upgrade is not allowed.
+		// A +2 minor version upgrade is not allowed, unless the upgrade path annotation defines intermediate versions
+		// not skipping any minor version.
 		ceilVersion := semver.Version{
 			Major: oldVersion.Major,
 			Minor: oldVersion.Minor + 2,
 			Patch: 0,
 		}
 		if inVersion.GTE(ceilVersion) {
-			allErrs = append(
-				allErrs,
-				field.Forbidden(
-					fldPath.Child("version"),
-					fmt.Sprintf("version cannot be increased from %q to %q", oldVersion, inVersion),
-				),
-			)
+			if len(upgrade.IntermediateVersions(newCluster)) == 0 {
+				allErrs = append(
+					allErrs,
+					field.Forbidden(
+						fldPath.Child("version"),
+						fmt.Sprintf("version cannot be increased from %q to %q; the %s annotation can be used to upgrade through intermediate versions",
+							oldVersion, inVersion, clusterv1.ClusterTopologyUpgradePathAnnotation),
+					),
+				)
+			} else if path, err := upgrade.Path(newCluster, oldCluster.Spec.Topology.Version); err == nil {
+				if err := upgrade.ValidatePath(oldCluster.Spec.Topology.Version, path); err != nil {
+					allErrs = append(
+						allErrs,
+						field.Forbidden(
+							fldPath.Child("version"),
+							fmt.Sprintf("version cannot be increased from %q to %q through the intermediate versions of the %s annotation: %v",
+								oldVersion, inVersion, clusterv1.ClusterTopologyUpgradePathAnnotation, err),
+						),
+					)
+				}
+			}
 		}
 
 		// If the ClusterClass referenced in the Topology has changed compatibility checks are needed.
@@ -377,6 +512,41 @@ func (webhook *Cluster) getClusterClassForCluster(ctx context.Context, cluster *
 	return clusterClass, nil
 }
 
+// validateClusterClassAllowedInNamespace checks that the ClusterClass is listed in the
+// ClusterTopologyAllowedClassesAnnotation of the Cluster's Namespace, if any.
+// NOTE: The check relies only on the Namespace, which can't be changed by tenants, and on the name of the ClusterClass;
+// attributes of the ClusterClass, e.g. labels, are not considered given that they could be changed by tenants.
+func (webhook *Cluster) validateClusterClassAllowedInNamespace(ctx context.Context, cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass, fldPath *field.Path) field.ErrorList {
+	namespace := &corev1.Namespace{}
+	if err := webhook.Client.Get(ctx, client.ObjectKey{Name: cluster.Namespace}, namespace); err != nil {
+		// NOTE: The API server rejects objects in non-existing namespaces, so if the Namespace can't be found
+		// there is no policy to enforce.
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return field.ErrorList{field.InternalError(fldPath, errors.Wrapf(err, "failed to get Namespace %q", cluster.Namespace))}
+	}
+
+	value, ok := namespace.Annotations[clusterv1.ClusterTopologyAllowedClassesAnnotation]
+	if !ok {
+		return nil
+	}
+
+	allowedClasses := []string{}
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowedClasses = append(allowedClasses, name)
+		}
+	}
+	for _, name := range allowedClasses {
+		if name == clusterClass.Name {
+			return nil
+		}
+	}
+	return field.ErrorList{field.Forbidden(fldPath,
+		fmt.Sprintf("ClusterClass %q is not allowed in Namespace %q; allowed ClusterClasses are %q", clusterClass.Name, cluster.Namespace, strings.Join(allowedClasses, ",")))}
+}
+
 func validateMachineHealthChecks(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -458,6 +628,173 @@ func machineDeploymentClassOfName(clusterClass *clusterv1.ClusterClass, name str
 	return nil
 }
 
+// validateTopologyReplicas validates that the replicas of the control plane, of the MachineDeployments and of the
+// MachinePools in the topology are not negative.
+func validateTopologyReplicas(topology *clusterv1.Topology, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	validateReplicas := func(replicas *int32, fldPath *field.Path) {
+		if replicas != nil && *replicas < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, *replicas, "replicas must be greater than or equal to 0"))
+		}
+	}
+
+	validateReplicas(topology.ControlPlane.Replicas, fldPath.Child("controlPlane", "replicas"))
+	if topology.Workers != nil {
+		for i, md := range topology.Workers.MachineDeployments {
+			validateReplicas(md.Replicas, fldPath.Child("workers", "machineDeployments").Index(i).Child("replicas"))
+		}
+		for i, mp := range topology.Workers.MachinePools {
+			validateReplicas(mp.Replicas, fldPath.Child("workers", "machinePools").Index(i).Child("replicas"))
+		}
+	}
+	return allErrs
+}
+
+// validateTopologyOperatingSystems ensures the operating systems used by the control plane and by the MachineDeployments
+// of a Cluster topology are a supported combination, so it is not necessary to wait for bootstrap failures to detect them:
+//   - the control plane must use Linux, given that Kubernetes does not support Windows control plane nodes;
+//   - MachineDeployments must use Linux or Windows;
+//   - all the operating systems must be supported by the CNI of the ClusterClass, if defined.
+func validateTopologyOperatingSystems(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "topology")
+
+	var cniSupportedOS []string
+	if value, ok := clusterClass.Annotations[clusterv1.ClusterClassCNISupportedOSAnnotation]; ok {
+		for _, os := range strings.Split(value, ",") {
+			if os = strings.TrimSpace(os); os != "" {
+				cniSupportedOS = append(cniSupportedOS, os)
+			}
+		}
+	}
+	validateCNISupportedOS := func(fldPath *field.Path, os string) {
+		if len(cniSupportedOS) == 0 {
+			return
+		}
+		for _, supportedOS := range cniSupportedOS {
+			if os == supportedOS {
+				return
+			}
+		}
+		allErrs = append(allErrs, field.Invalid(
+			fldPath,
+			os,
+			fmt.Sprintf("operating system is not supported by the CNI of ClusterClass %s, supported operating systems are defined by the %s annotation: %s",
+				clusterClass.Name, clusterv1.ClusterClassCNISupportedOSAnnotation, strings.Join(cniSupportedOS, ", "))))
+	}
+
+	controlPlaneOS := topologyOperatingSystem(cluster, clusterClass.Spec.ControlPlane.Metadata, cluster.Spec.Topology.ControlPlane.Metadata)
+	controlPlanePath := fldPath.Child("controlPlane", "metadata", "labels").Key(corev1.LabelOSStable)
+	if controlPlaneOS != string(corev1.Linux) {
+		allErrs = append(allErrs, field.Invalid(
+			controlPlanePath,
+			controlPlaneOS,
+			fmt.Sprintf("control plane must use operating system %q", corev1.Linux)))
+	} else {
+		validateCNISupportedOS(controlPlanePath, controlPlaneOS)
+	}
+
+	if cluster.Spec.Topology.Workers == nil {
+		return allErrs
+	}
+	for i, md := range cluster.Spec.Topology.Workers.MachineDeployments {
+		// MachineDeployment classes not defined in the ClusterClass are reported by MachineDeploymentTopologiesAreValidAndDefinedInClusterClass.
+		mdClass := machineDeploymentClassOfName(clusterClass, md.Class)
+		if mdClass == nil {
+			continue
+		}
+
+		mdOS := topologyOperatingSystem(cluster, mdClass.Template.Metadata, md.Metadata)
+		mdPath := fldPath.Child("workers", "machineDeployments").Index(i).Child("metadata", "labels").Key(corev1.LabelOSStable)
+		if mdOS != string(corev1.Linux) && mdOS != string(corev1.Windows) {
+			allErrs = append(allErrs, field.NotSupported(mdPath, mdOS, []string{string(corev1.Linux), string(corev1.Windows)}))
+			continue
+		}
+		validateCNISupportedOS(mdPath, mdOS)
+	}
+	return allErrs
+}
+
+// topologyOperatingSystem returns the operating system defined by the kubernetes.io/os label for a part of the Cluster topology,
+// honoring the precedence between the Cluster topology and the ClusterClass metadata; Linux is assumed if the label is not set.
+func topologyOperatingSystem(cluster *clusterv1.Cluster, classMetadata, topologyMetadata clusterv1.ObjectMeta) string {
+	classOS, inClass := classMetadata.Labels[corev1.LabelOSStable]
+	topologyOS, inTopology := topologyMetadata.Labels[corev1.LabelOSStable]
+	switch {
+	case inClass && (!inTopology || cluster.Annotations[clusterv1.ClusterTopologyMetadataPrecedenceAnnotation] == clusterv1.ClusterTopologyMetadataPrecedenceClusterClass):
+		return classOS
+	case inTopology:
+		return topologyOS
+	default:
+		return string(corev1.Linux)
+	}
+}
+
+// validateBootstrapOverrides validates the bootstrap overrides of a MachineDeployment:
+//   - node labels must be valid labels;
+//   - node labels must not be set also through the node-labels kubelet extra arg;
+//   - taints must have a valid key and effect.
+func validateBootstrapOverrides(overrides *clusterv1.MachineDeploymentBootstrapOverrides, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for k, v := range overrides.NodeLabels {
+		for _, msg := range validation.IsQualifiedName(k) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeLabels"), k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeLabels").Key(k), v, msg))
+		}
+	}
+	if _, ok := overrides.KubeletExtraArgs["node-labels"]; ok && len(overrides.NodeLabels) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("kubeletExtraArgs").Key("node-labels"),
+			"node-labels cannot be set when nodeLabels are defined"))
+	}
+
+	for i, taint := range overrides.Taints {
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("taints").Index(i).Child("key"), taint.Key, msg))
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("taints").Index(i).Child("effect"), taint.Effect,
+				[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+		}
+	}
+	return allErrs
+}
+
+// kubeadmConfigTemplateGroupKind is the GroupKind of the bootstrap configuration templates supporting bootstrap overrides.
+var kubeadmConfigTemplateGroupKind = schema.GroupKind{Group: "bootstrap.cluster.x-k8s.io", Kind: "KubeadmConfigTemplate"}
+
+// validateBootstrapOverridesAreSupported ensures bootstrap overrides are only defined for MachineDeployments
+// using a KubeadmConfigTemplate, given that overrides cannot be applied to other bootstrap configuration templates.
+func validateBootstrapOverridesAreSupported(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	if cluster.Spec.Topology.Workers == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "topology", "workers", "machineDeployments")
+	for i, md := range cluster.Spec.Topology.Workers.MachineDeployments {
+		if md.BootstrapOverrides == nil {
+			continue
+		}
+		// NOTE: MachineDeployments using a class not defined in the ClusterClass are reported by other validations.
+		mdClass := machineDeploymentClassOfName(clusterClass, md.Class)
+		if mdClass == nil || mdClass.Template.Bootstrap.Ref == nil {
+			continue
+		}
+		if ref := mdClass.Template.Bootstrap.Ref; ref.GroupVersionKind().GroupKind() != kubeadmConfigTemplateGroupKind {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("bootstrapOverrides"),
+				fmt.Sprintf("bootstrap overrides are only supported for %s, while MachineDeploymentClass %s of ClusterClass %s uses %s",
+					kubeadmConfigTemplateGroupKind, md.Class, clusterClass.Name, ref.GroupVersionKind().GroupKind())))
+		}
+	}
+	return allErrs
+}
+
 // validateCIDRBlocks ensures the passed CIDR is valid.
 func validateCIDRBlocks(fldPath *field.Path, cidrs []string) field.ErrorList {
 	var allErrs field.ErrorList
@@ -471,3 +808,172 @@ func validateCIDRBlocks(fldPath *field.Path, cidrs []string) field.ErrorList {
 	}
 	return allErrs
 }
+
+// validateClusterNetworkIsPermitted ensures the CIDR blocks defined in the cluster network of a Cluster are
+// contained in the CIDR blocks permitted by its ClusterClass, if any.
+func validateClusterNetworkIsPermitted(cluster *clusterv1.Cluster, clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	if cluster.Spec.ClusterNetwork == nil || clusterClass.Spec.ClusterNetwork == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "clusterNetwork")
+	if cluster.Spec.ClusterNetwork.Pods != nil && clusterClass.Spec.ClusterNetwork.Pods != nil {
+		allErrs = append(allErrs, validateCIDRBlocksArePermitted(fldPath.Child("pods", "cidrBlocks"),
+			cluster.Spec.ClusterNetwork.Pods.CIDRBlocks, clusterClass.Spec.ClusterNetwork.Pods.PermittedCIDRBlocks)...)
+	}
+	if cluster.Spec.ClusterNetwork.Services != nil && clusterClass.Spec.ClusterNetwork.Services != nil {
+		allErrs = append(allErrs, validateCIDRBlocksArePermitted(fldPath.Child("services", "cidrBlocks"),
+			cluster.Spec.ClusterNetwork.Services.CIDRBlocks, clusterClass.Spec.ClusterNetwork.Services.PermittedCIDRBlocks)...)
+	}
+	return allErrs
+}
+
+// validateCIDRBlocksArePermitted ensures each of the passed CIDR blocks is contained in one of the permitted CIDR blocks.
+// If there are no permitted CIDR blocks, any CIDR block is permitted.
+// NOTE: Invalid CIDR blocks are ignored; they are expected to be reported by validateCIDRBlocks.
+func validateCIDRBlocksArePermitted(fldPath *field.Path, cidrs, permittedCIDRs []string) field.ErrorList {
+	if len(permittedCIDRs) == 0 {
+		return nil
+	}
+
+	permittedNets := []*net.IPNet{}
+	for _, permittedCIDR := range permittedCIDRs {
+		if _, permittedNet, err := net.ParseCIDR(permittedCIDR); err == nil {
+			permittedNets = append(permittedNets, permittedNet)
+		}
+	}
+
+	var allErrs field.ErrorList
+	for i, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if !cidrIsContained(ipNet, permittedNets) {
+			allErrs = append(allErrs, field.Invalid(
+				fldPath.Index(i),
+				cidr,
+				fmt.Sprintf("must be contained in one of the CIDR blocks permitted by the ClusterClass: %s", strings.Join(permittedCIDRs, ", "))))
+		}
+	}
+	return allErrs
+}
+
+// cidrIsContained returns true if ipNet is contained in one of the given networks.
+func cidrIsContained(ipNet *net.IPNet, nets []*net.IPNet) bool {
+	ones, bits := ipNet.Mask.Size()
+	for _, n := range nets {
+		nOnes, nBits := n.Mask.Size()
+		if nBits == bits && nOnes <= ones && n.Contains(ipNet.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBootstrapAnnotations validates the annotations which can be used to configure bootstrap settings
+// for all the machines of a Cluster.
+func validateBootstrapAnnotations(cluster *clusterv1.Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+	annotationsPath := field.NewPath("metadata", "annotations")
+
+	if value, ok := cluster.Annotations[clusterv1.BootstrapTokenTTLAnnotation]; ok {
+		if ttl, err := time.ParseDuration(value); err != nil || ttl <= 0 {
+			allErrs = append(allErrs, field.Invalid(
+				annotationsPath.Key(clusterv1.BootstrapTokenTTLAnnotation),
+				value,
+				"must be a positive duration, e.g. 30m",
+			))
+		}
+	}
+
+	if value, ok := cluster.Annotations[clusterv1.KubeletRotateServerCertificatesAnnotation]; ok {
+		if value != "true" && value != "false" {
+			allErrs = append(allErrs, field.NotSupported(
+				annotationsPath.Key(clusterv1.KubeletRotateServerCertificatesAnnotation),
+				value,
+				[]string{"true", "false"},
+			))
+		}
+	}
+
+	return allErrs
+}
+
+// clusterDeletionUnlockMaxDuration is the maximum time a Cluster protected from deletion can be unlocked for.
+const clusterDeletionUnlockMaxDuration = time.Hour
+
+// validateDeletionProtectionAnnotations validates the annotations which can be used to protect a Cluster from
+// accidental deletion.
+func validateDeletionProtectionAnnotations(cluster *clusterv1.Cluster) field.ErrorList {
+	var allErrs field.ErrorList
+	annotationsPath := field.NewPath("metadata", "annotations")
+
+	if value, ok := cluster.Annotations[clusterv1.ClusterDeletionProtectionAnnotation]; ok {
+		if maxMachines, err := strconv.Atoi(value); err != nil || maxMachines < 0 {
+			allErrs = append(allErrs, field.Invalid(
+				annotationsPath.Key(clusterv1.ClusterDeletionProtectionAnnotation),
+				value,
+				"must be a non-negative integer, e.g. 0",
+			))
+		}
+	}
+
+	if value, ok := cluster.Annotations[clusterv1.ClusterDeletionUnlockAnnotation]; ok {
+		expiry, err := time.Parse(time.RFC3339, value)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(
+				annotationsPath.Key(clusterv1.ClusterDeletionUnlockAnnotation),
+				value,
+				"must be a time in RFC3339 format, e.g. 2022-10-17T15:04:05Z",
+			))
+		case expiry.After(time.Now().Add(clusterDeletionUnlockMaxDuration)):
+			allErrs = append(allErrs, field.Invalid(
+				annotationsPath.Key(clusterv1.ClusterDeletionUnlockAnnotation),
+				value,
+				"must be at most one hour in the future",
+			))
+		}
+	}
+
+	return allErrs
+}
+
+// validateDeletionProtection rejects the deletion of a Cluster protected by the deletion-protection annotation
+// having more Machines than allowed, unless the Cluster is unlocked by a deletion-unlock annotation not yet expired.
+func (webhook *Cluster) validateDeletionProtection(ctx context.Context, cluster *clusterv1.Cluster) error {
+	value, ok := cluster.Annotations[clusterv1.ClusterDeletionProtectionAnnotation]
+	if !ok || !cluster.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	if expiry, err := time.Parse(time.RFC3339, cluster.Annotations[clusterv1.ClusterDeletionUnlockAnnotation]); err == nil && time.Now().Before(expiry) {
+		return nil
+	}
+
+	// NOTE: Invalid values are rejected on create and update; if an invalid value is set anyway, e.g. before the
+	// webhook was deployed, the Cluster is protected no matter of the number of its Machines.
+	maxMachines, err := strconv.Atoi(value)
+	if err != nil || maxMachines < 0 {
+		maxMachines = 0
+	}
+
+	machines := &clusterv1.MachineList{}
+	if err := webhook.Client.List(ctx, machines,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return apierrors.NewInternalError(errors.Wrapf(err, "failed to list Machines of Cluster %s", cluster.Name))
+	}
+	if len(machines.Items) <= maxMachines {
+		return nil
+	}
+
+	return apierrors.NewForbidden(clusterv1.GroupVersion.WithResource("clusters").GroupResource(), cluster.Name,
+		fmt.Errorf("cluster is protected by the %s annotation and it has %d Machine(s), more than %d: "+
+			"set the %s annotation to an expiry time at most one hour in the future to unlock its deletion",
+			clusterv1.ClusterDeletionProtectionAnnotation, len(machines.Items), maxMachines,
+			clusterv1.ClusterDeletionUnlockAnnotation))
+}
@@ -18,12 +18,15 @@ package webhooks
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -518,6 +521,82 @@ func TestClusterValidation(t *testing.T) {
 							CIDRBlocks: []string{"10.10.10.10", "11.11.11.11"}}}).
 					Build(),
 			},
+			{
+				name:      "pass with a valid metadata precedence annotation",
+				expectErr: false,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.ClusterTopologyMetadataPrecedenceAnnotation: clusterv1.ClusterTopologyMetadataPrecedenceClusterClass}).
+					Build(),
+			},
+			{
+				name:      "fails if the metadata precedence annotation is not valid",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.ClusterTopologyMetadataPrecedenceAnnotation: "foo"}).
+					Build(),
+			},
+			{
+				name:      "pass with valid bootstrap settings annotations",
+				expectErr: false,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{
+						clusterv1.BootstrapTokenTTLAnnotation:               "30m",
+						clusterv1.KubeletRotateServerCertificatesAnnotation: "true",
+					}).
+					Build(),
+			},
+			{
+				name:      "fails if the bootstrap token TTL annotation is not a duration",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.BootstrapTokenTTLAnnotation: "foo"}).
+					Build(),
+			},
+			{
+				name:      "fails if the bootstrap token TTL annotation is not a positive duration",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.BootstrapTokenTTLAnnotation: "0s"}).
+					Build(),
+			},
+			{
+				name:      "fails if the kubelet rotate server certificates annotation is not valid",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.KubeletRotateServerCertificatesAnnotation: "yes"}).
+					Build(),
+			},
+			{
+				name:      "pass with valid deletion protection annotations",
+				expectErr: false,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{
+						clusterv1.ClusterDeletionProtectionAnnotation: "3",
+						clusterv1.ClusterDeletionUnlockAnnotation:     time.Now().Add(10 * time.Minute).Format(time.RFC3339),
+					}).
+					Build(),
+			},
+			{
+				name:      "fails if the deletion protection annotation is not a non-negative integer",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.ClusterDeletionProtectionAnnotation: "-1"}).
+					Build(),
+			},
+			{
+				name:      "fails if the deletion unlock annotation is not a time",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.ClusterDeletionUnlockAnnotation: "10m"}).
+					Build(),
+			},
+			{
+				name:      "fails if the deletion unlock annotation is more than one hour in the future",
+				expectErr: true,
+				in: builder.Cluster("fooNamespace", "cluster1").
+					WithAnnotations(map[string]string{clusterv1.ClusterDeletionUnlockAnnotation: time.Now().Add(2 * time.Hour).Format(time.RFC3339)}).
+					Build(),
+			},
 		}
 	)
 	for _, tt := range tests {
@@ -565,6 +644,110 @@ func TestClusterTopologyValidation(t *testing.T) {
 					WithVersion("invalid").Build()).
 				Build(),
 		},
+		{
+			name:      "should return error when topology does not have a valid profile",
+			expectErr: true,
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.19.1").
+					WithProfile("not a valid profile").
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should accept a topology with a valid profile",
+			expectErr: false,
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.19.1").
+					WithProfile("prod").
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should return error when topology version is newer than the supported versions",
+			expectErr: true,
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.27.0").
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should return error when the control plane replicas are negative",
+			expectErr: true,
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.19.1").
+					WithControlPlaneReplicas(-1).
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should return error when MachineDeployment replicas are negative",
+			expectErr: true,
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.19.1").
+					WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+						WithClass("aa").
+						WithReplicas(-1).
+						Build()).
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should accept zero replicas",
+			expectErr: false,
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.19.1").
+					WithControlPlaneReplicas(0).
+					WithMachineDeployment(builder.MachineDeploymentTopology("workers1").
+						WithClass("aa").
+						WithReplicas(0).
+						Build()).
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should return error when upgrading topology version to a version newer than the supported versions",
+			expectErr: true,
+			old: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.26.3").
+					Build()).
+				Build(),
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.27.0").
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should accept an unchanged topology version newer than the supported versions",
+			expectErr: false,
+			old: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.27.0").
+					Build()).
+				Build(),
+			in: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.27.0").
+					Build()).
+				Build(),
+		},
 		{
 			name:      "should return error when downgrading topology version - major",
 			expectErr: true,
@@ -661,6 +844,57 @@ func TestClusterTopologyValidation(t *testing.T) {
 					Build()).
 				Build(),
 		},
+		{
+			name:      "should pass when upgrading +2 minor version through intermediate versions",
+			expectErr: false,
+			old: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.2.3").
+					Build()).
+				Build(),
+			in: builder.Cluster("fooboo", "cluster1").
+				WithAnnotations(map[string]string{clusterv1.ClusterTopologyUpgradePathAnnotation: "v1.1.0,v1.3.5"}).
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.4.0").
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should return error when upgrading through intermediate versions skipping a minor version",
+			expectErr: true,
+			old: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.2.3").
+					Build()).
+				Build(),
+			in: builder.Cluster("fooboo", "cluster1").
+				WithAnnotations(map[string]string{clusterv1.ClusterTopologyUpgradePathAnnotation: "v1.3.5"}).
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.5.0").
+					Build()).
+				Build(),
+		},
+		{
+			name:      "should return error when the intermediate versions are not valid",
+			expectErr: true,
+			old: builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.2.3").
+					Build()).
+				Build(),
+			in: builder.Cluster("fooboo", "cluster1").
+				WithAnnotations(map[string]string{clusterv1.ClusterTopologyUpgradePathAnnotation: "v1.3"}).
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.4.0").
+					Build()).
+				Build(),
+		},
 		{
 			name:      "should return error when duplicated MachineDeployments names exists in a Topology",
 			expectErr: true,
@@ -924,6 +1158,66 @@ func TestClusterTopologyValidation(t *testing.T) {
 	}
 }
 
+func TestClusterTopologyValidationForMachinePools(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	tests := []struct {
+		name               string
+		machinePoolEnabled bool
+		machinePools       []clusterv1.MachinePoolTopology
+		expectErr          bool
+	}{
+		{
+			name:               "should accept MachinePools defined in the ClusterClass",
+			machinePoolEnabled: true,
+			machinePools:       []clusterv1.MachinePoolTopology{{Class: "aa", Name: "pool1"}},
+			expectErr:          false,
+		},
+		{
+			name:               "should return error when MachinePools are set and the MachinePool feature flag is disabled",
+			machinePoolEnabled: false,
+			machinePools:       []clusterv1.MachinePoolTopology{{Class: "aa", Name: "pool1"}},
+			expectErr:          true,
+		},
+		{
+			name:               "should return error when the MachinePool class is not defined in the ClusterClass",
+			machinePoolEnabled: true,
+			machinePools:       []clusterv1.MachinePoolTopology{{Class: "bb", Name: "pool1"}},
+			expectErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, tt.machinePoolEnabled)()
+			g := NewWithT(t)
+
+			class := builder.ClusterClass("fooboo", "foo").Build()
+			class.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{{Class: "aa"}}
+			cluster := builder.Cluster("fooboo", "cluster1").
+				WithTopology(builder.ClusterTopology().
+					WithClass("foo").
+					WithVersion("v1.19.1").
+					Build()).
+				Build()
+			cluster.Spec.Topology.Workers = &clusterv1.WorkersTopology{MachinePools: tt.machinePools}
+
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(class).
+				WithScheme(fakeScheme).
+				Build()
+			webhook := &Cluster{Client: fakeClient}
+
+			err := webhook.validate(ctx, nil, cluster)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
 // TestClusterTopologyValidationWithClient tests the additional cases introduced in new validation in the webhook package.
 func TestClusterTopologyValidationWithClient(t *testing.T) {
 	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
@@ -1619,6 +1913,231 @@ func TestClusterTopologyValidationForTopologyClassChange(t *testing.T) {
 	}
 }
 
+// TestClusterTopologyValidationForAllowedClasses tests that Clusters can only use the ClusterClasses allowed in their namespace.
+func TestClusterTopologyValidationForAllowedClasses(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	ref := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "barTemplate",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	clusterClass := func(name, tier string) *clusterv1.ClusterClass {
+		clusterClass := builder.ClusterClass(metav1.NamespaceDefault, name).
+			WithInfrastructureClusterTemplate(refToUnstructured(ref)).
+			WithControlPlaneTemplate(refToUnstructured(ref)).
+			WithControlPlaneInfrastructureMachineTemplate(refToUnstructured(ref)).
+			Build()
+		clusterClass.Labels = map[string]string{"tier": tier}
+		return clusterClass
+	}
+	goldClass := clusterClass("gold", "gold")
+	bronzeClass := clusterClass("bronze", "bronze")
+	// fakeGoldClass is a ClusterClass created by a tenant with the same labels of an allowed ClusterClass.
+	fakeGoldClass := clusterClass("fake-gold", "gold")
+
+	namespace := func(annotations map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        metav1.NamespaceDefault,
+				Annotations: annotations,
+			},
+		}
+	}
+	cluster := func(class string) *clusterv1.Cluster {
+		return builder.Cluster(metav1.NamespaceDefault, "cluster1").
+			WithTopology(
+				builder.ClusterTopology().
+					WithClass(class).
+					WithVersion("v1.22.2").
+					Build()).
+			Build()
+	}
+
+	tests := []struct {
+		name       string
+		namespace  *corev1.Namespace
+		oldCluster *clusterv1.Cluster
+		cluster    *clusterv1.Cluster
+		wantErr    bool
+	}{
+		{
+			name:      "Accept any ClusterClass if the Namespace does not have the annotation",
+			namespace: namespace(nil),
+			cluster:   cluster("bronze"),
+			wantErr:   false,
+		},
+		{
+			name:      "Accept a ClusterClass listed in the Namespace annotation",
+			namespace: namespace(map[string]string{clusterv1.ClusterTopologyAllowedClassesAnnotation: "gold, silver"}),
+			cluster:   cluster("gold"),
+			wantErr:   false,
+		},
+		{
+			name:      "Reject a ClusterClass not listed in the Namespace annotation",
+			namespace: namespace(map[string]string{clusterv1.ClusterTopologyAllowedClassesAnnotation: "gold,silver"}),
+			cluster:   cluster("bronze"),
+			wantErr:   true,
+		},
+		{
+			name:      "Reject a ClusterClass not listed in the Namespace annotation even if its labels match an allowed ClusterClass",
+			namespace: namespace(map[string]string{clusterv1.ClusterTopologyAllowedClassesAnnotation: "gold"}),
+			cluster:   cluster("fake-gold"),
+			wantErr:   true,
+		},
+		{
+			name:      "Reject any ClusterClass if the Namespace annotation is empty",
+			namespace: namespace(map[string]string{clusterv1.ClusterTopologyAllowedClassesAnnotation: ""}),
+			cluster:   cluster("gold"),
+			wantErr:   true,
+		},
+		{
+			name:       "Reject changing the class to a ClusterClass not listed in the Namespace annotation",
+			namespace:  namespace(map[string]string{clusterv1.ClusterTopologyAllowedClassesAnnotation: "gold"}),
+			oldCluster: cluster("gold"),
+			cluster:    cluster("bronze"),
+			wantErr:    true,
+		},
+		{
+			name:       "Accept updates to a Cluster using a ClusterClass not listed in the Namespace annotation if the class is not changed",
+			namespace:  namespace(map[string]string{clusterv1.ClusterTopologyAllowedClassesAnnotation: "gold"}),
+			oldCluster: cluster("bronze"),
+			cluster:    cluster("bronze"),
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(tt.namespace, goldClass, bronzeClass, fakeGoldClass).
+				WithScheme(fakeScheme).
+				Build()
+
+			c := &Cluster{Client: fakeClient}
+
+			var err error
+			if tt.oldCluster == nil {
+				err = c.ValidateCreate(ctx, tt.cluster)
+			} else {
+				err = c.ValidateUpdate(ctx, tt.oldCluster, tt.cluster)
+			}
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestClusterTopologyValidationForClusterNetwork(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	ref := &corev1.ObjectReference{
+		APIVersion: "group.test.io/foo",
+		Kind:       "barTemplate",
+		Name:       "baz",
+		Namespace:  "default",
+	}
+	clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").
+		WithInfrastructureClusterTemplate(refToUnstructured(ref)).
+		WithControlPlaneTemplate(refToUnstructured(ref)).
+		WithControlPlaneInfrastructureMachineTemplate(refToUnstructured(ref)).
+		WithClusterNetwork(&clusterv1.ClusterClassNetwork{
+			Pods: &clusterv1.ClusterClassNetworkRanges{
+				PermittedCIDRBlocks: []string{"192.168.0.0/16", "fd00::/8"},
+			},
+		}).
+		Build()
+
+	cluster := func(podsCIDRBlocks ...string) *clusterv1.Cluster {
+		b := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+			WithTopology(
+				builder.ClusterTopology().
+					WithClass("class1").
+					WithVersion("v1.22.2").
+					Build())
+		if len(podsCIDRBlocks) > 0 {
+			b = b.WithClusterNetwork(&clusterv1.ClusterNetwork{
+				Pods: &clusterv1.NetworkRanges{CIDRBlocks: podsCIDRBlocks},
+			})
+		}
+		return b.Build()
+	}
+
+	tests := []struct {
+		name       string
+		oldCluster *clusterv1.Cluster
+		cluster    *clusterv1.Cluster
+		wantErr    bool
+	}{
+		{
+			name:    "Accept a Cluster without cluster network",
+			cluster: cluster(),
+			wantErr: false,
+		},
+		{
+			name:    "Accept CIDR blocks contained in the permitted CIDR blocks",
+			cluster: cluster("192.168.1.0/24", "fd00:100::/64"),
+			wantErr: false,
+		},
+		{
+			name:    "Accept CIDR blocks equal to the permitted CIDR blocks",
+			cluster: cluster("192.168.0.0/16"),
+			wantErr: false,
+		},
+		{
+			name:    "Reject CIDR blocks not contained in the permitted CIDR blocks",
+			cluster: cluster("10.0.0.0/16"),
+			wantErr: true,
+		},
+		{
+			name:    "Reject CIDR blocks larger than the permitted CIDR blocks",
+			cluster: cluster("192.168.0.0/15"),
+			wantErr: true,
+		},
+		{
+			name:       "Reject changing the cluster network to CIDR blocks not contained in the permitted CIDR blocks",
+			oldCluster: cluster("192.168.0.0/16"),
+			cluster:    cluster("10.0.0.0/16"),
+			wantErr:    true,
+		},
+		{
+			name:       "Accept updates to a Cluster with CIDR blocks not contained in the permitted CIDR blocks if the cluster network is not changed",
+			oldCluster: cluster("10.0.0.0/16"),
+			cluster:    cluster("10.0.0.0/16"),
+			wantErr:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeClient := fake.NewClientBuilder().
+				WithObjects(clusterClass).
+				WithScheme(fakeScheme).
+				Build()
+
+			c := &Cluster{Client: fakeClient}
+
+			var err error
+			if tt.oldCluster == nil {
+				err = c.ValidateCreate(ctx, tt.cluster)
+			} else {
+				err = c.ValidateUpdate(ctx, tt.oldCluster, tt.cluster)
+			}
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
 // TestMovingBetweenManagedAndUnmanaged cluster tests cases where a clusterClass is added or removed during a cluster update.
 func TestMovingBetweenManagedAndUnmanaged(t *testing.T) {
 	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
@@ -1724,3 +2243,307 @@ func refToUnstructured(ref *corev1.ObjectReference) *unstructured.Unstructured {
 	output.SetNamespace(ref.Namespace)
 	return output
 }
+
+func TestValidateTopologyOperatingSystems(t *testing.T) {
+	osLabel := func(os string) clusterv1.ObjectMeta {
+		return clusterv1.ObjectMeta{Labels: map[string]string{corev1.LabelOSStable: os}}
+	}
+	clusterClass := func(cniSupportedOS string, controlPlaneOS string) *clusterv1.ClusterClass {
+		cc := &clusterv1.ClusterClass{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "class1"},
+			Spec: clusterv1.ClusterClassSpec{
+				Workers: clusterv1.WorkersClass{
+					MachineDeployments: []clusterv1.MachineDeploymentClass{
+						{Class: "linux-workers"},
+						{Class: "windows-workers", Template: clusterv1.MachineDeploymentClassTemplate{Metadata: osLabel("windows")}},
+					},
+				},
+			},
+		}
+		if cniSupportedOS != "" {
+			cc.Annotations = map[string]string{clusterv1.ClusterClassCNISupportedOSAnnotation: cniSupportedOS}
+		}
+		if controlPlaneOS != "" {
+			cc.Spec.ControlPlane.Metadata = osLabel(controlPlaneOS)
+		}
+		return cc
+	}
+	cluster := func(machineDeployments ...clusterv1.MachineDeploymentTopology) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1"},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{
+					Class:   "class1",
+					Workers: &clusterv1.WorkersTopology{MachineDeployments: machineDeployments},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		cluster      *clusterv1.Cluster
+		clusterClass *clusterv1.ClusterClass
+		wantErr      bool
+	}{
+		{
+			name:         "Linux is assumed if the operating system is not defined",
+			cluster:      cluster(clusterv1.MachineDeploymentTopology{Class: "linux-workers", Name: "md1"}),
+			clusterClass: clusterClass("linux", ""),
+		},
+		{
+			name: "Mixed Linux and Windows MachineDeployments are allowed if supported by the CNI",
+			cluster: cluster(
+				clusterv1.MachineDeploymentTopology{Class: "linux-workers", Name: "md1"},
+				clusterv1.MachineDeploymentTopology{Class: "windows-workers", Name: "md2"},
+			),
+			clusterClass: clusterClass("linux, windows", ""),
+		},
+		{
+			name:         "Windows MachineDeployments are allowed if the CNI supported operating systems are not defined",
+			cluster:      cluster(clusterv1.MachineDeploymentTopology{Class: "windows-workers", Name: "md1"}),
+			clusterClass: clusterClass("", ""),
+		},
+		{
+			name:         "Windows MachineDeployments are rejected if not supported by the CNI",
+			cluster:      cluster(clusterv1.MachineDeploymentTopology{Class: "windows-workers", Name: "md1"}),
+			clusterClass: clusterClass("linux", ""),
+			wantErr:      true,
+		},
+		{
+			name:         "Operating system defined in the Cluster topology takes precedence",
+			cluster:      cluster(clusterv1.MachineDeploymentTopology{Class: "linux-workers", Name: "md1", Metadata: osLabel("windows")}),
+			clusterClass: clusterClass("linux", ""),
+			wantErr:      true,
+		},
+		{
+			name:         "Unknown operating systems are rejected",
+			cluster:      cluster(clusterv1.MachineDeploymentTopology{Class: "linux-workers", Name: "md1", Metadata: osLabel("plan9")}),
+			clusterClass: clusterClass("", ""),
+			wantErr:      true,
+		},
+		{
+			name:         "Windows control planes are rejected",
+			cluster:      cluster(),
+			clusterClass: clusterClass("", "windows"),
+			wantErr:      true,
+		},
+		{
+			name:         "Control planes with an operating system not supported by the CNI are rejected",
+			cluster:      cluster(),
+			clusterClass: clusterClass("windows", ""),
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := validateTopologyOperatingSystems(tt.cluster, tt.clusterClass)
+			if tt.wantErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
+func TestValidateBootstrapOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides *clusterv1.MachineDeploymentBootstrapOverrides
+		wantErr   bool
+	}{
+		{
+			name: "Valid overrides",
+			overrides: &clusterv1.MachineDeploymentBootstrapOverrides{
+				KubeletExtraArgs: map[string]string{"max-pods": "200"},
+				NodeLabels:       map[string]string{"node.example.com/gpu": "true"},
+				Taints:           []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+			},
+		},
+		{
+			name: "Invalid node label key",
+			overrides: &clusterv1.MachineDeploymentBootstrapOverrides{
+				NodeLabels: map[string]string{"gpu/": "true"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid node label value",
+			overrides: &clusterv1.MachineDeploymentBootstrapOverrides{
+				NodeLabels: map[string]string{"gpu": "true,false"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Node labels defined both as node labels and kubelet extra arg",
+			overrides: &clusterv1.MachineDeploymentBootstrapOverrides{
+				KubeletExtraArgs: map[string]string{"node-labels": "gpu=true"},
+				NodeLabels:       map[string]string{"gpu": "true"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid taint effect",
+			overrides: &clusterv1.MachineDeploymentBootstrapOverrides{
+				Taints: []corev1.Taint{{Key: "gpu", Effect: "NoWay"}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := validateBootstrapOverrides(tt.overrides, field.NewPath("spec", "topology", "workers", "machineDeployments").Index(0).Child("bootstrapOverrides"))
+			if tt.wantErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
+func TestValidateBootstrapOverridesAreSupported(t *testing.T) {
+	clusterClass := &clusterv1.ClusterClass{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "class1"},
+		Spec: clusterv1.ClusterClassSpec{
+			Workers: clusterv1.WorkersClass{
+				MachineDeployments: []clusterv1.MachineDeploymentClass{
+					{
+						Class: "kubeadm-workers",
+						Template: clusterv1.MachineDeploymentClassTemplate{Bootstrap: clusterv1.LocalObjectTemplate{
+							Ref: &corev1.ObjectReference{APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1", Kind: "KubeadmConfigTemplate", Name: "kubeadm"},
+						}},
+					},
+					{
+						Class: "other-workers",
+						Template: clusterv1.MachineDeploymentClassTemplate{Bootstrap: clusterv1.LocalObjectTemplate{
+							Ref: &corev1.ObjectReference{APIVersion: "bootstrap.example.com/v1beta1", Kind: "OtherConfigTemplate", Name: "other"},
+						}},
+					},
+				},
+			},
+		},
+	}
+	overrides := &clusterv1.MachineDeploymentBootstrapOverrides{NodeLabels: map[string]string{"gpu": "true"}}
+	cluster := func(machineDeployments ...clusterv1.MachineDeploymentTopology) *clusterv1.Cluster {
+		return &clusterv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "cluster1"},
+			Spec: clusterv1.ClusterSpec{
+				Topology: &clusterv1.Topology{
+					Class:   "class1",
+					Workers: &clusterv1.WorkersTopology{MachineDeployments: machineDeployments},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cluster *clusterv1.Cluster
+		wantErr bool
+	}{
+		{
+			name:    "Overrides are allowed for MachineDeployments using a KubeadmConfigTemplate",
+			cluster: cluster(clusterv1.MachineDeploymentTopology{Class: "kubeadm-workers", Name: "md1", BootstrapOverrides: overrides}),
+		},
+		{
+			name:    "MachineDeployments using other bootstrap configuration templates are allowed without overrides",
+			cluster: cluster(clusterv1.MachineDeploymentTopology{Class: "other-workers", Name: "md1"}),
+		},
+		{
+			name:    "Overrides are rejected for MachineDeployments using other bootstrap configuration templates",
+			cluster: cluster(clusterv1.MachineDeploymentTopology{Class: "other-workers", Name: "md1", BootstrapOverrides: overrides}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := validateBootstrapOverridesAreSupported(tt.cluster, clusterClass)
+			if tt.wantErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
+func TestClusterValidateDelete(t *testing.T) {
+	machine := func(name, clusterName string) client.Object {
+		return builder.Machine(metav1.NamespaceDefault, name).
+			WithClusterName(clusterName).
+			WithLabels(map[string]string{clusterv1.ClusterLabelName: clusterName}).
+			Build()
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		machines    []client.Object
+		expectErr   bool
+	}{
+		{
+			name:     "allows deletion of a Cluster without deletion protection",
+			machines: []client.Object{machine("m1", "cluster1"), machine("m2", "cluster1")},
+		},
+		{
+			name:        "allows deletion of a protected Cluster with no more Machines than allowed",
+			annotations: map[string]string{clusterv1.ClusterDeletionProtectionAnnotation: "2"},
+			machines:    []client.Object{machine("m1", "cluster1"), machine("m2", "cluster1"), machine("m3", "cluster2")},
+		},
+		{
+			name:        "rejects deletion of a protected Cluster with more Machines than allowed",
+			annotations: map[string]string{clusterv1.ClusterDeletionProtectionAnnotation: "1"},
+			machines:    []client.Object{machine("m1", "cluster1"), machine("m2", "cluster1")},
+			expectErr:   true,
+		},
+		{
+			name: "allows deletion of an unlocked protected Cluster",
+			annotations: map[string]string{
+				clusterv1.ClusterDeletionProtectionAnnotation: "0",
+				clusterv1.ClusterDeletionUnlockAnnotation:     time.Now().Add(10 * time.Minute).Format(time.RFC3339),
+			},
+			machines: []client.Object{machine("m1", "cluster1")},
+		},
+		{
+			name: "rejects deletion of a protected Cluster when the unlock is expired",
+			annotations: map[string]string{
+				clusterv1.ClusterDeletionProtectionAnnotation: "0",
+				clusterv1.ClusterDeletionUnlockAnnotation:     time.Now().Add(-10 * time.Minute).Format(time.RFC3339),
+			},
+			machines:  []client.Object{machine("m1", "cluster1")},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := builder.Cluster(metav1.NamespaceDefault, "cluster1").
+				WithAnnotations(tt.annotations).
+				Build()
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(tt.machines...).
+				Build()
+			webhook := &Cluster{Client: fakeClient}
+
+			err := webhook.ValidateDelete(ctx, cluster)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+				g.Expect(err.Error()).To(HavePrefix("clusters.cluster.x-k8s.io \"cluster1\" is forbidden: cluster is protected"))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -26,9 +26,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -36,8 +38,11 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/contract"
 	"sigs.k8s.io/cluster-api/internal/topology/check"
+	"sigs.k8s.io/cluster-api/internal/topology/clusterclass"
 	"sigs.k8s.io/cluster-api/internal/topology/variables"
+	"sigs.k8s.io/cluster-api/util/annotations"
 )
 
 func (webhook *ClusterClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
@@ -66,16 +71,8 @@ func (webhook *ClusterClass) Default(_ context.Context, obj runtime.Object) erro
 		return apierrors.NewBadRequest(fmt.Sprintf("expected a ClusterClass but got a %T", obj))
 	}
 	// Default all namespaces in the references to the object namespace.
-	defaultNamespace(in.Spec.Infrastructure.Ref, in.Namespace)
-	defaultNamespace(in.Spec.ControlPlane.Ref, in.Namespace)
-
-	if in.Spec.ControlPlane.MachineInfrastructure != nil {
-		defaultNamespace(in.Spec.ControlPlane.MachineInfrastructure.Ref, in.Namespace)
-	}
-
-	for i := range in.Spec.Workers.MachineDeployments {
-		defaultNamespace(in.Spec.Workers.MachineDeployments[i].Template.Bootstrap.Ref, in.Namespace)
-		defaultNamespace(in.Spec.Workers.MachineDeployments[i].Template.Infrastructure.Ref, in.Namespace)
+	for _, t := range clusterclass.TemplateRefs(in) {
+		defaultNamespace(t.Ref, in.Namespace)
 	}
 	return nil
 }
@@ -142,12 +139,34 @@ func (webhook *ClusterClass) validate(ctx context.Context, oldClusterClass, newC
 	// Ensure all references are valid.
 	allErrs = append(allErrs, check.ClusterClassReferencesAreValid(newClusterClass)...)
 
+	// Ensure all referenced templates exist; this is checked only on create or when a reference changes, so that
+	// deleting a template does not block updates to existing ClusterClasses. The check is skipped for paused
+	// ClusterClasses, e.g. when clusterctl move creates the ClusterClass before its templates.
+	if !annotations.HasPaused(newClusterClass) {
+		allErrs = append(allErrs, webhook.validateTemplatesExist(ctx, oldClusterClass, newClusterClass)...)
+	}
+
 	// Ensure all MachineDeployment classes are unique.
 	allErrs = append(allErrs, check.MachineDeploymentClassesAreUnique(newClusterClass)...)
 
+	// Ensure all MachinePool classes are unique, and MachinePool classes are only used with the MachinePool feature flag.
+	allErrs = append(allErrs, check.MachinePoolClassesAreUnique(newClusterClass)...)
+	if len(newClusterClass.Spec.Workers.MachinePools) > 0 && !feature.Gates.Enabled(feature.MachinePool) {
+		allErrs = append(allErrs, field.Forbidden(
+			field.NewPath("spec", "workers", "machinePools"),
+			"can be set only if the MachinePool feature flag is enabled",
+		))
+	}
+
+	// Ensure failure domain specific machine infrastructure is valid.
+	allErrs = append(allErrs, validateFailureDomainMachineInfrastructure(newClusterClass)...)
+
 	// Ensure MachineHealthChecks are valid.
 	allErrs = append(allErrs, validateMachineHealthCheckClasses(newClusterClass)...)
 
+	// Ensure the cluster network defaults are valid.
+	allErrs = append(allErrs, validateClusterClassNetwork(newClusterClass)...)
+
 	// Validate variables.
 	allErrs = append(allErrs,
 		variables.ValidateClusterClassVariables(ctx, newClusterClass.Spec.Variables, field.NewPath("spec", "variables"))...,
@@ -156,6 +175,10 @@ func (webhook *ClusterClass) validate(ctx context.Context, oldClusterClass, newC
 	// Validate patches.
 	allErrs = append(allErrs, validatePatches(newClusterClass)...)
 
+	// Validate ignore paths.
+	allErrs = append(allErrs, validateIgnorePaths(newClusterClass)...)
+	allErrs = append(allErrs, validateBuiltinPatches(newClusterClass)...)
+
 	// If this is an update run additional validation.
 	if oldClusterClass != nil {
 		// Ensure spec changes are compatible.
@@ -173,6 +196,10 @@ func (webhook *ClusterClass) validate(ctx context.Context, oldClusterClass, newC
 		allErrs = append(allErrs,
 			webhook.validateRemovedMachineDeploymentClassesAreNotUsed(clusters, oldClusterClass, newClusterClass)...)
 
+		// Ensure no MachinePoolClass currently in use has been removed from the ClusterClass.
+		allErrs = append(allErrs,
+			webhook.validateRemovedMachinePoolClassesAreNotUsed(clusters, oldClusterClass, newClusterClass)...)
+
 		// Ensure no MachineHealthCheck currently in use has been removed from the ClusterClass.
 		allErrs = append(allErrs,
 			validateUpdatesToMachineHealthCheckClasses(clusters, oldClusterClass, newClusterClass)...)
@@ -466,6 +493,44 @@ func getClusterClassVariablesForValidation(oldVars, newVars map[string]*clusterv
 	return out
 }
 
+// validateTemplatesExist checks that the templates referenced by the ClusterClass exist; references which are
+// already set in the old ClusterClass are not checked.
+func (webhook *ClusterClass) validateTemplatesExist(ctx context.Context, oldClusterClass, newClusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	oldRefs := map[corev1.ObjectReference]bool{}
+	if oldClusterClass != nil {
+		for _, t := range clusterclass.TemplateRefs(oldClusterClass) {
+			oldRefs[*t.Ref] = true
+		}
+	}
+
+	for _, t := range clusterclass.TemplateRefs(newClusterClass) {
+		// Incomplete references are reported by check.ClusterClassReferencesAreValid.
+		if oldRefs[*t.Ref] || t.Ref.APIVersion == "" || t.Ref.Kind == "" || t.Ref.Name == "" {
+			continue
+		}
+		template := &unstructured.Unstructured{}
+		template.SetAPIVersion(t.Ref.APIVersion)
+		template.SetKind(t.Ref.Kind)
+		if err := webhook.Client.Get(ctx, client.ObjectKey{Namespace: t.Ref.Namespace, Name: t.Ref.Name}, template); err != nil {
+			if apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.Invalid(
+					t.Path.Child("ref", "name"),
+					t.Ref.Name,
+					fmt.Sprintf("%s %s could not be found", t.Ref.Kind, klog.KRef(t.Ref.Namespace, t.Ref.Name)),
+				))
+				continue
+			}
+			allErrs = append(allErrs, field.InternalError(
+				t.Path.Child("ref"),
+				errors.Wrapf(err, "failed to get %s %s", t.Ref.Kind, klog.KRef(t.Ref.Namespace, t.Ref.Name)),
+			))
+		}
+	}
+	return allErrs
+}
+
 func (webhook *ClusterClass) validateRemovedMachineDeploymentClassesAreNotUsed(clusters []clusterv1.Cluster, oldClusterClass, newClusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -490,6 +555,40 @@ func (webhook *ClusterClass) validateRemovedMachineDeploymentClassesAreNotUsed(c
 	return allErrs
 }
 
+func (webhook *ClusterClass) validateRemovedMachinePoolClassesAreNotUsed(clusters []clusterv1.Cluster, oldClusterClass, newClusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+
+	classes := sets.NewString()
+	for _, class := range newClusterClass.Spec.Workers.MachinePools {
+		classes.Insert(class.Class)
+	}
+	removedClasses := sets.NewString()
+	for _, oldClass := range oldClusterClass.Spec.Workers.MachinePools {
+		if !classes.Has(oldClass.Class) {
+			removedClasses.Insert(oldClass.Class)
+		}
+	}
+	// If no classes have been removed return early as no further checks are needed.
+	if len(removedClasses) == 0 {
+		return nil
+	}
+	// Error if any Cluster using the ClusterClass uses a MachinePoolClass that has been removed.
+	for _, c := range clusters {
+		if c.Spec.Topology.Workers == nil {
+			continue
+		}
+		for _, machinePoolTopology := range c.Spec.Topology.Workers.MachinePools {
+			if removedClasses.Has(machinePoolTopology.Class) {
+				allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "workers", "machinePools"),
+					fmt.Sprintf("MachinePoolClass %q cannot be deleted because it is used by Cluster %q",
+						machinePoolTopology.Class, c.Name),
+				))
+			}
+		}
+	}
+	return allErrs
+}
+
 func (webhook *ClusterClass) removedMachineClasses(oldClusterClass, newClusterClass *clusterv1.ClusterClass) sets.String {
 	removedClasses := sets.NewString()
 
@@ -534,6 +633,118 @@ func getClusterClassVariablesMapWithReverseIndex(clusterClassVariables []cluster
 	return variablesMap, variablesIndexMap
 }
 
+// validateIgnorePaths validates the paths defined in the ClusterTopologyIgnorePathsAnnotation, if any.
+func validateIgnorePaths(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	value, ok := clusterClass.GetAnnotations()[clusterv1.ClusterTopologyIgnorePathsAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := contract.ParsePaths(value); err != nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("metadata", "annotations").Key(clusterv1.ClusterTopologyIgnorePathsAnnotation),
+			value,
+			err.Error(),
+		)}
+	}
+	return nil
+}
+
+// validateBuiltinPatches validates the builtin patches enabled with the ClusterTopologyBuiltinPatchesAnnotation, if any;
+// builtin patches must be known and the ClusterClass must define the variables they are using.
+func validateBuiltinPatches(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	value, ok := clusterClass.GetAnnotations()[clusterv1.ClusterTopologyBuiltinPatchesAnnotation]
+	if !ok {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("metadata", "annotations").Key(clusterv1.ClusterTopologyBuiltinPatchesAnnotation)
+	knownPatches := sets.NewString(
+		clusterv1.ClusterTopologyBuiltinPatchProxy,
+		clusterv1.ClusterTopologyBuiltinPatchRegistryMirrors,
+		clusterv1.ClusterTopologyBuiltinPatchControlPlaneLoadBalancer,
+	)
+	variables := sets.NewString()
+	for _, variable := range clusterClass.Spec.Variables {
+		variables.Insert(variable.Name)
+	}
+	for _, patch := range strings.Split(value, ",") {
+		patch = strings.TrimSpace(patch)
+		if patch == "" {
+			continue
+		}
+		if !knownPatches.Has(patch) {
+			allErrs = append(allErrs, field.NotSupported(fldPath, patch, knownPatches.List()))
+			continue
+		}
+		if !variables.Has(patch) {
+			allErrs = append(allErrs, field.Invalid(fldPath, value,
+				fmt.Sprintf("builtin patch %q requires a variable with the same name to be defined in spec.variables", patch)))
+		}
+	}
+	return allErrs
+}
+
+func validateFailureDomainMachineInfrastructure(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "controlPlane", "failureDomainMachineInfrastructure")
+
+	if len(clusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure) == 0 {
+		return nil
+	}
+
+	// Ensure ControlPlane does not define failure domain specific machine infrastructure if it does not define MachineInfrastructure.
+	if clusterClass.Spec.ControlPlane.MachineInfrastructure == nil {
+		allErrs = append(allErrs, field.Forbidden(
+			fldPath,
+			"can be set only if spec.controlPlane.machineInfrastructure is set",
+		))
+	}
+
+	failureDomains := sets.NewString()
+	for i, machineInfrastructure := range clusterClass.Spec.ControlPlane.FailureDomainMachineInfrastructure {
+		if machineInfrastructure.FailureDomain == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("failureDomain"), "cannot be empty"))
+			continue
+		}
+		if failureDomains.Has(machineInfrastructure.FailureDomain) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("failureDomain"), machineInfrastructure.FailureDomain))
+		}
+		failureDomains.Insert(machineInfrastructure.FailureDomain)
+	}
+	return allErrs
+}
+
+// validateClusterClassNetwork validates the CIDR blocks defined in the cluster network of a ClusterClass, and ensures
+// the default CIDR blocks are contained in the permitted ones.
+func validateClusterClassNetwork(clusterClass *clusterv1.ClusterClass) field.ErrorList {
+	if clusterClass.Spec.ClusterNetwork == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	fldPath := field.NewPath("spec", "clusterNetwork")
+	allErrs = append(allErrs, validateClusterClassNetworkRanges(fldPath.Child("pods"), clusterClass.Spec.ClusterNetwork.Pods)...)
+	allErrs = append(allErrs, validateClusterClassNetworkRanges(fldPath.Child("services"), clusterClass.Spec.ClusterNetwork.Services)...)
+	return allErrs
+}
+
+func validateClusterClassNetworkRanges(fldPath *field.Path, ranges *clusterv1.ClusterClassNetworkRanges) field.ErrorList {
+	if ranges == nil {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateCIDRBlocks(fldPath.Child("permittedCIDRBlocks"), ranges.PermittedCIDRBlocks)...)
+	allErrs = append(allErrs, validateCIDRBlocks(fldPath.Child("cidrBlocks"), ranges.CIDRBlocks)...)
+
+	// Check the default CIDR blocks are permitted only if all the CIDR blocks are valid.
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	return validateCIDRBlocksArePermitted(fldPath.Child("cidrBlocks"), ranges.CIDRBlocks, ranges.PermittedCIDRBlocks)
+}
+
 func validateMachineHealthCheckClasses(clusterClass *clusterv1.ClusterClass) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -24,7 +24,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -34,6 +36,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/internal/topology/clusterclass"
 	"sigs.k8s.io/cluster-api/internal/webhooks/util"
 )
 
@@ -44,6 +47,7 @@ var (
 
 func init() {
 	_ = clusterv1.AddToScheme(fakeScheme)
+	_ = corev1.AddToScheme(fakeScheme)
 }
 
 func TestClusterClassDefaultNamespaces(t *testing.T) {
@@ -73,6 +77,7 @@ func TestClusterClassDefaultNamespaces(t *testing.T) {
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(fakeScheme).
+		WithObjects(clusterClassTemplates(in)...).
 		Build()
 
 	// Create the webhook and add the fakeClient as its client.
@@ -646,6 +651,126 @@ func TestClusterClassValidation(t *testing.T) {
 						Duration: time.Duration(6000000000000)}}).
 				Build(),
 		},
+		{
+			name: "create pass if failureDomainMachineInfrastructure is defined for ControlPlane with MachineInfrastructure set",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithControlPlaneFailureDomainInfrastructureMachineTemplate("fd1",
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfraFd1").
+						Build()).
+				Build(),
+			expectErr: false,
+		},
+		{
+			name: "create fail if failureDomainMachineInfrastructure is defined for ControlPlane with MachineInfrastructure unset",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				// No ControlPlaneMachineInfrastructure makes this an invalid creation request.
+				WithControlPlaneFailureDomainInfrastructureMachineTemplate("fd1",
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfraFd1").
+						Build()).
+				Build(),
+			expectErr: true,
+		},
+		{
+			name: "create fail if failureDomainMachineInfrastructure has an empty failure domain",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithControlPlaneFailureDomainInfrastructureMachineTemplate("",
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfraFd1").
+						Build()).
+				Build(),
+			expectErr: true,
+		},
+		{
+			name: "create fail if failureDomainMachineInfrastructure has an inconsistent namespace",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithControlPlaneInfrastructureMachineTemplate(
+					builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, "cpInfra1").
+						Build()).
+				WithControlPlaneFailureDomainInfrastructureMachineTemplate("fd1",
+					builder.InfrastructureMachineTemplate("WrongNamespace", "cpInfraFd1").
+						Build()).
+				Build(),
+			expectErr: true,
+		},
+		{
+			name: "create pass if the cluster network defaults are within the permitted CIDR blocks",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithClusterNetwork(&clusterv1.ClusterClassNetwork{
+					Services: &clusterv1.ClusterClassNetworkRanges{
+						CIDRBlocks:          []string{"10.128.0.0/12"},
+						PermittedCIDRBlocks: []string{"10.128.0.0/9"},
+					},
+					Pods: &clusterv1.ClusterClassNetworkRanges{
+						CIDRBlocks: []string{"192.168.0.0/16"},
+					},
+					ServiceDomain: "cluster.local",
+				}).
+				Build(),
+			expectErr: false,
+		},
+		{
+			name: "create fail if the cluster network defines an invalid CIDR block",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithClusterNetwork(&clusterv1.ClusterClassNetwork{
+					Pods: &clusterv1.ClusterClassNetworkRanges{
+						PermittedCIDRBlocks: []string{"192.168.0.0"},
+					},
+				}).
+				Build(),
+			expectErr: true,
+		},
+		{
+			name: "create fail if the cluster network defaults are not within the permitted CIDR blocks",
+			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
+				WithInfrastructureClusterTemplate(
+					builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+				WithControlPlaneTemplate(
+					builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").
+						Build()).
+				WithClusterNetwork(&clusterv1.ClusterClassNetwork{
+					Services: &clusterv1.ClusterClassNetworkRanges{
+						CIDRBlocks:          []string{"10.96.0.0/12"},
+						PermittedCIDRBlocks: []string{"10.128.0.0/9"},
+					},
+				}).
+				Build(),
+			expectErr: true,
+		},
 		{
 			name: "create fail if MachineHealthCheck defined for ControlPlane with MachineInfrastructure unset",
 			in: builder.ClusterClass(metav1.NamespaceDefault, "class1").
@@ -1148,6 +1273,86 @@ func TestClusterClassValidation(t *testing.T) {
 			// Sets up the fakeClient for the test case.
 			fakeClient := fake.NewClientBuilder().
 				WithScheme(fakeScheme).
+				WithObjects(clusterClassTemplates(tt.in, tt.old)...).
+				Build()
+
+			// Create the webhook and add the fakeClient as its client.
+			webhook := &ClusterClass{Client: fakeClient}
+			err := webhook.validate(ctx, tt.old, tt.in)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestClusterClassValidationTemplatesExist(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.ClusterTopology, true)()
+
+	clusterClass := func(infrastructureMachineTemplateName string) *builder.ClusterClassBuilder {
+		return builder.ClusterClass(metav1.NamespaceDefault, "class1").
+			WithInfrastructureClusterTemplate(
+				builder.InfrastructureClusterTemplate(metav1.NamespaceDefault, "infra1").Build()).
+			WithControlPlaneTemplate(
+				builder.ControlPlaneTemplate(metav1.NamespaceDefault, "cp1").Build()).
+			WithWorkerMachineDeploymentClasses(
+				*builder.MachineDeploymentClass("aa").
+					WithInfrastructureTemplate(
+						builder.InfrastructureMachineTemplate(metav1.NamespaceDefault, infrastructureMachineTemplateName).Build()).
+					WithBootstrapTemplate(
+						builder.BootstrapTemplate(metav1.NamespaceDefault, "bootstrap1").Build()).
+					Build())
+	}
+	existingTemplates := clusterClassTemplates(clusterClass("infra1").Build())
+
+	tests := []struct {
+		name      string
+		in        *clusterv1.ClusterClass
+		old       *clusterv1.ClusterClass
+		expectErr bool
+	}{
+		{
+			name:      "create should pass if all the templates exist",
+			in:        clusterClass("infra1").Build(),
+			expectErr: false,
+		},
+		{
+			name:      "create should fail if a template does not exist",
+			in:        clusterClass("infra2").Build(),
+			expectErr: true,
+		},
+		{
+			name: "create should pass if a template does not exist but the ClusterClass is paused",
+			in: func() *clusterv1.ClusterClass {
+				in := clusterClass("infra2").Build()
+				in.Annotations = map[string]string{clusterv1.PausedAnnotation: ""}
+				return in
+			}(),
+			expectErr: false,
+		},
+		{
+			name:      "update should pass if a template which does not exist is not changed",
+			old:       clusterClass("infra2").Build(),
+			in:        clusterClass("infra2").Build(),
+			expectErr: false,
+		},
+		{
+			name:      "update should fail if a template is changed to one which does not exist",
+			old:       clusterClass("infra1").Build(),
+			in:        clusterClass("infra2").Build(),
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// Sets up the fakeClient for the test case.
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(fakeScheme).
+				WithObjects(existingTemplates...).
 				Build()
 
 			// Create the webhook and add the fakeClient as its client.
@@ -1162,6 +1367,102 @@ func TestClusterClassValidation(t *testing.T) {
 	}
 }
 
+func TestClusterClassValidationIgnorePaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expectErr   bool
+	}{
+		{
+			name:        "pass without the ignore paths annotation",
+			annotations: nil,
+			expectErr:   false,
+		},
+		{
+			name:        "pass with valid ignore paths",
+			annotations: map[string]string{clusterv1.ClusterTopologyIgnorePathsAnnotation: "spec.foo,spec.template.spec.bar"},
+			expectErr:   false,
+		},
+		{
+			name:        "fail with ignore paths not nested inside spec",
+			annotations: map[string]string{clusterv1.ClusterTopologyIgnorePathsAnnotation: "spec.foo,metadata.labels"},
+			expectErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+			clusterClass.SetAnnotations(tt.annotations)
+
+			errs := validateIgnorePaths(clusterClass)
+			if tt.expectErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
+func TestClusterClassValidationBuiltinPatches(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		variables   []string
+		expectErr   bool
+	}{
+		{
+			name:        "pass without the builtin patches annotation",
+			annotations: nil,
+			expectErr:   false,
+		},
+		{
+			name:        "pass with builtin patches and the corresponding variables",
+			annotations: map[string]string{clusterv1.ClusterTopologyBuiltinPatchesAnnotation: "proxy, registryMirrors, controlPlaneLoadBalancer"},
+			variables:   []string{"proxy", "registryMirrors", "controlPlaneLoadBalancer"},
+			expectErr:   false,
+		},
+		{
+			name:        "fail with unknown builtin patches",
+			annotations: map[string]string{clusterv1.ClusterTopologyBuiltinPatchesAnnotation: "proxy,foo"},
+			variables:   []string{"proxy", "foo"},
+			expectErr:   true,
+		},
+		{
+			name:        "fail with builtin patches without the corresponding variables",
+			annotations: map[string]string{clusterv1.ClusterTopologyBuiltinPatchesAnnotation: "proxy,registryMirrors"},
+			variables:   []string{"proxy"},
+			expectErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			variables := []clusterv1.ClusterClassVariable{}
+			for _, name := range tt.variables {
+				variables = append(variables, clusterv1.ClusterClassVariable{
+					Name: name,
+					Schema: clusterv1.VariableSchema{
+						OpenAPIV3Schema: clusterv1.JSONSchemaProps{Type: "object"},
+					},
+				})
+			}
+			clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").WithVariables(variables...).Build()
+			clusterClass.SetAnnotations(tt.annotations)
+
+			errs := validateBuiltinPatches(clusterClass)
+			if tt.expectErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
 func TestClusterClassValidationWithClusterAwareChecks(t *testing.T) {
 	// NOTE: ClusterTopology feature flag is disabled by default, thus preventing to create or update ClusterClasses.
 	// Enabling the feature flag temporarily for this test.
@@ -2766,3 +3067,32 @@ func TestClusterClassValidationWithVariableChecks(t *testing.T) {
 		})
 	}
 }
+
+// clusterClassTemplates returns the templates referenced by the given ClusterClasses, so they can be added to a fake client.
+func clusterClassTemplates(clusterClasses ...*clusterv1.ClusterClass) []client.Object {
+	objs := []client.Object{}
+	seen := map[corev1.ObjectReference]bool{}
+	for _, clusterClass := range clusterClasses {
+		if clusterClass == nil {
+			continue
+		}
+		for _, t := range clusterclass.TemplateRefs(clusterClass) {
+			ref := *t.Ref
+			if ref.Namespace == "" {
+				ref.Namespace = clusterClass.Namespace
+			}
+			if _, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || ref.APIVersion == "" || seen[ref] {
+				continue
+			}
+			seen[ref] = true
+
+			template := &unstructured.Unstructured{}
+			template.SetAPIVersion(ref.APIVersion)
+			template.SetKind(ref.Kind)
+			template.SetNamespace(ref.Namespace)
+			template.SetName(ref.Name)
+			objs = append(objs, template)
+		}
+	}
+	return objs
+}
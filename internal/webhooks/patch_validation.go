@@ -158,7 +158,8 @@ func validateSelectors(selector clusterv1.PatchSelector, class *clusterv1.Cluste
 
 	// Return an error if none of the possible selectors are enabled.
 	if !(selector.MatchResources.InfrastructureCluster || selector.MatchResources.ControlPlane ||
-		(selector.MatchResources.MachineDeploymentClass != nil && len(selector.MatchResources.MachineDeploymentClass.Names) > 0)) {
+		(selector.MatchResources.MachineDeploymentClass != nil && len(selector.MatchResources.MachineDeploymentClass.Names) > 0) ||
+		(selector.MatchResources.MachinePoolClass != nil && len(selector.MatchResources.MachinePoolClass.Names) > 0)) {
 		return append(allErrs,
 			field.Invalid(
 				path,
@@ -217,6 +218,28 @@ func validateSelectors(selector clusterv1.PatchSelector, class *clusterv1.Cluste
 		}
 	}
 
+	if selector.MatchResources.MachinePoolClass != nil && len(selector.MatchResources.MachinePoolClass.Names) > 0 {
+		for i, name := range selector.MatchResources.MachinePoolClass.Names {
+			match := false
+			for _, mp := range class.Spec.Workers.MachinePools {
+				if mp.Class == name {
+					if selectorMatchTemplate(selector, mp.Template.Infrastructure.Ref) ||
+						selectorMatchTemplate(selector, mp.Template.Bootstrap.Ref) {
+						match = true
+						break
+					}
+				}
+			}
+			if !match {
+				allErrs = append(allErrs, field.Invalid(
+					path.Child("matchResources", "machinePoolClass", "names").Index(i),
+					name,
+					"selector is enabled but matches neither the bootstrap ref nor the infrastructure ref of a MachinePool class",
+				))
+			}
+		}
+	}
+
 	return allErrs
 }
 
@@ -412,6 +435,17 @@ var builtinVariables = sets.NewString(
 	// MachineDeployment ref builtins.
 	"builtin.machineDeployment.bootstrap.configRef.name",
 	"builtin.machineDeployment.infrastructureRef.name",
+
+	// MachinePool builtins.
+	"builtin.machinePool",
+	"builtin.machinePool.class",
+	"builtin.machinePool.name",
+	"builtin.machinePool.replicas",
+	"builtin.machinePool.topologyName",
+	"builtin.machinePool.version",
+	// MachinePool ref builtins.
+	"builtin.machinePool.bootstrap.configRef.name",
+	"builtin.machinePool.infrastructureRef.name",
 )
 
 // validateIndexAccess checks to see if the jsonPath is attempting to add an element in the array i.e. access by number
@@ -1787,6 +1787,100 @@ func Test_validateSelectors(t *testing.T) {
 				Build(),
 			wantErr: true,
 		},
+		{
+			name: "pass if selector targets an existing MachinePoolClass BootstrapConfigTemplate",
+			selector: clusterv1.PatchSelector{
+				APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+				Kind:       "BootstrapConfigTemplate",
+				MatchResources: clusterv1.PatchSelectorMatch{
+					MachinePoolClass: &clusterv1.PatchSelectorMatchMachinePoolClass{
+						Names: []string{"aa"},
+					},
+				},
+			},
+			clusterClass: func() *clusterv1.ClusterClass {
+				clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+				clusterClass.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{
+					{
+						Class: "aa",
+						Template: clusterv1.MachinePoolClassTemplate{
+							Bootstrap: clusterv1.LocalObjectTemplate{Ref: &corev1.ObjectReference{
+								APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+								Kind:       "BootstrapConfigTemplate",
+							}},
+							Infrastructure: clusterv1.LocalObjectTemplate{Ref: &corev1.ObjectReference{
+								APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+								Kind:       "InfrastructureMachinePoolTemplate",
+							}},
+						},
+					},
+				}
+				return clusterClass
+			}(),
+		},
+		{
+			name: "pass if selector targets an existing MachinePoolClass InfrastructureMachinePoolTemplate",
+			selector: clusterv1.PatchSelector{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "InfrastructureMachinePoolTemplate",
+				MatchResources: clusterv1.PatchSelectorMatch{
+					MachinePoolClass: &clusterv1.PatchSelectorMatchMachinePoolClass{
+						Names: []string{"aa"},
+					},
+				},
+			},
+			clusterClass: func() *clusterv1.ClusterClass {
+				clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+				clusterClass.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{
+					{
+						Class: "aa",
+						Template: clusterv1.MachinePoolClassTemplate{
+							Bootstrap: clusterv1.LocalObjectTemplate{Ref: &corev1.ObjectReference{
+								APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+								Kind:       "BootstrapConfigTemplate",
+							}},
+							Infrastructure: clusterv1.LocalObjectTemplate{Ref: &corev1.ObjectReference{
+								APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+								Kind:       "InfrastructureMachinePoolTemplate",
+							}},
+						},
+					},
+				}
+				return clusterClass
+			}(),
+		},
+		{
+			name: "error if selector targets a non-existing MachinePoolClass",
+			selector: clusterv1.PatchSelector{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "InfrastructureMachinePoolTemplate",
+				MatchResources: clusterv1.PatchSelectorMatch{
+					MachinePoolClass: &clusterv1.PatchSelectorMatchMachinePoolClass{
+						Names: []string{"bb"},
+					},
+				},
+			},
+			clusterClass: func() *clusterv1.ClusterClass {
+				clusterClass := builder.ClusterClass(metav1.NamespaceDefault, "class1").Build()
+				clusterClass.Spec.Workers.MachinePools = []clusterv1.MachinePoolClass{
+					{
+						Class: "aa",
+						Template: clusterv1.MachinePoolClassTemplate{
+							Bootstrap: clusterv1.LocalObjectTemplate{Ref: &corev1.ObjectReference{
+								APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
+								Kind:       "BootstrapConfigTemplate",
+							}},
+							Infrastructure: clusterv1.LocalObjectTemplate{Ref: &corev1.ObjectReference{
+								APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+								Kind:       "InfrastructureMachinePoolTemplate",
+							}},
+						},
+					},
+				}
+				return clusterClass
+			}(),
+			wantErr: true,
+		},
 		{
 			name: "fail if selector targets ControlPlane Machine Infrastructure but does not have MatchResources.ControlPlane enabled",
 			selector: clusterv1.PatchSelector{
@@ -20,7 +20,9 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -48,7 +50,7 @@ func TestClusterClassWebhook_Succeed_Create(t *testing.T) {
 		WithWorkerMachineDeploymentClasses(
 			*builder.MachineDeploymentClass("md1").
 				WithInfrastructureTemplate(
-					builder.InfrastructureMachineTemplate(ns.Name, "OLD_INFRA").Build()).
+					builder.InfrastructureMachineTemplate(ns.Name, "old-infra").Build()).
 				WithBootstrapTemplate(
 					builder.BootstrapTemplate(ns.Name, "bootstrap1").Build()).
 				Build(),
@@ -66,7 +68,8 @@ func TestClusterClassWebhook_Succeed_Create(t *testing.T) {
 		g.Expect(env.Cleanup(ctx, ns))
 	})
 
-	// Create the ClusterClass in the API server. Expect no error.
+	// Create the ClusterClass and its templates in the API server. Expect no error.
+	createClusterClassTemplates(g, clusterClass1)
 	g.Expect(env.Create(ctx, clusterClass1)).To(Succeed())
 }
 
@@ -88,7 +91,7 @@ func TestClusterClassWebhook_Fail_Create(t *testing.T) {
 		WithWorkerMachineDeploymentClasses(
 			*builder.MachineDeploymentClass("md1").
 				WithInfrastructureTemplate(
-					builder.InfrastructureMachineTemplate(ns.Name, "OLD_INFRA").Build()).
+					builder.InfrastructureMachineTemplate(ns.Name, "old-infra").Build()).
 				WithBootstrapTemplate(
 					builder.BootstrapTemplate(ns.Name, "bootstrap1").Build()).
 				Build(),
@@ -130,7 +133,7 @@ func TestClusterWebhook_Succeed_Update(t *testing.T) {
 		WithWorkerMachineDeploymentClasses(
 			*builder.MachineDeploymentClass("NOT_USED").
 				WithInfrastructureTemplate(
-					builder.InfrastructureMachineTemplate(ns.Name, "OLD_INFRA").Build()).
+					builder.InfrastructureMachineTemplate(ns.Name, "old-infra").Build()).
 				WithBootstrapTemplate(
 					builder.BootstrapTemplate(ns.Name, "bootstrap1").Build()).
 				Build(),
@@ -156,7 +159,8 @@ func TestClusterWebhook_Succeed_Update(t *testing.T) {
 				Build()).
 		Build()
 
-	// Create the ClusterClass in the API server.
+	// Create the ClusterClass and its templates in the API server.
+	createClusterClassTemplates(g, clusterClass)
 	g.Expect(env.CreateAndWait(ctx, clusterClass)).To(Succeed())
 
 	// Create a Cluster using the ClusterClass.
@@ -178,7 +182,8 @@ func TestClusterWebhook_Succeed_Update(t *testing.T) {
 		actualCluster.Spec.Workers.MachineDeployments[1],
 	}
 	// Change the template used in the ClusterClass to a compatible alternative (Only name is changed).
-	actualCluster.Spec.Infrastructure.Ref.Name = "NEW_INFRA"
+	g.Expect(env.Create(ctx, builder.InfrastructureClusterTemplate(ns.Name, "new-infra").Build())).To(Succeed())
+	actualCluster.Spec.Infrastructure.Ref.Name = "new-infra"
 
 	// Attempt to update the ClusterClass with the above changes.
 	// Expect no error here as the updates are compatible with the current Clusters using the ClusterClass.
@@ -232,7 +237,8 @@ func TestClusterWebhook_Fail_Update(t *testing.T) {
 				Build()).
 		Build()
 
-	// Create the ClusterClass in the API server.
+	// Create the ClusterClass and its templates in the API server.
+	createClusterClassTemplates(g, clusterClass)
 	g.Expect(env.CreateAndWait(ctx, clusterClass)).To(Succeed())
 
 	// Create a cluster using the ClusterClass.
@@ -280,7 +286,7 @@ func TestClusterClassWebhook_Delete(t *testing.T) {
 		WithWorkerMachineDeploymentClasses(
 			*builder.MachineDeploymentClass("md1").
 				WithInfrastructureTemplate(
-					builder.InfrastructureMachineTemplate(ns.Name, "OLD_INFRA").Build()).
+					builder.InfrastructureMachineTemplate(ns.Name, "old-infra").Build()).
 				WithBootstrapTemplate(
 					builder.BootstrapTemplate(ns.Name, "bootstrap1").Build()).
 				Build(),
@@ -301,7 +307,7 @@ func TestClusterClassWebhook_Delete(t *testing.T) {
 		WithWorkerMachineDeploymentClasses(
 			*builder.MachineDeploymentClass("md1").
 				WithInfrastructureTemplate(
-					builder.InfrastructureMachineTemplate(ns.Name, "OLD_INFRA").Build()).
+					builder.InfrastructureMachineTemplate(ns.Name, "old-infra").Build()).
 				WithBootstrapTemplate(
 					builder.BootstrapTemplate(ns.Name, "bootstrap1").Build()).
 				Build(),
@@ -327,7 +333,9 @@ func TestClusterClassWebhook_Delete(t *testing.T) {
 				Build()).
 		Build()
 
-	// Create the ClusterClasses in the API server.
+	// Create the ClusterClasses and their templates in the API server.
+	createClusterClassTemplates(g, clusterClass1)
+	createClusterClassTemplates(g, clusterClass2)
 	g.Expect(env.CreateAndWait(ctx, clusterClass1)).To(Succeed())
 	g.Expect(env.CreateAndWait(ctx, clusterClass2)).To(Succeed())
 
@@ -432,8 +440,9 @@ func TestClusterClassWebhook_Delete_MultipleExistingClusters(t *testing.T) {
 			Build(),
 	}
 	var classForDeletion string
-	// Create the ClusterClasses in the API server.
+	// Create the ClusterClasses and their templates in the API server.
 	for _, class := range clusterClasses {
+		createClusterClassTemplates(g, class.(*clusterv1.ClusterClass))
 		g.Expect(env.CreateAndWait(ctx, class)).To(Succeed())
 	}
 
@@ -483,3 +492,29 @@ func TestClusterClassWebhook_Delete_MultipleExistingClusters(t *testing.T) {
 	// Expect an error here as the webhook should not allow the deletion of an existing ClusterClass.
 	g.Expect(env.Delete(ctx, class)).To(Not(Succeed()))
 }
+
+// createClusterClassTemplates creates the templates referenced by a ClusterClass, which must exist
+// when the ClusterClass is created.
+func createClusterClassTemplates(g *WithT, clusterClass *clusterv1.ClusterClass) {
+	refs := []*corev1.ObjectReference{clusterClass.Spec.Infrastructure.Ref, clusterClass.Spec.ControlPlane.Ref}
+	if clusterClass.Spec.ControlPlane.MachineInfrastructure != nil {
+		refs = append(refs, clusterClass.Spec.ControlPlane.MachineInfrastructure.Ref)
+	}
+	for _, md := range clusterClass.Spec.Workers.MachineDeployments {
+		refs = append(refs, md.Template.Bootstrap.Ref, md.Template.Infrastructure.Ref)
+	}
+
+	for _, ref := range refs {
+		if ref == nil {
+			continue
+		}
+		template := &unstructured.Unstructured{}
+		template.SetAPIVersion(ref.APIVersion)
+		template.SetKind(ref.Kind)
+		template.SetNamespace(ref.Namespace)
+		template.SetName(ref.Name)
+		if err := env.Create(ctx, template); err != nil && !apierrors.IsAlreadyExists(err) {
+			g.Expect(err).ToNot(HaveOccurred())
+		}
+	}
+}
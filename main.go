@@ -48,6 +48,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/api/v1beta1/index"
 	"sigs.k8s.io/cluster-api/controllers"
+	"sigs.k8s.io/cluster-api/controllers/readiness"
 	"sigs.k8s.io/cluster-api/controllers/remote"
 	addonsv1alpha3 "sigs.k8s.io/cluster-api/exp/addons/api/v1alpha3"
 	addonsv1alpha4 "sigs.k8s.io/cluster-api/exp/addons/api/v1alpha4"
@@ -78,30 +79,45 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 
 	// flags.
-	metricsBindAddr               string
-	enableLeaderElection          bool
-	leaderElectionLeaseDuration   time.Duration
-	leaderElectionRenewDeadline   time.Duration
-	leaderElectionRetryPeriod     time.Duration
-	watchNamespace                string
-	watchFilterValue              string
-	profilerAddress               string
-	clusterTopologyConcurrency    int
-	clusterClassConcurrency       int
-	clusterConcurrency            int
-	extensionConfigConcurrency    int
-	machineConcurrency            int
-	machineSetConcurrency         int
-	machineDeploymentConcurrency  int
-	machinePoolConcurrency        int
-	clusterResourceSetConcurrency int
-	machineHealthCheckConcurrency int
-	syncPeriod                    time.Duration
-	webhookPort                   int
-	webhookCertDir                string
-	healthAddr                    string
-	tlsOptions                    = flags.TLSOptions{}
-	logOptions                    = logs.NewOptions()
+	metricsBindAddr                 string
+	enableLeaderElection            bool
+	leaderElectionLeaseDuration     time.Duration
+	leaderElectionRenewDeadline     time.Duration
+	leaderElectionRetryPeriod       time.Duration
+	watchNamespace                  string
+	watchFilterValue                string
+	profilerAddress                 string
+	clusterTopologyConcurrency      int
+	clusterTopologyApplyConcurrency int
+	clusterTopologyTemplateReaderSA string
+	clusterTopologySyncPeriod       time.Duration
+	clusterTopologyDriftInterval    time.Duration
+	clusterTopologyGCInterval       time.Duration
+	clusterTopologyGCGracePeriod    time.Duration
+	clusterClassConcurrency         int
+	clusterConcurrency              int
+	clusterSyncPeriod               time.Duration
+	clusterCircuitBreakerFailures   int
+	clusterCircuitBreakerInterval   time.Duration
+	extensionConfigConcurrency      int
+	machineConcurrency              int
+	machineSyncPeriod               time.Duration
+	machineDeletionEvents           bool
+	machineCrossNamespaceRefs       []string
+	machineReadinessWebhookURL      string
+	machineReadinessWebhookTimeout  time.Duration
+	machineSetConcurrency           int
+	machineDeploymentConcurrency    int
+	machinePoolConcurrency          int
+	clusterSummaryConcurrency       int
+	clusterResourceSetConcurrency   int
+	machineHealthCheckConcurrency   int
+	syncPeriod                      time.Duration
+	webhookPort                     int
+	webhookCertDir                  string
+	healthAddr                      string
+	tlsOptions                      = flags.TLSOptions{}
+	logOptions                      = logs.NewOptions()
 )
 
 func init() {
@@ -161,18 +177,60 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterTopologyConcurrency, "clustertopology-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.IntVar(&clusterTopologyApplyConcurrency, "clustertopology-apply-concurrency", 5,
+		"Number of objects generated from the topology of a Cluster, e.g. MachineDeployments, to reconcile simultaneously")
+
+	fs.StringVar(&clusterTopologyTemplateReaderSA, "clustertopology-template-reader-service-account", "",
+		"Name of the ServiceAccount, in the namespace of each Cluster, impersonated when reading the templates referenced by its ClusterClass. If empty, templates are read with the permissions of the controller.")
+
+	fs.DurationVar(&clusterTopologySyncPeriod, "clustertopology-sync-period", 0,
+		"The interval at which Clusters with a managed topology are periodically reconciled (e.g. 30m). If zero, --sync-period is used.")
+
+	fs.DurationVar(&clusterTopologyDriftInterval, "clustertopology-drift-check-interval", 10*time.Minute,
+		"Interval between periodic checks for out-of-band changes to the objects generated from the topology of Clusters with the topology.cluster.x-k8s.io/drift-policy annotation. If zero, objects are checked only when reconciled.")
+
+	fs.DurationVar(&clusterTopologyGCInterval, "clustertopology-gc-interval", 0,
+		"Interval between periodic checks for orphaned objects generated from Cluster topologies, e.g. templates left behind by failed template rotations. If zero (default), orphaned objects are not deleted.")
+
+	fs.DurationVar(&clusterTopologyGCGracePeriod, "clustertopology-gc-grace-period", 10*time.Minute,
+		"Minimum age of an orphaned object generated from a Cluster topology before it is deleted.")
+
 	fs.IntVar(&clusterClassConcurrency, "clusterclass-concurrency", 10,
 		"Number of ClusterClasses to process simultaneously")
 
 	fs.IntVar(&clusterConcurrency, "cluster-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.DurationVar(&clusterSyncPeriod, "cluster-sync-period", 0,
+		"The interval at which Clusters are periodically reconciled (e.g. 15m). If zero, --sync-period is used.")
+
+	fs.IntVar(&clusterCircuitBreakerFailures, "cluster-circuit-breaker-failures", 0,
+		"Number of consecutive reconcile failures after which a cluster is reconciled only every cluster-circuit-breaker-interval, until it is reconciled successfully or its spec is changed. If zero, clusters failing reconciliation are always retried with exponential backoff.")
+
+	fs.DurationVar(&clusterCircuitBreakerInterval, "cluster-circuit-breaker-interval", 10*time.Minute,
+		"Interval at which clusters are reconciled after failing reconciliation cluster-circuit-breaker-failures consecutive times.")
+
 	fs.IntVar(&extensionConfigConcurrency, "extensionconfig-concurrency", 10,
 		"Number of extension configs to process simultaneously")
 
 	fs.IntVar(&machineConcurrency, "machine-concurrency", 10,
 		"Number of machines to process simultaneously")
 
+	fs.DurationVar(&machineSyncPeriod, "machine-sync-period", 0,
+		"The interval at which Machines are periodically reconciled (e.g. 5m). If zero, --sync-period is used.")
+
+	fs.BoolVar(&machineDeletionEvents, "machine-deletion-events", false,
+		"If true, a MachineDeleted event with the deletion cause is recorded for each deleted Machine; events are not stored durably, and they are retained only for the event TTL of the kube-apiserver")
+
+	fs.StringSliceVar(&machineCrossNamespaceRefs, "machine-cross-namespace-refs-allow-list", nil,
+		"Comma separated list of <Machine namespace>=<referenced namespace> pairs, e.g. tenant-a=platform-infra, defining the namespaces Machines can reference bootstrap configs and infrastructure machines in when the MachineCrossNamespaceRefs feature flag is enabled; all other cross-namespace references are rejected")
+
+	fs.StringVar(&machineReadinessWebhookURL, "machine-infrastructure-readiness-webhook-url", "",
+		"If set, a Machine is marked as InfrastructureReady only after the webhook at this URL reports the infrastructure of the Machine is ready, in addition to the infrastructure provider and the compiled-in readiness checkers")
+
+	fs.DurationVar(&machineReadinessWebhookTimeout, "machine-infrastructure-readiness-webhook-timeout", 10*time.Second,
+		"Timeout for calls to the machine-infrastructure-readiness-webhook-url")
+
 	fs.IntVar(&machineSetConcurrency, "machineset-concurrency", 10,
 		"Number of machine sets to process simultaneously")
 
@@ -182,6 +240,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&machinePoolConcurrency, "machinepool-concurrency", 10,
 		"Number of machine pools to process simultaneously")
 
+	fs.IntVar(&clusterSummaryConcurrency, "clustersummary-concurrency", 10,
+		"Number of cluster summaries to process simultaneously")
+
 	fs.IntVar(&clusterResourceSetConcurrency, "clusterresourceset-concurrency", 10,
 		"Number of cluster resource sets to process simultaneously")
 
@@ -371,16 +432,33 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 
 		if err := (&controllers.ClusterTopologyReconciler{
-			Client:                    mgr.GetClient(),
-			APIReader:                 mgr.GetAPIReader(),
-			RuntimeClient:             runtimeClient,
-			UnstructuredCachingClient: unstructuredCachingClient,
-			WatchFilterValue:          watchFilterValue,
+			Client:                       mgr.GetClient(),
+			APIReader:                    mgr.GetAPIReader(),
+			RuntimeClient:                runtimeClient,
+			UnstructuredCachingClient:    unstructuredCachingClient,
+			WatchFilterValue:             watchFilterValue,
+			SyncPeriod:                   clusterTopologySyncPeriod,
+			DriftCheckInterval:           clusterTopologyDriftInterval,
+			MaxConcurrentApplies:         clusterTopologyApplyConcurrency,
+			TemplateReaderServiceAccount: clusterTopologyTemplateReaderSA,
 		}).SetupWithManager(ctx, mgr, concurrency(clusterTopologyConcurrency)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ClusterTopology")
 			os.Exit(1)
 		}
 
+		if clusterTopologyGCInterval > 0 {
+			if err := (&controllers.TopologyGarbageCollectorReconciler{
+				Client:                    mgr.GetClient(),
+				UnstructuredCachingClient: unstructuredCachingClient,
+				WatchFilterValue:          watchFilterValue,
+				Interval:                  clusterTopologyGCInterval,
+				GracePeriod:               clusterTopologyGCGracePeriod,
+			}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "TopologyGarbageCollector")
+				os.Exit(1)
+			}
+		}
+
 		if err := (&controllers.MachineDeploymentTopologyReconciler{
 			Client:           mgr.GetClient(),
 			APIReader:        mgr.GetAPIReader(),
@@ -398,6 +476,16 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 			setupLog.Error(err, "unable to create controller", "controller", "MachineSetTopology")
 			os.Exit(1)
 		}
+
+		if feature.Gates.Enabled(feature.ClusterUpgradeRollout) {
+			if err := (&expcontrollers.ClusterUpgradeRolloutReconciler{
+				Client:           mgr.GetClient(),
+				WatchFilterValue: watchFilterValue,
+			}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "ClusterUpgradeRollout")
+				os.Exit(1)
+			}
+		}
 	}
 
 	if feature.Gates.Enabled(feature.RuntimeSDK) {
@@ -413,18 +501,31 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 	}
 
 	if err := (&controllers.ClusterReconciler{
-		Client:           mgr.GetClient(),
-		APIReader:        mgr.GetAPIReader(),
-		WatchFilterValue: watchFilterValue,
+		Client:                     mgr.GetClient(),
+		APIReader:                  mgr.GetAPIReader(),
+		WatchFilterValue:           watchFilterValue,
+		SyncPeriod:                 clusterSyncPeriod,
+		CircuitBreakerThreshold:    clusterCircuitBreakerFailures,
+		CircuitBreakerRequeueAfter: clusterCircuitBreakerInterval,
+		Tracker:                    tracker,
 	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
+	infrastructureReadinessCheckers := readiness.DefaultRegistry.Checkers()
+	if machineReadinessWebhookURL != "" {
+		infrastructureReadinessCheckers = append(infrastructureReadinessCheckers,
+			readiness.NewWebhookChecker("webhook", machineReadinessWebhookURL, machineReadinessWebhookTimeout))
+	}
 	if err := (&controllers.MachineReconciler{
-		Client:           mgr.GetClient(),
-		APIReader:        mgr.GetAPIReader(),
-		Tracker:          tracker,
-		WatchFilterValue: watchFilterValue,
+		Client:                          mgr.GetClient(),
+		APIReader:                       mgr.GetAPIReader(),
+		Tracker:                         tracker,
+		WatchFilterValue:                watchFilterValue,
+		SyncPeriod:                      machineSyncPeriod,
+		RecordDeletionEvents:            machineDeletionEvents,
+		InfrastructureReadinessCheckers: infrastructureReadinessCheckers,
+		CrossNamespaceRefsAllowList:     machineCrossNamespaceRefs,
 	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
@@ -457,6 +558,16 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 	}
 
+	if feature.Gates.Enabled(feature.ClusterSummary) {
+		if err := (&expcontrollers.ClusterSummaryReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(clusterSummaryConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterSummary")
+			os.Exit(1)
+		}
+	}
+
 	if feature.Gates.Enabled(feature.ClusterResourceSet) {
 		if err := (&addonscontrollers.ClusterResourceSetReconciler{
 			Client:           mgr.GetClient(),
@@ -468,6 +579,7 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		}
 		if err := (&addonscontrollers.ClusterResourceSetBindingReconciler{
 			Client:           mgr.GetClient(),
+			Tracker:          tracker,
 			WatchFilterValue: watchFilterValue,
 		}).SetupWithManager(ctx, mgr, concurrency(clusterResourceSetConcurrency)); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ClusterResourceSetBinding")
@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package addresses implements utility functions for Machine addresses, including dual-stack ordering rules.
+package addresses
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// typeOrder is the order of the address types in normalized addresses; types not in the list come last.
+var typeOrder = []clusterv1.MachineAddressType{
+	clusterv1.MachineInternalIP,
+	clusterv1.MachineExternalIP,
+	clusterv1.MachineInternalDNS,
+	clusterv1.MachineExternalDNS,
+	clusterv1.MachineHostName,
+}
+
+// Normalize returns the addresses with the following rules applied, so addresses are reported consistently
+// regardless of the infrastructure provider:
+//   - empty addresses are dropped, IP addresses are converted to their canonical form, e.g. IPv6 addresses
+//     are lowercase and compressed, and duplicated addresses are dropped.
+//   - addresses are ordered by type: InternalIP, ExternalIP, InternalDNS, ExternalDNS, Hostname, other types.
+//   - IP addresses of the primary IP family, i.e. the family of the first IP address reported, come first
+//     within each type, so dual-stack addresses are ordered consistently across types.
+//
+// The order of addresses with the same type and IP family is preserved.
+func Normalize(addresses []clusterv1.MachineAddress) clusterv1.MachineAddresses {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	normalized := clusterv1.MachineAddresses{}
+	seen := map[clusterv1.MachineAddress]bool{}
+	for _, a := range addresses {
+		a.Address = strings.TrimSpace(a.Address)
+		if a.Address == "" {
+			continue
+		}
+		if ip := net.ParseIP(a.Address); ip != nil {
+			a.Address = ip.String()
+		}
+		if seen[a] {
+			continue
+		}
+		seen[a] = true
+		normalized = append(normalized, a)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	primary, hasPrimary := primaryIPFamily(normalized)
+	rank := func(a clusterv1.MachineAddress) (int, int) {
+		typeRank := len(typeOrder)
+		for i, t := range typeOrder {
+			if a.Type == t {
+				typeRank = i
+				break
+			}
+		}
+		familyRank := 0
+		if family, ok := IPFamily(a.Address); ok && hasPrimary && family != primary {
+			familyRank = 1
+		}
+		return typeRank, familyRank
+	}
+	sort.SliceStable(normalized, func(i, j int) bool {
+		iType, iFamily := rank(normalized[i])
+		jType, jFamily := rank(normalized[j])
+		if iType != jType {
+			return iType < jType
+		}
+		return iFamily < jFamily
+	})
+	return normalized
+}
+
+// Merge returns the normalized addresses of many Machines; the primary IP family is the family of the first
+// IP address of the first Machine with IP addresses.
+func Merge(addresses ...[]clusterv1.MachineAddress) clusterv1.MachineAddresses {
+	all := []clusterv1.MachineAddress{}
+	for _, a := range addresses {
+		all = append(all, a...)
+	}
+	return Normalize(all)
+}
+
+// IPFamily returns the IP family of an address; false is returned if the address is not an IP address.
+func IPFamily(address string) (clusterv1.AddressIPFamily, bool) {
+	ip := net.ParseIP(strings.TrimSpace(address))
+	switch {
+	case ip == nil:
+		return "", false
+	case ip.To4() != nil:
+		return clusterv1.IPv4AddressFamily, true
+	default:
+		return clusterv1.IPv6AddressFamily, true
+	}
+}
+
+// IPFamilies returns the IP families of the addresses, with the primary IP family first.
+func IPFamilies(addresses []clusterv1.MachineAddress) []clusterv1.AddressIPFamily {
+	var families []clusterv1.AddressIPFamily
+	for _, a := range addresses {
+		family, ok := IPFamily(a.Address)
+		if !ok || containsFamily(families, family) {
+			continue
+		}
+		families = append(families, family)
+	}
+	return families
+}
+
+// IsDualStack returns true if the addresses include both IPv4 and IPv6 addresses.
+func IsDualStack(addresses []clusterv1.MachineAddress) bool {
+	return len(IPFamilies(addresses)) == 2
+}
+
+// Filter returns the addresses with one of the given types, preserving their order.
+func Filter(addresses []clusterv1.MachineAddress, types ...clusterv1.MachineAddressType) clusterv1.MachineAddresses {
+	filtered := clusterv1.MachineAddresses{}
+	for _, a := range addresses {
+		for _, t := range types {
+			if a.Type == t {
+				filtered = append(filtered, a)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// IPs returns the IP addresses of the given IP family, preserving their order and without duplicates.
+func IPs(addresses []clusterv1.MachineAddress, family clusterv1.AddressIPFamily) []string {
+	var ips []string
+	seen := map[string]bool{}
+	for _, a := range addresses {
+		if f, ok := IPFamily(a.Address); !ok || f != family {
+			continue
+		}
+		ip := net.ParseIP(strings.TrimSpace(a.Address)).String()
+		if seen[ip] {
+			continue
+		}
+		seen[ip] = true
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+// primaryIPFamily returns the IP family of the first IP address; false is returned if there are no IP addresses.
+func primaryIPFamily(addresses []clusterv1.MachineAddress) (clusterv1.AddressIPFamily, bool) {
+	for _, a := range addresses {
+		if family, ok := IPFamily(a.Address); ok {
+			return family, true
+		}
+	}
+	return "", false
+}
+
+func containsFamily(families []clusterv1.AddressIPFamily, family clusterv1.AddressIPFamily) bool {
+	for _, f := range families {
+		if f == family {
+			return true
+		}
+	}
+	return false
+}
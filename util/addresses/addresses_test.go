@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addresses
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []clusterv1.MachineAddress
+		want      clusterv1.MachineAddresses
+	}{
+		{
+			name: "No addresses",
+			want: nil,
+		},
+		{
+			name: "Drops empty and duplicated addresses",
+			addresses: []clusterv1.MachineAddress{
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+				{Type: clusterv1.MachineInternalIP, Address: ""},
+				{Type: clusterv1.MachineInternalIP, Address: " 10.0.0.1 "},
+				{Type: clusterv1.MachineExternalIP, Address: "10.0.0.1"},
+			},
+			want: clusterv1.MachineAddresses{
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+				{Type: clusterv1.MachineExternalIP, Address: "10.0.0.1"},
+			},
+		},
+		{
+			name: "Converts IP addresses to their canonical form",
+			addresses: []clusterv1.MachineAddress{
+				{Type: clusterv1.MachineInternalIP, Address: "FD00:0:0:0::1"},
+				{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+			},
+			want: clusterv1.MachineAddresses{
+				{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+			},
+		},
+		{
+			name: "Orders addresses by type",
+			addresses: []clusterv1.MachineAddress{
+				{Type: clusterv1.MachineHostName, Address: "machine1"},
+				{Type: "Custom", Address: "custom"},
+				{Type: clusterv1.MachineExternalDNS, Address: "machine1.example.com"},
+				{Type: clusterv1.MachineExternalIP, Address: "192.168.0.1"},
+				{Type: clusterv1.MachineInternalDNS, Address: "machine1.internal"},
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+			},
+			want: clusterv1.MachineAddresses{
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+				{Type: clusterv1.MachineExternalIP, Address: "192.168.0.1"},
+				{Type: clusterv1.MachineInternalDNS, Address: "machine1.internal"},
+				{Type: clusterv1.MachineExternalDNS, Address: "machine1.example.com"},
+				{Type: clusterv1.MachineHostName, Address: "machine1"},
+				{Type: "Custom", Address: "custom"},
+			},
+		},
+		{
+			name: "Orders dual-stack addresses with the primary IP family first",
+			addresses: []clusterv1.MachineAddress{
+				{Type: clusterv1.MachineExternalIP, Address: "2001:db8::1"},
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+				{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+				{Type: clusterv1.MachineExternalIP, Address: "192.168.0.1"},
+				{Type: clusterv1.MachineInternalIP, Address: "fd00::2"},
+			},
+			want: clusterv1.MachineAddresses{
+				{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+				{Type: clusterv1.MachineInternalIP, Address: "fd00::2"},
+				{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+				{Type: clusterv1.MachineExternalIP, Address: "2001:db8::1"},
+				{Type: clusterv1.MachineExternalIP, Address: "192.168.0.1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(Normalize(tt.addresses)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	g := NewWithT(t)
+
+	got := Merge(
+		[]clusterv1.MachineAddress{
+			{Type: clusterv1.MachineHostName, Address: "machine1"},
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+			{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+		},
+		[]clusterv1.MachineAddress{
+			{Type: clusterv1.MachineInternalIP, Address: "fd00::2"},
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"},
+			{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		},
+	)
+	g.Expect(got).To(Equal(clusterv1.MachineAddresses{
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"},
+		{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+		{Type: clusterv1.MachineInternalIP, Address: "fd00::2"},
+		{Type: clusterv1.MachineHostName, Address: "machine1"},
+	}))
+}
+
+func TestIPFamilies(t *testing.T) {
+	g := NewWithT(t)
+
+	ipv4 := []clusterv1.MachineAddress{
+		{Type: clusterv1.MachineHostName, Address: "machine1"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+	}
+	g.Expect(IPFamilies(ipv4)).To(Equal([]clusterv1.AddressIPFamily{clusterv1.IPv4AddressFamily}))
+	g.Expect(IsDualStack(ipv4)).To(BeFalse())
+
+	dualStack := []clusterv1.MachineAddress{
+		{Type: clusterv1.MachineInternalIP, Address: "fd00::1"},
+		{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+		{Type: clusterv1.MachineExternalIP, Address: "2001:db8::1"},
+	}
+	g.Expect(IPFamilies(dualStack)).To(Equal([]clusterv1.AddressIPFamily{clusterv1.IPv6AddressFamily, clusterv1.IPv4AddressFamily}))
+	g.Expect(IsDualStack(dualStack)).To(BeTrue())
+
+	g.Expect(IPFamilies([]clusterv1.MachineAddress{{Type: clusterv1.MachineHostName, Address: "machine1"}})).To(BeEmpty())
+
+	g.Expect(IPs(dualStack, clusterv1.IPv6AddressFamily)).To(Equal([]string{"fd00::1", "2001:db8::1"}))
+	g.Expect(IPs(dualStack, clusterv1.IPv4AddressFamily)).To(Equal([]string{"10.0.0.1"}))
+	g.Expect(Filter(dualStack, clusterv1.MachineExternalIP)).To(Equal(clusterv1.MachineAddresses{{Type: clusterv1.MachineExternalIP, Address: "2001:db8::1"}}))
+}
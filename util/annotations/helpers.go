@@ -48,6 +48,11 @@ func HasSkipRemediation(o metav1.Object) bool {
 	return hasAnnotation(o, clusterv1.MachineSkipRemediationAnnotation)
 }
 
+// HasRebootRequested returns true if the object has the `reboot-requested` annotation.
+func HasRebootRequested(o metav1.Object) bool {
+	return hasAnnotation(o, clusterv1.MachineRebootRequestedAnnotation)
+}
+
 // HasWithPrefix returns true if at least one of the annotations has the prefix specified.
 func HasWithPrefix(prefix string, annotations map[string]string) bool {
 	for key := range annotations {
@@ -71,7 +76,6 @@ func AddAnnotations(o metav1.Object, desired map[string]string) bool {
 	annotations := o.GetAnnotations()
 	if annotations == nil {
 		annotations = make(map[string]string)
-		o.SetAnnotations(annotations)
 	}
 	hasChanged := false
 	for k, v := range desired {
@@ -80,6 +84,8 @@ func AddAnnotations(o metav1.Object, desired map[string]string) bool {
 			hasChanged = true
 		}
 	}
+	// NOTE: Annotations must be set back, because some objects, e.g. Unstructured, return a copy of the annotations.
+	o.SetAnnotations(annotations)
 	return hasChanged
 }
 
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestAddAnnotations(t *testing.T) {
@@ -141,6 +142,24 @@ func TestAddAnnotations(t *testing.T) {
 			},
 			changed: true,
 		},
+		{
+			name: "should return true if annotations are added to unstructured objects",
+			obj: &unstructured.Unstructured{Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]interface{}{
+						"foo": "bar",
+					},
+				},
+			}},
+			input: map[string]string{
+				"thing1": "thing2",
+			},
+			expected: map[string]string{
+				"foo":    "bar",
+				"thing1": "thing2",
+			},
+			changed: true,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apply implements utils for applying objects to a cluster, e.g. a workload cluster, and for
+// assessing the health of the applied objects.
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	utilresource "sigs.k8s.io/cluster-api/util/resource"
+)
+
+// Options defines the options for Apply.
+type Options struct {
+	// FieldManager is the field manager used when applying objects using server side apply.
+	FieldManager string
+
+	// ForceOwnership forces the ownership of fields owned by other field managers; if false,
+	// objects with fields owned by other field managers are not applied and reported as conflicts.
+	ForceOwnership bool
+
+	// SkipExisting, if set, is called for objects already existing in the cluster; objects for which it
+	// returns true are not applied and are reported as skipped, e.g. to avoid taking over objects managed by someone else.
+	SkipExisting func(current *unstructured.Unstructured) bool
+}
+
+// Result reports the outcome of Apply.
+type Result struct {
+	// Applied lists the objects which have been applied, as returned by the API server.
+	Applied []unstructured.Unstructured
+
+	// Skipped lists the IDs of the objects which have been skipped, see Options.SkipExisting.
+	Skipped []string
+
+	// Conflicts lists the IDs of the objects which have not been applied because of conflicts with other
+	// field managers, together with the corresponding error message.
+	Conflicts []string
+}
+
+// Apply applies objects to a cluster using server side apply; objects are applied in the order defined by
+// util/resource.SortForCreate, so e.g. Namespaces and CRDs are applied before the objects depending on them.
+// Errors applying an object do not prevent the other objects to be applied; all the errors are returned
+// as an aggregate error, together with the result for the objects which have been processed.
+// NOTE: Apply does not wait for the objects to become healthy, use Wait for this.
+func Apply(ctx context.Context, c client.Client, objs []unstructured.Unstructured, options Options) (*Result, error) {
+	if options.FieldManager == "" {
+		return nil, errors.New("failed to apply objects: field manager must be set")
+	}
+
+	result := &Result{}
+	errList := []error{}
+	sortedObjs := utilresource.SortForCreate(objs)
+	for i := range sortedObjs {
+		if err := applyObject(ctx, c, sortedObjs[i].DeepCopy(), options, result); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return result, kerrors.NewAggregate(errList)
+}
+
+func applyObject(ctx context.Context, c client.Client, obj *unstructured.Unstructured, options Options, result *Result) error {
+	if options.SkipExisting != nil {
+		current := &unstructured.Unstructured{}
+		current.SetGroupVersionKind(obj.GroupVersionKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get object %s", ObjectID(obj))
+			}
+		} else if options.SkipExisting(current) {
+			result.Skipped = append(result.Skipped, ObjectID(obj))
+			return nil
+		}
+	}
+
+	patchOptions := []client.PatchOption{client.FieldOwner(options.FieldManager)}
+	if options.ForceOwnership {
+		patchOptions = append(patchOptions, client.ForceOwnership)
+	}
+	if err := c.Patch(ctx, obj, client.Apply, patchOptions...); err != nil {
+		if apierrors.IsConflict(err) {
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s: %s", ObjectID(obj), err.Error()))
+			return nil
+		}
+		return errors.Wrapf(err, "failed to apply object %s", ObjectID(obj))
+	}
+	result.Applied = append(result.Applied, *obj)
+	return nil
+}
+
+// ObjectID returns a string identifying an object, e.g. "apps/v1, Kind=Deployment kube-system/foo".
+func ObjectID(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", obj.GroupVersionKind(), klog.KObj(obj))
+}
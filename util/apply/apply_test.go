@@ -0,0 +1,245 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestApply(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault},
+	}
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs := []unstructured.Unstructured{{Object: u}}
+
+	t.Run("Fail if the field manager is not set", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		_, err := Apply(context.Background(), c, objs, Options{})
+		g.Expect(err).To(HaveOccurred())
+	})
+	t.Run("Skip existing objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+		result, err := Apply(context.Background(), c, objs, Options{
+			FieldManager: "test-manager",
+			SkipExisting: func(current *unstructured.Unstructured) bool {
+				return true
+			},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Applied).To(BeEmpty())
+		g.Expect(result.Skipped).To(ConsistOf("/v1, Kind=ConfigMap default/foo"))
+	})
+	t.Run("Apply objects", func(t *testing.T) {
+		g := NewWithT(t)
+
+		namespace := &corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: "bar"},
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(namespace)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		c := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme).Build()}
+
+		// The Namespace is applied first, even if defined after the ConfigMap.
+		result, err := Apply(context.Background(), c, append(objs, unstructured.Unstructured{Object: u}), Options{
+			FieldManager:   "test-manager",
+			ForceOwnership: true,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Applied).To(HaveLen(2))
+		g.Expect(result.Applied[0].GetKind()).To(Equal("Namespace"))
+		g.Expect(result.Applied[1].GetKind()).To(Equal("ConfigMap"))
+		g.Expect(result.Skipped).To(BeEmpty())
+		g.Expect(result.Conflicts).To(BeEmpty())
+		g.Expect(c.fieldManagers).To(ConsistOf("test-manager", "test-manager"))
+		g.Expect(c.forced).To(BeTrue())
+
+		g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(configMap), &corev1.ConfigMap{})).To(Succeed())
+		g.Expect(c.Get(context.Background(), client.ObjectKeyFromObject(namespace), &corev1.Namespace{})).To(Succeed())
+	})
+	t.Run("Report conflicts", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := &applyClient{
+			Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+			conflicts: map[string]bool{"foo": true},
+		}
+
+		result, err := Apply(context.Background(), c, objs, Options{FieldManager: "test-manager"})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Applied).To(BeEmpty())
+		g.Expect(result.Conflicts).To(HaveLen(1))
+		g.Expect(result.Conflicts[0]).To(HavePrefix("/v1, Kind=ConfigMap default/foo: "))
+		g.Expect(c.forced).To(BeFalse())
+	})
+	t.Run("Continue applying objects after an error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		other := objs[0].DeepCopy()
+		other.SetName("other")
+		c := &applyClient{
+			Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+			errors: map[string]bool{"foo": true},
+		}
+
+		result, err := Apply(context.Background(), c, []unstructured.Unstructured{objs[0], *other}, Options{FieldManager: "test-manager"})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("failed to apply object /v1, Kind=ConfigMap default/foo"))
+		g.Expect(result.Applied).To(HaveLen(1))
+		g.Expect(result.Applied[0].GetName()).To(Equal("other"))
+	})
+}
+
+func TestGetStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault},
+	}
+	deployment := func(availableReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(1)},
+			Status: appsv1.DeploymentStatus{
+				Replicas:          1,
+				UpdatedReplicas:   1,
+				ReadyReplicas:     availableReplicas,
+				AvailableReplicas: availableReplicas,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		obj        client.Object
+		wantStatus Status
+	}{
+		{
+			name:       "Current if the object exists and it has no status",
+			objs:       []client.Object{configMap},
+			obj:        configMap,
+			wantStatus: CurrentStatus,
+		},
+		{
+			name:       "NotFound if the object does not exist",
+			obj:        configMap,
+			wantStatus: NotFoundStatus,
+		},
+		{
+			name:       "Status computed from the object in the cluster, InProgress",
+			objs:       []client.Object{deployment(0)},
+			obj:        deployment(1),
+			wantStatus: InProgressStatus,
+		},
+		{
+			name:       "Status computed from the object in the cluster, Current",
+			objs:       []client.Object{deployment(1)},
+			obj:        deployment(0),
+			wantStatus: CurrentStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objs...).Build()
+			u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(tt.obj)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			status, err := GetStatus(context.Background(), c, &unstructured.Unstructured{Object: u})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(status.Status).To(Equal(tt.wantStatus))
+		})
+	}
+}
+
+// applyClient is a client emulating server side apply on top of the fake client, which does not support it;
+// objects are created or updated with the applied content, and conflicts or errors are returned for the given object names.
+type applyClient struct {
+	client.Client
+	conflicts     map[string]bool
+	errors        map[string]bool
+	fieldManagers []string
+	forced        bool
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+
+	patchOptions := &client.PatchOptions{}
+	patchOptions.ApplyOptions(opts)
+	c.fieldManagers = append(c.fieldManagers, patchOptions.FieldManager)
+	if patchOptions.Force != nil && *patchOptions.Force {
+		c.forced = true
+	}
+
+	gk := schema.GroupKind{Group: obj.GetObjectKind().GroupVersionKind().Group, Kind: obj.GetObjectKind().GroupVersionKind().Kind}
+	if c.conflicts[obj.GetName()] {
+		return apierrors.NewConflict(schema.GroupResource{Group: gk.Group, Resource: gk.Kind}, obj.GetName(), errors.New("conflict with \"kubectl\": .data.foo"))
+	}
+	if c.errors[obj.GetName()] {
+		return apierrors.NewInternalError(errors.New("failed to apply"))
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GetObjectKind().GroupVersionKind())
+	if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return c.Client.Create(ctx, obj)
+	}
+	obj.SetResourceVersion(current.GetResourceVersion())
+	return c.Client.Update(ctx, obj)
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Status is the health status of an object.
+// NOTE: Statuses and the rules for computing them are aligned with the kstatus library
+// (see https://github.com/kubernetes-sigs/cli-utils/tree/master/pkg/kstatus).
+type Status string
+
+const (
+	// InProgressStatus signals that the actual state of the object has not yet reached the desired state.
+	InProgressStatus Status = "InProgress"
+
+	// FailedStatus signals that the object failed to reach the desired state, and that a manual
+	// intervention is most probably required.
+	FailedStatus Status = "Failed"
+
+	// CurrentStatus signals that the actual state of the object matches the desired state.
+	CurrentStatus Status = "Current"
+
+	// TerminatingStatus signals that the object is being deleted.
+	TerminatingStatus Status = "Terminating"
+
+	// NotFoundStatus signals that the object does not exist.
+	NotFoundStatus Status = "NotFound"
+)
+
+// StatusResult is the health status of an object, together with a human readable message.
+type StatusResult struct {
+	Status  Status
+	Message string
+}
+
+// GetStatus reads an object from the cluster and computes its health status;
+// if the object does not exist, NotFoundStatus is returned.
+func GetStatus(ctx context.Context, c client.Reader, obj *unstructured.Unstructured) (*StatusResult, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &StatusResult{Status: NotFoundStatus, Message: "Object not found"}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get object %s", ObjectID(obj))
+	}
+	return ComputeStatus(current)
+}
+
+// ComputeStatus computes the health status of an object.
+// The status is computed using specific rules for well known Kubernetes kinds, e.g. Deployments or Pods,
+// while for all the other kinds, e.g. custom resources, status.observedGeneration and the Ready,
+// Reconciling and Stalled conditions are used, if defined.
+func ComputeStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	if obj.GetDeletionTimestamp() != nil {
+		return &StatusResult{Status: TerminatingStatus, Message: "Object scheduled for deletion"}, nil
+	}
+
+	// An object is in progress until the controller observes the latest generation.
+	observedGeneration, found, err := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get status.observedGeneration from %s", ObjectID(obj))
+	}
+	if found && observedGeneration != obj.GetGeneration() {
+		return inProgress("Generation %d is not yet observed, latest observed generation is %d", obj.GetGeneration(), observedGeneration), nil
+	}
+
+	var result *StatusResult
+	switch obj.GroupVersionKind().GroupKind() {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"}:
+		result, err = deploymentStatus(obj)
+	case schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+		result, err = statefulSetStatus(obj)
+	case schema.GroupKind{Group: "apps", Kind: "DaemonSet"}:
+		result, err = daemonSetStatus(obj)
+	case schema.GroupKind{Group: "apps", Kind: "ReplicaSet"}:
+		result, err = replicaSetStatus(obj)
+	case schema.GroupKind{Group: "batch", Kind: "Job"}:
+		result, err = jobStatus(obj)
+	case schema.GroupKind{Group: "", Kind: "Pod"}:
+		result, err = podStatus(obj)
+	case schema.GroupKind{Group: "", Kind: "PersistentVolumeClaim"}:
+		result, err = persistentVolumeClaimStatus(obj)
+	case schema.GroupKind{Group: "", Kind: "Service"}:
+		result, err = serviceStatus(obj)
+	case schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:
+		result, err = customResourceDefinitionStatus(obj)
+	default:
+		result, err = genericStatus(obj)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute status for %s", ObjectID(obj))
+	}
+	return result, nil
+}
+
+func deploymentStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	progressing, err := getCondition(obj, "Progressing")
+	if err != nil {
+		return nil, err
+	}
+	if progressing != nil && progressing.reason == "ProgressDeadlineExceeded" {
+		return failed("Progress deadline exceeded"), nil
+	}
+
+	fields, err := getInt64Fields(obj, "spec.replicas", "status.replicas", "status.updatedReplicas", "status.readyReplicas", "status.availableReplicas")
+	if err != nil {
+		return nil, err
+	}
+	replicas := specReplicas(obj, fields["spec.replicas"])
+	if fields["status.updatedReplicas"] < replicas {
+		return inProgress("Updated replicas: %d/%d", fields["status.updatedReplicas"], replicas), nil
+	}
+	if fields["status.replicas"] > fields["status.updatedReplicas"] {
+		return inProgress("Pending termination: %d", fields["status.replicas"]-fields["status.updatedReplicas"]), nil
+	}
+	if fields["status.availableReplicas"] < fields["status.updatedReplicas"] {
+		return inProgress("Available replicas: %d/%d", fields["status.availableReplicas"], fields["status.updatedReplicas"]), nil
+	}
+	if fields["status.readyReplicas"] < replicas {
+		return inProgress("Ready replicas: %d/%d", fields["status.readyReplicas"], replicas), nil
+	}
+	return current("Deployment is available, replicas: %d", replicas), nil
+}
+
+func statefulSetStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	fields, err := getInt64Fields(obj, "spec.replicas", "status.replicas", "status.readyReplicas", "status.currentReplicas", "status.updatedReplicas")
+	if err != nil {
+		return nil, err
+	}
+	replicas := specReplicas(obj, fields["spec.replicas"])
+	if fields["status.replicas"] < replicas {
+		return inProgress("Replicas: %d/%d", fields["status.replicas"], replicas), nil
+	}
+	if fields["status.readyReplicas"] < replicas {
+		return inProgress("Ready replicas: %d/%d", fields["status.readyReplicas"], replicas), nil
+	}
+
+	// With the RollingUpdate strategy and no partition, the rollout is completed when all the replicas are updated.
+	strategy, _, err := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if err != nil {
+		return nil, err
+	}
+	partition, _, err := unstructured.NestedInt64(obj.Object, "spec", "updateStrategy", "rollingUpdate", "partition")
+	if err != nil {
+		return nil, err
+	}
+	if (strategy == "" || strategy == "RollingUpdate") && partition == 0 {
+		currentRevision, _, err := unstructured.NestedString(obj.Object, "status", "currentRevision")
+		if err != nil {
+			return nil, err
+		}
+		updateRevision, _, err := unstructured.NestedString(obj.Object, "status", "updateRevision")
+		if err != nil {
+			return nil, err
+		}
+		if fields["status.updatedReplicas"] < replicas || currentRevision != updateRevision {
+			return inProgress("Updated replicas: %d/%d", fields["status.updatedReplicas"], replicas), nil
+		}
+	}
+	return current("All replicas are ready, replicas: %d", replicas), nil
+}
+
+func daemonSetStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	fields, err := getInt64Fields(obj, "status.desiredNumberScheduled", "status.currentNumberScheduled", "status.updatedNumberScheduled", "status.numberAvailable", "status.numberReady")
+	if err != nil {
+		return nil, err
+	}
+	desired := fields["status.desiredNumberScheduled"]
+	if fields["status.currentNumberScheduled"] < desired {
+		return inProgress("Scheduled pods: %d/%d", fields["status.currentNumberScheduled"], desired), nil
+	}
+	if fields["status.updatedNumberScheduled"] < desired {
+		return inProgress("Updated pods: %d/%d", fields["status.updatedNumberScheduled"], desired), nil
+	}
+	if fields["status.numberAvailable"] < desired {
+		return inProgress("Available pods: %d/%d", fields["status.numberAvailable"], desired), nil
+	}
+	if fields["status.numberReady"] < desired {
+		return inProgress("Ready pods: %d/%d", fields["status.numberReady"], desired), nil
+	}
+	return current("All pods are ready, pods: %d", desired), nil
+}
+
+func replicaSetStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	replicaFailure, err := getCondition(obj, "ReplicaFailure")
+	if err != nil {
+		return nil, err
+	}
+	if replicaFailure != nil && replicaFailure.status == "True" {
+		return failed("Replica failure: %s", replicaFailure.message), nil
+	}
+
+	fields, err := getInt64Fields(obj, "spec.replicas", "status.replicas", "status.readyReplicas", "status.availableReplicas")
+	if err != nil {
+		return nil, err
+	}
+	replicas := specReplicas(obj, fields["spec.replicas"])
+	if fields["status.replicas"] < replicas {
+		return inProgress("Replicas: %d/%d", fields["status.replicas"], replicas), nil
+	}
+	if fields["status.availableReplicas"] < replicas {
+		return inProgress("Available replicas: %d/%d", fields["status.availableReplicas"], replicas), nil
+	}
+	if fields["status.readyReplicas"] < replicas {
+		return inProgress("Ready replicas: %d/%d", fields["status.readyReplicas"], replicas), nil
+	}
+	return current("All replicas are ready, replicas: %d", replicas), nil
+}
+
+func jobStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	jobFailed, err := getCondition(obj, "Failed")
+	if err != nil {
+		return nil, err
+	}
+	if jobFailed != nil && jobFailed.status == "True" {
+		return failed("Job failed: %s", jobFailed.message), nil
+	}
+	complete, err := getCondition(obj, "Complete")
+	if err != nil {
+		return nil, err
+	}
+	if complete != nil && complete.status == "True" {
+		return current("Job completed"), nil
+	}
+	return inProgress("Job in progress"), nil
+}
+
+func podStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+	if err != nil {
+		return nil, err
+	}
+	switch phase {
+	case "Succeeded":
+		return current("Pod has completed successfully"), nil
+	case "Failed":
+		return failed("Pod has failed"), nil
+	case "Running":
+		ready, err := getCondition(obj, "Ready")
+		if err != nil {
+			return nil, err
+		}
+		if ready != nil && ready.status == "True" {
+			return current("Pod is ready"), nil
+		}
+		return inProgress("Pod is running but not ready"), nil
+	}
+	return inProgress("Pod phase is %q", phase), nil
+}
+
+func persistentVolumeClaimStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	phase, _, err := unstructured.NestedString(obj.Object, "status", "phase")
+	if err != nil {
+		return nil, err
+	}
+	if phase != "Bound" {
+		return inProgress("PersistentVolumeClaim is not bound"), nil
+	}
+	return current("PersistentVolumeClaim is bound"), nil
+}
+
+func serviceStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	serviceType, _, err := unstructured.NestedString(obj.Object, "spec", "type")
+	if err != nil {
+		return nil, err
+	}
+	if serviceType == "LoadBalancer" {
+		ingress, _, err := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+		if err != nil {
+			return nil, err
+		}
+		if len(ingress) == 0 {
+			return inProgress("LoadBalancer ingress is not yet assigned"), nil
+		}
+	}
+	return current("Service is ready"), nil
+}
+
+func customResourceDefinitionStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	namesAccepted, err := getCondition(obj, "NamesAccepted")
+	if err != nil {
+		return nil, err
+	}
+	if namesAccepted != nil && namesAccepted.status == "False" {
+		return failed("CustomResourceDefinition names not accepted: %s", namesAccepted.message), nil
+	}
+	established, err := getCondition(obj, "Established")
+	if err != nil {
+		return nil, err
+	}
+	if established == nil || established.status != "True" {
+		return inProgress("CustomResourceDefinition is not yet established"), nil
+	}
+	return current("CustomResourceDefinition is established"), nil
+}
+
+// genericStatus computes the status of objects using the kstatus standard conditions, i.e. Reconciling and Stalled,
+// and the Ready condition commonly used by Kubernetes and Cluster API objects.
+func genericStatus(obj *unstructured.Unstructured) (*StatusResult, error) {
+	stalled, err := getCondition(obj, "Stalled")
+	if err != nil {
+		return nil, err
+	}
+	if stalled != nil && stalled.status == "True" {
+		return failed("Object is stalled: %s", stalled.message), nil
+	}
+	reconciling, err := getCondition(obj, "Reconciling")
+	if err != nil {
+		return nil, err
+	}
+	if reconciling != nil && reconciling.status == "True" {
+		return inProgress("Object is reconciling: %s", reconciling.message), nil
+	}
+	ready, err := getCondition(obj, "Ready")
+	if err != nil {
+		return nil, err
+	}
+	if ready != nil && ready.status != "True" {
+		return inProgress("Object is not ready: %s", ready.message), nil
+	}
+	return current("Object is current"), nil
+}
+
+type condition struct {
+	status  string
+	reason  string
+	message string
+}
+
+// getCondition returns the condition with the given type from status.conditions, if any.
+func getCondition(obj *unstructured.Unstructured, conditionType string) (*condition, error) {
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _, _ := unstructured.NestedString(m, "type"); t != conditionType {
+			continue
+		}
+		status, _, _ := unstructured.NestedString(m, "status")
+		reason, _, _ := unstructured.NestedString(m, "reason")
+		message, _, _ := unstructured.NestedString(m, "message")
+		return &condition{status: status, reason: reason, message: message}, nil
+	}
+	return nil, nil
+}
+
+// getInt64Fields returns the values of the given int64 fields, identified by a dot separated path;
+// fields which are not set are returned as 0.
+func getInt64Fields(obj *unstructured.Unstructured, paths ...string) (map[string]int64, error) {
+	values := map[string]int64{}
+	for _, path := range paths {
+		value, _, err := unstructured.NestedInt64(obj.Object, strings.Split(path, ".")...)
+		if err != nil {
+			return nil, err
+		}
+		values[path] = value
+	}
+	return values, nil
+}
+
+// specReplicas returns the desired number of replicas, defaulting to 1 if spec.replicas is not set.
+func specReplicas(obj *unstructured.Unstructured, replicas int64) int64 {
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "replicas"); !found {
+		return 1
+	}
+	return replicas
+}
+
+func inProgress(format string, a ...interface{}) *StatusResult {
+	return &StatusResult{Status: InProgressStatus, Message: fmt.Sprintf(format, a...)}
+}
+
+func failed(format string, a ...interface{}) *StatusResult {
+	return &StatusResult{Status: FailedStatus, Message: fmt.Sprintf(format, a...)}
+}
+
+func current(format string, a ...interface{}) *StatusResult {
+	return &StatusResult{Status: CurrentStatus, Message: fmt.Sprintf(format, a...)}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestComputeStatus(t *testing.T) {
+	object := func(apiVersion, kind string, generation int64, spec, status map[string]interface{}) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":       "foo",
+				"namespace":  metav1.NamespaceDefault,
+				"generation": generation,
+			},
+		}}
+		if spec != nil {
+			obj.Object["spec"] = spec
+		}
+		if status != nil {
+			obj.Object["status"] = status
+		}
+		return obj
+	}
+	conditions := func(conditions ...map[string]interface{}) []interface{} {
+		ret := []interface{}{}
+		for _, c := range conditions {
+			ret = append(ret, c)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want Status
+	}{
+		{
+			name: "Object being deleted is Terminating",
+			obj: func() *unstructured.Unstructured {
+				obj := object("v1", "ConfigMap", 1, nil, nil)
+				now := metav1.Now()
+				obj.SetDeletionTimestamp(&now)
+				return obj
+			}(),
+			want: TerminatingStatus,
+		},
+		{
+			name: "Object without status is Current",
+			obj:  object("v1", "ConfigMap", 1, nil, nil),
+			want: CurrentStatus,
+		},
+		{
+			name: "Object with a generation not yet observed is InProgress",
+			obj:  object("foo.io/v1", "Foo", 2, nil, map[string]interface{}{"observedGeneration": int64(1)}),
+			want: InProgressStatus,
+		},
+		{
+			name: "Object with Ready condition false is InProgress",
+			obj: object("foo.io/v1", "Foo", 1, nil, map[string]interface{}{
+				"observedGeneration": int64(1),
+				"conditions":         conditions(map[string]interface{}{"type": "Ready", "status": "False"}),
+			}),
+			want: InProgressStatus,
+		},
+		{
+			name: "Object with Stalled condition true is Failed",
+			obj: object("foo.io/v1", "Foo", 1, nil, map[string]interface{}{
+				"conditions": conditions(map[string]interface{}{"type": "Stalled", "status": "True"}),
+			}),
+			want: FailedStatus,
+		},
+		{
+			name: "Object with Ready condition true is Current",
+			obj: object("foo.io/v1", "Foo", 1, nil, map[string]interface{}{
+				"observedGeneration": int64(1),
+				"conditions":         conditions(map[string]interface{}{"type": "Ready", "status": "True"}),
+			}),
+			want: CurrentStatus,
+		},
+		{
+			name: "Deployment with replicas not yet available is InProgress",
+			obj: object("apps/v1", "Deployment", 1, map[string]interface{}{"replicas": int64(2)}, map[string]interface{}{
+				"observedGeneration": int64(1),
+				"replicas":           int64(2),
+				"updatedReplicas":    int64(2),
+				"readyReplicas":      int64(2),
+				"availableReplicas":  int64(1),
+			}),
+			want: InProgressStatus,
+		},
+		{
+			name: "Deployment exceeding the progress deadline is Failed",
+			obj: object("apps/v1", "Deployment", 1, map[string]interface{}{"replicas": int64(2)}, map[string]interface{}{
+				"observedGeneration": int64(1),
+				"conditions":         conditions(map[string]interface{}{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"}),
+			}),
+			want: FailedStatus,
+		},
+		{
+			name: "Deployment with all the replicas available is Current",
+			obj: object("apps/v1", "Deployment", 1, nil, map[string]interface{}{
+				"observedGeneration": int64(1),
+				"replicas":           int64(1),
+				"updatedReplicas":    int64(1),
+				"readyReplicas":      int64(1),
+				"availableReplicas":  int64(1),
+			}),
+			want: CurrentStatus,
+		},
+		{
+			name: "DaemonSet with pods not yet ready is InProgress",
+			obj: object("apps/v1", "DaemonSet", 1, nil, map[string]interface{}{
+				"observedGeneration":     int64(1),
+				"desiredNumberScheduled": int64(3),
+				"currentNumberScheduled": int64(3),
+				"updatedNumberScheduled": int64(3),
+				"numberAvailable":        int64(3),
+				"numberReady":            int64(2),
+			}),
+			want: InProgressStatus,
+		},
+		{
+			name: "StatefulSet with a rollout in progress is InProgress",
+			obj: object("apps/v1", "StatefulSet", 1, map[string]interface{}{"replicas": int64(1)}, map[string]interface{}{
+				"observedGeneration": int64(1),
+				"replicas":           int64(1),
+				"readyReplicas":      int64(1),
+				"updatedReplicas":    int64(1),
+				"currentRevision":    "foo-1",
+				"updateRevision":     "foo-2",
+			}),
+			want: InProgressStatus,
+		},
+		{
+			name: "Job failed is Failed",
+			obj: object("batch/v1", "Job", 1, nil, map[string]interface{}{
+				"conditions": conditions(map[string]interface{}{"type": "Failed", "status": "True"}),
+			}),
+			want: FailedStatus,
+		},
+		{
+			name: "Pod running and ready is Current",
+			obj: object("v1", "Pod", 1, nil, map[string]interface{}{
+				"phase":      "Running",
+				"conditions": conditions(map[string]interface{}{"type": "Ready", "status": "True"}),
+			}),
+			want: CurrentStatus,
+		},
+		{
+			name: "Service of type LoadBalancer without ingress is InProgress",
+			obj:  object("v1", "Service", 1, map[string]interface{}{"type": "LoadBalancer"}, nil),
+			want: InProgressStatus,
+		},
+		{
+			name: "CustomResourceDefinition not yet established is InProgress",
+			obj:  object("apiextensions.k8s.io/v1", "CustomResourceDefinition", 1, nil, nil),
+			want: InProgressStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := ComputeStatus(tt.obj)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got.Status).To(Equal(tt.want), got.Message)
+		})
+	}
+}
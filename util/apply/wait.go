@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Wait waits for all the given objects to reach CurrentStatus, checking their status every interval until
+// timeout expires.
+// An error is returned as soon as an object reaches FailedStatus, or if not all the objects are Current
+// when the timeout expires; in both cases the error reports the status of the objects which are not Current.
+func Wait(ctx context.Context, c client.Reader, objs []unstructured.Unstructured, interval, timeout time.Duration) error {
+	notCurrent := map[string]*StatusResult{}
+	err := wait.PollImmediateWithContext(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		notCurrent = map[string]*StatusResult{}
+		hasFailed := false
+		for i := range objs {
+			status, err := GetStatus(ctx, c, &objs[i])
+			if err != nil {
+				// Errors reading the objects are considered transient, so the status is checked again at the next interval.
+				notCurrent[ObjectID(&objs[i])] = &StatusResult{Status: InProgressStatus, Message: err.Error()}
+				continue
+			}
+			if status.Status == FailedStatus {
+				hasFailed = true
+			}
+			if status.Status != CurrentStatus {
+				notCurrent[ObjectID(&objs[i])] = status
+			}
+		}
+		if hasFailed {
+			return false, errors.Errorf("some objects have failed: %s", aggregateStatus(notCurrent))
+		}
+		return len(notCurrent) == 0, nil
+	})
+	if err != nil {
+		if errors.Is(err, wait.ErrWaitTimeout) {
+			return errors.Errorf("timed out waiting for objects to be current: %s", aggregateStatus(notCurrent))
+		}
+		return err
+	}
+	return nil
+}
+
+// aggregateStatus returns a message with the status of the given objects, sorted by object ID.
+func aggregateStatus(statuses map[string]*StatusResult) string {
+	ids := make([]string, 0, len(statuses))
+	for id := range statuses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	messages := make([]string, 0, len(ids))
+	for _, id := range ids {
+		messages = append(messages, fmt.Sprintf("%s is %s (%s)", id, statuses[id].Status, statuses[id].Message))
+	}
+	return strings.Join(messages, ", ")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWait(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault},
+	}
+	deployment := func(availableReplicas int32, conditions ...appsv1.DeploymentCondition) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: metav1.NamespaceDefault},
+			Spec:       appsv1.DeploymentSpec{Replicas: pointer.Int32(1)},
+			Status: appsv1.DeploymentStatus{
+				Replicas:          1,
+				UpdatedReplicas:   1,
+				ReadyReplicas:     availableReplicas,
+				AvailableReplicas: availableReplicas,
+				Conditions:        conditions,
+			},
+		}
+	}
+	toUnstructured := func(objs ...client.Object) []unstructured.Unstructured {
+		ret := []unstructured.Unstructured{}
+		for _, obj := range objs {
+			u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if err != nil {
+				panic(err)
+			}
+			ret = append(ret, unstructured.Unstructured{Object: u})
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name      string
+		objs      []client.Object
+		wait      []client.Object
+		getErrors int
+		wantErr   string
+	}{
+		{
+			name:    "Pass if all the objects are current",
+			objs:    []client.Object{configMap, deployment(1)},
+			wait:    []client.Object{configMap, deployment(1)},
+			wantErr: "",
+		},
+		{
+			name:    "Fail if an object does not become current before the timeout",
+			objs:    []client.Object{configMap, deployment(0)},
+			wait:    []client.Object{configMap, deployment(0)},
+			wantErr: "timed out waiting for objects to be current: apps/v1, Kind=Deployment default/foo is InProgress",
+		},
+		{
+			name:    "Fail if an object does not exist",
+			objs:    []client.Object{},
+			wait:    []client.Object{configMap},
+			wantErr: "timed out waiting for objects to be current: /v1, Kind=ConfigMap default/foo is NotFound",
+		},
+		{
+			name:      "Pass if errors reading the objects are transient",
+			objs:      []client.Object{configMap, deployment(1)},
+			wait:      []client.Object{configMap, deployment(1)},
+			getErrors: 2,
+			wantErr:   "",
+		},
+		{
+			name:      "Fail if errors reading the objects persist until the timeout",
+			objs:      []client.Object{configMap},
+			wait:      []client.Object{configMap},
+			getErrors: 100,
+			wantErr:   "timed out waiting for objects to be current: /v1, Kind=ConfigMap default/foo is InProgress (failed to get object /v1, Kind=ConfigMap default/foo: failed to read",
+		},
+		{
+			name:    "Fail without waiting for the timeout if an object is failed",
+			objs:    []client.Object{deployment(0, appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Reason: "ProgressDeadlineExceeded"})},
+			wait:    []client.Object{deployment(0)},
+			wantErr: "some objects have failed: apps/v1, Kind=Deployment default/foo is Failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &failingGetReader{
+				Reader:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.objs...).Build(),
+				getErrors: tt.getErrors,
+			}
+
+			err := Wait(context.Background(), c, toUnstructured(tt.wait...), 10*time.Millisecond, 50*time.Millisecond)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(HavePrefix(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestWaitStopsWhenContextIsCancelled(t *testing.T) {
+	g := NewWithT(t)
+
+	c := fake.NewClientBuilder().Build()
+	configMap := unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetNamespace(metav1.NamespaceDefault)
+	configMap.SetName("foo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Wait(ctx, c, []unstructured.Unstructured{configMap}, 10*time.Millisecond, time.Minute)
+	g.Expect(err).To(HaveOccurred())
+}
+
+// failingGetReader is a reader failing the first getErrors calls to Get.
+type failingGetReader struct {
+	client.Reader
+	getErrors int
+}
+
+func (r *failingGetReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if r.getErrors > 0 {
+		r.getErrors--
+		return errors.New("failed to read")
+	}
+	return r.Reader.Get(ctx, key, obj, opts...)
+}
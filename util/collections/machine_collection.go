@@ -116,6 +116,38 @@ func (s Machines) Difference(machines Machines) Machines {
 	})
 }
 
+// Intersection returns a copy with only the machines that are also in the given collection.
+func (s Machines) Intersection(machines Machines) Machines {
+	return s.Filter(func(m *clusterv1.Machine) bool {
+		_, found := machines[m.Name]
+		return found
+	})
+}
+
+// Union returns a copy with the machines in this and in the given collection.
+func (s Machines) Union(machines Machines) Machines {
+	result := make(Machines, len(s)+len(machines))
+	result.Insert(s.UnsortedList()...)
+	result.Insert(machines.UnsortedList()...)
+	return result
+}
+
+// Has returns true if the collection contains a machine with the same name.
+func (s Machines) Has(machine *clusterv1.Machine) bool {
+	if machine == nil {
+		return false
+	}
+	_, found := s[machine.Name]
+	return found
+}
+
+// SortedByName returns the machines sorted by name, e.g. to process them in a deterministic order.
+func (s Machines) SortedByName() []*clusterv1.Machine {
+	res := s.UnsortedList()
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
 // SortedByCreationTimestamp returns the machines sorted by creation timestamp.
 func (s Machines) SortedByCreationTimestamp() []*clusterv1.Machine {
 	res := make(machinesByCreationTimestamp, 0, len(s))
@@ -197,13 +229,14 @@ func (s Machines) ConditionGetters() []conditions.Getter {
 	return res
 }
 
-// Names returns a slice of the names of each machine in the collection.
+// Names returns a sorted slice of the names of each machine in the collection.
 // Useful for logging and test assertions.
 func (s Machines) Names() []string {
 	names := make([]string, 0, s.Len())
 	for _, m := range s {
 		names = append(names, m.Name)
 	}
+	sort.Strings(names)
 	return names
 }
 
@@ -52,11 +52,42 @@ func TestMachineCollection(t *testing.T) {
 			g.Expect(c3.Names()).To(ConsistOf("machine-1"))
 		})
 	})
+	t.Run("Intersection", func(t *testing.T) {
+		t.Run("should return the collection with only the elements also in the second collection", func(t *testing.T) {
+			g := NewWithT(t)
+			collection := collections.FromMachines(machine("1"), machine("2"), machine("3"))
+			c2 := collections.FromMachines(machine("2"), machine("3"), machine("4"))
+			g.Expect(collection.Intersection(c2).Names()).To(Equal([]string{"2", "3"}))
+			// does not mutate
+			g.Expect(collection.Names()).To(Equal([]string{"1", "2", "3"}))
+		})
+	})
+	t.Run("Union", func(t *testing.T) {
+		t.Run("should return the collection with the elements of both collections", func(t *testing.T) {
+			g := NewWithT(t)
+			collection := collections.FromMachines(machine("1"), machine("2"))
+			c2 := collections.FromMachines(machine("2"), machine("3"))
+			g.Expect(collection.Union(c2).Names()).To(Equal([]string{"1", "2", "3"}))
+			// does not mutate
+			g.Expect(collection.Names()).To(Equal([]string{"1", "2"}))
+			g.Expect(collection.Has(machine("1"))).To(BeTrue())
+			g.Expect(collection.Has(machine("3"))).To(BeFalse())
+		})
+	})
+	t.Run("SortedByName", func(t *testing.T) {
+		t.Run("should return the machines sorted by name", func(t *testing.T) {
+			g := NewWithT(t)
+			sortedMachines := collections.FromMachines(machine("b"), machine("c"), machine("a")).SortedByName()
+			g.Expect(sortedMachines).To(HaveLen(3))
+			g.Expect(sortedMachines[0].Name).To(Equal("a"))
+			g.Expect(sortedMachines[2].Name).To(Equal("c"))
+		})
+	})
 	t.Run("Names", func(t *testing.T) {
-		t.Run("should return a slice of names of each machine in the collection", func(t *testing.T) {
+		t.Run("should return a sorted slice of names of each machine in the collection", func(t *testing.T) {
 			g := NewWithT(t)
 			g.Expect(collections.New().Names()).To(BeEmpty())
-			g.Expect(collections.FromMachines(machine("1"), machine("2")).Names()).To(ConsistOf("1", "2"))
+			g.Expect(collections.FromMachines(machine("2"), machine("1")).Names()).To(Equal([]string{"1", "2"}))
 		})
 	})
 }
@@ -110,6 +110,17 @@ func OwnedMachines(owner client.Object) func(machine *clusterv1.Machine) bool {
 	}
 }
 
+// ControlledMachines returns a filter to find all machines controlled by the specified owner, i.e. with a controller
+// reference with the UID of the owner.
+func ControlledMachines(owner client.Object) Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil {
+			return false
+		}
+		return metav1.IsControlledBy(machine, owner)
+	}
+}
+
 // ControlPlaneMachines returns a filter to find all control plane machines for a cluster, regardless of ownership.
 // Usage: GetFilteredMachinesForCluster(ctx, client, cluster, ControlPlaneMachines(cluster.Name)).
 func ControlPlaneMachines(clusterName string) func(machine *clusterv1.Machine) bool {
@@ -148,14 +159,69 @@ func HasDeletionTimestamp(machine *clusterv1.Machine) bool {
 	return !machine.DeletionTimestamp.IsZero()
 }
 
+// HasNodeRef returns a filter to find all machines with a NodeRef, i.e. machines whose Node has been created.
+func HasNodeRef(machine *clusterv1.Machine) bool {
+	if machine == nil {
+		return false
+	}
+	return machine.Status.NodeRef != nil
+}
+
+// HasNodeRefName returns a filter to find all machines with a NodeRef to the Node with the given name.
+func HasNodeRefName(nodeName string) Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil {
+			return false
+		}
+		return machine.Status.NodeRef != nil && machine.Status.NodeRef.Name == nodeName
+	}
+}
+
 // HasUnhealthyCondition returns a filter to find all machines that have a MachineHealthCheckSucceeded condition set to False,
 // indicating a problem was detected on the machine, and the MachineOwnerRemediated condition set, indicating that KCP is
 // responsible of performing remediation as owner of the machine.
 func HasUnhealthyCondition(machine *clusterv1.Machine) bool {
+	return HasFailedHealthCheck(machine) && NeedsRemediation(machine)
+}
+
+// HasHealthyCondition returns a filter to find all machines that have a MachineHealthCheckSucceeded condition set to True,
+// i.e. machines checked by a MachineHealthCheck and found healthy.
+func HasHealthyCondition(machine *clusterv1.Machine) bool {
+	if machine == nil {
+		return false
+	}
+	return conditions.IsTrue(machine, clusterv1.MachineHealthCheckSucceededCondition)
+}
+
+// HasFailedHealthCheck returns a filter to find all machines that have a MachineHealthCheckSucceeded condition set to False,
+// indicating a problem was detected on the machine by a MachineHealthCheck.
+func HasFailedHealthCheck(machine *clusterv1.Machine) bool {
 	if machine == nil {
 		return false
 	}
-	return conditions.IsFalse(machine, clusterv1.MachineHealthCheckSucceededCondition) && conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)
+	return conditions.IsFalse(machine, clusterv1.MachineHealthCheckSucceededCondition)
+}
+
+// NeedsRemediation returns a filter to find all machines that have a MachineOwnerRemediated condition set to False,
+// indicating the owner of the machine is responsible of performing remediation.
+func NeedsRemediation(machine *clusterv1.Machine) bool {
+	if machine == nil {
+		return false
+	}
+	return conditions.IsFalse(machine, clusterv1.MachineOwnerRemediatedCondition)
+}
+
+// HasTemplateHash returns a filter to find all machines created by the MachineSet of a MachineDeployment with the
+// given template hash, i.e. with the given value of the MachineDeploymentUniqueLabel; Not(HasTemplateHash(hash))
+// can be used to find the machines of a MachineDeployment which are not yet rolled out to the current template.
+func HasTemplateHash(hash string) Func {
+	return func(machine *clusterv1.Machine) bool {
+		if machine == nil {
+			return false
+		}
+		value, ok := machine.Labels[clusterv1.MachineDeploymentUniqueLabel]
+		return ok && value == hash
+	}
 }
 
 // IsReady returns a filter to find all machines with the ReadyCondition equals to True.
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -105,6 +106,72 @@ func TestHasUnhealthyCondition(t *testing.T) {
 	})
 }
 
+func TestHasHealthyCondition(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{}
+	g.Expect(collections.HasHealthyCondition(m)).To(BeFalse())
+	g.Expect(collections.HasFailedHealthCheck(m)).To(BeFalse())
+
+	conditions.MarkTrue(m, clusterv1.MachineHealthCheckSucceededCondition)
+	g.Expect(collections.HasHealthyCondition(m)).To(BeTrue())
+	g.Expect(collections.HasFailedHealthCheck(m)).To(BeFalse())
+
+	conditions.MarkFalse(m, clusterv1.MachineHealthCheckSucceededCondition, clusterv1.MachineHasFailureReason, clusterv1.ConditionSeverityWarning, "")
+	g.Expect(collections.HasHealthyCondition(m)).To(BeFalse())
+	g.Expect(collections.HasFailedHealthCheck(m)).To(BeTrue())
+	g.Expect(collections.NeedsRemediation(m)).To(BeFalse())
+
+	conditions.MarkFalse(m, clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+	g.Expect(collections.NeedsRemediation(m)).To(BeTrue())
+
+	g.Expect(collections.HasHealthyCondition(nil)).To(BeFalse())
+	g.Expect(collections.HasFailedHealthCheck(nil)).To(BeFalse())
+	g.Expect(collections.NeedsRemediation(nil)).To(BeFalse())
+}
+
+func TestHasTemplateHash(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{}
+	g.Expect(collections.HasTemplateHash("1234")(m)).To(BeFalse())
+
+	m.SetLabels(map[string]string{clusterv1.MachineDeploymentUniqueLabel: "1234"})
+	g.Expect(collections.HasTemplateHash("1234")(m)).To(BeTrue())
+	g.Expect(collections.HasTemplateHash("5678")(m)).To(BeFalse())
+	g.Expect(collections.HasTemplateHash("1234")(nil)).To(BeFalse())
+}
+
+func TestControlledMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	owner := &clusterv1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: "ms", UID: "ms-uid"}}
+	other := &clusterv1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "other-uid"}}
+	m := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, clusterv1.GroupVersion.WithKind("MachineSet"))},
+	}}
+
+	g.Expect(collections.ControlledMachines(owner)(m)).To(BeTrue())
+	g.Expect(collections.ControlledMachines(other)(m)).To(BeFalse())
+	g.Expect(collections.ControlledMachines(owner)(nil)).To(BeFalse())
+}
+
+func TestHasNodeRef(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{}
+	g.Expect(collections.HasNodeRef(m)).To(BeFalse())
+	g.Expect(collections.HasNodeRefName("node-1")(m)).To(BeFalse())
+
+	m.Status.NodeRef = &corev1.ObjectReference{Name: "node-1"}
+	g.Expect(collections.HasNodeRef(m)).To(BeTrue())
+	g.Expect(collections.HasNodeRefName("node-1")(m)).To(BeTrue())
+	g.Expect(collections.HasNodeRefName("node-2")(m)).To(BeFalse())
+
+	g.Expect(collections.HasNodeRef(nil)).To(BeFalse())
+	g.Expect(collections.HasNodeRefName("node-1")(nil)).To(BeFalse())
+}
+
 func TestHasDeletionTimestamp(t *testing.T) {
 	t.Run("machine with deletion timestamp returns true", func(t *testing.T) {
 		g := NewWithT(t)
@@ -17,6 +17,9 @@ limitations under the License.
 package conditions
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -147,8 +150,9 @@ func summary(from Getter, options ...MergeOption) *clusterv1.Condition {
 			}
 		}
 
+		// Conditions with negative polarity are normalized, so Status=True always represents the desired state when merging.
 		conditionsInScope = append(conditionsInScope, localizedCondition{
-			Condition: &c,
+			Condition: normalize(&c, mergeOpt.negativePolarityConditionTypes),
 			Getter:    from,
 		})
 	}
@@ -192,6 +196,9 @@ type mirrorOptions struct {
 	fallbackReason   string
 	fallbackSeverity clusterv1.ConditionSeverity
 	fallbackMessage  string
+	addDetails       bool
+	// negativePolarityConditionTypes are the condition types with negative polarity considered when adding details.
+	negativePolarityConditionTypes []clusterv1.ConditionType
 }
 
 // MirrorOptions defines an option for mirroring conditions.
@@ -208,6 +215,18 @@ func WithFallbackValue(fallbackValue bool, reason string, severity clusterv1.Con
 	}
 }
 
+// WithDetails specify that, in case the mirrored condition is not true, messages from the
+// other not true conditions existing on the source object should be appended to the target condition message,
+// so it is possible to understand why the source object is not ready without looking at it.
+// Condition types with negative polarity, e.g. Degraded, where Status=True represents an undesired state, can be
+// passed so their messages are appended when Status=True instead of when Status=False.
+func WithDetails(negativePolarityConditionTypes ...clusterv1.ConditionType) MirrorOptions {
+	return func(c *mirrorOptions) {
+		c.addDetails = true
+		c.negativePolarityConditionTypes = negativePolarityConditionTypes
+	}
+}
+
 // mirror mirrors the Ready condition from a dependent object into the target condition;
 // if the Ready condition does not exists in the source object, no target conditions is generated.
 func mirror(from Getter, targetCondition clusterv1.ConditionType, options ...MirrorOptions) *clusterv1.Condition {
@@ -229,11 +248,37 @@ func mirror(from Getter, targetCondition clusterv1.ConditionType, options ...Mir
 
 	if condition != nil {
 		condition.Type = targetCondition
+
+		if mirrorOpt.addDetails && condition.Status != corev1.ConditionTrue {
+			condition.Message = addDetails(condition.Message, from, mirrorOpt.negativePolarityConditionTypes)
+		}
 	}
 
 	return condition
 }
 
+// addDetails appends to a message the messages of all the conditions not in the desired state existing on the source object,
+// according to the given negative polarity condition types, excluding the Ready condition and the conditions with an empty
+// message or a message already included.
+func addDetails(message string, from Getter, negativePolarityConditionTypes []clusterv1.ConditionType) string {
+	details := []string{}
+	for i := range from.GetConditions() {
+		c := from.GetConditions()[i]
+		if c.Type == clusterv1.ReadyCondition || normalize(&c, negativePolarityConditionTypes).Status == corev1.ConditionTrue || c.Message == "" || c.Message == message {
+			continue
+		}
+		details = append(details, fmt.Sprintf("%s: %s", c.Type, c.Message))
+	}
+
+	if len(details) == 0 {
+		return message
+	}
+	if message == "" {
+		return strings.Join(details, "; ")
+	}
+	return fmt.Sprintf("%s (%s)", message, strings.Join(details, "; "))
+}
+
 // Aggregates all the Ready condition from a list of dependent objects into the target object;
 // if the Ready condition does not exists in one of the source object, the object is excluded from
 // the aggregation; if none of the source object have ready condition, no target conditions is generated.
@@ -94,12 +94,16 @@ func TestMirror(t *testing.T) {
 	ready := TrueCondition(clusterv1.ReadyCondition)
 	readyBar := ready.DeepCopy()
 	readyBar.Type = "bar"
+	notReady := FalseCondition(clusterv1.ReadyCondition, "reason foo", clusterv1.ConditionSeverityInfo, "message foo")
+	baz := FalseCondition("baz", "reason baz", clusterv1.ConditionSeverityWarning, "message baz")
+	qux := TrueCondition("qux")
 
 	tests := []struct {
-		name string
-		from Getter
-		t    clusterv1.ConditionType
-		want *clusterv1.Condition
+		name    string
+		from    Getter
+		t       clusterv1.ConditionType
+		options []MirrorOptions
+		want    *clusterv1.Condition
 	}{
 		{
 			name: "Returns nil when the ready condition does not exists",
@@ -112,13 +116,34 @@ func TestMirror(t *testing.T) {
 			t:    "bar",
 			want: readyBar,
 		},
+		{
+			name:    "Does not add details when the ready condition is true",
+			from:    getterWithConditions(ready, foo),
+			t:       "bar",
+			options: []MirrorOptions{WithDetails()},
+			want:    readyBar,
+		},
+		{
+			name:    "Adds details from not true conditions when the ready condition is false",
+			from:    getterWithConditions(notReady, foo, baz, qux),
+			t:       "bar",
+			options: []MirrorOptions{WithDetails()},
+			want:    FalseCondition("bar", "reason foo", clusterv1.ConditionSeverityInfo, "message foo (baz: message baz)"),
+		},
+		{
+			name:    "Adds details to the fallback condition when the ready condition does not exists",
+			from:    getterWithConditions(foo, baz),
+			t:       "bar",
+			options: []MirrorOptions{WithFallbackValue(false, "fallback reason", clusterv1.ConditionSeverityInfo, ""), WithDetails()},
+			want:    FalseCondition("bar", "fallback reason", clusterv1.ConditionSeverityInfo, "foo: message foo; baz: message baz"),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			g := NewWithT(t)
 
-			got := mirror(tt.from, tt.t)
+			got := mirror(tt.from, tt.t, tt.options...)
 			if tt.want == nil {
 				g.Expect(got).To(BeNil())
 				return
@@ -31,6 +31,7 @@ type mergeOptions struct {
 	addStepCounter                     bool
 	addStepCounterIfOnlyConditionTypes []clusterv1.ConditionType
 	stepCounter                        int
+	negativePolarityConditionTypes     []clusterv1.ConditionType
 }
 
 // MergeOption defines an option for computing a summary of conditions.
@@ -81,6 +82,18 @@ func WithStepCounterIfOnly(t ...clusterv1.ConditionType) MergeOption {
 	}
 }
 
+// WithNegativePolarityConditions instructs merge about condition types with negative polarity, e.g. Degraded,
+// where Status=True represents an undesired state; those conditions are considered as not in the desired state
+// when Status=True, using their severity or Warning if not set, and as in the desired state when Status=False.
+// All the other condition types are considered with positive polarity, where Status=True represents the desired state.
+//
+// IMPORTANT: This options works only while generating the Summary condition.
+func WithNegativePolarityConditions(t ...clusterv1.ConditionType) MergeOption {
+	return func(c *mergeOptions) {
+		c.negativePolarityConditionTypes = t
+	}
+}
+
 // AddSourceRef instructs merge to add info about the originating object to the target Reason.
 func AddSourceRef() MergeOption {
 	return func(c *mergeOptions) {
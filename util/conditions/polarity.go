@@ -0,0 +1,56 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// normalize returns a condition where Status=True represents the desired state, so it can be merged with other conditions.
+// Conditions with one of the given negative polarity types, where Status=True represents an undesired state, e.g. Degraded,
+// are inverted; all the other conditions are returned as they are.
+// NOTE: Inverted conditions with Status=True keep their severity, defaulting to Warning if not set.
+func normalize(c *clusterv1.Condition, negativePolarityTypes []clusterv1.ConditionType) *clusterv1.Condition {
+	if c == nil || !hasType(c.Type, negativePolarityTypes) {
+		return c
+	}
+
+	normalized := c.DeepCopy()
+	switch c.Status {
+	case corev1.ConditionTrue:
+		normalized.Status = corev1.ConditionFalse
+		if normalized.Severity == clusterv1.ConditionSeverityNone {
+			normalized.Severity = clusterv1.ConditionSeverityWarning
+		}
+	case corev1.ConditionFalse:
+		normalized.Status = corev1.ConditionTrue
+		normalized.Severity = clusterv1.ConditionSeverityNone
+	}
+	return normalized
+}
+
+// hasType returns true if the list of condition types includes the given type.
+func hasType(t clusterv1.ConditionType, types []clusterv1.ConditionType) bool {
+	for _, x := range types {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name          string
+		condition     *clusterv1.Condition
+		negativeTypes []clusterv1.ConditionType
+		want          *clusterv1.Condition
+	}{
+		{
+			name:      "Returns nil for nil conditions",
+			condition: nil,
+			want:      nil,
+		},
+		{
+			name:      "Returns conditions with positive polarity as they are",
+			condition: FalseCondition("foo", "reason foo", clusterv1.ConditionSeverityInfo, "message foo"),
+			want:      FalseCondition("foo", "reason foo", clusterv1.ConditionSeverityInfo, "message foo"),
+		},
+		{
+			name:          "Returns conditions not included in the negative polarity types as they are",
+			condition:     TrueCondition("foo"),
+			negativeTypes: []clusterv1.ConditionType{"bar"},
+			want:          TrueCondition("foo"),
+		},
+		{
+			name:          "Inverts true conditions with negative polarity, defaulting severity to warning",
+			condition:     TrueCondition("foo"),
+			negativeTypes: []clusterv1.ConditionType{"foo"},
+			want:          FalseCondition("foo", "", clusterv1.ConditionSeverityWarning, ""),
+		},
+		{
+			name:          "Inverts true conditions with negative polarity, preserving severity",
+			condition:     &clusterv1.Condition{Type: "foo", Status: "True", Severity: clusterv1.ConditionSeverityError, Reason: "reason error", Message: "message error"},
+			negativeTypes: []clusterv1.ConditionType{"foo"},
+			want:          FalseCondition("foo", "reason error", clusterv1.ConditionSeverityError, "message error"),
+		},
+		{
+			name:          "Inverts false conditions with negative polarity",
+			condition:     &clusterv1.Condition{Type: "foo", Status: "False", Reason: "reason foo"},
+			negativeTypes: []clusterv1.ConditionType{"foo"},
+			want:          TrueCondition("foo"),
+		},
+		{
+			name:          "Does not change unknown conditions with negative polarity",
+			condition:     UnknownCondition("foo", "reason foo", "message foo"),
+			negativeTypes: []clusterv1.ConditionType{"foo"},
+			want:          UnknownCondition("foo", "reason foo", "message foo"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := normalize(tt.condition, tt.negativeTypes)
+			if tt.want == nil {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got.Type).To(Equal(tt.want.Type))
+			g.Expect(got.Status).To(Equal(tt.want.Status))
+			g.Expect(got.Severity).To(Equal(tt.want.Severity))
+		})
+	}
+}
+
+func TestSummaryWithNegativePolarity(t *testing.T) {
+	g := NewWithT(t)
+
+	foo := TrueCondition("foo")
+	notDegraded := FalseCondition("Degraded", "AsExpected", clusterv1.ConditionSeverityNone, "")
+	degraded := &clusterv1.Condition{Type: "Degraded", Status: "True", Reason: "LoadBalancerDegraded", Message: "1 of 2 load balancers available"}
+
+	// Without the option, conditions with Status=True are considered in the desired state.
+	g.Expect(summary(getterWithConditions(foo, degraded))).To(HaveSameStateOf(TrueCondition(clusterv1.ReadyCondition)))
+
+	// Conditions with negative polarity and Status=False do not affect the Ready condition.
+	g.Expect(summary(getterWithConditions(foo, notDegraded), WithStepCounter(), WithNegativePolarityConditions("Degraded"))).To(HaveSameStateOf(TrueCondition(clusterv1.ReadyCondition)))
+
+	// Conditions with negative polarity and Status=True affect the Ready condition.
+	g.Expect(summary(getterWithConditions(foo, degraded), WithNegativePolarityConditions("Degraded"))).To(HaveSameStateOf(
+		FalseCondition(clusterv1.ReadyCondition, "LoadBalancerDegraded", clusterv1.ConditionSeverityWarning, "1 of 2 load balancers available"),
+	))
+	g.Expect(summary(getterWithConditions(foo, degraded), WithStepCounter(), WithNegativePolarityConditions("Degraded"))).To(HaveSameStateOf(
+		FalseCondition(clusterv1.ReadyCondition, "LoadBalancerDegraded", clusterv1.ConditionSeverityWarning, "1 of 2 completed"),
+	))
+}
+
+func TestMirrorWithNegativePolarity(t *testing.T) {
+	g := NewWithT(t)
+
+	notReady := FalseCondition(clusterv1.ReadyCondition, "reason foo", clusterv1.ConditionSeverityInfo, "message foo")
+	notScalingDown := FalseCondition("ScalingDown", "AsExpected", clusterv1.ConditionSeverityNone, "no machines to delete")
+	degraded := &clusterv1.Condition{Type: "Degraded", Status: "True", Reason: "LoadBalancerDegraded", Message: "1 of 2 load balancers available"}
+
+	// Without negative polarity condition types, details are added for conditions with Status=False.
+	g.Expect(mirror(getterWithConditions(notReady, notScalingDown, degraded), "bar", WithDetails())).To(HaveSameStateOf(
+		FalseCondition("bar", "reason foo", clusterv1.ConditionSeverityInfo, "message foo (ScalingDown: no machines to delete)"),
+	))
+
+	// Details are added taking into account the negative polarity condition types.
+	g.Expect(mirror(getterWithConditions(notReady, notScalingDown, degraded), "bar", WithDetails("Degraded", "ScalingDown"))).To(HaveSameStateOf(
+		FalseCondition("bar", "reason foo", clusterv1.ConditionSeverityInfo, "message foo (Degraded: 1 of 2 load balancers available)"),
+	))
+}
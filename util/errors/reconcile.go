@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errors implements typed reconcile errors, which allow controllers to handle reconcile errors consistently.
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const (
+	// TerminalReason is the reason reported for a TerminalError without a more specific reason.
+	TerminalReason = "Terminal"
+
+	// RequeueAfterReason is the reason reported for a RequeueAfterError without a more specific reason.
+	RequeueAfterReason = "RequeueAfter"
+
+	// ExternalDependencyNotReadyReason is the reason reported for an ExternalDependencyNotReadyError.
+	ExternalDependencyNotReadyReason = "ExternalDependencyNotReady"
+
+	// UnknownReason is the reason reported for errors which are not reconcile errors.
+	UnknownReason = "Unknown"
+)
+
+// TerminalError is a reconcile error which cannot be solved by retrying the reconcile, e.g. an invalid
+// configuration; reconciliation is not retried until the object, or one of the watched objects, changes.
+type TerminalError struct {
+	// Reason is a short, CamelCase reason for the error, meant to be used in conditions and metrics.
+	Reason string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error satisfies the error interface.
+func (e *TerminalError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *TerminalError) Unwrap() error {
+	return e.Err
+}
+
+// NewTerminalError returns a TerminalError with the given reason; if the reason is empty, TerminalReason is used.
+func NewTerminalError(reason string, err error) *TerminalError {
+	if reason == "" {
+		reason = TerminalReason
+	}
+	return &TerminalError{Reason: reason, Err: err}
+}
+
+// RequeueAfterError is a reconcile error which is expected to be solved by retrying the reconcile after
+// some time, e.g. a lock held by another worker; reconciliation is retried after RequeueAfter, without
+// the exponential backoff applied to other errors.
+type RequeueAfterError struct {
+	// Reason is a short, CamelCase reason for the error, meant to be used in conditions and metrics.
+	Reason string
+
+	// Message is a human-readable message describing the error.
+	Message string
+
+	// RequeueAfter is the time after which reconciliation is retried; if zero, reconciliation is retried
+	// as soon as possible.
+	RequeueAfter time.Duration
+}
+
+// Error satisfies the error interface.
+func (e *RequeueAfterError) Error() string {
+	return e.Message
+}
+
+// NewRequeueAfterError returns a RequeueAfterError with the given reason; if the reason is empty,
+// RequeueAfterReason is used.
+func NewRequeueAfterError(reason string, requeueAfter time.Duration, format string, args ...interface{}) *RequeueAfterError {
+	if reason == "" {
+		reason = RequeueAfterReason
+	}
+	return &RequeueAfterError{Reason: reason, Message: fmt.Sprintf(format, args...), RequeueAfter: requeueAfter}
+}
+
+// ExternalDependencyNotReadyError is a reconcile error reporting an object, usually managed by another controller
+// or provider, which is not ready yet; reconciliation is retried after RequeueAfter, without the exponential
+// backoff applied to other errors.
+type ExternalDependencyNotReadyError struct {
+	// Dependency describes the object which is not ready, e.g. the kind and the namespace/name of the object.
+	Dependency string
+
+	// RequeueAfter is the time after which reconciliation is retried; if zero, reconciliation is retried
+	// as soon as possible.
+	RequeueAfter time.Duration
+}
+
+// Error satisfies the error interface.
+func (e *ExternalDependencyNotReadyError) Error() string {
+	return fmt.Sprintf("%s is not ready", e.Dependency)
+}
+
+// NewExternalDependencyNotReadyError returns an ExternalDependencyNotReadyError for the given dependency.
+func NewExternalDependencyNotReadyError(dependency string, requeueAfter time.Duration) *ExternalDependencyNotReadyError {
+	return &ExternalDependencyNotReadyError{Dependency: dependency, RequeueAfter: requeueAfter}
+}
+
+// IsTerminal returns true if the error, which can be a wrapped error or an aggregate, is or contains a TerminalError.
+func IsTerminal(err error) bool {
+	for _, e := range flatten(err) {
+		var terminalErr *TerminalError
+		if errors.As(e, &terminalErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reason returns the reason of a reconcile error, which can be a wrapped error or an aggregate, meant to be used
+// in conditions and metrics; an empty string is returned if the error is nil, UnknownReason if it is not a
+// reconcile error. For aggregates, the reason of the first error is returned.
+func Reason(err error) string {
+	errs := flatten(err)
+	if len(errs) == 0 {
+		return ""
+	}
+
+	var terminalErr *TerminalError
+	var requeueAfterErr *RequeueAfterError
+	var notReadyErr *ExternalDependencyNotReadyError
+	switch {
+	case errors.As(errs[0], &terminalErr):
+		return terminalErr.Reason
+	case errors.As(errs[0], &requeueAfterErr):
+		return requeueAfterErr.Reason
+	case errors.As(errs[0], &notReadyErr):
+		return ExternalDependencyNotReadyReason
+	default:
+		return UnknownReason
+	}
+}
+
+// ReconcileResult translates the result and the error of a reconcile, where the error can be a wrapped error
+// or an aggregate, into the result and the error to be returned to controller-runtime, so all controllers handle
+// reconcile errors consistently:
+//   - RequeueAfterErrors and ExternalDependencyNotReadyErrors are logged and reconciliation is retried after the
+//     shortest of their RequeueAfter and of the requeue of the result, without returning an error.
+//   - TerminalErrors are logged and dropped, so reconciliation is not retried.
+//   - Other errors are returned as is.
+func ReconcileResult(ctx context.Context, res ctrl.Result, err error) (ctrl.Result, error) {
+	if err == nil {
+		return res, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+
+	requeue := ctrl.Result{}
+	requeueAfter := func(d time.Duration) {
+		requeue = util.LowestNonZeroResult(requeue, ctrl.Result{Requeue: d == 0, RequeueAfter: d})
+	}
+
+	errs := []error{}
+	for _, e := range flatten(err) {
+		var terminalErr *TerminalError
+		var requeueAfterErr *RequeueAfterError
+		var notReadyErr *ExternalDependencyNotReadyError
+		switch {
+		case errors.As(e, &terminalErr):
+			log.Error(e, "Reconciliation failed with a terminal error, not retrying", "reason", terminalErr.Reason)
+		case errors.As(e, &requeueAfterErr):
+			log.V(5).Info("Requeueing", "reason", requeueAfterErr.Reason, "requeueAfter", requeueAfterErr.RequeueAfter, "message", e.Error())
+			requeueAfter(requeueAfterErr.RequeueAfter)
+		case errors.As(e, &notReadyErr):
+			log.Info(fmt.Sprintf("Waiting for %s", notReadyErr.Dependency), "requeueAfter", notReadyErr.RequeueAfter)
+			requeueAfter(notReadyErr.RequeueAfter)
+		default:
+			errs = append(errs, e)
+		}
+	}
+
+	// Other errors take precedence, so they are retried with the exponential backoff.
+	if len(errs) == 1 {
+		return res, errs[0]
+	}
+	if len(errs) > 1 {
+		return res, kerrors.NewAggregate(errs)
+	}
+	return util.LowestNonZeroResult(res, requeue), nil
+}
+
+// flatten returns the errors of an aggregate, recursively, or the error itself if it is not an aggregate.
+func flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+	var agg kerrors.Aggregate
+	if !errors.As(err, &agg) {
+		return []error{err}
+	}
+	errs := []error{}
+	for _, e := range agg.Errors() {
+		errs = append(errs, flatten(e)...)
+	}
+	return errs
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	pkgerrors "github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestReconcileResult(t *testing.T) {
+	otherErr := pkgerrors.New("something went wrong")
+
+	tests := []struct {
+		name       string
+		res        ctrl.Result
+		err        error
+		wantResult ctrl.Result
+		wantErr    error
+	}{
+		{
+			name:       "No error",
+			res:        ctrl.Result{RequeueAfter: time.Minute},
+			wantResult: ctrl.Result{RequeueAfter: time.Minute},
+		},
+		{
+			name:    "Other errors are returned as is",
+			err:     otherErr,
+			wantErr: otherErr,
+		},
+		{
+			name:       "Terminal errors are dropped",
+			err:        pkgerrors.Wrap(NewTerminalError("InvalidConfiguration", otherErr), "failed to reconcile"),
+			wantResult: ctrl.Result{},
+		},
+		{
+			name:       "RequeueAfter errors are retried after RequeueAfter",
+			err:        pkgerrors.Wrap(NewRequeueAfterError("", time.Minute, "waiting"), "failed to reconcile"),
+			wantResult: ctrl.Result{RequeueAfter: time.Minute},
+		},
+		{
+			name:       "RequeueAfter errors without RequeueAfter are retried as soon as possible",
+			err:        NewRequeueAfterError("ClusterLocked", 0, "cluster is locked already"),
+			wantResult: ctrl.Result{Requeue: true},
+		},
+		{
+			name:       "ExternalDependencyNotReady errors are retried after RequeueAfter",
+			err:        NewExternalDependencyNotReadyError("DockerCluster default/cluster1", 30*time.Second),
+			wantResult: ctrl.Result{RequeueAfter: 30 * time.Second},
+		},
+		{
+			name: "The shortest requeue is used",
+			res:  ctrl.Result{RequeueAfter: 20 * time.Second},
+			err: kerrors.NewAggregate([]error{
+				NewRequeueAfterError("", time.Minute, "waiting"),
+				NewExternalDependencyNotReadyError("DockerCluster default/cluster1", 30*time.Second),
+			}),
+			wantResult: ctrl.Result{RequeueAfter: 20 * time.Second},
+		},
+		{
+			name: "Other errors take precedence",
+			err: kerrors.NewAggregate([]error{
+				NewRequeueAfterError("", time.Minute, "waiting"),
+				NewTerminalError("", otherErr),
+				otherErr,
+			}),
+			wantErr: otherErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			res, err := ReconcileResult(context.Background(), tt.res, tt.err)
+			if tt.wantErr != nil {
+				g.Expect(err).To(Equal(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res).To(Equal(tt.wantResult))
+		})
+	}
+}
+
+func TestReason(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Reason(nil)).To(BeEmpty())
+	g.Expect(Reason(pkgerrors.New("something went wrong"))).To(Equal(UnknownReason))
+	g.Expect(Reason(pkgerrors.Wrap(NewTerminalError("", pkgerrors.New("invalid")), "failed"))).To(Equal(TerminalReason))
+	g.Expect(Reason(NewTerminalError("InvalidConfiguration", pkgerrors.New("invalid")))).To(Equal("InvalidConfiguration"))
+	g.Expect(Reason(NewRequeueAfterError("", time.Minute, "waiting"))).To(Equal(RequeueAfterReason))
+	g.Expect(Reason(kerrors.NewAggregate([]error{NewExternalDependencyNotReadyError("DockerCluster default/cluster1", 0), pkgerrors.New("other")}))).To(Equal(ExternalDependencyNotReadyReason))
+
+	g.Expect(IsTerminal(kerrors.NewAggregate([]error{pkgerrors.New("other"), NewTerminalError("", pkgerrors.New("invalid"))}))).To(BeTrue())
+	g.Expect(IsTerminal(NewRequeueAfterError("", time.Minute, "waiting"))).To(BeFalse())
+}
@@ -40,7 +40,11 @@ func (f failureDomainAggregations) Len() int {
 
 // Less reports whether the element with
 // index i should sort before the element with index j.
+// Failure domains with the same count are sorted by id, so the failure domain picked is deterministic.
 func (f failureDomainAggregations) Less(i, j int) bool {
+	if f[i].count == f[j].count {
+		return f[i].id < f[j].id
+	}
 	return f[i].count < f[j].count
 }
 
@@ -18,6 +18,7 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/x509"
@@ -176,6 +177,58 @@ func NeedsClientCertRotation(configSecret *corev1.Secret, threshold time.Duratio
 	return false, nil
 }
 
+// NeedsEndpointUpdate returns whether the server in the Kubeconfig secret is different from the given endpoint,
+// e.g. because the control plane endpoint of the Cluster has been changed.
+func NeedsEndpointUpdate(configSecret *corev1.Secret, endpoint string) (bool, error) {
+	clusterName, _, err := secret.ParseSecretName(configSecret.Name)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse secret name")
+	}
+	data, err := toKubeconfigBytes(configSecret)
+	if err != nil {
+		return false, err
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+	}
+	cluster, ok := config.Clusters[clusterName]
+	if !ok {
+		return false, nil
+	}
+	return cluster.Server != fmt.Sprintf("https://%s", endpoint), nil
+}
+
+// NeedsClusterCAUpdate returns whether the certificate authority in the Kubeconfig secret is different from the
+// given cluster CA certificate, e.g. because the cluster CA has been rotated.
+func NeedsClusterCAUpdate(configSecret *corev1.Secret, caCert []byte) (bool, error) {
+	clusterName, _, err := secret.ParseSecretName(configSecret.Name)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse secret name")
+	}
+	data, err := toKubeconfigBytes(configSecret)
+	if err != nil {
+		return false, err
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+	}
+	cluster, ok := config.Clusters[clusterName]
+	if !ok {
+		return false, nil
+	}
+
+	// NOTE: the Kubeconfig is generated using the first certificate of the cluster CA, which is the one used for signing.
+	cert, err := certs.DecodeCertPEM(caCert)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to decode CA Cert")
+	}
+	return !bytes.Equal(cluster.CertificateAuthorityData, certs.EncodeCertPEM(cert)), nil
+}
+
 // RegenerateSecret creates and stores a new Kubeconfig in the given secret.
 func RegenerateSecret(ctx context.Context, c client.Client, configSecret *corev1.Secret) error {
 	clusterName, _, err := secret.ParseSecretName(configSecret.Name)
@@ -192,8 +245,22 @@ func RegenerateSecret(ctx context.Context, c client.Client, configSecret *corev1
 		return errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
 	}
 	endpoint := config.Clusters[clusterName].Server
+	return regenerateSecret(ctx, c, configSecret, clusterName, endpoint)
+}
+
+// RegenerateSecretWithEndpoint creates and stores a new Kubeconfig using the given endpoint in the given secret.
+func RegenerateSecretWithEndpoint(ctx context.Context, c client.Client, configSecret *corev1.Secret, endpoint string) error {
+	clusterName, _, err := secret.ParseSecretName(configSecret.Name)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse secret name")
+	}
+	server := fmt.Sprintf("https://%s", endpoint)
+	return regenerateSecret(ctx, c, configSecret, clusterName, server)
+}
+
+func regenerateSecret(ctx context.Context, c client.Client, configSecret *corev1.Secret, clusterName, server string) error {
 	key := client.ObjectKey{Name: clusterName, Namespace: configSecret.Namespace}
-	out, err := generateKubeconfig(ctx, c, key, endpoint)
+	out, err := generateKubeconfig(ctx, c, key, server)
 	if err != nil {
 		return err
 	}
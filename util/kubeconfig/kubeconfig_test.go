@@ -417,3 +417,85 @@ func TestRegenerateClientCerts(t *testing.T) {
 
 	g.Expect(newCert.NotAfter).To(BeTemporally(">", oldCert.NotAfter))
 }
+
+func TestNeedsEndpointUpdate(t *testing.T) {
+	g := NewWithT(t)
+
+	needsUpdate, err := NeedsEndpointUpdate(validSecret, "test-cluster-api:6443")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(needsUpdate).To(BeFalse())
+
+	needsUpdate, err = NeedsEndpointUpdate(validSecret, "new-cluster-api:6443")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(needsUpdate).To(BeTrue())
+}
+
+func TestRegenerateSecretWithEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-ca",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			secret.TLSKeyDataName: certs.EncodePrivateKeyPEM(caKey),
+			secret.TLSCrtDataName: certs.EncodeCertPEM(caCert),
+		},
+	}
+
+	configSecret := validSecret.DeepCopy()
+	c := fake.NewClientBuilder().WithObjects(configSecret, caSecret).Build()
+
+	g.Expect(RegenerateSecretWithEndpoint(ctx, c, configSecret, "new-cluster-api:6443")).To(Succeed())
+
+	newSecret := &corev1.Secret{}
+	g.Expect(c.Get(ctx, util.ObjectKey(validSecret), newSecret)).To(Succeed())
+	newConfig, err := clientcmd.Load(newSecret.Data[secret.KubeconfigDataName])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(newConfig.Clusters["test1"].Server).To(Equal("https://new-cluster-api:6443"))
+
+	needsUpdate, err := NeedsEndpointUpdate(newSecret, "new-cluster-api:6443")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(needsUpdate).To(BeFalse())
+}
+
+func TestNeedsClusterCAUpdate(t *testing.T) {
+	g := NewWithT(t)
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test1-ca",
+			Namespace: "test",
+		},
+		Data: map[string][]byte{
+			secret.TLSKeyDataName: certs.EncodePrivateKeyPEM(caKey),
+			secret.TLSCrtDataName: certs.EncodeCertPEM(caCert),
+		},
+	}
+
+	configSecret := validSecret.DeepCopy()
+	c := fake.NewClientBuilder().WithObjects(configSecret, caSecret).Build()
+
+	needsUpdate, err := NeedsClusterCAUpdate(configSecret, caSecret.Data[secret.TLSCrtDataName])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(needsUpdate).To(BeTrue())
+
+	g.Expect(RegenerateSecret(ctx, c, configSecret)).To(Succeed())
+
+	newSecret := &corev1.Secret{}
+	g.Expect(c.Get(ctx, util.ObjectKey(validSecret), newSecret)).To(Succeed())
+	needsUpdate, err = NeedsClusterCAUpdate(newSecret, caSecret.Data[secret.TLSCrtDataName])
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(needsUpdate).To(BeFalse())
+}
@@ -18,7 +18,12 @@ limitations under the License.
 package labels
 
 import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -29,6 +34,38 @@ func IsTopologyOwned(o metav1.Object) bool {
 	return ok
 }
 
+// GetTopologyClassName returns the name of the ClusterClass a topology owned object has been generated from,
+// as tracked by the `topology.cluster.x-k8s.io/class-name` label; an empty string is returned if the label is not set.
+func GetTopologyClassName(o metav1.Object) string {
+	return o.GetLabels()[clusterv1.ClusterTopologyClassNameLabel]
+}
+
+// IsTopologyOwnedByClass returns true if the object has the `topology.cluster.x-k8s.io/owned` label
+// and it has been generated from the ClusterClass with the given name.
+func IsTopologyOwnedByClass(o metav1.Object, className string) bool {
+	return IsTopologyOwned(o) && GetTopologyClassName(o) == className
+}
+
+// GetClusterProfile returns the profile, or tier, of a Cluster, as tracked by the `cluster.x-k8s.io/profile` label;
+// an empty string is returned if the label is not set.
+func GetClusterProfile(o metav1.Object) string {
+	return o.GetLabels()[clusterv1.ClusterProfileLabelName]
+}
+
+// ClusterProfileSelector returns a label selector matching the Clusters with any of the given profiles,
+// e.g. to be used as a ClusterResourceSet clusterSelector.
+func ClusterProfileSelector(profiles ...string) metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      clusterv1.ClusterProfileLabelName,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   profiles,
+			},
+		},
+	}
+}
+
 // HasWatchLabel returns true if the object has a label with the WatchLabel key matching the given value.
 func HasWatchLabel(o metav1.Object, labelValue string) bool {
 	val, ok := o.GetLabels()[clusterv1.WatchLabel]
@@ -37,3 +74,29 @@ func HasWatchLabel(o metav1.Object, labelValue string) bool {
 	}
 	return val == labelValue
 }
+
+// MustFormatValue returns the passed inputLabelValue if it meets the standards for a Kubernetes label value.
+// If the name is not a valid label value this function returns a hash which meets the requirements.
+func MustFormatValue(str string) string {
+	// a valid Kubernetes label value must:
+	// - be less than 64 characters long.
+	// - be an empty string OR consist of alphanumeric characters, '-', '_' or '.'.
+	// - start and end with an alphanumeric character
+	if len(validation.IsValidLabelValue(str)) == 0 {
+		return str
+	}
+	hasher := fnv.New32a()
+	_, err := hasher.Write([]byte(str))
+	if err != nil {
+		// At time of writing the implementation of fnv's Write function can never return an error.
+		// If this changes in a future go version this function will panic.
+		panic(err)
+	}
+	return fmt.Sprintf("hash_%s_z", base64.RawURLEncoding.EncodeToString(hasher.Sum(nil)))
+}
+
+// MustEqualValue returns true if the actualLabelValue equals either the inputLabelValue or the hashed
+// value of the inputLabelValue.
+func MustEqualValue(str, labelValue string) bool {
+	return labelValue == MustFormatValue(str)
+}
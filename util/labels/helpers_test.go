@@ -17,11 +17,14 @@ limitations under the License.
 package labels
 
 import (
+	"strings"
 	"testing"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
@@ -84,3 +87,111 @@ func TestHasWatchLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestIsTopologyOwnedByClass(t *testing.T) {
+	g := NewWithT(t)
+
+	var testcases = []struct {
+		name     string
+		obj      metav1.Object
+		input    string
+		expected bool
+	}{
+		{
+			name: "should return false if the object is not topology owned",
+			obj: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.ClusterTopologyClassNameLabel: "class1",
+					},
+				},
+			},
+			input:    "class1",
+			expected: false,
+		},
+		{
+			name: "should return false if the object is topology owned by another class",
+			obj: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.ClusterTopologyOwnedLabel:     "",
+						clusterv1.ClusterTopologyClassNameLabel: "class2",
+					},
+				},
+			},
+			input:    "class1",
+			expected: false,
+		},
+		{
+			name: "should return true if the object is topology owned by the class",
+			obj: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						clusterv1.ClusterTopologyOwnedLabel:     "",
+						clusterv1.ClusterTopologyClassNameLabel: "class1",
+					},
+				},
+			},
+			input:    "class1",
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := IsTopologyOwnedByClass(tc.obj, tc.input)
+			g.Expect(res).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestClusterProfile(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				clusterv1.ClusterProfileLabelName: "prod",
+			},
+		},
+	}
+	g.Expect(GetClusterProfile(cluster)).To(Equal("prod"))
+	g.Expect(GetClusterProfile(&clusterv1.Cluster{})).To(BeEmpty())
+
+	profileSelector := ClusterProfileSelector("stage", "prod")
+	selector, err := metav1.LabelSelectorAsSelector(&profileSelector)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(selector.Matches(labels.Set(cluster.Labels))).To(BeTrue())
+	g.Expect(selector.Matches(labels.Set{clusterv1.ClusterProfileLabelName: "dev"})).To(BeFalse())
+	g.Expect(selector.Matches(labels.Set{})).To(BeFalse())
+}
+
+func TestMustFormatValue(t *testing.T) {
+	longPoolName := strings.Repeat("a", 64)
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "should return the input if it is a valid label value",
+			input: "machine-pool-1",
+			want:  "machine-pool-1",
+		},
+		{
+			name:  "should return a hash if the input is longer than 63 characters",
+			input: longPoolName,
+			want:  "hash_2W8PhQ_z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := MustFormatValue(tt.input)
+			g.Expect(got).To(Equal(tt.want))
+			g.Expect(validation.IsValidLabelValue(got)).To(BeEmpty())
+			g.Expect(MustEqualValue(tt.input, got)).To(BeTrue())
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -32,6 +33,33 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// Cluster API controllers identify objects in log entries with a k/v pair using the Kind of the object as key and
+// a klog.ObjectRef as value, e.g. "Cluster"="ns1/cluster1"; this is the same convention used by controller runtime
+// for the object being reconciled, so log entries related to an object can be correlated across controllers.
+// In order to allow to drill down logs for related objects, at the beginning of each reconcile the logger should get
+// k/v pairs for the hierarchy of objects the reconciled object belongs to (e.g. Cluster, MachineDeployment, MachineSet)
+// and for the objects it references (e.g. the InfrastructureMachine of a Machine); the helpers below can be used for this.
+
+// AddCluster adds the Cluster with the given namespace and name as k/v pair to the logger in ctx.
+func AddCluster(ctx context.Context, namespace, name string) (context.Context, logr.Logger) {
+	return AddValues(ctx, "Cluster", klog.KRef(namespace, name))
+}
+
+// AddObjectRef adds the object referenced by ref as k/v pair to the logger in ctx, using the Kind of the referenced
+// object as key, e.g. "DockerMachine"="ns1/machine1". If ref is nil the logger is not changed.
+func AddObjectRef(ctx context.Context, ref *corev1.ObjectReference) (context.Context, logr.Logger) {
+	if ref == nil || ref.Kind == "" {
+		return ctx, ctrl.LoggerFrom(ctx)
+	}
+	return AddValues(ctx, ref.Kind, klog.KRef(ref.Namespace, ref.Name))
+}
+
+// AddValues adds the given k/v pairs to the logger in ctx.
+func AddValues(ctx context.Context, keysAndValues ...interface{}) (context.Context, logr.Logger) {
+	log := ctrl.LoggerFrom(ctx).WithValues(keysAndValues...)
+	return ctrl.LoggerInto(ctx, log), log
+}
+
 // AddOwners adds the owners of an Object based on OwnerReferences as k/v pairs to the logger in ctx.
 // Note: If an owner is a MachineSet we also add the owners from the MachineSet OwnerReferences.
 func AddOwners(ctx context.Context, c client.Client, obj metav1.Object) (context.Context, logr.Logger, error) {
@@ -22,6 +22,7 @@ import (
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -201,6 +202,55 @@ func Test_AddObjectHierarchy(t *testing.T) {
 	}
 }
 
+func Test_AddCluster(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := ctrl.LoggerInto(context.Background(), logr.New(fakeLogSink{}))
+
+	_, logger := AddCluster(ctx, metav1.NamespaceDefault, "development-3961")
+	g.Expect(logger.GetSink().(fakeLogSink).keysAndValues).To(Equal([]interface{}{
+		"Cluster",
+		klog.ObjectRef{Namespace: metav1.NamespaceDefault, Name: "development-3961"},
+	}))
+}
+
+func Test_AddObjectRef(t *testing.T) {
+	tests := []struct {
+		name                  string
+		ref                   *corev1.ObjectReference
+		expectedKeysAndValues []interface{}
+	}{
+		{
+			name:                  "nil ref is not added",
+			ref:                   nil,
+			expectedKeysAndValues: nil,
+		},
+		{
+			name: "ref is added using its Kind as key",
+			ref: &corev1.ObjectReference{
+				APIVersion: "infrastructure.cluster.x-k8s.io/v1beta1",
+				Kind:       "DockerMachine",
+				Namespace:  metav1.NamespaceDefault,
+				Name:       "development-3961-4flkb-gzxnb",
+			},
+			expectedKeysAndValues: []interface{}{
+				"DockerMachine",
+				klog.ObjectRef{Namespace: metav1.NamespaceDefault, Name: "development-3961-4flkb-gzxnb"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := ctrl.LoggerInto(context.Background(), logr.New(fakeLogSink{}))
+
+			_, logger := AddObjectRef(ctx, tt.ref)
+			g.Expect(logger.GetSink().(fakeLogSink).keysAndValues).To(Equal(tt.expectedKeysAndValues))
+		})
+	}
+}
+
 type fakeLogSink struct {
 	// Embedding NullLogSink so we don't have to implement all funcs
 	// of the LogSink interface.
@@ -0,0 +1,36 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(patchConflictsTotal)
+}
+
+// patchConflictsTotal reports the conflicts hit by the patch helper when patching with WithRetryOnConflict.
+// NOTE: Given that each controller patches the objects it reconciles, the kind identifies the controller hitting
+// the conflicts in most cases.
+var patchConflictsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Subsystem: "capi_patch_helper",
+	Name:      "conflicts_total",
+	Help:      "Number of conflicts hit when patching objects, partitioned by group and kind of the object.",
+}, []string{"group", "kind"})
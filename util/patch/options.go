@@ -37,6 +37,33 @@ type HelperOptions struct {
 	// OwnedConditions defines condition types owned by the controller.
 	// In case of conflicts for the owned conditions, the patch helper will always use the value provided by the controller.
 	OwnedConditions []clusterv1.ConditionType
+
+	// Mode defines which parts of the object are patched; by default metadata, spec and status are patched.
+	Mode Mode
+
+	// RetryOnConflict issues the spec and status patches with optimistic locking; in case of conflicts the
+	// patch helper gets the latest version of the object and patches it again, unless the fields being
+	// patched have been changed concurrently.
+	RetryOnConflict bool
+}
+
+// Mode defines which parts of an object are patched by the patch helper.
+type Mode string
+
+const (
+	// PatchAll patches metadata, spec and status of the object.
+	PatchAll Mode = ""
+
+	// PatchSpecOnly patches only metadata and spec of the object; status, including conditions, is not patched.
+	PatchSpecOnly Mode = "SpecOnly"
+
+	// PatchStatusOnly patches only the status of the object, including conditions; metadata and spec are not patched.
+	PatchStatusOnly Mode = "StatusOnly"
+)
+
+// ApplyToHelper applies this configuration to the given HelperOptions.
+func (m Mode) ApplyToHelper(in *HelperOptions) {
+	in.Mode = m
 }
 
 // WithForceOverwriteConditions allows the patch helper to overwrite conditions in case of conflicts.
@@ -67,3 +94,13 @@ type WithOwnedConditions struct {
 func (w WithOwnedConditions) ApplyToHelper(in *HelperOptions) {
 	in.OwnedConditions = w.Conditions
 }
+
+// WithRetryOnConflict issues the spec and status patches with optimistic locking; in case of conflicts the
+// patch helper gets the latest version of the object and patches it again, unless the fields being
+// patched have been changed concurrently.
+type WithRetryOnConflict struct{}
+
+// ApplyToHelper applies this configuration to the given HelperOptions.
+func (w WithRetryOnConflict) ApplyToHelper(in *HelperOptions) {
+	in.RetryOnConflict = true
+}
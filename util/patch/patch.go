@@ -19,6 +19,7 @@ package patch
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -26,16 +27,25 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 
-	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
+// conflictBackoff is the backoff used to handle conflicts between controllers working on the same object.
+//
+// This has been copied from https://github.com/kubernetes/kubernetes/blob/release-1.16/pkg/controller/controller_utils.go#L86-L88.
+var conflictBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 100 * time.Millisecond,
+	Jitter:   1.0,
+}
+
 // Helper is a utility for ensuring the proper patching of objects.
 type Helper struct {
 	client       client.Client
@@ -46,6 +56,11 @@ type Helper struct {
 	changes      map[string]bool
 
 	isConditionsSetter bool
+
+	// resourceVersion is the resourceVersion used for patches with optimistic locking; it is initialized
+	// with the resourceVersion of the before object, and it is moved forward by the patches issued by the
+	// helper, as long as no one else changed the object in the meantime.
+	resourceVersion string
 }
 
 // NewHelper returns an initialized Helper.
@@ -125,6 +140,7 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 
 	// Calculate and store the top-level field changes (e.g. "metadata", "spec", "status") we have before/after.
 	h.changes, err = h.calculateChanges(obj)
+	h.resourceVersion = h.beforeObject.GetResourceVersion()
 	if err != nil {
 		return err
 	}
@@ -136,16 +152,19 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 		// Given that we pass in metadata.resourceVersion to perform a 3-way-merge conflict resolution,
 		// patching conditions first avoids an extra loop if spec or status patch succeeds first
 		// given that causes the resourceVersion to mutate.
-		h.patchStatusConditions(ctx, obj, options.ForceOverwriteConditions, options.OwnedConditions),
+		h.patchStatusConditions(ctx, obj, options),
 
 		// Then proceed to patch the rest of the object.
-		h.patch(ctx, obj),
-		h.patchStatus(ctx, obj),
+		h.patch(ctx, obj, options),
+		h.patchStatus(ctx, obj, options),
 	})
 }
 
 // patch issues a patch for metadata and spec.
-func (h *Helper) patch(ctx context.Context, obj client.Object) error {
+func (h *Helper) patch(ctx context.Context, obj client.Object, options *HelperOptions) error {
+	if options.Mode == PatchStatusOnly {
+		return nil
+	}
 	if !h.shouldPatch("metadata") && !h.shouldPatch("spec") {
 		return nil
 	}
@@ -153,11 +172,17 @@ func (h *Helper) patch(ctx context.Context, obj client.Object) error {
 	if err != nil {
 		return err
 	}
+	if options.RetryOnConflict {
+		return h.patchWithRetryOnConflict(ctx, beforeObject, afterObject, specPatch, h.client.Patch)
+	}
 	return h.client.Patch(ctx, afterObject, client.MergeFrom(beforeObject))
 }
 
 // patchStatus issues a patch if the status has changed.
-func (h *Helper) patchStatus(ctx context.Context, obj client.Object) error {
+func (h *Helper) patchStatus(ctx context.Context, obj client.Object, options *HelperOptions) error {
+	if options.Mode == PatchSpecOnly {
+		return nil
+	}
 	if !h.shouldPatch("status") {
 		return nil
 	}
@@ -165,9 +190,82 @@ func (h *Helper) patchStatus(ctx context.Context, obj client.Object) error {
 	if err != nil {
 		return err
 	}
+	if options.RetryOnConflict {
+		return h.patchWithRetryOnConflict(ctx, beforeObject, afterObject, statusPatch, h.client.Status().Patch)
+	}
 	return h.client.Status().Patch(ctx, afterObject, client.MergeFrom(beforeObject))
 }
 
+// patchWithRetryOnConflict issues a merge patch with optimistic locking for the changes between the before and after objects.
+//
+// This method has an internal backoff loop. When a conflict is detected, the method asks the Client for
+// a new version of the object we're trying to patch; if none of the fields changed by the patch has been
+// changed concurrently, the patch is sent again using the resourceVersion of the latest object, otherwise
+// the conflict is returned.
+func (h *Helper) patchWithRetryOnConflict(ctx context.Context, beforeObject, afterObject client.Object, focus patchType, patchFunc func(context.Context, client.Object, client.Patch, ...client.PatchOption) error) error {
+	changes, err := mergePatchData(beforeObject, afterObject)
+	if err != nil {
+		return err
+	}
+
+	key := client.ObjectKeyFromObject(afterObject)
+	resourceVersion := h.resourceVersion
+
+	var conflictErr error
+	err = wait.ExponentialBackoff(conflictBackoff, func() (bool, error) {
+		data, err := json.Marshal(withResourceVersion(changes, resourceVersion))
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to marshal patch data")
+		}
+
+		patched := afterObject.DeepCopyObject().(client.Object)
+		err = patchFunc(ctx, patched, client.RawPatch(types.MergePatchType, data))
+		switch {
+		case apierrors.IsConflict(err):
+			conflictErr = err
+			patchConflictsTotal.WithLabelValues(h.gvk.Group, h.gvk.Kind).Inc()
+		case err != nil:
+			return false, err
+		default:
+			// If the patch succeeded at the first attempt, no one else changed the object, so
+			// the following patches can use the resourceVersion of the patched object.
+			if conflictErr == nil {
+				h.resourceVersion = patched.GetResourceVersion()
+			}
+			return true, nil
+		}
+
+		// Get a new copy of the object.
+		latest := h.beforeObject.DeepCopyObject().(client.Object)
+		if err := h.client.Get(ctx, key, latest); err != nil {
+			return false, err
+		}
+		latestUnstructured, err := toUnstructured(latest)
+		if err != nil {
+			return false, err
+		}
+
+		// Check if any of the fields changed by the patch has been changed concurrently; if so, the conflict can't be resolved.
+		concurrentChanges, err := mergePatchData(
+			unsafeUnstructuredCopy(h.before, focus, h.isConditionsSetter),
+			unsafeUnstructuredCopy(latestUnstructured, focus, h.isConditionsSetter),
+		)
+		if err != nil {
+			return false, err
+		}
+		if path := overlappingPath(changes, concurrentChanges, nil); path != nil {
+			return false, errors.Wrapf(conflictErr, "field %s has been changed concurrently", strings.Join(path, "."))
+		}
+
+		resourceVersion = latest.GetResourceVersion()
+		return false, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return errors.Wrapf(conflictErr, "failed to patch %s %s after %d attempts", h.gvk.Kind, key, conflictBackoff.Steps)
+	}
+	return err
+}
+
 // patchStatusConditions issues a patch if there are any changes to the conditions slice under
 // the status subresource. This is a special case and it's handled separately given that
 // we allow different controllers to act on conditions of the same object.
@@ -177,9 +275,9 @@ func (h *Helper) patchStatus(ctx context.Context, obj client.Object) error {
 //
 // Condition changes are then applied to the latest version of the object, and if there are
 // no unresolvable conflicts, the patch is sent again.
-func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, forceOverwrite bool, ownedConditions []clusterv1.ConditionType) error {
-	// Nothing to do if the object isn't a condition patcher.
-	if !h.isConditionsSetter {
+func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, options *HelperOptions) error {
+	// Nothing to do if the object isn't a condition patcher, or if status must not be patched.
+	if !h.isConditionsSetter || options.Mode == PatchSpecOnly {
 		return nil
 	}
 
@@ -211,18 +309,8 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 	// Make a copy of the object and store the key used if we have conflicts.
 	key := client.ObjectKeyFromObject(after)
 
-	// Define and start a backoff loop to handle conflicts
-	// between controllers working on the same object.
-	//
-	// This has been copied from https://github.com/kubernetes/kubernetes/blob/release-1.16/pkg/controller/controller_utils.go#L86-L88.
-	backoff := wait.Backoff{
-		Steps:    5,
-		Duration: 100 * time.Millisecond,
-		Jitter:   1.0,
-	}
-
 	// Start the backoff loop and return errors if any.
-	return wait.ExponentialBackoff(backoff, func() (bool, error) {
+	return wait.ExponentialBackoff(conflictBackoff, func() (bool, error) {
 		latest, ok := before.DeepCopyObject().(conditions.Setter)
 		if !ok {
 			return false, errors.Errorf("object %s doesn't satisfy conditions.Setter, cannot patch", latest.GetObjectKind())
@@ -237,11 +325,12 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 		conditionsPatch := client.MergeFromWithOptions(latest.DeepCopyObject().(conditions.Setter), client.MergeFromWithOptimisticLock{})
 
 		// Set the condition patch previously created on the new object.
-		if err := diff.Apply(latest, conditions.WithForceOverwrite(forceOverwrite), conditions.WithOwnedConditions(ownedConditions...)); err != nil {
+		if err := diff.Apply(latest, conditions.WithForceOverwrite(options.ForceOverwriteConditions), conditions.WithOwnedConditions(options.OwnedConditions...)); err != nil {
 			return false, err
 		}
 
 		// Issue the patch.
+		latestResourceVersion := latest.GetResourceVersion()
 		err := h.client.Status().Patch(ctx, latest, conditionsPatch)
 		switch {
 		case apierrors.IsConflict(err):
@@ -250,6 +339,10 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 		case err != nil:
 			return false, err
 		default:
+			// If no one else changed the object, the following patches can use the resourceVersion of the patched object.
+			if latestResourceVersion == h.resourceVersion {
+				h.resourceVersion = latest.GetResourceVersion()
+			}
 			return true, nil
 		}
 	})
@@ -22,13 +22,16 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
@@ -918,3 +921,158 @@ func TestNewHelperNil(t *testing.T) {
 	_, err = NewHelper(nil, nil)
 	g.Expect(err).NotTo(BeNil())
 }
+
+func TestPatchHelperModes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "machine-1",
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster-1",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		mode           Mode
+		wantSpec       bool
+		wantStatus     bool
+		wantConditions bool
+	}{
+		{
+			name:           "PatchAll patches spec and status",
+			mode:           PatchAll,
+			wantSpec:       true,
+			wantStatus:     true,
+			wantConditions: true,
+		},
+		{
+			name:     "PatchSpecOnly patches spec",
+			mode:     PatchSpecOnly,
+			wantSpec: true,
+		},
+		{
+			name:           "PatchStatusOnly patches status and conditions",
+			mode:           PatchStatusOnly,
+			wantStatus:     true,
+			wantConditions: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine.DeepCopy()).Build()
+
+			obj := &clusterv1.Machine{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), obj)).To(Succeed())
+
+			patcher, err := NewHelper(obj, c)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			obj.Spec.Version = pointer.String("v1.25.0")
+			obj.Status.Phase = string(clusterv1.MachinePhaseRunning)
+			conditions.MarkTrue(obj, clusterv1.ReadyCondition)
+
+			g.Expect(patcher.Patch(ctx, obj, tt.mode)).To(Succeed())
+
+			got := &clusterv1.Machine{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), got)).To(Succeed())
+			g.Expect(got.Spec.Version != nil).To(Equal(tt.wantSpec))
+			g.Expect(got.Status.Phase != "").To(Equal(tt.wantStatus))
+			g.Expect(conditions.Has(got, clusterv1.ReadyCondition)).To(Equal(tt.wantConditions))
+		})
+	}
+}
+
+func TestPatchHelperRetryOnConflict(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clusterv1.AddToScheme(scheme)
+
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "machine-1",
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: "cluster-1",
+		},
+	}
+
+	t.Run("should retry the patch if the object has been changed concurrently on other fields", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine.DeepCopy()).Build()
+
+		obj := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), obj)).To(Succeed())
+
+		patcher, err := NewHelper(obj, c)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		// Change the object concurrently.
+		concurrent := obj.DeepCopy()
+		concurrent.Labels = map[string]string{"foo": "bar"}
+		g.Expect(c.Update(ctx, concurrent)).To(Succeed())
+
+		obj.Spec.Version = pointer.String("v1.25.0")
+		g.Expect(patcher.Patch(ctx, obj, WithRetryOnConflict{})).To(Succeed())
+
+		got := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), got)).To(Succeed())
+		g.Expect(got.Labels).To(HaveKeyWithValue("foo", "bar"))
+		g.Expect(got.Spec.Version).To(Equal(pointer.String("v1.25.0")))
+	})
+
+	t.Run("should not hit conflicts when patching metadata, spec, status and conditions", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine.DeepCopy()).Build()
+
+		obj := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), obj)).To(Succeed())
+
+		patcher, err := NewHelper(obj, c)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		conflicts := testutil.ToFloat64(patchConflictsTotal.WithLabelValues(clusterv1.GroupVersion.Group, "Machine"))
+
+		obj.Labels = map[string]string{"foo": "bar"}
+		obj.Spec.Version = pointer.String("v1.25.0")
+		obj.Status.Phase = string(clusterv1.MachinePhaseRunning)
+		conditions.MarkTrue(obj, clusterv1.ReadyCondition)
+		g.Expect(patcher.Patch(ctx, obj, WithRetryOnConflict{})).To(Succeed())
+
+		g.Expect(testutil.ToFloat64(patchConflictsTotal.WithLabelValues(clusterv1.GroupVersion.Group, "Machine"))).To(Equal(conflicts))
+	})
+
+	t.Run("should return a conflict if the patched fields have been changed concurrently", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(machine.DeepCopy()).Build()
+
+		obj := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), obj)).To(Succeed())
+
+		patcher, err := NewHelper(obj, c)
+		g.Expect(err).NotTo(HaveOccurred())
+
+		// Change the same field concurrently.
+		concurrent := obj.DeepCopy()
+		concurrent.Spec.Version = pointer.String("v1.24.0")
+		g.Expect(c.Update(ctx, concurrent)).To(Succeed())
+
+		obj.Spec.Version = pointer.String("v1.25.0")
+		err = patcher.Patch(ctx, obj, WithRetryOnConflict{})
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("field spec.version has been changed concurrently"))
+
+		got := &clusterv1.Machine{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(machine), got)).To(Succeed())
+		g.Expect(got.Spec.Version).To(Equal(pointer.String("v1.24.0")))
+	})
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package patch
 
 import (
+	"encoding/json"
 	"strings"
 
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type patchType string
@@ -95,3 +98,54 @@ func unsafeUnstructuredCopy(obj *unstructured.Unstructured, focus patchType, isC
 
 	return res
 }
+
+// mergePatchData returns the JSON merge patch between the before and after objects as a map.
+func mergePatchData(before, after client.Object) (map[string]interface{}, error) {
+	data, err := client.MergeFrom(before).Data(after)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to calculate patch data")
+	}
+	res := map[string]interface{}{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal patch data into a map")
+	}
+	return res, nil
+}
+
+// withResourceVersion returns a shallow copy of the given JSON merge patch with metadata.resourceVersion set,
+// so the API server rejects the patch if the object has been changed in the meantime.
+func withResourceVersion(patch map[string]interface{}, resourceVersion string) map[string]interface{} {
+	res := make(map[string]interface{}, len(patch)+1)
+	for k, v := range patch {
+		res[k] = v
+	}
+	metadata := map[string]interface{}{}
+	if m, ok := patch["metadata"].(map[string]interface{}); ok {
+		for k, v := range m {
+			metadata[k] = v
+		}
+	}
+	metadata["resourceVersion"] = resourceVersion
+	res["metadata"] = metadata
+	return res
+}
+
+// overlappingPath returns the path of the first field changed by both the given JSON merge patches, if any.
+func overlappingPath(a, b map[string]interface{}, path []string) []string {
+	for key, aValue := range a {
+		bValue, ok := b[key]
+		if !ok {
+			continue
+		}
+		fieldPath := append(append([]string{}, path...), key)
+		aMap, aIsMap := aValue.(map[string]interface{})
+		bMap, bIsMap := bValue.(map[string]interface{})
+		if !aIsMap || !bIsMap {
+			return fieldPath
+		}
+		if p := overlappingPath(aMap, bMap, fieldPath); p != nil {
+			return p
+		}
+	}
+	return nil
+}
@@ -225,3 +225,54 @@ func TestUnsafeFocusedUnstructured(t *testing.T) {
 		g.Expect(obj.Object["status"].(map[string]interface{})["conditions"]).ToNot(BeNil())
 	})
 }
+
+func TestOverlappingPath(t *testing.T) {
+	tests := []struct {
+		name string
+		a    map[string]interface{}
+		b    map[string]interface{}
+		want []string
+	}{
+		{
+			name: "No overlap on different fields",
+			a:    map[string]interface{}{"spec": map[string]interface{}{"foo": "a"}},
+			b:    map[string]interface{}{"spec": map[string]interface{}{"bar": "b"}, "metadata": map[string]interface{}{"resourceVersion": "2"}},
+			want: nil,
+		},
+		{
+			name: "Overlap on the same field",
+			a:    map[string]interface{}{"spec": map[string]interface{}{"foo": "a"}},
+			b:    map[string]interface{}{"spec": map[string]interface{}{"foo": "b"}},
+			want: []string{"spec", "foo"},
+		},
+		{
+			name: "Overlap when a field is replaced by a value",
+			a:    map[string]interface{}{"spec": map[string]interface{}{"foo": map[string]interface{}{"bar": "a"}}},
+			b:    map[string]interface{}{"spec": map[string]interface{}{"foo": nil}},
+			want: []string{"spec", "foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(overlappingPath(tt.a, tt.b, nil)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestWithResourceVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"foo": "bar"}},
+		"spec":     map[string]interface{}{"foo": "a"},
+	}
+	got := withResourceVersion(patch, "2")
+	g.Expect(got).To(Equal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"foo": "bar"}, "resourceVersion": "2"},
+		"spec":     map[string]interface{}{"foo": "a"},
+	}))
+	// The original patch must not be changed.
+	g.Expect(patch["metadata"]).ToNot(HaveKey("resourceVersion"))
+}
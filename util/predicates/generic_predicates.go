@@ -262,6 +262,36 @@ func ResourceIsTopologyOwned(logger logr.Logger) predicate.Funcs {
 	}
 }
 
+// ResourceIsTopologyOwnedByClass returns a predicate that returns true only if the resource has
+// the `topology.cluster.x-k8s.io/owned` label and it has been generated from the ClusterClass with the given name.
+func ResourceIsTopologyOwnedByClass(logger logr.Logger, className string) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return processIfTopologyOwnedByClass(logger.WithValues("predicate", "ResourceIsTopologyOwnedByClass", "eventType", "update"), e.ObjectNew, className)
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return processIfTopologyOwnedByClass(logger.WithValues("predicate", "ResourceIsTopologyOwnedByClass", "eventType", "create"), e.Object, className)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return processIfTopologyOwnedByClass(logger.WithValues("predicate", "ResourceIsTopologyOwnedByClass", "eventType", "delete"), e.Object, className)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return processIfTopologyOwnedByClass(logger.WithValues("predicate", "ResourceIsTopologyOwnedByClass", "eventType", "generic"), e.Object, className)
+		},
+	}
+}
+
+func processIfTopologyOwnedByClass(logger logr.Logger, obj client.Object, className string) bool {
+	kind := strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind)
+	log := logger.WithValues("namespace", obj.GetNamespace(), kind, obj.GetName(), "clusterClass", className)
+	if labels.IsTopologyOwnedByClass(obj, className) {
+		log.V(6).Info("Resource is topology owned by the ClusterClass, will attempt to map resource")
+		return true
+	}
+	log.V(6).Info("Resource is not topology owned by the ClusterClass, will not attempt to map resource")
+	return false
+}
+
 func processIfTopologyOwned(logger logr.Logger, obj client.Object) bool {
 	kind := strings.ToLower(obj.GetObjectKind().GroupVersionKind().Kind)
 	log := logger.WithValues("namespace", obj.GetNamespace(), kind, obj.GetName())
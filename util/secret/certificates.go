@@ -18,9 +18,11 @@ package secret
 
 import (
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/hex"
@@ -34,6 +36,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -57,6 +60,9 @@ var (
 
 	// ErrMissingKey is an error indicating the key file is missing from the certificate.
 	ErrMissingKey = errors.New("missing key data")
+
+	// ErrInvalidCertificate is an error indicating a certificate provided by the user is not valid.
+	ErrInvalidCertificate = errors.New("invalid certificate")
 )
 
 // Certificates are the certificates necessary to bootstrap a cluster.
@@ -234,6 +240,20 @@ func (c Certificates) EnsureAllExist() error {
 	return nil
 }
 
+// Validate validates the certificates provided by the user, i.e. the certificates which have been looked up
+// and not generated, so errors in secrets created out of band are surfaced before they are used to bootstrap machines.
+func (c Certificates) Validate() error {
+	for _, certificate := range c {
+		if certificate.KeyPair == nil || certificate.Generated {
+			continue
+		}
+		if err := certificate.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Generate will generate any certificates that do not have KeyPair data.
 func (c Certificates) Generate() error {
 	for _, certificate := range c {
@@ -268,6 +288,11 @@ func (c Certificates) LookupOrGenerate(ctx context.Context, ctrlclient client.Cl
 		return err
 	}
 
+	// Validate the certificates that exist, which could have been provided by the user
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
 	// Generate the certificates that don't exist
 	if err := c.Generate(); err != nil {
 		return err
@@ -286,6 +311,60 @@ type Certificate struct {
 	CertFile, KeyFile string
 }
 
+// Validate validates the certificate data.
+// CA certificates can be provided as a bundle, e.g. while rotating a CA: the first certificate in the bundle is the
+// one matching the key and used for signing, while the other certificates are only trusted.
+func (c *Certificate) Validate() error {
+	if c.KeyPair == nil {
+		return ErrMissingCertificate
+	}
+
+	if c.Purpose == ServiceAccount {
+		return c.validateServiceAccountKeys()
+	}
+
+	certificates, err := cert.ParseCertsPEM(c.KeyPair.Cert)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidCertificate, "failed to parse %s certificate: %v", c.Purpose, err)
+	}
+	now := time.Now()
+	if now.Before(certificates[0].NotBefore) || now.After(certificates[0].NotAfter) {
+		return errors.Wrapf(ErrInvalidCertificate, "%s certificate is valid only from %s to %s", c.Purpose, certificates[0].NotBefore.UTC().Format(time.RFC3339), certificates[0].NotAfter.UTC().Format(time.RFC3339))
+	}
+	if c.Purpose != APIServerEtcdClient {
+		for _, certificate := range certificates {
+			if !certificate.IsCA {
+				return errors.Wrapf(ErrInvalidCertificate, "%s certificate %q is not a CA certificate", c.Purpose, certificate.Subject.CommonName)
+			}
+		}
+	}
+
+	// The key could be missing for external certificates; EnsureAllExist checks the key exists when required.
+	if len(c.KeyPair.Key) == 0 {
+		return nil
+	}
+	if _, err := tls.X509KeyPair(c.KeyPair.Cert, c.KeyPair.Key); err != nil {
+		return errors.Wrapf(ErrInvalidCertificate, "%s key does not match the certificate: %v", c.Purpose, err)
+	}
+	return nil
+}
+
+// validateServiceAccountKeys validates the service account key pair, which is made of a public and a private key.
+func (c *Certificate) validateServiceAccountKeys() error {
+	privateKey, err := certs.DecodePrivateKeyPEM(c.KeyPair.Key)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidCertificate, "failed to parse %s private key: %v", c.Purpose, err)
+	}
+	publicKeys, err := keyutil.ParsePublicKeysPEM(c.KeyPair.Cert)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidCertificate, "failed to parse %s public key: %v", c.Purpose, err)
+	}
+	if publicKey, ok := publicKeys[0].(interface{ Equal(crypto.PublicKey) bool }); !ok || !publicKey.Equal(privateKey.Public()) {
+		return errors.Wrapf(ErrInvalidCertificate, "%s public key does not match the private key", c.Purpose)
+	}
+	return nil
+}
+
 // Hashes hashes all the certificates stored in a CA certificate.
 func (c *Certificate) Hashes() ([]string, error) {
 	certificates, err := cert.ParseCertsPEM(c.KeyPair.Cert)
@@ -17,11 +17,20 @@ limitations under the License.
 package secret_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
 
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/certs"
 	"sigs.k8s.io/cluster-api/util/secret"
 )
 
@@ -45,3 +54,137 @@ func TestNewControlPlaneJoinCertsExternal(t *testing.T) {
 	certs := secret.NewControlPlaneJoinCerts(config)
 	g.Expect(certs.GetByPurpose(secret.EtcdCA).KeyFile).To(BeEmpty())
 }
+
+func TestCertificateValidate(t *testing.T) {
+	caKey, err := certs.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := certs.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCert := func(isCA bool, notAfter time.Time) []byte {
+		tmpl := x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "test"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              notAfter,
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+			BasicConstraintsValid: true,
+			IsCA:                  isCA,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, caKey.Public(), caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	}
+	caCert := newCert(true, time.Now().Add(time.Hour))
+	caKeyPEM := certs.EncodePrivateKeyPEM(caKey)
+	publicKeyPEM := func(key *rsa.PrivateKey) []byte {
+		out, err := certs.EncodePublicKeyPEM(&key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		purpose secret.Purpose
+		cert    []byte
+		key     []byte
+		wantErr bool
+	}{
+		{
+			name:    "Valid CA",
+			purpose: secret.ClusterCA,
+			cert:    caCert,
+			key:     caKeyPEM,
+		},
+		{
+			name:    "Valid CA bundle, e.g. while rotating a CA",
+			purpose: secret.ClusterCA,
+			cert:    append(append([]byte{}, caCert...), newCert(true, time.Now().Add(2*time.Hour))...),
+			key:     caKeyPEM,
+		},
+		{
+			name:    "Valid CA without key, e.g. for an external etcd",
+			purpose: secret.EtcdCA,
+			cert:    caCert,
+		},
+		{
+			name:    "Invalid CA data",
+			purpose: secret.ClusterCA,
+			cert:    []byte("hello world"),
+			key:     caKeyPEM,
+			wantErr: true,
+		},
+		{
+			name:    "Expired CA",
+			purpose: secret.FrontProxyCA,
+			cert:    newCert(true, time.Now().Add(-time.Minute)),
+			key:     caKeyPEM,
+			wantErr: true,
+		},
+		{
+			name:    "Not a CA",
+			purpose: secret.EtcdCA,
+			cert:    newCert(false, time.Now().Add(time.Hour)),
+			key:     caKeyPEM,
+			wantErr: true,
+		},
+		{
+			name:    "CA not matching the key",
+			purpose: secret.ClusterCA,
+			cert:    caCert,
+			key:     certs.EncodePrivateKeyPEM(otherKey),
+			wantErr: true,
+		},
+		{
+			name:    "Valid service account keys",
+			purpose: secret.ServiceAccount,
+			cert:    publicKeyPEM(caKey),
+			key:     caKeyPEM,
+		},
+		{
+			name:    "Service account public key not matching the private key",
+			purpose: secret.ServiceAccount,
+			cert:    publicKeyPEM(otherKey),
+			key:     caKeyPEM,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &secret.Certificate{
+				Purpose: tt.purpose,
+				KeyPair: &certs.KeyPair{Cert: tt.cert, Key: tt.key},
+			}
+			err := c.Validate()
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(errors.Is(err, secret.ErrInvalidCertificate)).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestCertificatesValidateSkipsGeneratedCertificates(t *testing.T) {
+	g := NewWithT(t)
+
+	certificates := secret.NewCertificatesForInitialControlPlane(nil)
+	g.Expect(certificates.Generate()).To(Succeed())
+	g.Expect(certificates.Validate()).To(Succeed())
+
+	certificates.GetByPurpose(secret.ClusterCA).KeyPair.Cert = []byte("hello world")
+	g.Expect(certificates.Validate()).To(Succeed())
+
+	certificates.GetByPurpose(secret.ClusterCA).Generated = false
+	g.Expect(certificates.Validate()).NotTo(Succeed())
+}
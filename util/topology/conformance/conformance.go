@@ -0,0 +1,329 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	utilcontract "sigs.k8s.io/cluster-api/util/contract"
+)
+
+const (
+	// conformanceClusterName is the name of the Cluster used when generating objects from templates.
+	conformanceClusterName = "conformance"
+
+	// conformanceVersion is the Kubernetes version set on control plane objects generated from templates.
+	conformanceVersion = "v1.25.0"
+)
+
+// ControlPlaneOptions defines the optional parts of the control plane contract implemented by a provider.
+type ControlPlaneOptions struct {
+	// Replicas must be true if the control plane supports replicas, e.g. if the Cluster topology can set
+	// the number of control plane replicas.
+	Replicas bool
+
+	// Machines must be true if the control plane uses Machines, i.e. if the ClusterClass defines the
+	// control plane machine infrastructure.
+	Machines bool
+}
+
+// schemaField is a field that must be defined in a CRD schema.
+type schemaField struct {
+	path contract.Path
+	typ  string
+}
+
+// ValidateTemplateCRD validates that the CRD of a template referenced by a ClusterClass, e.g. an
+// InfrastructureMachineTemplate, satisfies the contract required by the topology controller:
+//   - the CRD name must have the format produced by contract.CalculateCRDName,
+//   - the CRD must have the Cluster API contract version label, and all the versions in the label must be served,
+//   - the kind must have the Template suffix,
+//   - all the versions in the label must define spec.template.spec, which is cloned into the objects
+//     generated from the template; this allows the topology controller to rotate templates.
+func ValidateTemplateCRD(crd *apiextensionsv1.CustomResourceDefinition) field.ErrorList {
+	allErrs := validateCRD(crd)
+	if !strings.HasSuffix(crd.Spec.Names.Kind, clusterv1.TemplateSuffix) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "names", "kind"), crd.Spec.Names.Kind,
+			fmt.Sprintf("kind must have the %q suffix", clusterv1.TemplateSuffix)))
+	}
+	allErrs = append(allErrs, validateCRDFields(crd, []schemaField{
+		{path: contract.Path{"spec", "template", "spec"}, typ: "object"},
+	})...)
+	return allErrs
+}
+
+// ValidateInfrastructureClusterCRD validates that the CRD of an InfrastructureCluster satisfies the
+// contract required by the topology controller, e.g. that it defines spec.controlPlaneEndpoint.
+func ValidateInfrastructureClusterCRD(crd *apiextensionsv1.CustomResourceDefinition) field.ErrorList {
+	allErrs := validateCRD(crd)
+	allErrs = append(allErrs, validateCRDFields(crd, []schemaField{
+		{path: contract.InfrastructureCluster().ControlPlaneEndpoint().Host().Path(), typ: "string"},
+		{path: contract.InfrastructureCluster().ControlPlaneEndpoint().Port().Path(), typ: "integer"},
+		{path: contract.InfrastructureCluster().Ready().Path(), typ: "boolean"},
+	})...)
+	return allErrs
+}
+
+// ValidateControlPlaneCRD validates that the CRD of a control plane satisfies the contract required by
+// the topology controller, e.g. that it defines spec.version and status.version, which are used to
+// orchestrate upgrades.
+func ValidateControlPlaneCRD(crd *apiextensionsv1.CustomResourceDefinition, options ControlPlaneOptions) field.ErrorList {
+	fields := []schemaField{
+		{path: contract.ControlPlane().Version().Path(), typ: "string"},
+		{path: contract.ControlPlane().StatusVersion().Path(), typ: "string"},
+		{path: contract.ControlPlane().Initialized().Path(), typ: "boolean"},
+		{path: contract.ControlPlane().Ready().Path(), typ: "boolean"},
+	}
+	if options.Replicas {
+		fields = append(fields,
+			schemaField{path: contract.ControlPlane().Replicas().Path(), typ: "integer"},
+			schemaField{path: contract.ControlPlane().StatusReplicas().Path(), typ: "integer"},
+			schemaField{path: contract.ControlPlane().UpdatedReplicas().Path(), typ: "integer"},
+			schemaField{path: contract.ControlPlane().ReadyReplicas().Path(), typ: "integer"},
+			schemaField{path: contract.ControlPlane().UnavailableReplicas().Path(), typ: "integer"},
+			schemaField{path: contract.ControlPlane().Selector().Path(), typ: "string"},
+		)
+	}
+	if options.Machines {
+		fields = append(fields,
+			schemaField{path: contract.ControlPlane().MachineTemplate().Metadata().Path(), typ: "object"},
+			schemaField{path: contract.ControlPlane().MachineTemplate().InfrastructureRef().Path(), typ: "object"},
+			schemaField{path: contract.ControlPlane().MachineTemplate().NodeDrainTimeout().Path(), typ: "string"},
+			schemaField{path: contract.ControlPlane().MachineTemplate().NodeVolumeDetachTimeout().Path(), typ: "string"},
+			schemaField{path: contract.ControlPlane().MachineTemplate().NodeDeletionTimeout().Path(), typ: "string"},
+			schemaField{path: contract.ControlPlane().MachineTemplate().AdditionalTags().Path(), typ: "object"},
+		)
+	}
+
+	allErrs := validateCRD(crd)
+	allErrs = append(allErrs, validateCRDFields(crd, fields)...)
+	return allErrs
+}
+
+// ValidateTemplate validates that a template, e.g. an InfrastructureMachineTemplate used in a ClusterClass,
+// can be cloned into a valid object, by validating the object generated from the template, the same way the
+// topology controller does, against the schema of the CRD of the generated object, e.g. the InfrastructureMachine CRD.
+// NOTE: Validation webhooks of the provider are not run; use ValidateControlPlaneTemplate for control plane templates,
+// given that the topology controller sets additional fields on the generated control plane objects.
+func ValidateTemplate(template *unstructured.Unstructured, crd *apiextensionsv1.CustomResourceDefinition) field.ErrorList {
+	obj, allErrs := generateObject(template)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	return validateObject(obj, crd)
+}
+
+// ValidateControlPlaneTemplate validates that a ControlPlaneTemplate used in a ClusterClass can be cloned into
+// a valid control plane object, after setting the fields the topology controller sets according to the Cluster
+// topology, e.g. spec.version; see ValidateTemplate for more details.
+func ValidateControlPlaneTemplate(template *unstructured.Unstructured, crd *apiextensionsv1.CustomResourceDefinition, options ControlPlaneOptions) field.ErrorList {
+	obj, allErrs := generateObject(template)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	fldPath := field.NewPath("spec", "template")
+	if err := contract.ControlPlane().Version().Set(obj, conformanceVersion); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	if options.Replicas {
+		if err := contract.ControlPlane().Replicas().Set(obj, 1); err != nil {
+			return field.ErrorList{field.InternalError(fldPath, err)}
+		}
+	}
+	if options.Machines {
+		infrastructureMachineTemplate := &unstructured.Unstructured{}
+		infrastructureMachineTemplate.SetAPIVersion("infrastructure.cluster.x-k8s.io/v1beta1")
+		infrastructureMachineTemplate.SetKind("InfrastructureMachineTemplate")
+		infrastructureMachineTemplate.SetNamespace(obj.GetNamespace())
+		infrastructureMachineTemplate.SetName(obj.GetName())
+		if err := contract.ControlPlane().MachineTemplate().InfrastructureRef().Set(obj, infrastructureMachineTemplate); err != nil {
+			return field.ErrorList{field.InternalError(fldPath, err)}
+		}
+	}
+	return validateObject(obj, crd)
+}
+
+// validateCRD validates the CRD name and the Cluster API contract version label.
+func validateCRD(crd *apiextensionsv1.CustomResourceDefinition) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if expectedName := utilcontract.CalculateCRDName(crd.Spec.Group, crd.Spec.Names.Kind); crd.Name != expectedName {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), crd.Name,
+			fmt.Sprintf("name must be %q", expectedName)))
+	}
+
+	labelPath := field.NewPath("metadata", "labels").Key(clusterv1.GroupVersion.String())
+	versions, ok := crd.Labels[clusterv1.GroupVersion.String()]
+	if !ok {
+		return append(allErrs, field.Required(labelPath, "the Cluster API contract version label must be set"))
+	}
+	for _, version := range strings.Split(versions, "_") {
+		if v := crdVersion(crd, version); v == nil || !v.Served {
+			allErrs = append(allErrs, field.Invalid(labelPath, versions,
+				fmt.Sprintf("version %q must be defined and served", version)))
+		}
+	}
+	return allErrs
+}
+
+// validateCRDFields validates that the fields are defined in the schema of all the versions of the CRD
+// in the Cluster API contract version label, or of all the served versions if the label is not set.
+func validateCRDFields(crd *apiextensionsv1.CustomResourceDefinition, fields []schemaField) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, v := range crd.Spec.Versions {
+		if !isContractVersion(crd, v) {
+			continue
+		}
+
+		fldPath := field.NewPath("spec", "versions").Index(i).Child("schema", "openAPIV3Schema")
+		if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			allErrs = append(allErrs, field.Required(fldPath, fmt.Sprintf("version %q must define a schema", v.Name)))
+			continue
+		}
+
+		for _, f := range fields {
+			schema, ok := lookupSchema(v.Schema.OpenAPIV3Schema, f.path)
+			if !ok {
+				allErrs = append(allErrs, field.Required(fldPath,
+					fmt.Sprintf("version %q must define %s with type %s", v.Name, f.path, f.typ)))
+				continue
+			}
+			if schema != nil && schema.Type != f.typ {
+				allErrs = append(allErrs, field.Invalid(fldPath, schema.Type,
+					fmt.Sprintf("version %q must define %s with type %s", v.Name, f.path, f.typ)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// isContractVersion returns true if a version of a CRD is in the Cluster API contract version label,
+// or if it is served and the label is not set.
+func isContractVersion(crd *apiextensionsv1.CustomResourceDefinition, v apiextensionsv1.CustomResourceDefinitionVersion) bool {
+	versions, ok := crd.Labels[clusterv1.GroupVersion.String()]
+	if !ok {
+		return v.Served
+	}
+	for _, version := range strings.Split(versions, "_") {
+		if version == v.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupSchema returns the schema of the field at path, if defined.
+// NOTE: If the field is under an object preserving unknown fields, the field is considered defined
+// but the schema is nil, because it cannot be validated.
+func lookupSchema(schema *apiextensionsv1.JSONSchemaProps, path contract.Path) (*apiextensionsv1.JSONSchemaProps, bool) {
+	for _, p := range path {
+		prop, ok := schema.Properties[p]
+		if !ok {
+			if schema.XPreserveUnknownFields != nil && *schema.XPreserveUnknownFields {
+				return nil, true
+			}
+			return nil, false
+		}
+		schema = &prop
+	}
+	return schema, true
+}
+
+// crdVersion returns a version of the CRD, if defined.
+func crdVersion(crd *apiextensionsv1.CustomResourceDefinition, version string) *apiextensionsv1.CustomResourceDefinitionVersion {
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == version {
+			return &crd.Spec.Versions[i]
+		}
+	}
+	return nil
+}
+
+// generateObject generates an object from a template, the same way the topology controller does.
+func generateObject(template *unstructured.Unstructured) (*unstructured.Unstructured, field.ErrorList) {
+	fldPath := field.NewPath("spec", "template")
+	if !strings.HasSuffix(template.GetKind(), clusterv1.TemplateSuffix) {
+		return nil, field.ErrorList{field.Invalid(field.NewPath("kind"), template.GetKind(),
+			fmt.Sprintf("kind must have the %q suffix", clusterv1.TemplateSuffix))}
+	}
+	if _, ok, _ := unstructured.NestedMap(template.UnstructuredContent(), "spec", "template", "spec"); !ok {
+		return nil, field.ErrorList{field.Required(fldPath.Child("spec"), "spec.template.spec must be defined")}
+	}
+
+	obj, err := external.GenerateTemplate(&external.GenerateTemplateInput{
+		Template: template,
+		TemplateRef: &corev1.ObjectReference{
+			APIVersion: template.GetAPIVersion(),
+			Kind:       template.GetKind(),
+			Namespace:  template.GetNamespace(),
+			Name:       template.GetName(),
+		},
+		Namespace:   template.GetNamespace(),
+		ClusterName: conformanceClusterName,
+		Labels: map[string]string{
+			clusterv1.ClusterTopologyOwnedLabel: "",
+		},
+	})
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(fldPath, template.GetName(), err.Error())}
+	}
+	return obj, nil
+}
+
+// validateObject validates an object generated from a template against the schema of its CRD.
+func validateObject(obj *unstructured.Unstructured, crd *apiextensionsv1.CustomResourceDefinition) field.ErrorList {
+	fldPath := field.NewPath("spec", "template")
+
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != crd.Spec.Group || gvk.Kind != crd.Spec.Names.Kind {
+		return field.ErrorList{field.Invalid(fldPath, gvk.GroupKind().String(),
+			fmt.Sprintf("the template must generate objects of kind %s.%s", crd.Spec.Names.Kind, crd.Spec.Group))}
+	}
+	v := crdVersion(crd, gvk.Version)
+	if v == nil || !v.Served {
+		return field.ErrorList{field.Invalid(fldPath, gvk.Version,
+			fmt.Sprintf("version %q must be defined and served by the %s CRD", gvk.Version, crd.Name))}
+	}
+	if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+		return nil
+	}
+
+	// Convert the schema to the internal APIExtensions schema, so it is possible to use
+	// the same library used for validating custom resources in the API server.
+	schema := &apiextensions.JSONSchemaProps{}
+	if err := apiextensionsv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(v.Schema.OpenAPIV3Schema, schema, nil); err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: schema})
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	return validation.ValidateCustomResource(fldPath, obj.UnstructuredContent(), validator)
+}
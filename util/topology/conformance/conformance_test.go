@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const crdBasesDir = "../../../controlplane/kubeadm/config/crd/bases"
+
+var kcpOptions = ControlPlaneOptions{Replicas: true, Machines: true}
+
+func TestValidateTemplateCRD(t *testing.T) {
+	t.Run("KubeadmControlPlaneTemplate satisfies the contract", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanetemplates.yaml")
+		g.Expect(ValidateTemplateCRD(crd)).To(BeEmpty())
+	})
+
+	t.Run("Contract version label is required", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanetemplates.yaml")
+		crd.Labels = nil
+		g.Expect(ValidateTemplateCRD(crd)).To(HaveLen(1))
+	})
+
+	t.Run("Versions in the contract version label must be served", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanetemplates.yaml")
+		crd.Labels[clusterv1.GroupVersion.String()] = "v1beta1_v1beta2"
+		g.Expect(ValidateTemplateCRD(crd)).To(HaveLen(1))
+	})
+
+	t.Run("CRD name and kind must follow the contract", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml")
+		crd.Name = "kcp.controlplane.cluster.x-k8s.io"
+		errs := ValidateTemplateCRD(crd)
+		g.Expect(errs).ToNot(BeEmpty())
+		g.Expect(errs.ToAggregate().Error()).To(ContainSubstring("metadata.name"))
+		g.Expect(errs.ToAggregate().Error()).To(ContainSubstring("spec.names.kind"))
+		g.Expect(errs.ToAggregate().Error()).To(ContainSubstring("spec.template.spec"))
+	})
+}
+
+func TestValidateControlPlaneCRD(t *testing.T) {
+	t.Run("KubeadmControlPlane satisfies the contract", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml")
+		g.Expect(ValidateControlPlaneCRD(crd, kcpOptions)).To(BeEmpty())
+	})
+
+	t.Run("Required fields must be defined with the expected type", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml")
+		for i := range crd.Spec.Versions {
+			spec := crd.Spec.Versions[i].Schema.OpenAPIV3Schema.Properties["spec"]
+			delete(spec.Properties, "version")
+			replicas := spec.Properties["replicas"]
+			replicas.Type = "string"
+			spec.Properties["replicas"] = replicas
+			crd.Spec.Versions[i].Schema.OpenAPIV3Schema.Properties["spec"] = spec
+		}
+		errs := ValidateControlPlaneCRD(crd, kcpOptions)
+		g.Expect(errs).To(HaveLen(2))
+		g.Expect(errs[0].Detail).To(ContainSubstring("spec.version"))
+		g.Expect(errs[1].Detail).To(ContainSubstring("spec.replicas"))
+
+		// Replicas are not checked if the control plane does not support them.
+		g.Expect(ValidateControlPlaneCRD(crd, ControlPlaneOptions{Machines: true})).To(HaveLen(1))
+	})
+}
+
+func TestValidateInfrastructureClusterCRD(t *testing.T) {
+	g := NewWithT(t)
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	crd.Name = "fooclusters.infrastructure.cluster.x-k8s.io"
+	crd.Labels = map[string]string{clusterv1.GroupVersion.String(): "v1beta1"}
+	crd.Spec.Group = "infrastructure.cluster.x-k8s.io"
+	crd.Spec.Names.Kind = "FooCluster"
+	crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{{
+		Name:   "v1beta1",
+		Served: true,
+		Schema: &apiextensionsv1.CustomResourceValidation{OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			Properties: map[string]apiextensionsv1.JSONSchemaProps{
+				"spec": {
+					Type: "object",
+					Properties: map[string]apiextensionsv1.JSONSchemaProps{
+						"controlPlaneEndpoint": {
+							Type: "object",
+							Properties: map[string]apiextensionsv1.JSONSchemaProps{
+								"host": {Type: "string"},
+								"port": {Type: "integer"},
+							},
+						},
+					},
+				},
+				"status": {
+					Type:                   "object",
+					XPreserveUnknownFields: pointer.Bool(true),
+				},
+			},
+		}},
+	}}
+	g.Expect(ValidateInfrastructureClusterCRD(crd)).To(BeEmpty())
+
+	delete(crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties, "spec")
+	g.Expect(ValidateInfrastructureClusterCRD(crd)).To(HaveLen(2))
+}
+
+func TestValidateControlPlaneTemplate(t *testing.T) {
+	template := func(spec map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+			"kind":       "KubeadmControlPlaneTemplate",
+			"metadata": map[string]interface{}{
+				"namespace": "default",
+				"name":      "kcp-template",
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": spec,
+				},
+			},
+		}}
+	}
+
+	t.Run("Valid template", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml")
+		tpl := template(map[string]interface{}{
+			"kubeadmConfigSpec": map[string]interface{}{},
+		})
+		g.Expect(ValidateControlPlaneTemplate(tpl, crd, kcpOptions)).To(BeEmpty())
+
+		// Fields set by the topology controller are required by the KubeadmControlPlane CRD.
+		g.Expect(ValidateTemplate(tpl, crd)).ToNot(BeEmpty())
+	})
+
+	t.Run("Template generating an invalid object", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml")
+		tpl := template(map[string]interface{}{
+			"kubeadmConfigSpec": map[string]interface{}{
+				"format": "unknown",
+			},
+		})
+		errs := ValidateControlPlaneTemplate(tpl, crd, kcpOptions)
+		g.Expect(errs).To(HaveLen(1))
+		g.Expect(errs[0].Field).To(Equal("spec.template.spec.kubeadmConfigSpec.format"))
+	})
+
+	t.Run("Template without spec.template.spec", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanes.yaml")
+		tpl := template(nil)
+		unstructured.RemoveNestedField(tpl.Object, "spec", "template", "spec")
+		g.Expect(ValidateControlPlaneTemplate(tpl, crd, kcpOptions)).To(HaveLen(1))
+	})
+
+	t.Run("Template generating objects of another kind", func(t *testing.T) {
+		g := NewWithT(t)
+
+		crd := loadCRD(g, "controlplane.cluster.x-k8s.io_kubeadmcontrolplanetemplates.yaml")
+		tpl := template(map[string]interface{}{})
+		g.Expect(ValidateTemplate(tpl, crd)).To(HaveLen(1))
+	})
+}
+
+// loadCRD loads a CRD from the KubeadmControlPlane CRD bases, and sets the contract version label
+// which is usually added by kustomize.
+func loadCRD(g *WithT, name string) *apiextensionsv1.CustomResourceDefinition {
+	data, err := os.ReadFile(filepath.Join(crdBasesDir, name)) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	g.Expect(yaml.Unmarshal(data, crd)).To(Succeed())
+	crd.Labels = map[string]string{clusterv1.GroupVersion.String(): "v1beta1"}
+	return crd
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// templatePath is the path of the template field in templates, e.g. InfrastructureMachineTemplates.
+var templatePath = []string{"spec", "template"}
+
+// defaultTemplateMutablePaths are the paths under spec.template that can be changed in place by default,
+// because they are not cloned into the objects generated from the template.
+var defaultTemplateMutablePaths = [][]string{
+	{"spec", "template", "metadata", "labels"},
+	{"spec", "template", "metadata", "annotations"},
+}
+
+// TemplateImmutabilityOption defines an option for ValidateTemplateImmutability.
+type TemplateImmutabilityOption func(*templateImmutabilityOptions)
+
+type templateImmutabilityOptions struct {
+	mutablePaths [][]string
+}
+
+// WithMutablePaths adds paths, e.g. spec.template.spec.foo, to the list of paths under spec.template that
+// can be changed in place; by default only spec.template.metadata.labels and spec.template.metadata.annotations
+// are mutable.
+func WithMutablePaths(paths ...[]string) TemplateImmutabilityOption {
+	return func(o *templateImmutabilityOptions) {
+		o.mutablePaths = append(o.mutablePaths, paths...)
+	}
+}
+
+// ValidateTemplateImmutability validates that an update does not change spec.template in a template object,
+// e.g. an InfrastructureMachineTemplate, except for the mutable paths.
+// This prevents accidental in-place changes to templates that are cloned into many objects, potentially across many
+// clusters; users are expected to create a new template and to rotate references instead.
+// NOTE: The check is skipped for dry-run requests issued by the topology controller, see ShouldSkipImmutabilityChecks.
+func ValidateTemplateImmutability(req admission.Request, oldObj, newObj client.Object, options ...TemplateImmutabilityOption) field.ErrorList {
+	if ShouldSkipImmutabilityChecks(req, newObj) {
+		return nil
+	}
+
+	opts := &templateImmutabilityOptions{
+		mutablePaths: defaultTemplateMutablePaths,
+	}
+	for _, o := range options {
+		o(opts)
+	}
+
+	fldPath := field.NewPath(templatePath[0], templatePath[1:]...)
+	oldTemplate, err := templateWithoutMutablePaths(oldObj, opts.mutablePaths)
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	newTemplate, err := templateWithoutMutablePaths(newObj, opts.mutablePaths)
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+
+	if reflect.DeepEqual(oldTemplate, newTemplate) {
+		return nil
+	}
+
+	mutable := make([]string, 0, len(opts.mutablePaths))
+	for _, p := range opts.mutablePaths {
+		mutable = append(mutable, strings.Join(p, "."))
+	}
+	return field.ErrorList{field.Forbidden(fldPath,
+		fmt.Sprintf("%s is immutable except for %s, please create a new %s instead",
+			strings.Join(templatePath, "."), strings.Join(mutable, ", "), newObj.GetObjectKind().GroupVersionKind().Kind))}
+}
+
+// templateWithoutMutablePaths returns the spec.template field of an object, without the mutable paths.
+func templateWithoutMutablePaths(obj client.Object, mutablePaths [][]string) (map[string]interface{}, error) {
+	// NOTE: The object is copied because the content of Unstructured objects is returned without copying it,
+	// and it would be otherwise changed when removing the mutable paths.
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range mutablePaths {
+		unstructured.RemoveNestedField(content, p...)
+	}
+
+	template, _, err := unstructured.NestedMap(content, templatePath...)
+	if err != nil {
+		return nil, err
+	}
+	// Drop empty maps left over after removing mutable paths, e.g. spec.template.metadata.
+	removeEmptyMaps(template)
+	return template, nil
+}
+
+// removeEmptyMaps recursively removes empty maps from a map.
+func removeEmptyMaps(m map[string]interface{}) {
+	for k, v := range m {
+		if nested, ok := v.(map[string]interface{}); ok {
+			removeEmptyMaps(nested)
+			if len(nested) == 0 {
+				delete(m, k)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestValidateTemplateImmutability(t *testing.T) {
+	template := func(labels map[string]interface{}, spec map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "infrastructure.cluster.x-k8s.io/v1beta1",
+			"kind":       "GenericInfrastructureMachineTemplate",
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": spec,
+				},
+			},
+		}}
+		if labels != nil {
+			_ = unstructured.SetNestedMap(u.Object, labels, "spec", "template", "metadata", "labels")
+		}
+		return u
+	}
+	dryRunTemplate := template(nil, map[string]interface{}{"foo": "baz"})
+	dryRunTemplate.SetAnnotations(map[string]string{clusterv1.TopologyDryRunAnnotation: ""})
+
+	tests := []struct {
+		name    string
+		req     admission.Request
+		oldObj  *unstructured.Unstructured
+		newObj  *unstructured.Unstructured
+		options []TemplateImmutabilityOption
+		wantErr bool
+	}{
+		{
+			name:    "allows no changes",
+			oldObj:  template(nil, map[string]interface{}{"foo": "bar"}),
+			newObj:  template(nil, map[string]interface{}{"foo": "bar"}),
+			wantErr: false,
+		},
+		{
+			name:    "allows changes to mutable metadata",
+			oldObj:  template(nil, map[string]interface{}{"foo": "bar"}),
+			newObj:  template(map[string]interface{}{"a": "b"}, map[string]interface{}{"foo": "bar"}),
+			wantErr: false,
+		},
+		{
+			name:    "does not allow changes to the template spec",
+			oldObj:  template(nil, map[string]interface{}{"foo": "bar"}),
+			newObj:  template(nil, map[string]interface{}{"foo": "baz"}),
+			wantErr: true,
+		},
+		{
+			name:    "allows changes to additional mutable paths",
+			oldObj:  template(nil, map[string]interface{}{"foo": "bar"}),
+			newObj:  template(nil, map[string]interface{}{"foo": "baz"}),
+			options: []TemplateImmutabilityOption{WithMutablePaths([]string{"spec", "template", "spec", "foo"})},
+			wantErr: false,
+		},
+		{
+			name:    "skips the check for topology dry-run requests",
+			req:     admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{DryRun: pointer.Bool(true)}},
+			oldObj:  template(nil, map[string]interface{}{"foo": "bar"}),
+			newObj:  dryRunTemplate,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			oldObj := tt.oldObj.DeepCopy()
+			newObj := tt.newObj.DeepCopy()
+
+			errs := ValidateTemplateImmutability(tt.req, tt.oldObj, tt.newObj, tt.options...)
+
+			// The objects being validated must not be changed.
+			g.Expect(tt.oldObj).To(Equal(oldObj))
+			g.Expect(tt.newObj).To(Equal(newObj))
+
+			if tt.wantErr {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
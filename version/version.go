@@ -20,6 +20,7 @@ package version
 import (
 	"fmt"
 	"runtime"
+	"strings"
 
 	"github.com/pkg/errors"
 	utilversion "k8s.io/apimachinery/pkg/util/version"
@@ -55,6 +56,67 @@ func CheckKubernetesVersion(config *rest.Config, minK8sVersion string) error {
 	return nil
 }
 
+// KubernetesVersionRange defines a range of supported Kubernetes versions.
+type KubernetesVersionRange struct {
+	// Minimum is the minimum supported version, e.g. v1.20.0.
+	Minimum string
+
+	// Maximum is the maximum supported minor version, e.g. v1.26; all the patch versions
+	// of the maximum minor version are supported.
+	Maximum string
+}
+
+// SupportedWorkloadKubernetesVersions defines the range of Kubernetes versions supported for workload clusters
+// by this release of Cluster API, including the kubeadm bootstrap and control plane providers.
+// NOTE: Other providers can restrict this range by annotating their CRDs with the
+// cluster.x-k8s.io/minimum-kubernetes-version and cluster.x-k8s.io/maximum-kubernetes-version annotations.
+var SupportedWorkloadKubernetesVersions = KubernetesVersionRange{
+	Minimum: "v1.20.0",
+	Maximum: "v1.26",
+}
+
+// Compare returns -1 if the given version is older than the minimum version of the range, +1 if the given version
+// is newer than the maximum minor version of the range, 0 otherwise. Empty bounds are not checked.
+func (r KubernetesVersionRange) Compare(v string) (int, error) {
+	ver, err := utilversion.ParseGeneric(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse version %q", v)
+	}
+
+	if r.Minimum != "" {
+		minVer, err := utilversion.ParseGeneric(r.Minimum)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse minimum version %q", r.Minimum)
+		}
+		if ver.LessThan(minVer) {
+			return -1, nil
+		}
+	}
+
+	if r.Maximum != "" {
+		maxVer, err := utilversion.ParseGeneric(r.Maximum)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to parse maximum version %q", r.Maximum)
+		}
+		if ver.Major() > maxVer.Major() || (ver.Major() == maxVer.Major() && ver.Minor() > maxVer.Minor()) {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// String returns a human-friendly representation of the range, e.g. ">= v1.20.0, <= v1.26.x".
+func (r KubernetesVersionRange) String() string {
+	bounds := []string{}
+	if r.Minimum != "" {
+		bounds = append(bounds, ">= "+r.Minimum)
+	}
+	if r.Maximum != "" {
+		bounds = append(bounds, "<= "+r.Maximum+".x")
+	}
+	return strings.Join(bounds, ", ")
+}
+
 var (
 	gitMajor     string // major version, always numeric
 	gitMinor     string // minor version, numeric possibly followed by "+"
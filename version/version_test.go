@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestKubernetesVersionRangeCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		r       KubernetesVersionRange
+		version string
+		want    int
+		wantErr bool
+	}{
+		{
+			name:    "supported version",
+			r:       KubernetesVersionRange{Minimum: "v1.20.0", Maximum: "v1.26"},
+			version: "v1.24.3",
+			want:    0,
+		},
+		{
+			name:    "minimum version is supported",
+			r:       KubernetesVersionRange{Minimum: "v1.20.0", Maximum: "v1.26"},
+			version: "v1.20.0",
+			want:    0,
+		},
+		{
+			name:    "all the patch versions of the maximum minor version are supported",
+			r:       KubernetesVersionRange{Minimum: "v1.20.0", Maximum: "v1.26"},
+			version: "v1.26.15+build.1",
+			want:    0,
+		},
+		{
+			name:    "too old version",
+			r:       KubernetesVersionRange{Minimum: "v1.20.0", Maximum: "v1.26"},
+			version: "v1.19.16",
+			want:    -1,
+		},
+		{
+			name:    "too new version",
+			r:       KubernetesVersionRange{Minimum: "v1.20.0", Maximum: "v1.26"},
+			version: "v1.27.0-alpha.1",
+			want:    1,
+		},
+		{
+			name:    "empty bounds are not checked",
+			r:       KubernetesVersionRange{},
+			version: "v1.2.3",
+			want:    0,
+		},
+		{
+			name:    "invalid version",
+			r:       KubernetesVersionRange{Minimum: "v1.20.0"},
+			version: "foo",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := tt.r.Compare(tt.version)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}